@@ -22,14 +22,21 @@ type Client interface {
 	GetDeletedChannelsForTeam(teamID string, page int, perPage int, etag string) ([]*model.Channel, *model.Response, error)
 	GetPrivateChannelsForTeam(teamID string, page int, perPage int, etag string) ([]*model.Channel, *model.Response, error)
 	GetChannelsForTeamForUser(teamID, userID string, includeDeleted bool, etag string) ([]*model.Channel, *model.Response, error)
+	GetChannelMembersForUser(userID, teamID, etag string) (model.ChannelMembers, *model.Response, error)
+	GetTeamsForUser(userID, etag string) ([]*model.Team, *model.Response, error)
+	GetTeamMembersForUser(userID string, etag string) ([]*model.TeamMember, *model.Response, error)
+	GetGroupsByUserId(userID string) ([]*model.Group, *model.Response, error)
 	RestoreChannel(channelID string) (*model.Channel, *model.Response, error)
 	PatchChannel(channelID string, patch *model.ChannelPatch) (*model.Channel, *model.Response, error)
 	GetChannelByName(channelName, teamID string, etag string) (*model.Channel, *model.Response, error)
 	GetChannelByNameIncludeDeleted(channelName, teamID string, etag string) (*model.Channel, *model.Response, error)
 	GetChannel(channelID, etag string) (*model.Channel, *model.Response, error)
+	GetChannelStats(channelID, etag string) (*model.ChannelStats, *model.Response, error)
 	GetTeam(teamID, etag string) (*model.Team, *model.Response, error)
 	GetTeamByName(name, etag string) (*model.Team, *model.Response, error)
 	GetAllTeams(etag string, page int, perPage int) ([]*model.Team, *model.Response, error)
+	GetTeamStats(teamID, etag string) (*model.TeamStats, *model.Response, error)
+	GetTotalUsersStats(etag string) (*model.UsersStats, *model.Response, error)
 	CreateTeam(team *model.Team) (*model.Team, *model.Response, error)
 	PatchTeam(teamID string, patch *model.TeamPatch) (*model.Team, *model.Response, error)
 	AddTeamMember(teamID, userID string) (*model.TeamMember, *model.Response, error)
@@ -39,18 +46,46 @@ type Client interface {
 	RestoreTeam(teamID string) (*model.Team, *model.Response, error)
 	UpdateTeamPrivacy(teamID string, privacy string) (*model.Team, *model.Response, error)
 	SearchTeams(search *model.TeamSearch) ([]*model.Team, *model.Response, error)
+	SetTeamIcon(teamID string, data []byte) (*model.Response, error)
+	RemoveTeamIcon(teamID string) (*model.Response, error)
 	GetPost(postID string, etag string) (*model.Post, *model.Response, error)
 	CreatePost(post *model.Post) (*model.Post, *model.Response, error)
+	PinPost(postID string) (*model.Response, error)
+	DeletePost(postID string) (*model.Response, error)
+	SaveReaction(reaction *model.Reaction) (*model.Reaction, *model.Response, error)
+	DeleteReaction(reaction *model.Reaction) (*model.Response, error)
+	UploadFile(data []byte, channelID string, filename string) (*model.FileUploadResponse, *model.Response, error)
+	GetFile(fileID string) ([]byte, *model.Response, error)
+	GetFileInfosForPost(postID string, etag string) ([]*model.FileInfo, *model.Response, error)
+	CreateDirectChannel(userID1, userID2 string) (*model.Channel, *model.Response, error)
+	CreateGroupChannel(userIDs []string) (*model.Channel, *model.Response, error)
+	GetChannelsForUserWithLastDeleteAt(userID string, lastDeleteAt int) ([]*model.Channel, *model.Response, error)
+	GetMe(etag string) (*model.User, *model.Response, error)
+	GetOldClientConfig(etag string) (map[string]string, *model.Response, error)
+	GetOldClientLicense(etag string) (map[string]string, *model.Response, error)
 	GetPostsForChannel(channelID string, page, perPage int, etag string, collapsedThreads bool) (*model.PostList, *model.Response, error)
 	GetPostsSince(channelID string, since int64, collapsedThreads bool) (*model.PostList, *model.Response, error)
+	DoAPIGet(url string, etag string) (*http.Response, error)
 	DoAPIPost(url string, data string) (*http.Response, error)
+	DoAPIRequestWithHeaders(method, url, data string, headers map[string]string) (*http.Response, error)
+	TestS3Connection(config *model.Config) (*model.Response, error)
 	GetLdapGroups() ([]*model.Group, *model.Response, error)
+	LinkLdapGroup(dn string) (*model.Group, *model.Response, error)
+	UnlinkLdapGroup(dn string) (*model.Group, *model.Response, error)
+	GetGroup(groupID, etag string) (*model.Group, *model.Response, error)
+	GetGroups(opts model.GroupSearchOpts) ([]*model.Group, *model.Response, error)
+	GetUsersInGroup(groupID string, page int, perPage int, etag string) ([]*model.User, *model.Response, error)
+	LinkGroupSyncable(groupID, syncableID string, syncableType model.GroupSyncableType, patch *model.GroupSyncablePatch) (*model.GroupSyncable, *model.Response, error)
+	UnlinkGroupSyncable(groupID, syncableID string, syncableType model.GroupSyncableType) (*model.Response, error)
 	GetGroupsByChannel(channelID string, groupOpts model.GroupSearchOpts) ([]*model.GroupWithSchemeAdmin, int, *model.Response, error)
 	GetGroupsByTeam(teamID string, groupOpts model.GroupSearchOpts) ([]*model.GroupWithSchemeAdmin, int, *model.Response, error)
 	UploadLicenseFile(data []byte) (*model.Response, error)
 	RemoveLicenseFile() (*model.Response, error)
 	GetLogs(page, perPage int) ([]string, *model.Response, error)
+	GetAllRoles() ([]*model.Role, *model.Response, error)
 	GetRoleByName(name string) (*model.Role, *model.Response, error)
+	GetSchemes(scope string, page int, perPage int) ([]*model.Scheme, *model.Response, error)
+	CreateScheme(scheme *model.Scheme) (*model.Scheme, *model.Response, error)
 	PatchRole(roleID string, patch *model.RolePatch) (*model.Role, *model.Response, error)
 	UploadPlugin(file io.Reader) (*model.Manifest, *model.Response, error)
 	UploadPluginForced(file io.Reader) (*model.Manifest, *model.Response, error)
@@ -58,17 +93,26 @@ type Client interface {
 	EnablePlugin(id string) (*model.Response, error)
 	DisablePlugin(id string) (*model.Response, error)
 	GetPlugins() (*model.PluginsResponse, *model.Response, error)
+	GetPluginStatuses() (model.PluginStatuses, *model.Response, error)
+	GetAnalyticsOld(name, teamID string) (model.AnalyticsRows, *model.Response, error)
 	GetUser(userID, etag string) (*model.User, *model.Response, error)
 	GetUserByUsername(userName, etag string) (*model.User, *model.Response, error)
 	GetUserByEmail(email, etag string) (*model.User, *model.Response, error)
 	PermanentDeleteUser(userID string) (*model.Response, error)
 	PermanentDeleteAllUsers() (*model.Response, error)
 	CreateUser(user *model.User) (*model.User, *model.Response, error)
+	GetSidebarCategoriesForTeamForUser(userID, teamID, etag string) (*model.OrderedSidebarCategories, *model.Response, error)
+	CreateSidebarCategoryForTeamForUser(userID, teamID string, category *model.SidebarCategoryWithChannels) (*model.SidebarCategoryWithChannels, *model.Response, error)
+	UpdateSidebarCategoryForTeamForUser(userID, teamID, categoryID string, category *model.SidebarCategoryWithChannels) (*model.SidebarCategoryWithChannels, *model.Response, error)
 	VerifyUserEmailWithoutToken(userID string) (*model.User, *model.Response, error)
 	UpdateUserRoles(userID, roles string) (*model.Response, error)
 	InviteUsersToTeam(teamID string, userEmails []string) (*model.Response, error)
 	SendPasswordResetEmail(email string) (*model.Response, error)
+	SendVerificationEmail(email string) (*model.Response, error)
 	UpdateUser(user *model.User) (*model.User, *model.Response, error)
+	PatchUser(userID string, patch *model.UserPatch) (*model.User, *model.Response, error)
+	GetPreferencesByCategory(userID, category string) (model.Preferences, *model.Response, error)
+	UpdatePreferences(userID string, preferences model.Preferences) (*model.Response, error)
 	UpdateUserMfa(userID, code string, activate bool) (*model.Response, error)
 	UpdateUserPassword(userID, currentPassword, newPassword string) (*model.Response, error)
 	UpdateUserHashedPassword(userID, newHashedPassword string) (*model.Response, error)
@@ -85,19 +129,26 @@ type Client interface {
 	UpdateCommand(cmd *model.Command) (*model.Command, *model.Response, error)
 	MoveCommand(teamID string, commandID string) (*model.Response, error)
 	DeleteCommand(commandID string) (*model.Response, error)
+	RegenCommandToken(commandID string) (string, *model.Response, error)
+	ExecuteCommand(channelID, command string) (*model.CommandResponse, *model.Response, error)
 	GetConfig() (*model.Config, *model.Response, error)
 	UpdateConfig(*model.Config) (*model.Config, *model.Response, error)
 	PatchConfig(*model.Config) (*model.Config, *model.Response, error)
+	GetEnvironmentConfig() (map[string]interface{}, *model.Response, error)
 	ReloadConfig() (*model.Response, error)
 	MigrateConfig(from, to string) (*model.Response, error)
 	SyncLdap(includeRemovedMembers bool) (*model.Response, error)
+	TestLdap() (*model.Response, error)
 	MigrateIdLdap(toAttribute string) (*model.Response, error)
 	GetUsers(page, perPage int, etag string) ([]*model.User, *model.Response, error)
 	GetUsersByIds(userIDs []string) ([]*model.User, *model.Response, error)
+	SetDefaultProfileImage(userID string) (*model.Response, error)
 	GetUsersInTeam(teamID string, page, perPage int, etag string) ([]*model.User, *model.Response, error)
+	GetUsersStatusesByIds(userIDs []string) ([]*model.Status, *model.Response, error)
 	UpdateUserActive(userID string, activate bool) (*model.Response, error)
 	UpdateTeam(team *model.Team) (*model.Team, *model.Response, error)
 	UpdateChannelPrivacy(channelID string, privacy model.ChannelType) (*model.Channel, *model.Response, error)
+	UpdateChannelMemberSchemeRoles(channelID, userID string, schemeRoles *model.SchemeRoles) (*model.Response, error)
 	CreateBot(bot *model.Bot) (*model.Bot, *model.Response, error)
 	PatchBot(userID string, patch *model.BotPatch) (*model.Bot, *model.Response, error)
 	GetBots(page, perPage int, etag string) ([]*model.Bot, *model.Response, error)
@@ -109,6 +160,8 @@ type Client interface {
 	SetServerBusy(secs int) (*model.Response, error)
 	ClearServerBusy() (*model.Response, error)
 	GetServerBusy() (*model.ServerBusyState, *model.Response, error)
+	GetNotices(lastViewed int64, teamID string, client model.NoticeClientType, clientVersion, locale, etag string) (model.NoticeMessages, *model.Response, error)
+	MarkNoticesViewed(ids []string) (*model.Response, error)
 	CheckIntegrity() ([]model.IntegrityCheckResult, *model.Response, error)
 	InstallPluginFromURL(string, bool) (*model.Manifest, *model.Response, error)
 	InstallMarketplacePlugin(*model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error)
@@ -127,6 +180,7 @@ type Client interface {
 	GetJobsByType(jobType string, page int, perPage int) ([]*model.Job, *model.Response, error)
 	CreateJob(job *model.Job) (*model.Job, *model.Response, error)
 	CancelJob(jobID string) (*model.Response, error)
+	DownloadJob(jobID string) ([]byte, *model.Response, error)
 	CreateIncomingWebhook(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.Response, error)
 	UpdateIncomingWebhook(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.Response, error)
 	GetIncomingWebhooks(page int, perPage int, etag string) ([]*model.IncomingWebhook, *model.Response, error)
@@ -145,4 +199,33 @@ type Client interface {
 	DeleteExport(name string) (*model.Response, error)
 	DownloadExport(name string, wr io.Writer, offset int64) (int64, *model.Response, error)
 	ResetSamlAuthDataToEmail(includeDeleted bool, dryRun bool, userIDs []string) (int64, *model.Response, error)
+	GetSamlCertificateStatus() (*model.SamlCertificateStatus, *model.Response, error)
+	UploadSamlIdpCertificate(data []byte, filename string) (*model.Response, error)
+	UploadSamlPublicCertificate(data []byte, filename string) (*model.Response, error)
+	UploadSamlPrivateCertificate(data []byte, filename string) (*model.Response, error)
+	DeleteSamlIdpCertificate() (*model.Response, error)
+	DeleteSamlPublicCertificate() (*model.Response, error)
+	DeleteSamlPrivateCertificate() (*model.Response, error)
+	GetSamlMetadataFromIdp(samlMetadataURL string) (*model.SamlMetadataResponse, *model.Response, error)
+	GetSessions(userID, etag string) ([]*model.Session, *model.Response, error)
+	RevokeSession(userID, sessionID string) (*model.Response, error)
+	RevokeAllSessions(userID string) (*model.Response, error)
+	GetChannelModerations(channelID string, etag string) ([]*model.ChannelModeration, *model.Response, error)
+	PatchChannelModerations(channelID string, patch []*model.ChannelModerationPatch) ([]*model.ChannelModeration, *model.Response, error)
+	GetAudits(page int, perPage int, etag string) (model.Audits, *model.Response, error)
+	SearchPosts(teamID string, terms string, isOrSearch bool) (*model.PostList, *model.Response, error)
+	SearchPostsWithParams(teamID string, params *model.SearchParameter) (*model.PostList, *model.Response, error)
+	Login(loginID string, password string) (*model.User, *model.Response, error)
+	Logout() (*model.Response, error)
+	GetDataRetentionPolicy() (*model.GlobalRetentionPolicy, *model.Response, error)
+	GetDataRetentionPolicies(page, perPage int) (*model.RetentionPolicyWithTeamAndChannelCountsList, *model.Response, error)
+	CreateDataRetentionPolicy(policy *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.Response, error)
+	PatchDataRetentionPolicy(patch *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.Response, error)
+	DeleteDataRetentionPolicy(policyID string) (*model.Response, error)
+	GetTeamsForRetentionPolicy(policyID string, page, perPage int) (*model.TeamsWithCount, *model.Response, error)
+	AddTeamsToRetentionPolicy(policyID string, teamIDs []string) (*model.Response, error)
+	RemoveTeamsFromRetentionPolicy(policyID string, teamIDs []string) (*model.Response, error)
+	GetChannelsForRetentionPolicy(policyID string, page, perPage int) (*model.ChannelsWithCount, *model.Response, error)
+	AddChannelsToRetentionPolicy(policyID string, channelIDs []string) (*model.Response, error)
+	RemoveChannelsFromRetentionPolicy(policyID string, channelIDs []string) (*model.Response, error)
 }
@@ -39,11 +39,14 @@ var LoginCmd = &cobra.Command{
 }
 
 var CurrentCmd = &cobra.Command{
-	Use:     "current",
-	Short:   "Show current user credentials",
-	Long:    "Show the currently stored user credentials",
-	Example: `  auth current`,
-	RunE:    currentCmdF,
+	Use:   "current",
+	Short: "Show current user credentials",
+	Long:  "Show the currently stored user credentials. With --json, the server is contacted to print the user the credentials authenticate as, their roles, the expiry of the session of their login for the password and MFA credentials, and the URL, version and license SKU of the server as a single document.",
+	Example: `  auth current
+
+  # check the environment before running a script
+  auth current --json`,
+	RunE: currentCmdF,
 }
 
 var SetCmd = &cobra.Command{
@@ -72,6 +75,15 @@ var RenewCmd = &cobra.Command{
 	RunE:    renewCmdF,
 }
 
+var RenameCmd = &cobra.Command{
+	Use:     "rename [server name] [new server name]",
+	Short:   "Rename a set of credentials",
+	Long:    "Rename the credentials of a server, keeping them active if they are",
+	Example: `  auth rename local-server staging`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    renameCmdF,
+}
+
 var DeleteCmd = &cobra.Command{
 	Use:     "delete [server name]",
 	Short:   "Delete an credentials",
@@ -115,6 +127,7 @@ func init() {
 		SetCmd,
 		ListCmd,
 		RenewCmd,
+		RenameCmd,
 		DeleteCmd,
 		CleanCmd,
 	)
@@ -165,6 +178,7 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 
 	url := strings.TrimRight(args[0], "/")
 	method := MethodPassword
+	var sessionID string
 
 	if name == "" {
 		reader := bufio.NewReader(os.Stdin)
@@ -212,6 +226,7 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("could not initiate client: %w", err)
 		}
 		accessToken = c.AuthToken
+		sessionID = getLoginSessionID(c)
 	} else {
 		username = "Personal Access Token"
 		method = MethodToken
@@ -230,7 +245,9 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		Username:    username,
 		AuthToken:   accessToken,
 		AuthMethod:  method,
+		SessionID:   sessionID,
 	}
+	credentials.Keyring, _ = cmd.Flags().GetBool("keyring")
 
 	if err := SaveCredentials(credentials); err != nil {
 		return err
@@ -260,6 +277,12 @@ func getPasswordFromStdin() (string, error) {
 }
 
 func currentCmdF(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	json, _ := cmd.Flags().GetBool("json")
+	if format == printer.FormatJSON || json {
+		return withClient(authCurrentIdentityCmdF)(cmd, args)
+	}
+
 	credentials, err := GetCurrentCredentials()
 	if err != nil {
 		return err
@@ -369,6 +392,7 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 		}
 
 		credentials.AuthToken = c.AuthToken
+		credentials.SessionID = getLoginSessionID(c)
 
 	case MethodToken:
 		if accessToken == "" {
@@ -390,6 +414,7 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		credentials.AuthToken = c.AuthToken
+		credentials.SessionID = getLoginSessionID(c)
 
 	default:
 		return errors.Errorf("invalid auth method %q", credentials.AuthMethod)
@@ -404,6 +429,48 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func renameCmdF(cmd *cobra.Command, args []string) error {
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return err
+	}
+
+	name, newName := args[0], args[1]
+	credentials := (*credentialsList)[name]
+	if credentials == nil {
+		return errors.Errorf("cannot find credentials for server name %q", name)
+	}
+	if (*credentialsList)[newName] != nil {
+		return errors.Errorf("there are already credentials for server name %q", newName)
+	}
+
+	// the token is stored in the keyring under the new name before
+	// removing the one under the old name
+	if err := loadKeyringToken(credentials); err != nil {
+		return err
+	}
+	previous := *credentials
+
+	delete(*credentialsList, name)
+	credentials.Name = newName
+	(*credentialsList)[newName] = credentials
+	if err := SaveCredentialsList(credentialsList); err != nil {
+		return err
+	}
+	if err := deleteKeyringToken(&previous); err != nil {
+		return err
+	}
+
+	// the version history is informative only, so failing to move it
+	// must not fail the rename
+	if err := renameVersionHistory(name, newName); err != nil && !viper.GetBool("suppress-warnings") {
+		printer.PrintWarning("unable to rename the version history: " + err.Error())
+	}
+
+	printer.Print(fmt.Sprintf("Credentials for server %q renamed to %q", name, newName))
+	return nil
+}
+
 func deleteCmdF(cmd *cobra.Command, args []string) error {
 	credentialsList, err := ReadCredentialsList()
 	if err != nil {
@@ -416,8 +483,18 @@ func deleteCmdF(cmd *cobra.Command, args []string) error {
 		return errors.Errorf("cannot find credentials for server name %q", name)
 	}
 
+	if err := deleteKeyringToken(credentials); err != nil {
+		return err
+	}
 	delete(*credentialsList, name)
-	return SaveCredentialsList(credentialsList)
+	if err := SaveCredentialsList(credentialsList); err != nil {
+		return err
+	}
+
+	if err := deleteVersionHistory(name); err != nil && !viper.GetBool("suppress-warnings") {
+		printer.PrintWarning("unable to delete the version history: " + err.Error())
+	}
+	return nil
 }
 
 func cleanCmdF(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// authIdentity describes who the current credentials authenticate as
+// and against which server
+type authIdentity struct {
+	Credentials      string   `json:"credentials"`
+	ServerURL        string   `json:"server_url"`
+	ServerVersion    string   `json:"server_version"`
+	AuthMethod       string   `json:"auth_method"`
+	UserID           string   `json:"user_id"`
+	Username         string   `json:"username"`
+	Email            string   `json:"email"`
+	Roles            []string `json:"roles"`
+	SessionExpiresAt int64    `json:"session_expires_at,omitempty"`
+	Licensed         bool     `json:"licensed"`
+	LicenseSKU       string   `json:"license_sku"`
+}
+
+// getLoginSessionID returns the id of the session created by the login
+// of the client, which is the most recently created session of the user
+// that doesn't belong to a user access token. The server doesn't expose
+// the tokens of the sessions, so the session is recorded right after the
+// login. It returns empty if the session can't be found
+func getLoginSessionID(c client.Client) string {
+	user, _, err := c.GetMe("")
+	if err != nil {
+		return ""
+	}
+	sessions, _, err := c.GetSessions(user.Id, "")
+	if err != nil {
+		return ""
+	}
+
+	var login *model.Session
+	for _, session := range sessions {
+		if session.IsExpired() || session.Props[model.SessionPropUserAccessTokenId] != "" {
+			continue
+		}
+		if login == nil || session.CreateAt > login.CreateAt {
+			login = session
+		}
+	}
+	if login == nil {
+		return ""
+	}
+	return login.Id
+}
+
+// getCurrentSession returns the session of the login of the password
+// and MFA credentials, or nil for the token credentials, as the sessions
+// of user access tokens don't expire, and for the credentials stored
+// before the session of their login was recorded
+func getCurrentSession(c client.Client, userID string, credentials *Credentials) (*model.Session, error) {
+	if credentials.AuthMethod != MethodPassword && credentials.AuthMethod != MethodMFA {
+		return nil, nil
+	}
+	if credentials.SessionID == "" {
+		return nil, nil
+	}
+
+	sessions, _, err := c.GetSessions(userID, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the sessions of the user")
+	}
+	for _, session := range sessions {
+		if session.Id == credentials.SessionID {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+func authCurrentIdentityCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if viper.GetBool("local") {
+		return errors.New("the identity can't be shown in local mode, as it doesn't use credentials")
+	}
+
+	credentials, err := GetCurrentCredentials()
+	if err != nil {
+		return err
+	}
+
+	user, resp, err := c.GetMe("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the current user")
+	}
+
+	session, err := getCurrentSession(c, user.Id, credentials)
+	if err != nil {
+		return err
+	}
+
+	license, _, err := c.GetOldClientLicense("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the license")
+	}
+
+	identity := &authIdentity{
+		Credentials:   credentials.Name,
+		ServerURL:     credentials.InstanceURL,
+		ServerVersion: resp.ServerVersion,
+		AuthMethod:    credentials.AuthMethod,
+		UserID:        user.Id,
+		Username:      user.Username,
+		Email:         user.Email,
+		Roles:         strings.Fields(user.Roles),
+		Licensed:      license["IsLicensed"] == "true",
+		LicenseSKU:    license["SkuShortName"],
+	}
+	if session != nil {
+		identity.SessionExpiresAt = session.ExpiresAt
+	}
+
+	printer.PrintT("{{.Username}}@{{.ServerURL}} ({{.ServerVersion}})", identity)
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestAuthCurrentIdentityCmd() {
+	user := &model.User{Id: "user-id", Username: "sysadmin", Email: "sysadmin@example.com", Roles: "system_user system_admin"}
+
+	withCredentials := func(credentials *Credentials, f func()) {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		originalConfig := viper.GetString("config")
+		defer viper.Set("config", originalConfig)
+		viper.Set("config", filepath.Join(tmp, "config"))
+
+		s.Require().NoError(SaveCredentialsList(&CredentialsList{credentials.Name: credentials}))
+		f()
+	}
+
+	s.Run("Should print the identity of the current credentials", func() {
+		printer.Clean()
+		credentials := &Credentials{Name: "production", Username: user.Username, AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true}
+
+		withCredentials(credentials, func() {
+			s.client.
+				EXPECT().
+				GetMe("").
+				Return(user, &model.Response{ServerVersion: "6.3.0"}, nil).
+				Times(1)
+
+			s.client.
+				EXPECT().
+				GetOldClientLicense("").
+				Return(map[string]string{"IsLicensed": "true", "SkuShortName": "enterprise"}, &model.Response{}, nil).
+				Times(1)
+
+			err := authCurrentIdentityCmdF(s.client, &cobra.Command{}, []string{})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 1)
+			// the session of the login wasn't recorded, so its expiry
+			// isn't shown
+			s.Require().Equal(&authIdentity{
+				Credentials:   "production",
+				ServerURL:     "https://mattermost.example.com",
+				ServerVersion: "6.3.0",
+				AuthMethod:    MethodPassword,
+				UserID:        user.Id,
+				Username:      user.Username,
+				Email:         user.Email,
+				Roles:         []string{"system_user", "system_admin"},
+				Licensed:      true,
+				LicenseSKU:    "enterprise",
+			}, printer.GetLines()[0])
+		})
+	})
+
+	s.Run("Should show the expiry of the session of the login", func() {
+		printer.Clean()
+		credentials := &Credentials{Name: "production", Username: user.Username, AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true, SessionID: "login"}
+
+		withCredentials(credentials, func() {
+			s.client.
+				EXPECT().
+				GetMe("").
+				Return(user, &model.Response{ServerVersion: "6.3.0"}, nil).
+				Times(1)
+
+			s.client.
+				EXPECT().
+				GetSessions(user.Id, "").
+				Return([]*model.Session{
+					{Id: "browser", LastActivityAt: 200, ExpiresAt: model.GetMillis() + 1000},
+					{Id: "login", LastActivityAt: 100, ExpiresAt: 42},
+				}, &model.Response{}, nil).
+				Times(1)
+
+			s.client.
+				EXPECT().
+				GetOldClientLicense("").
+				Return(map[string]string{"IsLicensed": "false"}, &model.Response{}, nil).
+				Times(1)
+
+			err := authCurrentIdentityCmdF(s.client, &cobra.Command{}, []string{})
+			s.Require().NoError(err)
+			identity := printer.GetLines()[0].(*authIdentity)
+			s.Require().Equal(int64(42), identity.SessionExpiresAt)
+			s.Require().False(identity.Licensed)
+			s.Require().Empty(identity.LicenseSKU)
+		})
+	})
+
+	s.Run("Should not show an expiry for the token credentials", func() {
+		printer.Clean()
+		credentials := &Credentials{Name: "ci", Username: user.Username, AuthMethod: MethodToken, InstanceURL: "https://mattermost.example.com", Active: true, TokenID: "token-id"}
+
+		withCredentials(credentials, func() {
+			s.client.
+				EXPECT().
+				GetMe("").
+				Return(user, &model.Response{ServerVersion: "6.3.0"}, nil).
+				Times(1)
+
+			s.client.
+				EXPECT().
+				GetOldClientLicense("").
+				Return(map[string]string{"IsLicensed": "false"}, &model.Response{}, nil).
+				Times(1)
+
+			err := authCurrentIdentityCmdF(s.client, &cobra.Command{}, []string{})
+			s.Require().NoError(err)
+			identity := printer.GetLines()[0].(*authIdentity)
+			s.Require().Zero(identity.SessionExpiresAt)
+		})
+	})
+
+	s.Run("Should record the session of the login", func() {
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSessions(user.Id, "").
+			Return([]*model.Session{
+				{Id: "old", CreateAt: 100, ExpiresAt: model.GetMillis() + 1000},
+				{Id: "token", CreateAt: 300, ExpiresAt: model.GetMillis() + 1000, Props: model.StringMap{model.SessionPropUserAccessTokenId: "token-id"}},
+				{Id: "login", CreateAt: 200, ExpiresAt: model.GetMillis() + 1000},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.Require().Equal("login", getLoginSessionID(s.client))
+	})
+}
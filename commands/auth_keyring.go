@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var AuthMigrateKeyringCmd = &cobra.Command{
+	Use:   "migrate-keyring [server names]",
+	Short: "Move the auth tokens to the keyring",
+	Long: `Move the auth tokens of the stored credentials from the credentials file to the keyring of the operating system: the macOS Keychain, the Windows Credential Manager, or the Secret Service through the secret-tool command of libsecret on Linux.
+All the credentials are moved unless server names are given. The credentials created with "auth login --keyring" are stored in the keyring from the start.`,
+	Example: `  auth migrate-keyring
+  auth migrate-keyring local-server`,
+	RunE: authMigrateKeyringCmdF,
+}
+
+func init() {
+	LoginCmd.Flags().Bool("keyring", false, "Store the auth token in the keyring of the operating system instead of the credentials file")
+
+	AuthCmd.AddCommand(AuthMigrateKeyringCmd)
+}
+
+type keyringMigration struct {
+	Name string `json:"name"`
+}
+
+func authMigrateKeyringCmdF(cmd *cobra.Command, args []string) error {
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return err
+	}
+
+	names := args
+	if len(names) == 0 {
+		for name := range *credentialsList {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	migrated := []*keyringMigration{}
+	for _, name := range names {
+		credentials := (*credentialsList)[name]
+		if credentials == nil {
+			return errors.Errorf("cannot find credentials for server name %q", name)
+		}
+		if credentials.Keyring {
+			continue
+		}
+		credentials.Keyring = true
+		migrated = append(migrated, &keyringMigration{Name: name})
+	}
+
+	if len(migrated) == 0 {
+		printer.Print("The credentials are already stored in the keyring")
+		return nil
+	}
+
+	if err := SaveCredentialsList(credentialsList); err != nil {
+		return err
+	}
+
+	for _, m := range migrated {
+		printer.PrintT("Credentials for server \"{{.Name}}\" moved to the keyring", m)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// memoryKeyring is a keyring keeping the secrets in memory
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Get(name string) (string, error) {
+	secret, ok := k[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func (k memoryKeyring) Set(name, secret string) error {
+	k[name] = secret
+	return nil
+}
+
+func (k memoryKeyring) Delete(name string) error {
+	delete(k, name)
+	return nil
+}
+
+// withTestCredentials points the configuration to a temporary
+// credentials file containing copies of the given credentials, the
+// keyring to an in-memory one and the clients of the credentials to
+// the mock
+func (s *MmctlUnitTestSuite) withTestCredentials(credentials []*Credentials, f func(keyring memoryKeyring, path string)) {
+	tmp, _ := ioutil.TempDir("", "mmctl-")
+	defer os.RemoveAll(tmp)
+	originalConfig := viper.GetString("config")
+	defer viper.Set("config", originalConfig)
+	path := filepath.Join(tmp, "config")
+	viper.Set("config", path)
+
+	originalKeyring := credentialsKeyring
+	defer func() { credentialsKeyring = originalKeyring }()
+	keyring := memoryKeyring{}
+	credentialsKeyring = keyring
+
+	originalNewClient := newCredentialsClient
+	defer func() { newCredentialsClient = originalNewClient }()
+	newCredentialsClient = func(*Credentials) client.Client { return s.client }
+
+	credentialsList := CredentialsList{}
+	for _, c := range credentials {
+		credentialsCopy := *c
+		credentialsList[c.Name] = &credentialsCopy
+	}
+	s.Require().NoError(SaveCredentialsList(&credentialsList))
+	f(keyring, path)
+}
+
+func (s *MmctlUnitTestSuite) TestAuthMigrateKeyringCmd() {
+	admin := &Credentials{Name: "admin", Username: "sysadmin", AuthToken: "admin-token", AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true}
+	ci := &Credentials{Name: "ci", Username: "Personal Access Token", AuthToken: "ci-token", AuthMethod: MethodToken, InstanceURL: "https://mattermost.example.com"}
+
+	credentials := []*Credentials{admin, ci}
+
+	s.Run("Should move the auth tokens to the keyring", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+
+			err := authMigrateKeyringCmdF(&cobra.Command{}, []string{})
+			s.Require().NoError(err)
+			s.Require().Equal([]interface{}{&keyringMigration{Name: "admin"}, &keyringMigration{Name: "ci"}}, printer.GetLines())
+			s.Require().Equal(memoryKeyring{"admin": "admin-token", "ci": "ci-token"}, keyring)
+
+			b, err := ioutil.ReadFile(path)
+			s.Require().NoError(err)
+			s.Require().NotContains(string(b), "admin-token")
+			s.Require().NotContains(string(b), "ci-token")
+
+			credentials, err := GetCurrentCredentials()
+			s.Require().NoError(err)
+			s.Require().Equal("admin-token", credentials.AuthToken)
+		})
+	})
+
+	s.Run("Should only move the given credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+
+			err := authMigrateKeyringCmdF(&cobra.Command{}, []string{"ci"})
+			s.Require().NoError(err)
+			s.Require().Equal(memoryKeyring{"ci": "ci-token"}, keyring)
+
+			credentials, err := GetCredentials("admin")
+			s.Require().NoError(err)
+			s.Require().False(credentials.Keyring)
+			s.Require().Equal("admin-token", credentials.AuthToken)
+
+			s.Require().NoError(deleteCmdF(&cobra.Command{}, []string{"ci"}))
+			s.Require().Empty(keyring)
+		})
+	})
+
+	s.Run("Should fail with unknown credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			err := authMigrateKeyringCmdF(&cobra.Command{}, []string{"unknown"})
+			s.Require().EqualError(err, `cannot find credentials for server name "unknown"`)
+			s.Require().Empty(keyring)
+		})
+	})
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var LogoutCmd = &cobra.Command{
+	Use:   "logout [server name]",
+	Short: "Log out of an instance",
+	Long: `Log out of an instance by deleting its stored credentials, the current ones if no server name is given, or all of them with --all-profiles.
+With --revoke, the session or the access token of the credentials is revoked on the server first, so it can't be used anymore by a copy of the credentials. The credentials that fail to be revoked are kept, so the revocation can be retried.`,
+	Example: `  auth logout
+  auth logout local-server --revoke
+
+  # before decommissioning a machine
+  auth logout --all-profiles --revoke`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: logoutCmdF,
+}
+
+func init() {
+	LogoutCmd.Flags().Bool("all-profiles", false, "Log out of all the stored credentials")
+	LogoutCmd.Flags().Bool("revoke", false, "Revoke the session or the access token of the credentials on the server")
+
+	AuthCmd.AddCommand(LogoutCmd)
+}
+
+// newCredentialsClient returns a client authenticated with the
+// credentials, and is replaced in the tests
+var newCredentialsClient = func(credentials *Credentials) client.Client {
+	c := NewAPIv4Client(credentials.InstanceURL, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	c.AuthType = model.HeaderBearer
+	c.AuthToken = credentials.AuthToken
+	return c
+}
+
+type logoutResult struct {
+	Name        string `json:"name"`
+	InstanceURL string `json:"instance_url"`
+	Revoked     bool   `json:"revoked"`
+}
+
+// revokeCredentials revokes the access token of the credentials if its
+// ID is known, or their session otherwise. A session or a token that
+// isn't valid anymore has nothing left to revoke
+func revokeCredentials(c client.Client, credentials *Credentials) error {
+	var r *model.Response
+	var err error
+	switch {
+	case credentials.TokenID != "":
+		r, err = c.RevokeUserAccessToken(credentials.TokenID)
+	case credentials.AuthMethod == MethodToken:
+		return errors.Errorf("the access token of %q can't be revoked as its ID isn't known, revoke it with %q", credentials.Name, "token revoke")
+	default:
+		r, err = c.Logout()
+	}
+
+	if err != nil && (r == nil || r.StatusCode != http.StatusUnauthorized) {
+		return errors.Wrapf(err, "cannot revoke the credentials of %q", credentials.Name)
+	}
+	return nil
+}
+
+func logoutCmdF(cmd *cobra.Command, args []string) error {
+	allProfiles, _ := cmd.Flags().GetBool("all-profiles")
+	revoke, _ := cmd.Flags().GetBool("revoke")
+
+	if allProfiles && len(args) > 0 {
+		return errors.New("a server name can't be given with --all-profiles")
+	}
+
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	switch {
+	case allProfiles:
+		for name := range *credentialsList {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	case len(args) > 0:
+		if (*credentialsList)[args[0]] == nil {
+			return errors.Errorf("cannot find credentials for server name %q", args[0])
+		}
+		names = append(names, args[0])
+	default:
+		current, err := GetCurrentCredentials()
+		if err != nil {
+			return err
+		}
+		names = append(names, current.Name)
+	}
+
+	failed := 0
+	loggedOut := []string{}
+	for _, name := range names {
+		credentials := (*credentialsList)[name]
+		if revoke {
+			if err := loadKeyringToken(credentials); err != nil {
+				printer.PrintError(err.Error())
+				failed++
+				continue
+			}
+			if err := revokeCredentials(newCredentialsClient(credentials), credentials); err != nil {
+				printer.PrintError(err.Error())
+				failed++
+				continue
+			}
+		}
+
+		if err := deleteKeyringToken(credentials); err != nil {
+			printer.PrintError(err.Error())
+			failed++
+			continue
+		}
+		delete(*credentialsList, name)
+		loggedOut = append(loggedOut, name)
+
+		result := &logoutResult{Name: credentials.Name, InstanceURL: credentials.InstanceURL, Revoked: revoke}
+		printer.PrintT("Logged out of {{.Name}} ({{.InstanceURL}}){{if .Revoked}}, credentials revoked{{end}}", result)
+	}
+
+	if err := SaveCredentialsList(credentialsList); err != nil {
+		return err
+	}
+	if len(*credentialsList) == 0 {
+		if err := CleanCredentials(); err != nil {
+			return err
+		}
+	}
+
+	// the version history is informative only, so failing to delete
+	// it must not fail the logout
+	if err := deleteVersionHistory(loggedOut...); err != nil && !viper.GetBool("suppress-warnings") {
+		printer.PrintWarning("unable to delete the version history: " + err.Error())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to log out of %d servers", failed)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestLogoutCmd() {
+	production := &Credentials{Name: "production", Username: "sysadmin", AuthToken: "production-token", AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true}
+	ci := &Credentials{Name: "ci", Username: "Personal Access Token", AuthToken: "ci-token", AuthMethod: MethodToken, InstanceURL: "https://mattermost.example.com", TokenID: "token-id", Keyring: true}
+
+	credentials := []*Credentials{production, ci}
+
+	newCmd := func(allProfiles, revoke bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all-profiles", allProfiles, "")
+		cmd.Flags().Bool("revoke", revoke, "")
+		return cmd
+	}
+
+	s.Run("Should log out of the current credentials without revoking them", func() {
+		printer.Clean()
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			s.Require().NoError(recordServerVersion(production, "6.3.0"))
+			s.Require().NoError(recordServerVersion(ci, "6.3.0"))
+
+			err := logoutCmdF(newCmd(false, false), []string{})
+			s.Require().NoError(err)
+			s.Require().Equal([]interface{}{&logoutResult{Name: "production", InstanceURL: production.InstanceURL}}, printer.GetLines())
+
+			history, err := readVersionHistory()
+			s.Require().NoError(err)
+			s.Require().NotContains(history, "production")
+			s.Require().Contains(history, "ci")
+
+			credentialsList, err := ReadCredentialsList()
+			s.Require().NoError(err)
+			s.Require().Len(*credentialsList, 1)
+			s.Require().NotNil((*credentialsList)["ci"])
+		})
+	})
+
+	s.Run("Should revoke and delete all the credentials", func() {
+		printer.Clean()
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			s.client.
+				EXPECT().
+				RevokeUserAccessToken(ci.TokenID).
+				Return(&model.Response{}, nil).
+				Times(1)
+
+			// an expired session has nothing left to revoke
+			s.client.
+				EXPECT().
+				Logout().
+				Return(&model.Response{StatusCode: http.StatusUnauthorized}, errors.New("invalid session")).
+				Times(1)
+
+			err := logoutCmdF(newCmd(true, true), []string{})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 2)
+			s.Require().Empty(keyring)
+			s.Require().NoFileExists(path)
+		})
+	})
+
+	s.Run("Should keep the credentials that fail to be revoked", func() {
+		printer.Clean()
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			s.client.
+				EXPECT().
+				Logout().
+				Return(&model.Response{StatusCode: http.StatusInternalServerError}, errors.New("server error")).
+				Times(1)
+
+			err := logoutCmdF(newCmd(false, true), []string{"production"})
+			s.Require().EqualError(err, "unable to log out of 1 servers")
+			s.Require().Len(printer.GetErrorLines(), 1)
+
+			_, err = GetCredentials("production")
+			s.Require().NoError(err)
+		})
+	})
+
+	s.Run("Should fail with a server name and --all-profiles", func() {
+		err := logoutCmdF(newCmd(true, false), []string{"production"})
+		s.Require().EqualError(err, "a server name can't be given with --all-profiles")
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func (s *MmctlUnitTestSuite) TestAuthProfiles() {
+	production := &Credentials{Name: "production", Username: "sysadmin", AuthToken: "production-token", AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true}
+	staging := &Credentials{Name: "staging", Username: "sysadmin", AuthToken: "staging-token", AuthMethod: MethodPassword, InstanceURL: "https://staging.example.com", Keyring: true}
+
+	credentials := []*Credentials{production, staging}
+
+	s.Run("Should use the credentials of the profile instead of the active ones", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			defer viper.Set("profile", "")
+
+			credentials, err := GetCurrentCredentials()
+			s.Require().NoError(err)
+			s.Require().Equal("production", credentials.Name)
+
+			viper.Set("profile", "staging")
+			credentials, err = GetCurrentCredentials()
+			s.Require().NoError(err)
+			s.Require().Equal("staging", credentials.Name)
+			s.Require().Equal("staging-token", credentials.AuthToken)
+
+			viper.Set("profile", "unknown")
+			_, err = GetCurrentCredentials()
+			s.Require().EqualError(err, `couldn't find credentials for connection "unknown"`)
+		})
+	})
+
+	s.Run("Should rename the credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			s.Require().NoError(recordServerVersion(staging, "6.4.0"))
+
+			err := renameCmdF(&cobra.Command{}, []string{"staging", "preprod"})
+			s.Require().NoError(err)
+			s.Require().Equal(memoryKeyring{"preprod": "staging-token"}, keyring)
+
+			history, err := readVersionHistory()
+			s.Require().NoError(err)
+			s.Require().NotContains(history, "staging")
+			s.Require().Len(history["preprod"], 1)
+			s.Require().Equal("preprod", history["preprod"][0].Server)
+
+			credentials, err := GetCredentials("preprod")
+			s.Require().NoError(err)
+			s.Require().Equal("preprod", credentials.Name)
+			s.Require().Equal("staging-token", credentials.AuthToken)
+
+			_, err = GetCredentials("staging")
+			s.Require().Error(err)
+
+			err = renameCmdF(&cobra.Command{}, []string{"production", "live"})
+			s.Require().NoError(err)
+			credentials, err = GetCurrentCredentials()
+			s.Require().NoError(err)
+			s.Require().Equal("live", credentials.Name)
+		})
+	})
+
+	s.Run("Should not overwrite existing credentials when renaming", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			err := renameCmdF(&cobra.Command{}, []string{"staging", "production"})
+			s.Require().EqualError(err, `there are already credentials for server name "production"`)
+
+			err = renameCmdF(&cobra.Command{}, []string{"unknown", "other"})
+			s.Require().EqualError(err, `cannot find credentials for server name "unknown"`)
+		})
+	})
+}
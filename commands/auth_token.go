@@ -0,0 +1,285 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var AuthTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage automation tokens",
+	Long:  "Manage user access tokens identified by their description, which can be stored as dedicated credentials for automation, so CI jobs use purpose-built tokens instead of the session of an administrator.",
+}
+
+var AuthTokenCreateCmd = &cobra.Command{
+	Use:   "create [description]",
+	Short: "Create an automation token",
+	Long: `Create a user access token with the given description for the current user, or for the user given with --user.
+With --scoped, the token is stored as a dedicated set of credentials for the server of the current credentials, named after the description unless --name is given. The stored credentials are not activated, use them with "auth set" or the --config flag of a CI job. Giving the token to a user with the minimum permissions needed by the automation keeps its privileges to a minimum.`,
+	Example: `  auth token create ci-deploy
+  auth token create ci-deploy --scoped --user ci-bot --name ci-deploy`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(authTokenCreateCmdF),
+}
+
+var AuthTokenListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the automation tokens",
+	Long:    "List the active user access tokens of the current user, or of the user given with --user, along with the stored credentials using them.",
+	Example: `  auth token list --user ci-bot`,
+	Args:    cobra.NoArgs,
+	RunE:    withClient(authTokenListCmdF),
+}
+
+var AuthTokenRevokeCmd = &cobra.Command{
+	Use:     "revoke [descriptions]",
+	Short:   "Revoke automation tokens by description",
+	Long:    "Revoke the active user access tokens with the given descriptions of the current user, or of the user given with --user, and delete the stored credentials using them.",
+	Example: `  auth token revoke ci-deploy --user ci-bot`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(authTokenRevokeCmdF),
+}
+
+func init() {
+	AuthTokenCreateCmd.Flags().String("user", "", "User to create the token for, defaults to the current one")
+	AuthTokenCreateCmd.Flags().Bool("scoped", false, "Store the token as dedicated credentials")
+	AuthTokenCreateCmd.Flags().String("name", "", "Name of the credentials to store the token as, defaults to the description")
+
+	AuthTokenListCmd.Flags().String("user", "", "User to list the tokens of, defaults to the current one")
+
+	AuthTokenRevokeCmd.Flags().String("user", "", "User to revoke the tokens of, defaults to the current one")
+
+	AuthTokenCmd.AddCommand(
+		AuthTokenCreateCmd,
+		AuthTokenListCmd,
+		AuthTokenRevokeCmd,
+	)
+
+	AuthCmd.AddCommand(AuthTokenCmd)
+}
+
+type authTokenResult struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Username    string `json:"username"`
+	Credentials string `json:"credentials,omitempty"`
+	Token       string `json:"token,omitempty"`
+}
+
+func getAuthTokenUser(c client.Client, userArg string) (*model.User, error) {
+	if userArg == "" {
+		user, _, err := c.GetMe("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the current user")
+		}
+		return user, nil
+	}
+
+	return getUserFromArg(c, userArg)
+}
+
+func getActiveUserAccessTokens(c client.Client, userID string) ([]*model.UserAccessToken, error) {
+	tokens := []*model.UserAccessToken{}
+	for page := 0; ; page++ {
+		pageTokens, _, err := c.GetUserAccessTokensForUser(userID, page, APILimitMaximum)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the tokens of the user")
+		}
+		for _, token := range pageTokens {
+			if token.IsActive {
+				tokens = append(tokens, token)
+			}
+		}
+		if len(pageTokens) < APILimitMaximum {
+			return tokens, nil
+		}
+	}
+}
+
+// getTokenCredentials maps the ids of the tokens to the names of the
+// credentials they are stored as. Missing credentials files are not an
+// error, as the tokens can be managed without them
+func getTokenCredentials() map[string]string {
+	tokenCredentials := map[string]string{}
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return tokenCredentials
+	}
+	for _, credentials := range *credentialsList {
+		if credentials.TokenID != "" {
+			tokenCredentials[credentials.TokenID] = credentials.Name
+		}
+	}
+	return tokenCredentials
+}
+
+func authTokenCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	description := args[0]
+	userArg, _ := cmd.Flags().GetString("user")
+	scoped, _ := cmd.Flags().GetBool("scoped")
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		name = description
+	}
+
+	clientConfig, _, err := c.GetOldClientConfig("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the client config")
+	}
+	if clientConfig["EnableUserAccessTokens"] != "true" {
+		return errors.New("the server doesn't allow user access tokens, they can be enabled with the ServiceSettings.EnableUserAccessTokens setting")
+	}
+
+	var current *Credentials
+	if scoped {
+		if current, err = GetCurrentCredentials(); err != nil {
+			return errors.Wrap(err, "the current credentials are needed to store a scoped token")
+		}
+		if _, err := GetCredentials(name); err == nil {
+			return fmt.Errorf("credentials %q already exist", name)
+		}
+	}
+
+	user, err := getAuthTokenUser(c, userArg)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := getActiveUserAccessTokens(c, user.Id)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.Description == description {
+			return fmt.Errorf("user %q already has a token with description %q", user.Username, description)
+		}
+	}
+
+	token, _, err := c.CreateUserAccessToken(user.Id, description)
+	if err != nil {
+		return errors.Wrapf(err, "could not create token for %q", user.Username)
+	}
+
+	result := authTokenResult{ID: token.Id, Description: token.Description, Username: user.Username}
+	if !scoped {
+		result.Token = token.Token
+		printer.PrintT("{{.Token}}: {{.Description}}", result)
+		return nil
+	}
+
+	credentials := Credentials{
+		Name:        name,
+		Username:    user.Username,
+		AuthToken:   token.Token,
+		AuthMethod:  MethodToken,
+		InstanceURL: current.InstanceURL,
+		TokenID:     token.Id,
+	}
+	if err := SaveCredentials(credentials); err != nil {
+		return errors.Wrap(err, "the token was created but couldn't be stored")
+	}
+
+	result.Credentials = name
+	printer.PrintT("Token {{.Description}} of {{.Username}} stored as credentials {{.Credentials}}", result)
+	return nil
+}
+
+func authTokenListCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	userArg, _ := cmd.Flags().GetString("user")
+	user, err := getAuthTokenUser(c, userArg)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := getActiveUserAccessTokens(c, user.Id)
+	if err != nil {
+		return err
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Description < tokens[j].Description })
+
+	tokenCredentials := getTokenCredentials()
+	for _, token := range tokens {
+		printer.PrintT("{{.ID}}: {{.Description}}{{if .Credentials}} (credentials {{.Credentials}}){{end}}", authTokenResult{
+			ID:          token.Id,
+			Description: token.Description,
+			Username:    user.Username,
+			Credentials: tokenCredentials[token.Id],
+		})
+	}
+
+	return nil
+}
+
+func authTokenRevokeCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	userArg, _ := cmd.Flags().GetString("user")
+	user, err := getAuthTokenUser(c, userArg)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := getActiveUserAccessTokens(c, user.Id)
+	if err != nil {
+		return err
+	}
+
+	tokenCredentials := getTokenCredentials()
+	var credentialsList *CredentialsList
+	if len(tokenCredentials) > 0 {
+		if credentialsList, err = ReadCredentialsList(); err != nil {
+			return err
+		}
+	}
+
+	var result error
+	for _, description := range args {
+		found := false
+		for _, token := range tokens {
+			if token.Description != description {
+				continue
+			}
+			found = true
+
+			if _, err := c.RevokeUserAccessToken(token.Id); err != nil {
+				result = multierror.Append(result, fmt.Errorf("could not revoke token %q: %w", description, err))
+				continue
+			}
+
+			revoked := authTokenResult{ID: token.Id, Description: token.Description, Username: user.Username}
+			if name, ok := tokenCredentials[token.Id]; ok {
+				if credentials := (*credentialsList)[name]; credentials != nil {
+					// the token is revoked, so failing to remove it from
+					// the keyring doesn't keep the credentials
+					if keyringErr := deleteKeyringToken(credentials); keyringErr != nil {
+						result = multierror.Append(result, keyringErr)
+					}
+				}
+				delete(*credentialsList, name)
+				revoked.Credentials = name
+			}
+			printer.PrintT("Token {{.Description}} of {{.Username}} revoked{{if .Credentials}}, credentials {{.Credentials}} deleted{{end}}", revoked)
+		}
+
+		if !found {
+			result = multierror.Append(result, fmt.Errorf("user %q has no active token with description %q", user.Username, description))
+		}
+	}
+
+	if credentialsList != nil {
+		if err := SaveCredentialsList(credentialsList); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	return result
+}
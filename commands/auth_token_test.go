@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestAuthTokenCmds() {
+	bot := &model.User{Id: "bot-id", Username: "ci-bot", Email: "ci-bot@example.com"}
+	admin := &Credentials{Name: "admin", Username: "sysadmin", AuthToken: "admin-token", AuthMethod: MethodPassword, InstanceURL: "https://mattermost.example.com", Active: true}
+
+	credentials := []*Credentials{admin}
+
+	expectTokens := func(tokens []*model.UserAccessToken) {
+		s.client.
+			EXPECT().
+			GetUserByEmail(bot.Email, "").
+			Return(bot, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserAccessTokensForUser(bot.Id, 0, APILimitMaximum).
+			Return(tokens, &model.Response{}, nil).
+			Times(1)
+	}
+
+	expectTokensEnabled := func(enabled string) {
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(map[string]string{"EnableUserAccessTokens": enabled}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	newCmd := func(scoped bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("user", bot.Email, "")
+		cmd.Flags().Bool("scoped", scoped, "")
+		cmd.Flags().String("name", "", "")
+		return cmd
+	}
+
+	s.Run("Should create a token and store it as credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+			expectTokensEnabled("true")
+			expectTokens([]*model.UserAccessToken{{Id: "revoked-id", Description: "ci-deploy", IsActive: false}})
+
+			s.client.
+				EXPECT().
+				CreateUserAccessToken(bot.Id, "ci-deploy").
+				Return(&model.UserAccessToken{Id: "token-id", Token: "secret-token", Description: "ci-deploy"}, &model.Response{}, nil).
+				Times(1)
+
+			err := authTokenCreateCmdF(s.client, newCmd(true), []string{"ci-deploy"})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 1)
+			s.Require().Equal(authTokenResult{ID: "token-id", Description: "ci-deploy", Username: bot.Username, Credentials: "ci-deploy"}, printer.GetLines()[0])
+
+			credentials, err := GetCredentials("ci-deploy")
+			s.Require().NoError(err)
+			s.Require().Equal(&Credentials{
+				Name:        "ci-deploy",
+				Username:    bot.Username,
+				AuthToken:   "secret-token",
+				AuthMethod:  MethodToken,
+				InstanceURL: admin.InstanceURL,
+				TokenID:     "token-id",
+			}, credentials)
+
+			current, err := GetCurrentCredentials()
+			s.Require().NoError(err)
+			s.Require().Equal(admin.Name, current.Name)
+		})
+	})
+
+	s.Run("Should not create a token with a duplicated description", func() {
+		printer.Clean()
+		expectTokensEnabled("true")
+		expectTokens([]*model.UserAccessToken{{Id: "token-id", Description: "ci-deploy", IsActive: true}})
+
+		err := authTokenCreateCmdF(s.client, newCmd(false), []string{"ci-deploy"})
+		s.Require().EqualError(err, `user "ci-bot" already has a token with description "ci-deploy"`)
+	})
+
+	s.Run("Should fail if the server doesn't allow user access tokens", func() {
+		printer.Clean()
+		expectTokensEnabled("false")
+
+		err := authTokenCreateCmdF(s.client, newCmd(false), []string{"ci-deploy"})
+		s.Require().EqualError(err, "the server doesn't allow user access tokens, they can be enabled with the ServiceSettings.EnableUserAccessTokens setting")
+	})
+
+	s.Run("Should revoke the tokens by description and delete their credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+			s.Require().NoError(SaveCredentials(Credentials{Name: "deploy", AuthToken: "secret-token", AuthMethod: MethodToken, TokenID: "token-id"}))
+			expectTokens([]*model.UserAccessToken{
+				{Id: "token-id", Description: "ci-deploy", IsActive: true},
+				{Id: "other-id", Description: "ci-test", IsActive: true},
+			})
+
+			s.client.
+				EXPECT().
+				RevokeUserAccessToken("token-id").
+				Return(&model.Response{StatusCode: 200}, nil).
+				Times(1)
+
+			err := authTokenRevokeCmdF(s.client, newCmd(false), []string{"ci-deploy", "ci-missing"})
+			s.Require().Error(err)
+			s.Require().Contains(err.Error(), `user "ci-bot" has no active token with description "ci-missing"`)
+			s.Require().Len(printer.GetLines(), 1)
+			s.Require().Equal(authTokenResult{ID: "token-id", Description: "ci-deploy", Username: bot.Username, Credentials: "deploy"}, printer.GetLines()[0])
+
+			_, err = GetCredentials("deploy")
+			s.Require().Error(err)
+			_, err = GetCredentials(admin.Name)
+			s.Require().NoError(err)
+		})
+	})
+
+	s.Run("Should remove the auth token of the deleted credentials from the keyring", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+
+			s.Require().NoError(SaveCredentials(Credentials{Name: "deploy", AuthToken: "secret-token", AuthMethod: MethodToken, TokenID: "token-id", Keyring: true}))
+			s.Require().Equal(memoryKeyring{"deploy": "secret-token"}, keyring)
+			expectTokens([]*model.UserAccessToken{{Id: "token-id", Description: "ci-deploy", IsActive: true}})
+
+			s.client.
+				EXPECT().
+				RevokeUserAccessToken("token-id").
+				Return(&model.Response{StatusCode: 200}, nil).
+				Times(1)
+
+			err := authTokenRevokeCmdF(s.client, newCmd(false), []string{"ci-deploy"})
+			s.Require().NoError(err)
+			s.Require().Empty(keyring)
+			_, err = GetCredentials("deploy")
+			s.Require().Error(err)
+		})
+	})
+
+	s.Run("Should list the active tokens with their credentials", func() {
+		s.withTestCredentials(credentials, func(keyring memoryKeyring, path string) {
+			printer.Clean()
+			s.Require().NoError(SaveCredentials(Credentials{Name: "deploy", AuthToken: "secret-token", AuthMethod: MethodToken, TokenID: "token-id"}))
+			expectTokens([]*model.UserAccessToken{
+				{Id: "token-id", Description: "ci-deploy", IsActive: true},
+				{Id: "revoked-id", Description: "ci-old", IsActive: false},
+				{Id: "other-id", Description: "ci-build", IsActive: true},
+			})
+
+			err := authTokenListCmdF(s.client, newCmd(false), []string{})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 2)
+			s.Require().Equal(authTokenResult{ID: "other-id", Description: "ci-build", Username: bot.Username}, printer.GetLines()[0])
+			s.Require().Equal(authTokenResult{ID: "token-id", Description: "ci-deploy", Username: bot.Username, Credentials: "deploy"}, printer.GetLines()[1])
+		})
+	})
+}
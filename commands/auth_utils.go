@@ -38,6 +38,15 @@ type Credentials struct {
 	AuthMethod  string `json:"authMethod"`
 	InstanceURL string `json:"instanceUrl"`
 	Active      bool   `json:"active"`
+	// TokenID is the id of the user access token of the credentials
+	// created with "auth token create --scoped"
+	TokenID string `json:"tokenId,omitempty"`
+	// SessionID is the id of the session created by the login of the
+	// password and MFA credentials
+	SessionID string `json:"sessionId,omitempty"`
+	// Keyring is set when the auth token is stored in the keyring of
+	// the operating system instead of the credentials file
+	Keyring bool `json:"keyring,omitempty"`
 }
 
 type CredentialsList map[string]*Credentials
@@ -118,7 +127,13 @@ func ReadCredentialsList() (*CredentialsList, error) {
 	return &credentialsList, nil
 }
 
+// GetCurrentCredentials returns the active credentials, or the ones
+// given with the --profile flag, which take precedence for the command
 func GetCurrentCredentials() (*Credentials, error) {
+	if profile := viper.GetString("profile"); profile != "" {
+		return GetCredentials(profile)
+	}
+
 	credentialsList, err := ReadCredentialsList()
 	if err != nil {
 		return nil, err
@@ -126,6 +141,9 @@ func GetCurrentCredentials() (*Credentials, error) {
 
 	for _, c := range *credentialsList {
 		if c.Active {
+			if err := loadKeyringToken(c); err != nil {
+				return nil, err
+			}
 			return c, nil
 		}
 	}
@@ -140,6 +158,9 @@ func GetCredentials(name string) (*Credentials, error) {
 
 	for _, c := range *credentialsList {
 		if c.Name == name {
+			if err := loadKeyringToken(c); err != nil {
+				return nil, err
+			}
 			return c, nil
 		}
 	}
@@ -165,7 +186,11 @@ func SaveCredentials(credentials Credentials) error {
 func SaveCredentialsList(credentialsList *CredentialsList) error {
 	configPath := resolveConfigFilePath()
 
-	marshaledCredentialsList, _ := json.MarshalIndent(credentialsList, "", "    ")
+	fileCredentialsList, err := storeKeyringTokens(credentialsList)
+	if err != nil {
+		return err
+	}
+	marshaledCredentialsList, _ := json.MarshalIndent(fileCredentialsList, "", "    ")
 
 	if err := ioutil.WriteFile(configPath, marshaledCredentialsList, 0600); err != nil {
 		return errors.WithMessage(err, "cannot save the credentials")
@@ -207,6 +232,14 @@ func CleanCredentials() error {
 		return err
 	}
 
+	if credentialsList, err := ReadCredentialsList(); err == nil {
+		for _, c := range *credentialsList {
+			if err := deleteKeyringToken(c); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := os.Remove(configFilePath); err != nil {
 		return err
 	}
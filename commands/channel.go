@@ -27,9 +27,13 @@ var ChannelCmd = &cobra.Command{
 var ChannelCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a channel",
-	Long:  `Create a channel.`,
+	Long: `Create a channel.
+With --from-template, the channel is set up from a YAML template with the header, purpose, privacy, sidebar category, members, bookmarks and welcome post of the channel, which the header, purpose and private flags override. The header, purpose and welcome post can use the {{.Name}}, {{.DisplayName}} and {{.Team}} of the channel. The channel is placed in the sidebar category of each of the members of the template, and as the server has no channel bookmarks, the bookmarks are listed in the welcome post, which is pinned to the channel.`,
 	Example: `  channel create --team myteam --name mynewchannel --display-name "My New Channel"
-  channel create --team myteam --name mynewprivatechannel --display-name "My New Private Channel" --private`,
+  channel create --team myteam --name mynewprivatechannel --display-name "My New Private Channel" --private
+
+  # create a standardized project channel
+  channel create --team myteam --name project-x --display-name "Project X" --from-template project.yaml`,
 	RunE: withClient(createChannelCmdF),
 }
 
@@ -69,19 +73,34 @@ var ArchiveChannelsCmd = &cobra.Command{
 	Short: "Archive channels",
 	Long: `Archive some channels.
 Archive a channel along with all related information including posts from the database.
-Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
-	Example: "  channel archive myteam:mychannel",
-	RunE:    withClient(archiveChannelsCmdF),
+Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.
+With --export-first, each channel and its posts are exported to a local zip archive before archiving it, and the channels that fail to export are not archived.`,
+	Example: `  channel archive myteam:mychannel
+
+  # keep a local copy of the channels before archiving them
+  channel archive myteam:mychannel myteam:otherchannel --export-first --export-dir ./archives
+
+  # read the channels to archive from the standard input
+  channel list myteam --id-only | mmctl channel archive -`,
+	RunE: withClient(withPlan(channelPlanResolver("archive_channel"), archiveChannelsCmdF)),
 }
 
 var DeleteChannelsCmd = &cobra.Command{
 	Use:   "delete [channels]",
 	Short: "Delete channels",
 	Long: `Permanently delete some channels.
-Permanently deletes one or multiple channels along with all related information including posts from the database.`,
-	Example: "  channel delete myteam:mychannel",
-	Args:    cobra.MinimumNArgs(1),
-	RunE:    withClient(deleteChannelsCmdF),
+Permanently deletes one or multiple channels along with all related information including posts from the database.
+With --export-first, each channel and its posts are exported to a local zip archive before deleting it, and the channels that fail to export are not deleted. The archive is a record of the channel for reading only: it doesn't contain the attachments, and it can't be imported back, so the deleted channels can't be restored from it.`,
+	Example: `  channel delete myteam:mychannel
+
+  # keep a local copy of the channel before deleting it
+  channel delete myteam:mychannel --export-first --export-dir ./archives --confirm
+
+  # review the plan before applying it
+  channel delete myteam:mychannel myteam:otherchannel --plan > plan.json
+  channel delete --apply-plan plan.json --confirm`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(withPlan(channelPlanResolver("delete_channel"), deleteChannelsCmdF)),
 }
 
 // ListChannelsCmd is a command which lists all the channels of team(s) in a server.
@@ -90,10 +109,19 @@ var ListChannelsCmd = &cobra.Command{
 	Short: "List all channels on specified teams.",
 	Long: `List all channels on specified teams.
 Archived channels are appended with ' (archived)'.
-Private channels the user is a member of or has access to are appended with ' (private)'.`,
-	Example: "  channel list myteam",
-	Args:    cobra.MinimumNArgs(1),
-	RunE:    withClient(listChannelsCmdF),
+Private channels the user is a member of or has access to are appended with ' (private)'.
+With --expand, the creator and the team of each channel are embedded into the JSON output.
+With --orphaned-creator, only the channels whose creator is deactivated or deleted and that have no channel admins are listed, and --assign-admin promotes the member that posted the most among the latest posts of each of them to channel admin. Without --confirm, the members that would be promoted are listed and nothing is changed.`,
+	Example: `  channel list myteam
+
+  # check which members would be promoted, then promote them
+  channel list myteam --orphaned-creator --assign-admin
+  channel list myteam --orphaned-creator --assign-admin --confirm
+
+  # include the creator and the team of the channels
+  channel list myteam --expand creator,team --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(listChannelsCmdF),
 }
 
 var ModifyChannelCmd = &cobra.Command{
@@ -151,11 +179,12 @@ var MoveChannelCmd = &cobra.Command{
 	Use:   "move [team] [channels]",
 	Short: "Moves channels to the specified team",
 	Long: `Moves the provided channels to the specified team.
-Validates that all users in the channel belong to the target team. Incoming/Outgoing webhooks are moved along with the channel.
+Validates that all users in the channel belong to the target team, and reports the members of the channel that don't, as they would lose access to the channel. The channel isn't moved unless they are added to the target team with --add-missing-members, or removed from the channel with --force. The posts and the incoming/outgoing webhooks are moved along with the channel.
 Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
-	Example: "  channel move newteam oldteam:mychannel",
-	Args:    cobra.MinimumNArgs(2),
-	RunE:    withClient(moveChannelCmdF),
+	Example: `  channel move newteam oldteam:mychannel
+  channel move newteam oldteam:mychannel --add-missing-members`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: withClient(moveChannelCmdF),
 }
 
 func init() {
@@ -180,9 +209,17 @@ func init() {
 
 	SearchChannelCmd.Flags().String("team", "", "Team name or ID")
 
+	ListChannelsCmd.Flags().StringSlice("expand", nil, "Related entities to embed into the JSON output of each channel: creator, team")
+
 	MoveChannelCmd.Flags().Bool("force", false, "Remove users that are not members of target team before moving the channel.")
+	MoveChannelCmd.Flags().Bool("add-missing-members", false, "Add the users that are not members of target team to it before moving the channel.")
 
 	DeleteChannelsCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the channel and a DB backup has been performed.")
+	addPlanFlags(DeleteChannelsCmd)
+	addPlanFlags(ArchiveChannelsCmd)
+	addExportFirstFlags(DeleteChannelsCmd)
+	addExportFirstFlags(ArchiveChannelsCmd)
+	addConcurrencyFlag(ArchiveChannelsCmd, "channels")
 
 	ChannelCmd.AddCommand(
 		ChannelCreateCmd,
@@ -224,6 +261,15 @@ func createChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	header, _ := cmd.Flags().GetString("header")
 	purpose, _ := cmd.Flags().GetString("purpose")
 	useprivate, _ := cmd.Flags().GetBool("private")
+	templatePath, _ := cmd.Flags().GetString("from-template")
+
+	var tmpl *channelTemplate
+	if templatePath != "" {
+		var err error
+		if tmpl, err = loadChannelTemplate(templatePath); err != nil {
+			return err
+		}
+	}
 
 	channelType := model.ChannelTypeOpen
 	if useprivate {
@@ -244,6 +290,12 @@ func createChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error
 		Type:        channelType,
 		CreatorId:   "",
 	}
+	if tmpl != nil {
+		if err := tmpl.render(channel, team); err != nil {
+			return err
+		}
+		tmpl.applyTo(cmd, channel)
+	}
 
 	newChannel, _, err := c.CreateChannel(channel)
 	if err != nil {
@@ -252,6 +304,10 @@ func createChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error
 
 	printer.PrintT("New channel {{.Name}} successfully created", newChannel)
 
+	if tmpl != nil {
+		return applyChannelTemplate(c, tmpl, newChannel)
+	}
+
 	return nil
 }
 
@@ -260,16 +316,35 @@ func archiveChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) err
 		return errors.New("enter at least one channel to archive")
 	}
 
+	exportDir, err := getExportFirstDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := getConcurrency(cmd)
+	if err != nil {
+		return err
+	}
+
 	channels := getChannelsFromChannelArgs(c, args)
-	for i, channel := range channels {
+	forEachConcurrently(len(channels), concurrency, func(i int) {
+		channel := channels[i]
 		if channel == nil {
 			printer.PrintError("Unable to find channel '" + args[i] + "'")
-			continue
+			return
+		}
+		if exportDir != "" {
+			result, err := exportChannelArchive(c, channel, exportDir)
+			if err != nil {
+				printer.PrintError("Unable to export channel '" + channel.Name + "', it won't be archived. error: " + err.Error())
+				return
+			}
+			printer.PrintT("Exported channel '{{.Name}}' to {{.Path}}", result)
 		}
 		if _, err := c.DeleteChannel(channel.Id); err != nil {
 			printer.PrintError("Unable to archive channel '" + channel.Name + "' error: " + err.Error())
 		}
-	}
+	})
 
 	return nil
 }
@@ -317,18 +392,33 @@ func getAllDeletedChannelsForTeam(c client.Client, teamID string) ([]*model.Chan
 }
 
 func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	orphanedCreator, _ := cmd.Flags().GetBool("orphaned-creator")
+	assignAdmin, _ := cmd.Flags().GetBool("assign-admin")
+	if assignAdmin && !orphanedCreator {
+		return errors.New("the --assign-admin flag can only be used with --orphaned-creator")
+	}
+	if orphanedCreator {
+		return listOrphanedChannelsCmdF(c, cmd, args)
+	}
+
+	expander, err := newEntityExpander(c, cmd, "creator", "team")
+	if err != nil {
+		return err
+	}
+
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
 			printer.PrintError("Unable to find team '" + args[i] + "'")
 			continue
 		}
+		expander.addTeam(team)
 
 		publicChannels, err := getAllPublicChannelsForTeam(c, team.Id)
 		if err != nil {
 			printer.PrintError(fmt.Sprintf("unable to list public channels for %q: %s", args[i], err))
 		}
-		for _, channel := range publicChannels {
+		for _, channel := range expander.channels(publicChannels) {
 			printer.PrintT("{{.Name}}", channel)
 		}
 
@@ -336,7 +426,7 @@ func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 		if err != nil {
 			printer.PrintError(fmt.Sprintf("unable to list archived channels for %q: %s", args[i], err))
 		}
-		for _, channel := range deletedChannels {
+		for _, channel := range expander.channels(deletedChannels) {
 			printer.PrintT("{{.Name}} (archived)", channel)
 		}
 
@@ -344,7 +434,7 @@ func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 		if appErr != nil {
 			printer.PrintError(fmt.Sprintf("unable to list private channels for %q: %s", args[i], appErr.Error()))
 		}
-		for _, channel := range privateChannels {
+		for _, channel := range expander.channels(privateChannels) {
 			printer.PrintT("{{.Name}} (private)", channel)
 		}
 	}
@@ -510,8 +600,107 @@ func searchChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	return nil
 }
 
+// channelMoveMember is a member of a moved channel that isn't a member
+// of the target team, with what happens to them
+type channelMoveMember struct {
+	Channel  string `json:"channel"`
+	Team     string `json:"team"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id"`
+	Action   string `json:"action"`
+}
+
+const (
+	channelMoveMemberMissing = "missing"
+	channelMoveMemberAdded   = "added"
+	channelMoveMemberRemoved = "removed"
+)
+
+// getChannelMembersNotInTeam returns the members of the channel that
+// aren't members of the team
+func getChannelMembersNotInTeam(c client.Client, channel *model.Channel, teamID string) ([]*model.User, error) {
+	teamMembers := map[string]bool{}
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsersInTeam(teamID, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the members of the team")
+		}
+		for _, user := range users {
+			teamMembers[user.Id] = true
+		}
+		if len(users) < APILimitMaximum {
+			break
+		}
+	}
+
+	missingIDs := []string{}
+	for page := 0; ; page++ {
+		channelMembers, _, err := c.GetChannelMembers(channel.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the members of the channel")
+		}
+		for _, member := range channelMembers {
+			if !teamMembers[member.UserId] {
+				missingIDs = append(missingIDs, member.UserId)
+			}
+		}
+		if len(channelMembers) < APILimitMaximum {
+			break
+		}
+	}
+
+	if len(missingIDs) == 0 {
+		return []*model.User{}, nil
+	}
+	users, _, err := c.GetUsersByIds(missingIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the members of the channel")
+	}
+	return users, nil
+}
+
+// prepareChannelMove reports the members of the channel that aren't
+// members of the team, and adds them to the team if asked. It returns
+// an error if the channel can't be moved without them
+func prepareChannelMove(c client.Client, channel *model.Channel, team *model.Team, force, addMissingMembers bool) error {
+	missing, err := getChannelMembersNotInTeam(c, channel, team.Id)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, user := range missing {
+		member := &channelMoveMember{Channel: channel.Name, Team: team.Name, Username: user.Username, UserID: user.Id, Action: channelMoveMemberMissing}
+		switch {
+		case addMissingMembers:
+			if _, _, addErr := c.AddTeamMember(team.Id, user.Id); addErr != nil {
+				printer.PrintError(fmt.Sprintf("unable to add user %q to team %q: %s", user.Username, team.Name, addErr))
+				failed++
+				continue
+			}
+			member.Action = channelMoveMemberAdded
+		case force:
+			member.Action = channelMoveMemberRemoved
+		}
+		printer.PrintT(`User {{.Username}} of channel {{.Channel}} is not a member of team {{.Team}}`+
+			`{{if eq .Action "added"}}, added to the team{{else if eq .Action "removed"}}, removed from the channel{{else}} and would lose access to the channel{{end}}`, member)
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("unable to add %d members of the channel to team %q", failed, team.Name)
+	case len(missing) > 0 && !addMissingMembers && !force:
+		return fmt.Errorf("%d members of the channel are not members of team %q, use --add-missing-members to add them to the team or --force to remove them from the channel", len(missing), team.Name)
+	}
+	return nil
+}
+
 func moveChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
+	addMissingMembers, _ := cmd.Flags().GetBool("add-missing-members")
+	if force && addMissingMembers {
+		return errors.New("--force and --add-missing-members can't be used together")
+	}
 
 	team := getTeamFromTeamArg(c, args[0])
 	if team == nil {
@@ -529,6 +718,11 @@ func moveChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		if err := prepareChannelMove(c, channel, team, force, addMissingMembers); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to move channel %q: %s", channel.Name, err))
+			continue
+		}
+
 		newChannel, _, err := c.MoveChannel(channel.Id, team.Id, force)
 		if err != nil {
 			printer.PrintError(fmt.Sprintf("unable to move channel %q: %s", channel.Name, err))
@@ -591,9 +785,18 @@ func getPrivateChannels(c client.Client, teamID string) ([]*model.Channel, error
 }
 
 func deleteChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	exportDir, err := getExportFirstDir(cmd)
+	if err != nil {
+		return err
+	}
+
 	confirmFlag, _ := cmd.Flags().GetBool("confirm")
 	if !confirmFlag {
-		if err := getConfirmation("Are you sure you want to delete the channels specified? All data will be permanently deleted?", true); err != nil {
+		question := "Are you sure you want to delete the channels specified? All data will be permanently deleted?"
+		if exportDir != "" {
+			question = "Are you sure you want to delete the channels specified? All data will be permanently deleted, and the exported archives can't be used to restore the channels, as they don't contain the attachments and can't be imported back?"
+		}
+		if err := getConfirmation(question, true); err != nil {
 			return err
 		}
 	}
@@ -606,6 +809,14 @@ func deleteChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) erro
 			result = multierror.Append(result, fmt.Errorf("unable to find channel '%s'", args[i]))
 			continue
 		}
+		if exportDir != "" {
+			exported, err := exportChannelArchive(c, channel, exportDir)
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("unable to export channel '%q', it won't be deleted. error: %w", channel.Name, err))
+				continue
+			}
+			printer.PrintT("Exported channel '{{.Name}}' to {{.Path}}", exported)
+		}
 		if _, err := c.PermanentDeleteChannel(channel.Id); err != nil {
 			result = multierror.Append(result, fmt.Errorf("unable to delete channel '%q' error: %w", channel.Name, err))
 		} else {
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+)
+
+type channelExportResult struct {
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+}
+
+// addExportFirstFlags adds the flags to export the channels before a
+// destructive command removes them
+func addExportFirstFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("export-first", false, "Export the channel and its posts to a local zip archive before removing it, skipping the channels that fail to export. The archive has no attachments and can't be imported back")
+	cmd.Flags().String("export-dir", ".", "Directory to write the archives of --export-first to")
+}
+
+// getExportFirstDir returns the directory to export the channels to,
+// or an empty string if the channels shouldn't be exported
+func getExportFirstDir(cmd *cobra.Command) (string, error) {
+	exportFirst, _ := cmd.Flags().GetBool("export-first")
+	if !exportFirst {
+		return "", nil
+	}
+
+	dir, _ := cmd.Flags().GetString("export-dir")
+	if dir == "" {
+		dir = "."
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("export directory %q doesn't exist", dir)
+	}
+	return dir, nil
+}
+
+// exportChannelArchive writes the channel, and all its posts oldest
+// first as JSON lines, to a zip archive in the given directory. The
+// attachments aren't downloaded, but their ids are kept in the posts.
+// The archive is a record of the channel that isn't in the bulk import
+// format, so the channel can't be restored from it
+func exportChannelArchive(c client.Client, channel *model.Channel, dir string) (*channelExportResult, error) {
+	posts, err := getFilteredPosts(c, channel.Id, &postListFilter{}, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.zip", channel.Name, channel.Id))
+	if err := writeChannelArchive(path, channel, posts); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	return &channelExportResult{ChannelID: channel.Id, Name: channel.Name, Path: path}, nil
+}
+
+func writeChannelArchive(path string, channel *model.Channel, posts []*model.Post) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the archive")
+	}
+	defer f.Close()
+
+	zipWriter := zip.NewWriter(f)
+
+	w, err := zipWriter.Create("channel.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to write the channel")
+	}
+	if err := json.NewEncoder(w).Encode(channel); err != nil {
+		return errors.Wrap(err, "failed to write the channel")
+	}
+
+	w, err = zipWriter.Create("posts.jsonl")
+	if err != nil {
+		return errors.Wrap(err, "failed to write the posts")
+	}
+	encoder := json.NewEncoder(w)
+	for i := len(posts) - 1; i >= 0; i-- {
+		if err := encoder.Encode(posts[i]); err != nil {
+			return errors.Wrap(err, "failed to write the posts")
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the archive")
+	}
+	return f.Close()
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestArchiveChannelsCmdExportFirst() {
+	newCmd := func(dir string) *cobra.Command {
+		cmd := &cobra.Command{}
+		addExportFirstFlags(cmd)
+		_ = cmd.Flags().Set("export-first", "true")
+		_ = cmd.Flags().Set("export-dir", dir)
+		return cmd
+	}
+
+	channel := &model.Channel{Id: "channel-id", Name: "town-square"}
+	postList := &model.PostList{
+		Order: []string{"new", "old"},
+		Posts: map[string]*model.Post{
+			"new": {Id: "new", ChannelId: channel.Id, Message: "second", CreateAt: 2},
+			"old": {Id: "old", ChannelId: channel.Id, Message: "first", CreateAt: 1},
+		},
+	}
+
+	s.Run("Should export the channel before archiving it", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteChannel(channel.Id).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := archiveChannelsCmdF(s.client, newCmd(tmp), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		path := filepath.Join(tmp, "town-square-channel-id.zip")
+		s.Require().Equal(&channelExportResult{ChannelID: channel.Id, Name: channel.Name, Path: path}, printer.GetLines()[0])
+
+		archive, err := zip.OpenReader(path)
+		s.Require().NoError(err)
+		defer archive.Close()
+		s.Require().Len(archive.File, 2)
+		s.Require().Equal("channel.json", archive.File[0].Name)
+		s.Require().Equal("posts.jsonl", archive.File[1].Name)
+
+		r, err := archive.File[1].Open()
+		s.Require().NoError(err)
+		defer r.Close()
+		b, err := ioutil.ReadAll(r)
+		s.Require().NoError(err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		s.Require().Len(lines, 2)
+		s.Require().Contains(lines[0], `"message":"first"`)
+		s.Require().Contains(lines[1], `"message":"second"`)
+	})
+
+	s.Run("Should not archive the channels that fail to export", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := archiveChannelsCmdF(s.client, newCmd(tmp), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal("Unable to export channel 'town-square', it won't be archived. error: failed to fetch posts: mock error", printer.GetErrorLines()[0])
+	})
+
+	s.Run("Should fail if the export directory doesn't exist", func() {
+		printer.Clean()
+
+		err := archiveChannelsCmdF(s.client, newCmd("/nonexistent/dir"), []string{channel.Id})
+		s.Require().EqualError(err, `export directory "/nonexistent/dir" doesn't exist`)
+	})
+}
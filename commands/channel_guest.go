@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ChannelGuestCmd = &cobra.Command{
+	Use:   "guest",
+	Short: "Management of guest access to channels",
+}
+
+var ChannelGuestAuditCmd = &cobra.Command{
+	Use:   "audit [teams]",
+	Short: "Report the guests of every channel",
+	Long: `List the guest accounts of the given teams, or of all the teams if none is provided, together with the channels they have access to and their last activity.
+Guests that don't belong to any channel of a team are listed with an empty channel. The server doesn't keep track of who invited a guest, so the inviter is not part of the report.`,
+	Example: `  channel guest audit
+  channel guest audit myteam --csv --columns team,channel,username,email,last_activity_at > guests.csv`,
+	RunE: withClient(channelGuestAuditCmdF),
+}
+
+func init() {
+	ChannelGuestCmd.AddCommand(ChannelGuestAuditCmd)
+	ChannelCmd.AddCommand(ChannelGuestCmd)
+}
+
+type guestAccessRow struct {
+	Team           string `json:"team"`
+	Channel        string `json:"channel"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	LastActivityAt int64  `json:"last_activity_at"`
+}
+
+func channelGuestAuditCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	teams, err := getTeamsOrAllTeams(c, args)
+	if err != nil {
+		return err
+	}
+
+	rows := []*guestAccessRow{}
+	for _, team := range teams {
+		teamRows, err := getTeamGuestAccess(c, team)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to audit the guests of team %q: %s", team.Name, err))
+			continue
+		}
+		rows = append(rows, teamRows...)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Team != rows[j].Team {
+			return rows[i].Team < rows[j].Team
+		}
+		if rows[i].Channel != rows[j].Channel {
+			return rows[i].Channel < rows[j].Channel
+		}
+		return rows[i].Username < rows[j].Username
+	})
+
+	for _, row := range rows {
+		printer.PrintT("{{.Team}}{{if .Channel}}:{{.Channel}}{{end}}: {{.Username}} ({{.Email}}), {{if .LastActivityAt}}last activity at {{timestamp .LastActivityAt}}{{else}}no activity{{end}}", row)
+	}
+
+	return nil
+}
+
+// getTeamsOrAllTeams returns the teams of the arguments, or all the
+// teams of the server if there are none
+func getTeamsOrAllTeams(c client.Client, teamArgs []string) ([]*model.Team, error) {
+	if len(teamArgs) > 0 {
+		teams := []*model.Team{}
+		for _, teamArg := range teamArgs {
+			team := getTeamFromTeamArg(c, teamArg)
+			if team == nil {
+				return nil, fmt.Errorf("unable to find team %q", teamArg)
+			}
+			teams = append(teams, team)
+		}
+		return teams, nil
+	}
+
+	teams, err := getAllTeams(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the teams")
+	}
+	return teams, nil
+}
+
+func getTeamGuestAccess(c client.Client, team *model.Team) ([]*guestAccessRow, error) {
+	guests := []*model.User{}
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsersInTeam(team.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if user.IsGuest() && user.DeleteAt == 0 {
+				guests = append(guests, user)
+			}
+		}
+
+		if len(users) < APILimitMaximum {
+			break
+		}
+	}
+
+	if len(guests) == 0 {
+		return nil, nil
+	}
+
+	guestIDs := make([]string, 0, len(guests))
+	for _, guest := range guests {
+		guestIDs = append(guestIDs, guest.Id)
+	}
+
+	statuses, _, err := c.GetUsersStatusesByIds(guestIDs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the guests activity: %w", err)
+	}
+	lastActivity := map[string]int64{}
+	for _, status := range statuses {
+		lastActivity[status.UserId] = status.LastActivityAt
+	}
+
+	rows := []*guestAccessRow{}
+	for _, guest := range guests {
+		channels, _, err := c.GetChannelsForTeamForUser(team.Id, guest.Id, false, "")
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to get the channels of guest %q: %s", guest.Username, err))
+			continue
+		}
+
+		newRow := func(channelName string) *guestAccessRow {
+			return &guestAccessRow{
+				Team:           team.Name,
+				Channel:        channelName,
+				UserID:         guest.Id,
+				Username:       guest.Username,
+				Email:          guest.Email,
+				LastActivityAt: lastActivity[guest.Id],
+			}
+		}
+
+		guestRows := 0
+		for _, channel := range channels {
+			if channel.Type != model.ChannelTypeOpen && channel.Type != model.ChannelTypePrivate {
+				continue
+			}
+			rows = append(rows, newRow(channel.Name))
+			guestRows++
+		}
+
+		if guestRows == 0 {
+			rows = append(rows, newRow(""))
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelGuestAuditCmd() {
+	team := &model.Team{Id: model.NewId(), Name: "team"}
+	guest1 := &model.User{Id: model.NewId(), Username: "guest1", Email: "guest1@example.com", Roles: model.SystemGuestRoleId}
+	guest2 := &model.User{Id: model.NewId(), Username: "guest2", Email: "guest2@example.com", Roles: model.SystemGuestRoleId}
+	member := &model.User{Id: model.NewId(), Username: "member", Roles: model.SystemUserRoleId}
+
+	expectTeamGuests := func() {
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{guest1, member, guest2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersStatusesByIds([]string{guest1.Id, guest2.Id}).
+			Return([]*model.Status{{UserId: guest1.Id, LastActivityAt: 1000}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForTeamForUser(team.Id, guest1.Id, false, "").
+			Return([]*model.Channel{
+				{Name: "town-square", Type: model.ChannelTypeOpen},
+				{Name: "private", Type: model.ChannelTypePrivate},
+				{Name: "dm", Type: model.ChannelTypeDirect},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForTeamForUser(team.Id, guest2.Id, false, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should list the guests of all the teams", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{team}, &model.Response{}, nil).
+			Times(1)
+
+		expectTeamGuests()
+
+		err := channelGuestAuditCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&guestAccessRow{Team: team.Name, UserID: guest2.Id, Username: guest2.Username, Email: guest2.Email},
+			&guestAccessRow{Team: team.Name, Channel: "private", UserID: guest1.Id, Username: guest1.Username, Email: guest1.Email, LastActivityAt: 1000},
+			&guestAccessRow{Team: team.Name, Channel: "town-square", UserID: guest1.Id, Username: guest1.Username, Email: guest1.Email, LastActivityAt: 1000},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should report the teams that cannot be audited", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := channelGuestAuditCmdF(s.client, &cobra.Command{}, []string{team.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal([]interface{}{`unable to audit the guests of team "team": mock error`}, printer.GetErrorLines())
+	})
+}
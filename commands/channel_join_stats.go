@@ -0,0 +1,192 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelJoinStatsCmd = &cobra.Command{
+	Use:   "join-stats [channels]",
+	Short: "Summarize the joins and leaves of channels",
+	Long: `Count the users that joined or were added to channels, and the ones that left or were removed from them, during a time range, based on the system messages of the channels.
+The members are counted at the end of the range, and the churn of a channel is the percentage of its members at the start of the range that left it. The channels are sorted by churn, and the ones with a churn of at least --churn-threshold are marked. With --team, all the public channels of the team are summarized.
+With --csv-file, the summary is also written to a CSV file.`,
+	Example: `  channel join-stats myteam:town-square myteam:off-topic --since 2026-01-01T00:00:00+00:00
+  channel join-stats --team myteam --since 2026-01-01T00:00:00+00:00 --until 2026-04-01T00:00:00+00:00 --csv-file join-stats.csv`,
+	RunE: withClient(channelJoinStatsCmdF),
+}
+
+func init() {
+	ChannelJoinStatsCmd.Flags().String("team", "", "Summarize all the public channels of this team")
+	ChannelJoinStatsCmd.Flags().String("since", "", "Start of the time range, in ISO 8601 format")
+	_ = ChannelJoinStatsCmd.MarkFlagRequired("since")
+	ChannelJoinStatsCmd.Flags().String("until", "", "End of the time range, in ISO 8601 format. Defaults to now")
+	ChannelJoinStatsCmd.Flags().Float64("churn-threshold", 10, "Churn percentage from which a channel is marked as churn-heavy")
+	ChannelJoinStatsCmd.Flags().String("csv-file", "", "Path of the CSV file to write the summary to")
+
+	ChannelCmd.AddCommand(ChannelJoinStatsCmd)
+}
+
+type channelJoinStats struct {
+	Channel    string  `json:"channel"`
+	ChannelID  string  `json:"channel_id"`
+	Joins      int     `json:"joins"`
+	Leaves     int     `json:"leaves"`
+	Members    int64   `json:"members"`
+	Churn      float64 `json:"churn"`
+	ChurnHeavy bool    `json:"churn_heavy"`
+}
+
+// getChannelJoinStats counts the join and leave system messages of a
+// channel in the range of the filter. The posts after the range are
+// fetched too, to work out the members at its start from the current
+// ones
+func getChannelJoinStats(c client.Client, channel *model.Channel, filter *postListFilter) (*channelJoinStats, error) {
+	posts, err := getFilteredPosts(c, channel.Id, &postListFilter{since: filter.since}, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &channelJoinStats{Channel: channel.Name, ChannelID: channel.Id}
+	joinsAfter, leavesAfter := 0, 0
+	for _, post := range posts {
+		after := filter.until > 0 && post.CreateAt > filter.until
+		switch post.Type {
+		case model.PostTypeJoinChannel, model.PostTypeAddToChannel:
+			if after {
+				joinsAfter++
+			} else {
+				stats.Joins++
+			}
+		case model.PostTypeLeaveChannel, model.PostTypeRemoveFromChannel:
+			if after {
+				leavesAfter++
+			} else {
+				stats.Leaves++
+			}
+		}
+	}
+
+	channelStats, _, err := c.GetChannelStats(channel.Id, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the channel stats")
+	}
+	stats.Members = channelStats.MemberCount - int64(joinsAfter) + int64(leavesAfter)
+
+	if start := stats.Members - int64(stats.Joins) + int64(stats.Leaves); start > 0 {
+		stats.Churn = float64(stats.Leaves) * 100 / float64(start)
+	}
+	return stats, nil
+}
+
+func writeChannelJoinStatsCSV(path string, stats []*channelJoinStats) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the CSV file")
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"channel", "channel_id", "joins", "leaves", "members", "churn", "churn_heavy"}); err != nil {
+		return errors.Wrap(err, "failed to write the CSV file")
+	}
+	for _, s := range stats {
+		record := []string{
+			s.Channel,
+			s.ChannelID,
+			strconv.Itoa(s.Joins),
+			strconv.Itoa(s.Leaves),
+			strconv.FormatInt(s.Members, 10),
+			strconv.FormatFloat(s.Churn, 'f', 1, 64),
+			strconv.FormatBool(s.ChurnHeavy),
+		}
+		if err := w.Write(record); err != nil {
+			return errors.Wrap(err, "failed to write the CSV file")
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return errors.Wrap(err, "failed to write the CSV file")
+	}
+	return f.Close()
+}
+
+func channelJoinStatsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	teamArg, _ := cmd.Flags().GetString("team")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	threshold, _ := cmd.Flags().GetFloat64("churn-threshold")
+	csvPath, _ := cmd.Flags().GetString("csv-file")
+
+	if (teamArg == "") == (len(args) == 0) {
+		return errors.New("either channels or --team must be given")
+	}
+
+	filter := &postListFilter{}
+	var err error
+	if filter.since, err = parsePostListTime("since", since); err != nil {
+		return err
+	}
+	if filter.since == 0 {
+		return errors.New("the --since flag is required")
+	}
+	if filter.until, err = parsePostListTime("until", until); err != nil {
+		return err
+	}
+
+	var channels []*model.Channel
+	if teamArg != "" {
+		team := getTeamFromTeamArg(c, teamArg)
+		if team == nil {
+			return errors.Errorf("unable to find team %q", teamArg)
+		}
+		if channels, err = getAllPublicChannelsForTeam(c, team.Id); err != nil {
+			return errors.Wrapf(err, "failed to fetch the channels of team %q", teamArg)
+		}
+	} else {
+		for i, channel := range getChannelsFromChannelArgs(c, args) {
+			if channel == nil {
+				printer.PrintError("Unable to find channel '" + args[i] + "'")
+				continue
+			}
+			channels = append(channels, channel)
+		}
+	}
+
+	stats := []*channelJoinStats{}
+	for _, channel := range channels {
+		channelStats, err := getChannelJoinStats(c, channel, filter)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to summarize channel %q: %s", channel.Name, err))
+			continue
+		}
+		channelStats.ChurnHeavy = channelStats.Churn >= threshold && channelStats.Leaves > 0
+		stats = append(stats, channelStats)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Churn > stats[j].Churn
+	})
+
+	for _, s := range stats {
+		printer.PrintT(`{{.Channel}}: {{.Joins}} joins, {{.Leaves}} leaves, {{.Members}} members, {{printf "%.1f" .Churn}}% churn{{if .ChurnHeavy}} (churn-heavy){{end}}`, s)
+	}
+
+	if csvPath != "" {
+		return writeChannelJoinStatsCSV(csvPath, stats)
+	}
+	return nil
+}
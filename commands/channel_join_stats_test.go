@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelJoinStatsCmd() {
+	newCmd := func(team, until, csvPath string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", team, "")
+		cmd.Flags().String("since", "2026-01-01T00:00:00+00:00", "")
+		cmd.Flags().String("until", until, "")
+		cmd.Flags().Float64("churn-threshold", 10, "")
+		cmd.Flags().String("csv-file", csvPath, "")
+		return cmd
+	}
+
+	at := func(day int) int64 {
+		return model.GetMillisForTime(time.Date(2026, time.January, day, 12, 0, 0, 0, time.UTC))
+	}
+	postList := func(posts ...*model.Post) *model.PostList {
+		list := model.NewPostList()
+		for _, post := range posts {
+			list.AddPost(post)
+			list.AddOrder(post.Id)
+		}
+		return list
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	quiet := &model.Channel{Id: "quiet-id", Name: "quiet", TeamId: team.Id}
+	busy := &model.Channel{Id: "busy-id", Name: "busy", TeamId: team.Id}
+
+	s.Run("Should summarize the public channels of a team sorted by churn", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		csvPath := filepath.Join(tmp, "join-stats.csv")
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, 200, "").
+			Return([]*model.Channel{quiet, busy}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, 200, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(quiet.Id, 0, APILimitMaximum, "", false).
+			Return(postList(
+				&model.Post{Id: "q2", Type: model.PostTypeJoinChannel, CreateAt: at(3)},
+				&model.Post{Id: "q1", Message: "hello", CreateAt: at(2)},
+			), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelStats(quiet.Id, "").
+			Return(&model.ChannelStats{ChannelId: quiet.Id, MemberCount: 10}, &model.Response{}, nil).
+			Times(1)
+
+		// the join after the end of the range is left out of the counts
+		// and of the members
+		s.client.
+			EXPECT().
+			GetPostsForChannel(busy.Id, 0, APILimitMaximum, "", false).
+			Return(postList(
+				&model.Post{Id: "b5", Type: model.PostTypeJoinChannel, CreateAt: at(20)},
+				&model.Post{Id: "b4", Type: model.PostTypeRemoveFromChannel, CreateAt: at(5)},
+				&model.Post{Id: "b3", Type: model.PostTypeLeaveChannel, CreateAt: at(4)},
+				&model.Post{Id: "b2", Type: model.PostTypeAddToChannel, CreateAt: at(3)},
+				&model.Post{Id: "b1", Type: model.PostTypeLeaveChannel, CreateAt: at(2)},
+			), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelStats(busy.Id, "").
+			Return(&model.ChannelStats{ChannelId: busy.Id, MemberCount: 9}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelJoinStatsCmdF(s.client, newCmd(team.Name, "2026-01-10T00:00:00+00:00", csvPath), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&channelJoinStats{Channel: "busy", ChannelID: busy.Id, Joins: 1, Leaves: 3, Members: 8, Churn: 30, ChurnHeavy: true},
+			&channelJoinStats{Channel: "quiet", ChannelID: quiet.Id, Joins: 1, Members: 10},
+		}, printer.GetLines())
+
+		b, err := ioutil.ReadFile(csvPath)
+		s.Require().NoError(err)
+		s.Require().Equal("channel,channel_id,joins,leaves,members,churn,churn_heavy\n"+
+			"busy,busy-id,1,3,8,30.0,true\n"+
+			"quiet,quiet-id,1,0,10,0.0,false\n", string(b))
+	})
+
+	s.Run("Should fail without channels or a team", func() {
+		err := channelJoinStatsCmdF(s.client, newCmd("", "", ""), []string{})
+		s.Require().EqualError(err, "either channels or --team must be given")
+	})
+
+	s.Run("Should fail with an invalid until time", func() {
+		err := channelJoinStatsCmdF(s.client, newCmd(team.Name, "yesterday", ""), []string{})
+		s.Require().EqualError(err, "invalid until time 'yesterday'")
+	})
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ChannelLeaveCmd = &cobra.Command{
+	Use:   "leave [channel-patterns]",
+	Short: "Remove a user from the channels of a team",
+	Long: `Remove the user given with --as from the channels of a team whose name matches any of the patterns, or from all of the channels of the team if no patterns are given, which is confirmed before removing the user unless --confirm is set.
+Patterns use shell glob syntax, e.g. "project-*". Channels matching any of the --except patterns are kept, and the default channel of the team is always kept, as users cannot leave it without leaving the team.
+With --plan, the channels the user would be removed from are printed as a plan that can be reviewed and then applied with --apply-plan, without the other flags.`,
+	Example: `  # remove a contractor from all the channels of a team but two
+  channel leave --as contractor@example.com --team myteam --except town-square,off-topic --confirm
+
+  # remove a user from the project channels of a team
+  channel leave "project-*" --as john.doe --team myteam
+
+  # review the changes before applying them
+  channel leave --as john.doe --team myteam --plan > plan.json
+  channel leave --apply-plan plan.json`,
+	RunE: withClient(withPlanActions(channelLeavePlanResolver, applyChannelLeavePlan, channelLeaveCmdF)),
+}
+
+func init() {
+	ChannelLeaveCmd.Flags().String("as", "", "User to remove from the channels")
+	ChannelLeaveCmd.Flags().String("team", "", "Team of the channels")
+	ChannelLeaveCmd.Flags().StringSlice("except", []string{}, "Patterns of the channels the user should not be removed from")
+	ChannelLeaveCmd.Flags().Bool("confirm", false, "Confirm you really want to remove the user from all the channels of the team when no patterns are given")
+	addPlanFlags(ChannelLeaveCmd)
+
+	ChannelCmd.AddCommand(ChannelLeaveCmd)
+}
+
+type channelLeaveResult struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// matchesAnyPattern returns true if the name matches any of the glob
+// patterns. The patterns are validated beforehand, so errors can't
+// happen at this point
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// planActionRemoveChannelMember removes the user of the ID from the
+// target channel
+const planActionRemoveChannelMember = "remove_channel_member"
+
+type channelLeaveSummary struct {
+	Removed int `json:"removed"`
+	Kept    int `json:"kept"`
+	Failed  int `json:"failed"`
+}
+
+// channelLeaveTarget is a channel of the team matching the patterns,
+// which the user is removed from unless it's kept
+type channelLeaveTarget struct {
+	channel *model.Channel
+	keep    bool
+}
+
+// getChannelLeaveTargets returns the user of the --as flag and the
+// channels of the team the user belongs to that match the patterns
+func getChannelLeaveTargets(c client.Client, cmd *cobra.Command, args []string) (*model.User, []channelLeaveTarget, error) {
+	userArg, _ := cmd.Flags().GetString("as")
+	teamArg, _ := cmd.Flags().GetString("team")
+	except, _ := cmd.Flags().GetStringSlice("except")
+	if userArg == "" || teamArg == "" {
+		return nil, nil, errors.New("the --as and --team flags are required")
+	}
+
+	patterns := args
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	for _, patternList := range [][]string{patterns, except} {
+		for _, pattern := range patternList {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	user, err := getUserFromArg(c, userArg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	team := getTeamFromTeamArg(c, teamArg)
+	if team == nil {
+		return nil, nil, fmt.Errorf("unable to find team %q", teamArg)
+	}
+
+	channels, _, err := c.GetChannelsForTeamForUser(team.Id, user.Id, false, "")
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to get the channels of user %q", user.Username)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Name < channels[j].Name })
+
+	targets := []channelLeaveTarget{}
+	for _, channel := range channels {
+		// direct and group messages are returned along with the
+		// channels of the team
+		if channel.TeamId != team.Id || !matchesAnyPattern(channel.Name, patterns) {
+			continue
+		}
+		keep := channel.Name == model.DefaultChannelName || matchesAnyPattern(channel.Name, except)
+		targets = append(targets, channelLeaveTarget{channel: channel, keep: keep})
+	}
+	return user, targets, nil
+}
+
+func channelLeavePlanResolver(c client.Client, cmd *cobra.Command, args []string) ([]planAction, error) {
+	user, targets, err := getChannelLeaveTargets(c, cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, target := range targets {
+		if !target.keep {
+			actions = append(actions, planAction{
+				Action:     planActionRemoveChannelMember,
+				ID:         user.Id,
+				Name:       user.Username,
+				TargetID:   target.channel.Id,
+				TargetName: target.channel.Name,
+			})
+		}
+	}
+	return actions, nil
+}
+
+// leaveChannel removes the user from the channel of the result,
+// updating the result and the summary
+func leaveChannel(c client.Client, userID string, result *channelLeaveResult, summary *channelLeaveSummary) {
+	if _, err := c.RemoveUserFromChannel(result.ID, userID); err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		summary.Failed++
+		return
+	}
+	result.Status = "removed"
+	summary.Removed++
+}
+
+func printChannelLeaveSummary(username string, summary *channelLeaveSummary) error {
+	printer.PrintT("{{.Removed}} channels left, {{.Kept}} kept, {{.Failed}} failed", summary)
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("unable to remove user %q from %d channels", username, summary.Failed)
+	}
+	return nil
+}
+
+func applyChannelLeavePlan(c client.Client, _ *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, planActionRemoveChannelMember); err != nil {
+		return err
+	}
+
+	summary := &channelLeaveSummary{}
+	for _, action := range actions {
+		result := channelLeaveResult{ID: action.TargetID, Channel: action.TargetName}
+		leaveChannel(c, action.ID, &result, summary)
+		printer.PrintT("{{.Channel}}: {{.Status}}{{if .Error}} ({{.Error}}){{end}}", result)
+	}
+	return printChannelLeaveSummary(actions[0].Name, summary)
+}
+
+func channelLeaveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	user, targets, err := getChannelLeaveTargets(c, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// without patterns the user is removed from all the channels of
+	// the team, so a mistyped command is confirmed first
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if len(args) == 0 && !confirmFlag {
+		removed := 0
+		for _, target := range targets {
+			if !target.keep {
+				removed++
+			}
+		}
+		question := fmt.Sprintf("Are you sure you want to remove user %q from all the %d channels of the team?", user.Username, removed)
+		if err := getConfirmation(question, false); err != nil {
+			return err
+		}
+	}
+
+	summary := &channelLeaveSummary{}
+	for _, target := range targets {
+		result := channelLeaveResult{ID: target.channel.Id, Channel: target.channel.Name}
+		if target.keep {
+			result.Status = "kept"
+			summary.Kept++
+		} else {
+			leaveChannel(c, user.Id, &result, summary)
+		}
+
+		printer.PrintT("{{.Channel}}: {{.Status}}{{if .Error}} ({{.Error}}){{end}}", result)
+	}
+
+	return printChannelLeaveSummary(user.Username, summary)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelLeaveCmd() {
+	user := &model.User{Id: "user-id", Username: "contractor", Email: "contractor@example.com"}
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	channels := []*model.Channel{
+		{Id: "channel-1", Name: "project-b", TeamId: team.Id},
+		{Id: "channel-2", Name: model.DefaultChannelName, TeamId: team.Id},
+		{Id: "channel-3", Name: "project-a", TeamId: team.Id},
+		{Id: "channel-4", Name: "off-topic", TeamId: team.Id},
+		{Id: "dm", Name: "user-id__other-id", Type: model.ChannelTypeDirect},
+	}
+
+	newCmd := func(except string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("as", user.Email, "")
+		cmd.Flags().String("team", team.Id, "")
+		cmd.Flags().StringSlice("except", []string{}, "")
+		cmd.Flags().Bool("confirm", false, "")
+		if except != "" {
+			_ = cmd.Flags().Set("except", except)
+		}
+		return cmd
+	}
+
+	expectUserAndChannels := func() {
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForTeamForUser(team.Id, user.Id, false, "").
+			Return(channels, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should remove the user from all the channels but the allowlist", func() {
+		printer.Clean()
+		expectUserAndChannels()
+
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-3", user.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-1", user.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd("off-*")
+		_ = cmd.Flags().Set("confirm", "true")
+
+		err := channelLeaveCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 5)
+		s.Require().Equal(channelLeaveResult{ID: "channel-4", Channel: "off-topic", Status: "kept"}, printer.GetLines()[0])
+		s.Require().Equal(channelLeaveResult{ID: "channel-3", Channel: "project-a", Status: "removed"}, printer.GetLines()[1])
+		s.Require().Equal(channelLeaveResult{ID: "channel-1", Channel: "project-b", Status: "removed"}, printer.GetLines()[2])
+		s.Require().Equal(channelLeaveResult{ID: "channel-2", Channel: model.DefaultChannelName, Status: "kept"}, printer.GetLines()[3])
+	})
+
+	s.Run("Should require a confirmation to remove the user from all the channels", func() {
+		printer.Clean()
+		expectUserAndChannels()
+
+		err := channelLeaveCmdF(s.client, newCmd(""), []string{})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "could not proceed, either enable --confirm flag or use an interactive shell to complete operation")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should only remove the user from the matching channels", func() {
+		printer.Clean()
+		expectUserAndChannels()
+
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-3", user.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-1", user.Id).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := channelLeaveCmdF(s.client, newCmd(""), []string{"project-*"})
+		s.Require().EqualError(err, `unable to remove user "contractor" from 1 channels`)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal(channelLeaveResult{ID: "channel-1", Channel: "project-b", Status: "failed", Error: "mock error"}, printer.GetLines()[1])
+	})
+
+	s.Run("Should fail with an invalid pattern", func() {
+		printer.Clean()
+
+		err := channelLeaveCmdF(s.client, newCmd(""), []string{"project-["})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), `invalid pattern "project-["`)
+	})
+
+	s.Run("Should plan the channels to leave and apply the plan", func() {
+		printer.Clean()
+		expectUserAndChannels()
+
+		cmd := newCmd("off-*")
+		cmd.Use = "leave"
+		addPlanFlags(cmd)
+		_ = cmd.Flags().Set("plan", "true")
+
+		err := withPlanActions(channelLeavePlanResolver, applyChannelLeavePlan, channelLeaveCmdF)(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		plan := printer.GetLines()[0].(*commandPlan)
+		s.Require().Equal([]planAction{
+			{Action: planActionRemoveChannelMember, ID: user.Id, Name: user.Username, TargetID: "channel-3", TargetName: "project-a"},
+			{Action: planActionRemoveChannelMember, ID: user.Id, Name: user.Username, TargetID: "channel-1", TargetName: "project-b"},
+		}, plan.Actions)
+
+		printer.Clean()
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-3", user.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel("channel-1", user.Id).
+			Return(nil, errors.New("mock error")).
+			Times(1)
+
+		err = applyChannelLeavePlan(s.client, cmd, plan.Actions)
+		s.Require().EqualError(err, `unable to remove user "contractor" from 1 channels`)
+		s.Require().Equal(channelLeaveResult{ID: "channel-3", Channel: "project-a", Status: "removed"}, printer.GetLines()[0])
+		s.Require().Equal(channelLeaveResult{ID: "channel-1", Channel: "project-b", Status: "failed", Error: "mock error"}, printer.GetLines()[1])
+	})
+
+	s.Run("Should refuse the plans with actions of other commands", func() {
+		err := applyChannelLeavePlan(s.client, newCmd(""), []planAction{{Action: "delete_user", ID: user.Id}})
+		s.Require().EqualError(err, `unsupported plan action "delete_user"`)
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelUsersExportHistoryCmd = &cobra.Command{
+	Use:   "export-history [channel]",
+	Short: "Export the membership history of a channel",
+	Long: `Reconstruct the membership timeline of a channel from its join, leave, add and remove system messages, oldest first.
+The timeline is only as complete as the system messages of the channel: memberships changed while the messages were disabled, or whose messages were removed by a data retention policy, are missing.`,
+	Example: `  channel users export-history myteam:mychannel
+  channel members export-history myteam:mychannel --csv --columns create_at,event,user_id,username,actor_id,actor_username > membership.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(channelUsersExportHistoryCmdF),
+}
+
+func init() {
+	ChannelUsersCmd.AddCommand(ChannelUsersExportHistoryCmd)
+}
+
+type membershipEvent struct {
+	ID            string `json:"id"`
+	CreateAt      int64  `json:"create_at"`
+	Event         string `json:"event"`
+	UserID        string `json:"user_id"`
+	Username      string `json:"username"`
+	ActorID       string `json:"actor_id,omitempty"`
+	ActorUsername string `json:"actor_username,omitempty"`
+}
+
+func getPostStringProp(post *model.Post, name string) string {
+	value, _ := post.GetProp(name).(string)
+	return value
+}
+
+// postToMembershipEvent builds the membership event of a system
+// message, returning nil if the post is not a membership one
+func postToMembershipEvent(post *model.Post) *membershipEvent {
+	event := &membershipEvent{ID: post.Id, CreateAt: post.CreateAt}
+	switch post.Type {
+	case model.PostTypeJoinChannel, model.PostTypeLeaveChannel:
+		event.Event = "joined"
+		if post.Type == model.PostTypeLeaveChannel {
+			event.Event = "left"
+		}
+		event.UserID = post.UserId
+		event.Username = getPostStringProp(post, "username")
+	case model.PostTypeAddToChannel, model.PostTypeAddGuestToChannel:
+		event.Event = "added"
+		event.UserID = getPostStringProp(post, model.PostPropsAddedUserId)
+		event.Username = getPostStringProp(post, "addedUsername")
+		event.ActorID = post.UserId
+		event.ActorUsername = getPostStringProp(post, "username")
+	case model.PostTypeRemoveFromChannel:
+		event.Event = "removed"
+		event.UserID = getPostStringProp(post, "removedUserId")
+		event.Username = getPostStringProp(post, "removedUsername")
+		event.ActorID = post.UserId
+	default:
+		return nil
+	}
+
+	return event
+}
+
+func getMembershipEvents(c client.Client, channelID string) ([]*membershipEvent, error) {
+	events := []*membershipEvent{}
+	for page := 0; ; page++ {
+		postList, _, err := c.GetPostsForChannel(channelID, page, APILimitMaximum, "", false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch posts")
+		}
+
+		posts := postList.ToSlice()
+		for _, post := range posts {
+			if event := postToMembershipEvent(post); event != nil {
+				events = append(events, event)
+			}
+		}
+
+		if len(posts) < APILimitMaximum {
+			break
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreateAt < events[j].CreateAt })
+	return events, nil
+}
+
+func channelUsersExportHistoryCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	events, err := getMembershipEvents(c, channel.Id)
+	if err != nil {
+		return err
+	}
+
+	// the messages of the removals don't contain the username of the
+	// user that removed the member
+	usernames := map[string]string{}
+	for _, event := range events {
+		if event.Username != "" {
+			usernames[event.UserID] = event.Username
+		}
+		if event.ActorUsername != "" {
+			usernames[event.ActorID] = event.ActorUsername
+		}
+	}
+	for _, event := range events {
+		if event.ActorID == "" || event.ActorUsername != "" {
+			continue
+		}
+		username, ok := usernames[event.ActorID]
+		if !ok {
+			if user, _, err := c.GetUser(event.ActorID, ""); err == nil {
+				username = user.Username
+			}
+			usernames[event.ActorID] = username
+		}
+		event.ActorUsername = username
+	}
+
+	for _, event := range events {
+		printer.PrintT("{{timestamp .CreateAt}} {{.Event}} {{.Username}}{{if .ActorUsername}} by {{.ActorUsername}}{{end}}", event)
+	}
+
+	return nil
+}
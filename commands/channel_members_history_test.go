@@ -0,0 +1,64 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelUsersExportHistoryCmd() {
+	channel := &model.Channel{Id: "channel-id", Name: "incident"}
+	posts := []*model.Post{
+		{Id: "post-5", CreateAt: 5000, Type: model.PostTypeRemoveFromChannel, UserId: "system-bot-id", Props: model.StringInterface{"removedUserId": "guest-id", "removedUsername": "guest"}},
+		{Id: "post-4", CreateAt: 4000, Type: model.PostTypeRemoveFromChannel, UserId: "admin-id", Props: model.StringInterface{"removedUserId": "john-id", "removedUsername": "john"}},
+		{Id: "post-3", CreateAt: 3000, Type: model.PostTypeLeaveChannel, UserId: "jane-id", Props: model.StringInterface{"username": "jane"}},
+		{Id: "post-2", CreateAt: 2500, Message: "a regular message", UserId: "john-id"},
+		{Id: "post-1", CreateAt: 2000, Type: model.PostTypeAddGuestToChannel, UserId: "admin-id", Props: model.StringInterface{"username": "admin", model.PostPropsAddedUserId: "guest-id", "addedUsername": "guest"}},
+		{Id: "post-0", CreateAt: 1000, Type: model.PostTypeJoinChannel, UserId: "jane-id", Props: model.StringInterface{"username": "jane"}},
+	}
+	postList := model.NewPostList()
+	for _, post := range posts {
+		postList.AddPost(post)
+		postList.AddOrder(post.Id)
+	}
+
+	expectHistory := func() {
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("system-bot-id", "").
+			Return(&model.User{Id: "system-bot-id", Username: "system-bot"}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should reconstruct the membership timeline in chronological order", func() {
+		printer.Clean()
+		expectHistory()
+
+		cmd := &cobra.Command{}
+		err := channelUsersExportHistoryCmdF(s.client, cmd, []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 5)
+		s.Require().Equal(&membershipEvent{ID: "post-0", CreateAt: 1000, Event: "joined", UserID: "jane-id", Username: "jane"}, printer.GetLines()[0])
+		s.Require().Equal(&membershipEvent{ID: "post-1", CreateAt: 2000, Event: "added", UserID: "guest-id", Username: "guest", ActorID: "admin-id", ActorUsername: "admin"}, printer.GetLines()[1])
+		s.Require().Equal(&membershipEvent{ID: "post-3", CreateAt: 3000, Event: "left", UserID: "jane-id", Username: "jane"}, printer.GetLines()[2])
+		s.Require().Equal(&membershipEvent{ID: "post-4", CreateAt: 4000, Event: "removed", UserID: "john-id", Username: "john", ActorID: "admin-id", ActorUsername: "admin"}, printer.GetLines()[3])
+		s.Require().Equal(&membershipEvent{ID: "post-5", CreateAt: 5000, Event: "removed", UserID: "guest-id", Username: "guest", ActorID: "system-bot-id", ActorUsername: "system-bot"}, printer.GetLines()[4])
+	})
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelMessageRetentionCmd = &cobra.Command{
+	Use:   "message-retention",
+	Short: "Management of the message retention of channels",
+}
+
+var ChannelMessageRetentionOverrideCmd = &cobra.Command{
+	Use:   "override",
+	Short: "Management of the granular retention policies covering channels",
+}
+
+var ChannelMessageRetentionOverrideListCmd = &cobra.Command{
+	Use:   "list [channels]",
+	Short: "List the granular retention policies covering channels",
+	Long: `List the granular data retention policy covering each of the channels given, or each channel of the team given with --team whose name matches any of the --pattern patterns. Without channels or --team, all the channels covered by a granular policy are listed.
+Patterns use shell glob syntax, e.g. "project-*". Channels with no override follow the policy of their team, if any, or the global policy.`,
+	Example: `  channel message-retention override list
+  channel message-retention override list myteam:town-square myteam:off-topic
+  channel message-retention override list --team myteam --pattern "project-*"`,
+	RunE: withClient(channelMessageRetentionOverrideListCmdF),
+}
+
+var ChannelMessageRetentionOverrideSetCmd = &cobra.Command{
+	Use:   "set [policy] [channels]",
+	Short: "Set the granular retention policy covering channels",
+	Long: `Make the granular data retention policy given by ID or display name cover the channels given, and each channel of the team given with --team whose name matches any of the --pattern patterns. The channels covered by another policy are moved out of it, as a channel can only be covered by one, and are added back to it if they can't be added to the new policy. They are reported as "failed (uncovered)" when that also fails, as they then follow the policy of their team or the global one.
+With --clear, the channels are removed from the policy covering them instead, and no policy is given. Patterns use shell glob syntax, e.g. "project-*".`,
+	Example: `  # check which channels would change
+  channel message-retention override set "Short retention" --team myteam --pattern "project-*" --dry-run
+
+  channel message-retention override set "Short retention" --team myteam --pattern "project-*"
+  channel message-retention override set --clear myteam:town-square`,
+	RunE: withClient(channelMessageRetentionOverrideSetCmdF),
+}
+
+func init() {
+	ChannelMessageRetentionOverrideListCmd.Flags().String("team", "", "Team of the channels to list")
+	ChannelMessageRetentionOverrideListCmd.Flags().StringSlice("pattern", []string{}, "Patterns of the names of the channels of the team. Defaults to all the channels")
+
+	ChannelMessageRetentionOverrideSetCmd.Flags().String("team", "", "Team of the channels to set the policy of")
+	ChannelMessageRetentionOverrideSetCmd.Flags().StringSlice("pattern", []string{}, "Patterns of the names of the channels of the team. Defaults to all the channels")
+	ChannelMessageRetentionOverrideSetCmd.Flags().Bool("clear", false, "Remove the channels from the policy covering them")
+	ChannelMessageRetentionOverrideSetCmd.Flags().Bool("dry-run", false, "List the changes without applying them")
+
+	ChannelMessageRetentionOverrideCmd.AddCommand(
+		ChannelMessageRetentionOverrideListCmd,
+		ChannelMessageRetentionOverrideSetCmd,
+	)
+	ChannelMessageRetentionCmd.AddCommand(ChannelMessageRetentionOverrideCmd)
+	ChannelCmd.AddCommand(ChannelMessageRetentionCmd)
+}
+
+type channelRetentionOverride struct {
+	ChannelID    string `json:"channel_id"`
+	Channel      string `json:"channel"`
+	PolicyID     string `json:"policy_id,omitempty"`
+	Policy       string `json:"policy,omitempty"`
+	PostDuration int64  `json:"post_duration,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+const channelRetentionOverrideTemplate = "{{.Channel}}: {{if .PolicyID}}{{.Policy}} ({{.PostDuration}} days){{else}}no override{{end}}"
+
+func newChannelRetentionOverride(channel *model.Channel, policy *model.RetentionPolicyWithTeamAndChannelCounts) *channelRetentionOverride {
+	override := &channelRetentionOverride{ChannelID: channel.Id, Channel: channel.Name}
+	if policy != nil {
+		override.PolicyID = policy.ID
+		override.Policy = policy.DisplayName
+		if policy.PostDurationDays != nil {
+			override.PostDuration = *policy.PostDurationDays
+		}
+	}
+	return override
+}
+
+func getAllRetentionPolicies(c client.Client) ([]*model.RetentionPolicyWithTeamAndChannelCounts, error) {
+	policies := []*model.RetentionPolicyWithTeamAndChannelCounts{}
+	for page := 0; ; page++ {
+		list, _, err := c.GetDataRetentionPolicies(page, web.PerPageMaximum)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the data retention policies")
+		}
+		policies = append(policies, list.Policies...)
+		if len(list.Policies) < web.PerPageMaximum {
+			return policies, nil
+		}
+	}
+}
+
+// getRetentionPolicyCoverage returns the channels covered by the
+// policies, and the policy covering each channel by channel ID
+func getRetentionPolicyCoverage(c client.Client, policies []*model.RetentionPolicyWithTeamAndChannelCounts) ([]*model.Channel, map[string]*model.RetentionPolicyWithTeamAndChannelCounts, error) {
+	channels := []*model.Channel{}
+	channelPolicies := map[string]*model.RetentionPolicyWithTeamAndChannelCounts{}
+	for _, policy := range policies {
+		for page := 0; ; page++ {
+			list, _, err := c.GetChannelsForRetentionPolicy(policy.ID, page, web.PerPageMaximum)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to get the channels of policy %q", policy.DisplayName)
+			}
+			for _, channel := range list.Channels {
+				channels = append(channels, &channel.Channel)
+				channelPolicies[channel.Id] = policy
+			}
+			if len(list.Channels) < web.PerPageMaximum {
+				break
+			}
+		}
+	}
+	return channels, channelPolicies, nil
+}
+
+// getRetentionPolicyFromArg finds a policy by ID or by display name,
+// failing if several policies have the name
+func getRetentionPolicyFromArg(policies []*model.RetentionPolicyWithTeamAndChannelCounts, policyArg string) (*model.RetentionPolicyWithTeamAndChannelCounts, error) {
+	var found *model.RetentionPolicyWithTeamAndChannelCounts
+	for _, policy := range policies {
+		if policy.ID == policyArg {
+			return policy, nil
+		}
+		if policy.DisplayName == policyArg {
+			if found != nil {
+				return nil, fmt.Errorf("several policies are named %q, use the ID of the policy instead", policyArg)
+			}
+			found = policy
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unable to find policy %q", policyArg)
+	}
+	return found, nil
+}
+
+// getRetentionOverrideChannels returns the channels given as arguments
+// and the channels of the team matching any of the patterns
+func getRetentionOverrideChannels(c client.Client, channelArgs []string, teamArg string, patterns []string) ([]*model.Channel, error) {
+	if teamArg == "" && len(patterns) > 0 {
+		return nil, errors.New("the --pattern flag can only be used with --team")
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	channels := []*model.Channel{}
+	for i, channel := range getChannelsFromChannelArgs(c, channelArgs) {
+		if channel == nil {
+			return nil, errors.New("Unable to find channel '" + channelArgs[i] + "'")
+		}
+		channels = append(channels, channel)
+	}
+
+	if teamArg == "" {
+		return channels, nil
+	}
+	team := getTeamFromTeamArg(c, teamArg)
+	if team == nil {
+		return nil, fmt.Errorf("unable to find team %q", teamArg)
+	}
+	teamChannels, err := getAllPublicChannelsForTeam(c, team.Id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the public channels of team %q", teamArg)
+	}
+	privateChannels, err := getPrivateChannels(c, team.Id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the private channels of team %q", teamArg)
+	}
+	teamChannels = append(teamChannels, privateChannels...)
+	sort.Slice(teamChannels, func(i, j int) bool { return teamChannels[i].Name < teamChannels[j].Name })
+
+	for _, channel := range teamChannels {
+		if matchesAnyPattern(channel.Name, patterns) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+func channelMessageRetentionOverrideListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	teamArg, _ := cmd.Flags().GetString("team")
+	patterns, _ := cmd.Flags().GetStringSlice("pattern")
+
+	policies, err := getAllRetentionPolicies(c)
+	if err != nil {
+		return err
+	}
+	coveredChannels, channelPolicies, err := getRetentionPolicyCoverage(c, policies)
+	if err != nil {
+		return err
+	}
+
+	channels := coveredChannels
+	if len(args) > 0 || teamArg != "" || len(patterns) > 0 {
+		if channels, err = getRetentionOverrideChannels(c, args, teamArg, patterns); err != nil {
+			return err
+		}
+	}
+
+	if len(channels) == 0 {
+		printer.Print("No channels found")
+		return nil
+	}
+	for _, channel := range channels {
+		printer.PrintT(channelRetentionOverrideTemplate, newChannelRetentionOverride(channel, channelPolicies[channel.Id]))
+	}
+	return nil
+}
+
+func channelMessageRetentionOverrideSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	teamArg, _ := cmd.Flags().GetString("team")
+	patterns, _ := cmd.Flags().GetStringSlice("pattern")
+	clearPolicy, _ := cmd.Flags().GetBool("clear")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if !clearPolicy && len(args) == 0 {
+		return errors.New("a policy must be given, or --clear to remove the channels from their policy")
+	}
+	channelArgs := args
+	if !clearPolicy {
+		channelArgs = args[1:]
+	}
+	if len(channelArgs) == 0 && teamArg == "" {
+		return errors.New("either channels or --team must be given")
+	}
+
+	policies, err := getAllRetentionPolicies(c)
+	if err != nil {
+		return err
+	}
+	var target *model.RetentionPolicyWithTeamAndChannelCounts
+	if !clearPolicy {
+		if target, err = getRetentionPolicyFromArg(policies, args[0]); err != nil {
+			return err
+		}
+	}
+	_, channelPolicies, err := getRetentionPolicyCoverage(c, policies)
+	if err != nil {
+		return err
+	}
+	channels, err := getRetentionOverrideChannels(c, channelArgs, teamArg, patterns)
+	if err != nil {
+		return err
+	}
+
+	// the channels are removed from the policy covering them first, as
+	// they can only be added to a policy when they aren't covered
+	results := []*channelRetentionOverride{}
+	removals := map[string][]*channelRetentionOverride{}
+	additions := []*channelRetentionOverride{}
+	for _, channel := range channels {
+		current := channelPolicies[channel.Id]
+		result := newChannelRetentionOverride(channel, target)
+		results = append(results, result)
+		switch {
+		case current == nil && target == nil, current != nil && target != nil && current.ID == target.ID:
+			result.Status = "unchanged"
+			continue
+		case target == nil:
+			result.Status = "cleared"
+		default:
+			result.Status = "set"
+			additions = append(additions, result)
+		}
+		if current != nil {
+			removals[current.ID] = append(removals[current.ID], result)
+		}
+		if dryRun {
+			result.Status = "would be " + result.Status
+		}
+	}
+
+	failed := 0
+	if !dryRun {
+		removalFailed := map[string]bool{}
+		for policyID, policyResults := range removals {
+			if _, err := c.RemoveChannelsFromRetentionPolicy(policyID, retentionOverrideChannelIDs(policyResults)); err != nil {
+				for _, result := range policyResults {
+					result.Status = "failed: " + err.Error()
+					removalFailed[result.ChannelID] = true
+				}
+				failed += len(policyResults)
+			}
+		}
+		pending := []*channelRetentionOverride{}
+		for _, result := range additions {
+			if !removalFailed[result.ChannelID] {
+				pending = append(pending, result)
+			}
+		}
+		if len(pending) > 0 {
+			if _, err := c.AddChannelsToRetentionPolicy(target.ID, retentionOverrideChannelIDs(pending)); err != nil {
+				restoreRetentionOverrides(c, pending, channelPolicies, err)
+				failed += len(pending)
+			}
+		}
+	}
+
+	for _, result := range results {
+		printer.PrintT("{{.Channel}}: {{.Status}}{{if .PolicyID}} ({{.Policy}}){{end}}", result)
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to update the retention policy of %d channels", failed)
+	}
+	return nil
+}
+
+// restoreRetentionOverrides adds the channels that couldn't be added to
+// a policy back to the policy they were moved out of, so that they
+// don't fall back to the retention of their team or the global one
+func restoreRetentionOverrides(c client.Client, results []*channelRetentionOverride, channelPolicies map[string]*model.RetentionPolicyWithTeamAndChannelCounts, addErr error) {
+	previous := map[string][]*channelRetentionOverride{}
+	for _, result := range results {
+		current := channelPolicies[result.ChannelID]
+		if current == nil {
+			result.Status = "failed: " + addErr.Error()
+			continue
+		}
+		previous[current.ID] = append(previous[current.ID], result)
+	}
+
+	for policyID, policyResults := range previous {
+		status := "failed (restored): "
+		if _, err := c.AddChannelsToRetentionPolicy(policyID, retentionOverrideChannelIDs(policyResults)); err != nil {
+			status = "failed (uncovered): "
+		}
+		for _, result := range policyResults {
+			result.Status = status + addErr.Error()
+		}
+	}
+}
+
+func retentionOverrideChannelIDs(results []*channelRetentionOverride) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.ChannelID
+	}
+	return ids
+}
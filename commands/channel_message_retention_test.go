@@ -0,0 +1,244 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelMessageRetentionOverrideCmds() {
+	shortPolicy := &model.RetentionPolicyWithTeamAndChannelCounts{
+		RetentionPolicy: model.RetentionPolicy{ID: "short-policy-id", DisplayName: "Short retention", PostDurationDays: model.NewInt64(30)},
+	}
+	longPolicy := &model.RetentionPolicyWithTeamAndChannelCounts{
+		RetentionPolicy: model.RetentionPolicy{ID: "long-policy-id", DisplayName: "Long retention", PostDurationDays: model.NewInt64(365)},
+	}
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	project := &model.Channel{Id: "project-id", Name: "project-a", TeamId: team.Id, Type: model.ChannelTypeOpen}
+	secret := &model.Channel{Id: "secret-id", Name: "project-b", TeamId: team.Id, Type: model.ChannelTypePrivate}
+	townSquare := &model.Channel{Id: "town-square-id", Name: "town-square", TeamId: team.Id, Type: model.ChannelTypeOpen}
+
+	expectPolicies := func() {
+		s.client.
+			EXPECT().
+			GetDataRetentionPolicies(0, web.PerPageMaximum).
+			Return(&model.RetentionPolicyWithTeamAndChannelCountsList{Policies: []*model.RetentionPolicyWithTeamAndChannelCounts{shortPolicy, longPolicy}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelsForRetentionPolicy(shortPolicy.ID, 0, web.PerPageMaximum).
+			Return(&model.ChannelsWithCount{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelsForRetentionPolicy(longPolicy.ID, 0, web.PerPageMaximum).
+			Return(&model.ChannelsWithCount{Channels: model.ChannelListWithTeamData{{Channel: *project, TeamName: team.Name}}}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	expectTeamChannels := func() {
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{townSquare, project}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{secret}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	newSetCmd := func(clearPolicy, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", team.Name, "")
+		cmd.Flags().StringSlice("pattern", []string{"project-*"}, "")
+		cmd.Flags().Bool("clear", clearPolicy, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	s.Run("Should list all the channels covered by a policy", func() {
+		printer.Clean()
+		expectPolicies()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "", "")
+		cmd.Flags().StringSlice("pattern", []string{}, "")
+
+		err := channelMessageRetentionOverrideListCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&channelRetentionOverride{
+			ChannelID:    project.Id,
+			Channel:      project.Name,
+			PolicyID:     longPolicy.ID,
+			Policy:       longPolicy.DisplayName,
+			PostDuration: 365,
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should list the policies of the channels given", func() {
+		printer.Clean()
+		expectPolicies()
+		s.client.
+			EXPECT().
+			GetChannel(townSquare.Id, "").
+			Return(townSquare, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "", "")
+		cmd.Flags().StringSlice("pattern", []string{}, "")
+
+		err := channelMessageRetentionOverrideListCmdF(s.client, cmd, []string{townSquare.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&channelRetentionOverride{ChannelID: townSquare.Id, Channel: townSquare.Name}}, printer.GetLines())
+	})
+
+	s.Run("Should move the channels matching the pattern to the policy", func() {
+		printer.Clean()
+		expectPolicies()
+		expectTeamChannels()
+		s.client.
+			EXPECT().
+			RemoveChannelsFromRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(shortPolicy.ID, []string{project.Id, secret.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(false, false), []string{"Short retention"})
+		s.Require().NoError(err)
+		lines := printer.GetLines()
+		s.Require().Len(lines, 2)
+		s.Require().Equal("set", lines[0].(*channelRetentionOverride).Status)
+		s.Require().Equal(secret.Id, lines[1].(*channelRetentionOverride).ChannelID)
+	})
+
+	s.Run("Should only list the changes on a dry run", func() {
+		printer.Clean()
+		expectPolicies()
+		expectTeamChannels()
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(true, true), []string{})
+		s.Require().NoError(err)
+		lines := printer.GetLines()
+		s.Require().Len(lines, 2)
+		s.Require().Equal("would be cleared", lines[0].(*channelRetentionOverride).Status)
+		s.Require().Equal("unchanged", lines[1].(*channelRetentionOverride).Status)
+	})
+
+	s.Run("Should not add the channels that couldn't be removed from their policy", func() {
+		printer.Clean()
+		expectPolicies()
+		expectTeamChannels()
+		s.client.
+			EXPECT().
+			RemoveChannelsFromRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(shortPolicy.ID, []string{secret.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(false, false), []string{shortPolicy.ID})
+		s.Require().EqualError(err, "unable to update the retention policy of 1 channels")
+		s.Require().Equal("failed: mock error", printer.GetLines()[0].(*channelRetentionOverride).Status)
+	})
+
+	s.Run("Should restore the channels to their previous policy when they can't be added", func() {
+		printer.Clean()
+		expectPolicies()
+		expectTeamChannels()
+		s.client.
+			EXPECT().
+			RemoveChannelsFromRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(shortPolicy.ID, []string{project.Id, secret.Id}).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(false, false), []string{shortPolicy.ID})
+		s.Require().EqualError(err, "unable to update the retention policy of 2 channels")
+		lines := printer.GetLines()
+		s.Require().Len(lines, 2)
+		s.Require().Equal("failed (restored): mock error", lines[0].(*channelRetentionOverride).Status)
+		s.Require().Equal("failed: mock error", lines[1].(*channelRetentionOverride).Status)
+	})
+
+	s.Run("Should report the channels that couldn't be restored to their previous policy", func() {
+		printer.Clean()
+		expectPolicies()
+		expectTeamChannels()
+		s.client.
+			EXPECT().
+			RemoveChannelsFromRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(shortPolicy.ID, []string{project.Id, secret.Id}).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddChannelsToRetentionPolicy(longPolicy.ID, []string{project.Id}).
+			Return(&model.Response{}, errors.New("restore error")).
+			Times(1)
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(false, false), []string{shortPolicy.ID})
+		s.Require().EqualError(err, "unable to update the retention policy of 2 channels")
+		s.Require().Equal("failed (uncovered): mock error", printer.GetLines()[0].(*channelRetentionOverride).Status)
+	})
+
+	s.Run("Should fail with an unknown policy", func() {
+		s.client.
+			EXPECT().
+			GetDataRetentionPolicies(0, web.PerPageMaximum).
+			Return(&model.RetentionPolicyWithTeamAndChannelCountsList{Policies: []*model.RetentionPolicyWithTeamAndChannelCounts{shortPolicy}}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelMessageRetentionOverrideSetCmdF(s.client, newSetCmd(false, false), []string{"Medium retention"})
+		s.Require().EqualError(err, `unable to find policy "Medium retention"`)
+	})
+}
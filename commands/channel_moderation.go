@@ -0,0 +1,254 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ChannelSlowmodeCmd = &cobra.Command{
+	Use:   "slowmode [channels]",
+	Short: "Restrict posting in channels",
+	Long: `Restrict posting in channels to channel admins through the channel moderation settings, removing the permission to post from members and guests.
+This is not a posting cooldown: the server has no setting to limit how often users can post in a channel, so posting is restricted altogether instead. This is useful for announcement channels. The server doesn't support time based posting windows either, so to open posting for a limited time run the command with --disable and enable it again later.
+The moderation of each channel before it was restricted is saved to a state file, next to the mmctl config file by default, and --disable restores it, so a channel where only some roles could post is left as it was.
+Channel moderation requires an Enterprise license.`,
+	Example: `  # only channel admins will be able to post in these channels
+  channel slowmode myteam:announcements myteam:news
+
+  # also prevent members and guests from adding reactions
+  channel slowmode myteam:announcements --include-reactions
+
+  # restore who could post and react before
+  channel slowmode myteam:announcements --disable`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(channelSlowmodeCmdF),
+}
+
+func init() {
+	ChannelSlowmodeCmd.Flags().Bool("disable", false, "Restore the moderation of the channels from before they were restricted")
+	ChannelSlowmodeCmd.Flags().Bool("include-reactions", false, "Also restrict adding reactions")
+	ChannelSlowmodeCmd.Flags().String("state-file", "", "Path of the file where the moderation to restore is saved. Defaults to a file next to the mmctl config file")
+
+	ChannelCmd.AddCommand(ChannelSlowmodeCmd)
+}
+
+const channelSlowmodeFileName = "channel_slowmode.json"
+
+// slowmodeRoles holds whether the members and the guests of a channel
+// had a moderated permission. A nil value is a role the channel doesn't
+// moderate, which is left unchanged
+type slowmodeRoles struct {
+	Members *bool `json:"members"`
+	Guests  *bool `json:"guests"`
+}
+
+func channelSlowmodeStateFilePath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("state-file"); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(resolveConfigFilePath()), channelSlowmodeFileName)
+}
+
+// readSlowmodeChannels returns the moderation of the restricted channels
+// saved to the state file, by channel ID and moderated permission
+func readSlowmodeChannels(path string) (map[string]map[string]*slowmodeRoles, error) {
+	channels := map[string]map[string]*slowmodeRoles{}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return channels, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the channel slowmode state")
+	}
+	if err := json.Unmarshal(b, &channels); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the channel slowmode state")
+	}
+	if channels == nil {
+		channels = map[string]map[string]*slowmodeRoles{}
+	}
+	return channels, nil
+}
+
+func saveSlowmodeChannels(path string, channels map[string]map[string]*slowmodeRoles) error {
+	b, _ := json.MarshalIndent(channels, "", "  ")
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "failed to save the channel slowmode state")
+	}
+	return nil
+}
+
+// getChannelModeratedRoles returns whether the members and the guests
+// of a channel have each of the permissions
+func getChannelModeratedRoles(c client.Client, channelID string, permissions []string) (map[string]*slowmodeRoles, error) {
+	moderations, _, err := c.GetChannelModerations(channelID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	roles := map[string]*slowmodeRoles{}
+	for _, moderation := range moderations {
+		if moderation.Roles == nil {
+			continue
+		}
+		for _, permission := range permissions {
+			if moderation.Name != permission {
+				continue
+			}
+			permissionRoles := &slowmodeRoles{}
+			if moderation.Roles.Members != nil {
+				permissionRoles.Members = model.NewBool(moderation.Roles.Members.Value)
+			}
+			if moderation.Roles.Guests != nil {
+				permissionRoles.Guests = model.NewBool(moderation.Roles.Guests.Value)
+			}
+			roles[permission] = permissionRoles
+		}
+	}
+	return roles, nil
+}
+
+func enableChannelSlowmode(c client.Client, stateFile string, channels []*model.Channel, permissions []string) error {
+	stored, err := readSlowmodeChannels(stateFile)
+	if err != nil {
+		return err
+	}
+
+	// the moderation of the channels is stored before restricting them,
+	// keeping the one stored when a channel is restricted again
+	restricted := []*model.Channel{}
+	for _, channel := range channels {
+		previous, err := getChannelModeratedRoles(c, channel.Id, permissions)
+		if err != nil {
+			printer.PrintError("Unable to get the moderation of channel '" + channel.Name + "'. Error: " + err.Error())
+			continue
+		}
+		if stored[channel.Id] == nil {
+			stored[channel.Id] = map[string]*slowmodeRoles{}
+		}
+		for permission, roles := range previous {
+			if _, ok := stored[channel.Id][permission]; !ok {
+				stored[channel.Id][permission] = roles
+			}
+		}
+		restricted = append(restricted, channel)
+	}
+	if len(restricted) == 0 {
+		return nil
+	}
+
+	if err := saveSlowmodeChannels(stateFile, stored); err != nil {
+		return err
+	}
+
+	patch := make([]*model.ChannelModerationPatch, 0, len(permissions))
+	for _, permission := range permissions {
+		patch = append(patch, &model.ChannelModerationPatch{
+			Name:  model.NewString(permission),
+			Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(false), Guests: model.NewBool(false)},
+		})
+	}
+	for _, channel := range restricted {
+		if _, _, err := c.PatchChannelModerations(channel.Id, patch); err != nil {
+			printer.PrintError("Unable to update the moderation of channel '" + channel.Name + "'. Error: " + err.Error())
+			continue
+		}
+		printer.PrintT("Posting restricted to channel admins in channel {{.Name}}", channel)
+	}
+	return nil
+}
+
+func disableChannelSlowmode(c client.Client, stateFile string, channels []*model.Channel) error {
+	stored, err := readSlowmodeChannels(stateFile)
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for _, channel := range channels {
+		permissions, ok := stored[channel.Id]
+		if !ok {
+			printer.PrintError("Unable to restore the moderation of channel '" + channel.Name + "'. Error: it wasn't restricted with channel slowmode")
+			continue
+		}
+
+		names := make([]string, 0, len(permissions))
+		for permission := range permissions {
+			names = append(names, permission)
+		}
+		sort.Strings(names)
+
+		patch := []*model.ChannelModerationPatch{}
+		for _, permission := range names {
+			roles := permissions[permission]
+			if roles == nil || (roles.Members == nil && roles.Guests == nil) {
+				continue
+			}
+			patch = append(patch, &model.ChannelModerationPatch{
+				Name:  model.NewString(permission),
+				Roles: &model.ChannelModeratedRolesPatch{Members: roles.Members, Guests: roles.Guests},
+			})
+		}
+		if len(patch) > 0 {
+			if _, _, err := c.PatchChannelModerations(channel.Id, patch); err != nil {
+				printer.PrintError("Unable to update the moderation of channel '" + channel.Name + "'. Error: " + err.Error())
+				continue
+			}
+		}
+
+		delete(stored, channel.Id)
+		restored++
+		printer.PrintT("Moderation restored in channel {{.Name}}", channel)
+	}
+	if restored == 0 {
+		return nil
+	}
+
+	if len(stored) == 0 {
+		if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove the channel slowmode state")
+		}
+		return nil
+	}
+	return saveSlowmodeChannels(stateFile, stored)
+}
+
+func channelSlowmodeCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	disable, _ := cmd.Flags().GetBool("disable")
+	includeReactions, _ := cmd.Flags().GetBool("include-reactions")
+
+	permissions := []string{model.ChannelModeratedPermissionsMap[model.PermissionCreatePost.Id]}
+	if includeReactions {
+		permissions = append(permissions, model.ChannelModeratedPermissionsMap[model.PermissionAddReaction.Id])
+	}
+
+	channels := []*model.Channel{}
+	for i, channel := range getChannelsFromChannelArgs(c, args) {
+		if channel == nil {
+			printer.PrintError("Unable to find channel '" + args[i] + "'")
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	stateFile := channelSlowmodeStateFilePath(cmd)
+	if disable {
+		return disableChannelSlowmode(c, stateFile, channels)
+	}
+	return enableChannelSlowmode(c, stateFile, channels, permissions)
+}
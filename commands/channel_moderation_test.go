@@ -0,0 +1,231 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelSlowmodeCmdF() {
+	mockChannel := model.Channel{Id: channelID, Name: channelName}
+
+	tmp, err := ioutil.TempDir("", "mmctl-")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmp)
+	stateFile := filepath.Join(tmp, "slowmode.json")
+
+	newCmd := func(disable, includeReactions bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("disable", disable, "")
+		cmd.Flags().Bool("include-reactions", includeReactions, "")
+		cmd.Flags().String("state-file", stateFile, "")
+		return cmd
+	}
+
+	// saveState saves the moderation of the channel as restricted by
+	// slowmode, or removes the state file without permissions
+	saveState := func(permissions map[string]*slowmodeRoles) {
+		_ = os.Remove(stateFile)
+		if permissions != nil {
+			s.Require().NoError(saveSlowmodeChannels(stateFile, map[string]map[string]*slowmodeRoles{channelID: permissions}))
+		}
+	}
+
+	readState := func() map[string]map[string]*slowmodeRoles {
+		channels, err := readSlowmodeChannels(stateFile)
+		s.Require().NoError(err)
+		return channels
+	}
+
+	s.Run("Restrict posting in a channel and store its moderation", func() {
+		printer.Clean()
+		saveState(nil)
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelModerations(channelID, "").
+			Return([]*model.ChannelModeration{
+				{
+					Name: "create_post",
+					Roles: &model.ChannelModeratedRoles{
+						Members: &model.ChannelModeratedRole{Value: true, Enabled: true},
+						Guests:  &model.ChannelModeratedRole{Value: false, Enabled: true},
+					},
+				},
+				{
+					Name:  "create_reactions",
+					Roles: &model.ChannelModeratedRoles{Members: &model.ChannelModeratedRole{Value: true, Enabled: true}},
+				},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channelID, []*model.ChannelModerationPatch{
+				{
+					Name:  model.NewString("create_post"),
+					Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(false), Guests: model.NewBool(false)},
+				},
+			}).
+			Return([]*model.ChannelModeration{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSlowmodeCmdF(s.client, newCmd(false, false), []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&mockChannel, printer.GetLines()[0])
+		s.Require().Equal(map[string]map[string]*slowmodeRoles{
+			channelID: {"create_post": {Members: model.NewBool(true), Guests: model.NewBool(false)}},
+		}, readState())
+	})
+
+	s.Run("Keep the stored moderation when restricting a channel again", func() {
+		printer.Clean()
+		saveState(nil)
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		saveState(map[string]*slowmodeRoles{"create_post": {Members: model.NewBool(true), Guests: model.NewBool(true)}})
+
+		restricted := &model.ChannelModeratedRoles{
+			Members: &model.ChannelModeratedRole{Value: false, Enabled: true},
+			Guests:  &model.ChannelModeratedRole{Value: false, Enabled: true},
+		}
+		s.client.
+			EXPECT().
+			GetChannelModerations(channelID, "").
+			Return([]*model.ChannelModeration{
+				{Name: "create_post", Roles: restricted},
+				{Name: "create_reactions", Roles: &model.ChannelModeratedRoles{Members: &model.ChannelModeratedRole{Value: true, Enabled: true}}},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channelID, gomock.Any()).
+			Return([]*model.ChannelModeration{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSlowmodeCmdF(s.client, newCmd(false, true), []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal(map[string]map[string]*slowmodeRoles{
+			channelID: {
+				"create_post":      {Members: model.NewBool(true), Guests: model.NewBool(true)},
+				"create_reactions": {Members: model.NewBool(true)},
+			},
+		}, readState())
+	})
+
+	s.Run("Restore the stored moderation of a channel", func() {
+		printer.Clean()
+		saveState(nil)
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		saveState(map[string]*slowmodeRoles{
+			"create_post":      {Members: model.NewBool(true), Guests: model.NewBool(false)},
+			"create_reactions": {Members: model.NewBool(true)},
+		})
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channelID, []*model.ChannelModerationPatch{
+				{
+					Name:  model.NewString("create_post"),
+					Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(true), Guests: model.NewBool(false)},
+				},
+				{
+					Name:  model.NewString("create_reactions"),
+					Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(true)},
+				},
+			}).
+			Return([]*model.ChannelModeration{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSlowmodeCmdF(s.client, newCmd(true, false), []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Empty(readState())
+		s.Require().NoFileExists(stateFile)
+	})
+
+	s.Run("Fail to restore a channel that wasn't restricted", func() {
+		printer.Clean()
+		saveState(nil)
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSlowmodeCmdF(s.client, newCmd(true, false), []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal([]interface{}{"Unable to restore the moderation of channel '" + channelName + "'. Error: it wasn't restricted with channel slowmode"}, printer.GetErrorLines())
+	})
+
+	s.Run("Report channels that can't be found or updated", func() {
+		printer.Clean()
+		saveState(nil)
+
+		s.client.
+			EXPECT().
+			GetChannel("nonexistent", "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelModerations(channelID, "").
+			Return([]*model.ChannelModeration{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channelID, gomock.Any()).
+			Return(nil, &model.Response{}, errors.New("license required")).
+			Times(1)
+
+		err := channelSlowmodeCmdF(s.client, newCmd(false, false), []string{"nonexistent", channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 2)
+		s.Require().Equal("Unable to find channel 'nonexistent'", printer.GetErrorLines()[0])
+		s.Require().Equal("Unable to update the moderation of channel '"+channelName+"'. Error: license required", printer.GetErrorLines()[1])
+	})
+}
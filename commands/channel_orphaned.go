@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func init() {
+	ListChannelsCmd.Flags().Bool("orphaned-creator", false, "Only list the channels whose creator is deactivated or deleted and that have no channel admins")
+	ListChannelsCmd.Flags().Bool("assign-admin", false, "Promote the most active member of each orphaned channel to channel admin")
+	ListChannelsCmd.Flags().Bool("confirm", false, "Confirm you really want to promote the members with --assign-admin. Without it, the members that would be promoted are listed")
+}
+
+const (
+	creatorDeactivated = "deactivated"
+	creatorDeleted     = "deleted"
+)
+
+type orphanedChannel struct {
+	Team          string `json:"team"`
+	Channel       string `json:"channel"`
+	ChannelID     string `json:"channel_id"`
+	Creator       string `json:"creator"`
+	CreatorStatus string `json:"creator_status"`
+	AssignedAdmin string `json:"assigned_admin,omitempty"`
+	Assigned      bool   `json:"assigned"`
+}
+
+// orphanedChannelFinder looks for the channels without an owner,
+// caching the users it fetches
+type orphanedChannelFinder struct {
+	c     client.Client
+	users map[string]*model.User
+}
+
+// getUser returns the user with the given id, or nil if it doesn't
+// exist anymore. Only a not found response means that the user doesn't
+// exist, any other error is returned
+func (f *orphanedChannelFinder) getUser(userID string) (*model.User, error) {
+	if user, ok := f.users[userID]; ok {
+		return user, nil
+	}
+	user, resp, err := f.c.GetUser(userID, "")
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return nil, errors.Wrapf(err, "failed to fetch user %s", userID)
+		}
+		user = nil
+	}
+	f.users[userID] = user
+	return user, nil
+}
+
+// creatorStatus returns whether the creator of the channel is
+// deactivated or deleted, or an empty string if it is active. The
+// channels created by the server have no creator and are never
+// orphaned
+func (f *orphanedChannelFinder) creatorStatus(channel *model.Channel) (string, string, error) {
+	if channel.CreatorId == "" {
+		return "", "", nil
+	}
+	creator, err := f.getUser(channel.CreatorId)
+	switch {
+	case err != nil:
+		return "", "", err
+	case creator == nil:
+		return channel.CreatorId, creatorDeleted, nil
+	case creator.DeleteAt > 0:
+		return creator.Username, creatorDeactivated, nil
+	}
+	return creator.Username, "", nil
+}
+
+// getChannelMembers returns the members of the channel, and whether any
+// of them is a channel admin
+func (f *orphanedChannelFinder) getChannelMembers(channel *model.Channel) ([]model.ChannelMember, bool, error) {
+	members := []model.ChannelMember{}
+	hasAdmin := false
+	for page := 0; ; page++ {
+		channelMembers, _, err := f.c.GetChannelMembers(channel.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to fetch the members of the channel")
+		}
+		for _, member := range channelMembers {
+			hasAdmin = hasAdmin || member.SchemeAdmin
+			members = append(members, member)
+		}
+		if len(channelMembers) < APILimitMaximum {
+			return members, hasAdmin, nil
+		}
+	}
+}
+
+// mostActiveMember returns the active member of the channel that posted
+// the most among its latest posts, or that viewed the channel the most
+// recently if they posted the same. Guests and bots are not candidates
+func (f *orphanedChannelFinder) mostActiveMember(channel *model.Channel, members []model.ChannelMember) (*model.User, error) {
+	postList, _, err := f.c.GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the posts of the channel")
+	}
+	posts := map[string]int{}
+	for _, post := range postList.Posts {
+		posts[post.UserId]++
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if posts[members[i].UserId] != posts[members[j].UserId] {
+			return posts[members[i].UserId] > posts[members[j].UserId]
+		}
+		return members[i].LastViewedAt > members[j].LastViewedAt
+	})
+
+	for _, member := range members {
+		if member.SchemeGuest {
+			continue
+		}
+		user, err := f.getUser(member.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.DeleteAt == 0 && !user.IsBot {
+			return user, nil
+		}
+	}
+	return nil, errors.New("there are no active members to promote")
+}
+
+func listOrphanedChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	assignAdmin, _ := cmd.Flags().GetBool("assign-admin")
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	finder := &orphanedChannelFinder{c: c, users: map[string]*model.User{}}
+
+	failed := 0
+	teams := getTeamsFromTeamArgs(c, args)
+	for i, team := range teams {
+		if team == nil {
+			printer.PrintError("Unable to find team '" + args[i] + "'")
+			continue
+		}
+
+		channels, err := getAllPublicChannelsForTeam(c, team.Id)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to list public channels for %q: %s", args[i], err))
+		}
+		privateChannels, err := getPrivateChannels(c, team.Id)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to list private channels for %q: %s", args[i], err))
+		}
+		channels = append(channels, privateChannels...)
+
+		for _, channel := range channels {
+			creator, status, err := finder.creatorStatus(channel)
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("unable to check the creator of channel %s:%s: %s", team.Name, channel.Name, err))
+				failed++
+				continue
+			}
+			if status == "" {
+				continue
+			}
+			members, hasAdmin, err := finder.getChannelMembers(channel)
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("unable to check the admins of channel %s:%s: %s", team.Name, channel.Name, err))
+				failed++
+				continue
+			}
+			if hasAdmin {
+				continue
+			}
+
+			orphaned := &orphanedChannel{Team: team.Name, Channel: channel.Name, ChannelID: channel.Id, Creator: creator, CreatorStatus: status}
+			if assignAdmin {
+				if err := assignChannelAdmin(c, finder, channel, members, orphaned, confirm); err != nil {
+					printer.PrintError(fmt.Sprintf("unable to assign an admin to channel %s:%s: %s", team.Name, channel.Name, err))
+					failed++
+				}
+			}
+			printer.PrintT("{{.Team}}:{{.Channel}} created by {{.Creator}} ({{.CreatorStatus}}){{if .AssignedAdmin}}, {{.AssignedAdmin}} {{if .Assigned}}assigned{{else}}would be assigned{{end}} as admin{{end}}", orphaned)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to check or fix %d orphaned channels", failed)
+	}
+	return nil
+}
+
+// assignChannelAdmin promotes the most active member of the channel to
+// channel admin, or only finds it if the promotion is not confirmed
+func assignChannelAdmin(c client.Client, finder *orphanedChannelFinder, channel *model.Channel, members []model.ChannelMember, orphaned *orphanedChannel, confirm bool) error {
+	user, err := finder.mostActiveMember(channel, members)
+	if err != nil {
+		return err
+	}
+	orphaned.AssignedAdmin = user.Username
+	if !confirm {
+		return nil
+	}
+	if _, err := c.UpdateChannelMemberSchemeRoles(channel.Id, user.Id, &model.SchemeRoles{SchemeUser: true, SchemeAdmin: true}); err != nil {
+		orphaned.AssignedAdmin = ""
+		return err
+	}
+	orphaned.Assigned = true
+	return nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestListOrphanedChannelsCmd() {
+	newCmd := func(assignAdmin bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("orphaned-creator", true, "")
+		cmd.Flags().Bool("assign-admin", assignAdmin, "")
+		cmd.Flags().Bool("confirm", assignAdmin, "")
+		return cmd
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	active := &model.User{Id: "active-id", Username: "active"}
+	gone := &model.User{Id: "gone-id", Username: "gone", DeleteAt: 1000}
+	poster := &model.User{Id: "poster-id", Username: "poster"}
+	viewer := &model.User{Id: "viewer-id", Username: "viewer"}
+
+	owned := &model.Channel{Id: "owned-id", Name: "owned", CreatorId: active.Id}
+	system := &model.Channel{Id: "system-id", Name: "town-square"}
+	administered := &model.Channel{Id: "administered-id", Name: "administered", CreatorId: gone.Id}
+	orphaned := &model.Channel{Id: "orphaned-id", Name: "orphaned", CreatorId: gone.Id}
+	deleted := &model.Channel{Id: "deleted-id", Name: "deleted", CreatorId: "deleted-user-id", Type: model.ChannelTypePrivate}
+
+	expectChannels := func() {
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{owned, system, administered, orphaned}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{deleted}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(active.Id, "").
+			Return(active, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(gone.Id, "").
+			Return(gone, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("deleted-user-id", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(administered.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{UserId: poster.Id, SchemeAdmin: true}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(orphaned.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{UserId: viewer.Id, LastViewedAt: 2000}, {UserId: poster.Id, LastViewedAt: 1000}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(deleted.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{UserId: gone.Id}}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should list the channels without an owner", func() {
+		printer.Clean()
+		expectChannels()
+
+		err := listChannelsCmdF(s.client, newCmd(false), []string{team.Name})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&orphanedChannel{Team: team.Name, Channel: orphaned.Name, ChannelID: orphaned.Id, Creator: gone.Username, CreatorStatus: creatorDeactivated},
+			&orphanedChannel{Team: team.Name, Channel: deleted.Name, ChannelID: deleted.Id, Creator: "deleted-user-id", CreatorStatus: creatorDeleted},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should promote the most active member to channel admin", func() {
+		printer.Clean()
+		expectChannels()
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(orphaned.Id, 0, APILimitMaximum, "", false).
+			Return(&model.PostList{Posts: map[string]*model.Post{"post-id": {UserId: poster.Id}}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(poster.Id, "").
+			Return(poster, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateChannelMemberSchemeRoles(orphaned.Id, poster.Id, &model.SchemeRoles{SchemeUser: true, SchemeAdmin: true}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		// the only member of the channel is deactivated
+		s.client.
+			EXPECT().
+			GetPostsForChannel(deleted.Id, 0, APILimitMaximum, "", false).
+			Return(&model.PostList{}, &model.Response{}, nil).
+			Times(1)
+
+		err := listChannelsCmdF(s.client, newCmd(true), []string{team.Name})
+		s.Require().EqualError(err, "unable to check or fix 1 orphaned channels")
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(poster.Username, printer.GetLines()[0].(*orphanedChannel).AssignedAdmin)
+		s.Require().Equal([]interface{}{"unable to assign an admin to channel myteam:deleted: there are no active members to promote"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should only list the members that would be promoted without --confirm", func() {
+		printer.Clean()
+		expectChannels()
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(orphaned.Id, 0, APILimitMaximum, "", false).
+			Return(&model.PostList{Posts: map[string]*model.Post{"post-id": {UserId: poster.Id}}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUser(poster.Id, "").
+			Return(poster, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPostsForChannel(deleted.Id, 0, APILimitMaximum, "", false).
+			Return(&model.PostList{}, &model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd(true)
+		_ = cmd.Flags().Set("confirm", "false")
+
+		err := listChannelsCmdF(s.client, cmd, []string{team.Name})
+		s.Require().EqualError(err, "unable to check or fix 1 orphaned channels")
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(poster.Username, printer.GetLines()[0].(*orphanedChannel).AssignedAdmin)
+		s.Require().False(printer.GetLines()[0].(*orphanedChannel).Assigned)
+	})
+
+	s.Run("Should not take the creator as deleted when it can't be fetched", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: "channel-id", Name: "channel", CreatorId: "creator-id"}
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{channel}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUser(channel.CreatorId, "").
+			Return(nil, &model.Response{StatusCode: http.StatusForbidden}, errors.New("forbidden")).
+			Times(1)
+
+		err := listChannelsCmdF(s.client, newCmd(true), []string{team.Name})
+		s.Require().EqualError(err, "unable to check or fix 1 orphaned channels")
+		s.Require().Empty(printer.GetLines())
+		s.Require().Equal([]interface{}{"unable to check the creator of channel myteam:channel: failed to fetch user creator-id: forbidden"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fail to assign admins without orphaned creator", func() {
+		cmd := newCmd(true)
+		_ = cmd.Flags().Set("orphaned-creator", "false")
+
+		err := listChannelsCmdF(s.client, cmd, []string{team.Name})
+		s.Require().EqualError(err, "the --assign-admin flag can only be used with --orphaned-creator")
+	})
+}
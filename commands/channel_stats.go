@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelStatsCmd = &cobra.Command{
+	Use:   "stats [channels]",
+	Short: "Show channel stats",
+	Long: `Show the members of the channels, and the posts and active members, the members that posted, of the last days. System messages are not counted.
+With --trend, the posts and active members are compared with the ones of the same number of days right before, to track the week-over-week change.`,
+	Example: `  channel stats myteam:town-square
+  channel stats myteam:town-square myteam:off-topic --trend
+  channel stats myteam:town-square --days 30 --trend`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(channelStatsCmdF),
+}
+
+func init() {
+	ChannelStatsCmd.Flags().Int("days", 7, "Number of days of the period to count the posts and active members of")
+	ChannelStatsCmd.Flags().Bool("trend", false, "Compare the period with the previous one")
+
+	ChannelCmd.AddCommand(ChannelStatsCmd)
+}
+
+// statsTrend compares a metric in the current period with the previous
+// one. The change is a percentage, and it's not set when the previous
+// value is zero
+type statsTrend struct {
+	Metric   string   `json:"metric"`
+	Current  float64  `json:"current"`
+	Previous float64  `json:"previous"`
+	Change   *float64 `json:"change,omitempty"`
+}
+
+func newStatsTrend(metric string, current, previous float64) *statsTrend {
+	trend := &statsTrend{Metric: metric, Current: current, Previous: previous}
+	if previous != 0 {
+		change := math.Round((current-previous)/previous*1000) / 10
+		trend.Change = &change
+	}
+	return trend
+}
+
+// String renders the trend for the plain output
+func (t *statsTrend) String() string {
+	change := "n/a"
+	if t.Change != nil {
+		change = fmt.Sprintf("%+.1f%%", *t.Change)
+	}
+	return fmt.Sprintf("%s %g (previous %g, %s)", t.Metric, t.Current, t.Previous, change)
+}
+
+type channelStatsRow struct {
+	ChannelID     string        `json:"channel_id"`
+	Name          string        `json:"name"`
+	Days          int           `json:"days"`
+	Members       int64         `json:"members"`
+	Posts         int           `json:"posts"`
+	ActiveMembers int           `json:"active_members"`
+	Trend         []*statsTrend `json:"trend,omitempty"`
+}
+
+// countChannelActivity counts the posts that aren't system messages and
+// the users that created them per period, the current one being the
+// posts created since the start and the previous one the older ones
+func countChannelActivity(posts []*model.Post, start int64) (currentPosts, currentActive, previousPosts, previousActive int) {
+	current := map[string]bool{}
+	previous := map[string]bool{}
+	for _, post := range posts {
+		if post.IsSystemMessage() {
+			continue
+		}
+		if post.CreateAt >= start {
+			currentPosts++
+			current[post.UserId] = true
+		} else {
+			previousPosts++
+			previous[post.UserId] = true
+		}
+	}
+	return currentPosts, len(current), previousPosts, len(previous)
+}
+
+func getChannelStatsRow(c client.Client, channel *model.Channel, days int, trend bool) (*channelStatsRow, error) {
+	stats, _, err := c.GetChannelStats(channel.Id, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the channel stats")
+	}
+
+	period := int64(days) * int64(24*time.Hour/time.Millisecond)
+	start := model.GetMillis() - period
+	since := start
+	if trend {
+		since = start - period
+	}
+	posts, err := getFilteredPosts(c, channel.Id, &postListFilter{since: since}, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	currentPosts, currentActive, previousPosts, previousActive := countChannelActivity(posts, start)
+	row := &channelStatsRow{
+		ChannelID:     channel.Id,
+		Name:          channel.Name,
+		Days:          days,
+		Members:       stats.MemberCount,
+		Posts:         currentPosts,
+		ActiveMembers: currentActive,
+	}
+	if trend {
+		row.Trend = []*statsTrend{
+			newStatsTrend("posts", float64(currentPosts), float64(previousPosts)),
+			newStatsTrend("active_members", float64(currentActive), float64(previousActive)),
+		}
+	}
+	return row, nil
+}
+
+func channelStatsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	trend, _ := cmd.Flags().GetBool("trend")
+	if days < 1 {
+		return errors.New("the number of days must be greater than 0")
+	}
+
+	channels := getChannelsFromChannelArgs(c, args)
+	for i, channel := range channels {
+		if channel == nil {
+			printer.PrintError("Unable to find channel '" + args[i] + "'")
+			continue
+		}
+
+		row, err := getChannelStatsRow(c, channel, days, trend)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to get the stats of channel '%s': %s", channel.Name, err))
+			continue
+		}
+		printer.PrintT(`{{.Name}}: {{.Members}} members, {{.Posts}} posts and {{.ActiveMembers}} active members in the last {{.Days}} days
+{{- range .Trend}}
+  {{.String}}
+{{- end}}`, row)
+	}
+
+	return nil
+}
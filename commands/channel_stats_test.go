@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelStatsCmd() {
+	newCmd := func(trend bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("days", 7, "")
+		cmd.Flags().Bool("trend", trend, "")
+		return cmd
+	}
+
+	channel := &model.Channel{Id: "channel-id", Name: "town-square"}
+	day := int64(24 * time.Hour / time.Millisecond)
+
+	s.Run("Should compare the activity with the previous period", func() {
+		printer.Clean()
+		now := model.GetMillis()
+
+		postList := model.NewPostList()
+		addPost := func(post *model.Post) {
+			postList.AddPost(post)
+			postList.AddOrder(post.Id)
+		}
+		addPost(&model.Post{Id: "p1", UserId: "john", CreateAt: now - day})
+		addPost(&model.Post{Id: "p2", UserId: "john", CreateAt: now - 2*day})
+		addPost(&model.Post{Id: "p3", UserId: "jane", CreateAt: now - 3*day})
+		addPost(&model.Post{Id: "p4", UserId: "john", CreateAt: now - 3*day, Type: model.PostTypeJoinChannel})
+		addPost(&model.Post{Id: "p5", UserId: "john", CreateAt: now - 10*day})
+		addPost(&model.Post{Id: "p6", UserId: "john", CreateAt: now - 11*day})
+		addPost(&model.Post{Id: "p7", UserId: "mary", CreateAt: now - 20*day})
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelStats(channel.Id, "").
+			Return(&model.ChannelStats{ChannelId: channel.Id, MemberCount: 12}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		err := channelStatsCmdF(s.client, newCmd(true), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		postsChange := 50.0
+		activeChange := 100.0
+		s.Require().Equal([]interface{}{
+			&channelStatsRow{
+				ChannelID:     channel.Id,
+				Name:          channel.Name,
+				Days:          7,
+				Members:       12,
+				Posts:         3,
+				ActiveMembers: 2,
+				Trend: []*statsTrend{
+					{Metric: "posts", Current: 3, Previous: 2, Change: &postsChange},
+					{Metric: "active_members", Current: 2, Previous: 1, Change: &activeChange},
+				},
+			},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should report the channels whose stats can't be retrieved", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelStats(channel.Id, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := channelStatsCmdF(s.client, newCmd(false), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal("Unable to get the stats of channel 'town-square': failed to get the channel stats: mock error", printer.GetErrorLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestStatsTrendString() {
+	s.Require().Equal("posts 3 (previous 2, +50.0%)", newStatsTrend("posts", 3, 2).String())
+	s.Require().Equal("posts 1 (previous 4, -75.0%)", newStatsTrend("posts", 1, 4).String())
+	s.Require().Equal("posts 3 (previous 0, n/a)", newStatsTrend("posts", 3, 0).String())
+}
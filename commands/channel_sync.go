@@ -0,0 +1,194 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelSyncFromTeamCmd = &cobra.Command{
+	Use:   "sync-from-team [channels]",
+	Short: "Add the members of the team to channels",
+	Long: `Add the active members of the team of each channel that aren't members of the channel yet, so the membership of the channel matches the one of its team. Only the missing members are added, so the command can be run again to keep the channel in sync.
+With --include-new-members, the channel is also added to the default channels of the server, which makes the server add the users to it as they join the team. This changes the TeamSettings.ExperimentalDefaultChannels setting of the server config, which applies to all the teams: the default channels are set by name, so the users joining any team with a channel of that name are added to it. The change is confirmed before the config is patched, unless --confirm is set.`,
+	Example: `  channel sync-from-team myteam:all-hands
+
+  # check which users would be added
+  channel sync-from-team myteam:all-hands --dry-run
+
+  # also add the users that join the team from now on
+  channel sync-from-team myteam:all-hands --include-new-members`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(channelSyncFromTeamCmdF),
+}
+
+func init() {
+	ChannelSyncFromTeamCmd.Flags().Bool("include-new-members", false, "Add the channel names to the server-wide default channels of the config, so the users joining any team with those channels are added to them")
+	ChannelSyncFromTeamCmd.Flags().Bool("confirm", false, "Confirm you really want to change the default channels of the server")
+	ChannelSyncFromTeamCmd.Flags().Bool("dry-run", false, "Report the users that would be added without adding them")
+
+	ChannelCmd.AddCommand(ChannelSyncFromTeamCmd)
+}
+
+type channelSyncResult struct {
+	Channel  string `json:"channel"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id"`
+}
+
+// getMissingChannelMembers returns the active members of the team of
+// the channel that aren't members of the channel
+func getMissingChannelMembers(c client.Client, channel *model.Channel) ([]*model.User, error) {
+	members := map[string]bool{}
+	for page := 0; ; page++ {
+		channelMembers, _, err := c.GetChannelMembers(channel.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the members of the channel")
+		}
+		for _, member := range channelMembers {
+			members[member.UserId] = true
+		}
+		if len(channelMembers) < APILimitMaximum {
+			break
+		}
+	}
+
+	missing := []*model.User{}
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsersInTeam(channel.TeamId, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the members of the team")
+		}
+		for _, user := range users {
+			if user.DeleteAt == 0 && !members[user.Id] {
+				missing = append(missing, user)
+			}
+		}
+		if len(users) < APILimitMaximum {
+			break
+		}
+	}
+
+	return missing, nil
+}
+
+// addDefaultChannels adds the channel names to the default channels
+// of the server, patching the config only if any of them is missing
+// and the change is confirmed
+func addDefaultChannels(c client.Client, names []string, dryRun, confirmFlag bool) error {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the server config")
+	}
+
+	defaultChannels := map[string]bool{}
+	for _, name := range config.TeamSettings.ExperimentalDefaultChannels {
+		defaultChannels[name] = true
+	}
+
+	added := []string{}
+	for _, name := range names {
+		if defaultChannels[name] {
+			printer.Print(fmt.Sprintf("Channel %s is already a default channel", name))
+			continue
+		}
+		defaultChannels[name] = true
+		added = append(added, name)
+	}
+	if len(added) == 0 || dryRun {
+		for _, name := range added {
+			printer.Print(fmt.Sprintf("Channel %s would be added to the default channels", name))
+		}
+		return nil
+	}
+
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to add %s to the default channels of the server? The users joining any team with a channel of these names will be added to it", strings.Join(added, ", ")), false); err != nil {
+			return err
+		}
+	}
+
+	config.TeamSettings.ExperimentalDefaultChannels = append(config.TeamSettings.ExperimentalDefaultChannels, added...)
+	if _, _, err := c.PatchConfig(config); err != nil {
+		return errors.Wrap(err, "unable to update the server config")
+	}
+	for _, name := range added {
+		printer.Print(fmt.Sprintf("Channel %s added to the default channels", name))
+	}
+
+	return nil
+}
+
+func channelSyncFromTeamCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	includeNewMembers, _ := cmd.Flags().GetBool("include-new-members")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	failed := 0
+	names := []string{}
+	channels := getChannelsFromChannelArgs(c, args)
+	for i, channel := range channels {
+		if channel == nil {
+			printer.PrintError(fmt.Sprintf("unable to find channel %q", args[i]))
+			failed++
+			continue
+		}
+		if channel.TeamId == "" {
+			printer.PrintError(fmt.Sprintf("channel %q doesn't belong to a team", args[i]))
+			failed++
+			continue
+		}
+		if channel.DeleteAt > 0 {
+			printer.PrintError(fmt.Sprintf("channel %q is archived", args[i]))
+			failed++
+			continue
+		}
+
+		users, err := getMissingChannelMembers(c, channel)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to sync channel %q: %s", args[i], err))
+			failed++
+			continue
+		}
+
+		for _, user := range users {
+			result := &channelSyncResult{Channel: channel.Name, Username: user.Username, UserID: user.Id}
+			if dryRun {
+				printer.PrintT("User {{.Username}} would be added to {{.Channel}}", result)
+				continue
+			}
+			if _, _, err := c.AddChannelMember(channel.Id, user.Id); err != nil {
+				printer.PrintError(fmt.Sprintf("unable to add user %q to channel %q: %s", user.Username, channel.Name, err))
+				failed++
+				continue
+			}
+			printer.PrintT("User {{.Username}} added to {{.Channel}}", result)
+		}
+		if len(users) == 0 {
+			printer.Print(fmt.Sprintf("Channel %s is in sync with its team", channel.Name))
+		}
+
+		names = append(names, channel.Name)
+	}
+
+	if includeNewMembers && len(names) > 0 {
+		if err := addDefaultChannels(c, names, dryRun, confirmFlag); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to sync %d channels or users", failed)
+	}
+
+	return nil
+}
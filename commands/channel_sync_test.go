@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelSyncFromTeamCmd() {
+	newCmd := func(includeNewMembers, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("include-new-members", includeNewMembers, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	channel := &model.Channel{Id: "channel-id", Name: "all-hands", TeamId: "team-id"}
+	john := &model.User{Id: "john-id", Username: "john"}
+	jane := &model.User{Id: "jane-id", Username: "jane"}
+	deactivated := &model.User{Id: "deactivated-id", Username: "deactivated", DeleteAt: 1}
+
+	expectMembers := func() {
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{ChannelId: channel.Id, UserId: john.Id}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(channel.TeamId, 0, APILimitMaximum, "").
+			Return([]*model.User{john, jane, deactivated}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should add the missing active members of the team", func() {
+		printer.Clean()
+		expectMembers()
+
+		s.client.
+			EXPECT().
+			AddChannelMember(channel.Id, jane.Id).
+			Return(&model.ChannelMember{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSyncFromTeamCmdF(s.client, newCmd(false, false), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{&channelSyncResult{Channel: channel.Name, Username: jane.Username, UserID: jane.Id}}, printer.GetLines())
+	})
+
+	s.Run("Should only report the missing members with dry run", func() {
+		printer.Clean()
+		expectMembers()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(&model.Config{TeamSettings: model.TeamSettings{ExperimentalDefaultChannels: []string{}}}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSyncFromTeamCmdF(s.client, newCmd(true, true), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&channelSyncResult{Channel: channel.Name, Username: jane.Username, UserID: jane.Id},
+			"Channel all-hands would be added to the default channels",
+		}, printer.GetLines())
+	})
+
+	s.Run("Should add the channel to the default channels once", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{ChannelId: channel.Id, UserId: john.Id}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(channel.TeamId, 0, APILimitMaximum, "").
+			Return([]*model.User{john}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(&model.Config{TeamSettings: model.TeamSettings{ExperimentalDefaultChannels: []string{"announcements"}}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(&model.Config{TeamSettings: model.TeamSettings{ExperimentalDefaultChannels: []string{"announcements", "all-hands"}}}).
+			Return(&model.Config{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelSyncFromTeamCmdF(s.client, newCmd(true, false), []string{channel.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			"Channel all-hands is in sync with its team",
+			"Channel all-hands added to the default channels",
+		}, printer.GetLines())
+	})
+
+	s.Run("Should not change the default channels without a confirmation", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{ChannelId: channel.Id, UserId: john.Id}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(channel.TeamId, 0, APILimitMaximum, "").
+			Return([]*model.User{john}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(&model.Config{TeamSettings: model.TeamSettings{ExperimentalDefaultChannels: []string{}}}, &model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd(true, false)
+		_ = cmd.Flags().Set("confirm", "false")
+		err := channelSyncFromTeamCmdF(s.client, cmd, []string{channel.Id})
+		s.Require().EqualError(err, "could not proceed, either enable --confirm flag or use an interactive shell to complete operation: this is not an interactive shell")
+	})
+
+	s.Run("Should report the users that can't be added", func() {
+		printer.Clean()
+		expectMembers()
+
+		s.client.
+			EXPECT().
+			AddChannelMember(channel.Id, jane.Id).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := channelSyncFromTeamCmdF(s.client, newCmd(false, false), []string{channel.Id})
+		s.Require().EqualError(err, "unable to sync 1 channels or users")
+		s.Require().Equal([]interface{}{`unable to add user "jane" to channel "all-hands": mock error`}, printer.GetErrorLines())
+	})
+}
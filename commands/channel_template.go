@@ -0,0 +1,207 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func init() {
+	ChannelCreateCmd.Flags().String("from-template", "", "Path of a YAML template with the header, purpose, category, members, bookmarks and welcome post of the channel")
+}
+
+// channelTemplate is the YAML template that channels can be created
+// from. The header, purpose and welcome post are Go templates that
+// can use the Name, DisplayName and Team of the channel
+type channelTemplate struct {
+	Header      string                     `yaml:"header"`
+	Purpose     string                     `yaml:"purpose"`
+	Private     bool                       `yaml:"private"`
+	Category    string                     `yaml:"category"`
+	Members     []string                   `yaml:"members"`
+	Bookmarks   []*channelTemplateBookmark `yaml:"bookmarks"`
+	WelcomePost string                     `yaml:"welcome_post"`
+}
+
+type channelTemplateBookmark struct {
+	Title string `yaml:"title"`
+	URL   string `yaml:"url"`
+}
+
+func loadChannelTemplate(path string) (*channelTemplate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the channel template")
+	}
+	defer f.Close()
+
+	var t channelTemplate
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&t); err != nil {
+		return nil, errors.Wrapf(err, "invalid channel template %s", path)
+	}
+	for i, bookmark := range t.Bookmarks {
+		if bookmark.URL == "" {
+			return nil, fmt.Errorf("invalid channel template %s: bookmark %d has no url", path, i+1)
+		}
+	}
+	return &t, nil
+}
+
+// render expands the Go templates of the texts of the template for the
+// channel
+func (t *channelTemplate) render(channel *model.Channel, team *model.Team) error {
+	data := struct {
+		Name        string
+		DisplayName string
+		Team        string
+	}{channel.Name, channel.DisplayName, team.Name}
+
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"header", &t.Header},
+		{"purpose", &t.Purpose},
+		{"welcome_post", &t.WelcomePost},
+	} {
+		tpl, err := template.New(field.name).Parse(*field.value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s in the channel template", field.name)
+		}
+		var b bytes.Buffer
+		if err := tpl.Execute(&b, data); err != nil {
+			return errors.Wrapf(err, "invalid %s in the channel template", field.name)
+		}
+		*field.value = b.String()
+	}
+	return nil
+}
+
+// welcomeMessage returns the message of the welcome post, listing the
+// bookmarks after it, as the server API has no channel bookmarks
+func (t *channelTemplate) welcomeMessage() string {
+	message := strings.TrimSpace(t.WelcomePost)
+	if len(t.Bookmarks) == 0 {
+		return message
+	}
+
+	lines := []string{}
+	if message != "" {
+		lines = append(lines, message, "")
+	}
+	lines = append(lines, "**Bookmarks**")
+	for _, bookmark := range t.Bookmarks {
+		title := bookmark.Title
+		if title == "" {
+			title = bookmark.URL
+		}
+		lines = append(lines, fmt.Sprintf("- [%s](%s)", title, bookmark.URL))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyTo sets the fields of the channel that the template has, unless
+// they were given as flags
+func (t *channelTemplate) applyTo(cmd *cobra.Command, channel *model.Channel) {
+	if !cmd.Flags().Changed("header") && t.Header != "" {
+		channel.Header = t.Header
+	}
+	if !cmd.Flags().Changed("purpose") && t.Purpose != "" {
+		channel.Purpose = t.Purpose
+	}
+	if !cmd.Flags().Changed("private") && t.Private {
+		channel.Type = model.ChannelTypePrivate
+	}
+}
+
+// placeChannelInCategory moves the channel to the custom sidebar
+// category of the user with the given name, creating it if needed
+func placeChannelInCategory(c client.Client, userID, teamID, channelID, name string) error {
+	categories, _, err := c.GetSidebarCategoriesForTeamForUser(userID, teamID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories.Categories {
+		if category.Type != model.SidebarCategoryCustom || category.DisplayName != name {
+			continue
+		}
+		category.Channels = append(category.Channels, channelID)
+		_, _, err := c.UpdateSidebarCategoryForTeamForUser(userID, teamID, category.Id, category)
+		return err
+	}
+
+	_, _, err = c.CreateSidebarCategoryForTeamForUser(userID, teamID, &model.SidebarCategoryWithChannels{
+		SidebarCategory: model.SidebarCategory{
+			UserId:      userID,
+			TeamId:      teamID,
+			DisplayName: name,
+			Type:        model.SidebarCategoryCustom,
+		},
+		Channels: []string{channelID},
+	})
+	return err
+}
+
+// applyChannelTemplate adds the members, category and welcome post of
+// the template to the created channel, reporting each failed step and
+// carrying on with the rest
+func applyChannelTemplate(c client.Client, t *channelTemplate, channel *model.Channel) error {
+	failed := 0
+
+	members := []*model.User{}
+	for _, memberArg := range t.Members {
+		user := getUserFromUserArg(c, memberArg)
+		if user == nil {
+			printer.PrintError(fmt.Sprintf("unable to find user %q", memberArg))
+			failed++
+			continue
+		}
+		if _, _, err := c.AddChannelMember(channel.Id, user.Id); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to add user %q to channel %s: %s", memberArg, channel.Name, err))
+			failed++
+			continue
+		}
+		members = append(members, user)
+	}
+
+	if t.Category != "" {
+		for _, user := range members {
+			if err := placeChannelInCategory(c, user.Id, channel.TeamId, channel.Id, t.Category); err != nil {
+				printer.PrintError(fmt.Sprintf("unable to place channel %s in category %q of user %s: %s", channel.Name, t.Category, user.Username, err))
+				failed++
+			}
+		}
+	}
+
+	if message := t.welcomeMessage(); message != "" {
+		post, _, err := c.CreatePost(&model.Post{ChannelId: channel.Id, Message: message})
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to create the welcome post of channel %s: %s", channel.Name, err))
+			failed++
+		} else if _, err := c.PinPost(post.Id); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to pin the welcome post of channel %s: %s", channel.Name, err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("channel %s created, but %d steps of the template failed", channel.Name, failed)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestCreateChannelFromTemplateCmd() {
+	writeTemplate := func(content string) (string, func()) {
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(tmp, "template.yaml")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path, func() { os.RemoveAll(tmp) }
+	}
+
+	newCmd := func(path string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "team-id", "")
+		cmd.Flags().String("name", "project-x", "")
+		cmd.Flags().String("display-name", "Project X", "")
+		cmd.Flags().String("header", "", "")
+		cmd.Flags().String("purpose", "", "")
+		cmd.Flags().Bool("private", false, "")
+		cmd.Flags().String("from-template", path, "")
+		return cmd
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	john := &model.User{Id: "john-id", Username: "john"}
+
+	s.Run("Should create the channel and apply the template", func() {
+		printer.Clean()
+		path, cleanup := writeTemplate(`header: "{{.DisplayName}} updates"
+purpose: Coordination of the project
+private: true
+category: Projects
+members:
+  - john
+  - unknown
+bookmarks:
+  - title: Roadmap
+    url: https://example.com/roadmap
+welcome_post: Welcome to {{.DisplayName}} in {{.Team}}!
+`)
+		defer cleanup()
+		cmd := newCmd(path)
+		_ = cmd.Flags().Set("purpose", "Custom purpose")
+
+		channel := &model.Channel{
+			TeamId:      team.Id,
+			Name:        "project-x",
+			DisplayName: "Project X",
+			Header:      "Project X updates",
+			Purpose:     "Custom purpose",
+			Type:        model.ChannelTypePrivate,
+		}
+		created := &model.Channel{Id: "channel-id", TeamId: team.Id, Name: "project-x"}
+		category := &model.SidebarCategoryWithChannels{
+			SidebarCategory: model.SidebarCategory{Id: "category-id", DisplayName: "Projects", Type: model.SidebarCategoryCustom},
+			Channels:        []string{"other-channel-id"},
+		}
+		post := &model.Post{ChannelId: created.Id, Message: "Welcome to Project X in myteam!\n\n**Bookmarks**\n- [Roadmap](https://example.com/roadmap)"}
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateChannel(channel).
+			Return(created, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("john", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("john", "").
+			Return(john, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddChannelMember(created.Id, john.Id).
+			Return(&model.ChannelMember{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSidebarCategoriesForTeamForUser(john.Id, team.Id, "").
+			Return(&model.OrderedSidebarCategories{Categories: model.SidebarCategoriesWithChannels{category}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateSidebarCategoryForTeamForUser(john.Id, team.Id, category.Id, &model.SidebarCategoryWithChannels{
+				SidebarCategory: category.SidebarCategory,
+				Channels:        []string{"other-channel-id", created.Id},
+			}).
+			Return(category, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreatePost(post).
+			Return(&model.Post{Id: "post-id"}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PinPost("post-id").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := createChannelCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "channel project-x created, but 1 steps of the template failed")
+		s.Require().Equal([]interface{}{created}, printer.GetLines())
+		s.Require().Equal([]interface{}{`unable to find user "unknown"`}, printer.GetErrorLines())
+	})
+
+	s.Run("Should create the sidebar category if it doesn't exist", func() {
+		s.client.
+			EXPECT().
+			GetSidebarCategoriesForTeamForUser(john.Id, team.Id, "").
+			Return(&model.OrderedSidebarCategories{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateSidebarCategoryForTeamForUser(john.Id, team.Id, &model.SidebarCategoryWithChannels{
+				SidebarCategory: model.SidebarCategory{UserId: john.Id, TeamId: team.Id, DisplayName: "Projects", Type: model.SidebarCategoryCustom},
+				Channels:        []string{"channel-id"},
+			}).
+			Return(&model.SidebarCategoryWithChannels{}, &model.Response{}, nil).
+			Times(1)
+
+		err := placeChannelInCategory(s.client, john.Id, team.Id, "channel-id", "Projects")
+		s.Require().NoError(err)
+	})
+
+	s.Run("Should fail with unknown fields in the template", func() {
+		path, cleanup := writeTemplate("header: hello\npinned_post: welcome\n")
+		defer cleanup()
+
+		err := createChannelCmdF(s.client, newCmd(path), []string{})
+		s.Require().Error(err)
+		s.Require().True(strings.HasPrefix(err.Error(), "invalid channel template"))
+	})
+}
@@ -2447,6 +2447,18 @@ func (s *MmctlUnitTestSuite) TestMoveChannelCmdF() {
 			Return(&mockChannel, &model.Response{}, nil).
 			Times(1)
 
+		s.client.
+			EXPECT().
+			GetUsersInTeam(mockTeam1.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(mockChannel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{}, &model.Response{}, nil).
+			Times(1)
+
 		s.client.
 			EXPECT().
 			MoveChannel(mockChannel.Id, mockTeam1.Id, false).
@@ -2540,6 +2552,18 @@ func (s *MmctlUnitTestSuite) TestMoveChannelCmdF() {
 			Return(&model.Channel{Id: channelID, Name: "some-name"}, &model.Response{}, nil).
 			Times(1)
 
+		s.client.
+			EXPECT().
+			GetUsersInTeam(mockTeam1.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channelID, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{}, &model.Response{}, nil).
+			Times(1)
+
 		s.client.
 			EXPECT().
 			MoveChannel(channelID, mockTeam1.Id, false).
@@ -2551,6 +2575,123 @@ func (s *MmctlUnitTestSuite) TestMoveChannelCmdF() {
 		s.Len(printer.GetErrorLines(), 1)
 		s.Contains(printer.GetErrorLines()[0], fmt.Sprintf("unable to move channel %q: ", "some-name"))
 	})
+
+	s.Run("Should report the members missing from the team and not move the channel", func() {
+		printer.Clean()
+
+		team := &model.Team{Id: model.NewId(), Name: "newteam"}
+		channel := &model.Channel{Id: model.NewId(), Name: "town-square", TeamId: model.NewId()}
+		member := &model.User{Id: model.NewId(), Username: "john.doe"}
+		missing := &model.User{Id: model.NewId(), Username: "jane.doe"}
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{member}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{UserId: member.Id}, {UserId: missing.Id}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{missing.Id}).
+			Return([]*model.User{missing}, &model.Response{}, nil).
+			Times(1)
+
+		err := moveChannelCmdF(s.client, &cobra.Command{}, []string{team.Id, channel.Id})
+		s.Require().Nil(err)
+		s.Require().Equal([]interface{}{
+			&channelMoveMember{Channel: channel.Name, Team: team.Name, Username: missing.Username, UserID: missing.Id, Action: channelMoveMemberMissing},
+		}, printer.GetLines())
+		s.Require().Equal([]interface{}{`unable to move channel "town-square": 1 members of the channel are not members of team "newteam", use --add-missing-members to add them to the team or --force to remove them from the channel`}, printer.GetErrorLines())
+	})
+
+	s.Run("Should add the missing members to the team before moving the channel", func() {
+		printer.Clean()
+
+		team := &model.Team{Id: model.NewId(), Name: "newteam"}
+		channel := &model.Channel{Id: model.NewId(), Name: "town-square", TeamId: model.NewId()}
+		missing := &model.User{Id: model.NewId(), Username: "jane.doe"}
+		movedChannel := &model.Channel{Id: channel.Id, Name: channel.Name, TeamId: team.Id}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("add-missing-members", true, "")
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembers(channel.Id, 0, APILimitMaximum, "").
+			Return(model.ChannelMembers{{UserId: missing.Id}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{missing.Id}).
+			Return([]*model.User{missing}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddTeamMember(team.Id, missing.Id).
+			Return(&model.TeamMember{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			MoveChannel(channel.Id, team.Id, false).
+			Return(movedChannel, &model.Response{}, nil).
+			Times(1)
+
+		err := moveChannelCmdF(s.client, cmd, []string{team.Id, channel.Id})
+		s.Require().Nil(err)
+		s.Require().Equal([]interface{}{
+			&channelMoveMember{Channel: channel.Name, Team: team.Name, Username: missing.Username, UserID: missing.Id, Action: channelMoveMemberAdded},
+			movedChannel,
+		}, printer.GetLines())
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Should fail with both --force and --add-missing-members", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("force", true, "")
+		cmd.Flags().Bool("add-missing-members", true, "")
+
+		err := moveChannelCmdF(s.client, cmd, []string{"newteam", "oldteam:town-square"})
+		s.Require().EqualError(err, "--force and --add-missing-members can't be used together")
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestCreateChannelCmd() {
@@ -13,29 +13,42 @@ import (
 )
 
 var ChannelUsersCmd = &cobra.Command{
-	Use:   "users",
-	Short: "Management of channel users",
+	Use:     "users",
+	Aliases: []string{"members"},
+	Short:   "Management of channel users",
 }
 
 var ChannelUsersAddCmd = &cobra.Command{
-	Use:     "add [channel] [users]",
-	Short:   "Add users to channel",
-	Long:    "Add some users to channel",
-	Example: "  channel users add myteam:mychannel user@example.com username",
-	RunE:    withClient(channelUsersAddCmdF),
+	Use:   "add [channel] [users]",
+	Short: "Add users to channel",
+	Long: `Add some users to channel.
+With --file, the users are also read from a file containing one email or username per line, or from the standard input if the file is "-", and a summary of the users added and the ones that failed is printed.`,
+	Example: `  channel users add myteam:mychannel user@example.com username
+  channel users add myteam:mychannel --file members.txt`,
+	RunE: withClient(channelUsersAddCmdF),
 }
 
 var ChannelUsersRemoveCmd = &cobra.Command{
 	Use:   "remove [channel] [users]",
 	Short: "Remove users from channel",
-	Long:  "Remove some users from channel",
+	Long: `Remove some users from channel.
+With --file, the users are also read from a file containing one email or username per line, or from the standard input if the file is "-", and a summary of the users removed and the ones that failed is printed.
+With --plan, the users that would be removed are printed as a plan that can be reviewed and then applied with --apply-plan.`,
 	Example: `  channel users remove myteam:mychannel user@example.com username
-  channel users remove myteam:mychannel --all-users`,
-	RunE: withClient(channelUsersRemoveCmdF),
+  channel users remove myteam:mychannel --all-users
+  cat leavers.txt | channel users remove myteam:mychannel --file -
+
+  # review the users before removing them
+  channel users remove myteam:mychannel --all-users --plan > plan.json
+  channel users remove --apply-plan plan.json`,
+	RunE: withClient(withPlanActions(channelUsersRemovePlanResolver, applyChannelUsersRemovePlan, channelUsersRemoveCmdF)),
 }
 
 func init() {
+	ChannelUsersAddCmd.Flags().String("file", "", "File containing the users to add, one per line, or - to read them from the standard input")
 	ChannelUsersRemoveCmd.Flags().Bool("all-users", false, "Remove all users from the indicated channel.")
+	ChannelUsersRemoveCmd.Flags().String("file", "", "File containing the users to remove, one per line, or - to read them from the standard input")
+	addPlanFlags(ChannelUsersRemoveCmd)
 
 	ChannelUsersCmd.AddCommand(
 		ChannelUsersAddCmd,
@@ -45,8 +58,37 @@ func init() {
 	ChannelCmd.AddCommand(ChannelUsersCmd)
 }
 
+type channelUsersSummary struct {
+	Channel   string `json:"channel"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+}
+
+// getChannelUserArgs returns the users given as arguments followed by
+// the ones of the --file flag, if set
+func getChannelUserArgs(cmd *cobra.Command, userArgs []string) ([]string, bool, error) {
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		return userArgs, false, nil
+	}
+
+	fileUsers, err := readUsersFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(append([]string{}, userArgs...), fileUsers...), true, nil
+}
+
 func channelUsersAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
-	if len(args) < 2 {
+	if len(args) < 1 {
+		return errors.New("not enough arguments")
+	}
+
+	userArgs, fromFile, err := getChannelUserArgs(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(userArgs) == 0 {
 		return errors.New("not enough arguments")
 	}
 
@@ -55,32 +97,53 @@ func channelUsersAddCmdF(c client.Client, cmd *cobra.Command, args []string) err
 		return errors.Errorf("unable to find channel %q", args[0])
 	}
 
-	users := getUsersFromUserArgs(c, args[1:])
+	summary := &channelUsersSummary{Channel: channel.Name}
+	users := getUsersFromUserArgs(c, userArgs)
 	for i, user := range users {
-		addUserToChannel(c, channel, user, args[i+1])
+		if addUserToChannel(c, channel, user, userArgs[i]) {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	if fromFile {
+		printer.PrintT("{{.Succeeded}} users added to {{.Channel}}, {{.Failed}} failed", summary)
 	}
 
 	return nil
 }
 
-func addUserToChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) {
+func addUserToChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) bool {
 	if user == nil {
 		printer.PrintError("Can't find user '" + userArg + "'")
-		return
+		return false
 	}
 	if _, _, err := c.AddChannelMember(channel.Id, user.Id); err != nil {
 		printer.PrintError("Unable to add '" + userArg + "' to " + channel.Name + ". Error: " + err.Error())
+		return false
 	}
+	return true
 }
 
 func channelUsersRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	allUsers, _ := cmd.Flags().GetBool("all-users")
 
-	if allUsers && len(args) != 1 {
+	path, _ := cmd.Flags().GetString("file")
+
+	if allUsers && (len(args) != 1 || path != "") {
 		return errors.New("individual users must not be specified in conjunction with the --all-users flag")
 	}
 
-	if !allUsers && len(args) < 2 {
+	if !allUsers && len(args) < 2 && path == "" {
+		return errors.New("you must specify some users to remove from the channel, or use the --all-users flag to remove them all")
+	}
+
+	userArgs, fromFile, err := getChannelUserArgs(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+	if !allUsers && len(userArgs) == 0 {
 		return errors.New("you must specify some users to remove from the channel, or use the --all-users flag to remove them all")
 	}
 
@@ -91,23 +154,107 @@ func channelUsersRemoveCmdF(c client.Client, cmd *cobra.Command, args []string)
 
 	if allUsers {
 		removeAllUsersFromChannel(c, channel)
-	} else {
-		for i, user := range getUsersFromUserArgs(c, args[1:]) {
-			removeUserFromChannel(c, channel, user, args[i+1])
+		return nil
+	}
+
+	summary := &channelUsersSummary{Channel: channel.Name}
+	for i, user := range getUsersFromUserArgs(c, userArgs) {
+		if removeUserFromChannel(c, channel, user, userArgs[i]) {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
 		}
 	}
 
+	if fromFile {
+		printer.PrintT("{{.Succeeded}} users removed from {{.Channel}}, {{.Failed}} failed", summary)
+	}
+
+	return nil
+}
+
+func channelUsersRemovePlanResolver(c client.Client, cmd *cobra.Command, args []string) ([]planAction, error) {
+	allUsers, _ := cmd.Flags().GetBool("all-users")
+	if len(args) < 1 {
+		return nil, errors.New("not enough arguments")
+	}
+	userArgs, _, err := getChannelUserArgs(cmd, args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if allUsers == (len(userArgs) > 0) {
+		return nil, errors.New("either some users or the --all-users flag must be provided")
+	}
+
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return nil, errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	var users []*model.User
+	if allUsers {
+		members, _, membersErr := c.GetChannelMembers(channel.Id, 0, 10000, "")
+		if membersErr != nil {
+			return nil, errors.Wrapf(membersErr, "unable to get the members of %s", channel.Name)
+		}
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			ids = append(ids, member.UserId)
+		}
+		if len(ids) > 0 {
+			if users, _, err = c.GetUsersByIds(ids); err != nil {
+				return nil, errors.Wrapf(err, "unable to get the members of %s", channel.Name)
+			}
+		}
+	} else if users, err = getUsersFromArgs(c, userArgs); err != nil {
+		return nil, err
+	}
+
+	actions := []planAction{}
+	for _, user := range users {
+		actions = append(actions, planAction{
+			Action:     planActionRemoveChannelMember,
+			ID:         user.Id,
+			Name:       user.Username,
+			TargetID:   channel.Id,
+			TargetName: channel.Name,
+		})
+	}
+	return actions, nil
+}
+
+func applyChannelUsersRemovePlan(c client.Client, _ *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, planActionRemoveChannelMember); err != nil {
+		return err
+	}
+
+	summary := &channelUsersSummary{Channel: actions[0].TargetName}
+	for _, action := range actions {
+		if _, err := c.RemoveUserFromChannel(action.TargetID, action.ID); err != nil {
+			printer.PrintError("Unable to remove '" + action.Name + "' from " + action.TargetName + ". Error: " + err.Error())
+			summary.Failed++
+			continue
+		}
+		summary.Succeeded++
+	}
+	printer.PrintT("{{.Succeeded}} users removed from {{.Channel}}, {{.Failed}} failed", summary)
+
+	if summary.Failed > 0 {
+		return errors.Errorf("unable to remove %d users", summary.Failed)
+	}
 	return nil
 }
 
-func removeUserFromChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) {
+func removeUserFromChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) bool {
 	if user == nil {
 		printer.PrintError("Can't find user '" + userArg + "'")
-		return
+		return false
 	}
 	if _, err := c.RemoveUserFromChannel(channel.Id, user.Id); err != nil {
 		printer.PrintError("Unable to remove '" + userArg + "' from " + channel.Name + ". Error: " + err.Error())
+		return false
 	}
+	return true
 }
 
 func removeAllUsersFromChannel(c client.Client, channel *model.Channel) {
@@ -5,7 +5,11 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/pkg/errors"
@@ -196,6 +200,64 @@ func (s *MmctlUnitTestSuite) TestChannelUsersAddCmdF() {
 		s.Equal("Unable to add '"+userEmail+"' to "+channelName+". Error: mock error",
 			printer.GetErrorLines()[0])
 	})
+
+	s.Run("Add the users of a file and print a summary", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "members.txt")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(userEmail+"\n\nunknown\n"), 0600))
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(&mockTeam, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channelName, teamID, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(userEmail, "").
+			Return(&mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddChannelMember(channelID, userID).
+			Return(&model.ChannelMember{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelUsersAddCmdF(s.client, cmd, []string{channelArg})
+		s.Require().Nil(err)
+		s.Require().Equal([]interface{}{&channelUsersSummary{Channel: channelName, Succeeded: 1, Failed: 1}}, printer.GetLines())
+		s.Require().Equal([]interface{}{"Can't find user 'unknown'"}, printer.GetErrorLines())
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestChannelUsersRemoveCmd() {
@@ -386,4 +448,46 @@ func (s *MmctlUnitTestSuite) TestChannelUsersRemoveCmd() {
 		s.Require().Nil(err)
 		s.Require().Len(printer.GetLines(), 0)
 	})
+
+	s.Run("should remove the users read from the standard input", func() {
+		printer.Clean()
+		original := stdinArgsReader
+		defer func() { stdinArgsReader = original }()
+		stdinArgsReader = strings.NewReader(userEmail + "\n")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all-users", false, "")
+		cmd.Flags().String("file", "-", "")
+
+		foundTeam := &model.Team{Id: teamID, Name: teamName}
+		foundChannel := &model.Channel{Id: channelID, Name: channelName}
+
+		s.client.
+			EXPECT().
+			GetTeam(teamName, "").
+			Return(foundTeam, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channelName, foundTeam.Id, "").
+			Return(foundChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(userEmail, "").
+			Return(&mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveUserFromChannel(foundChannel.Id, mockUser.Id).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := channelUsersRemoveCmdF(s.client, cmd, []string{argsTeamChannel})
+		s.Require().Nil(err)
+		s.Require().Equal([]interface{}{&channelUsersSummary{Channel: channelName, Succeeded: 1}}, printer.GetLines())
+	})
 }
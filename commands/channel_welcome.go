@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ChannelWelcomeMessageCmd = &cobra.Command{
+	Use:   "welcome-message",
+	Short: "Management of channel welcome messages",
+	Long: `Management of the messages shown to users when they join a channel.
+Channel props cannot be modified through the API, so welcome messages are managed through the welcomebot plugin, which needs to be installed and enabled on the server. The commands run the welcomebot slash commands in each of the given channels as the user mmctl is authenticated as.`,
+}
+
+var ChannelWelcomeMessageSetCmd = &cobra.Command{
+	Use:     "set [channels]",
+	Short:   "Set the welcome message of channels",
+	Long:    "Set the welcome message of the given channels, replacing the current one.",
+	Example: `  channel welcome-message set myteam:project-a myteam:project-b --message "Welcome! Please read the pinned posts."`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(channelWelcomeMessageSetCmdF),
+}
+
+var ChannelWelcomeMessageShowCmd = &cobra.Command{
+	Use:   "show [channels]",
+	Short: "Show the welcome message of channels",
+	Long: `Show the welcome message of the given channels.
+Welcomebot answers with an ephemeral message, which is displayed in the channel to the user mmctl is authenticated as, and printed here when the plugin also returns it in the command response.`,
+	Example: `  channel welcome-message show myteam:project-a`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(channelWelcomeMessageShowCmdF),
+}
+
+var ChannelWelcomeMessageRemoveCmd = &cobra.Command{
+	Use:     "remove [channels]",
+	Short:   "Remove the welcome message of channels",
+	Example: `  channel welcome-message remove myteam:project-a myteam:project-b`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(channelWelcomeMessageRemoveCmdF),
+}
+
+func init() {
+	ChannelWelcomeMessageSetCmd.Flags().StringP("message", "m", "", "Welcome message to set")
+	_ = ChannelWelcomeMessageSetCmd.MarkFlagRequired("message")
+
+	ChannelWelcomeMessageCmd.AddCommand(
+		ChannelWelcomeMessageSetCmd,
+		ChannelWelcomeMessageShowCmd,
+		ChannelWelcomeMessageRemoveCmd,
+	)
+	ChannelCmd.AddCommand(ChannelWelcomeMessageCmd)
+}
+
+type channelWelcomeMessageResult struct {
+	ID       string `json:"id"`
+	Channel  string `json:"channel"`
+	Response string `json:"response"`
+}
+
+// executeWelcomebotCommand runs the given welcomebot command in each
+// of the channels, printing an error for the ones where it fails
+func executeWelcomebotCommand(c client.Client, channelArgs []string, command, tmpl string) error {
+	failed := 0
+	channels := getChannelsFromChannelArgs(c, channelArgs)
+	for i, channel := range channels {
+		if channel == nil {
+			printer.PrintError(fmt.Sprintf("unable to find channel %q", channelArgs[i]))
+			failed++
+			continue
+		}
+
+		resp, _, err := c.ExecuteCommand(channel.Id, "/welcomebot "+command)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to run welcomebot in channel %q: %s", channel.Name, err))
+			failed++
+			continue
+		}
+
+		result := channelWelcomeMessageResult{ID: channel.Id, Channel: channel.Name}
+		if resp != nil {
+			result.Response = strings.TrimSpace(resp.Text)
+		}
+		printer.PrintT(tmpl, result)
+	}
+
+	if failed > 0 {
+		return errors.Errorf("the welcome message could not be managed in %d of %d channels", failed, len(channels))
+	}
+
+	return nil
+}
+
+func channelWelcomeMessageSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	message, _ := cmd.Flags().GetString("message")
+	if strings.TrimSpace(message) == "" {
+		return errors.New("the welcome message cannot be empty")
+	}
+
+	return executeWelcomebotCommand(c, args, "set_channel_welcome "+message, "{{.Channel}}: welcome message set")
+}
+
+func channelWelcomeMessageShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return executeWelcomebotCommand(c, args, "get_channel_welcome", "{{.Channel}}: {{if .Response}}{{.Response}}{{else}}sent as an ephemeral message{{end}}")
+}
+
+func channelWelcomeMessageRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return executeWelcomebotCommand(c, args, "delete_channel_welcome", "{{.Channel}}: welcome message removed")
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelWelcomeMessageCmds() {
+	channel1 := &model.Channel{Id: "channel-1", Name: "project-a"}
+	channel2 := &model.Channel{Id: "channel-2", Name: "project-b"}
+
+	s.Run("Should set the welcome message of every channel", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("message", "Welcome!", "")
+
+		s.client.
+			EXPECT().
+			GetChannel(channel1.Id, "").
+			Return(channel1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel2.Id, "").
+			Return(channel2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			ExecuteCommand(channel1.Id, "/welcomebot set_channel_welcome Welcome!").
+			Return(&model.CommandResponse{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			ExecuteCommand(channel2.Id, "/welcomebot set_channel_welcome Welcome!").
+			Return(&model.CommandResponse{}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelWelcomeMessageSetCmdF(s.client, cmd, []string{channel1.Id, channel2.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(channelWelcomeMessageResult{ID: channel1.Id, Channel: channel1.Name}, printer.GetLines()[0])
+	})
+
+	s.Run("Should fail to set an empty welcome message", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("message", " ", "")
+
+		err := channelWelcomeMessageSetCmdF(s.client, cmd, []string{channel1.Id})
+		s.Require().EqualError(err, "the welcome message cannot be empty")
+	})
+
+	s.Run("Should show the welcome message returned by the plugin", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel1.Id, "").
+			Return(channel1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			ExecuteCommand(channel1.Id, "/welcomebot get_channel_welcome").
+			Return(&model.CommandResponse{Text: "Welcome message is:\nWelcome!\n"}, &model.Response{}, nil).
+			Times(1)
+
+		err := channelWelcomeMessageShowCmdF(s.client, &cobra.Command{}, []string{channel1.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(channelWelcomeMessageResult{ID: channel1.Id, Channel: channel1.Name, Response: "Welcome message is:\nWelcome!"}, printer.GetLines()[0])
+	})
+
+	s.Run("Should report the channels where the message cannot be removed", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel1.Id, "").
+			Return(channel1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel("missing", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			ExecuteCommand(channel1.Id, "/welcomebot delete_channel_welcome").
+			Return(nil, &model.Response{}, errors.New("command not found")).
+			Times(1)
+
+		err := channelWelcomeMessageRemoveCmdF(s.client, &cobra.Command{}, []string{channel1.Id, "missing"})
+		s.Require().EqualError(err, "the welcome message could not be managed in 2 of 2 channels")
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 2)
+		s.Require().Equal(`unable to run welcomebot in channel "project-a": command not found`, printer.GetErrorLines()[0])
+		s.Require().Equal(`unable to find channel "missing"`, printer.GetErrorLines()[1])
+	})
+}
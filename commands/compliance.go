@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ComplianceCmd = &cobra.Command{
+	Use:   "compliance",
+	Short: "Compliance related utilities",
+}
+
+var CompliancePolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Analyze data retention policies",
+}
+
+var CompliancePolicySimulateCmd = &cobra.Command{
+	Use:   "simulate [policy-file]",
+	Short: "Simulate a data retention policy",
+	Long: `Reports how many posts and files would be deleted per channel if the given data retention policy was applied, without applying it.
+The policy file uses the same JSON format as the data retention API, with the "post_duration" in days and the "team_ids" and "channel_ids" the policy would cover.
+Every post of the affected channels needs to be read, so the command can take a while on channels with a long history.`,
+	Example: `  # policy.json
+  # {"display_name": "Short retention", "post_duration": 30, "team_ids": ["4xp9fdt77pncbef59f4k1qe83o"], "channel_ids": ["ppc1dn3ck3bqjdbmmbu4a5j3me"]}
+  $ mmctl compliance policy simulate policy.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(compliancePolicySimulateCmdF),
+}
+
+func init() {
+	CompliancePolicyCmd.AddCommand(
+		CompliancePolicySimulateCmd,
+	)
+	ComplianceCmd.AddCommand(
+		CompliancePolicyCmd,
+	)
+	RootCmd.AddCommand(ComplianceCmd)
+}
+
+type retentionSimulationResult struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	TeamID      string `json:"team_id"`
+	Posts       int64  `json:"posts"`
+	Files       int64  `json:"files"`
+}
+
+type retentionSimulationSummary struct {
+	Channels int   `json:"channels"`
+	Posts    int64 `json:"posts"`
+	Files    int64 `json:"files"`
+}
+
+func readRetentionPolicyFile(path string) (*model.RetentionPolicyWithTeamAndChannelIDs, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read policy file: %w", err)
+	}
+
+	var policy model.RetentionPolicyWithTeamAndChannelIDs
+	if err := json.Unmarshal(b, &policy); err != nil {
+		return nil, fmt.Errorf("cannot parse policy file: %w", err)
+	}
+
+	return &policy, nil
+}
+
+func compliancePolicySimulateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := readRetentionPolicyFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if policy.PostDurationDays == nil || *policy.PostDurationDays <= 0 {
+		return errors.New("the policy must define a positive post_duration")
+	}
+
+	if len(policy.TeamIDs) == 0 && len(policy.ChannelIDs) == 0 {
+		return errors.New("the policy must apply to at least one team or channel")
+	}
+
+	channels := getRetentionPolicyChannels(c, policy)
+	cutoff := model.GetMillisForTime(time.Now().AddDate(0, 0, -int(*policy.PostDurationDays)))
+
+	summary := retentionSimulationSummary{}
+	for _, channel := range channels {
+		result, err := simulateChannelRetention(c, channel, cutoff)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to analyze channel %q: %s", channel.Name, err))
+			continue
+		}
+
+		summary.Channels++
+		summary.Posts += result.Posts
+		summary.Files += result.Files
+		printer.PrintT("{{.ChannelName}} ({{.ChannelID}}): {{.Posts}} posts, {{.Files}} files would be deleted", result)
+	}
+
+	printer.PrintT("Total: {{.Posts}} posts, {{.Files}} files would be deleted across {{.Channels}} channels", summary)
+
+	return nil
+}
+
+// getRetentionPolicyChannels resolves the channels a policy applies
+// to, expanding the teams of the policy to all of their channels
+func getRetentionPolicyChannels(c client.Client, policy *model.RetentionPolicyWithTeamAndChannelIDs) []*model.Channel {
+	channels := []*model.Channel{}
+	seen := map[string]bool{}
+	appendChannel := func(channel *model.Channel) {
+		if seen[channel.Id] {
+			return
+		}
+		seen[channel.Id] = true
+		channels = append(channels, channel)
+	}
+
+	for _, teamID := range policy.TeamIDs {
+		team, _, err := c.GetTeam(teamID, "")
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to find team %q: %s", teamID, err))
+			continue
+		}
+
+		publicChannels, err := getAllPublicChannelsForTeam(c, team.Id)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to list public channels for %q: %s", team.Name, err))
+		}
+		privateChannels, err := getPrivateChannels(c, team.Id)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to list private channels for %q: %s", team.Name, err))
+		}
+
+		for _, channel := range append(publicChannels, privateChannels...) {
+			appendChannel(channel)
+		}
+	}
+
+	for _, channelID := range policy.ChannelIDs {
+		channel, _, err := c.GetChannel(channelID, "")
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to find channel %q: %s", channelID, err))
+			continue
+		}
+		appendChannel(channel)
+	}
+
+	return channels
+}
+
+func simulateChannelRetention(c client.Client, channel *model.Channel, cutoff int64) (*retentionSimulationResult, error) {
+	result := &retentionSimulationResult{
+		ChannelID:   channel.Id,
+		ChannelName: channel.Name,
+		TeamID:      channel.TeamId,
+	}
+
+	page := 0
+	for {
+		postList, _, err := c.GetPostsForChannel(channel.Id, page, web.PerPageMaximum, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, postID := range postList.Order {
+			post, ok := postList.Posts[postID]
+			if !ok || post.CreateAt >= cutoff {
+				continue
+			}
+			result.Posts++
+			result.Files += int64(len(post.FileIds))
+		}
+
+		if len(postList.Order) < web.PerPageMaximum {
+			break
+		}
+		page++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ComplianceExportCmd = &cobra.Command{
+	Use:   "compliance-export",
+	Short: "Management of compliance exports",
+}
+
+var ComplianceExportCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a compliance export job",
+	Long: `Create a job exporting the messages to the compliance export format given with --format, or to the format of the MessageExportSettings of the server if it's not set.
+Without --start, the messages are exported from where the previous compliance export ended, as the scheduled jobs do. The --end flag requires a server that supports ending exports at a given time, older servers export the messages up to the creation of the job.`,
+	Example: `  compliance-export create --format csv --start 2026-01-01T00:00:00+00:00 --end 2026-02-01T00:00:00+00:00
+  compliance-export create --format actiance --wait`,
+	Args: cobra.NoArgs,
+	RunE: withClient(complianceExportCreateCmdF),
+}
+
+var ComplianceExportListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Example: "  compliance-export list --all",
+	Short:   "List compliance export jobs",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(complianceExportListCmdF),
+}
+
+var ComplianceExportShowCmd = &cobra.Command{
+	Use:     "show [complianceExportJobID]",
+	Aliases: []string{"status"},
+	Example: "  compliance-export show o98rj3ur83dp5dppfyk5yk6osy",
+	Short:   "Show the status of a compliance export job",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(complianceExportShowCmdF),
+}
+
+var ComplianceExportDownloadCmd = &cobra.Command{
+	Use:   "download [complianceExportJobID] [filepath]",
+	Short: "Download the file of a compliance export job",
+	Long:  "Download the file of a successful compliance export job and print its SHA-256 digest. Without a path, the file is saved as the ID of the job with a .zip extension.",
+	Example: `  compliance-export download o98rj3ur83dp5dppfyk5yk6osy
+  compliance-export download o98rj3ur83dp5dppfyk5yk6osy compliance-2026-01.zip
+  compliance-export download o98rj3ur83dp5dppfyk5yk6osy --expect-sha256 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: withClient(complianceExportDownloadCmdF),
+}
+
+func init() {
+	ComplianceExportCreateCmd.Flags().String("format", "", "Format of the export: actiance, globalrelay or csv. Defaults to the format of the server config")
+	ComplianceExportCreateCmd.Flags().String("start", "", "Export the messages posted from this time, in ISO 8601 format")
+	ComplianceExportCreateCmd.Flags().String("end", "", "Export the messages posted up to this time, in ISO 8601 format")
+	addJobWaitFlags(ComplianceExportCreateCmd)
+
+	ComplianceExportListCmd.Flags().Int("page", 0, "Page number to fetch for the list of compliance export jobs")
+	ComplianceExportListCmd.Flags().Int("per-page", 200, "Number of compliance export jobs to be fetched")
+	ComplianceExportListCmd.Flags().Bool("all", false, "Fetch all compliance export jobs. --page flag will be ignore if provided")
+
+	ComplianceExportDownloadCmd.Flags().Bool("overwrite", false, "Overwrite the file if it already exists")
+	ComplianceExportDownloadCmd.Flags().String("expect-sha256", "", "Fail if the SHA-256 digest of the downloaded file doesn't match.")
+
+	ComplianceExportCmd.AddCommand(
+		ComplianceExportCreateCmd,
+		ComplianceExportListCmd,
+		ComplianceExportShowCmd,
+		ComplianceExportDownloadCmd,
+	)
+	RootCmd.AddCommand(ComplianceExportCmd)
+}
+
+// the job data keys read by the message export worker
+const (
+	complianceExportTypeKey  = "export_type"
+	complianceExportStartKey = "batch_start_timestamp"
+	complianceExportEndKey   = "batch_end_timestamp"
+)
+
+var complianceExportFormats = []string{
+	model.ComplianceExportTypeActiance,
+	model.ComplianceExportTypeGlobalrelay,
+	model.ComplianceExportTypeCsv,
+}
+
+func isValidComplianceExportFormat(format string) bool {
+	for _, f := range complianceExportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func complianceExportCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	startArg, _ := cmd.Flags().GetString("start")
+	endArg, _ := cmd.Flags().GetString("end")
+
+	data := map[string]string{}
+	if format != "" {
+		if !isValidComplianceExportFormat(format) {
+			return fmt.Errorf("invalid format %q, the formats are %s", format, strings.Join(complianceExportFormats, ", "))
+		}
+		data[complianceExportTypeKey] = format
+	}
+
+	start, err := parsePostListTime("start", startArg)
+	if err != nil {
+		return err
+	}
+	end, err := parsePostListTime("end", endArg)
+	if err != nil {
+		return err
+	}
+	if start > 0 && end > 0 && end <= start {
+		return fmt.Errorf("the end time must be after the start time")
+	}
+	if start > 0 {
+		data[complianceExportStartKey] = strconv.FormatInt(start, 10)
+	}
+	if end > 0 {
+		data[complianceExportEndKey] = strconv.FormatInt(end, 10)
+	}
+
+	job, _, err := c.CreateJob(&model.Job{
+		Type: model.JobTypeMessageExport,
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create compliance export job: %w", err)
+	}
+
+	printer.PrintT("Compliance export job successfully created, ID: {{.Id}}", job)
+
+	return waitForJobIfRequested(c, cmd, job)
+}
+
+func complianceExportListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return jobListCmdF(c, cmd, model.JobTypeMessageExport)
+}
+
+// getComplianceExportJob fails if the job isn't a compliance export,
+// so that the ID of another kind of job isn't taken for one
+func getComplianceExportJob(c client.Client, jobID string) (*model.Job, error) {
+	job, _, err := c.GetJob(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance export job: %w", err)
+	}
+	if job.Type != model.JobTypeMessageExport {
+		return nil, fmt.Errorf("job %s is not a compliance export job, its type is %s", job.Id, job.Type)
+	}
+	return job, nil
+}
+
+func complianceExportShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	job, err := getComplianceExportJob(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	printJobDetails(job)
+
+	return nil
+}
+
+// downloadComplianceExportFile streams the file of the job to the path
+// through a temporary file, as DownloadJob reads the whole file in
+// memory, so that a failed download doesn't leave a partial file nor
+// replace an existing one
+func downloadComplianceExportFile(c client.Client, jobID, path string) (int64, error) {
+	r, err := c.DoAPIGet("/jobs/"+jobID+"/download", "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to download compliance export file: %w", err)
+	}
+	defer r.Body.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to write compliance export file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	var w io.Writer = f
+	if progress := newTransferProgress(filepath.Base(path)); progress != nil {
+		progress.begin(0, r.ContentLength)
+		defer progress.finish()
+		w = io.MultiWriter(f, progress)
+	}
+
+	n, err := io.Copy(w, r.Body)
+	if err != nil {
+		f.Close()
+		return 0, fmt.Errorf("failed to download compliance export file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write compliance export file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to write compliance export file: %w", err)
+	}
+	return n, nil
+}
+
+func complianceExportDownloadCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	expectedSHA256, _ := cmd.Flags().GetString("expect-sha256")
+
+	job, err := getComplianceExportJob(c, args[0])
+	if err != nil {
+		return err
+	}
+	if job.Status != model.JobStatusSuccess && job.Status != model.JobStatusWarning {
+		return fmt.Errorf("job %s has no file to download, its status is %s", job.Id, job.Status)
+	}
+
+	path := job.Id + ".zip"
+	if len(args) > 1 {
+		path = args[1]
+	}
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("file %s already exists, use --overwrite to replace it", path)
+	}
+
+	size, err := downloadComplianceExportFile(c, job.Id, path)
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT("Compliance export file downloaded to {{.Path}} ({{.Size}})", map[string]string{
+		"Path": path,
+		"Size": formatByteSize(size),
+	})
+
+	digest, err := checkFileSHA256(path, expectedSHA256)
+	if err != nil {
+		return err
+	}
+	printSHA256(path, digest)
+
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestComplianceExportCreateCmdF() {
+	newCmd := func(format, start, end string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("format", format, "")
+		cmd.Flags().String("start", start, "")
+		cmd.Flags().String("end", end, "")
+		addJobWaitFlags(cmd)
+		return cmd
+	}
+
+	s.Run("Should create a compliance export job for the date range", func() {
+		printer.Clean()
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport}
+
+		s.client.
+			EXPECT().
+			CreateJob(&model.Job{
+				Type: model.JobTypeMessageExport,
+				Data: map[string]string{
+					"export_type":           "csv",
+					"batch_start_timestamp": "1767225600000",
+					"batch_end_timestamp":   "1769904000000",
+				},
+			}).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportCreateCmdF(s.client, newCmd("csv", "2026-01-01T00:00:00+00:00", "2026-02-01T00:00:00+00:00"), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(mockJob, printer.GetLines()[0])
+	})
+
+	s.Run("Should create a compliance export job with the defaults of the server", func() {
+		printer.Clean()
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport}
+
+		s.client.
+			EXPECT().
+			CreateJob(&model.Job{Type: model.JobTypeMessageExport, Data: map[string]string{}}).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportCreateCmdF(s.client, newCmd("", "", ""), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should fail with an invalid format", func() {
+		err := complianceExportCreateCmdF(s.client, newCmd("pdf", "", ""), []string{})
+		s.Require().EqualError(err, `invalid format "pdf", the formats are actiance, globalrelay, csv`)
+	})
+
+	s.Run("Should fail if the end is before the start", func() {
+		err := complianceExportCreateCmdF(s.client, newCmd("", "2026-02-01T00:00:00+00:00", "2026-01-01T00:00:00+00:00"), []string{})
+		s.Require().EqualError(err, "the end time must be after the start time")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestComplianceExportShowCmdF() {
+	s.Run("Should fail if the job isn't a compliance export", func() {
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeExportProcess}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportShowCmdF(s.client, &cobra.Command{}, []string{mockJob.Id})
+		s.Require().EqualError(err, "job "+mockJob.Id+" is not a compliance export job, its type is export_process")
+	})
+
+	s.Run("Should show the compliance export job", func() {
+		printer.Clean()
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusInProgress}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportShowCmdF(s.client, &cobra.Command{}, []string{mockJob.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{mockJob}, printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestComplianceExportDownloadCmdF() {
+	newCmd := func(overwrite bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("overwrite", overwrite, "")
+		cmd.Flags().String("expect-sha256", "", "")
+		return cmd
+	}
+
+	downloadResponse := func(content string) *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, ContentLength: int64(len(content)), Body: ioutil.NopCloser(strings.NewReader(content))}
+	}
+
+	// the SHA-256 digest of "export"
+	const exportSHA256 = "d46aee08cc49f6d1eb41800c1d6bab4506c960c700cff0efffe490d7cb1de5e3"
+
+	s.Run("Should download the file of the job", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/jobs/"+mockJob.Id+"/download", "").
+			Return(downloadResponse("export"), nil).
+			Times(1)
+
+		err := complianceExportDownloadCmdF(s.client, newCmd(false), []string{mockJob.Id, path})
+		s.Require().NoError(err)
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		s.Require().Equal("export", string(b))
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(struct {
+			File   string `json:"file"`
+			SHA256 string `json:"sha256"`
+		}{path, exportSHA256}, printer.GetLines()[1])
+	})
+
+	s.Run("Should fail if the digest of the file doesn't match", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/jobs/"+mockJob.Id+"/download", "").
+			Return(downloadResponse("export"), nil).
+			Times(1)
+
+		cmd := newCmd(false)
+		s.Require().NoError(cmd.Flags().Set("expect-sha256", "0000"))
+
+		err := complianceExportDownloadCmdF(s.client, cmd, []string{mockJob.Id, path})
+		s.Require().EqualError(err, `SHA-256 mismatch for "`+path+`": expected 0000, got `+exportSHA256)
+	})
+
+	s.Run("Should fail if the job didn't succeed", func() {
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusError}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportDownloadCmdF(s.client, newCmd(false), []string{mockJob.Id})
+		s.Require().EqualError(err, "job "+mockJob.Id+" has no file to download, its status is error")
+	})
+
+	s.Run("Should fail if the file exists", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("previous"), 0600))
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := complianceExportDownloadCmdF(s.client, newCmd(false), []string{mockJob.Id, path})
+		s.Require().EqualError(err, "file "+path+" already exists, use --overwrite to replace it")
+	})
+
+	s.Run("Should keep the existing file if the download fails", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("previous"), 0600))
+		mockJob := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport, Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/jobs/"+mockJob.Id+"/download", "").
+			Return(nil, errors.New("mock error")).
+			Times(1)
+
+		err := complianceExportDownloadCmdF(s.client, newCmd(true), []string{mockJob.Id, path})
+		s.Require().EqualError(err, "failed to download compliance export file: mock error")
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		s.Require().Equal("previous", string(b))
+		files, err := ioutil.ReadDir(tmp)
+		s.Require().NoError(err)
+		s.Require().Len(files, 1)
+	})
+}
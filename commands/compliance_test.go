@@ -0,0 +1,172 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestCompliancePolicySimulateCmd() {
+	writePolicy := func(dir, content string) string {
+		path := filepath.Join(dir, "policy.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	oldPostTime := model.GetMillisForTime(time.Now().AddDate(0, 0, -60))
+	newPostTime := model.GetMillis()
+
+	s.Run("Should fail when the policy file does not exist", func() {
+		printer.Clean()
+
+		err := compliancePolicySimulateCmdF(s.client, &cobra.Command{}, []string{"/tmp/nonexistent-policy.json"})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "cannot read policy file")
+	})
+
+	s.Run("Should fail when the policy has no post duration", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		path := writePolicy(tmp, `{"display_name": "policy", "channel_ids": ["channel-id"]}`)
+
+		err := compliancePolicySimulateCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().EqualError(err, "the policy must define a positive post_duration")
+	})
+
+	s.Run("Should fail when the policy has no teams nor channels", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		path := writePolicy(tmp, `{"display_name": "policy", "post_duration": 30}`)
+
+		err := compliancePolicySimulateCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().EqualError(err, "the policy must apply to at least one team or channel")
+	})
+
+	s.Run("Should count the posts and files older than the duration", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		path := writePolicy(tmp, `{"display_name": "policy", "post_duration": 30, "team_ids": ["team-id"], "channel_ids": ["channel-2", "channel-3"]}`)
+
+		team := &model.Team{Id: "team-id", Name: "team"}
+		channel1 := &model.Channel{Id: "channel-1", Name: "channel1", TeamId: team.Id}
+		channel2 := &model.Channel{Id: "channel-2", Name: "channel2", TeamId: team.Id}
+		channel3 := &model.Channel{Id: "channel-3", Name: "channel3", TeamId: "other-team"}
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{channel1}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{channel2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel2.Id, "").
+			Return(channel2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel3.Id, "").
+			Return(channel3, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel1.Id, 0, web.PerPageMaximum, "", false).
+			Return(&model.PostList{
+				Order: []string{"post-1", "post-2"},
+				Posts: map[string]*model.Post{
+					"post-1": {Id: "post-1", CreateAt: newPostTime},
+					"post-2": {Id: "post-2", CreateAt: oldPostTime, FileIds: []string{"file-1", "file-2"}},
+				},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel2.Id, 0, web.PerPageMaximum, "", false).
+			Return(&model.PostList{
+				Order: []string{"post-3"},
+				Posts: map[string]*model.Post{
+					"post-3": {Id: "post-3", CreateAt: oldPostTime},
+				},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel3.Id, 0, web.PerPageMaximum, "", false).
+			Return(&model.PostList{}, &model.Response{}, nil).
+			Times(1)
+
+		err := compliancePolicySimulateCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 4)
+		s.Require().Equal(&retentionSimulationResult{ChannelID: channel1.Id, ChannelName: channel1.Name, TeamID: team.Id, Posts: 1, Files: 2}, printer.GetLines()[0])
+		s.Require().Equal(&retentionSimulationResult{ChannelID: channel2.Id, ChannelName: channel2.Name, TeamID: team.Id, Posts: 1, Files: 0}, printer.GetLines()[1])
+		s.Require().Equal(&retentionSimulationResult{ChannelID: channel3.Id, ChannelName: channel3.Name, TeamID: channel3.TeamId, Posts: 0, Files: 0}, printer.GetLines()[2])
+		s.Require().Equal(retentionSimulationSummary{Channels: 3, Posts: 2, Files: 2}, printer.GetLines()[3])
+	})
+
+	s.Run("Should report channels that cannot be found", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		path := writePolicy(tmp, `{"display_name": "policy", "post_duration": 30, "channel_ids": ["channel-id"]}`)
+
+		s.client.
+			EXPECT().
+			GetChannel("channel-id", "").
+			Return(nil, &model.Response{}, ErrEntityNotFound{Type: "channel", ID: "channel-id"}).
+			Times(1)
+
+		err := compliancePolicySimulateCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Contains(printer.GetErrorLines()[0], `unable to find channel "channel-id"`)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(retentionSimulationSummary{}, printer.GetLines()[0])
+	})
+}
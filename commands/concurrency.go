@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// maxConcurrency caps the number of requests sent at a time, so that a
+// large value doesn't flood the server. The requests rejected by its
+// rate limiter are retried as set with the --retries flag
+const maxConcurrency = 32
+
+// addConcurrencyFlag adds the --concurrency flag to the bulk commands
+// that run their requests with forEachConcurrently
+func addConcurrencyFlag(cmd *cobra.Command, entity string) {
+	cmd.Flags().Int("concurrency", 1, fmt.Sprintf("Number of %s processed at a time, up to %d. The output order is not kept when greater than 1", entity, maxConcurrency))
+}
+
+// getConcurrency returns the value of the --concurrency flag, which
+// defaults to processing the items one after another
+func getConcurrency(cmd *cobra.Command) (int, error) {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency > maxConcurrency {
+		return 0, fmt.Errorf("the concurrency cannot be greater than %d", maxConcurrency)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return concurrency, nil
+}
+
+// forEachConcurrently calls fn for each index from 0 to n-1 using up to
+// the given number of workers, and waits for all of them to finish.
+// With a concurrency of 1 the calls are made in order on the current
+// goroutine
+func forEachConcurrently(n, concurrency int, fn func(i int)) {
+	if concurrency <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	t.Run("Should call the function in order without concurrency", func(t *testing.T) {
+		indexes := []int{}
+		forEachConcurrently(4, 1, func(i int) {
+			indexes = append(indexes, i)
+		})
+		require.Equal(t, []int{0, 1, 2, 3}, indexes)
+	})
+
+	t.Run("Should call the function once per index with up to the given workers", func(t *testing.T) {
+		var mu sync.Mutex
+		calls := make([]int, 20)
+		running, maxRunning := 0, 0
+		forEachConcurrently(len(calls), 3, func(i int) {
+			mu.Lock()
+			calls[i]++
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+
+		for i := range calls {
+			require.Equal(t, 1, calls[i])
+		}
+		require.LessOrEqual(t, maxRunning, 3)
+	})
+
+	t.Run("Should not start more workers than items", func(t *testing.T) {
+		called := false
+		forEachConcurrently(0, 8, func(i int) { called = true })
+		require.False(t, called)
+	})
+}
@@ -30,6 +30,9 @@ var ErrConfigInvalidPath = errors.New("selected path object is not valid")
 var ConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configuration",
+	Long: `Management of the configuration of the server.
+Some mmctl commands keep state shared by the admins in the plugin settings of the configuration, which shows in the output of config show, diff and apply like the settings of a plugin:
+  PluginSettings.Plugins.mmctl_guest_access_expiries: the times, in milliseconds and by user ID, when the access of the guests set with "guest expire" expires`,
 }
 
 var ConfigGetCmd = &cobra.Command{
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ConfigApplyCmd = &cobra.Command{
+	Use:   "apply <config-file>",
+	Short: "Apply a configuration file and follow its rollout",
+	Long: `Apply the settings of a JSON configuration file on top of the configuration of the server, and print the settings that changed.
+Some settings only take effect after a restart of the server. With --wait-for-restart, mmctl waits for the server to stop answering and come back up, and to accept a websocket connection again, and then checks that the applied settings are still in the configuration of the server, as it can be reverted on startup, for example by environment variables or a configuration file deployed along with the server. The check reads the stored configuration, so it can't tell whether the restarted server uses the settings. mmctl doesn't restart the server: restart it while mmctl waits, with the tools used to run it.`,
+	Example: `  config apply production-config.json
+  config apply production-config.json --wait-for-restart --timeout 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(configApplyCmdF),
+}
+
+func init() {
+	ConfigApplyCmd.Flags().Bool("wait-for-restart", false, "Wait for the server to restart and check that the applied settings are still in its configuration")
+	ConfigApplyCmd.Flags().Duration("timeout", 5*time.Minute, "Maximum time to wait for the server to restart")
+
+	ConfigCmd.AddCommand(ConfigApplyCmd)
+}
+
+// configRestartPollInterval is the time between the pings to the server
+// while waiting for it to restart
+var configRestartPollInterval = 2 * time.Second
+
+// watchServerConnection opens a websocket connection to the server, and
+// returns a channel that is closed when the connection drops
+var watchServerConnection = func() (<-chan struct{}, func(), error) {
+	if viper.GetBool("local") {
+		return nil, nil, errors.New("the websocket is not available in local mode")
+	}
+	ws, err := InitWebSocketClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	if appErr := ws.Connect(); appErr != nil {
+		return nil, nil, errors.New(appErr.Error())
+	}
+	ws.Listen()
+
+	dropped := make(chan struct{})
+	go func() {
+		for range ws.EventChannel {
+			// the events are discarded, only the end of the connection matters
+		}
+		close(dropped)
+	}()
+	return dropped, ws.Close, nil
+}
+
+// waitForServerRestart waits for the server to stop answering the pings,
+// and then to answer them and accept a websocket connection again. A
+// dropped websocket connection alone isn't taken as a restart, as it
+// can also be closed by a proxy while the server keeps running
+func waitForServerRestart(c client.Client, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	timedOut := func() bool { return time.Now().Add(configRestartPollInterval).After(deadline) }
+
+	dropped, stop, err := watchServerConnection()
+	watching := err == nil
+	if err != nil {
+		printer.PrintWarning(fmt.Sprintf("Unable to watch the websocket connection, only pinging the server: %s", err))
+	} else {
+		defer stop()
+	}
+
+	for {
+		if _, _, pingErr := c.GetPing(); pingErr != nil {
+			break
+		}
+		select {
+		case <-dropped:
+			printer.PrintWarning("The websocket connection dropped but the server still answers the pings, waiting for it to go down")
+			dropped = nil
+		default:
+		}
+		if timedOut() {
+			return fmt.Errorf("timed out after %s waiting for the server to go down", timeout)
+		}
+		time.Sleep(configRestartPollInterval)
+	}
+	printer.Print("The server went down, waiting for it to come back up")
+
+	for {
+		if _, _, pingErr := c.GetPing(); pingErr == nil {
+			break
+		}
+		if timedOut() {
+			return fmt.Errorf("timed out after %s waiting for the server to come back up", timeout)
+		}
+		time.Sleep(configRestartPollInterval)
+	}
+
+	if watching {
+		_, stopAgain, wsErr := watchServerConnection()
+		if wsErr != nil {
+			return errors.Wrap(wsErr, "the server is up but the websocket connection can't be reestablished")
+		}
+		stopAgain()
+	}
+	printer.Print("The server restarted")
+	return nil
+}
+
+func configApplyCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	waitForRestart, _ := cmd.Flags().GetBool("wait-for-restart")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	configBytes, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to read the configuration file")
+	}
+
+	current, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+
+	config := current.Clone()
+	if err = json.Unmarshal(configBytes, config); err != nil {
+		return errors.Wrap(err, "failed to parse the configuration file")
+	}
+
+	applied, _, err := c.PatchConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to apply the config")
+	}
+
+	differences, err := diffConfigs(current, applied, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the configurations")
+	}
+	if len(differences) == 0 {
+		printer.Print("The configuration of the server is already up to date")
+		return nil
+	}
+	printConfigDifferences(differences)
+
+	if !waitForRestart {
+		printer.Print(fmt.Sprintf("%d settings applied, restart the server for the ones that require it", len(differences)))
+		return nil
+	}
+
+	printer.Print(fmt.Sprintf("%d settings applied, waiting for the server to restart", len(differences)))
+	if err = waitForServerRestart(c, timeout); err != nil {
+		return err
+	}
+
+	restarted, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config after the restart")
+	}
+	reverted, err := diffConfigs(restarted, applied, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the configurations")
+	}
+	if len(reverted) > 0 {
+		printConfigDifferences(reverted)
+		return fmt.Errorf("%d settings are no longer in the configuration after the restart", len(reverted))
+	}
+
+	printer.Print("The applied settings are still in the configuration after the restart")
+	return nil
+}
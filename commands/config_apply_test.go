@@ -0,0 +1,189 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestConfigApplyCmd() {
+	newCmd := func(waitForRestart bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("wait-for-restart", waitForRestart, "")
+		cmd.Flags().Duration("timeout", 0, "")
+		return cmd
+	}
+
+	writeConfigFile := func() (string, func()) {
+		dir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(dir, "config.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`{"ServiceSettings": {"SiteURL": "https://chat.example.com"}}`), 0600))
+		return path, func() { os.RemoveAll(dir) }
+	}
+
+	newConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		return config
+	}
+
+	stubWatchServerConnection := func(dropped chan struct{}) func() {
+		originalWatch := watchServerConnection
+		originalInterval := configRestartPollInterval
+		configRestartPollInterval = 0
+		watchServerConnection = func() (<-chan struct{}, func(), error) {
+			return dropped, func() {}, nil
+		}
+		return func() {
+			watchServerConnection = originalWatch
+			configRestartPollInterval = originalInterval
+		}
+	}
+
+	expectApply := func(current *model.Config) {
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(current, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchConfig(gomock.Any()).
+			DoAndReturn(func(config *model.Config) (*model.Config, *model.Response, error) {
+				return config, &model.Response{}, nil
+			}).
+			Times(1)
+	}
+
+	siteURLChange := &configDifference{Key: "ServiceSettings.SiteURL", Change: configKeyChanged, Server: "", Other: "https://chat.example.com"}
+
+	s.Run("Should apply the config and print the settings that changed", func() {
+		printer.Clean()
+		path, cleanup := writeConfigFile()
+		defer cleanup()
+
+		expectApply(newConfig())
+
+		err := configApplyCmdF(s.client, newCmd(false), []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			siteURLChange,
+			"1 settings applied, restart the server for the ones that require it",
+		}, printer.GetLines())
+	})
+
+	s.Run("Should wait for the restart and check the settings are still in the configuration", func() {
+		printer.Clean()
+		path, cleanup := writeConfigFile()
+		defer cleanup()
+		defer stubWatchServerConnection(make(chan struct{}))()
+
+		live := newConfig()
+		live.ServiceSettings.SiteURL = model.NewString("https://chat.example.com")
+
+		expectApply(newConfig())
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("", &model.Response{}, errors.New("connection refused")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(live, &model.Response{}, nil).
+			Times(1)
+
+		err := configApplyCmdF(s.client, newCmd(true), []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			siteURLChange,
+			"1 settings applied, waiting for the server to restart",
+			"The server went down, waiting for it to come back up",
+			"The server restarted",
+			"The applied settings are still in the configuration after the restart",
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the settings are no longer in the configuration after the restart", func() {
+		printer.Clean()
+		path, cleanup := writeConfigFile()
+		defer cleanup()
+		dropped := make(chan struct{})
+		close(dropped)
+		defer stubWatchServerConnection(dropped)()
+
+		expectApply(newConfig())
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("", &model.Response{}, errors.New("connection refused")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := configApplyCmdF(s.client, newCmd(true), []string{path})
+		s.Require().EqualError(err, "1 settings are no longer in the configuration after the restart")
+		s.Require().Equal(siteURLChange, printer.GetLines()[len(printer.GetLines())-1])
+	})
+
+	s.Run("Should time out if the server doesn't go down", func() {
+		printer.Clean()
+		path, cleanup := writeConfigFile()
+		defer cleanup()
+		defer stubWatchServerConnection(make(chan struct{}))()
+
+		expectApply(newConfig())
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{}, nil).
+			Times(1)
+
+		err := configApplyCmdF(s.client, newCmd(true), []string{path})
+		s.Require().EqualError(err, "timed out after 0s waiting for the server to go down")
+	})
+
+	s.Run("Should not take a dropped websocket connection as a restart while the server answers the pings", func() {
+		printer.Clean()
+		path, cleanup := writeConfigFile()
+		defer cleanup()
+		dropped := make(chan struct{})
+		close(dropped)
+		defer stubWatchServerConnection(dropped)()
+
+		expectApply(newConfig())
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{}, nil).
+			Times(1)
+
+		err := configApplyCmdF(s.client, newCmd(true), []string{path})
+		s.Require().EqualError(err, "timed out after 0s waiting for the server to go down")
+		s.Require().NotContains(printer.GetLines(), "The server restarted")
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const (
+	configKeyChanged = "changed"
+	configKeyAdded   = "added"
+	configKeyRemoved = "removed"
+)
+
+var ConfigDiffCmd = &cobra.Command{
+	Use:   "diff [config-file]",
+	Short: "Compare the server configuration",
+	Long: `Compare the configuration of the server to a local JSON file, or to the configuration of another server given by the name of its stored credentials with --server.
+Each setting that differs is printed with its value on the server and on the other side, prefixed with "~" if it changed, "-" if it only exists on the server and "+" if it only exists on the other side. The settings missing from the file take their default value, and the secrets are masked on both sides, so they are not compared.`,
+	Example: `  config diff production-config.json
+  config diff --server staging --ignore ServiceSettings.SiteURL --ignore SqlSettings`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: withClient(configDiffCmdF),
+}
+
+func init() {
+	ConfigDiffCmd.Flags().String("server", "", "Name of the stored credentials of the server to compare the configuration to")
+	ConfigDiffCmd.Flags().StringSlice("ignore", nil, "Settings or sections to leave out of the comparison, in dot notation")
+
+	ConfigCmd.AddCommand(ConfigDiffCmd)
+}
+
+type configDifference struct {
+	Key    string      `json:"key"`
+	Change string      `json:"change"`
+	Server interface{} `json:"server,omitempty"`
+	Other  interface{} `json:"other,omitempty"`
+}
+
+// flattenConfig returns the settings of the configuration by their
+// name in dot notation. Arrays are kept as values of their setting
+func flattenConfig(prefix string, value interface{}, settings map[string]interface{}) {
+	section, ok := value.(map[string]interface{})
+	if !ok || (len(section) == 0 && prefix != "") {
+		settings[prefix] = value
+		return
+	}
+	for key, v := range section {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenConfig(key, v, settings)
+	}
+}
+
+func configSettings(cfg *model.Config) (map[string]interface{}, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	settings := map[string]interface{}{}
+	flattenConfig("", m, settings)
+	return settings, nil
+}
+
+func isIgnoredSetting(key string, ignore []string) bool {
+	for _, prefix := range ignore {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// diffConfigs returns the settings that differ between the server and
+// the other configuration, sorted by name
+func diffConfigs(server, other *model.Config, ignore []string) ([]*configDifference, error) {
+	serverSettings, err := configSettings(server)
+	if err != nil {
+		return nil, err
+	}
+	otherSettings, err := configSettings(other)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := []*configDifference{}
+	for key, serverValue := range serverSettings {
+		if isIgnoredSetting(key, ignore) {
+			continue
+		}
+		otherValue, ok := otherSettings[key]
+		switch {
+		case !ok:
+			differences = append(differences, &configDifference{Key: key, Change: configKeyRemoved, Server: serverValue})
+		case !reflect.DeepEqual(serverValue, otherValue):
+			differences = append(differences, &configDifference{Key: key, Change: configKeyChanged, Server: serverValue, Other: otherValue})
+		}
+	}
+	for key, otherValue := range otherSettings {
+		if _, ok := serverSettings[key]; !ok && !isIgnoredSetting(key, ignore) {
+			differences = append(differences, &configDifference{Key: key, Change: configKeyAdded, Other: otherValue})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Key < differences[j].Key
+	})
+	return differences, nil
+}
+
+// readConfigFile reads a configuration file the way the server does,
+// with defaults for the missing settings, and masks its secrets as the
+// server does when returning its configuration
+func readConfigFile(path string) (*model.Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the configuration file")
+	}
+	var cfg model.Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the configuration file")
+	}
+	cfg.SetDefaults()
+	cfg.Sanitize()
+	return &cfg, nil
+}
+
+func getServerConfig(name string) (*model.Config, error) {
+	credentials, err := GetCredentials(name)
+	if err != nil {
+		return nil, err
+	}
+	c, _, err := InitClientWithCredentials(credentials, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to server %q", name)
+	}
+	cfg, _, err := c.GetConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the config of server %q", name)
+	}
+	return cfg, nil
+}
+
+func configDiffValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func configDiffCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	server, _ := cmd.Flags().GetString("server")
+	ignore, _ := cmd.Flags().GetStringSlice("ignore")
+	if (server == "") == (len(args) == 0) {
+		return errors.New("either a configuration file or --server must be given")
+	}
+
+	var other *model.Config
+	var err error
+	if server != "" {
+		other, err = getServerConfig(server)
+	} else {
+		other, err = readConfigFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+
+	differences, err := diffConfigs(cfg, other, ignore)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the configurations")
+	}
+
+	printConfigDifferences(differences)
+	return nil
+}
+
+func printConfigDifferences(differences []*configDifference) {
+	printer.SetTemplateFunc("value", configDiffValue)
+	printer.SetTemplateFunc("removed", color.RedString)
+	printer.SetTemplateFunc("added", color.GreenString)
+	for _, difference := range differences {
+		printer.PrintT(`{{if eq .Change "changed"}}~ {{.Key}}: {{removed "%s" (value .Server)}} -> {{added "%s" (value .Other)}}`+
+			`{{else if eq .Change "removed"}}{{removed "- %s: %s" .Key (value .Server)}}`+
+			`{{else}}{{added "+ %s: %s" .Key (value .Other)}}{{end}}`, difference)
+	}
+}
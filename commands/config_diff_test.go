@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestConfigDiffCmd() {
+	newCmd := func(ignore ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("server", "", "")
+		cmd.Flags().StringSlice("ignore", ignore, "")
+		return cmd
+	}
+
+	liveConfig := func() *model.Config {
+		cfg := &model.Config{}
+		cfg.ServiceSettings.SiteURL = model.NewString("https://mattermost.example.com")
+		cfg.SetDefaults()
+		cfg.TeamSettings.SiteName = model.NewString("Mattermost")
+		cfg.SqlSettings.DataSource = model.NewString("postgres://production")
+		cfg.Sanitize()
+		return cfg
+	}
+
+	writeConfigFile := func(dir string) string {
+		path := filepath.Join(dir, "config.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`{
+			"ServiceSettings": {"SiteURL": "https://staging.example.com"},
+			"TeamSettings": {"SiteName": "Staging", "MaxUsersPerTeam": 100},
+			"SqlSettings": {"DataSource": "postgres://staging"}
+		}`), 0600))
+		return path
+	}
+
+	s.Run("Should print the settings that differ from the file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(liveConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := configDiffCmdF(s.client, newCmd(), []string{writeConfigFile(tmp)})
+		s.Require().NoError(err)
+		// the data sources are secrets, so they are masked on both sides
+		s.Require().Equal([]interface{}{
+			&configDifference{Key: "ServiceSettings.SiteURL", Change: configKeyChanged, Server: "https://mattermost.example.com", Other: "https://staging.example.com"},
+			&configDifference{Key: "TeamSettings.MaxUsersPerTeam", Change: configKeyChanged, Server: 50.0, Other: 100.0},
+			&configDifference{Key: "TeamSettings.SiteName", Change: configKeyChanged, Server: "Mattermost", Other: "Staging"},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should leave the ignored settings out", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(liveConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := configDiffCmdF(s.client, newCmd("ServiceSettings.SiteURL", "TeamSettings"), []string{writeConfigFile(tmp)})
+		s.Require().NoError(err)
+		s.Require().Empty(printer.GetLines())
+	})
+
+	s.Run("Should render the differences as a diff", func() {
+		printer.Clean()
+		printer.SetFormat(printer.FormatPlain)
+		defer printer.SetFormat(printer.FormatJSON)
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(liveConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := configDiffCmdF(s.client, newCmd("TeamSettings"), []string{writeConfigFile(tmp)})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{`~ ServiceSettings.SiteURL: "https://mattermost.example.com" -> "https://staging.example.com"`}, printer.GetLines())
+	})
+
+	s.Run("Should flatten the settings in dot notation", func() {
+		server := map[string]interface{}{"A": map[string]interface{}{"B": 1.0, "C": []interface{}{"x"}}}
+		other := map[string]interface{}{"A": map[string]interface{}{"C": []interface{}{"x"}, "D": "new"}}
+
+		serverSettings, otherSettings := map[string]interface{}{}, map[string]interface{}{}
+		flattenConfig("", server, serverSettings)
+		flattenConfig("", other, otherSettings)
+		s.Require().Equal(map[string]interface{}{"A.B": 1.0, "A.C": []interface{}{"x"}}, serverSettings)
+		s.Require().Equal(map[string]interface{}{"A.C": []interface{}{"x"}, "A.D": "new"}, otherSettings)
+	})
+
+	s.Run("Should fail without a file or a server", func() {
+		err := configDiffCmdF(s.client, newCmd(), []string{})
+		s.Require().EqualError(err, "either a configuration file or --server must be given")
+
+		cmd := newCmd()
+		_ = cmd.Flags().Set("server", "staging")
+		err = configDiffCmdF(s.client, cmd, []string{"config.json"})
+		s.Require().EqualError(err, "either a configuration file or --server must be given")
+	})
+}
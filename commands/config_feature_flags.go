@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	featureFlagSourceConfig      = "config"
+	featureFlagSourceEnvironment = "environment"
+)
+
+var ConfigFeatureFlagsCmd = &cobra.Command{
+	Use:   "feature-flags",
+	Short: "Management of feature flags",
+}
+
+var ConfigFeatureFlagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the feature flags",
+	Long: `List the feature flags of the server with their current value and where the value comes from.
+Feature flags set through MM_FEATUREFLAGS_* environment variables are reported with the "environment" source, and can't be changed with "config feature-flags set".`,
+	Example: "  config feature-flags list",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(configFeatureFlagsListCmdF),
+}
+
+var ConfigFeatureFlagsSetCmd = &cobra.Command{
+	Use:   "set [flag] [value]",
+	Short: "Set a feature flag",
+	Long: `Set the value of a feature flag in the server configuration.
+Depending on the deployment, the server may manage the feature flags externally and ignore the change, in which case the command fails.`,
+	Example: "  config feature-flags set CollapsedThreads true",
+	Args:    cobra.ExactArgs(2),
+	RunE:    withClient(configFeatureFlagsSetCmdF),
+}
+
+func init() {
+	ConfigFeatureFlagsCmd.AddCommand(
+		ConfigFeatureFlagsListCmd,
+		ConfigFeatureFlagsSetCmd,
+	)
+	ConfigCmd.AddCommand(ConfigFeatureFlagsCmd)
+}
+
+type featureFlag struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// getEnvironmentFeatureFlags returns the names of the feature flags
+// that are overridden through environment variables
+func getEnvironmentFeatureFlags(c client.Client) (map[string]bool, error) {
+	envConfig, _, err := c.GetEnvironmentConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get the environment config")
+	}
+
+	flags := map[string]bool{}
+	if envFlags, ok := envConfig["FeatureFlags"].(map[string]interface{}); ok {
+		for name := range envFlags {
+			flags[name] = true
+		}
+	}
+
+	return flags, nil
+}
+
+func configFeatureFlagsListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the server config")
+	}
+	if config.FeatureFlags == nil {
+		return errors.New("the server did not return the feature flags")
+	}
+
+	envFlags, err := getEnvironmentFeatureFlags(c)
+	if err != nil {
+		return err
+	}
+
+	values := config.FeatureFlags.ToMap()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := &featureFlag{Name: name, Value: values[name], Source: featureFlagSourceConfig}
+		if envFlags[name] {
+			flag.Source = featureFlagSourceEnvironment
+		}
+		printer.PrintT("{{.Name}}: {{.Value}}{{if eq .Source \"environment\"}} (environment){{end}}", flag)
+	}
+
+	return nil
+}
+
+func configFeatureFlagsSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	name, value := args[0], args[1]
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the server config")
+	}
+	if config.FeatureFlags == nil {
+		config.FeatureFlags = &model.FeatureFlags{}
+		config.FeatureFlags.SetDefaults()
+	}
+
+	if _, ok := config.FeatureFlags.ToMap()[name]; !ok {
+		return fmt.Errorf("unknown feature flag %q", name)
+	}
+
+	envFlags, err := getEnvironmentFeatureFlags(c)
+	if err != nil {
+		return err
+	}
+	if envFlags[name] {
+		return fmt.Errorf("feature flag %q is set through the environment and can't be changed", name)
+	}
+
+	field := reflect.ValueOf(config.FeatureFlags).Elem().FieldByName(name)
+	if err := setValueWithConversion(field, value); err != nil {
+		return fmt.Errorf("invalid value for feature flag %q: %w", name, err)
+	}
+	expected := config.FeatureFlags.ToMap()[name]
+
+	newConfig, _, err := c.PatchConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "unable to update the server config")
+	}
+
+	flag := &featureFlag{Name: name, Value: expected, Source: featureFlagSourceConfig}
+	if newConfig.FeatureFlags != nil {
+		flag.Value = newConfig.FeatureFlags.ToMap()[name]
+	}
+	if flag.Value != expected {
+		return fmt.Errorf("the server kept the value %q for feature flag %q, it may be managed externally", flag.Value, name)
+	}
+
+	printer.PrintT("Feature flag {{.Name}} set to {{.Value}}", flag)
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestConfigFeatureFlagsListCmd() {
+	s.Run("Should list the feature flags with their source", func() {
+		printer.Clean()
+		config := &model.Config{}
+		config.SetDefaults()
+		config.FeatureFlags.CollapsedThreads = false
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetEnvironmentConfig().
+			Return(map[string]interface{}{
+				"FeatureFlags": map[string]interface{}{"CollapsedThreads": true},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := configFeatureFlagsListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), len(config.FeatureFlags.ToMap()))
+		s.Require().Contains(printer.GetLines(), &featureFlag{Name: "CollapsedThreads", Value: "false", Source: featureFlagSourceEnvironment})
+		s.Require().Contains(printer.GetLines(), &featureFlag{Name: "InviteToTeam", Value: "none", Source: featureFlagSourceConfig})
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestConfigFeatureFlagsSetCmd() {
+	newConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		return config
+	}
+
+	s.Run("Should set a feature flag", func() {
+		printer.Clean()
+		config := newConfig()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetEnvironmentConfig().
+			Return(map[string]interface{}{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := configFeatureFlagsSetCmdF(s.client, &cobra.Command{}, []string{"GraphQL", "true"})
+		s.Require().NoError(err)
+		s.Require().True(config.FeatureFlags.GraphQL)
+		s.Require().Equal([]interface{}{&featureFlag{Name: "GraphQL", Value: "true", Source: featureFlagSourceConfig}}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the server ignores the change", func() {
+		printer.Clean()
+		config := newConfig()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetEnvironmentConfig().
+			Return(map[string]interface{}{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(newConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := configFeatureFlagsSetCmdF(s.client, &cobra.Command{}, []string{"GraphQL", "true"})
+		s.Require().EqualError(err, `the server kept the value "false" for feature flag "GraphQL", it may be managed externally`)
+	})
+
+	s.Run("Should fail for flags set through the environment", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetEnvironmentConfig().
+			Return(map[string]interface{}{
+				"FeatureFlags": map[string]interface{}{"GraphQL": true},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := configFeatureFlagsSetCmdF(s.client, &cobra.Command{}, []string{"GraphQL", "false"})
+		s.Require().EqualError(err, `feature flag "GraphQL" is set through the environment and can't be changed`)
+	})
+
+	s.Run("Should fail for unknown flags and invalid values", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{}, nil).
+			Times(2)
+
+		err := configFeatureFlagsSetCmdF(s.client, &cobra.Command{}, []string{"Unknown", "true"})
+		s.Require().EqualError(err, `unknown feature flag "Unknown"`)
+
+		s.client.
+			EXPECT().
+			GetEnvironmentConfig().
+			Return(map[string]interface{}{}, &model.Response{}, nil).
+			Times(1)
+
+		err = configFeatureFlagsSetCmdF(s.client, &cobra.Command{}, []string{"GraphQL", "maybe"})
+		s.Require().EqualError(err, `invalid value for feature flag "GraphQL": target value is of type Bool and provided value is not`)
+	})
+}
@@ -0,0 +1,421 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var DataRetentionCmd = &cobra.Command{
+	Use:   "data-retention",
+	Short: "Management of data retention policies",
+}
+
+var DataRetentionGlobalCmd = &cobra.Command{
+	Use:     "global",
+	Short:   "Show the global data retention policy",
+	Long:    "Show the global data retention policy, set in the DataRetentionSettings of the config, which applies to the teams and channels not covered by a granular policy.",
+	Example: "  data-retention global",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(dataRetentionGlobalCmdF),
+}
+
+var DataRetentionPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Management of granular data retention policies",
+	Long:  "Management of the granular data retention policies, which set how long the messages of the teams and channels they cover are kept. The policies are given by ID or by display name.",
+}
+
+var DataRetentionPolicyListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the granular data retention policies",
+	Example: "  data-retention policy list",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(dataRetentionPolicyListCmdF),
+}
+
+var DataRetentionPolicyShowCmd = &cobra.Command{
+	Use:     "show [policy]",
+	Short:   "Show a granular data retention policy with its teams and channels",
+	Example: `  data-retention policy show "Short retention"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(dataRetentionPolicyShowCmdF),
+}
+
+var DataRetentionPolicyCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a granular data retention policy",
+	Example: `  data-retention policy create --display-name "Short retention" --post-duration 30 --teams myteam --channels otherteam:town-square`,
+	Args:    cobra.NoArgs,
+	RunE:    withClient(dataRetentionPolicyCreateCmdF),
+}
+
+var DataRetentionPolicyUpdateCmd = &cobra.Command{
+	Use:     "update [policy]",
+	Short:   "Update the display name or the post duration of a granular data retention policy",
+	Example: `  data-retention policy update "Short retention" --post-duration 60`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(dataRetentionPolicyUpdateCmdF),
+}
+
+var DataRetentionPolicyDeleteCmd = &cobra.Command{
+	Use:     "delete [policy]",
+	Aliases: []string{"rm"},
+	Short:   "Delete a granular data retention policy",
+	Long:    "Delete a granular data retention policy. The teams and channels it covers fall back to the policy of their team, if any, or to the global policy.",
+	Example: `  data-retention policy delete "Short retention" --confirm`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(dataRetentionPolicyDeleteCmdF),
+}
+
+var DataRetentionPolicyAddTeamsCmd = &cobra.Command{
+	Use:     "add-teams [policy] [teams]",
+	Short:   "Add teams to a granular data retention policy",
+	Example: `  data-retention policy add-teams "Short retention" myteam otherteam`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(dataRetentionPolicyAddTeamsCmdF),
+}
+
+var DataRetentionPolicyRemoveTeamsCmd = &cobra.Command{
+	Use:     "remove-teams [policy] [teams]",
+	Short:   "Remove teams from a granular data retention policy",
+	Example: `  data-retention policy remove-teams "Short retention" myteam`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(dataRetentionPolicyRemoveTeamsCmdF),
+}
+
+var DataRetentionPolicyAddChannelsCmd = &cobra.Command{
+	Use:     "add-channels [policy] [channels]",
+	Short:   "Add channels to a granular data retention policy",
+	Example: `  data-retention policy add-channels "Short retention" myteam:town-square myteam:off-topic`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(dataRetentionPolicyAddChannelsCmdF),
+}
+
+var DataRetentionPolicyRemoveChannelsCmd = &cobra.Command{
+	Use:     "remove-channels [policy] [channels]",
+	Short:   "Remove channels from a granular data retention policy",
+	Example: `  data-retention policy remove-channels "Short retention" myteam:off-topic`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(dataRetentionPolicyRemoveChannelsCmdF),
+}
+
+func init() {
+	DataRetentionPolicyCreateCmd.Flags().String("display-name", "", "Display name of the policy")
+	_ = DataRetentionPolicyCreateCmd.MarkFlagRequired("display-name")
+	DataRetentionPolicyCreateCmd.Flags().Int64("post-duration", 0, "Number of days the messages are kept")
+	_ = DataRetentionPolicyCreateCmd.MarkFlagRequired("post-duration")
+	DataRetentionPolicyCreateCmd.Flags().StringSlice("teams", []string{}, "Teams covered by the policy")
+	DataRetentionPolicyCreateCmd.Flags().StringSlice("channels", []string{}, "Channels covered by the policy")
+
+	DataRetentionPolicyUpdateCmd.Flags().String("display-name", "", "Display name of the policy")
+	DataRetentionPolicyUpdateCmd.Flags().Int64("post-duration", 0, "Number of days the messages are kept")
+
+	DataRetentionPolicyDeleteCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the policy")
+
+	DataRetentionPolicyCmd.AddCommand(
+		DataRetentionPolicyListCmd,
+		DataRetentionPolicyShowCmd,
+		DataRetentionPolicyCreateCmd,
+		DataRetentionPolicyUpdateCmd,
+		DataRetentionPolicyDeleteCmd,
+		DataRetentionPolicyAddTeamsCmd,
+		DataRetentionPolicyRemoveTeamsCmd,
+		DataRetentionPolicyAddChannelsCmd,
+		DataRetentionPolicyRemoveChannelsCmd,
+	)
+	DataRetentionCmd.AddCommand(
+		DataRetentionGlobalCmd,
+		DataRetentionPolicyCmd,
+	)
+	RootCmd.AddCommand(DataRetentionCmd)
+}
+
+const retentionPolicyTemplate = "{{.ID}}: {{.DisplayName}}, messages kept {{.PostDurationDays}} days, {{.TeamCount}} teams, {{.ChannelCount}} channels"
+
+// getRetentionPolicyArg finds the policy given by ID or display name
+// among all the policies
+func getRetentionPolicyArg(c client.Client, policyArg string) (*model.RetentionPolicyWithTeamAndChannelCounts, error) {
+	policies, err := getAllRetentionPolicies(c)
+	if err != nil {
+		return nil, err
+	}
+	return getRetentionPolicyFromArg(policies, policyArg)
+}
+
+func getRetentionTeamIDs(c client.Client, teamArgs []string) ([]string, error) {
+	ids := make([]string, 0, len(teamArgs))
+	for i, team := range getTeamsFromTeamArgs(c, teamArgs) {
+		if team == nil {
+			return nil, fmt.Errorf("unable to find team %q", teamArgs[i])
+		}
+		ids = append(ids, team.Id)
+	}
+	return ids, nil
+}
+
+func getRetentionChannelIDs(c client.Client, channelArgs []string) ([]string, error) {
+	ids := make([]string, 0, len(channelArgs))
+	for i, channel := range getChannelsFromChannelArgs(c, channelArgs) {
+		if channel == nil {
+			return nil, fmt.Errorf("unable to find channel %q", channelArgs[i])
+		}
+		ids = append(ids, channel.Id)
+	}
+	return ids, nil
+}
+
+func validatePostDuration(days int64) error {
+	if days <= 0 {
+		return errors.New("the post duration must be at least one day")
+	}
+	return nil
+}
+
+func dataRetentionGlobalCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, _, err := c.GetDataRetentionPolicy()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the global data retention policy")
+	}
+
+	printer.PrintT(`Messages: {{if .MessageDeletionEnabled}}deleted if posted before {{timestamp .MessageRetentionCutoff}}{{else}}kept forever{{end}}
+Files: {{if .FileDeletionEnabled}}deleted if uploaded before {{timestamp .FileRetentionCutoff}}{{else}}kept forever{{end}}
+Boards: {{if .BoardsDeletionEnabled}}deleted if created before {{timestamp .BoardsRetentionCutoff}}{{else}}kept forever{{end}}`, policy)
+
+	return nil
+}
+
+func dataRetentionPolicyListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policies, err := getAllRetentionPolicies(c)
+	if err != nil {
+		return err
+	}
+
+	if len(policies) == 0 {
+		printer.Print("No policies found")
+		return nil
+	}
+	for _, policy := range policies {
+		printer.PrintT(retentionPolicyTemplate, policy)
+	}
+	return nil
+}
+
+func dataRetentionPolicyShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	details := struct {
+		*model.RetentionPolicyWithTeamAndChannelCounts
+		Teams    []*model.Team                `json:"teams"`
+		Channels []*model.ChannelWithTeamData `json:"channels"`
+	}{RetentionPolicyWithTeamAndChannelCounts: policy}
+	for page := 0; ; page++ {
+		list, _, err := c.GetTeamsForRetentionPolicy(policy.ID, page, web.PerPageMaximum)
+		if err != nil {
+			return errors.Wrap(err, "failed to get the teams of the policy")
+		}
+		details.Teams = append(details.Teams, list.Teams...)
+		if len(list.Teams) < web.PerPageMaximum {
+			break
+		}
+	}
+	for page := 0; ; page++ {
+		list, _, err := c.GetChannelsForRetentionPolicy(policy.ID, page, web.PerPageMaximum)
+		if err != nil {
+			return errors.Wrap(err, "failed to get the channels of the policy")
+		}
+		details.Channels = append(details.Channels, list.Channels...)
+		if len(list.Channels) < web.PerPageMaximum {
+			break
+		}
+	}
+
+	printer.PrintT(`  ID: {{.ID}}
+  Display name: {{.DisplayName}}
+  Post duration: {{.PostDurationDays}} days
+  Teams:{{range .Teams}} {{.Name}}{{end}}
+  Channels:{{range .Channels}} {{.TeamName}}:{{.Name}}{{end}}
+`, details)
+
+	return nil
+}
+
+func dataRetentionPolicyCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	postDuration, _ := cmd.Flags().GetInt64("post-duration")
+	teamArgs, _ := cmd.Flags().GetStringSlice("teams")
+	channelArgs, _ := cmd.Flags().GetStringSlice("channels")
+
+	if err := validatePostDuration(postDuration); err != nil {
+		return err
+	}
+	teamIDs, err := getRetentionTeamIDs(c, teamArgs)
+	if err != nil {
+		return err
+	}
+	channelIDs, err := getRetentionChannelIDs(c, channelArgs)
+	if err != nil {
+		return err
+	}
+
+	policy, _, err := c.CreateDataRetentionPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+		RetentionPolicy: model.RetentionPolicy{
+			DisplayName:      displayName,
+			PostDurationDays: model.NewInt64(postDuration),
+		},
+		TeamIDs:    teamIDs,
+		ChannelIDs: channelIDs,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the policy")
+	}
+
+	printer.PrintT("Policy {{.DisplayName}} successfully created, ID: {{.ID}}", policy)
+
+	return nil
+}
+
+func dataRetentionPolicyUpdateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	patch := &model.RetentionPolicyWithTeamAndChannelIDs{}
+	if cmd.Flags().Changed("display-name") {
+		displayName, _ := cmd.Flags().GetString("display-name")
+		patch.DisplayName = displayName
+	}
+	if cmd.Flags().Changed("post-duration") {
+		postDuration, _ := cmd.Flags().GetInt64("post-duration")
+		if err := validatePostDuration(postDuration); err != nil {
+			return err
+		}
+		patch.PostDurationDays = model.NewInt64(postDuration)
+	}
+	if patch.DisplayName == "" && patch.PostDurationDays == nil {
+		return errors.New("either --display-name or --post-duration must be given")
+	}
+
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	patch.ID = policy.ID
+
+	updated, _, err := c.PatchDataRetentionPolicy(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to update the policy")
+	}
+
+	printer.PrintT(retentionPolicyTemplate, updated)
+
+	return nil
+}
+
+func dataRetentionPolicyDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to delete the policy %q covering %d teams and %d channels?", policy.DisplayName, policy.TeamCount, policy.ChannelCount), false); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.DeleteDataRetentionPolicy(policy.ID); err != nil {
+		return errors.Wrap(err, "failed to delete the policy")
+	}
+
+	printer.PrintT("Policy {{.DisplayName}} successfully deleted", policy)
+
+	return nil
+}
+
+func dataRetentionPolicyAddTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	teamIDs, err := getRetentionTeamIDs(c, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.AddTeamsToRetentionPolicy(policy.ID, teamIDs); err != nil {
+		return errors.Wrap(err, "failed to add the teams to the policy")
+	}
+
+	printer.PrintT("{{.Count}} teams added to policy {{.Policy}}", map[string]interface{}{"Count": len(teamIDs), "Policy": policy.DisplayName})
+
+	return nil
+}
+
+func dataRetentionPolicyRemoveTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	teamIDs, err := getRetentionTeamIDs(c, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.RemoveTeamsFromRetentionPolicy(policy.ID, teamIDs); err != nil {
+		return errors.Wrap(err, "failed to remove the teams from the policy")
+	}
+
+	printer.PrintT("{{.Count}} teams removed from policy {{.Policy}}", map[string]interface{}{"Count": len(teamIDs), "Policy": policy.DisplayName})
+
+	return nil
+}
+
+func dataRetentionPolicyAddChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	channelIDs, err := getRetentionChannelIDs(c, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.AddChannelsToRetentionPolicy(policy.ID, channelIDs); err != nil {
+		return errors.Wrap(err, "failed to add the channels to the policy")
+	}
+
+	printer.PrintT("{{.Count}} channels added to policy {{.Policy}}", map[string]interface{}{"Count": len(channelIDs), "Policy": policy.DisplayName})
+
+	return nil
+}
+
+func dataRetentionPolicyRemoveChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	policy, err := getRetentionPolicyArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	channelIDs, err := getRetentionChannelIDs(c, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.RemoveChannelsFromRetentionPolicy(policy.ID, channelIDs); err != nil {
+		return errors.Wrap(err, "failed to remove the channels from the policy")
+	}
+
+	printer.PrintT("{{.Count}} channels removed from policy {{.Policy}}", map[string]interface{}{"Count": len(channelIDs), "Policy": policy.DisplayName})
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestDataRetentionCmds() {
+	policy := &model.RetentionPolicyWithTeamAndChannelCounts{
+		RetentionPolicy: model.RetentionPolicy{ID: "policy-id", DisplayName: "Short retention", PostDurationDays: model.NewInt64(30)},
+		TeamCount:       1,
+	}
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	channel := &model.Channel{Id: "channel-id", Name: "town-square", TeamId: team.Id}
+
+	expectPolicies := func() {
+		s.client.
+			EXPECT().
+			GetDataRetentionPolicies(0, web.PerPageMaximum).
+			Return(&model.RetentionPolicyWithTeamAndChannelCountsList{Policies: []*model.RetentionPolicyWithTeamAndChannelCounts{policy}}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should show the global policy", func() {
+		printer.Clean()
+		global := &model.GlobalRetentionPolicy{MessageDeletionEnabled: true, MessageRetentionCutoff: 1767225600000}
+
+		s.client.
+			EXPECT().
+			GetDataRetentionPolicy().
+			Return(global, &model.Response{}, nil).
+			Times(1)
+
+		err := dataRetentionGlobalCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{global}, printer.GetLines())
+	})
+
+	s.Run("Should list the policies", func() {
+		printer.Clean()
+		expectPolicies()
+
+		err := dataRetentionPolicyListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{policy}, printer.GetLines())
+	})
+
+	s.Run("Should show a policy with its teams and channels", func() {
+		printer.Clean()
+		expectPolicies()
+		s.client.
+			EXPECT().
+			GetTeamsForRetentionPolicy(policy.ID, 0, web.PerPageMaximum).
+			Return(&model.TeamsWithCount{Teams: []*model.Team{team}, TotalCount: 1}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelsForRetentionPolicy(policy.ID, 0, web.PerPageMaximum).
+			Return(&model.ChannelsWithCount{}, &model.Response{}, nil).
+			Times(1)
+
+		err := dataRetentionPolicyShowCmdF(s.client, &cobra.Command{}, []string{"Short retention"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should create a policy", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateDataRetentionPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+				RetentionPolicy: model.RetentionPolicy{DisplayName: "Short retention", PostDurationDays: model.NewInt64(30)},
+				TeamIDs:         []string{},
+				ChannelIDs:      []string{channel.Id},
+			}).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("display-name", "Short retention", "")
+		cmd.Flags().Int64("post-duration", 30, "")
+		cmd.Flags().StringSlice("teams", []string{}, "")
+		cmd.Flags().StringSlice("channels", []string{channel.Id}, "")
+
+		err := dataRetentionPolicyCreateCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{policy}, printer.GetLines())
+	})
+
+	s.Run("Should fail to create a policy with an invalid post duration", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("display-name", "Short retention", "")
+		cmd.Flags().Int64("post-duration", 0, "")
+		cmd.Flags().StringSlice("teams", []string{}, "")
+		cmd.Flags().StringSlice("channels", []string{}, "")
+
+		err := dataRetentionPolicyCreateCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the post duration must be at least one day")
+	})
+
+	s.Run("Should only update the flags given", func() {
+		printer.Clean()
+		expectPolicies()
+		updated := &model.RetentionPolicyWithTeamAndChannelCounts{
+			RetentionPolicy: model.RetentionPolicy{ID: policy.ID, DisplayName: policy.DisplayName, PostDurationDays: model.NewInt64(60)},
+		}
+		s.client.
+			EXPECT().
+			PatchDataRetentionPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+				RetentionPolicy: model.RetentionPolicy{ID: policy.ID, PostDurationDays: model.NewInt64(60)},
+			}).
+			Return(updated, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("display-name", "", "")
+		cmd.Flags().Int64("post-duration", 0, "")
+		s.Require().NoError(cmd.Flags().Set("post-duration", "60"))
+
+		err := dataRetentionPolicyUpdateCmdF(s.client, cmd, []string{policy.ID})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{updated}, printer.GetLines())
+	})
+
+	s.Run("Should fail to update without changes", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("display-name", "", "")
+		cmd.Flags().Int64("post-duration", 0, "")
+
+		err := dataRetentionPolicyUpdateCmdF(s.client, cmd, []string{policy.ID})
+		s.Require().EqualError(err, "either --display-name or --post-duration must be given")
+	})
+
+	s.Run("Should delete a policy", func() {
+		printer.Clean()
+		expectPolicies()
+		s.client.
+			EXPECT().
+			DeleteDataRetentionPolicy(policy.ID).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := dataRetentionPolicyDeleteCmdF(s.client, cmd, []string{policy.ID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should add teams to a policy", func() {
+		printer.Clean()
+		expectPolicies()
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			AddTeamsToRetentionPolicy(policy.ID, []string{team.Id}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := dataRetentionPolicyAddTeamsCmdF(s.client, &cobra.Command{}, []string{policy.ID, team.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should fail to remove channels that can't be found", func() {
+		expectPolicies()
+		s.client.
+			EXPECT().
+			GetChannel("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := dataRetentionPolicyRemoveChannelsCmdF(s.client, &cobra.Command{}, []string{policy.ID, "unknown"})
+		s.Require().EqualError(err, `unable to find channel "unknown"`)
+	})
+
+	s.Run("Should fail if removing the channels fails", func() {
+		expectPolicies()
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RemoveChannelsFromRetentionPolicy(policy.ID, []string{channel.Id}).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := dataRetentionPolicyRemoveChannelsCmdF(s.client, &cobra.Command{}, []string{policy.ID, channel.Id})
+		s.Require().EqualError(err, "failed to remove the channels from the policy: mock error")
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var DMCmd = &cobra.Command{
+	Use:   "dm",
+	Short: "Management of direct and group messages",
+}
+
+var DMCreateCmd = &cobra.Command{
+	Use:   "create [users]",
+	Short: "Create or find a direct message channel",
+	Long: `Get the direct message channel between the given users, creating it if it doesn't exist yet. If three or more users are given, a group message channel is used instead.
+If only one user is given, the channel is the one between the current user and the given user.`,
+	Example: `  dm create john.doe
+  dm create john.doe jane.doe
+  dm create john.doe jane.doe user@example.com`,
+	Args: cobra.RangeArgs(1, model.ChannelGroupMaxUsers),
+	RunE: withClient(dmCreateCmdF),
+}
+
+var DMPostCmd = &cobra.Command{
+	Use:   "post [users]",
+	Short: "Post a direct message",
+	Long:  `Post a message into the direct or group message channel between the given users, creating it if it doesn't exist yet. As with "dm create", if only one user is given, the channel is the one between the current user and the given user.`,
+	Example: `  dm post john.doe --message "your report is ready"
+  dm post john.doe jane.doe --message "please review the incident"`,
+	Args: cobra.RangeArgs(1, model.ChannelGroupMaxUsers),
+	RunE: withClient(dmPostCmdF),
+}
+
+var DMListCmd = &cobra.Command{
+	Use:     "list [user]",
+	Short:   "List the direct message channels of a user",
+	Long:    "List the direct and group message channels of a user, sorted by their last activity.",
+	Example: `  dm list john.doe`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(dmListCmdF),
+}
+
+func init() {
+	DMPostCmd.Flags().StringP("message", "m", "", "Message for the post")
+	_ = DMPostCmd.MarkFlagRequired("message")
+
+	DMCmd.AddCommand(
+		DMCreateCmd,
+		DMPostCmd,
+		DMListCmd,
+	)
+	RootCmd.AddCommand(DMCmd)
+}
+
+type dmChannel struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Members    []string `json:"members"`
+	LastPostAt int64    `json:"last_post_at"`
+}
+
+// getDMChannel returns the direct or group message channel of the
+// given users, adding the current user if only one is given
+func getDMChannel(c client.Client, userArgs []string) (*model.Channel, error) {
+	users, err := getUsersFromArgs(c, userArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, user := range users {
+		userIDs = append(userIDs, user.Id)
+	}
+
+	if len(userIDs) == 1 {
+		me, _, err := c.GetMe("")
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the current user: %w", err)
+		}
+		userIDs = append([]string{me.Id}, userIDs...)
+	}
+
+	if len(userIDs) == 2 {
+		channel, _, err := c.CreateDirectChannel(userIDs[0], userIDs[1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the direct message channel: %w", err)
+		}
+		return channel, nil
+	}
+
+	channel, _, err := c.CreateGroupChannel(userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the group message channel: %w", err)
+	}
+	return channel, nil
+}
+
+func dmCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	channel, err := getDMChannel(c, args)
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT("Channel {{.Id}} is ready", channel)
+	return nil
+}
+
+func dmPostCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	message, _ := cmd.Flags().GetString("message")
+	if message == "" {
+		return errors.New("message cannot be empty")
+	}
+
+	channel, err := getDMChannel(c, args)
+	if err != nil {
+		return err
+	}
+
+	post, _, err := c.CreatePost(&model.Post{
+		ChannelId: channel.Id,
+		Message:   message,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create post: %w", err)
+	}
+
+	printer.PrintT("Post {{.Id}} created", post)
+	return nil
+}
+
+func dmListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	channels, _, err := c.GetChannelsForUserWithLastDeleteAt(user.Id, 0)
+	if err != nil {
+		return fmt.Errorf("unable to get the channels of user %s: %w", user.Username, err)
+	}
+
+	dms := []*model.Channel{}
+	otherUserIDs := []string{}
+	for _, channel := range channels {
+		switch channel.Type {
+		case model.ChannelTypeDirect:
+			if otherUserID := channel.GetOtherUserIdForDM(user.Id); otherUserID != "" {
+				otherUserIDs = append(otherUserIDs, otherUserID)
+			}
+		case model.ChannelTypeGroup:
+		default:
+			continue
+		}
+		dms = append(dms, channel)
+	}
+
+	usernames := map[string]string{user.Id: user.Username}
+	if len(otherUserIDs) > 0 {
+		otherUsers, _, err := c.GetUsersByIds(otherUserIDs)
+		if err != nil {
+			return fmt.Errorf("unable to get the users of the direct message channels: %w", err)
+		}
+		for _, otherUser := range otherUsers {
+			usernames[otherUser.Id] = otherUser.Username
+		}
+	}
+
+	sort.Slice(dms, func(i, j int) bool {
+		return dms[i].LastPostAt > dms[j].LastPostAt
+	})
+
+	for _, channel := range dms {
+		result := &dmChannel{
+			ID:         channel.Id,
+			Type:       string(channel.Type),
+			LastPostAt: channel.LastPostAt,
+		}
+
+		if channel.Type == model.ChannelTypeDirect {
+			otherUserID := channel.GetOtherUserIdForDM(user.Id)
+			if otherUserID == "" {
+				// a direct channel of the user with themself
+				otherUserID = user.Id
+			}
+			username, ok := usernames[otherUserID]
+			if !ok {
+				username = otherUserID
+			}
+			result.Members = []string{username}
+		} else {
+			result.Members = strings.Split(channel.DisplayName, ", ")
+		}
+
+		printer.PrintT("{{.ID}}: {{range $i, $m := .Members}}{{if $i}}, {{end}}{{$m}}{{end}}{{if .LastPostAt}}, last activity at {{timestamp .LastPostAt}}{{else}}, no activity{{end}}", result)
+	}
+
+	return nil
+}
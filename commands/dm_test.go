@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestDMCreateCmd() {
+	me := &model.User{Id: model.NewId(), Username: "me"}
+	user1 := &model.User{Id: model.NewId(), Username: "user1", Email: "user1@example.com"}
+	user2 := &model.User{Id: model.NewId(), Username: "user2", Email: "user2@example.com"}
+	user3 := &model.User{Id: model.NewId(), Username: "user3", Email: "user3@example.com"}
+
+	expectUser := func(user *model.User) {
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should get the direct channel with the current user", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect}
+		expectUser(user1)
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(me, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateDirectChannel(me.Id, user1.Id).
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		err := dmCreateCmdF(s.client, &cobra.Command{}, []string{user1.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{channel}, printer.GetLines())
+	})
+
+	s.Run("Should get the direct channel between two users", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect}
+		expectUser(user1)
+		expectUser(user2)
+
+		s.client.
+			EXPECT().
+			CreateDirectChannel(user1.Id, user2.Id).
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		err := dmCreateCmdF(s.client, &cobra.Command{}, []string{user1.Email, user2.Email})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{channel}, printer.GetLines())
+	})
+
+	s.Run("Should get the group channel of three users", func() {
+		printer.Clean()
+		expectUser(user1)
+		expectUser(user2)
+		expectUser(user3)
+
+		s.client.
+			EXPECT().
+			CreateGroupChannel([]string{user1.Id, user2.Id, user3.Id}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := dmCreateCmdF(s.client, &cobra.Command{}, []string{user1.Email, user2.Email, user3.Email})
+		s.Require().EqualError(err, "unable to get the group message channel: mock error")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestDMPostCmd() {
+	user1 := &model.User{Id: model.NewId(), Username: "user1", Email: "user1@example.com"}
+	user2 := &model.User{Id: model.NewId(), Username: "user2", Email: "user2@example.com"}
+	channel := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect}
+
+	s.Run("Should fail with an empty message", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("message", "", "")
+
+		err := dmPostCmdF(s.client, cmd, []string{user1.Email})
+		s.Require().EqualError(err, "message cannot be empty")
+	})
+
+	s.Run("Should post into the direct channel", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("message", "some text", "")
+		post := &model.Post{Id: model.NewId(), ChannelId: channel.Id, Message: "some text"}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user1.Email, "").
+			Return(user1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user2.Email, "").
+			Return(user2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateDirectChannel(user1.Id, user2.Id).
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreatePost(&model.Post{ChannelId: channel.Id, Message: "some text"}).
+			Return(post, &model.Response{}, nil).
+			Times(1)
+
+		err := dmPostCmdF(s.client, cmd, []string{user1.Email, user2.Email})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{post}, printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestDMListCmd() {
+	user := &model.User{Id: model.NewId(), Username: "user", Email: "user@example.com"}
+	other := &model.User{Id: model.NewId(), Username: "other"}
+
+	s.Run("Should list the direct and group channels sorted by activity", func() {
+		printer.Clean()
+		direct := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect, Name: model.GetDMNameFromIds(user.Id, other.Id), LastPostAt: 100}
+		self := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect, Name: model.GetDMNameFromIds(user.Id, user.Id)}
+		group := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeGroup, DisplayName: "other, user, third", LastPostAt: 200}
+		open := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeOpen, LastPostAt: 300}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForUserWithLastDeleteAt(user.Id, 0).
+			Return([]*model.Channel{direct, self, group, open}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{other.Id}).
+			Return([]*model.User{other}, &model.Response{}, nil).
+			Times(1)
+
+		err := dmListCmdF(s.client, &cobra.Command{}, []string{user.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&dmChannel{ID: group.Id, Type: "G", Members: []string{"other", "user", "third"}, LastPostAt: 200},
+			&dmChannel{ID: direct.Id, Type: "D", Members: []string{"other"}, LastPostAt: 100},
+			&dmChannel{ID: self.Id, Type: "D", Members: []string{"user"}},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the channels cannot be retrieved", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForUserWithLastDeleteAt(user.Id, 0).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := dmListCmdF(s.client, &cobra.Command{}, []string{user.Email})
+		s.Require().EqualError(err, "unable to get the channels of user user: mock error")
+	})
+}
@@ -0,0 +1,445 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const (
+	doctorStatusOK      = "ok"
+	doctorStatusWarning = "warning"
+	doctorStatusError   = "error"
+
+	doctorMaxClockSkew     = time.Minute
+	doctorCertExpiryMargin = 14 * 24 * time.Hour
+)
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor [server-url]",
+	Short: "Diagnose connection problems with a server",
+	Long: `Check the DNS resolution, proxy settings, TLS handshake, HTTP/2 support, API reachability, clock skew and websocket upgrade against a server, and print the findings along with advice to fix them. If the requests to the server go through a proxy, the checks connect to the server through it.
+The server of the current credentials is checked if no URL is given. No authentication is needed, so the command can be used to diagnose why logging in fails.`,
+	Example: `  doctor
+  doctor https://mattermost.example.com`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: doctorCmdF,
+}
+
+func init() {
+	DoctorCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of each of the checks")
+
+	RootCmd.AddCommand(DoctorCmd)
+}
+
+type doctorFinding struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Advice string `json:"advice,omitempty"`
+}
+
+type doctor struct {
+	url     *url.URL
+	timeout time.Duration
+	// rootCAs overrides the system certificate pool when set
+	rootCAs *x509.CertPool
+	// proxy selects the proxy of the requests, as in http.Transport
+	proxy func(*http.Request) (*url.URL, error)
+}
+
+func parseDoctorURL(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server URL scheme %q", u.Scheme)
+	}
+
+	return u, nil
+}
+
+func (d *doctor) address() string {
+	port := d.url.Port()
+	if port == "" {
+		port = "443"
+		if d.url.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(d.url.Hostname(), port)
+}
+
+func (d *doctor) tlsConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		ServerName: d.url.Hostname(),
+		RootCAs:    d.rootCAs,
+		NextProtos: nextProtos,
+		// old versions are accepted so they can be reported
+		MinVersion: tls.VersionTLS10,
+	}
+}
+
+func (d *doctor) checkDNS() doctorFinding {
+	finding := doctorFinding{Check: "dns"}
+	host := d.url.Hostname()
+	if net.ParseIP(host) != nil {
+		finding.Status = doctorStatusOK
+		finding.Detail = host + " is an IP address, no resolution needed"
+		return finding
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		finding.Status = doctorStatusError
+		finding.Detail = err.Error()
+		finding.Advice = "Check the server name and the DNS configuration of this machine, e.g. the VPN connection or /etc/hosts."
+		return finding
+	}
+
+	finding.Status = doctorStatusOK
+	finding.Detail = fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", "))
+	return finding
+}
+
+func (d *doctor) checkProxy() doctorFinding {
+	finding := doctorFinding{Check: "proxy", Status: doctorStatusOK}
+	proxyURL, err := d.proxy(&http.Request{URL: d.url})
+	switch {
+	case err != nil:
+		finding.Status = doctorStatusError
+		finding.Detail = "invalid proxy configuration: " + err.Error()
+		finding.Advice = "Fix the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables."
+	case proxyURL != nil:
+		finding.Status = doctorStatusWarning
+		finding.Detail = "requests to the server go through the proxy " + proxyURL.Redacted()
+		finding.Advice = fmt.Sprintf("If the connection fails, the proxy can be bypassed by adding %s to the NO_PROXY environment variable.", d.url.Hostname())
+	default:
+		finding.Detail = "no proxy is used to reach the server"
+	}
+
+	return finding
+}
+
+// dial opens a connection to the server, through a CONNECT tunnel if
+// the requests to the server go through a proxy, returning the address
+// that was connected to
+func (d *doctor) dial() (net.Conn, string, error) {
+	proxyURL, err := d.proxy(&http.Request{URL: d.url})
+	if err != nil {
+		return nil, "", err
+	}
+	if proxyURL == nil {
+		conn, dialErr := net.DialTimeout("tcp", d.address(), d.timeout)
+		return conn, d.address(), dialErr
+	}
+
+	proxyAddress := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddress = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", proxyAddress, d.timeout)
+	if err != nil {
+		return nil, proxyAddress, err
+	}
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname(), RootCAs: d.rootCAs, MinVersion: tls.VersionTLS12})
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(d.timeout))
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: d.address()},
+		Host:   d.address(),
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		connectReq.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err = connectReq.Write(conn); err != nil {
+		conn.Close()
+		return nil, proxyAddress, err
+	}
+	// the server sends nothing before the client does, so the reader
+	// can't buffer anything past the response of the proxy
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+	if err != nil {
+		conn.Close()
+		return nil, proxyAddress, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, proxyAddress, fmt.Errorf("the proxy %s answered the connection to %s with status %q", proxyURL.Redacted(), d.address(), resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, proxyAddress, nil
+}
+
+// dialTLS performs the TLS handshake with the server over a connection
+// opened with dial
+func (d *doctor) dialTLS(config *tls.Config) (*tls.Conn, error) {
+	conn, _, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, config)
+	_ = tlsConn.SetDeadline(time.Now().Add(d.timeout))
+	if handshakeErr := tlsConn.Handshake(); handshakeErr != nil {
+		conn.Close()
+		return nil, handshakeErr
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	return tlsConn, nil
+}
+
+func (d *doctor) checkTCP() doctorFinding {
+	finding := doctorFinding{Check: "tcp"}
+	start := time.Now()
+	conn, address, err := d.dial()
+	if err != nil {
+		finding.Status = doctorStatusError
+		finding.Detail = err.Error()
+		finding.Advice = "Check that the server is running and that no firewall blocks the port. If a proxy is required to reach the server, set the HTTPS_PROXY environment variable."
+		if address != "" && address != d.address() {
+			finding.Advice = "Check that the proxy is running and that it allows connections to the port of the server, or bypass it by adding the server to the NO_PROXY environment variable."
+		}
+		return finding
+	}
+	conn.Close()
+
+	finding.Status = doctorStatusOK
+	finding.Detail = fmt.Sprintf("connected to %s in %dms", d.address(), time.Since(start).Milliseconds())
+	if address != d.address() {
+		finding.Detail = fmt.Sprintf("connected to %s through the proxy %s in %dms", d.address(), address, time.Since(start).Milliseconds())
+	}
+	return finding
+}
+
+// checkTLS performs the TLS handshake with the server, returning the
+// findings about the handshake and about the HTTP/2 support
+func (d *doctor) checkTLS() []doctorFinding {
+	finding := doctorFinding{Check: "tls"}
+	conn, err := d.dialTLS(d.tlsConfig("h2", "http/1.1"))
+	if err != nil {
+		finding.Status = doctorStatusError
+		finding.Detail = err.Error()
+
+		// the handshake is done again without verification to
+		// report the certificate that the server presented
+		insecureConfig := d.tlsConfig("h2", "http/1.1")
+		insecureConfig.InsecureSkipVerify = true
+		if insecureConn, insecureErr := d.dialTLS(insecureConfig); insecureErr == nil {
+			certs := insecureConn.ConnectionState().PeerCertificates
+			insecureConn.Close()
+			if len(certs) > 0 {
+				finding.Detail += fmt.Sprintf(" (certificate for %q issued by %q)", certs[0].Subject.CommonName, certs[0].Issuer.CommonName)
+			}
+			finding.Advice = "The certificate of the server can't be verified. If the issuer is not the expected one, a proxy or an antivirus may be intercepting TLS connections, otherwise add the issuing CA to the certificate store of this machine."
+		} else {
+			finding.Advice = "The TLS handshake failed. Check that the server listens for HTTPS connections on this port."
+		}
+		return []doctorFinding{finding}
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	leaf := state.PeerCertificates[0]
+	finding.Status = doctorStatusOK
+	finding.Detail = fmt.Sprintf("%s with %s, certificate for %q issued by %q valid until %s",
+		tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite),
+		leaf.Subject.CommonName, leaf.Issuer.CommonName, leaf.NotAfter.Format(time.RFC3339))
+
+	switch {
+	case state.Version < tls.VersionTLS12:
+		finding.Status = doctorStatusWarning
+		finding.Advice = "The server only supports TLS versions older than 1.2, so the --insecure-tls-version flag is needed to connect to it."
+	case len(state.VerifiedChains) > 0 && !isValidChain(state.VerifiedChains[0]):
+		finding.Status = doctorStatusWarning
+		finding.Advice = "The certificate chain uses insecure signature algorithms, so the --insecure-sha1-intermediate flag is needed to connect to the server."
+	case time.Until(leaf.NotAfter) < doctorCertExpiryMargin:
+		finding.Status = doctorStatusWarning
+		finding.Advice = "The certificate of the server expires soon, it should be renewed."
+	}
+
+	http2 := doctorFinding{Check: "http2", Status: doctorStatusOK}
+	if state.NegotiatedProtocol == "h2" {
+		http2.Detail = "the server supports HTTP/2"
+	} else {
+		http2.Status = doctorStatusWarning
+		http2.Detail = "the server does not support HTTP/2"
+		http2.Advice = "Connections fall back to HTTP/1.1, which works but is slower for the web and desktop apps. If the server is behind a proxy, check that HTTP/2 is enabled on it."
+	}
+
+	return []doctorFinding{finding, http2}
+}
+
+// checkAPI sends a ping to the API, returning the findings about the
+// reachability of the API and about the clock skew with the server
+func (d *doctor) checkAPI() []doctorFinding {
+	finding := doctorFinding{Check: "api"}
+	httpClient := &http.Client{
+		Timeout: d.timeout,
+		Transport: &http.Transport{
+			Proxy:           d.proxy,
+			TLSClientConfig: d.tlsConfig(),
+		},
+	}
+
+	pingURL := strings.TrimSuffix(d.url.String(), "/") + "/api/v4/system/ping"
+	start := time.Now()
+	resp, err := httpClient.Get(pingURL)
+	if err != nil {
+		finding.Status = doctorStatusError
+		finding.Detail = err.Error()
+		finding.Advice = "The API can't be reached, check the findings above."
+		return []doctorFinding{finding}
+	}
+	resp.Body.Close()
+	latency := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		finding.Status = doctorStatusError
+		finding.Detail = fmt.Sprintf("%s responded with status %d", pingURL, resp.StatusCode)
+		finding.Advice = "Check that the URL points to the Mattermost server and not to another site, and that the proxies in front of it forward the /api/v4 routes."
+		return []doctorFinding{finding}
+	}
+	finding.Status = doctorStatusOK
+	finding.Detail = fmt.Sprintf("the API responded in %dms", latency.Milliseconds())
+
+	clock := doctorFinding{Check: "clock"}
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		clock.Status = doctorStatusWarning
+		clock.Detail = "the server did not send its time"
+		return []doctorFinding{finding, clock}
+	}
+
+	// the Date header has a precision of one second
+	skew := time.Since(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	clock.Status = doctorStatusOK
+	clock.Detail = fmt.Sprintf("the clock of this machine is %s apart from the server one", skew)
+	if skew > doctorMaxClockSkew {
+		clock.Status = doctorStatusWarning
+		clock.Advice = "Synchronize the clock of this machine and of the server with NTP, clock skew breaks MFA tokens and certificate validation."
+	}
+
+	return []doctorFinding{finding, clock}
+}
+
+func (d *doctor) checkWebSocket() doctorFinding {
+	finding := doctorFinding{Check: "websocket"}
+	wsURL := *d.url
+	wsURL.Scheme = strings.Replace(wsURL.Scheme, "http", "ws", 1)
+	wsURL.Path = strings.TrimSuffix(wsURL.Path, "/") + "/api/v4/websocket"
+
+	dialer := &websocket.Dialer{
+		Proxy:            d.proxy,
+		TLSClientConfig:  d.tlsConfig(),
+		HandshakeTimeout: d.timeout,
+	}
+	conn, resp, err := dialer.Dial(wsURL.String(), nil)
+	if err != nil {
+		finding.Status = doctorStatusError
+		finding.Detail = err.Error()
+		if resp != nil {
+			finding.Detail = fmt.Sprintf("the upgrade was answered with status %d", resp.StatusCode)
+		}
+		finding.Advice = "The websocket connection can't be established, so real time events won't work. Check that the proxies in front of the server forward the Upgrade and Connection headers."
+		return finding
+	}
+	conn.Close()
+
+	finding.Status = doctorStatusOK
+	finding.Detail = "the websocket connection was upgraded"
+	return finding
+}
+
+func (d *doctor) run() []doctorFinding {
+	findings := []doctorFinding{d.checkDNS(), d.checkProxy()}
+
+	tcp := d.checkTCP()
+	findings = append(findings, tcp)
+	if tcp.Status == doctorStatusError {
+		return findings
+	}
+
+	if d.url.Scheme == "https" {
+		findings = append(findings, d.checkTLS()...)
+	}
+	findings = append(findings, d.checkAPI()...)
+	findings = append(findings, d.checkWebSocket())
+
+	return findings
+}
+
+func doctorCmdF(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	var rawURL string
+	if len(args) > 0 {
+		rawURL = args[0]
+	} else {
+		credentials, err := GetCurrentCredentials()
+		if err != nil {
+			return errors.Wrap(err, "no server URL given and no current credentials found")
+		}
+		rawURL = credentials.InstanceURL
+	}
+
+	u, err := parseDoctorURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	d := &doctor{url: u, timeout: timeout, proxy: http.ProxyFromEnvironment}
+	failed := 0
+	for _, finding := range d.run() {
+		if finding.Status == doctorStatusError {
+			failed++
+		}
+		printer.PrintT("[{{.Status}}] {{.Check}}: {{.Detail}}{{if .Advice}}\n  {{.Advice}}{{end}}", finding)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/shared/templates"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var EmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Management of email notifications",
+}
+
+var EmailTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Management of email templates",
+}
+
+var EmailTemplateTestRenderCmd = &cobra.Command{
+	Use:   "test-render [template]",
+	Short: "Preview an email template locally with sample data",
+	Long: `Renders a notification email template locally with sample data and prints the resulting HTML, as a preview: the server doesn't render anything.
+The server does not expose an endpoint to render its templates, so they are read from the given local templates directory, usually a copy of the "templates" folder of the server installation, and rendered with the same template engine the server uses. The preview can differ from the emails the server sends if the directory doesn't match the templates of the server.
+The site URL and site name of the connected server are used as sample data, and any further props can be provided in a JSON file with the "props" and "html" maps the templates expect.`,
+	Example: `  email template test-render reset_body --templates-dir /opt/mattermost/templates
+  email template test-render invite_body --templates-dir ./templates --data sample.json > invite.html`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(emailTemplateTestRenderCmdF),
+}
+
+func init() {
+	EmailTemplateTestRenderCmd.Flags().String("templates-dir", "", "Directory containing the server email templates")
+	EmailTemplateTestRenderCmd.Flags().String("data", "", "JSON file with the sample props to render the template with")
+	_ = EmailTemplateTestRenderCmd.MarkFlagRequired("templates-dir")
+
+	EmailTemplateCmd.AddCommand(
+		EmailTemplateTestRenderCmd,
+	)
+	EmailCmd.AddCommand(
+		EmailTemplateCmd,
+	)
+	RootCmd.AddCommand(EmailCmd)
+}
+
+// getSampleTemplateData builds the data to render a template with,
+// using the server branding as a base for the props of the data file
+func getSampleTemplateData(c client.Client, dataPath string) (templates.Data, error) {
+	data := templates.Data{}
+	if dataPath != "" {
+		b, err := ioutil.ReadFile(dataPath)
+		if err != nil {
+			return data, fmt.Errorf("cannot read data file: %w", err)
+		}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return data, fmt.Errorf("cannot parse data file: %w", err)
+		}
+	}
+
+	clientConfig, _, err := c.GetOldClientConfig("")
+	if err != nil {
+		return data, fmt.Errorf("unable to get the server branding: %w", err)
+	}
+
+	if data.Props == nil {
+		data.Props = map[string]interface{}{}
+	}
+	for _, key := range []string{"SiteURL", "SiteName"} {
+		if _, ok := data.Props[key]; !ok {
+			data.Props[key] = clientConfig[key]
+		}
+	}
+
+	return data, nil
+}
+
+func emailTemplateTestRenderCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	templatesDir, _ := cmd.Flags().GetString("templates-dir")
+	dataPath, _ := cmd.Flags().GetString("data")
+
+	container, err := templates.New(templatesDir)
+	if err != nil {
+		return errors.Wrap(err, "unable to load the templates")
+	}
+
+	data, err := getSampleTemplateData(c, dataPath)
+	if err != nil {
+		return err
+	}
+
+	html, err := container.RenderToString(strings.TrimSuffix(args[0], ".html"), data)
+	if err != nil {
+		return errors.Wrapf(err, "unable to render template %q", args[0])
+	}
+
+	printer.SetNoNewline(true)
+	printer.Print(html)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestEmailTemplateTestRenderCmd() {
+	writeTemplates := func(dir string) {
+		content := `{{define "reset_body"}}<a href="{{.Props.SiteURL}}">{{.Props.SiteName}}</a> {{.Props.Title}}{{end}}`
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "reset_body.html"), []byte(content), 0600))
+	}
+
+	newCmd := func(templatesDir, data string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("templates-dir", templatesDir, "")
+		cmd.Flags().String("data", data, "")
+		return cmd
+	}
+
+	clientConfig := map[string]string{"SiteURL": "https://chat.example.com", "SiteName": "Example Chat"}
+
+	s.Run("Should render the template with the server branding", func() {
+		printer.Clean()
+		defer printer.SetNoNewline(false)
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeTemplates(tmp)
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(clientConfig, &model.Response{}, nil).
+			Times(1)
+
+		err := emailTemplateTestRenderCmdF(s.client, newCmd(tmp, ""), []string{"reset_body.html"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(`<a href="https://chat.example.com">Example Chat</a> `, printer.GetLines()[0])
+	})
+
+	s.Run("Should render the template with the props of the data file", func() {
+		printer.Clean()
+		defer printer.SetNoNewline(false)
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeTemplates(tmp)
+		dataPath := filepath.Join(tmp, "data.json")
+		s.Require().NoError(ioutil.WriteFile(dataPath, []byte(`{"props": {"Title": "Reset your password", "SiteName": "Branded"}}`), 0600))
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(clientConfig, &model.Response{}, nil).
+			Times(1)
+
+		err := emailTemplateTestRenderCmdF(s.client, newCmd(tmp, dataPath), []string{"reset_body"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(`<a href="https://chat.example.com">Branded</a> Reset your password`, printer.GetLines()[0])
+	})
+
+	s.Run("Should fail if the template does not exist", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeTemplates(tmp)
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(clientConfig, &model.Response{}, nil).
+			Times(1)
+
+		err := emailTemplateTestRenderCmdF(s.client, newCmd(tmp, ""), []string{"invite_body"})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), `unable to render template "invite_body"`)
+	})
+
+	s.Run("Should fail if the templates directory has no templates", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		err := emailTemplateTestRenderCmdF(s.client, newCmd(tmp, ""), []string{"reset_body"})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "unable to load the templates")
+	})
+
+	s.Run("Should fail if the server branding cannot be fetched", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeTemplates(tmp)
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := emailTemplateTestRenderCmdF(s.client, newCmd(tmp, ""), []string{"reset_body"})
+		s.Require().EqualError(err, "unable to get the server branding: mock error")
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// entityExpander embeds the related entities requested with the
+// --expand flag into the printed entities. The related entities are
+// cached, so each of them is fetched only once
+type entityExpander struct {
+	c      client.Client
+	fields map[string]bool
+	users  map[string]*model.User
+	teams  map[string]*model.Team
+}
+
+type expandedChannel struct {
+	*model.Channel
+	Creator *model.User `json:"creator,omitempty"`
+	Team    *model.Team `json:"team,omitempty"`
+}
+
+// newEntityExpander reads the --expand flag of the command, failing if
+// any of the fields isn't one of the supported ones
+func newEntityExpander(c client.Client, cmd *cobra.Command, supported ...string) (*entityExpander, error) {
+	fields, _ := cmd.Flags().GetStringSlice("expand")
+
+	e := &entityExpander{c: c, fields: map[string]bool{}, users: map[string]*model.User{}, teams: map[string]*model.Team{}}
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !isSupportedExpandField(field, supported) {
+			sort.Strings(supported)
+			return nil, fmt.Errorf("invalid expand field %q, the supported fields are %s", field, strings.Join(supported, ", "))
+		}
+		e.fields[field] = true
+	}
+	return e, nil
+}
+
+func isSupportedExpandField(field string, supported []string) bool {
+	for _, s := range supported {
+		if s == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *entityExpander) enabled() bool {
+	return len(e.fields) > 0
+}
+
+// addTeam caches a team that the command has already fetched
+func (e *entityExpander) addTeam(team *model.Team) {
+	e.teams[team.Id] = team
+}
+
+// fetchUsers fetches the users that aren't cached yet in a single
+// request
+func (e *entityExpander) fetchUsers(userIDs []string) {
+	missing := []string{}
+	for _, userID := range userIDs {
+		if _, ok := e.users[userID]; !ok && userID != "" {
+			missing = append(missing, userID)
+			e.users[userID] = nil
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	users, _, err := e.c.GetUsersByIds(missing)
+	if err != nil {
+		printer.PrintError("unable to expand the users: " + err.Error())
+		return
+	}
+	for _, user := range users {
+		e.users[user.Id] = user
+	}
+}
+
+func (e *entityExpander) team(teamID string) *model.Team {
+	if team, ok := e.teams[teamID]; ok {
+		return team
+	}
+
+	team, _, err := e.c.GetTeam(teamID, "")
+	if err != nil {
+		printer.PrintError(fmt.Sprintf("unable to expand team %q: %s", teamID, err))
+	}
+	e.teams[teamID] = team
+	return team
+}
+
+// channels returns the channels ready to be printed, embedding the
+// requested related entities. If no entity was requested, the
+// channels are returned as they are
+func (e *entityExpander) channels(channels []*model.Channel) []interface{} {
+	if e.fields["creator"] {
+		creatorIDs := make([]string, 0, len(channels))
+		for _, channel := range channels {
+			creatorIDs = append(creatorIDs, channel.CreatorId)
+		}
+		e.fetchUsers(creatorIDs)
+	}
+
+	result := make([]interface{}, 0, len(channels))
+	for _, channel := range channels {
+		if !e.enabled() {
+			result = append(result, channel)
+			continue
+		}
+
+		expanded := &expandedChannel{Channel: channel}
+		if e.fields["creator"] {
+			expanded.Creator = e.users[channel.CreatorId]
+		}
+		if e.fields["team"] && channel.TeamId != "" {
+			expanded.Team = e.team(channel.TeamId)
+		}
+		result = append(result, expanded)
+	}
+	return result
+}
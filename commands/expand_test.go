@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestListChannelsCmdExpand() {
+	newCmd := func(expand string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("expand", nil, "")
+		_ = cmd.Flags().Set("expand", expand)
+		return cmd
+	}
+
+	team := &model.Team{Id: "team-id", Name: "team"}
+	john := &model.User{Id: "john-id", Username: "john"}
+	jane := &model.User{Id: "jane-id", Username: "jane"}
+	public1 := &model.Channel{Id: "public-1", Name: "public-1", TeamId: team.Id, CreatorId: john.Id}
+	public2 := &model.Channel{Id: "public-2", Name: "public-2", TeamId: team.Id, CreatorId: jane.Id}
+	private := &model.Channel{Id: "private", Name: "private", TeamId: team.Id, CreatorId: john.Id, Type: model.ChannelTypePrivate}
+
+	s.Run("Should embed the creator and the team of the channels", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{public1, public2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{john.Id, jane.Id}).
+			Return([]*model.User{john, jane}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetDeletedChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return([]*model.Channel{private}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+			Return([]*model.Channel{}, &model.Response{}, nil).
+			Times(1)
+
+		err := listChannelsCmdF(s.client, newCmd("creator,team"), []string{team.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&expandedChannel{Channel: public1, Creator: john, Team: team},
+			&expandedChannel{Channel: public2, Creator: jane, Team: team},
+			&expandedChannel{Channel: private, Creator: john, Team: team},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail with an unsupported field", func() {
+		printer.Clean()
+
+		err := listChannelsCmdF(s.client, newCmd("posts"), []string{team.Id})
+		s.Require().EqualError(err, `invalid expand field "posts", the supported fields are creator, team`)
+	})
+}
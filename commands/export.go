@@ -5,7 +5,6 @@ package commands
 
 import (
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/mattermost/mmctl/v6/client"
@@ -30,11 +29,19 @@ var ExportCreateCmd = &cobra.Command{
 var ExportDownloadCmd = &cobra.Command{
 	Use:   "download [exportname] [filepath]",
 	Short: "Download export files",
+	Long: `Download export files using HTTP range requests, so that a failed transfer is retried from the last downloaded byte, and an interrupted download can be resumed with --resume.
+With --chunk-size the file is requested in chunks, each one retried up to --num-retries times. A progress bar with the transfer rate and the time left is shown when the errors are printed to a terminal and the output isn't JSON, and the SHA-256 digest of the file is printed once downloaded, and checked against --expect-sha256 if set.`,
 	Example: `  # you can indicate the name of the export and its destination path
   $ mmctl export download samplename sample_export.zip
   
   # or if you only indicate the name, the path would match it
-  $ mmctl export download sample_export.zip`,
+  $ mmctl export download sample_export.zip
+
+  # resume an interrupted download in chunks of 64MB
+  $ mmctl export download sample_export.zip --resume --chunk-size 64M
+
+  # encrypt the downloaded file to a GPG recipient
+  $ mmctl export download sample_export.zip --gpg-recipient admin@example.com`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: withClient(exportDownloadCmdF),
 }
@@ -84,13 +91,13 @@ func init() {
 	_ = ExportCreateCmd.Flags().MarkDeprecated("attachments", "the tool now includes attachments by default. The flag will be removed in a future version.")
 
 	ExportCreateCmd.Flags().Bool("no-attachments", false, "Set to true to exclude file attachments in the export file.")
+	addJobWaitFlags(ExportCreateCmd)
 
-	ExportDownloadCmd.Flags().Bool("resume", false, "Set to true to resume an export download.")
-	_ = ExportDownloadCmd.Flags().MarkHidden("resume")
-	// Intentionally the message does not start with a capital letter because
-	// cobra prepends "Flag --resume has been deprecated,"
-	_ = ExportDownloadCmd.Flags().MarkDeprecated("resume", "the tool now resumes a download automatically. The flag will be removed in a future version.")
-	ExportDownloadCmd.Flags().Int("num-retries", 5, "Number of retries to do to resume a download.")
+	ExportDownloadCmd.Flags().Bool("resume", false, "Resume the download of an existing partially downloaded file instead of failing.")
+	ExportDownloadCmd.Flags().Int("num-retries", 5, "Number of retries of each failed chunk.")
+	ExportDownloadCmd.Flags().String("chunk-size", "", "Download the file in chunks of this size in bytes, optionally followed by K, M or G, e.g. 64M. By default it's downloaded in a single request.")
+	ExportDownloadCmd.Flags().String("expect-sha256", "", "Fail if the SHA-256 digest of the downloaded file doesn't match.")
+	ExportDownloadCmd.Flags().String("limit-rate", "", "Maximum download rate in bytes per second, optionally followed by K, M or G, e.g. 500K.")
 
 	ExportJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of export jobs")
 	ExportJobListCmd.Flags().Int("per-page", 200, "Number of export jobs to be fetched")
@@ -128,7 +135,7 @@ func exportCreateCmdF(c client.Client, command *cobra.Command, args []string) er
 
 	printer.PrintT("Export process job successfully created, ID: {{.Id}}", job)
 
-	return nil
+	return waitForJobIfRequested(c, command, job)
 }
 
 func exportListCmdF(c client.Client, command *cobra.Command, args []string) error {
@@ -172,6 +179,28 @@ func exportDownloadCmdF(c client.Client, command *cobra.Command, args []string)
 	}
 
 	retries, _ := command.Flags().GetInt("num-retries")
+	resume, _ := command.Flags().GetBool("resume")
+
+	limitRate, _ := command.Flags().GetString("limit-rate")
+	rate, err := parseRate(limitRate)
+	if err != nil {
+		return err
+	}
+
+	chunkSizeArg, _ := command.Flags().GetString("chunk-size")
+	chunkSize, err := parseByteSize(chunkSizeArg)
+	if err != nil {
+		return fmt.Errorf("invalid chunk size %q, must be a positive number of bytes optionally followed by K, M or G", chunkSizeArg)
+	}
+
+	// gpg and the recipients are checked before the download, as
+	// exports can take long to download
+	recipients, _ := command.Flags().GetStringSlice("gpg-recipient")
+	if len(recipients) > 0 {
+		if err := checkGPGRecipients(recipients); err != nil {
+			return err
+		}
+	}
 
 	var outFile *os.File
 	info, err := os.Stat(path)
@@ -179,7 +208,7 @@ func exportDownloadCmdF(c client.Client, command *cobra.Command, args []string)
 	case err != nil && !os.IsNotExist(err):
 		// some error occurred and not because file doesn't exist
 		return fmt.Errorf("failed to stat export file: %w", err)
-	case err == nil && info.Size() > 0:
+	case err == nil && info.Size() > 0 && !resume:
 		// we exit to avoid overwriting an existing non-empty file
 		return fmt.Errorf("export file already exists")
 	case err != nil:
@@ -195,28 +224,46 @@ func exportDownloadCmdF(c client.Client, command *cobra.Command, args []string)
 	}
 	defer outFile.Close()
 
-	i := 0
-	for i < retries+1 {
-		off, err := outFile.Seek(0, io.SeekEnd)
-		if err != nil {
-			return fmt.Errorf("failed to seek export file: %w", err)
-		}
+	if err := downloadExportFile(c, name, outFile, exportDownloadOptions{
+		retries:   retries,
+		chunkSize: chunkSize,
+		rate:      rate,
+		progress:  newTransferProgress(name),
+	}); err != nil {
+		return err
+	}
 
-		if _, _, err := c.DownloadExport(name, outFile, off); err != nil {
-			printer.PrintWarning(fmt.Sprintf("failed to download export file: %v. Retrying...", err))
-			i++
-			continue
+	expectedSHA256, _ := command.Flags().GetString("expect-sha256")
+	digest, err := checkFileSHA256(path, expectedSHA256)
+	if err != nil {
+		if len(recipients) > 0 {
+			outFile.Close()
+			return removeUnencryptedExport(path, err)
 		}
-		break
+		return err
 	}
+	printSHA256(path, digest)
 
-	if retries != 0 && i == retries+1 {
-		return fmt.Errorf("failed to download export after %d retries", retries)
+	if len(recipients) > 0 {
+		outFile.Close()
+		if err := encryptExportFile(path, recipients, false); err != nil {
+			return removeUnencryptedExport(path, err)
+		}
 	}
 
 	return nil
 }
 
+// removeUnencryptedExport removes the downloaded export when it was to
+// be encrypted and a step failed, so the unencrypted export is not left
+// behind, returning the error of the step
+func removeUnencryptedExport(path string, err error) error {
+	if rErr := os.Remove(path); rErr != nil {
+		return fmt.Errorf("%w, and failed to remove the unencrypted export file: %s", err, rErr)
+	}
+	return err
+}
+
 func exportJobListCmdF(c client.Client, command *cobra.Command, args []string) error {
 	return jobListCmdF(c, command, model.JobTypeExportProcess)
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/commands/importer"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ExportCompareCmd = &cobra.Command{
+	Use:   "compare [old-archive] [new-archive]",
+	Short: "Compare two export archives",
+	Long: `Compare the contents of two export archives, printing the teams, channels and users that were added or removed, and the changes of the number of posts of each channel, including the replies.
+This command runs locally and doesn't need a connection to a server, and is useful to validate that an incremental migration captured what was expected.`,
+	Example: `  export compare export-2021-06-01.zip export-2021-07-01.zip`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    exportCompareCmdF,
+}
+
+func init() {
+	ExportCmd.AddCommand(ExportCompareCmd)
+}
+
+const directPostsName = "direct messages"
+
+// exportSummary holds the entities of an export archive, along with the
+// number of posts of each channel
+type exportSummary struct {
+	teams    map[string]bool
+	channels map[string]bool
+	users    map[string]bool
+	posts    map[string]int
+}
+
+type exportChange struct {
+	Entity string `json:"entity"`
+	Name   string `json:"name"`
+	Change string `json:"change"`
+	Old    int    `json:"old"`
+	New    int    `json:"new"`
+}
+
+func countImportReplies(replies *[]importer.ReplyImportData) int {
+	if replies == nil {
+		return 0
+	}
+	return len(*replies)
+}
+
+func readExportSummary(archivePath string) (*exportSummary, error) {
+	z, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the archive %q: %w", archivePath, err)
+	}
+	defer z.Close()
+
+	var jsonlZip *zip.File
+	for _, zfile := range z.File {
+		if filepath.Ext(zfile.Name) == ".jsonl" {
+			jsonlZip = zfile
+			break
+		}
+	}
+	if jsonlZip == nil {
+		return nil, fmt.Errorf("could not find a .jsonl file in the archive %q", archivePath)
+	}
+
+	f, err := jsonlZip.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the archive %q: %w", archivePath, err)
+	}
+	defer f.Close()
+
+	summary := &exportSummary{
+		teams:    map[string]bool{},
+		channels: map[string]bool{},
+		users:    map[string]bool{},
+		posts:    map[string]int{},
+	}
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNumber := 1; s.Scan(); lineNumber++ {
+		var line importer.LineImportData
+		if err := json.Unmarshal(s.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("error parsing line %d of the archive %q: %w", lineNumber, archivePath, err)
+		}
+
+		switch {
+		case line.Team != nil && line.Team.Name != nil:
+			summary.teams[*line.Team.Name] = true
+		case line.Channel != nil && line.Channel.Team != nil && line.Channel.Name != nil:
+			summary.channels[*line.Channel.Team+":"+*line.Channel.Name] = true
+		case line.User != nil && line.User.Username != nil:
+			summary.users[*line.User.Username] = true
+		case line.Post != nil && line.Post.Team != nil && line.Post.Channel != nil:
+			summary.posts[*line.Post.Team+":"+*line.Post.Channel] += 1 + countImportReplies(line.Post.Replies)
+		case line.DirectPost != nil:
+			summary.posts[directPostsName] += 1 + countImportReplies(line.DirectPost.Replies)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("error reading the archive %q: %w", archivePath, err)
+	}
+
+	return summary, nil
+}
+
+func compareExportSets(entity string, oldSet, newSet map[string]bool) []*exportChange {
+	changes := []*exportChange{}
+	for name := range newSet {
+		if !oldSet[name] {
+			changes = append(changes, &exportChange{Entity: entity, Name: name, Change: "added"})
+		}
+	}
+	for name := range oldSet {
+		if !newSet[name] {
+			changes = append(changes, &exportChange{Entity: entity, Name: name, Change: "removed"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func compareExportSummaries(oldSummary, newSummary *exportSummary) []*exportChange {
+	changes := compareExportSets("team", oldSummary.teams, newSummary.teams)
+	changes = append(changes, compareExportSets("channel", oldSummary.channels, newSummary.channels)...)
+	changes = append(changes, compareExportSets("user", oldSummary.users, newSummary.users)...)
+
+	channelNames := map[string]bool{}
+	for name := range oldSummary.posts {
+		channelNames[name] = true
+	}
+	for name := range newSummary.posts {
+		channelNames[name] = true
+	}
+	postChanges := []*exportChange{}
+	for name := range channelNames {
+		if oldPosts, newPosts := oldSummary.posts[name], newSummary.posts[name]; oldPosts != newPosts {
+			postChanges = append(postChanges, &exportChange{Entity: "posts", Name: name, Change: "changed", Old: oldPosts, New: newPosts})
+		}
+	}
+	sort.Slice(postChanges, func(i, j int) bool { return postChanges[i].Name < postChanges[j].Name })
+
+	return append(changes, postChanges...)
+}
+
+func exportCompareCmdF(cmd *cobra.Command, args []string) error {
+	oldSummary, err := readExportSummary(args[0])
+	if err != nil {
+		return err
+	}
+	newSummary, err := readExportSummary(args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := compareExportSummaries(oldSummary, newSummary)
+	for _, change := range changes {
+		printer.PrintT(`{{if eq .Change "added"}}+{{else if eq .Change "removed"}}-{{else}}~{{end}} {{.Entity}} {{.Name}}{{if eq .Entity "posts"}}: {{.Old}} -> {{.New}}{{end}}`, change)
+	}
+
+	if len(changes) == 0 {
+		printer.Print("The archives have the same teams, channels, users and number of posts")
+	}
+
+	return nil
+}
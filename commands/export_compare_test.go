@@ -0,0 +1,101 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestExportCompareCmd() {
+	writeArchive := func(dir, name string, lines ...string) string {
+		archivePath := filepath.Join(dir, name)
+		archive, err := os.Create(archivePath)
+		s.Require().NoError(err)
+		zw := zip.NewWriter(archive)
+		w, err := zw.Create("import.jsonl")
+		s.Require().NoError(err)
+		_, err = w.Write([]byte(strings.Join(lines, "\n") + "\n"))
+		s.Require().NoError(err)
+		s.Require().NoError(zw.Close())
+		s.Require().NoError(archive.Close())
+		return archivePath
+	}
+
+	s.Run("Should print the differences between the archives", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		oldArchive := writeArchive(tmp, "old.zip",
+			`{"type":"version","version":1}`,
+			`{"type":"team","team":{"name":"myteam"}}`,
+			`{"type":"channel","channel":{"team":"myteam","name":"town-square"}}`,
+			`{"type":"channel","channel":{"team":"myteam","name":"old-channel"}}`,
+			`{"type":"user","user":{"username":"john"}}`,
+			`{"type":"user","user":{"username":"jane"}}`,
+			`{"type":"post","post":{"team":"myteam","channel":"town-square","user":"john","message":"hello"}}`,
+			`{"type":"post","post":{"team":"myteam","channel":"old-channel","user":"john","message":"hello"}}`,
+			`{"type":"direct_post","direct_post":{"channel_members":["john","jane"],"user":"john","message":"hi"}}`,
+		)
+		newArchive := writeArchive(tmp, "new.zip",
+			`{"type":"version","version":1}`,
+			`{"type":"team","team":{"name":"myteam"}}`,
+			`{"type":"channel","channel":{"team":"myteam","name":"town-square"}}`,
+			`{"type":"channel","channel":{"team":"myteam","name":"new-channel"}}`,
+			`{"type":"user","user":{"username":"john"}}`,
+			`{"type":"user","user":{"username":"alice"}}`,
+			`{"type":"post","post":{"team":"myteam","channel":"town-square","user":"john","message":"hello","replies":[{"user":"alice","message":"hi"}]}}`,
+			`{"type":"post","post":{"team":"myteam","channel":"new-channel","user":"alice","message":"hello"}}`,
+			`{"type":"direct_post","direct_post":{"channel_members":["john","alice"],"user":"john","message":"hi"}}`,
+		)
+
+		err := exportCompareCmdF(&cobra.Command{}, []string{oldArchive, newArchive})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&exportChange{Entity: "channel", Name: "myteam:new-channel", Change: "added"},
+			&exportChange{Entity: "channel", Name: "myteam:old-channel", Change: "removed"},
+			&exportChange{Entity: "user", Name: "alice", Change: "added"},
+			&exportChange{Entity: "user", Name: "jane", Change: "removed"},
+			&exportChange{Entity: "posts", Name: "myteam:new-channel", Change: "changed", Old: 0, New: 1},
+			&exportChange{Entity: "posts", Name: "myteam:old-channel", Change: "changed", Old: 1, New: 0},
+			&exportChange{Entity: "posts", Name: "myteam:town-square", Change: "changed", Old: 1, New: 2},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should report identical archives", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		archive := writeArchive(tmp, "export.zip", `{"type":"team","team":{"name":"myteam"}}`)
+
+		err := exportCompareCmdF(&cobra.Command{}, []string{archive, archive})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"The archives have the same teams, channels, users and number of posts"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the archive has no import file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		archivePath := filepath.Join(tmp, "empty.zip")
+		archive, err := os.Create(archivePath)
+		s.Require().NoError(err)
+		s.Require().NoError(zip.NewWriter(archive).Close())
+		s.Require().NoError(archive.Close())
+
+		err = exportCompareCmdF(&cobra.Command{}, []string{archivePath, archivePath})
+		s.Require().EqualError(err, `could not find a .jsonl file in the archive "`+archivePath+`"`)
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// errChunkDownloaded stops the transfer of an export once the bytes of
+// the current chunk are written
+var errChunkDownloaded = errors.New("chunk downloaded")
+
+// exportURL returns the URL of the export for DoAPIRequestWithHeaders,
+// which unlike the rest of the client methods takes an absolute URL
+func exportURL(c client.Client, name string) string {
+	var apiURL string
+	if c4, ok := c.(*model.Client4); ok {
+		apiURL = c4.APIURL
+	}
+	return apiURL + "/exports/" + name
+}
+
+// downloadExportChunk downloads the bytes of the export from the offset
+// up to the size of the chunk. DownloadExport only requests open-ended
+// ranges and drains the rest of the response when the transfer stops,
+// so each chunk would otherwise transfer the rest of the export
+func downloadExportChunk(c client.Client, name string, w io.Writer, offset, size int64) (int64, *model.Response, error) {
+	r, err := c.DoAPIRequestWithHeaders(http.MethodGet, exportURL(c, name), "", map[string]string{
+		model.HeaderRange: fmt.Sprintf("bytes=%d-%d", offset, offset+size-1),
+	})
+	if err != nil {
+		return 0, model.BuildResponse(r), err
+	}
+	defer r.Body.Close()
+
+	n, err := io.Copy(w, r.Body)
+	return n, model.BuildResponse(r), err
+}
+
+// chunkWriter writes up to a number of bytes and then fails with
+// errChunkDownloaded, so the next chunk is requested from the offset
+// where this one ended. The ranges of the requests are bounded to the
+// chunk by downloadExportChunk, so the writer only cuts the transfer
+// of the servers that ignore the bound
+type chunkWriter struct {
+	w         io.Writer
+	remaining int64
+	full      bool
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) < w.remaining {
+		n, err := w.w.Write(p)
+		w.remaining -= int64(n)
+		return n, err
+	}
+
+	n, err := w.w.Write(p[:w.remaining])
+	w.remaining -= int64(n)
+	if err != nil {
+		return n, err
+	}
+	w.full = true
+	return n, errChunkDownloaded
+}
+
+// exportTotalSize returns the size of the export from the headers of a
+// download response starting at the offset, or -1 if it's unknown
+func exportTotalSize(r *model.Response, offset int64) int64 {
+	if r == nil || r.Header == nil {
+		return -1
+	}
+	if contentRange := r.Header.Get("Content-Range"); contentRange != "" {
+		if i := strings.LastIndex(contentRange, "/"); i != -1 {
+			if total, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return total
+			}
+		}
+		return -1
+	}
+	if r.StatusCode == http.StatusOK {
+		if length, err := strconv.ParseInt(r.Header.Get("Content-Length"), 10, 64); err == nil {
+			return offset + length
+		}
+	}
+	return -1
+}
+
+type exportDownloadOptions struct {
+	retries   int
+	chunkSize int64
+	rate      int64
+	progress  *transferProgress
+}
+
+// downloadExportFile downloads the export to the end of the file, in
+// chunks of the given size if it's set. Each chunk starts where the
+// file ends, so a failed chunk is retried from the last written byte
+// and a previous partial download is resumed
+func downloadExportFile(c client.Client, name string, f *os.File, opts exportDownloadOptions) error {
+	if opts.progress != nil {
+		defer opts.progress.finish()
+	}
+
+	failures := 0
+	total := int64(-1)
+	for {
+		offset, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return fmt.Errorf("failed to seek export file: %w", err)
+		}
+		if total >= 0 && offset >= total {
+			return nil
+		}
+
+		w := limitWriter(f, opts.rate)
+		if opts.progress != nil {
+			opts.progress.begin(offset, total)
+			w = io.MultiWriter(w, opts.progress)
+		}
+		chunk := &chunkWriter{w: w, remaining: opts.chunkSize}
+		var r *model.Response
+		if opts.chunkSize > 0 {
+			_, r, err = downloadExportChunk(c, name, chunk, offset, opts.chunkSize)
+		} else {
+			_, r, err = c.DownloadExport(name, w, offset)
+		}
+		if size := exportTotalSize(r, offset); size >= 0 {
+			total = size
+		}
+		switch {
+		case err == nil:
+			return nil
+		case chunk.full:
+			failures = 0
+			continue
+		case offset > 0 && r != nil && r.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+			// the file was already downloaded completely
+			return nil
+		}
+
+		failures++
+		if failures > opts.retries {
+			return fmt.Errorf("failed to download export after %d retries", opts.retries)
+		}
+		printer.PrintWarning(fmt.Sprintf("failed to download export file at offset %d: %v. Retrying...", offset, err))
+	}
+}
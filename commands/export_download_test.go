@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/require"
+)
+
+// countingResponseWriter counts the bytes of the bodies the server sends
+type countingResponseWriter struct {
+	http.ResponseWriter
+	sent *int
+}
+
+func (w *countingResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	*w.sent += n
+	return n, err
+}
+
+func TestDownloadExportFileInChunks(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	sent := 0
+	ranges := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get(model.HeaderRange))
+		http.ServeContent(&countingResponseWriter{ResponseWriter: w, sent: &sent}, r, "export.zip", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	c := model.NewAPIv4Client(server.URL)
+	// http.DefaultTransport would cache the proxy environment variables
+	// for the whole test binary
+	c.HTTPClient = &http.Client{Transport: &http.Transport{}}
+
+	tmp, err := ioutil.TempDir("", "mmctl-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	f, err := os.Create(filepath.Join(tmp, "export.zip"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	err = downloadExportFile(c, "export.zip", f, exportDownloadOptions{chunkSize: 4000})
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+	require.Equal(t, []string{"bytes=0-3999", "bytes=4000-7999", "bytes=8000-11999"}, ranges)
+	// each chunk only transfers its own bytes
+	require.Equal(t, len(data), sent)
+}
@@ -240,7 +240,7 @@ func (s *MmctlE2ETestSuite) TestExportDownloadCmdF() {
 
 		err = exportDownloadCmdF(c, cmd, []string{exportName, downloadPath})
 		s.Require().Nil(err)
-		s.Require().Empty(printer.GetLines())
+		s.Require().Len(printer.GetLines(), 1)
 		s.Require().Empty(printer.GetErrorLines())
 	})
 
@@ -261,7 +261,7 @@ func (s *MmctlE2ETestSuite) TestExportDownloadCmdF() {
 
 		err = exportDownloadCmdF(c, cmd, []string{exportName, downloadPath})
 		s.Require().Nil(err)
-		s.Require().Empty(printer.GetLines())
+		s.Require().Len(printer.GetLines(), 1)
 		s.Require().Empty(printer.GetErrorLines())
 
 		expected, err := ioutil.ReadFile(exportFilePath)
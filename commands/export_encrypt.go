@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ExportEncryptCmd = &cobra.Command{
+	Use:   "encrypt [filepath]",
+	Short: "Encrypt an export file with GPG",
+	Long: `Encrypt a downloaded export file to one or more GPG recipients, as exports contain sensitive data. The encrypted file is written next to the original one with the ".gpg" extension, and the original file is removed unless --keep is set.
+The recipients are resolved by the gpg command from the keyring of the user, so their public keys must have been imported and trusted beforehand. The file can be decrypted with "gpg --decrypt".`,
+	Example: `  export encrypt export.zip --gpg-recipient admin@example.com
+
+  # or encrypt the export as part of the download
+  export download export.zip --gpg-recipient admin@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: exportEncryptCmdF,
+}
+
+func init() {
+	ExportEncryptCmd.Flags().StringSlice("gpg-recipient", []string{}, "Key id, fingerprint or email of the GPG recipients to encrypt the file to.")
+	_ = ExportEncryptCmd.MarkFlagRequired("gpg-recipient")
+	ExportEncryptCmd.Flags().Bool("keep", false, "Keep the original file after encrypting it.")
+
+	ExportDownloadCmd.Flags().StringSlice("gpg-recipient", []string{}, "Encrypt the downloaded file to these GPG recipients and remove the unencrypted one, also if the encryption fails.")
+
+	ExportCmd.AddCommand(ExportEncryptCmd)
+}
+
+// gpgCommand is the command used to encrypt the exports
+var gpgCommand = "gpg"
+
+type encryptedExport struct {
+	File          string   `json:"file"`
+	EncryptedFile string   `json:"encrypted_file"`
+	Recipients    []string `json:"recipients"`
+}
+
+// checkGPGRecipients makes sure that gpg can be run and knows the
+// public keys of the recipients, so a download isn't done for nothing
+func checkGPGRecipients(recipients []string) error {
+	if len(recipients) == 0 {
+		return errors.New("at least one GPG recipient is required")
+	}
+
+	for _, recipient := range recipients {
+		if output, err := exec.Command(gpgCommand, "--batch", "--list-keys", "--", recipient).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to find the public key of GPG recipient %q: %w: %s", recipient, err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// encryptFileWithGPG encrypts the file to the recipients with gpg,
+// returning the path of the encrypted file
+func encryptFileWithGPG(path string, recipients []string) (string, error) {
+	if len(recipients) == 0 {
+		return "", errors.New("at least one GPG recipient is required")
+	}
+
+	out := path + ".gpg"
+	if _, err := os.Stat(out); err == nil {
+		return "", fmt.Errorf("encrypted file %q already exists", out)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to stat encrypted file: %w", err)
+	}
+
+	args := []string{"--batch", "--encrypt", "--output", out}
+	for _, recipient := range recipients {
+		args = append(args, "--recipient", recipient)
+	}
+	args = append(args, "--", path)
+
+	if output, err := exec.Command(gpgCommand, args...).CombinedOutput(); err != nil {
+		_ = os.Remove(out)
+		return "", fmt.Errorf("failed to encrypt %q with gpg: %w: %s", path, err, strings.TrimSpace(string(output)))
+	}
+	return out, nil
+}
+
+// encryptExportFile encrypts the export file and removes the original
+// one unless keep is set
+func encryptExportFile(path string, recipients []string, keep bool) error {
+	out, err := encryptFileWithGPG(path, recipients)
+	if err != nil {
+		return err
+	}
+	if !keep {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove unencrypted export file: %w", err)
+		}
+	}
+
+	printer.PrintT("Export file {{.File}} encrypted to {{.EncryptedFile}}", &encryptedExport{
+		File:          path,
+		EncryptedFile: out,
+		Recipients:    recipients,
+	})
+	return nil
+}
+
+func exportEncryptCmdF(command *cobra.Command, args []string) error {
+	recipients, _ := command.Flags().GetStringSlice("gpg-recipient")
+	keep, _ := command.Flags().GetBool("keep")
+
+	info, err := os.Stat(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to stat export file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%q is a directory", args[0])
+	}
+
+	return encryptExportFile(args[0], recipients, keep)
+}
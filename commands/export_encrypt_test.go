@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestExportEncryptCmdF() {
+	if _, err := exec.LookPath(gpgCommand); err != nil {
+		s.T().Skip("gpg is not available")
+	}
+
+	gpgHome, err := ioutil.TempDir("", "mmctl-gpg-")
+	s.Require().NoError(err)
+	defer os.RemoveAll(gpgHome)
+	oldGPGHome, hadGPGHome := os.LookupEnv("GNUPGHOME")
+	s.Require().NoError(os.Setenv("GNUPGHOME", gpgHome))
+	defer func() {
+		_ = exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+		if hadGPGHome {
+			os.Setenv("GNUPGHOME", oldGPGHome)
+		} else {
+			os.Unsetenv("GNUPGHOME")
+		}
+	}()
+
+	recipient := "mmctl@example.com"
+	output, err := exec.Command(gpgCommand, "--batch", "--passphrase", "", "--quick-gen-key", recipient, "default", "default", "never").CombinedOutput()
+	s.Require().NoError(err, string(output))
+
+	decrypt := func(path string) string {
+		b, err := exec.Command(gpgCommand, "--batch", "--quiet", "--decrypt", path).Output()
+		s.Require().NoError(err)
+		return string(b)
+	}
+
+	s.Run("Should encrypt the file and remove the original one", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("export data"), 0600))
+
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("gpg-recipient", []string{recipient}, "")
+		cmd.Flags().Bool("keep", false, "")
+
+		err := exportEncryptCmdF(cmd, []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&encryptedExport{File: path, EncryptedFile: path + ".gpg", Recipients: []string{recipient}},
+		}, printer.GetLines())
+		s.Require().NoFileExists(path)
+		s.Require().Equal("export data", decrypt(path+".gpg"))
+	})
+
+	s.Run("Should fail for an unknown recipient without leaving files", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("export data"), 0600))
+
+		err := encryptExportFile(path, []string{"unknown@example.com"}, false)
+		s.Require().Error(err)
+		s.Require().FileExists(path)
+		s.Require().NoFileExists(path + ".gpg")
+	})
+
+	s.Run("Should fail if the encrypted file already exists", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("export data"), 0600))
+		s.Require().NoError(ioutil.WriteFile(path+".gpg", []byte{}, 0600))
+
+		err := encryptExportFile(path, []string{recipient}, true)
+		s.Require().EqualError(err, "encrypted file \""+path+".gpg\" already exists")
+	})
+
+	s.Run("Should encrypt the downloaded export", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+
+		s.client.
+			EXPECT().
+			DownloadExport("export.zip", gomock.Any(), int64(0)).
+			Return(int64(0), &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 0, "")
+		cmd.Flags().StringSlice("gpg-recipient", []string{recipient}, "")
+
+		err := exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().NoFileExists(path)
+		s.Require().Equal("", decrypt(path+".gpg"))
+	})
+
+	s.Run("Should check the recipients before downloading the export", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 0, "")
+		cmd.Flags().StringSlice("gpg-recipient", []string{"unknown@example.com"}, "")
+
+		err := exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), `failed to find the public key of GPG recipient "unknown@example.com"`)
+		s.Require().NoFileExists(path)
+	})
+
+	s.Run("Should remove the downloaded export if it can't be encrypted", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path+".gpg", []byte{}, 0600))
+
+		s.client.
+			EXPECT().
+			DownloadExport("export.zip", gomock.Any(), int64(0)).
+			Return(int64(0), &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 0, "")
+		cmd.Flags().StringSlice("gpg-recipient", []string{recipient}, "")
+
+		err := exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().EqualError(err, "encrypted file \""+path+".gpg\" already exists")
+		s.Require().NoFileExists(path)
+	})
+
+	s.Run("Should remove the downloaded export if its digest doesn't match", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+
+		s.client.
+			EXPECT().
+			DownloadExport("export.zip", gomock.Any(), int64(0)).
+			Return(int64(0), &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 0, "")
+		cmd.Flags().String("expect-sha256", "0000", "")
+		cmd.Flags().StringSlice("gpg-recipient", []string{recipient}, "")
+
+		err := exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "SHA-256 mismatch")
+		s.Require().NoFileExists(path)
+		s.Require().NoFileExists(path + ".gpg")
+	})
+}
@@ -4,16 +4,57 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/mattermost/mmctl/v6/printer"
 
+	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/spf13/cobra"
 )
 
 func (s *MmctlUnitTestSuite) TestExportCreateCmdF() {
+	s.Run("create export and wait for it to finish", func() {
+		printer.Clean()
+		originalInterval := jobPollInterval
+		defer func() { jobPollInterval = originalInterval }()
+		jobPollInterval = 0
+
+		mockJob := &model.Job{
+			Id:   "job-id",
+			Type: model.JobTypeExportProcess,
+			Data: map[string]string{"include_attachments": "true"},
+		}
+
+		s.client.
+			EXPECT().
+			CreateJob(&model.Job{Type: model.JobTypeExportProcess, Data: map[string]string{"include_attachments": "true"}}).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(&model.Job{Id: mockJob.Id, Status: model.JobStatusWarning}, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+
+		err := exportCreateCmdF(s.client, cmd, nil)
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(model.JobStatusWarning, printer.GetLines()[1].(*model.Job).Status)
+	})
+
 	s.Run("create export", func() {
 		printer.Clean()
 		mockJob := &model.Job{
@@ -117,3 +158,163 @@ func (s *MmctlUnitTestSuite) TestExportListCmdF() {
 		}
 	})
 }
+
+func (s *MmctlUnitTestSuite) TestExportDownloadCmdF() {
+	emptyDigest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	newCmd := func(expected string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 0, "")
+		cmd.Flags().String("expect-sha256", expected, "")
+		return cmd
+	}
+
+	s.Run("download export and print its digest", func() {
+		printer.Clean()
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+
+		s.client.
+			EXPECT().
+			DownloadExport("export.zip", gomock.Any(), int64(0)).
+			Return(int64(0), &model.Response{}, nil).
+			Times(1)
+
+		err = exportDownloadCmdF(s.client, newCmd(emptyDigest), []string{"export.zip", path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(struct {
+			File   string `json:"file"`
+			SHA256 string `json:"sha256"`
+		}{path, emptyDigest}, printer.GetLines()[0])
+	})
+
+	s.Run("fail if the downloaded export has an unexpected digest", func() {
+		printer.Clean()
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+
+		s.client.
+			EXPECT().
+			DownloadExport("export.zip", gomock.Any(), int64(0)).
+			Return(int64(0), &model.Response{}, nil).
+			Times(1)
+
+		err = exportDownloadCmdF(s.client, newCmd("0000"), []string{"export.zip", path})
+		s.Require().EqualError(err, fmt.Sprintf("SHA-256 mismatch for %q: expected 0000, got %s", path, emptyDigest))
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	// serveExport writes the export from the offset as the server does,
+	// failing after the given number of bytes if it's positive
+	serveExport := func(data []byte, failAfter int) func(string, io.Writer, int64) (int64, *model.Response, error) {
+		return func(name string, w io.Writer, offset int64) (int64, *model.Response, error) {
+			r := &model.Response{StatusCode: http.StatusPartialContent, Header: http.Header{}}
+			r.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(data)-1, len(data)))
+			body := data[offset:]
+			if failAfter > 0 {
+				body = body[:failAfter]
+			}
+			n, err := io.Copy(w, bytes.NewReader(body))
+			if err == nil && failAfter > 0 {
+				err = errors.New("connection reset")
+			}
+			return n, r, err
+		}
+	}
+
+	s.Run("resume an existing file in chunks", func() {
+		printer.Clean()
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("abc"), 0600))
+		data := []byte("abcdefghij")
+
+		cmd := newCmd("")
+		cmd.Flags().Bool("resume", true, "")
+		cmd.Flags().String("chunk-size", "4", "")
+
+		// serveChunk writes the bounded range of the export as the
+		// server does
+		serveChunk := func(method, url, body string, headers map[string]string) (*http.Response, error) {
+			var start, end int
+			_, err := fmt.Sscanf(headers[model.HeaderRange], "bytes=%d-%d", &start, &end)
+			s.Require().NoError(err)
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			r := &http.Response{StatusCode: http.StatusPartialContent, Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewReader(data[start : end+1]))}
+			r.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+			return r, nil
+		}
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				DoAPIRequestWithHeaders(http.MethodGet, "/exports/export.zip", "", map[string]string{model.HeaderRange: "bytes=3-6"}).
+				DoAndReturn(serveChunk).
+				Times(1),
+			s.client.
+				EXPECT().
+				DoAPIRequestWithHeaders(http.MethodGet, "/exports/export.zip", "", map[string]string{model.HeaderRange: "bytes=7-10"}).
+				DoAndReturn(serveChunk).
+				Times(1),
+		)
+
+		err = exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().NoError(err)
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		s.Require().Equal(data, b)
+	})
+
+	s.Run("retry a failed chunk from the last written byte", func() {
+		printer.Clean()
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		data := []byte("abcdefghij")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("num-retries", 1, "")
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				DownloadExport("export.zip", gomock.Any(), int64(0)).
+				DoAndReturn(serveExport(data, 6)).
+				Times(1),
+			s.client.
+				EXPECT().
+				DownloadExport("export.zip", gomock.Any(), int64(6)).
+				DoAndReturn(serveExport(data, 0)).
+				Times(1),
+		)
+
+		err = exportDownloadCmdF(s.client, cmd, []string{"export.zip", path})
+		s.Require().NoError(err)
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		s.Require().Equal(data, b)
+	})
+
+	s.Run("fail if an existing file is not resumed", func() {
+		printer.Clean()
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "export.zip")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("abc"), 0600))
+
+		err = exportDownloadCmdF(s.client, newCmd(""), []string{"export.zip", path})
+		s.Require().EqualError(err, "export file already exists")
+	})
+}
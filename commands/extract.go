@@ -6,7 +6,6 @@ package commands
 import (
 	"fmt"
 	"strconv"
-	"time"
 
 	"github.com/mattermost/mmctl/v6/client"
 	"github.com/mattermost/mmctl/v6/printer"
@@ -111,10 +110,18 @@ func extractJobListCmdF(c client.Client, command *cobra.Command, args []string)
 
 func printExtractContentJob(job *model.Job) {
 	if job.StartAt > 0 {
-		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n  Started: %s\n  Processed: %s\n  Errors: %s\n",
-			time.Unix(job.CreateAt/1000, 0), time.Unix(job.StartAt/1000, 0), job.Data["processed"], job.Data["errors"]), job)
+		printer.PrintT(`  ID: {{.Id}}
+  Status: {{.Status}}
+  Created: {{timestamp .CreateAt}}
+  Started: {{timestamp .StartAt}}
+  Processed: {{index .Data "processed"}}
+  Errors: {{index .Data "errors"}}
+`, job)
 	} else {
-		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n\n",
-			time.Unix(job.CreateAt/1000, 0)), job)
+		printer.PrintT(`  ID: {{.Id}}
+  Status: {{.Status}}
+  Created: {{timestamp .CreateAt}}
+
+`, job)
 	}
 }
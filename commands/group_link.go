@@ -0,0 +1,308 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var LinkLdapGroupCmd = &cobra.Command{
+	Use:   "link-ldap [ldap-groups]",
+	Short: "Link LDAP groups to Mattermost",
+	Long: `Link LDAP groups to Mattermost, creating a Mattermost group for each one, whose members are synchronized from LDAP. The LDAP groups are given by their remote ID or by their name, as shown by "group list-ldap".
+Once linked, the groups can be linked to teams and channels with "group team link" and "group channel link".`,
+	Example: `  group link-ldap developers "support engineers"`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(linkLdapGroupCmdF),
+}
+
+var UnlinkLdapGroupCmd = &cobra.Command{
+	Use:     "unlink-ldap [ldap-groups]",
+	Short:   "Unlink LDAP groups from Mattermost",
+	Long:    "Unlink LDAP groups from Mattermost, deleting the Mattermost group of each one. The LDAP groups are given by their remote ID or by their name.",
+	Example: "  group unlink-ldap developers",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(unlinkLdapGroupCmdF),
+}
+
+var GroupMembersCmd = &cobra.Command{
+	Use:     "members [group]",
+	Short:   "List the members of a group",
+	Long:    "List the members of a group, given by ID, name or display name.",
+	Example: "  group members developers",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(groupMembersCmdF),
+}
+
+var TeamGroupLinkCmd = &cobra.Command{
+	Use:   "link [team] [groups]",
+	Short: "Link groups to a team",
+	Long: `Link groups to a team, so that their members are added to the team when --auto-add is set, and with --scheme-admin are made team admins. The groups are given by ID, name or display name.
+Linking groups to a team is required before making it group-constrained with "group team enable".`,
+	Example: "  group team link myteam developers support",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(teamGroupLinkCmdF),
+}
+
+var TeamGroupUnlinkCmd = &cobra.Command{
+	Use:     "unlink [team] [groups]",
+	Short:   "Unlink groups from a team",
+	Example: "  group team unlink myteam support",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(teamGroupUnlinkCmdF),
+}
+
+var ChannelGroupLinkCmd = &cobra.Command{
+	Use:   "link [team]:[channel] [groups]",
+	Short: "Link groups to a channel",
+	Long: `Link groups to a channel, so that their members are added to the channel when --auto-add is set, and with --scheme-admin are made channel admins. The groups are given by ID, name or display name.
+Linking groups to a channel is required before making it group-constrained with "group channel enable".`,
+	Example: "  group channel link myteam:mychannel developers support",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(channelGroupLinkCmdF),
+}
+
+var ChannelGroupUnlinkCmd = &cobra.Command{
+	Use:     "unlink [team]:[channel] [groups]",
+	Short:   "Unlink groups from a channel",
+	Example: "  group channel unlink myteam:mychannel support",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(channelGroupUnlinkCmdF),
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{TeamGroupLinkCmd, ChannelGroupLinkCmd} {
+		cmd.Flags().Bool("auto-add", true, "Add the members of the groups automatically")
+		cmd.Flags().Bool("scheme-admin", false, "Make the members of the groups admins")
+	}
+
+	TeamGroupCmd.AddCommand(
+		TeamGroupLinkCmd,
+		TeamGroupUnlinkCmd,
+	)
+	ChannelGroupCmd.AddCommand(
+		ChannelGroupLinkCmd,
+		ChannelGroupUnlinkCmd,
+	)
+	GroupCmd.AddCommand(
+		LinkLdapGroupCmd,
+		UnlinkLdapGroupCmd,
+		GroupMembersCmd,
+	)
+}
+
+// groupSearchPerPage is the number of groups fetched when looking a
+// group up by name
+const groupSearchPerPage = 100
+
+// getGroupFromGroupArg finds a group by ID, or else by name or display
+// name among the groups matching the search
+func getGroupFromGroupArg(c client.Client, groupArg string) (*model.Group, error) {
+	if model.IsValidId(groupArg) {
+		if group, _, err := c.GetGroup(groupArg, ""); err == nil {
+			return group, nil
+		}
+	}
+
+	groups, _, err := c.GetGroups(model.GroupSearchOpts{
+		Q:        groupArg,
+		PageOpts: &model.PageOpts{Page: 0, PerPage: groupSearchPerPage},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search group %q", groupArg)
+	}
+	for _, group := range groups {
+		if (group.Name != nil && *group.Name == groupArg) || group.DisplayName == groupArg {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find group %q", groupArg)
+}
+
+// findLdapGroupRemoteID returns the remote ID of the LDAP group with
+// the given name, paging through the groups that match it, as
+// GetLdapGroups only returns the first page of the groups
+func findLdapGroupRemoteID(c client.Client, name string) (string, error) {
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(groupSearchPerPage))
+		query.Set("q", name)
+		r, err := c.DoAPIGet("/ldap/groups?"+query.Encode(), "")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to search LDAP group %q", name)
+		}
+
+		var groups struct {
+			Groups []*model.Group `json:"groups"`
+		}
+		err = json.NewDecoder(r.Body).Decode(&groups)
+		r.Body.Close()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode the LDAP groups")
+		}
+
+		for _, group := range groups.Groups {
+			if group.Name != nil && *group.Name == name && group.RemoteId != nil {
+				return *group.RemoteId, nil
+			}
+		}
+		if len(groups.Groups) < groupSearchPerPage {
+			return "", fmt.Errorf("unable to find LDAP group %q", name)
+		}
+	}
+}
+
+// changeLdapGroupLink links or unlinks the LDAP group with the given
+// remote ID or name. The argument is tried as a remote ID first, and
+// looked up by name if the server can't find a group with that ID
+func changeLdapGroupLink(c client.Client, arg string, link bool) (*model.Group, error) {
+	change := c.UnlinkLdapGroup
+	if link {
+		change = c.LinkLdapGroup
+	}
+
+	group, r, err := change(arg)
+	if err == nil || r == nil || r.StatusCode != http.StatusNotFound {
+		return group, err
+	}
+
+	remoteID, err := findLdapGroupRemoteID(c, arg)
+	if err != nil {
+		return nil, err
+	}
+	group, _, err = change(remoteID)
+	return group, err
+}
+
+func changeLdapGroupsLink(c client.Client, args []string, link bool) error {
+	action := "unlink"
+	if link {
+		action = "link"
+	}
+
+	failed := 0
+	for _, arg := range args {
+		group, err := changeLdapGroupLink(c, arg, link)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to %s LDAP group %q: %s", action, arg, err))
+			failed++
+			continue
+		}
+		printer.PrintT("Group {{.DisplayName}} "+action+"ed, ID: {{.Id}}", group)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to %s %d groups", action, failed)
+	}
+	return nil
+}
+
+func linkLdapGroupCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return changeLdapGroupsLink(c, args, true)
+}
+
+func unlinkLdapGroupCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return changeLdapGroupsLink(c, args, false)
+}
+
+func groupMembersCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	group, err := getGroupFromGroupArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsersInGroup(group.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return errors.Wrap(err, "failed to get the members of the group")
+		}
+		for _, user := range users {
+			printer.PrintT("{{.Id}}: {{.Username}} ({{.Email}})", user)
+		}
+		if len(users) < APILimitMaximum {
+			return nil
+		}
+	}
+}
+
+// changeGroupSyncables links or unlinks the groups to a team or a
+// channel, going on with the rest of the groups if one fails
+func changeGroupSyncables(c client.Client, cmd *cobra.Command, syncableID string, syncableType model.GroupSyncableType, groupArgs []string, link bool) error {
+	action := "unlink"
+	if link {
+		action = "link"
+	}
+
+	failed := 0
+	for _, groupArg := range groupArgs {
+		group, err := getGroupFromGroupArg(c, groupArg)
+		if err == nil {
+			if link {
+				autoAdd, _ := cmd.Flags().GetBool("auto-add")
+				schemeAdmin, _ := cmd.Flags().GetBool("scheme-admin")
+				_, _, err = c.LinkGroupSyncable(group.Id, syncableID, syncableType, &model.GroupSyncablePatch{
+					AutoAdd:     model.NewBool(autoAdd),
+					SchemeAdmin: model.NewBool(schemeAdmin),
+				})
+			} else {
+				_, err = c.UnlinkGroupSyncable(group.Id, syncableID, syncableType)
+			}
+		}
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to %s group %q: %s", action, groupArg, err))
+			failed++
+			continue
+		}
+		printer.PrintT("Group {{.DisplayName}} "+action+"ed", group)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to %s %d groups", action, failed)
+	}
+	return nil
+}
+
+func teamGroupLinkCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	team := getTeamFromTeamArg(c, args[0])
+	if team == nil {
+		return errors.New("Unable to find team '" + args[0] + "'")
+	}
+	return changeGroupSyncables(c, cmd, team.Id, model.GroupSyncableTypeTeam, args[1:], true)
+}
+
+func teamGroupUnlinkCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	team := getTeamFromTeamArg(c, args[0])
+	if team == nil {
+		return errors.New("Unable to find team '" + args[0] + "'")
+	}
+	return changeGroupSyncables(c, cmd, team.Id, model.GroupSyncableTypeTeam, args[1:], false)
+}
+
+func channelGroupLinkCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.New("Unable to find channel '" + args[0] + "'")
+	}
+	return changeGroupSyncables(c, cmd, channel.Id, model.GroupSyncableTypeChannel, args[1:], true)
+}
+
+func channelGroupUnlinkCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.New("Unable to find channel '" + args[0] + "'")
+	}
+	return changeGroupSyncables(c, cmd, channel.Id, model.GroupSyncableTypeChannel, args[1:], false)
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestLinkLdapGroupCmd() {
+	notFound := &model.Response{StatusCode: http.StatusNotFound}
+	ldapGroupsResponse := func(groups ...*model.Group) *http.Response {
+		b, err := json.Marshal(map[string]interface{}{"count": len(groups), "groups": groups})
+		s.Require().NoError(err)
+		return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}
+	}
+
+	s.Run("Should link the LDAP groups by remote ID or name", func() {
+		printer.Clean()
+		developers := &model.Group{Id: model.NewId(), DisplayName: "developers"}
+		support := &model.Group{Id: model.NewId(), DisplayName: "support"}
+
+		s.client.
+			EXPECT().
+			LinkLdapGroup("developers").
+			Return(nil, notFound, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			DoAPIGet("/ldap/groups?page=0&per_page=100&q=developers", "").
+			Return(ldapGroupsResponse(
+				&model.Group{Name: model.NewString("developers-eu"), RemoteId: model.NewString("remote-developers-eu")},
+				&model.Group{Name: model.NewString("developers"), RemoteId: model.NewString("remote-developers")},
+			), nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			LinkLdapGroup("remote-developers").
+			Return(developers, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			LinkLdapGroup("remote-support").
+			Return(support, &model.Response{}, nil).
+			Times(1)
+
+		err := linkLdapGroupCmdF(s.client, &cobra.Command{}, []string{"developers", "remote-support"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{developers, support}, printer.GetLines())
+	})
+
+	s.Run("Should page through the LDAP groups to find a group by name", func() {
+		printer.Clean()
+		support := &model.Group{Id: model.NewId(), DisplayName: "support"}
+		firstPage := []*model.Group{}
+		for i := 0; i < groupSearchPerPage; i++ {
+			firstPage = append(firstPage, &model.Group{Name: model.NewString(fmt.Sprintf("support-%d", i)), RemoteId: model.NewString(model.NewId())})
+		}
+
+		s.client.
+			EXPECT().
+			LinkLdapGroup("support").
+			Return(nil, notFound, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			DoAPIGet("/ldap/groups?page=0&per_page=100&q=support", "").
+			Return(ldapGroupsResponse(firstPage...), nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			DoAPIGet("/ldap/groups?page=1&per_page=100&q=support", "").
+			Return(ldapGroupsResponse(&model.Group{Name: model.NewString("support"), RemoteId: model.NewString("remote-support")}), nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			LinkLdapGroup("remote-support").
+			Return(support, &model.Response{}, nil).
+			Times(1)
+
+		err := linkLdapGroupCmdF(s.client, &cobra.Command{}, []string{"support"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{support}, printer.GetLines())
+	})
+
+	s.Run("Should go on with the rest of the groups if one can't be found", func() {
+		printer.Clean()
+		developers := &model.Group{Id: model.NewId(), DisplayName: "developers"}
+
+		s.client.
+			EXPECT().
+			UnlinkLdapGroup("unknown").
+			Return(nil, notFound, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			DoAPIGet("/ldap/groups?page=0&per_page=100&q=unknown", "").
+			Return(ldapGroupsResponse(), nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			UnlinkLdapGroup("remote-developers").
+			Return(developers, &model.Response{}, nil).
+			Times(1)
+
+		err := unlinkLdapGroupCmdF(s.client, &cobra.Command{}, []string{"unknown", "remote-developers"})
+		s.Require().EqualError(err, "unable to unlink 1 groups")
+		s.Require().Equal([]interface{}{developers}, printer.GetLines())
+		s.Require().Equal([]interface{}{`Unable to unlink LDAP group "unknown": unable to find LDAP group "unknown"`}, printer.GetErrorLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestGroupMembersCmd() {
+	s.Run("Should list the members of a group found by name", func() {
+		printer.Clean()
+		group := &model.Group{Id: model.NewId(), Name: model.NewString("developers"), DisplayName: "Developers"}
+		users := []*model.User{{Id: model.NewId(), Username: "john.doe"}}
+
+		s.client.
+			EXPECT().
+			GetGroups(model.GroupSearchOpts{Q: "developers", PageOpts: &model.PageOpts{Page: 0, PerPage: groupSearchPerPage}}).
+			Return([]*model.Group{{Id: model.NewId(), Name: model.NewString("developers-eu")}, group}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUsersInGroup(group.Id, 0, APILimitMaximum, "").
+			Return(users, &model.Response{}, nil).
+			Times(1)
+
+		err := groupMembersCmdF(s.client, &cobra.Command{}, []string{"developers"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{users[0]}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the group can't be found", func() {
+		s.client.
+			EXPECT().
+			GetGroups(model.GroupSearchOpts{Q: "unknown", PageOpts: &model.PageOpts{Page: 0, PerPage: groupSearchPerPage}}).
+			Return([]*model.Group{}, &model.Response{}, nil).
+			Times(1)
+
+		err := groupMembersCmdF(s.client, &cobra.Command{}, []string{"unknown"})
+		s.Require().EqualError(err, `unable to find group "unknown"`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestGroupSyncableLinkCmds() {
+	group := &model.Group{Id: model.NewId(), DisplayName: "Developers"}
+	team := &model.Team{Id: model.NewId(), Name: "myteam"}
+	channel := &model.Channel{Id: model.NewId(), Name: "mychannel", TeamId: team.Id}
+
+	newLinkCmd := func(autoAdd, schemeAdmin bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("auto-add", autoAdd, "")
+		cmd.Flags().Bool("scheme-admin", schemeAdmin, "")
+		return cmd
+	}
+
+	s.Run("Should link a group to a team", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetGroup(group.Id, "").
+			Return(group, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			LinkGroupSyncable(group.Id, team.Id, model.GroupSyncableTypeTeam, &model.GroupSyncablePatch{AutoAdd: model.NewBool(true), SchemeAdmin: model.NewBool(true)}).
+			Return(&model.GroupSyncable{}, &model.Response{}, nil).
+			Times(1)
+
+		err := teamGroupLinkCmdF(s.client, newLinkCmd(true, true), []string{team.Id, group.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{group}, printer.GetLines())
+	})
+
+	s.Run("Should report the groups that fail to be unlinked from a channel", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetGroup(group.Id, "").
+			Return(group, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			UnlinkGroupSyncable(group.Id, channel.Id, model.GroupSyncableTypeChannel).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := channelGroupUnlinkCmdF(s.client, &cobra.Command{}, []string{channel.Id, group.Id})
+		s.Require().EqualError(err, "unable to unlink 1 groups")
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal([]interface{}{`Unable to unlink group "` + group.Id + `": mock error`}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fail if the team can't be found", func() {
+		s.client.
+			EXPECT().
+			GetTeam("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamByName("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := teamGroupUnlinkCmdF(s.client, &cobra.Command{}, []string{"unknown", group.Id})
+		s.Require().EqualError(err, "Unable to find team 'unknown'")
+	})
+}
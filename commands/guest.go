@@ -0,0 +1,325 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var GuestCmd = &cobra.Command{
+	Use:   "guest",
+	Short: "Management of guest accounts",
+}
+
+var GuestExpireCmd = &cobra.Command{
+	Use:   "expire [guests]",
+	Short: "Set when the access of guests expires",
+	Long: `Record when the access of the given guests expires, either after a duration from now with --after, or at a time with --at. The expiries are stored in the configuration of the server, so they are shared by all the administrators of the server and can't be changed by the guests themselves.
+The guests are not deactivated by this command: run "guest enforce" regularly, for instance from a cron job, to deactivate the guests past their expiry.`,
+	Example: `  guest expire guest@example.com --after 30d
+  guest expire guest1 guest2 --at 2026-12-31T00:00:00+00:00
+
+  # remove the expiry
+  guest expire guest1 --clear`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(guestExpireCmdF),
+}
+
+var GuestEnforceCmd = &cobra.Command{
+	Use:   "enforce",
+	Short: "Deactivate the guests past their expiry",
+	Long: `Deactivate the active guests whose access expiry, set with "guest expire", has passed.
+With --plan, the guests that would be deactivated are printed as a plan that can be reviewed and then applied with --apply-plan.`,
+	Example: `  # check which guests would be deactivated
+  guest enforce --dry-run
+
+  guest enforce
+
+  # review the guests before deactivating them
+  guest enforce --plan > plan.json
+  guest enforce --apply-plan plan.json`,
+	Args: cobra.NoArgs,
+	RunE: withClient(withPlanActions(guestEnforcePlanResolver, applyGuestEnforcePlan, guestEnforceCmdF)),
+}
+
+func init() {
+	GuestExpireCmd.Flags().String("after", "", "Expire the access after this duration from now, in days like 30d or as a duration like 12h")
+	GuestExpireCmd.Flags().String("at", "", "Expire the access at this time, in ISO 8601 format")
+	GuestExpireCmd.Flags().Bool("clear", false, "Remove the expiry of the access")
+
+	GuestEnforceCmd.Flags().Bool("dry-run", false, "List the guests that would be deactivated without deactivating them")
+	addPlanFlags(GuestEnforceCmd)
+
+	GuestCmd.AddCommand(
+		GuestExpireCmd,
+		GuestEnforceCmd,
+	)
+
+	RootCmd.AddCommand(GuestCmd)
+}
+
+// guestExpiriesConfigKey is the key of the plugin settings of the
+// config that store the time, in milliseconds, when the access of each
+// guest expires, by user ID. Unlike the props of the users, which the
+// guests can patch themselves, the config can only be changed by the
+// system admins, and unlike a local file it's shared by the admins
+// that run "guest enforce". It's documented in the help of config
+const guestExpiriesConfigKey = "mmctl_guest_access_expiries"
+
+type guestExpiry struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	ExpiresAt int64  `json:"expires_at"`
+	Result    string `json:"result,omitempty"`
+}
+
+// parseExpiryDuration parses a duration, accepting a number of days
+// like 30d besides the units of time.ParseDuration
+func parseExpiryDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(value)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// getGuestExpiries returns the times when the access of the guests
+// expires stored in the config, by user ID
+func getGuestExpiries(config *model.Config) map[string]int64 {
+	expiries := map[string]int64{}
+	for userID, value := range config.PluginSettings.Plugins[guestExpiriesConfigKey] {
+		var expiresAt int64
+		switch v := value.(type) {
+		case float64:
+			expiresAt = int64(v)
+		case int64:
+			expiresAt = v
+		case string:
+			expiresAt, _ = strconv.ParseInt(v, 10, 64)
+		}
+		if expiresAt > 0 {
+			expiries[userID] = expiresAt
+		}
+	}
+	return expiries
+}
+
+// setGuestExpiries replaces the times when the access of the guests
+// expires stored in the config
+func setGuestExpiries(config *model.Config, expiries map[string]int64) {
+	settings := map[string]interface{}{}
+	for userID, expiresAt := range expiries {
+		settings[userID] = expiresAt
+	}
+	if config.PluginSettings.Plugins == nil {
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{}
+	}
+	config.PluginSettings.Plugins[guestExpiriesConfigKey] = settings
+}
+
+// pluginSettingsPatch returns a config patch that only changes the
+// settings of the plugins, so that storing the expiries doesn't write
+// the rest of the config back. The server replaces the maps of a patch
+// as a whole, and the fields that aren't pointers even when empty, so
+// those are taken from the config
+func pluginSettingsPatch(config *model.Config) *model.Config {
+	patch := &model.Config{}
+	patch.PluginSettings.Plugins = config.PluginSettings.Plugins
+	patch.RateLimitSettings.VaryByHeader = config.RateLimitSettings.VaryByHeader
+	patch.ClientRequirements = config.ClientRequirements
+	return patch
+}
+
+func guestExpireCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	after, _ := cmd.Flags().GetString("after")
+	at, _ := cmd.Flags().GetString("at")
+	clearExpiry, _ := cmd.Flags().GetBool("clear")
+
+	set := 0
+	for _, flag := range []bool{after != "", at != "", clearExpiry} {
+		if flag {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("one of --after, --at or --clear must be provided")
+	}
+
+	var expiresAt int64
+	switch {
+	case after != "":
+		d, err := parseExpiryDuration(after)
+		if err != nil {
+			return err
+		}
+		expiresAt = model.GetMillisForTime(time.Now().Add(d))
+	case at != "":
+		var err error
+		if expiresAt, err = parsePostListTime("at", at); err != nil {
+			return err
+		}
+	}
+
+	users, err := getUsersFromArgs(c, args)
+	if err != nil {
+		printer.PrintError(err.Error())
+	}
+
+	failed := 0
+	guests := []*model.User{}
+	for _, user := range users {
+		if !user.IsGuest() {
+			printer.PrintError(fmt.Sprintf("user %s is not a guest", user.Username))
+			failed++
+			continue
+		}
+		guests = append(guests, user)
+	}
+
+	if len(guests) > 0 {
+		config, _, configErr := c.GetConfig()
+		if configErr != nil {
+			return errors.Wrap(configErr, "failed to get the config")
+		}
+
+		expiries := getGuestExpiries(config)
+		for _, guest := range guests {
+			if clearExpiry {
+				delete(expiries, guest.Id)
+			} else {
+				expiries[guest.Id] = expiresAt
+			}
+		}
+		setGuestExpiries(config, expiries)
+		if _, _, configErr = c.PatchConfig(pluginSettingsPatch(config)); configErr != nil {
+			return errors.Wrap(configErr, "failed to store the expiries in the config")
+		}
+	}
+
+	for _, guest := range guests {
+		expiry := &guestExpiry{ID: guest.Id, Username: guest.Username, ExpiresAt: expiresAt}
+		if clearExpiry {
+			printer.PrintT("Guest {{.Username}} access no longer expires", expiry)
+		} else {
+			printer.PrintT("Guest {{.Username}} access expires at {{timestamp .ExpiresAt}}", expiry)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to set the expiry of %d guests", failed)
+	}
+	return nil
+}
+
+// getExpiredGuests fetches the active guests whose access expired
+// before the given time, along with the times when it expired
+func getExpiredGuests(c client.Client, now int64) ([]*model.User, map[string]int64, error) {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get the config")
+	}
+	expiries := getGuestExpiries(config)
+
+	filter := &userListFilter{role: model.SystemGuestRoleId}
+	guests := []*model.User{}
+	for page := 0; ; page++ {
+		users, usersErr := filter.getPage(c, page, APILimitMaximum)
+		if usersErr != nil {
+			return nil, nil, usersErr
+		}
+		for _, user := range users {
+			if expiresAt := expiries[user.Id]; user.DeleteAt == 0 && expiresAt > 0 && expiresAt <= now {
+				guests = append(guests, user)
+			}
+		}
+		if len(users) < APILimitMaximum {
+			return guests, expiries, nil
+		}
+	}
+}
+
+func guestEnforcePlanResolver(c client.Client, _ *cobra.Command, _ []string) ([]planAction, error) {
+	guests, _, err := getExpiredGuests(c, model.GetMillis())
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, guest := range guests {
+		actions = append(actions, planAction{Action: "deactivate_user", ID: guest.Id, Name: guest.Username})
+	}
+	return actions, nil
+}
+
+func applyGuestEnforcePlan(c client.Client, _ *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, "deactivate_user"); err != nil {
+		return err
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+	guests := []*model.User{}
+	for _, action := range actions {
+		guests = append(guests, &model.User{Id: action.ID, Username: action.Name})
+	}
+	return deactivateExpiredGuests(c, guests, getGuestExpiries(config), false)
+}
+
+func deactivateExpiredGuests(c client.Client, guests []*model.User, expiries map[string]int64, dryRun bool) error {
+	failed := 0
+	for _, guest := range guests {
+		expiry := &guestExpiry{ID: guest.Id, Username: guest.Username, ExpiresAt: expiries[guest.Id]}
+		if dryRun {
+			expiry.Result = "would be deactivated"
+			printer.PrintT("Guest {{.Username}} expired at {{timestamp .ExpiresAt}} would be deactivated", expiry)
+			continue
+		}
+
+		if _, err := c.UpdateUserActive(guest.Id, false); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to deactivate guest %s: %s", guest.Username, err))
+			failed++
+			continue
+		}
+		expiry.Result = "deactivated"
+		printer.PrintT("Guest {{.Username}} expired at {{timestamp .ExpiresAt}} deactivated", expiry)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to deactivate %d guests", failed)
+	}
+	return nil
+}
+
+func guestEnforceCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	guests, expiries, err := getExpiredGuests(c, model.GetMillis())
+	if err != nil {
+		return err
+	}
+	if len(guests) == 0 {
+		printer.Print("There are no guests past their expiry")
+		return nil
+	}
+	return deactivateExpiredGuests(c, guests, expiries, dryRun)
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestGuestExpireCmd() {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("after", "", "")
+		cmd.Flags().String("at", "", "")
+		cmd.Flags().Bool("clear", false, "")
+		return cmd
+	}
+
+	guest := &model.User{Id: "guest-id", Username: "guest", Email: "guest@example.com", Roles: model.SystemGuestRoleId}
+
+	// newConfig returns a config with the given expiries, as the server
+	// returns them
+	newConfig := func(expiries map[string]interface{}) *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{
+			"com.example.plugin": {"setting": "value"},
+		}
+		if expiries != nil {
+			config.PluginSettings.Plugins[guestExpiriesConfigKey] = expiries
+		}
+		return config
+	}
+
+	s.Run("Should record the expiry at the given time", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("at", "2026-12-31T00:00:00+00:00")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(guest.Email, "").
+			Return(guest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(map[string]interface{}{"other-guest-id": float64(1798675200000)}), &model.Response{}, nil).
+			Times(1)
+
+		expected := pluginSettingsPatch(newConfig(map[string]interface{}{"other-guest-id": int64(1798675200000), guest.Id: int64(1798675200000)}))
+		s.client.
+			EXPECT().
+			PatchConfig(expected).
+			Return(expected, &model.Response{}, nil).
+			Times(1)
+
+		err := guestExpireCmdF(s.client, cmd, []string{guest.Email})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&guestExpiry{ID: guest.Id, Username: guest.Username, ExpiresAt: 1798675200000}}, printer.GetLines())
+	})
+
+	s.Run("Should remove the expiry", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("clear", "true")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(guest.Email, "").
+			Return(guest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(map[string]interface{}{guest.Id: float64(1798675200000)}), &model.Response{}, nil).
+			Times(1)
+
+		expected := pluginSettingsPatch(newConfig(map[string]interface{}{}))
+		s.client.
+			EXPECT().
+			PatchConfig(expected).
+			Return(expected, &model.Response{}, nil).
+			Times(1)
+
+		err := guestExpireCmdF(s.client, cmd, []string{guest.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should only patch the settings of the plugins", func() {
+		config := newConfig(nil)
+		config.RateLimitSettings.VaryByHeader = "X-Real-IP"
+		config.ClientRequirements.AndroidMinVersion = "2.0.0"
+
+		patch := pluginSettingsPatch(config)
+		s.Require().Equal(config.PluginSettings.Plugins, patch.PluginSettings.Plugins)
+		s.Require().Equal("X-Real-IP", patch.RateLimitSettings.VaryByHeader)
+		s.Require().Equal("2.0.0", patch.ClientRequirements.AndroidMinVersion)
+		s.Require().Nil(patch.ServiceSettings.SiteURL)
+		s.Require().Nil(patch.PluginSettings.Enable)
+		s.Require().Nil(patch.PluginSettings.PluginStates)
+	})
+
+	s.Run("Should not set the expiry of regular users", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("after", "30d")
+		user := &model.User{Id: "user-id", Username: "user", Email: "user@example.com", Roles: model.SystemUserRoleId}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		err := guestExpireCmdF(s.client, cmd, []string{user.Email})
+		s.Require().EqualError(err, "unable to set the expiry of 1 guests")
+		s.Require().Equal([]interface{}{"user user is not a guest"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fail without an expiry", func() {
+		err := guestExpireCmdF(s.client, newCmd(), []string{guest.Email})
+		s.Require().EqualError(err, "one of --after, --at or --clear must be provided")
+	})
+
+	s.Run("Should fail with an invalid duration", func() {
+		cmd := newCmd()
+		_ = cmd.Flags().Set("after", "a month")
+
+		err := guestExpireCmdF(s.client, cmd, []string{guest.Email})
+		s.Require().EqualError(err, `invalid duration "a month"`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestGuestEnforceCmd() {
+	usersResponse := func(users ...*model.User) *http.Response {
+		b, err := json.Marshal(users)
+		s.Require().NoError(err)
+		return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}
+	}
+
+	past := float64(model.GetMillis() - 1000)
+	future := float64(model.GetMillis() + 3600000)
+	expired := &model.User{Id: "expired-id", Username: "expired", Roles: model.SystemGuestRoleId}
+	valid := &model.User{Id: "valid-id", Username: "valid", Roles: model.SystemGuestRoleId}
+	unbounded := &model.User{Id: "unbounded-id", Username: "unbounded", Roles: model.SystemGuestRoleId}
+	deactivated := &model.User{Id: "deactivated-id", Username: "deactivated", Roles: model.SystemGuestRoleId, DeleteAt: 1}
+	// the props of the users don't count, as the guests can patch them
+	tampered := &model.User{Id: "tampered-id", Username: "tampered", Roles: model.SystemGuestRoleId, Props: model.StringMap{"guest_access_expires_at": "0"}}
+
+	config := &model.Config{}
+	config.SetDefaults()
+	config.PluginSettings.Plugins = map[string]map[string]interface{}{
+		guestExpiriesConfigKey: {
+			expired.Id:     past,
+			valid.Id:       future,
+			deactivated.Id: past,
+			tampered.Id:    past,
+		},
+	}
+
+	expectGuests := func() {
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?page=0&per_page=200&role=system_guest", "").
+			Return(usersResponse(expired, valid, unbounded, deactivated, tampered), nil).
+			Times(1)
+	}
+
+	s.Run("Should deactivate the guests past their expiry", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "")
+		expectGuests()
+
+		s.client.
+			EXPECT().
+			UpdateUserActive(expired.Id, false).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateUserActive(tampered.Id, false).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := guestEnforceCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		for i, guest := range []*model.User{expired, tampered} {
+			s.Require().Equal(guest.Id, printer.GetLines()[i].(*guestExpiry).ID)
+			s.Require().Equal("deactivated", printer.GetLines()[i].(*guestExpiry).Result)
+		}
+	})
+
+	s.Run("Should only list the guests with dry run", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", true, "")
+		expectGuests()
+
+		err := guestEnforceCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal("would be deactivated", printer.GetLines()[0].(*guestExpiry).Result)
+	})
+
+	s.Run("Should report the guests that can't be deactivated", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "")
+		expectGuests()
+
+		s.client.
+			EXPECT().
+			UpdateUserActive(expired.Id, false).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateUserActive(tampered.Id, false).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := guestEnforceCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "unable to deactivate 1 guests")
+		s.Require().Equal([]interface{}{"unable to deactivate guest expired: mock error"}, printer.GetErrorLines())
+	})
+}
@@ -97,6 +97,10 @@ var ImportValidateCmd = &cobra.Command{
 func init() {
 	ImportUploadCmd.Flags().Bool("resume", false, "Set to true to resume an incomplete import upload.")
 	ImportUploadCmd.Flags().String("upload", "", "The ID of the import upload to resume.")
+	ImportUploadCmd.Flags().String("expect-sha256", "", "Fail without uploading if the SHA-256 digest of the import file doesn't match.")
+	ImportUploadCmd.Flags().String("limit-rate", "", "Maximum upload rate in bytes per second, optionally followed by K, M or G, e.g. 500K.")
+
+	addJobWaitFlags(ImportProcessCmd)
 
 	ImportJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of import jobs")
 	ImportJobListCmd.Flags().Int("per-page", 200, "Number of import jobs to be fetched")
@@ -174,12 +178,24 @@ func importListAvailableCmdF(c client.Client, command *cobra.Command, args []str
 func importUploadCmdF(c client.Client, command *cobra.Command, args []string) error {
 	filepath := args[0]
 
+	limitRate, _ := command.Flags().GetString("limit-rate")
+	rate, err := parseRate(limitRate)
+	if err != nil {
+		return err
+	}
+
 	file, err := os.Open(filepath)
 	if err != nil {
 		return fmt.Errorf("failed to open import file: %w", err)
 	}
 	defer file.Close()
 
+	expectedSHA256, _ := command.Flags().GetString("expect-sha256")
+	digest, err := checkFileSHA256(filepath, expectedSHA256)
+	if err != nil {
+		return err
+	}
+
 	info, err := file.Stat()
 	if err != nil {
 		return fmt.Errorf("failed to stat import file: %w", err)
@@ -225,12 +241,18 @@ func importUploadCmdF(c client.Client, command *cobra.Command, args []string) er
 		printer.PrintT("Upload session successfully created, ID: {{.Id}} ", us)
 	}
 
-	finfo, _, err := c.UploadData(us.Id, file)
+	var finfo *model.FileInfo
+	err = withUploadProgress(info.Name(), us.FileOffset, us.FileSize, func() error {
+		var uErr error
+		finfo, _, uErr = c.UploadData(us.Id, limitReader(file, rate))
+		return uErr
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upload data: %w", err)
 	}
 
 	printer.PrintT("Import file successfully uploaded, name: {{.Id}}", finfo)
+	printSHA256(filepath, digest)
 
 	return nil
 }
@@ -250,24 +272,22 @@ func importProcessCmdF(c client.Client, command *cobra.Command, args []string) e
 
 	printer.PrintT("Import process job successfully created, ID: {{.Id}}", job)
 
-	return nil
+	return waitForJobIfRequested(c, command, job)
 }
 
 func printJob(job *model.Job) {
 	if job.StartAt > 0 {
-		printer.PrintT(fmt.Sprintf(`  ID: {{.Id}}
+		printer.PrintT(`  ID: {{.Id}}
   Status: {{.Status}}
-  Created: %s
-  Started: %s
+  Created: {{timestamp .CreateAt}}
+  Started: {{timestamp .StartAt}}
   Data: {{.Data}}
-`,
-			time.Unix(job.CreateAt/1000, 0), time.Unix(job.StartAt/1000, 0)), job)
+`, job)
 	} else {
-		printer.PrintT(fmt.Sprintf(`  ID: {{.Id}}
+		printer.PrintT(`  ID: {{.Id}}
   Status: {{.Status}}
-  Created: %s
-`,
-			time.Unix(job.CreateAt/1000, 0)), job)
+  Created: {{timestamp .CreateAt}}
+`, job)
 	}
 }
 
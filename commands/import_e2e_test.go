@@ -51,7 +51,7 @@ func (s *MmctlE2ETestSuite) TestImportUploadCmdF() {
 
 		err := importUploadCmdF(c, cmd, []string{importFilePath})
 		s.Require().Nil(err)
-		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Len(printer.GetLines(), 3)
 		s.Require().Empty(printer.GetErrorLines())
 		s.Require().Equal(importName, printer.GetLines()[0].(*model.UploadSession).Filename)
 		s.Require().Equal(importName, printer.GetLines()[1].(*model.FileInfo).Name)
@@ -80,7 +80,7 @@ func (s *MmctlE2ETestSuite) TestImportUploadCmdF() {
 
 		err = importUploadCmdF(c, cmd, []string{importFilePath})
 		s.Require().NoError(err)
-		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Len(printer.GetLines(), 2)
 		s.Require().Empty(printer.GetErrorLines())
 		s.Require().Equal(importName, printer.GetLines()[0].(*model.FileInfo).Name)
 	})
@@ -112,7 +112,7 @@ func (s *MmctlE2ETestSuite) TestImportProcessCmdF() {
 
 		err := importUploadCmdF(c, cmd, []string{importFilePath})
 		s.Require().Nil(err)
-		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Len(printer.GetLines(), 3)
 		s.Require().Empty(printer.GetErrorLines())
 
 		us := printer.GetLines()[0].(*model.UploadSession)
@@ -0,0 +1,721 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/app"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ImportFromRocketChatCmd = &cobra.Command{
+	Use:   "from-rocketchat [dump-directory] [filepath]",
+	Short: "Convert a Rocket.Chat dump into an import file",
+	Long: `Convert the collections of a Rocket.Chat database exported with mongoexport into a bulk import JSONL file.
+The dump directory must contain the users.json, rocketchat_room.json and rocketchat_message.json files, and optionally rocketchat_subscription.json for the memberships of the channels, which are otherwise taken from the rooms. Each file can be a JSON array or one document per line.
+Rocket.Chat has no teams, so all the channels are created in the team given with --team. Public and private rooms become channels, direct rooms direct channels, and threads become replies. The users and channels whose names are the same once converted get a numeric suffix, and the messages too long for a post are split into several posts. Bots, users without an email, system messages, attachments and the messages of unknown users are skipped. The Rocket.Chat admins are imported as regular users unless --import-admins is set, in which case they are promoted to system admins. Use --report to write how each user and channel was mapped and why entries were skipped to a CSV file.
+Check the result with "import validate" before uploading it.`,
+	Example: `  # mongoexport --db rocketchat --collection users --jsonArray --out dump/users.json
+  import from-rocketchat dump import.jsonl --team myteam --report mapping.csv
+
+  # import the Rocket.Chat admins as system admins
+  import from-rocketchat dump import.jsonl --team myteam --import-admins`,
+	Args: cobra.ExactArgs(2),
+	RunE: importFromRocketChatCmdF,
+}
+
+func init() {
+	ImportFromRocketChatCmd.Flags().String("team", "", "Name of the team to create the channels in")
+	_ = ImportFromRocketChatCmd.MarkFlagRequired("team")
+	ImportFromRocketChatCmd.Flags().String("team-display-name", "", "Display name of the team, defaults to its name")
+	ImportFromRocketChatCmd.Flags().String("report", "", "Path of the CSV file to write the mapping of the users and channels to")
+	ImportFromRocketChatCmd.Flags().Bool("import-admins", false, "Import the Rocket.Chat admins as system admins instead of regular users")
+
+	ImportCmd.AddCommand(ImportFromRocketChatCmd)
+}
+
+// rocketChatDate is a date of a mongoexport document, which is either
+// in the extended JSON format or a plain ISO 8601 string or number of
+// milliseconds
+type rocketChatDate int64
+
+func (d *rocketChatDate) UnmarshalJSON(b []byte) error {
+	var extended struct {
+		Date json.RawMessage `json:"$date"`
+		Long string          `json:"$numberLong"`
+	}
+	if len(b) > 0 && b[0] == '{' {
+		if err := json.Unmarshal(b, &extended); err != nil {
+			return err
+		}
+		if extended.Long != "" {
+			b = []byte(extended.Long)
+		} else {
+			return d.UnmarshalJSON(extended.Date)
+		}
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+	if millis, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = rocketChatDate(millis)
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("invalid date %s", b)
+	}
+	*d = rocketChatDate(model.GetMillisForTime(t))
+	return nil
+}
+
+type rocketChatUser struct {
+	ID       string `json:"_id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Emails   []struct {
+		Address string `json:"address"`
+	} `json:"emails"`
+	Roles  []string `json:"roles"`
+	Active bool     `json:"active"`
+	Type   string   `json:"type"`
+}
+
+type rocketChatRoom struct {
+	ID          string   `json:"_id"`
+	Type        string   `json:"t"`
+	Name        string   `json:"name"`
+	FName       string   `json:"fname"`
+	Topic       string   `json:"topic"`
+	Description string   `json:"description"`
+	Usernames   []string `json:"usernames"`
+}
+
+type rocketChatSubscription struct {
+	RoomID string `json:"rid"`
+	User   struct {
+		Username string `json:"username"`
+	} `json:"u"`
+	Roles []string `json:"roles"`
+}
+
+type rocketChatFile struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+type rocketChatMessage struct {
+	ID       string `json:"_id"`
+	RoomID   string `json:"rid"`
+	Message  string `json:"msg"`
+	Type     string `json:"t"`
+	ThreadID string `json:"tmid"`
+	User     struct {
+		Username string `json:"username"`
+	} `json:"u"`
+	Timestamp rocketChatDate   `json:"ts"`
+	File      *rocketChatFile  `json:"file"`
+	Files     []rocketChatFile `json:"files"`
+}
+
+// attachments returns the files of the message, which are given in the
+// files field, and in the file field by the older versions
+func (m *rocketChatMessage) attachments() []rocketChatFile {
+	files := append([]rocketChatFile{}, m.Files...)
+	if m.File != nil {
+		found := false
+		for _, file := range files {
+			found = found || file.ID == m.File.ID
+		}
+		if !found {
+			files = append(files, *m.File)
+		}
+	}
+	return files
+}
+
+// decodeRocketChatCollection decodes the documents of a collection
+// exported as a JSON array or as one document per line
+func decodeRocketChatCollection(path string, decode func(json.RawMessage) error) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	docs := []json.RawMessage{}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &docs); err != nil {
+			return fmt.Errorf("cannot parse %s: %w", filepath.Base(path), err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		for decoder.More() {
+			var doc json.RawMessage
+			if err := decoder.Decode(&doc); err != nil {
+				return fmt.Errorf("cannot parse %s: %w", filepath.Base(path), err)
+			}
+			docs = append(docs, doc)
+		}
+	}
+
+	for i, doc := range docs {
+		if err := decode(doc); err != nil {
+			return fmt.Errorf("invalid document %d of %s: %w", i+1, filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+type rocketChatDump struct {
+	users         []*rocketChatUser
+	rooms         []*rocketChatRoom
+	subscriptions []*rocketChatSubscription
+	messages      []*rocketChatMessage
+}
+
+func readRocketChatDump(dir string) (*rocketChatDump, error) {
+	dump := &rocketChatDump{}
+	collections := []struct {
+		file     string
+		optional bool
+		decode   func(json.RawMessage) error
+	}{
+		{"users.json", false, func(doc json.RawMessage) error {
+			var user rocketChatUser
+			dump.users = append(dump.users, &user)
+			return json.Unmarshal(doc, &user)
+		}},
+		{"rocketchat_room.json", false, func(doc json.RawMessage) error {
+			var room rocketChatRoom
+			dump.rooms = append(dump.rooms, &room)
+			return json.Unmarshal(doc, &room)
+		}},
+		{"rocketchat_subscription.json", true, func(doc json.RawMessage) error {
+			var subscription rocketChatSubscription
+			dump.subscriptions = append(dump.subscriptions, &subscription)
+			return json.Unmarshal(doc, &subscription)
+		}},
+		{"rocketchat_message.json", false, func(doc json.RawMessage) error {
+			var message rocketChatMessage
+			dump.messages = append(dump.messages, &message)
+			return json.Unmarshal(doc, &message)
+		}},
+	}
+
+	for _, collection := range collections {
+		path := filepath.Join(dir, collection.file)
+		if _, err := os.Stat(path); os.IsNotExist(err) && collection.optional {
+			continue
+		}
+		if err := decodeRocketChatCollection(path, collection.decode); err != nil {
+			return nil, fmt.Errorf("cannot read the Rocket.Chat dump: %w", err)
+		}
+	}
+	return dump, nil
+}
+
+var invalidChannelNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// cleanRocketChatChannelName turns the name of a room into a valid
+// channel name, falling back to one based on its id
+func cleanRocketChatChannelName(room *rocketChatRoom) string {
+	name := invalidChannelNameChars.ReplaceAllString(strings.ToLower(room.Name), "-")
+	name = strings.Trim(name, "-_")
+	if len(name) > model.ChannelNameMaxLength {
+		name = strings.Trim(name[:model.ChannelNameMaxLength], "-_")
+	}
+	if !model.IsValidChannelIdentifier(name) {
+		name = "rc-" + strings.ToLower(invalidChannelNameChars.ReplaceAllString(room.ID, ""))
+	}
+	return name
+}
+
+// uniqueImportName returns the name, or the name with the lowest
+// numeric suffix that isn't taken yet if it is, cut to fit in the
+// maximum length
+func uniqueImportName(name string, taken map[string]bool, maxLength int) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		base := name
+		if len(base)+len(suffix) > maxLength {
+			base = base[:maxLength-len(suffix)]
+		}
+		unique = base + suffix
+	}
+	taken[unique] = true
+	return unique
+}
+
+func truncateRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
+
+// splitRunes splits the string into parts of at most max runes
+func splitRunes(s string, max int) []string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return []string{s}
+	}
+	parts := []string{}
+	for len(runes) > max {
+		parts = append(parts, string(runes[:max]))
+		runes = runes[max:]
+	}
+	return append(parts, string(runes))
+}
+
+type rocketChatMapping struct {
+	Kind           string
+	RocketChatID   string
+	RocketChatName string
+	MattermostName string
+	Result         string
+}
+
+type rocketChatConversion struct {
+	File           string `json:"file"`
+	Users          int    `json:"users"`
+	Channels       int    `json:"channels"`
+	DirectChannels int    `json:"direct_channels"`
+	Posts          int    `json:"posts"`
+	Skipped        int    `json:"skipped"`
+	Admins         int    `json:"admins"`
+
+	mappings []*rocketChatMapping
+}
+
+func (conv *rocketChatConversion) mapped(kind, id, name, mattermostName string) {
+	conv.mappings = append(conv.mappings, &rocketChatMapping{kind, id, name, mattermostName, "imported"})
+}
+
+// mappedUnique records the mapping of an entity whose name had to be
+// changed as another entity is imported with the same name
+func (conv *rocketChatConversion) mappedUnique(kind, id, name, cleanName, mattermostName string) {
+	if cleanName == mattermostName {
+		conv.mapped(kind, id, name, mattermostName)
+		return
+	}
+	conv.mappings = append(conv.mappings, &rocketChatMapping{kind, id, name, mattermostName, "imported: renamed, " + cleanName + " is taken"})
+}
+
+// note appends a note to the result of the last recorded mapping
+func (conv *rocketChatConversion) note(note string) {
+	mapping := conv.mappings[len(conv.mappings)-1]
+	if mapping.Result == "imported" {
+		mapping.Result += ": " + note
+		return
+	}
+	mapping.Result += ", " + note
+}
+
+func (conv *rocketChatConversion) skip(kind, id, name, reason string) {
+	conv.Skipped++
+	conv.mappings = append(conv.mappings, &rocketChatMapping{kind, id, name, "", "skipped: " + reason})
+}
+
+// rocketChatConverter maps the entities of a Rocket.Chat dump to the
+// lines of a bulk import file
+type rocketChatConverter struct {
+	team         string
+	importAdmins bool
+	now          int64
+	conv         *rocketChatConversion
+
+	// usernames maps the Rocket.Chat usernames to the imported ones,
+	// and takenUsernames and takenChannels are the imported names
+	usernames      map[string]string
+	takenUsernames map[string]bool
+	takenChannels  map[string]bool
+	// channels maps the ids of the public and private rooms to the
+	// channel names, and directs the ids of the direct rooms to
+	// their members
+	channels map[string]string
+	directs  map[string][]string
+}
+
+func (rc *rocketChatConverter) userLines(dump *rocketChatDump) []app.LineImportData {
+	// memberships maps the Rocket.Chat usernames to the rooms they
+	// belong to, and the rooms they own
+	memberships := map[string][]string{}
+	owners := map[string]bool{}
+	if len(dump.subscriptions) > 0 {
+		for _, subscription := range dump.subscriptions {
+			memberships[subscription.User.Username] = append(memberships[subscription.User.Username], subscription.RoomID)
+			for _, role := range subscription.Roles {
+				if role == "owner" {
+					owners[subscription.RoomID+":"+subscription.User.Username] = true
+				}
+			}
+		}
+	} else {
+		for _, room := range dump.rooms {
+			for _, username := range room.Usernames {
+				memberships[username] = append(memberships[username], room.ID)
+			}
+		}
+	}
+
+	lines := []app.LineImportData{}
+	for _, user := range dump.users {
+		switch {
+		case user.Type == "bot":
+			rc.conv.skip("user", user.ID, user.Username, "bot")
+			continue
+		case user.Username == "":
+			rc.conv.skip("user", user.ID, user.Username, "no username")
+			continue
+		case len(user.Emails) == 0 || user.Emails[0].Address == "":
+			rc.conv.skip("user", user.ID, user.Username, "no email")
+			continue
+		}
+
+		cleanUsername := model.CleanUsername(user.Username)
+		username := uniqueImportName(cleanUsername, rc.takenUsernames, model.UserNameMaxLength)
+		rc.usernames[user.Username] = username
+		rc.conv.mappedUnique("user", user.ID, user.Username, cleanUsername, username)
+		rc.conv.Users++
+
+		firstName, lastName := user.Name, ""
+		if i := strings.LastIndex(user.Name, " "); i > 0 {
+			firstName, lastName = user.Name[:i], user.Name[i+1:]
+		}
+		roles := model.SystemUserRoleId
+		for _, role := range user.Roles {
+			if role != "admin" {
+				continue
+			}
+			rc.conv.Admins++
+			if rc.importAdmins {
+				roles = model.SystemAdminRoleId + " " + model.SystemUserRoleId
+				rc.conv.note("promoted to system admin")
+			} else {
+				rc.conv.note("admin imported as a regular user")
+			}
+			break
+		}
+
+		channels := []app.UserChannelImportData{}
+		for _, roomID := range memberships[user.Username] {
+			channel, ok := rc.channels[roomID]
+			if !ok {
+				continue
+			}
+			channelRoles := model.ChannelUserRoleId
+			if owners[roomID+":"+user.Username] {
+				channelRoles += " " + model.ChannelAdminRoleId
+			}
+			channels = append(channels, app.UserChannelImportData{Name: model.NewString(channel), Roles: model.NewString(channelRoles)})
+		}
+
+		line := &app.UserImportData{
+			Username:  model.NewString(username),
+			Email:     model.NewString(strings.ToLower(user.Emails[0].Address)),
+			FirstName: model.NewString(firstName),
+			LastName:  model.NewString(lastName),
+			Roles:     model.NewString(roles),
+			Teams: &[]app.UserTeamImportData{{
+				Name:     model.NewString(rc.team),
+				Roles:    model.NewString(model.TeamUserRoleId),
+				Channels: &channels,
+			}},
+		}
+		if !user.Active {
+			line.DeleteAt = model.NewInt64(rc.now)
+		}
+		lines = append(lines, app.LineImportData{Type: "user", User: line})
+	}
+	return lines
+}
+
+func (rc *rocketChatConverter) channelLines(dump *rocketChatDump) []app.LineImportData {
+	lines := []app.LineImportData{}
+	for _, room := range dump.rooms {
+		var channelType model.ChannelType
+		switch room.Type {
+		case "c":
+			channelType = model.ChannelTypeOpen
+		case "p":
+			channelType = model.ChannelTypePrivate
+		case "d":
+			rc.directs[room.ID] = room.Usernames
+			continue
+		default:
+			rc.conv.skip("channel", room.ID, room.Name, "unsupported room type "+room.Type)
+			continue
+		}
+
+		cleanName := cleanRocketChatChannelName(room)
+		name := uniqueImportName(cleanName, rc.takenChannels, model.ChannelNameMaxLength)
+		rc.channels[room.ID] = name
+		rc.conv.mappedUnique("channel", room.ID, room.Name, cleanName, name)
+		rc.conv.Channels++
+
+		displayName := room.FName
+		if displayName == "" {
+			displayName = room.Name
+		}
+		lines = append(lines, app.LineImportData{Type: "channel", Channel: &app.ChannelImportData{
+			Team:        model.NewString(rc.team),
+			Name:        model.NewString(name),
+			DisplayName: model.NewString(truncateRunes(displayName, model.ChannelDisplayNameMaxRunes)),
+			Type:        &channelType,
+			Header:      model.NewString(truncateRunes(room.Topic, model.ChannelHeaderMaxRunes)),
+			Purpose:     model.NewString(truncateRunes(room.Description, model.ChannelPurposeMaxRunes)),
+		}})
+	}
+	return lines
+}
+
+// directLines returns the direct channels whose members were all
+// imported, mapping their ids to the imported usernames
+func (rc *rocketChatConverter) directLines() []app.LineImportData {
+	ids := make([]string, 0, len(rc.directs))
+	for id := range rc.directs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	lines := []app.LineImportData{}
+	for _, id := range ids {
+		members := []string{}
+		for _, username := range rc.directs[id] {
+			if member, ok := rc.usernames[username]; ok {
+				members = append(members, member)
+			}
+		}
+		sort.Strings(members)
+
+		switch {
+		case len(members) != len(rc.directs[id]) || len(members) < 2:
+			rc.conv.skip("direct_channel", id, strings.Join(rc.directs[id], ","), "not all the members were imported")
+			delete(rc.directs, id)
+			continue
+		case len(members) > model.ChannelGroupMaxUsers:
+			rc.conv.skip("direct_channel", id, strings.Join(rc.directs[id], ","), "too many members")
+			delete(rc.directs, id)
+			continue
+		}
+
+		rc.conv.mapped("direct_channel", id, strings.Join(rc.directs[id], ","), strings.Join(members, ","))
+		rc.directs[id] = members
+		rc.conv.DirectChannels++
+		lines = append(lines, app.LineImportData{Type: "direct_channel", DirectChannel: &app.DirectChannelImportData{Members: &members}})
+	}
+	return lines
+}
+
+// postLines returns the posts of the channels and direct channels,
+// with the messages of the threads as their replies
+func (rc *rocketChatConverter) postLines(dump *rocketChatDump) []app.LineImportData {
+	messages := []*rocketChatMessage{}
+	for _, message := range dump.messages {
+		_, inChannel := rc.channels[message.RoomID]
+		_, inDirect := rc.directs[message.RoomID]
+		switch {
+		case message.Type != "":
+			// system messages, like users joining a room
+			rc.conv.Skipped++
+			continue
+		case !inChannel && !inDirect:
+			rc.conv.skip("post", message.ID, message.User.Username, "the room was not imported")
+			continue
+		}
+		if _, ok := rc.usernames[message.User.Username]; !ok {
+			rc.conv.skip("post", message.ID, message.User.Username, "the user was not imported")
+			continue
+		}
+		messages = append(messages, message)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp < messages[j].Timestamp
+	})
+
+	roots := map[string]*app.LineImportData{}
+	lines := []*app.LineImportData{}
+	for _, message := range messages {
+		for _, file := range message.attachments() {
+			rc.conv.skip("attachment", file.ID, file.Name, "attachments are not converted")
+		}
+
+		// the messages too long for a post are split into several
+		// posts, one millisecond apart to keep their order
+		user := model.NewString(rc.usernames[message.User.Username])
+		parts := splitRunes(message.Message, model.PostMessageMaxRunesV1)
+		if len(parts) > 1 {
+			rc.conv.mappings = append(rc.conv.mappings, &rocketChatMapping{"post", message.ID, message.User.Username, "", fmt.Sprintf("imported: split into %d posts", len(parts))})
+		}
+
+		if root, ok := roots[message.ThreadID]; ok && message.ThreadID != "" {
+			for i, part := range parts {
+				reply := app.ReplyImportData{User: user, Message: model.NewString(part), CreateAt: model.NewInt64(int64(message.Timestamp) + int64(i))}
+				if root.Post != nil {
+					*root.Post.Replies = append(*root.Post.Replies, reply)
+				} else {
+					*root.DirectPost.Replies = append(*root.DirectPost.Replies, reply)
+				}
+			}
+			continue
+		}
+
+		for i, part := range parts {
+			text := model.NewString(part)
+			createAt := model.NewInt64(int64(message.Timestamp) + int64(i))
+
+			var line *app.LineImportData
+			if channel, ok := rc.channels[message.RoomID]; ok {
+				line = &app.LineImportData{Type: "post", Post: &app.PostImportData{
+					Team:     model.NewString(rc.team),
+					Channel:  model.NewString(channel),
+					User:     user,
+					Message:  text,
+					CreateAt: createAt,
+					Replies:  &[]app.ReplyImportData{},
+				}}
+			} else {
+				members := rc.directs[message.RoomID]
+				line = &app.LineImportData{Type: "direct_post", DirectPost: &app.DirectPostImportData{
+					ChannelMembers: &members,
+					User:           user,
+					Message:        text,
+					CreateAt:       createAt,
+					Replies:        &[]app.ReplyImportData{},
+				}}
+			}
+			if i == 0 {
+				roots[message.ID] = line
+			}
+			lines = append(lines, line)
+			rc.conv.Posts++
+		}
+	}
+
+	result := make([]app.LineImportData, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, *line)
+	}
+	return result
+}
+
+// convertRocketChatDump returns the import lines of the dump, with the
+// channels created in the given team and the admins promoted to system
+// admins if importAdmins is set
+func convertRocketChatDump(dump *rocketChatDump, team, teamDisplayName string, importAdmins bool) ([]app.LineImportData, *rocketChatConversion) {
+	rc := &rocketChatConverter{
+		team:           team,
+		importAdmins:   importAdmins,
+		now:            model.GetMillis(),
+		conv:           &rocketChatConversion{},
+		usernames:      map[string]string{},
+		takenUsernames: map[string]bool{},
+		takenChannels:  map[string]bool{},
+		channels:       map[string]string{},
+		directs:        map[string][]string{},
+	}
+
+	version := 1
+	lines := []app.LineImportData{
+		{Type: "version", Version: &version},
+		{Type: "team", Team: &app.TeamImportData{
+			Name:        model.NewString(team),
+			DisplayName: model.NewString(teamDisplayName),
+			Type:        model.NewString(model.TeamOpen),
+		}},
+	}
+	lines = append(lines, rc.channelLines(dump)...)
+	lines = append(lines, rc.userLines(dump)...)
+	lines = append(lines, rc.directLines()...)
+	lines = append(lines, rc.postLines(dump)...)
+	return lines, rc.conv
+}
+
+func writeRocketChatReport(path string, mappings []*rocketChatMapping) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write into the %q file: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"kind", "rocketchat_id", "rocketchat_name", "mattermost_name", "result"})
+	for _, m := range mappings {
+		_ = w.Write([]string{m.Kind, m.RocketChatID, m.RocketChatName, m.MattermostName, m.Result})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write the report: %w", err)
+	}
+	return f.Close()
+}
+
+func importFromRocketChatCmdF(command *cobra.Command, args []string) error {
+	team, _ := command.Flags().GetString("team")
+	teamDisplayName, _ := command.Flags().GetString("team-display-name")
+	reportPath, _ := command.Flags().GetString("report")
+	importAdmins, _ := command.Flags().GetBool("import-admins")
+
+	if !model.IsValidTeamName(team) {
+		return fmt.Errorf("invalid team name %q", team)
+	}
+	if teamDisplayName == "" {
+		teamDisplayName = team
+	}
+
+	dump, err := readRocketChatDump(args[0])
+	if err != nil {
+		return err
+	}
+	if len(dump.users) == 0 {
+		return errors.New("the Rocket.Chat dump has no users")
+	}
+
+	lines, conv := convertRocketChatDump(dump, team, teamDisplayName, importAdmins)
+
+	out, err := openImportOutput(args[1], false)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+	if err := encodeImportLines(out, lines); err != nil {
+		return err
+	}
+
+	if reportPath != "" {
+		if err := writeRocketChatReport(reportPath, conv.mappings); err != nil {
+			return err
+		}
+	}
+
+	if conv.Admins > 0 && !importAdmins {
+		printer.PrintWarning(fmt.Sprintf("%d Rocket.Chat admins were imported as regular users, use --import-admins to make them system admins", conv.Admins))
+	}
+	if out != os.Stdout {
+		conv.File = args[1]
+		printer.PrintT("{{.Users}} users, {{.Channels}} channels, {{.DirectChannels}} direct channels and {{.Posts}} posts written to {{.File}}, {{.Skipped}} entries skipped", conv)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/app"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/commands/importer"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestImportFromRocketChatCmd() {
+	writeDump := func(dir string, files map[string]string) {
+		for name, content := range files {
+			s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+		}
+	}
+
+	newCmd := func(reportPath string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "rocketchat", "")
+		cmd.Flags().String("team-display-name", "", "")
+		cmd.Flags().String("report", reportPath, "")
+		cmd.Flags().Bool("import-admins", false, "")
+		return cmd
+	}
+
+	dump := map[string]string{
+		"users.json": `[
+			{"_id": "u1", "username": "John.Doe", "name": "John Doe", "emails": [{"address": "John@example.com"}], "roles": ["user", "admin"], "active": true, "type": "user"},
+			{"_id": "u2", "username": "jane", "name": "Jane", "emails": [{"address": "jane@example.com"}], "roles": ["user"], "active": false, "type": "user"},
+			{"_id": "u3", "username": "rocket.cat", "roles": ["bot"], "active": true, "type": "bot"},
+			{"_id": "u4", "username": "noemail", "roles": ["user"], "active": true, "type": "user"}
+		]`,
+		"rocketchat_room.json": `{"_id": "GENERAL", "t": "c", "name": "general", "topic": "Welcome", "usernames": ["John.Doe", "jane"]}
+{"_id": "r2", "t": "p", "name": "Secret Plans!", "fname": "Secret Plans!", "description": "Do not share"}
+{"_id": "u1u2", "t": "d", "usernames": ["John.Doe", "jane"]}
+{"_id": "u1u4", "t": "d", "usernames": ["John.Doe", "noemail"]}
+{"_id": "l1", "t": "l", "name": "livechat"}`,
+		"rocketchat_subscription.json": `[
+			{"rid": "GENERAL", "u": {"username": "John.Doe"}, "roles": ["owner"]},
+			{"rid": "GENERAL", "u": {"username": "jane"}},
+			{"rid": "r2", "u": {"username": "jane"}}
+		]`,
+		"rocketchat_message.json": `[
+			{"_id": "m1", "rid": "GENERAL", "msg": "Hello", "u": {"username": "John.Doe"}, "ts": {"$date": "2021-03-01T10:00:00.000Z"}},
+			{"_id": "m2", "rid": "GENERAL", "msg": "Hi", "tmid": "m1", "u": {"username": "jane"}, "ts": {"$date": {"$numberLong": "1614593000000"}}},
+			{"_id": "m3", "rid": "GENERAL", "msg": "jane", "t": "uj", "u": {"username": "jane"}, "ts": {"$date": 1614592000000}},
+			{"_id": "m4", "rid": "u1u2", "msg": "Private hello", "u": {"username": "jane"}, "ts": {"$date": "2021-03-02T10:00:00Z"}},
+			{"_id": "m5", "rid": "r2", "msg": "From a bot", "u": {"username": "rocket.cat"}, "ts": {"$date": "2021-03-02T11:00:00Z"}},
+			{"_id": "m6", "rid": "l1", "msg": "Livechat", "u": {"username": "jane"}, "ts": {"$date": "2021-03-02T12:00:00Z"}}
+		]`,
+	}
+
+	s.Run("Should convert a Rocket.Chat dump into a valid import file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeDump(tmp, dump)
+		path := filepath.Join(tmp, "import.jsonl")
+		reportPath := filepath.Join(tmp, "mapping.csv")
+
+		err := importFromRocketChatCmdF(newCmd(reportPath), []string{tmp, path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		conv := printer.GetLines()[0].(*rocketChatConversion)
+		s.Require().Equal(2, conv.Users)
+		s.Require().Equal(2, conv.Channels)
+		s.Require().Equal(1, conv.DirectChannels)
+		s.Require().Equal(2, conv.Posts)
+		s.Require().Equal(7, conv.Skipped)
+
+		lines := map[string][]app.LineImportData{}
+		for _, line := range decodeTestImportFile(s, path) {
+			lines[line.Type] = append(lines[line.Type], line)
+		}
+
+		s.Require().Len(lines["channel"], 2)
+		s.Require().Equal("general", *lines["channel"][0].Channel.Name)
+		s.Require().Equal("Welcome", *lines["channel"][0].Channel.Header)
+		s.Require().Equal("secret-plans", *lines["channel"][1].Channel.Name)
+		s.Require().Equal("Secret Plans!", *lines["channel"][1].Channel.DisplayName)
+
+		s.Require().Len(lines["user"], 2)
+		john, jane := lines["user"][0].User, lines["user"][1].User
+		s.Require().Equal("john.doe", *john.Username)
+		s.Require().Equal("john@example.com", *john.Email)
+		s.Require().Equal("system_user", *john.Roles)
+		s.Require().Nil(john.DeleteAt)
+		s.Require().Equal("channel_user channel_admin", *(*(*john.Teams)[0].Channels)[0].Roles)
+		s.Require().NotNil(jane.DeleteAt)
+		s.Require().Len(*(*jane.Teams)[0].Channels, 2)
+
+		s.Require().Len(lines["post"], 1)
+		post := lines["post"][0].Post
+		s.Require().Equal("Hello", *post.Message)
+		s.Require().Equal(int64(1614592800000), *post.CreateAt)
+		s.Require().Len(*post.Replies, 1)
+		s.Require().Equal("jane", *(*post.Replies)[0].User)
+
+		s.Require().Len(lines["direct_post"], 1)
+		s.Require().Equal([]string{"jane", "john.doe"}, *lines["direct_post"][0].DirectPost.ChannelMembers)
+
+		report, err := ioutil.ReadFile(reportPath)
+		s.Require().NoError(err)
+		s.Require().Contains(string(report), "kind,rocketchat_id,rocketchat_name,mattermost_name,result\n")
+		s.Require().Contains(string(report), "user,u1,John.Doe,john.doe,imported: admin imported as a regular user\n")
+		s.Require().Contains(string(report), "direct_channel,u1u2,\"John.Doe,jane\",\"jane,john.doe\",imported\n")
+		s.Require().Contains(string(report), "user,u4,noemail,,skipped: no email\n")
+		s.Require().Contains(string(report), "channel,l1,livechat,,skipped: unsupported room type l\n")
+		s.Require().Contains(string(report), "post,m5,rocket.cat,,skipped: the user was not imported\n")
+
+		s.Require().Empty(validateTestImportFile(s, tmp, path))
+	})
+
+	s.Run("Should promote the admins to system admins with --import-admins", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeDump(tmp, dump)
+		path := filepath.Join(tmp, "import.jsonl")
+		reportPath := filepath.Join(tmp, "mapping.csv")
+		cmd := newCmd(reportPath)
+		_ = cmd.Flags().Set("import-admins", "true")
+
+		err := importFromRocketChatCmdF(cmd, []string{tmp, path})
+		s.Require().NoError(err)
+		conv := printer.GetLines()[0].(*rocketChatConversion)
+		s.Require().Equal(1, conv.Admins)
+
+		lines := map[string][]app.LineImportData{}
+		for _, line := range decodeTestImportFile(s, path) {
+			lines[line.Type] = append(lines[line.Type], line)
+		}
+		s.Require().Equal("system_admin system_user", *lines["user"][0].User.Roles)
+		s.Require().Equal("system_user", *lines["user"][1].User.Roles)
+
+		report, err := ioutil.ReadFile(reportPath)
+		s.Require().NoError(err)
+		s.Require().Contains(string(report), "user,u1,John.Doe,john.doe,imported: promoted to system admin\n")
+		s.Require().Contains(string(report), "user,u2,jane,jane,imported\n")
+	})
+
+	s.Run("Should rename the duplicated names and split the long messages", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		longMessage := strings.Repeat("a", model.PostMessageMaxRunesV1+10)
+		writeDump(tmp, map[string]string{
+			"users.json": `[
+				{"_id": "u1", "username": "John.Doe", "emails": [{"address": "john@example.com"}], "active": true},
+				{"_id": "u2", "username": "john.doe", "emails": [{"address": "john.doe@example.com"}], "active": true}
+			]`,
+			"rocketchat_room.json": `[
+				{"_id": "r1", "t": "c", "name": "General!", "usernames": ["John.Doe", "john.doe"]},
+				{"_id": "r2", "t": "c", "name": "general", "usernames": ["john.doe"]}
+			]`,
+			"rocketchat_message.json": `[
+				{"_id": "m1", "rid": "r1", "msg": "` + longMessage + `", "u": {"username": "John.Doe"}, "ts": 1000},
+				{"_id": "m2", "rid": "r2", "msg": "", "u": {"username": "john.doe"}, "ts": 2000, "file": {"_id": "f1", "name": "plan.pdf"}, "files": [{"_id": "f1", "name": "plan.pdf"}]}
+			]`,
+		})
+		path := filepath.Join(tmp, "import.jsonl")
+		reportPath := filepath.Join(tmp, "mapping.csv")
+
+		err := importFromRocketChatCmdF(newCmd(reportPath), []string{tmp, path})
+		s.Require().NoError(err)
+		conv := printer.GetLines()[0].(*rocketChatConversion)
+		s.Require().Equal(2, conv.Users)
+		s.Require().Equal(2, conv.Channels)
+		s.Require().Equal(3, conv.Posts)
+		s.Require().Equal(1, conv.Skipped)
+
+		lines := map[string][]app.LineImportData{}
+		for _, line := range decodeTestImportFile(s, path) {
+			lines[line.Type] = append(lines[line.Type], line)
+		}
+		s.Require().Equal("general", *lines["channel"][0].Channel.Name)
+		s.Require().Equal("general-2", *lines["channel"][1].Channel.Name)
+		s.Require().Equal("john.doe", *lines["user"][0].User.Username)
+		s.Require().Equal("john.doe-2", *lines["user"][1].User.Username)
+		s.Require().Len(lines["post"], 3)
+		s.Require().Equal(longMessage, *lines["post"][0].Post.Message+*lines["post"][1].Post.Message)
+		s.Require().Equal(int64(1001), *lines["post"][1].Post.CreateAt)
+
+		report, err := ioutil.ReadFile(reportPath)
+		s.Require().NoError(err)
+		s.Require().Contains(string(report), "channel,r2,general,general-2,\"imported: renamed, general is taken\"\n")
+		s.Require().Contains(string(report), "user,u2,john.doe,john.doe-2,\"imported: renamed, john.doe is taken\"\n")
+		s.Require().Contains(string(report), "post,m1,John.Doe,,imported: split into 2 posts\n")
+		s.Require().Contains(string(report), "attachment,f1,plan.pdf,,skipped: attachments are not converted\n")
+
+		s.Require().Empty(validateTestImportFile(s, tmp, path))
+	})
+
+	s.Run("Should fail with an invalid team name", func() {
+		cmd := newCmd("")
+		_ = cmd.Flags().Set("team", "Not A Team")
+
+		err := importFromRocketChatCmdF(cmd, []string{"dump", "import.jsonl"})
+		s.Require().EqualError(err, `invalid team name "Not A Team"`)
+	})
+
+	s.Run("Should fail if a collection is missing", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		writeDump(tmp, map[string]string{"users.json": dump["users.json"]})
+
+		err := importFromRocketChatCmdF(newCmd(""), []string{tmp, filepath.Join(tmp, "import.jsonl")})
+		s.Require().Error(err)
+		s.Require().True(strings.HasPrefix(err.Error(), "cannot read the Rocket.Chat dump: "))
+	})
+}
+
+func decodeTestImportFile(s *MmctlUnitTestSuite, path string) []app.LineImportData {
+	f, err := os.Open(path)
+	s.Require().NoError(err)
+	defer f.Close()
+
+	lines := []app.LineImportData{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line app.LineImportData
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	s.Require().NoError(scanner.Err())
+	return lines
+}
+
+// validateTestImportFile runs the import validator on an archive
+// containing the given import file
+func validateTestImportFile(s *MmctlUnitTestSuite, dir, path string) []*importer.ImportValidationError {
+	archivePath := filepath.Join(dir, "import.zip")
+	archive, err := os.Create(archivePath)
+	s.Require().NoError(err)
+	zw := zip.NewWriter(archive)
+	w, err := zw.Create("import.jsonl")
+	s.Require().NoError(err)
+	data, err := ioutil.ReadFile(path)
+	s.Require().NoError(err)
+	_, err = w.Write(data)
+	s.Require().NoError(err)
+	s.Require().NoError(zw.Close())
+	s.Require().NoError(archive.Close())
+
+	validationErrors := []*importer.ImportValidationError{}
+	validator := importer.NewValidator(archivePath, true, false)
+	validator.OnError(func(ive *importer.ImportValidationError) error {
+		validationErrors = append(validationErrors, ive)
+		return nil
+	})
+	s.Require().NoError(validator.Validate())
+	return validationErrors
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/app"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ImportTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Scaffolding for hand-built import files",
+}
+
+var ImportTemplateGenerateCmd = &cobra.Command{
+	Use:   "generate [filepath]",
+	Short: "Generate a skeleton import file",
+	Long: `Generate a bulk import JSONL file with an example of each of the main sections: a team, a public and a private channel, two users with their memberships, a post with a reply, and a direct message.
+JSONL files cannot contain comments, so the examples are valid entries whose values describe what they are. Replace them with the real data, and check the result with "import validate" before uploading it. Use "-" as the file path to print the skeleton to the standard output.`,
+	Example: `  import template generate import.jsonl
+  import template generate - | less`,
+	Args: cobra.ExactArgs(1),
+	RunE: importTemplateGenerateCmdF,
+}
+
+var ImportTemplateConvertUsersCmd = &cobra.Command{
+	Use:   "convert-users [csv-file] [filepath]",
+	Short: "Convert a CSV of users into import lines",
+	Long: `Convert a CSV file of users into bulk import user lines.
+The first row of the CSV must be a header. The "username" and "email" columns are required, and the "first_name", "last_name", "nickname", "position", "roles", "locale", "auth_service" and "auth_data" columns are optional.
+The optional "teams" column contains the names of the teams of the user separated by semicolons, and the optional "channels" column the channels of the user in "team:channel" format separated by semicolons.
+Use --append to add the user lines to an existing import file, e.g. one created with "import template generate".`,
+	Example: `  # users.csv
+  # username,email,first_name,last_name,teams,channels
+  # john.doe,john@example.com,John,Doe,myteam,myteam:town-square;myteam:off-topic
+  import template convert-users users.csv import.jsonl
+  import template convert-users users.csv import.jsonl --append`,
+	Args: cobra.ExactArgs(2),
+	RunE: importTemplateConvertUsersCmdF,
+}
+
+func init() {
+	ImportTemplateConvertUsersCmd.Flags().Bool("append", false, "Append the user lines to an existing import file instead of creating a new one")
+
+	ImportTemplateCmd.AddCommand(
+		ImportTemplateGenerateCmd,
+		ImportTemplateConvertUsersCmd,
+	)
+	ImportCmd.AddCommand(ImportTemplateCmd)
+}
+
+// importTemplateLines returns the example lines of the skeleton
+// import file, with the posts created at the given time
+func importTemplateLines(createAt int64) []app.LineImportData {
+	version := 1
+	openChannel := model.ChannelTypeOpen
+	privateChannel := model.ChannelTypePrivate
+
+	return []app.LineImportData{
+		{Type: "version", Version: &version},
+		{Type: "team", Team: &app.TeamImportData{
+			Name:            model.NewString("example-team"),
+			DisplayName:     model.NewString("Example Team"),
+			Type:            model.NewString(model.TeamOpen),
+			Description:     model.NewString("Teams are defined before the channels and users that reference them"),
+			AllowOpenInvite: model.NewBool(false),
+		}},
+		{Type: "channel", Channel: &app.ChannelImportData{
+			Team:        model.NewString("example-team"),
+			Name:        model.NewString("example-channel"),
+			DisplayName: model.NewString("Example Channel"),
+			Type:        &openChannel,
+			Header:      model.NewString("Public channels have the O type"),
+			Purpose:     model.NewString("Channels reference their team by name"),
+		}},
+		{Type: "channel", Channel: &app.ChannelImportData{
+			Team:        model.NewString("example-team"),
+			Name:        model.NewString("example-private-channel"),
+			DisplayName: model.NewString("Example Private Channel"),
+			Type:        &privateChannel,
+			Header:      model.NewString("Private channels have the P type"),
+		}},
+		{Type: "user", User: &app.UserImportData{
+			Username:  model.NewString("example.user"),
+			Email:     model.NewString("example.user@example.com"),
+			Nickname:  model.NewString(""),
+			FirstName: model.NewString("Example"),
+			LastName:  model.NewString("User"),
+			Position:  model.NewString("Users without a password or auth service get a random password"),
+			Roles:     model.NewString(model.SystemUserRoleId),
+			Locale:    model.NewString("en"),
+			Teams: &[]app.UserTeamImportData{{
+				Name:  model.NewString("example-team"),
+				Roles: model.NewString(model.TeamUserRoleId),
+				Channels: &[]app.UserChannelImportData{{
+					Name:  model.NewString("example-channel"),
+					Roles: model.NewString(model.ChannelUserRoleId),
+				}},
+			}},
+		}},
+		{Type: "user", User: &app.UserImportData{
+			Username:  model.NewString("example.admin"),
+			Email:     model.NewString("example.admin@example.com"),
+			Nickname:  model.NewString(""),
+			FirstName: model.NewString("Example"),
+			LastName:  model.NewString("Admin"),
+			Position:  model.NewString("Admins have the admin roles of the system, teams and channels they manage"),
+			Roles:     model.NewString(model.SystemUserRoleId),
+			Locale:    model.NewString("en"),
+			Teams: &[]app.UserTeamImportData{{
+				Name:  model.NewString("example-team"),
+				Roles: model.NewString(model.TeamUserRoleId + " " + model.TeamAdminRoleId),
+				Channels: &[]app.UserChannelImportData{
+					{
+						Name:  model.NewString("example-channel"),
+						Roles: model.NewString(model.ChannelUserRoleId + " " + model.ChannelAdminRoleId),
+					},
+					{
+						Name:  model.NewString("example-private-channel"),
+						Roles: model.NewString(model.ChannelUserRoleId),
+					},
+				},
+			}},
+		}},
+		{Type: "post", Post: &app.PostImportData{
+			Team:     model.NewString("example-team"),
+			Channel:  model.NewString("example-channel"),
+			User:     model.NewString("example.user"),
+			Message:  model.NewString("Posts reference their team, channel and user by name, and need a creation time in milliseconds"),
+			CreateAt: model.NewInt64(createAt),
+			Replies: &[]app.ReplyImportData{{
+				User:     model.NewString("example.admin"),
+				Message:  model.NewString("Replies are nested in the post they belong to"),
+				CreateAt: model.NewInt64(createAt + 1),
+			}},
+		}},
+		{Type: "direct_channel", DirectChannel: &app.DirectChannelImportData{
+			Members: &[]string{"example.user", "example.admin"},
+		}},
+		{Type: "direct_post", DirectPost: &app.DirectPostImportData{
+			ChannelMembers: &[]string{"example.user", "example.admin"},
+			User:           model.NewString("example.admin"),
+			Message:        model.NewString("Direct posts reference their channel by its members"),
+			CreateAt:       model.NewInt64(createAt + 2),
+		}},
+	}
+}
+
+// openImportOutput opens the file the import lines are written to,
+// which is the standard output if the path is "-"
+func openImportOutput(path string, appendLines bool) (io.WriteCloser, error) {
+	if path == "-" {
+		return os.Stdout, nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendLines {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write into the %q file: %w", path, err)
+	}
+	return f, nil
+}
+
+func encodeImportLines(w io.Writer, lines []app.LineImportData) error {
+	encoder := json.NewEncoder(w)
+	for i, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			return fmt.Errorf("could not encode line %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func importTemplateGenerateCmdF(command *cobra.Command, args []string) error {
+	out, err := openImportOutput(args[0], false)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	lines := importTemplateLines(model.GetMillisForTime(time.Now().Truncate(time.Hour)))
+	if err := encodeImportLines(out, lines); err != nil {
+		return err
+	}
+
+	if out != os.Stdout {
+		printer.Print(fmt.Sprintf("Import file skeleton with %d lines written to %s", len(lines), args[0]))
+	}
+
+	return nil
+}
+
+// splitCSVList splits a semicolon separated CSV cell
+func splitCSVList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func csvRecordToUserLine(record map[string]string) (app.LineImportData, error) {
+	if record["username"] == "" || record["email"] == "" {
+		return app.LineImportData{}, errors.New("username and email are required")
+	}
+
+	optional := func(column string) *string {
+		if value, ok := record[column]; ok && value != "" {
+			return model.NewString(value)
+		}
+		return nil
+	}
+
+	user := &app.UserImportData{
+		Username:    model.NewString(record["username"]),
+		Email:       model.NewString(record["email"]),
+		FirstName:   optional("first_name"),
+		LastName:    optional("last_name"),
+		Nickname:    optional("nickname"),
+		Position:    optional("position"),
+		Roles:       optional("roles"),
+		Locale:      optional("locale"),
+		AuthService: optional("auth_service"),
+		AuthData:    optional("auth_data"),
+	}
+
+	teams := []app.UserTeamImportData{}
+	teamIndex := map[string]int{}
+	addTeam := func(name string) int {
+		if i, ok := teamIndex[name]; ok {
+			return i
+		}
+		teams = append(teams, app.UserTeamImportData{
+			Name:     model.NewString(name),
+			Roles:    model.NewString(model.TeamUserRoleId),
+			Channels: &[]app.UserChannelImportData{},
+		})
+		teamIndex[name] = len(teams) - 1
+		return len(teams) - 1
+	}
+
+	for _, teamName := range splitCSVList(record["teams"]) {
+		addTeam(teamName)
+	}
+	for _, channelArg := range splitCSVList(record["channels"]) {
+		teamName, channelName := parseChannelArg(channelArg)
+		if teamName == "" || channelName == "" {
+			return app.LineImportData{}, fmt.Errorf("channel %q is not in team:channel format", channelArg)
+		}
+		channels := teams[addTeam(teamName)].Channels
+		*channels = append(*channels, app.UserChannelImportData{
+			Name:  model.NewString(channelName),
+			Roles: model.NewString(model.ChannelUserRoleId),
+		})
+	}
+	if len(teams) > 0 {
+		user.Teams = &teams
+	}
+
+	return app.LineImportData{Type: "user", User: user}, nil
+}
+
+func readUsersCSV(path string) ([]app.LineImportData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open CSV file: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CSV file: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("the CSV file needs a header and at least one user")
+	}
+
+	header := rows[0]
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	lines := make([]app.LineImportData, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := map[string]string{}
+		for j, column := range header {
+			record[column] = strings.TrimSpace(row[j])
+		}
+
+		line, err := csvRecordToUserLine(record)
+		if err != nil {
+			// the header is the first row, and rows are 1-indexed
+			return nil, fmt.Errorf("invalid user in row %d: %w", i+2, err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func importTemplateConvertUsersCmdF(command *cobra.Command, args []string) error {
+	appendLines, _ := command.Flags().GetBool("append")
+
+	lines, err := readUsersCSV(args[0])
+	if err != nil {
+		return err
+	}
+	userCount := len(lines)
+	if !appendLines {
+		version := 1
+		lines = append([]app.LineImportData{{Type: "version", Version: &version}}, lines...)
+	}
+
+	out, err := openImportOutput(args[1], appendLines)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	if err := encodeImportLines(out, lines); err != nil {
+		return err
+	}
+
+	if out != os.Stdout {
+		printer.Print(fmt.Sprintf("%d users written to %s", userCount, args[1]))
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/app"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/commands/importer"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestImportTemplateCmds() {
+	readLines := func(path string) []app.LineImportData {
+		f, err := os.Open(path)
+		s.Require().NoError(err)
+		defer f.Close()
+
+		lines := []app.LineImportData{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var line app.LineImportData
+			s.Require().NoError(json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		s.Require().NoError(scanner.Err())
+		return lines
+	}
+
+	// validateImportFile runs the import validator on an archive
+	// containing the given import file
+	validateImportFile := func(dir, path string) []*importer.ImportValidationError {
+		archivePath := filepath.Join(dir, "import.zip")
+		archive, err := os.Create(archivePath)
+		s.Require().NoError(err)
+		zw := zip.NewWriter(archive)
+		w, err := zw.Create("import.jsonl")
+		s.Require().NoError(err)
+		data, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		_, err = w.Write(data)
+		s.Require().NoError(err)
+		s.Require().NoError(zw.Close())
+		s.Require().NoError(archive.Close())
+
+		validationErrors := []*importer.ImportValidationError{}
+		validator := importer.NewValidator(archivePath, true, false)
+		validator.OnError(func(ive *importer.ImportValidationError) error {
+			validationErrors = append(validationErrors, ive)
+			return nil
+		})
+		s.Require().NoError(validator.Validate())
+		return validationErrors
+	}
+
+	s.Run("Should generate a valid skeleton import file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "import.jsonl")
+
+		err := importTemplateGenerateCmdF(&cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+
+		types := []string{}
+		for _, line := range readLines(path) {
+			types = append(types, line.Type)
+		}
+		s.Require().Equal([]string{"version", "team", "channel", "channel", "user", "user", "post", "direct_channel", "direct_post"}, types)
+		s.Require().Empty(validateImportFile(tmp, path))
+	})
+
+	s.Run("Should convert a CSV of users into import lines", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		csvPath := filepath.Join(tmp, "users.csv")
+		s.Require().NoError(ioutil.WriteFile(csvPath, []byte(`username,email,first_name,teams,channels
+john.doe,john@example.com,John,example-team,example-team:example-channel;other-team:town-square
+jane.doe,jane@example.com,,,
+`), 0600))
+		path := filepath.Join(tmp, "import.jsonl")
+		s.Require().NoError(importTemplateGenerateCmdF(&cobra.Command{}, []string{path}))
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("append", true, "")
+		err := importTemplateConvertUsersCmdF(cmd, []string{csvPath, path})
+		s.Require().NoError(err)
+
+		lines := readLines(path)
+		s.Require().Len(lines, 11)
+
+		john := lines[9].User
+		s.Require().Equal("john.doe", *john.Username)
+		s.Require().Equal("John", *john.FirstName)
+		s.Require().Nil(john.LastName)
+		s.Require().Len(*john.Teams, 2)
+		s.Require().Equal("example-team", *(*john.Teams)[0].Name)
+		s.Require().Equal("example-channel", *(*(*john.Teams)[0].Channels)[0].Name)
+		s.Require().Equal("other-team", *(*john.Teams)[1].Name)
+		s.Require().Equal("town-square", *(*(*john.Teams)[1].Channels)[0].Name)
+
+		jane := lines[10].User
+		s.Require().Equal("jane.doe", *jane.Username)
+		s.Require().Nil(jane.FirstName)
+		s.Require().Nil(jane.Teams)
+
+		// other-team is not defined in the file, so it is reported
+		// as missing by the validator
+		validationErrors := validateImportFile(tmp, path)
+		s.Require().Len(validationErrors, 1)
+		s.Require().Contains(validationErrors[0].Error(), `reference to unknown team "other-team"`)
+	})
+
+	s.Run("Should start a new import file with the version line", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		csvPath := filepath.Join(tmp, "users.csv")
+		s.Require().NoError(ioutil.WriteFile(csvPath, []byte("username,email\njohn.doe,john@example.com\n"), 0600))
+		path := filepath.Join(tmp, "users.jsonl")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("append", false, "")
+		err := importTemplateConvertUsersCmdF(cmd, []string{csvPath, path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("1 users written to "+path, printer.GetLines()[0])
+
+		lines := readLines(path)
+		s.Require().Len(lines, 2)
+		s.Require().Equal("version", lines[0].Type)
+		s.Require().Equal("user", lines[1].Type)
+	})
+
+	s.Run("Should fail if a user has no email", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		csvPath := filepath.Join(tmp, "users.csv")
+		s.Require().NoError(ioutil.WriteFile(csvPath, []byte("username,email\njohn.doe,john@example.com\njane.doe,\n"), 0600))
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("append", false, "")
+		err := importTemplateConvertUsersCmdF(cmd, []string{csvPath, filepath.Join(tmp, "users.jsonl")})
+		s.Require().EqualError(err, "invalid user in row 3: username and email are required")
+	})
+}
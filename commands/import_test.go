@@ -224,3 +224,59 @@ func (s *MmctlUnitTestSuite) TestImportProcessCmdF() {
 	s.Empty(printer.GetErrorLines())
 	s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
 }
+
+func (s *MmctlUnitTestSuite) TestImportProcessCmdFWait() {
+	originalInterval := jobPollInterval
+	defer func() { jobPollInterval = originalInterval }()
+	jobPollInterval = 0
+
+	importFile := "import.zip"
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+		return cmd
+	}
+
+	s.Run("Should fail if the job fails", func() {
+		printer.Clean()
+		mockJob := &model.Job{Id: "job-id", Type: model.JobTypeImportProcess, Data: map[string]string{"import_file": importFile}}
+
+		s.client.
+			EXPECT().
+			CreateJob(&model.Job{Type: model.JobTypeImportProcess, Data: map[string]string{"import_file": importFile}}).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(&model.Job{Id: mockJob.Id, Status: model.JobStatusError, Data: map[string]string{"error": "invalid archive"}}, &model.Response{}, nil).
+			Times(1)
+
+		err := importProcessCmdF(s.client, newCmd(), []string{importFile})
+		s.Require().EqualError(err, "job job-id finished with status error: invalid archive")
+	})
+
+	s.Run("Should time out if the job doesn't finish", func() {
+		printer.Clean()
+		mockJob := &model.Job{Id: "job-id", Type: model.JobTypeImportProcess, Data: map[string]string{"import_file": importFile}}
+
+		s.client.
+			EXPECT().
+			CreateJob(&model.Job{Type: model.JobTypeImportProcess, Data: map[string]string{"import_file": importFile}}).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetJob(mockJob.Id).
+			Return(&model.Job{Id: mockJob.Id, Status: model.JobStatusPending}, &model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd()
+		_ = cmd.Flags().Set("timeout", "0s")
+		err := importProcessCmdF(s.client, cmd, []string{importFile})
+		s.Require().EqualError(err, "timed out after 0s waiting for job job-id to finish, its status is pending")
+	})
+}
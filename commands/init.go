@@ -62,14 +62,40 @@ func CheckVersionMatch(version, serverVersion string) (bool, error) {
 }
 
 func withClient(fn func(c client.Client, cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
-	return func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		if shellClient != nil {
+			for _, arg := range args {
+				if arg == stdinArg {
+					return errors.New("the standard input can't be used in the arguments of the commands of the shell")
+				}
+			}
+			// the commands that change how the client is created get a
+			// client of their own
+			if !shellClientFlagsChanged() {
+				return fn(shellClient, cmd, args)
+			}
+		}
+
+		if destination := viper.GetString("trace-otlp"); destination != "" {
+			previousTracer := apiTracer
+			apiTracer = newTracer(cmd.CommandPath())
+			defer func() {
+				if exportErr := apiTracer.export(destination, err); exportErr != nil {
+					printer.PrintWarning("unable to export the trace: " + exportErr.Error())
+				}
+				apiTracer = previousTracer
+			}()
+		}
+
 		if viper.GetBool("local") {
 			c, err := InitUnixClient(viper.GetString("local-socket-path"))
 			if err != nil {
 				return err
 			}
 			printer.SetServerAddres("local instance")
-			return fn(c, cmd, args)
+			return withStdinArgs(cmd, args, func(args []string) error {
+				return fn(c, cmd, args)
+			})
 		}
 
 		c, serverVersion, err := InitClient(viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
@@ -94,7 +120,9 @@ func withClient(fn func(c client.Client, cmd *cobra.Command, args []string) erro
 		}
 
 		printer.SetServerAddres(c.APIURL)
-		return fn(c, cmd, args)
+		return withStdinArgs(cmd, args, func(args []string) error {
+			return fn(c, cmd, args)
+		})
 	}
 }
 
@@ -159,6 +187,12 @@ func NewAPIv4Client(instanceURL string, allowInsecureSHA1, allowInsecureTLS bool
 			Proxy:           http.ProxyFromEnvironment,
 		},
 	}
+	client.HTTPClient.Transport = withTransferProgress(client.HTTPClient.Transport)
+
+	if apiTracer != nil {
+		client.HTTPClient.Transport = apiTracer.wrap(client.HTTPClient.Transport)
+	}
+	client.HTTPClient.Transport = withRetries(client.HTTPClient.Transport)
 
 	return client
 }
@@ -201,7 +235,17 @@ func InitClient(allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, strin
 	if err != nil {
 		return nil, "", err
 	}
-	return InitClientWithCredentials(credentials, allowInsecureSHA1, allowInsecureTLS)
+	client, serverVersion, err := InitClientWithCredentials(credentials, allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// the version history is informative only, so failing to record
+	// it must not prevent running the command
+	if err := recordServerVersion(credentials, serverVersion); err != nil && !viper.GetBool("suppress-warnings") {
+		printer.PrintWarning("unable to record the server version: " + err.Error())
+	}
+	return client, serverVersion, nil
 }
 
 func InitWebSocketClient() (*model.WebSocketClient, error) {
@@ -221,7 +265,14 @@ func InitUnixClient(socketPath string) (*model.Client4, error) {
 		return nil, err
 	}
 
-	return model.NewAPIv4SocketClient(socketPath), nil
+	client := model.NewAPIv4SocketClient(socketPath)
+	client.HTTPClient.Transport = withTransferProgress(client.HTTPClient.Transport)
+	if apiTracer != nil {
+		client.HTTPClient.Transport = apiTracer.wrap(client.HTTPClient.Transport)
+	}
+	client.HTTPClient.Transport = withRetries(client.HTTPClient.Transport)
+
+	return client, nil
 }
 
 func checkInsecureTLSError(err error, allowInsecureTLS bool) error {
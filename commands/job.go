@@ -0,0 +1,304 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var JobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Management of the server jobs",
+	Long:  "List, show and cancel the jobs of any type run by the server, such as data retention, LDAP sync, export and indexing jobs.",
+}
+
+var JobListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List jobs",
+	Long:  "List the jobs of the server, newest first, optionally only the ones of a type or with a status.",
+	Example: `  job list
+  job list --type ldap_sync --status error,warning
+  job list --type data_retention --all`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    withClient(jobListAllCmdF),
+}
+
+var JobShowCmd = &cobra.Command{
+	Use:     "show [jobID]",
+	Short:   "Show a job",
+	Example: "  job show f3d68qkkm7n8xgsfxwuo498rah",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(jobShowCmdF),
+}
+
+var JobCancelCmd = &cobra.Command{
+	Use:     "cancel [jobID]",
+	Short:   "Cancel a job",
+	Long:    "Request the cancellation of a pending or in progress job. The job is canceled by its worker, which may take some time.",
+	Example: "  job cancel f3d68qkkm7n8xgsfxwuo498rah",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(jobCancelCmdF),
+}
+
+var JobLogsCmd = &cobra.Command{
+	Use:   "logs [jobID]",
+	Short: "Display the logs of a job",
+	Long: `Display the server log lines that mention a job, in a human-readable format, among the last lines of the logs of the server. The error of the job is shown too if it failed.
+As the logs format depends on the server, the "--format" flag cannot be used with this command.`,
+	Example: `  job logs f3d68qkkm7n8xgsfxwuo498rah
+  job logs f3d68qkkm7n8xgsfxwuo498rah --number 5000`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(jobLogsCmdF),
+}
+
+func init() {
+	JobListCmd.Flags().String("type", "", "Only list the jobs of this type")
+	JobListCmd.Flags().StringSlice("status", []string{}, "Only list the jobs with these statuses: pending, in_progress, success, error, cancel_requested, canceled or warning")
+	JobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of jobs")
+	JobListCmd.Flags().Int("per-page", 200, "Number of jobs to be fetched")
+	JobListCmd.Flags().Bool("all", false, "Fetch all jobs. --page flag will be ignore if provided")
+
+	JobLogsCmd.Flags().IntP("number", "n", 1000, "Number of server log lines to search the job in")
+	JobLogsCmd.Flags().BoolP("logrus", "l", false, "Use logrus for formatting.")
+
+	JobCmd.AddCommand(
+		JobListCmd,
+		JobShowCmd,
+		JobCancelCmd,
+		JobLogsCmd,
+	)
+	RootCmd.AddCommand(JobCmd)
+}
+
+var jobStatuses = []string{
+	model.JobStatusPending,
+	model.JobStatusInProgress,
+	model.JobStatusSuccess,
+	model.JobStatusError,
+	model.JobStatusCancelRequested,
+	model.JobStatusCanceled,
+	model.JobStatusWarning,
+}
+
+// jobPollInterval is the time between job status checks
+// when waiting for a job to finish
+var jobPollInterval = 2 * time.Second
+
+// addJobWaitFlags adds the flags to wait for the job started by a
+// command to finish
+func addJobWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait for the job to finish, failing if it doesn't succeed")
+	cmd.Flags().Duration("timeout", time.Hour, "Maximum time to wait for the job to finish when --wait is set")
+}
+
+// waitForJobIfRequested waits for the job to finish if the --wait flag
+// of the command is set
+func waitForJobIfRequested(c client.Client, cmd *cobra.Command, job *model.Job) error {
+	if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+		return nil
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	return waitForJobUntil(c, job, time.Now().Add(timeout), timeout)
+}
+
+// waitForJobUntil waits for the job to finish until the deadline of the
+// timeout, which can have started before the job was created, and
+// prints the job once finished
+func waitForJobUntil(c client.Client, job *model.Job, deadline time.Time, timeout time.Duration) error {
+	job, err := waitForJob(c, job.Id, deadline, timeout)
+	if err != nil {
+		return err
+	}
+	printer.PrintT("Job {{.Id}} finished with status {{.Status}}", job)
+	return nil
+}
+
+// waitForJob polls the status of the job until it's finished or the
+// deadline of the timeout is reached, failing if it didn't succeed.
+// Jobs finished with warnings are successful
+func waitForJob(c client.Client, jobID string, deadline time.Time, timeout time.Duration) (*model.Job, error) {
+	for {
+		job, _, err := c.GetJob(jobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job: %w", err)
+		}
+
+		switch job.Status {
+		case model.JobStatusSuccess:
+			return job, nil
+		case model.JobStatusWarning:
+			printer.PrintWarning(fmt.Sprintf("job %s finished with warnings", job.Id))
+			return job, nil
+		case model.JobStatusError, model.JobStatusCanceled:
+			if jobError := job.Data["error"]; jobError != "" {
+				return nil, fmt.Errorf("job %s finished with status %s: %s", job.Id, job.Status, jobError)
+			}
+			return nil, fmt.Errorf("job %s finished with status %s", job.Id, job.Status)
+		}
+
+		if time.Now().Add(jobPollInterval).After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for job %s to finish, its status is %s", timeout, job.Id, job.Status)
+		}
+		time.Sleep(jobPollInterval)
+	}
+}
+
+func isValidJobType(jobType string) bool {
+	for _, t := range model.AllJobTypes {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidJobStatus(status string) bool {
+	for _, s := range jobStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func printJobDetails(job *model.Job) {
+	printer.PrintT(`  ID: {{.Id}}
+  Type: {{.Type}}
+  Status: {{.Status}}
+  Created: {{timestamp .CreateAt}}{{if .StartAt}}
+  Started: {{timestamp .StartAt}}
+  Progress: {{.Progress}}%{{end}}{{if .Data}}
+  Data: {{.Data}}{{end}}
+`, job)
+}
+
+func jobListAllCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	jobType, _ := cmd.Flags().GetString("type")
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	page, _ := cmd.Flags().GetInt("page")
+	perPage, _ := cmd.Flags().GetInt("per-page")
+	showAll, _ := cmd.Flags().GetBool("all")
+
+	if jobType != "" && !isValidJobType(jobType) {
+		return fmt.Errorf("invalid job type %q, the types are %s", jobType, strings.Join(model.AllJobTypes[:], ", "))
+	}
+	wanted := map[string]bool{}
+	for _, status := range statuses {
+		if !isValidJobStatus(status) {
+			return fmt.Errorf("invalid job status %q, the statuses are %s", status, strings.Join(jobStatuses, ", "))
+		}
+		wanted[status] = true
+	}
+
+	if showAll {
+		page = 0
+	}
+
+	found := 0
+	for {
+		var jobs []*model.Job
+		var err error
+		if jobType != "" {
+			jobs, _, err = c.GetJobsByType(jobType, page, perPage)
+		} else {
+			jobs, _, err = c.GetJobs(page, perPage)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get jobs: %w", err)
+		}
+
+		for _, job := range jobs {
+			if len(wanted) > 0 && !wanted[job.Status] {
+				continue
+			}
+			found++
+			printer.PrintT("{{.Id}} {{.Type}} {{.Status}} {{timestamp .CreateAt}}", job)
+		}
+
+		if !showAll || len(jobs) < perPage {
+			break
+		}
+		page++
+	}
+
+	if found == 0 {
+		printer.Print("No jobs found")
+	}
+	return nil
+}
+
+func jobShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	job, _, err := c.GetJob(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	printJobDetails(job)
+	return nil
+}
+
+func jobCancelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	job, _, err := c.GetJob(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if job.Status != model.JobStatusPending && job.Status != model.JobStatusInProgress {
+		return fmt.Errorf("job %s can't be canceled as its status is %s", job.Id, job.Status)
+	}
+
+	if _, err := c.CancelJob(job.Id); err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	printer.PrintT("Cancellation of job {{.Id}} requested", job)
+	return nil
+}
+
+func jobLogsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if err := checkLogsFormat(cmd); err != nil {
+		return err
+	}
+
+	job, _, err := c.GetJob(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	number, _ := cmd.Flags().GetInt("number")
+	logLines, _, err := c.GetLogs(0, number)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve logs: %w", err)
+	}
+
+	// the workers log the ID of the job they run, so the lines of the
+	// job are the ones that mention it
+	jobLines := []string{}
+	for _, line := range logLines {
+		if strings.Contains(line, job.Id) {
+			jobLines = append(jobLines, line)
+		}
+	}
+
+	if len(jobLines) == 0 {
+		printer.PrintWarning(fmt.Sprintf("no log lines of job %s found in the last %d lines of the server logs", job.Id, number))
+	} else {
+		printHumanLogs(cmd, jobLines)
+	}
+
+	if jobError, ok := job.Data["error"]; ok && jobError != "" {
+		fmt.Fprintf(os.Stdout, "Job error: %s\n", jobError)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestJobListAllCmd() {
+	newCmd := func(jobType string, statuses []string, all bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("type", jobType, "")
+		cmd.Flags().StringSlice("status", statuses, "")
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", 2, "")
+		cmd.Flags().Bool("all", all, "")
+		return cmd
+	}
+
+	s.Run("Should list the jobs of a type with a status across the pages", func() {
+		printer.Clean()
+		failed := &model.Job{Id: "job-1", Type: model.JobTypeLdapSync, Status: model.JobStatusError}
+		succeeded := &model.Job{Id: "job-2", Type: model.JobTypeLdapSync, Status: model.JobStatusSuccess}
+		warned := &model.Job{Id: "job-3", Type: model.JobTypeLdapSync, Status: model.JobStatusWarning}
+
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeLdapSync, 0, 2).
+			Return([]*model.Job{failed, succeeded}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeLdapSync, 1, 2).
+			Return([]*model.Job{warned}, &model.Response{}, nil).
+			Times(1)
+
+		err := jobListAllCmdF(s.client, newCmd(model.JobTypeLdapSync, []string{"error", "warning"}, true), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{failed, warned}, printer.GetLines())
+	})
+
+	s.Run("Should list a page of the jobs of all types", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetJobs(0, 2).
+			Return([]*model.Job{}, &model.Response{}, nil).
+			Times(1)
+
+		err := jobListAllCmdF(s.client, newCmd("", nil, false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"No jobs found"}, printer.GetLines())
+	})
+
+	s.Run("Should fail with an invalid status", func() {
+		err := jobListAllCmdF(s.client, newCmd("", []string{"done"}, false), []string{})
+		s.Require().EqualError(err, "invalid job status \"done\", the statuses are pending, in_progress, success, error, cancel_requested, canceled, warning")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestJobCancelCmd() {
+	s.Run("Should cancel a job in progress", func() {
+		printer.Clean()
+		job := &model.Job{Id: "job-id", Status: model.JobStatusInProgress}
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CancelJob(job.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := jobCancelCmdF(s.client, &cobra.Command{}, []string{job.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{job}, printer.GetLines())
+	})
+
+	s.Run("Should fail to cancel a finished job", func() {
+		job := &model.Job{Id: "job-id", Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+
+		err := jobCancelCmdF(s.client, &cobra.Command{}, []string{job.Id})
+		s.Require().EqualError(err, "job job-id can't be canceled as its status is success")
+	})
+
+	s.Run("Should fail if the job doesn't exist", func() {
+		s.client.
+			EXPECT().
+			GetJob("job-id").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := jobCancelCmdF(s.client, &cobra.Command{}, []string{"job-id"})
+		s.Require().EqualError(err, "failed to get job: not found")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestJobLogsCmd() {
+	s.Run("Should display the log lines of a job and its error", func() {
+		job := &model.Job{Id: "job-id", Status: model.JobStatusError, Data: map[string]string{"error": "LDAP server unreachable"}}
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("number", 100, "")
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetLogs(0, 100).
+			Return([]string{
+				testLogInfo + "\n",
+				`{"level":"error","ts":1573516748,"caller":"jobs/workers.go:10","msg":"Worker experienced an error","job_id":"job-id"}` + "\n",
+			}, &model.Response{}, nil).
+			Times(1)
+
+		currStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := jobLogsCmdF(s.client, cmd, []string{job.Id})
+		w.Close()
+		os.Stdout = currStdout
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		s.Require().NoError(err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		s.Require().Len(lines, 2)
+		s.Require().Contains(lines[0], "Worker experienced an error")
+		s.Require().Equal("Job error: LDAP server unreachable", lines[1])
+	})
+}
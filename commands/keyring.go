@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/pkg/errors"
+)
+
+// keyringService is the service the auth tokens are stored under in the
+// keyring of the operating system
+const keyringService = "mmctl"
+
+// errKeyringUnsupported is returned by the keyring of the operating
+// systems that mmctl can't store the credentials in
+var errKeyringUnsupported = errors.New("the keyring is not supported on this operating system")
+
+// secretStore stores the auth tokens of the credentials by their name
+type secretStore interface {
+	Get(name string) (string, error)
+	Set(name, secret string) error
+	Delete(name string) error
+}
+
+// credentialsKeyring is the keyring of the operating system: the macOS
+// Keychain, the Windows Credential Manager or the Secret Service through
+// libsecret. It can be replaced by the tests
+var credentialsKeyring secretStore = osKeyring{}
+
+// loadKeyringToken sets the auth token of the credentials stored in the
+// keyring
+func loadKeyringToken(credentials *Credentials) error {
+	if !credentials.Keyring || credentials.AuthToken != "" {
+		return nil
+	}
+
+	token, err := credentialsKeyring.Get(credentials.Name)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read the auth token of %q from the keyring", credentials.Name)
+	}
+	credentials.AuthToken = token
+	return nil
+}
+
+// storeKeyringTokens stores in the keyring the auth tokens of the
+// credentials that use it, and returns the list to write to the
+// credentials file, without those tokens
+func storeKeyringTokens(credentialsList *CredentialsList) (*CredentialsList, error) {
+	fileList := CredentialsList{}
+	for name, credentials := range *credentialsList {
+		if !credentials.Keyring {
+			fileList[name] = credentials
+			continue
+		}
+
+		if credentials.AuthToken != "" {
+			if err := credentialsKeyring.Set(credentials.Name, credentials.AuthToken); err != nil {
+				return nil, errors.Wrapf(err, "cannot store the auth token of %q in the keyring", credentials.Name)
+			}
+		}
+		fileCredentials := *credentials
+		fileCredentials.AuthToken = ""
+		fileList[name] = &fileCredentials
+	}
+	return &fileList, nil
+}
+
+// deleteKeyringToken removes the auth token of the credentials from the
+// keyring, if they use it
+func deleteKeyringToken(credentials *Credentials) error {
+	if !credentials.Keyring {
+		return nil
+	}
+	if err := credentialsKeyring.Delete(credentials.Name); err != nil {
+		return errors.Wrapf(err, "cannot delete the auth token of %q from the keyring", credentials.Name)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// osKeyring stores the secrets in the macOS Keychain through the
+// security command
+type osKeyring struct{}
+
+// run runs security, and fails if it reports an error, as its
+// interactive mode doesn't exit with the status of the commands it reads
+func (osKeyring) run(stdin string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("security", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return "", errors.New(message)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (k osKeyring) Get(name string) (string, error) {
+	return k.run("", "find-generic-password", "-s", keyringService, "-a", name, "-w")
+}
+
+// securityQuote quotes an argument of a command read by security -i
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// Set passes the secret to security through stdin, in the commands of
+// its interactive mode, as the arguments of a process can be read by the
+// other users of the machine. The secret is hex encoded with -X, so it
+// doesn't need quoting
+func (k osKeyring) Set(name, secret string) error {
+	command := "add-generic-password -U -s " + securityQuote(keyringService) + " -a " + securityQuote(name) + " -X " + hex.EncodeToString([]byte(secret)) + "\n"
+	_, err := k.run(command, "-i")
+	return err
+}
+
+func (k osKeyring) Delete(name string) error {
+	_, err := k.run("", "delete-generic-password", "-s", keyringService, "-a", name)
+	return err
+}
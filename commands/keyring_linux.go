@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// osKeyring stores the secrets in the Secret Service, like GNOME
+// Keyring or KWallet, through the secret-tool command of libsecret
+type osKeyring struct{}
+
+func (osKeyring) run(stdin string, args ...string) (string, error) {
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (k osKeyring) Get(name string) (string, error) {
+	secret, err := k.run("", "lookup", "service", keyringService, "account", name)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", errors.New("the secret is not in the keyring")
+	}
+	return secret, nil
+}
+
+func (k osKeyring) Set(name, secret string) error {
+	_, err := k.run(secret, "store", "--label", keyringService+" "+name, "service", keyringService, "account", name)
+	return err
+}
+
+func (k osKeyring) Delete(name string) error {
+	_, err := k.run("", "clear", "service", keyringService, "account", name)
+	return err
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package commands
+
+// osKeyring is not available on the operating systems without a
+// supported keyring
+type osKeyring struct{}
+
+func (osKeyring) Get(name string) (string, error) {
+	return "", errKeyringUnsupported
+}
+
+func (osKeyring) Set(name, secret string) error {
+	return errKeyringUnsupported
+}
+
+func (osKeyring) Delete(name string) error {
+	return errKeyringUnsupported
+}
@@ -4,8 +4,12 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/spf13/cobra"
 
 	"github.com/mattermost/mmctl/v6/client"
@@ -18,11 +22,12 @@ var LdapCmd = &cobra.Command{
 }
 
 var LdapSyncCmd = &cobra.Command{
-	Use:     "sync",
-	Short:   "Synchronize now",
-	Long:    "Synchronize all LDAP users and groups now.",
-	Example: "  ldap sync",
-	RunE:    withClient(ldapSyncCmdF),
+	Use:   "sync",
+	Short: "Synchronize now",
+	Long:  "Synchronize all LDAP users and groups now. With --wait, the command waits for the synchronization job to be created and to finish, within --timeout, and fails if it doesn't succeed. The server doesn't tell which job a sync creates, so the first LDAP sync job created after the command started is waited for.",
+	Example: `  ldap sync
+  ldap sync --wait --timeout 30m`,
+	RunE: withClient(ldapSyncCmdF),
 }
 
 var LdapIDMigrate = &cobra.Command{
@@ -41,6 +46,7 @@ var LdapIDMigrate = &cobra.Command{
 
 func init() {
 	LdapSyncCmd.Flags().Bool("include-removed-members", false, "Include members who left or were removed from a group-synced team/channel")
+	addJobWaitFlags(LdapSyncCmd)
 	LdapCmd.AddCommand(
 		LdapSyncCmd,
 		LdapIDMigrate,
@@ -48,23 +54,100 @@ func init() {
 	RootCmd.AddCommand(LdapCmd)
 }
 
+// ldapSyncJobsPerPage is the number of last LDAP sync jobs looked at to
+// find the one created by a sync
+const ldapSyncJobsPerPage = 20
+
+// latestLdapSyncJob returns the most recently created LDAP sync job, or
+// nil if there are none
+func latestLdapSyncJob(c client.Client) (*model.Job, error) {
+	jobs, _, err := c.GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	var latest *model.Job
+	for _, job := range jobs {
+		if latest == nil || job.CreateAt > latest.CreateAt {
+			latest = job
+		}
+	}
+	return latest, nil
+}
+
+// findLdapSyncJob polls the LDAP sync jobs until one newer than the
+// previous latest job appears, as the server creates the job of a sync
+// asynchronously. The oldest of the newer jobs is the closest to the
+// sync, in case other syncs were started meanwhile. It fails once the
+// deadline of the timeout is reached
+func findLdapSyncJob(c client.Client, previous *model.Job, deadline time.Time, timeout time.Duration) (*model.Job, error) {
+	for {
+		jobs, _, err := c.GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs: %w", err)
+		}
+
+		var found *model.Job
+		for _, job := range jobs {
+			if previous != nil && (job.Id == previous.Id || job.CreateAt < previous.CreateAt) {
+				continue
+			}
+			if found == nil || job.CreateAt < found.CreateAt {
+				found = job
+			}
+		}
+		if found != nil {
+			return found, nil
+		}
+
+		if time.Now().Add(jobPollInterval).After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for the LDAP sync job to be created", timeout)
+		}
+		time.Sleep(jobPollInterval)
+	}
+}
+
 func ldapSyncCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	printer.SetSingle(true)
 
 	includeRemovedMembers, _ := cmd.Flags().GetBool("include-removed-members")
+	wait, _ := cmd.Flags().GetBool("wait")
+
+	// the sync doesn't return the job it creates, so it's the first one
+	// created after the latest one before the sync
+	var previousJob *model.Job
+	if wait {
+		var err error
+		if previousJob, err = latestLdapSyncJob(c); err != nil {
+			return err
+		}
+	}
 
 	resp, err := c.SyncLdap(includeRemovedMembers)
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		printer.PrintT("Status: {{.status}}", map[string]interface{}{"status": "ok"})
-	} else {
+	if resp.StatusCode != http.StatusOK {
 		printer.PrintT("Status: {{.status}}", map[string]interface{}{"status": "error"})
+		if wait {
+			return errors.New("the LDAP sync could not be started")
+		}
+		return nil
 	}
+	printer.PrintT("Status: {{.status}}", map[string]interface{}{"status": "ok"})
 
-	return nil
+	if !wait {
+		return nil
+	}
+	// the timeout covers both finding the job of the sync and waiting
+	// for it to finish
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	deadline := time.Now().Add(timeout)
+	job, err := findLdapSyncJob(c, previousJob, deadline, timeout)
+	if err != nil {
+		return err
+	}
+	return waitForJobUntil(c, job, deadline, timeout)
 }
 
 func ldapIDMigrateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
@@ -0,0 +1,313 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/ldap"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+	"github.com/mattermost/mmctl/v6/printer/human"
+)
+
+var LdapTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test the AD/LDAP connection",
+	Long: `Test the connection of the server to the AD/LDAP server with its current settings.
+With --user, the filters of the settings are tested too against a sample user of the directory, found by its ID, username, email or login ID attribute. The directory is queried from the machine running mmctl, so the bind password has to be given with --bind-password-file unless the command is run in local mode.`,
+	Example: `  ldap test
+  ldap test --user john.doe --bind-password-file bind-password.txt`,
+	Args: cobra.NoArgs,
+	RunE: withClient(ldapTestCmdF),
+}
+
+var LdapJobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Management of the LDAP sync jobs",
+}
+
+var LdapJobStatusCmd = &cobra.Command{
+	Use:   "status [jobID]",
+	Short: "Show the result of an LDAP sync job",
+	Long: `Show the result of an LDAP sync job, the last one by default: its status, duration and errors.
+The errors are the error of the job and the errors and warnings of the server logs that mention the job, among the last lines of the logs of the server.`,
+	Example: `  ldap job status
+  ldap job status f3d68qkkm7n8xgsfxwuo498rah --number 5000`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: withClient(ldapJobStatusCmdF),
+}
+
+func init() {
+	LdapTestCmd.Flags().String("user", "", "ID, username, email or login ID of a user of the directory to test the filters against")
+	LdapTestCmd.Flags().String("bind-password-file", "", "File containing the password of the AD/LDAP bind user, used with --user")
+
+	LdapJobStatusCmd.Flags().IntP("number", "n", 1000, "Number of server log lines to search the errors of the job in")
+
+	LdapJobCmd.AddCommand(
+		LdapJobStatusCmd,
+	)
+	LdapCmd.AddCommand(
+		LdapTestCmd,
+		LdapJobCmd,
+	)
+}
+
+// ldapUserTest is the result of testing the filters of the AD/LDAP
+// settings against a user of the directory. The guest and admin
+// filters are only tested when they are set
+type ldapUserTest struct {
+	Login       string `json:"login"`
+	Found       bool   `json:"found"`
+	DN          string `json:"dn,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Email       string `json:"email,omitempty"`
+	UserFilter  bool   `json:"user_filter"`
+	GuestFilter *bool  `json:"guest_filter,omitempty"`
+	AdminFilter *bool  `json:"admin_filter,omitempty"`
+}
+
+func (d *ldapConnDirectory) testUser(login string) (*ldapUserTest, error) {
+	settings := d.settings
+	escapedLogin := ldap.EscapeFilter(login)
+	filter := ""
+	for _, attribute := range []string{*settings.IdAttribute, *settings.UsernameAttribute, *settings.EmailAttribute, *settings.LoginIdAttribute} {
+		if attribute != "" {
+			filter += "(" + attribute + "=" + escapedLogin + ")"
+		}
+	}
+
+	attributes := []string{*settings.IdAttribute, *settings.UsernameAttribute, *settings.EmailAttribute}
+	result, err := d.conn.Search(ldap.NewSearchRequest(
+		*settings.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(|"+filter+")",
+		attributes,
+		nil,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search the AD/LDAP user")
+	}
+
+	test := &ldapUserTest{Login: login}
+	if len(result.Entries) == 0 {
+		return test, nil
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("%d users of the directory match %q", len(result.Entries), login)
+	}
+
+	entry := result.Entries[0]
+	test.Found = true
+	test.DN = entry.DN
+	test.ID = entry.GetAttributeValue(*settings.IdAttribute)
+	test.Username = entry.GetAttributeValue(*settings.UsernameAttribute)
+	test.Email = entry.GetAttributeValue(*settings.EmailAttribute)
+
+	if test.UserFilter, err = d.matches(entry.DN, ldapUserFilter(settings)); err != nil {
+		return nil, errors.Wrap(err, "failed to test the user filter")
+	}
+	if guestFilter := ldapFilter(*settings.GuestFilter); guestFilter != "" {
+		matches, matchErr := d.matches(entry.DN, guestFilter)
+		if matchErr != nil {
+			return nil, errors.Wrap(matchErr, "failed to test the guest filter")
+		}
+		test.GuestFilter = model.NewBool(matches)
+	}
+	if adminFilter := ldapFilter(*settings.AdminFilter); *settings.EnableAdminFilter && adminFilter != "" {
+		matches, matchErr := d.matches(entry.DN, adminFilter)
+		if matchErr != nil {
+			return nil, errors.Wrap(matchErr, "failed to test the admin filter")
+		}
+		test.AdminFilter = model.NewBool(matches)
+	}
+	return test, nil
+}
+
+// matches tells if the entry with the DN matches the filter
+func (d *ldapConnDirectory) matches(dn, filter string) (bool, error) {
+	result, err := d.conn.Search(ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		filter,
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return false, err
+	}
+	return len(result.Entries) > 0, nil
+}
+
+func ldapTestCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if _, err := c.TestLdap(); err != nil {
+		return errors.Wrap(err, "AD/LDAP connection test failed")
+	}
+	printer.Print("AD/LDAP connection test succeeded")
+
+	login, _ := cmd.Flags().GetString("user")
+	if login == "" {
+		return nil
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+	settings := &config.LdapSettings
+	bindPassword, err := getLDAPBindPassword(cmd, settings)
+	if err != nil {
+		return err
+	}
+
+	directory, err := newLDAPDirectory(settings, bindPassword)
+	if err != nil {
+		return err
+	}
+	defer directory.close()
+
+	test, err := directory.testUser(login)
+	if err != nil {
+		return err
+	}
+	if !test.Found {
+		return fmt.Errorf("user %q not found in the directory", login)
+	}
+
+	printer.PrintT(`User {{.Login}} found as {{.DN}}
+  ID: {{.ID}}
+  Username: {{.Username}}
+  Email: {{.Email}}
+  User filter: {{.UserFilter}}{{if .GuestFilter}}
+  Guest filter: {{.GuestFilter}}{{end}}{{if .AdminFilter}}
+  Admin filter: {{.AdminFilter}}{{end}}`, test)
+
+	if !test.UserFilter {
+		return fmt.Errorf("user %q doesn't match the user filter, so it won't be synchronized", login)
+	}
+	return nil
+}
+
+// ldapSyncJobStatus is the result of an LDAP sync job, with the errors
+// of the job and of the server logs
+type ldapSyncJobStatus struct {
+	*model.Job
+	Duration string           `json:"duration,omitempty"`
+	Errors   []*ldapSyncError `json:"errors,omitempty"`
+}
+
+type ldapSyncError struct {
+	Time    string `json:"time,omitempty"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// getLdapSyncJob returns the LDAP sync job of the ID, or the last one
+func getLdapSyncJob(c client.Client, args []string) (*model.Job, error) {
+	if len(args) == 0 {
+		jobs, _, err := c.GetJobsByType(model.JobTypeLdapSync, 0, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs: %w", err)
+		}
+		if len(jobs) == 0 {
+			return nil, errors.New("no LDAP sync job found")
+		}
+		return jobs[0], nil
+	}
+
+	job, _, err := c.GetJob(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job.Type != model.JobTypeLdapSync {
+		return nil, fmt.Errorf("job %s is not an LDAP sync job", job.Id)
+	}
+	return job, nil
+}
+
+// ldapSyncLogErrors returns the errors and warnings of the log lines
+// that mention the job
+func ldapSyncLogErrors(logLines []string, jobID string) []*ldapSyncError {
+	syncErrors := []*ldapSyncError{}
+	for _, line := range logLines {
+		if !strings.Contains(line, jobID) {
+			continue
+		}
+		entry := human.ParseLogMessage(line)
+		if entry.Level != "error" && entry.Level != "warn" {
+			continue
+		}
+		syncError := &ldapSyncError{Level: entry.Level, Message: entry.Message}
+		if !entry.Time.IsZero() {
+			syncError.Time = entry.Time.Format(time.RFC3339)
+		}
+		for _, field := range entry.Fields {
+			switch field.Key {
+			case "error", "err":
+				syncError.Detail = fmt.Sprint(field.Interface)
+			case "timestamp":
+				if syncError.Time == "" {
+					syncError.Time = fmt.Sprint(field.Interface)
+				}
+			}
+		}
+		syncErrors = append(syncErrors, syncError)
+	}
+	return syncErrors
+}
+
+func ldapJobStatusCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	job, err := getLdapSyncJob(c, args)
+	if err != nil {
+		return err
+	}
+
+	status := &ldapSyncJobStatus{Job: job, Errors: []*ldapSyncError{}}
+	if job.StartAt > 0 && job.LastActivityAt > job.StartAt && job.Status != model.JobStatusInProgress && job.Status != model.JobStatusPending {
+		status.Duration = (time.Duration(job.LastActivityAt-job.StartAt) * time.Millisecond).String()
+	}
+	if jobError := job.Data["error"]; jobError != "" {
+		// the server stores the error as "where: message, detail", which
+		// can't be split reliably as the message can contain commas, and
+		// leaves a trailing separator when the error has no detail
+		status.Errors = append(status.Errors, &ldapSyncError{Level: "error", Message: strings.TrimSuffix(jobError, ", ")})
+	}
+
+	number, _ := cmd.Flags().GetInt("number")
+	logLines, _, err := c.GetLogs(0, number)
+	if err != nil {
+		printer.PrintWarning(fmt.Sprintf("Unable to retrieve the logs of the server: %s", err))
+	} else {
+		status.Errors = append(status.Errors, ldapSyncLogErrors(logLines, job.Id)...)
+	}
+
+	printer.PrintT(`LDAP sync job {{.Id}}
+  Status: {{.Status}}
+  Created: {{timestamp .CreateAt}}{{if .StartAt}}
+  Started: {{timestamp .StartAt}}{{end}}{{if .Duration}}
+  Duration: {{.Duration}}{{end}}
+  Errors: {{len .Errors}}{{range .Errors}}
+    {{if .Time}}{{.Time}} {{end}}{{.Level}}: {{.Message}}{{if .Detail}} ({{.Detail}}){{end}}{{end}}`, status)
+
+	if job.Status == model.JobStatusError {
+		return fmt.Errorf("the LDAP sync job %s failed", job.Id)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestLdapTestCmd() {
+	newCmd := func(user string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("user", user, "")
+		cmd.Flags().String("bind-password-file", "", "")
+		return cmd
+	}
+
+	ldapConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.LdapSettings.BindPassword = model.NewString("bind-secret")
+		return config
+	}
+
+	s.Run("Should test the connection", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			TestLdap().
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := ldapTestCmdF(s.client, newCmd(""), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"AD/LDAP connection test succeeded"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the connection test fails", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			TestLdap().
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := ldapTestCmdF(s.client, newCmd("john.doe"), []string{})
+		s.Require().EqualError(err, "AD/LDAP connection test failed: mock error")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should test the filters against a user", func() {
+		printer.Clean()
+		originalNewDirectory := newLDAPDirectory
+		defer func() { newLDAPDirectory = originalNewDirectory }()
+		var bindPassword string
+		newLDAPDirectory = func(_ *model.LdapSettings, password string) (ldapDirectory, error) {
+			bindPassword = password
+			return staticLDAPDirectory{"john.doe": true}, nil
+		}
+
+		s.client.
+			EXPECT().
+			TestLdap().
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(ldapConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := ldapTestCmdF(s.client, newCmd("john.doe"), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal("bind-secret", bindPassword)
+		s.Require().Equal([]interface{}{
+			"AD/LDAP connection test succeeded",
+			&ldapUserTest{Login: "john.doe", Found: true, UserFilter: true},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the user isn't in the directory", func() {
+		printer.Clean()
+		originalNewDirectory := newLDAPDirectory
+		defer func() { newLDAPDirectory = originalNewDirectory }()
+		newLDAPDirectory = func(_ *model.LdapSettings, _ string) (ldapDirectory, error) {
+			return staticLDAPDirectory{}, nil
+		}
+
+		s.client.
+			EXPECT().
+			TestLdap().
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(ldapConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := ldapTestCmdF(s.client, newCmd("jane.doe"), []string{})
+		s.Require().EqualError(err, `user "jane.doe" not found in the directory`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestLdapJobStatusCmd() {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("number", 1000, "")
+		return cmd
+	}
+
+	s.Run("Should show the errors of the last sync job", func() {
+		printer.Clean()
+		job := &model.Job{
+			Id:             model.NewId(),
+			Type:           model.JobTypeLdapSync,
+			Status:         model.JobStatusError,
+			StartAt:        1000,
+			LastActivityAt: 91000,
+			Data:           model.StringMap{"error": "LdapSyncJob.Run: Unable to sync, LDAP Result Code 32"},
+		}
+		logLines := []string{
+			`{"timestamp":"2022-07-21 10:00:00.000 Z","level":"info","msg":"Worker: Job is complete","job_id":"` + job.Id + `"}`,
+			`{"timestamp":"2022-07-21 10:00:01.000 Z","level":"error","msg":"Unable to update the user","job_id":"` + job.Id + `","error":"duplicate email"}`,
+			`{"timestamp":"2022-07-21 10:00:02.000 Z","level":"error","msg":"Another job failed","job_id":"other"}`,
+		}
+
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeLdapSync, 0, 1).
+			Return([]*model.Job{job}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetLogs(0, 1000).
+			Return(logLines, &model.Response{}, nil).
+			Times(1)
+
+		err := ldapJobStatusCmdF(s.client, newCmd(), []string{})
+		s.Require().EqualError(err, "the LDAP sync job "+job.Id+" failed")
+		s.Require().Equal([]interface{}{&ldapSyncJobStatus{
+			Job:      job,
+			Duration: "1m30s",
+			Errors: []*ldapSyncError{
+				{Level: "error", Message: "LdapSyncJob.Run: Unable to sync, LDAP Result Code 32"},
+				{Time: "2022-07-21 10:00:01.000 Z", Level: "error", Message: "Unable to update the user", Detail: "duplicate email"},
+			},
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should show the job error without the separator of a missing detail", func() {
+		printer.Clean()
+		job := &model.Job{
+			Id:     model.NewId(),
+			Type:   model.JobTypeLdapSync,
+			Status: model.JobStatusError,
+			Data:   model.StringMap{"error": "LdapSyncJob.Run: Unable to sync, "},
+		}
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetLogs(0, 1000).
+			Return([]string{}, &model.Response{}, nil).
+			Times(1)
+
+		err := ldapJobStatusCmdF(s.client, newCmd(), []string{job.Id})
+		s.Require().EqualError(err, "the LDAP sync job "+job.Id+" failed")
+		s.Require().Equal([]interface{}{&ldapSyncJobStatus{
+			Job:    job,
+			Errors: []*ldapSyncError{{Level: "error", Message: "LdapSyncJob.Run: Unable to sync"}},
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should show a successful job without errors", func() {
+		printer.Clean()
+		job := &model.Job{Id: model.NewId(), Type: model.JobTypeLdapSync, Status: model.JobStatusSuccess}
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetLogs(0, 1000).
+			Return([]string{}, &model.Response{}, nil).
+			Times(1)
+
+		err := ldapJobStatusCmdF(s.client, newCmd(), []string{job.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&ldapSyncJobStatus{Job: job, Errors: []*ldapSyncError{}}}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the job is not an LDAP sync job", func() {
+		job := &model.Job{Id: model.NewId(), Type: model.JobTypeDataRetention}
+
+		s.client.
+			EXPECT().
+			GetJob(job.Id).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+
+		err := ldapJobStatusCmdF(s.client, newCmd(), []string{job.Id})
+		s.Require().EqualError(err, "job "+job.Id+" is not an LDAP sync job")
+	})
+}
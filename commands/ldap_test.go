@@ -5,7 +5,9 @@ package commands
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/pkg/errors"
 
@@ -80,6 +82,145 @@ func (s *MmctlUnitTestSuite) TestLdapSyncCmd() {
 		err := ldapSyncCmdF(s.client, cmd, []string{})
 		s.Require().Nil(err)
 	})
+
+	s.Run("Sync and wait for the job to finish", func() {
+		printer.Clean()
+		originalInterval := jobPollInterval
+		defer func() { jobPollInterval = originalInterval }()
+		jobPollInterval = 0
+
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+
+		previousJob := &model.Job{Id: "previous-job", Type: model.JobTypeLdapSync, Status: model.JobStatusSuccess, CreateAt: 100}
+		syncJob := &model.Job{Id: "sync-job", Type: model.JobTypeLdapSync, Status: model.JobStatusPending, CreateAt: 200}
+		otherJob := &model.Job{Id: "other-job", Type: model.JobTypeLdapSync, Status: model.JobStatusPending, CreateAt: 300}
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+				Return([]*model.Job{previousJob}, &model.Response{}, nil),
+			s.client.
+				EXPECT().
+				SyncLdap(false).
+				Return(&model.Response{StatusCode: http.StatusOK}, nil),
+			s.client.
+				EXPECT().
+				GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+				Return([]*model.Job{previousJob}, &model.Response{}, nil),
+			s.client.
+				EXPECT().
+				GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+				Return([]*model.Job{otherJob, syncJob, previousJob}, &model.Response{}, nil),
+			s.client.
+				EXPECT().
+				GetJob(syncJob.Id).
+				Return(&model.Job{Id: syncJob.Id, Status: model.JobStatusInProgress}, &model.Response{}, nil),
+			s.client.
+				EXPECT().
+				GetJob(syncJob.Id).
+				Return(&model.Job{Id: syncJob.Id, Status: model.JobStatusSuccess}, &model.Response{}, nil),
+		)
+
+		err := ldapSyncCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(model.JobStatusSuccess, printer.GetLines()[1].(*model.Job).Status)
+	})
+
+	s.Run("Should not wait if the sync could not be started", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+			Return([]*model.Job{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			SyncLdap(false).
+			Return(&model.Response{StatusCode: http.StatusAccepted}, nil).
+			Times(1)
+
+		err := ldapSyncCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the LDAP sync could not be started")
+	})
+
+	s.Run("Should time out if the sync job is not created", func() {
+		printer.Clean()
+		originalInterval := jobPollInterval
+		defer func() { jobPollInterval = originalInterval }()
+		jobPollInterval = time.Millisecond
+
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+		_ = cmd.Flags().Set("timeout", "1ms")
+
+		previousJob := &model.Job{Id: "previous-job", Type: model.JobTypeLdapSync, CreateAt: 100}
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+			Return([]*model.Job{previousJob}, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			SyncLdap(false).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := ldapSyncCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "timed out after 1ms waiting for the LDAP sync job to be created")
+	})
+
+	s.Run("Should share the timeout between finding the job and waiting for it", func() {
+		printer.Clean()
+		originalInterval := jobPollInterval
+		defer func() { jobPollInterval = originalInterval }()
+		jobPollInterval = time.Millisecond
+
+		cmd := &cobra.Command{}
+		addJobWaitFlags(cmd)
+		_ = cmd.Flags().Set("wait", "true")
+		_ = cmd.Flags().Set("timeout", "20ms")
+
+		previousJob := &model.Job{Id: "previous-job", Type: model.JobTypeLdapSync, CreateAt: 100}
+		syncJob := &model.Job{Id: "sync-job", Type: model.JobTypeLdapSync, CreateAt: 200, Status: model.JobStatusPending}
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+				Return([]*model.Job{previousJob}, &model.Response{}, nil).
+				Times(2),
+			s.client.
+				EXPECT().
+				GetJobsByType(model.JobTypeLdapSync, 0, ldapSyncJobsPerPage).
+				DoAndReturn(func(string, int, int) ([]*model.Job, *model.Response, error) {
+					time.Sleep(30 * time.Millisecond)
+					return []*model.Job{syncJob, previousJob}, &model.Response{}, nil
+				}).
+				Times(1),
+		)
+		s.client.
+			EXPECT().
+			SyncLdap(false).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetJob(syncJob.Id).
+			Return(syncJob, &model.Response{}, nil).
+			Times(1)
+
+		err := ldapSyncCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "timed out after 20ms waiting for job sync-job to finish, its status is pending")
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestLdapMigrateID() {
@@ -4,8 +4,11 @@
 package commands
 
 import (
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"path/filepath"
 
 	"github.com/mattermost/mmctl/v6/client"
 	"github.com/mattermost/mmctl/v6/printer"
@@ -43,6 +46,8 @@ var RemoveLicenseCmd = &cobra.Command{
 }
 
 func init() {
+	UploadLicenseCmd.Flags().String("expect-sha256", "", "Fail without uploading if the SHA-256 digest of the license file doesn't match.")
+
 	LicenseCmd.AddCommand(UploadLicenseCmd)
 	LicenseCmd.AddCommand(RemoveLicenseCmd)
 	LicenseCmd.AddCommand(UploadLicenseStringCmd)
@@ -75,11 +80,21 @@ func uploadLicenseCmdF(c client.Client, cmd *cobra.Command, args []string) error
 		return err
 	}
 
-	if _, err := c.UploadLicenseFile(fileBytes); err != nil {
+	expectedSHA256, _ := cmd.Flags().GetString("expect-sha256")
+	digest := fmt.Sprintf("%x", sha256.Sum256(fileBytes))
+	if err := verifySHA256(args[0], digest, expectedSHA256); err != nil {
+		return err
+	}
+
+	if err := withUploadProgress(filepath.Base(args[0]), 0, -1, func() error {
+		_, err := c.UploadLicenseFile(fileBytes)
+		return err
+	}); err != nil {
 		return err
 	}
 
 	printer.Print("Uploaded license file")
+	printSHA256(args[0], digest)
 
 	return nil
 }
@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -80,6 +81,37 @@ func (s *MmctlUnitTestSuite) TestUploadLicenseCmdF() {
 		s.Require().Nil(err)
 	})
 
+	s.Run("Upload license with the expected digest", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("expect-sha256", "4681e217f86e36b344bbc72836320de203e1e665dc21278e1ea9c7fd1b18d1bd", "")
+
+		s.client.
+			EXPECT().
+			UploadLicenseFile(mockLicenseFile).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := uploadLicenseCmdF(s.client, cmd, []string{tmpFile.Name()})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(printer.GetLines()[0], "Uploaded license file")
+	})
+
+	s.Run("Fail to upload license with an unexpected digest", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("expect-sha256", "0000", "")
+
+		s.client.
+			EXPECT().
+			UploadLicenseFile(mockLicenseFile).
+			Times(0)
+
+		err := uploadLicenseCmdF(s.client, cmd, []string{tmpFile.Name()})
+		s.Require().EqualError(err, fmt.Sprintf("SHA-256 mismatch for %q: expected 0000, got 4681e217f86e36b344bbc72836320de203e1e665dc21278e1ea9c7fd1b18d1bd", tmpFile.Name()))
+	})
+
 	s.Run("Fail to upload license if file not found", func() {
 		printer.Clean()
 		path := "/path/to/nonexistentfile"
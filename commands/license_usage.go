@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var LicenseUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Reports on the usage of the license",
+}
+
+var LicenseUsageForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Forecast when the seats of the license will be exceeded",
+	Long: `Project when the active users will exceed the seats of the license, from the average monthly growth of the active users in the last months, and show the growth of each month for budgeting.
+The active users are the ones counted by the analytics of the server. Their history is rebuilt from the creation and deactivation times of the users, so the users that were deactivated and then activated again count as active since their creation, and bots are not counted.`,
+	Example: `  license usage forecast
+  license usage forecast --months 12`,
+	Args: cobra.NoArgs,
+	RunE: withClient(licenseUsageForecastCmdF),
+}
+
+func init() {
+	LicenseUsageForecastCmd.Flags().Int("months", 6, "Number of past months the growth is computed from")
+
+	LicenseUsageCmd.AddCommand(LicenseUsageForecastCmd)
+	LicenseCmd.AddCommand(LicenseUsageCmd)
+}
+
+const licenseUsageMonthLayout = "2006-01"
+
+// averageDaysPerMonth is used to turn the time covered by the months
+// into a number of months, and the months left before the seats are
+// exceeded into a date
+const averageDaysPerMonth = 365.25 / 12
+
+type licenseUsageMonth struct {
+	Month         string `json:"month"`
+	ActiveUsers   int64  `json:"active_users"`
+	Activations   int    `json:"activations"`
+	Deactivations int    `json:"deactivations"`
+	Growth        int64  `json:"growth"`
+}
+
+type licenseUsageForecast struct {
+	Seats         int64                `json:"seats"`
+	ActiveUsers   int64                `json:"active_users"`
+	MonthlyGrowth float64              `json:"monthly_growth"`
+	Exceeded      bool                 `json:"exceeded"`
+	ExceededAt    string               `json:"exceeded_at,omitempty"`
+	Months        []*licenseUsageMonth `json:"months"`
+}
+
+// buildLicenseUsageForecast rebuilds the active users at the end of
+// each of the last months, and projects when the seats are exceeded if
+// the average growth of these months goes on
+func buildLicenseUsageForecast(users []*model.User, seats, activeUsers int64, months int, now time.Time) *licenseUsageForecast {
+	now = now.UTC()
+	forecast := &licenseUsageForecast{Seats: seats, ActiveUsers: activeUsers, Months: []*licenseUsageMonth{}}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -months+1, 0)
+	activeAt := func(t time.Time) int64 {
+		millis := model.GetMillisForTime(t)
+		var count int64
+		for _, user := range users {
+			if user.CreateAt < millis && (user.DeleteAt == 0 || user.DeleteAt >= millis) {
+				count++
+			}
+		}
+		return count
+	}
+
+	startActive := activeAt(monthStart)
+	previous := startActive
+	for i := 0; i < months; i++ {
+		start := monthStart.AddDate(0, i, 0)
+		end := start.AddDate(0, 1, 0)
+		if end.After(now) {
+			end = now
+		}
+		month := &licenseUsageMonth{Month: start.Format(licenseUsageMonthLayout), ActiveUsers: activeAt(end)}
+		startMillis, endMillis := model.GetMillisForTime(start), model.GetMillisForTime(end)
+		for _, user := range users {
+			if user.CreateAt >= startMillis && user.CreateAt < endMillis {
+				month.Activations++
+			}
+			if user.DeleteAt >= startMillis && user.DeleteAt < endMillis {
+				month.Deactivations++
+			}
+		}
+		month.Growth = month.ActiveUsers - previous
+		previous = month.ActiveUsers
+		forecast.Months = append(forecast.Months, month)
+	}
+
+	// the current month is only partly elapsed, so the growth is
+	// averaged over the time actually covered rather than whole months
+	elapsedMonths := now.Sub(monthStart).Hours() / 24 / averageDaysPerMonth
+	if elapsedMonths > 0 {
+		forecast.MonthlyGrowth = math.Round(float64(previous-startActive)/elapsedMonths*10) / 10
+	}
+
+	switch {
+	case seats <= 0:
+	case activeUsers > seats:
+		forecast.Exceeded = true
+	case forecast.MonthlyGrowth > 0:
+		monthsLeft := float64(seats-activeUsers) / forecast.MonthlyGrowth
+		exceededAt := now.Add(time.Duration(monthsLeft * averageDaysPerMonth * 24 * float64(time.Hour)))
+		forecast.ExceededAt = exceededAt.Format("2006-01-02")
+	}
+	return forecast
+}
+
+func licenseUsageForecastCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	months, _ := cmd.Flags().GetInt("months")
+	if months < 1 {
+		return errors.New("--months must be at least 1")
+	}
+
+	license, _, err := c.GetOldClientLicense("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the license")
+	}
+	if license["IsLicensed"] != "true" {
+		return errors.New("the server has no license")
+	}
+	seats, err := strconv.ParseInt(license["Users"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("the license has no seats: %q", license["Users"])
+	}
+
+	analytics, _, err := c.GetAnalyticsOld("standard", "")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the analytics")
+	}
+	activeUsers := int64(-1)
+	for _, row := range analytics {
+		if row.Name == "unique_user_count" {
+			activeUsers = int64(row.Value)
+		}
+	}
+	if activeUsers < 0 {
+		return errors.New("the analytics have no count of active users")
+	}
+
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		pageUsers, _, usersErr := c.GetUsers(page, APILimitMaximum, "")
+		if usersErr != nil {
+			return errors.Wrap(usersErr, "failed to fetch the users")
+		}
+		for _, user := range pageUsers {
+			if !user.IsBot {
+				users = append(users, user)
+			}
+		}
+		if len(pageUsers) < APILimitMaximum {
+			break
+		}
+	}
+
+	forecast := buildLicenseUsageForecast(users, seats, activeUsers, months, time.Now())
+	printer.PrintT(`Active users: {{.ActiveUsers}} of {{.Seats}} seats
+Average monthly growth: {{.MonthlyGrowth}} users
+{{if .Exceeded}}The seats are already exceeded{{else if .ExceededAt}}The seats will be exceeded around {{.ExceededAt}}{{else}}The seats are not projected to be exceeded{{end}}
+Month    Active  Activations  Deactivations  Growth{{range .Months}}
+{{printf "%-8s %6d %12d %14d %7d" .Month .ActiveUsers .Activations .Deactivations .Growth}}{{end}}`, forecast)
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestLicenseUsageForecastCmd() {
+	newCmd := func(months int) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("months", months, "")
+		return cmd
+	}
+
+	millis := func(value string) int64 {
+		t, err := time.Parse("2006-01-02", value)
+		s.Require().NoError(err)
+		return model.GetMillisForTime(t)
+	}
+
+	s.Run("Should project the date the seats are exceeded", func() {
+		now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+		users := []*model.User{
+			{Id: model.NewId(), CreateAt: millis("2025-06-01")},
+			{Id: model.NewId(), CreateAt: millis("2025-12-20")},
+			{Id: model.NewId(), CreateAt: millis("2026-01-10")},
+			{Id: model.NewId(), CreateAt: millis("2026-01-20"), DeleteAt: millis("2026-02-05")},
+			{Id: model.NewId(), CreateAt: millis("2026-02-10")},
+			{Id: model.NewId(), CreateAt: millis("2026-03-01")},
+		}
+
+		// 3 more active users in the 2.4 months since the start of January
+		forecast := buildLicenseUsageForecast(users, 10, 5, 3, now)
+		s.Require().Equal(&licenseUsageForecast{
+			Seats:         10,
+			ActiveUsers:   5,
+			MonthlyGrowth: 1.3,
+			ExceededAt:    "2026-07-10",
+			Months: []*licenseUsageMonth{
+				{Month: "2026-01", ActiveUsers: 4, Activations: 2, Growth: 2},
+				{Month: "2026-02", ActiveUsers: 4, Activations: 1, Deactivations: 1, Growth: 0},
+				{Month: "2026-03", ActiveUsers: 5, Activations: 1, Growth: 1},
+			},
+		}, forecast)
+	})
+
+	s.Run("Should report the seats already exceeded", func() {
+		forecast := buildLicenseUsageForecast([]*model.User{}, 10, 12, 1, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+		s.Require().True(forecast.Exceeded)
+		s.Require().Empty(forecast.ExceededAt)
+	})
+
+	s.Run("Should print the forecast of the server", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetOldClientLicense("").
+			Return(map[string]string{"IsLicensed": "true", "Users": "100"}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetAnalyticsOld("standard", "").
+			Return(model.AnalyticsRows{{Name: "unique_user_count", Value: 2}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{
+				{Id: model.NewId(), CreateAt: model.GetMillis() - 1000},
+				{Id: model.NewId(), CreateAt: model.GetMillis() - 1000, IsBot: true},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := licenseUsageForecastCmdF(s.client, newCmd(1), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		forecast := printer.GetLines()[0].(*licenseUsageForecast)
+		s.Require().Equal(int64(100), forecast.Seats)
+		s.Require().Equal(int64(2), forecast.ActiveUsers)
+		s.Require().Len(forecast.Months, 1)
+		s.Require().Equal(1, forecast.Months[0].Activations)
+	})
+
+	s.Run("Should fail if the server has no license", func() {
+		s.client.
+			EXPECT().
+			GetOldClientLicense("").
+			Return(map[string]string{"IsLicensed": "false"}, &model.Response{}, nil).
+			Times(1)
+
+		err := licenseUsageForecastCmdF(s.client, newCmd(6), []string{})
+		s.Require().EqualError(err, "the server has no license")
+	})
+}
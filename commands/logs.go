@@ -31,19 +31,18 @@ func init() {
 	RootCmd.AddCommand(LogsCmd)
 }
 
-func logsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+// checkLogsFormat fails if an output format is requested, as the logs
+// are printed in the format of the server
+func checkLogsFormat(cmd *cobra.Command) error {
 	if cmd.Flags().Changed("format") || cmd.Flags().Changed("json") {
 		return fmt.Errorf("the %q and %q flags cannot be used with this command", "--format", "--json")
 	} else if viper.GetString("format") == printer.FormatJSON {
 		return fmt.Errorf("json formatting cannot be applied on this command. Please check the value of %q", "MMCTL_FORMAT")
 	}
+	return nil
+}
 
-	number, _ := cmd.Flags().GetInt("number")
-	logLines, _, err := c.GetLogs(0, number)
-	if err != nil {
-		return errors.New("Unable to retrieve logs. Error: " + err.Error())
-	}
-
+func printHumanLogs(cmd *cobra.Command, logLines []string) {
 	reader := bytes.NewReader([]byte(strings.Join(logLines, "")))
 
 	var writer human.LogWriter
@@ -53,6 +52,20 @@ func logsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		writer = human.NewSimpleWriter(os.Stdout)
 	}
 	human.ProcessLogs(reader, writer)
+}
+
+func logsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if err := checkLogsFormat(cmd); err != nil {
+		return err
+	}
+
+	number, _ := cmd.Flags().GetInt("number")
+	logLines, _, err := c.GetLogs(0, number)
+	if err != nil {
+		return errors.New("Unable to retrieve logs. Error: " + err.Error())
+	}
+
+	printHumanLogs(cmd, logLines)
 
 	return nil
 }
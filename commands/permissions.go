@@ -76,17 +76,7 @@ func addPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) erro
 		return err
 	}
 
-	newPermissions := role.Permissions
-
-	for _, permissionID := range args[1:] {
-		newPermissions = append(newPermissions, permissionID)
-
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			for _, ancillaryPermission := range ancillaryPermissions {
-				newPermissions = append(newPermissions, ancillaryPermission.Id)
-			}
-		}
-	}
+	newPermissions := addRolePermissions(role.Permissions, args[1:])
 
 	patchRole := model.RolePatch{
 		Permissions: &newPermissions,
@@ -105,27 +95,7 @@ func removePermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) e
 		return err
 	}
 
-	newPermissionSet := role.Permissions
-	for _, permissionID := range args[1:] {
-		newPermissionSet = removeFromStringSlice(newPermissionSet, permissionID)
-	}
-
-	var ancillaryPermissionsStillUsed []*model.Permission
-	for _, permissionID := range newPermissionSet {
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			ancillaryPermissionsStillUsed = append(ancillaryPermissionsStillUsed, ancillaryPermissions...)
-		}
-	}
-
-	for _, permissionID := range args[1:] {
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			for _, permission := range ancillaryPermissions {
-				if !permissionsSliceIncludes(ancillaryPermissionsStillUsed, permission) {
-					newPermissionSet = removeFromStringSlice(newPermissionSet, permission.Id)
-				}
-			}
-		}
-	}
+	newPermissionSet := removeRolePermissions(role.Permissions, args[1:])
 
 	patchRole := model.RolePatch{
 		Permissions: &newPermissionSet,
@@ -163,6 +133,50 @@ func resetPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) er
 	return nil
 }
 
+// addRolePermissions adds the permissions to the permissions of a role,
+// along with the ancillary permissions of the sysconsole ones
+func addRolePermissions(permissions []string, permissionIDs []string) []string {
+	newPermissions := permissions
+	for _, permissionID := range permissionIDs {
+		newPermissions = append(newPermissions, permissionID)
+
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			for _, ancillaryPermission := range ancillaryPermissions {
+				newPermissions = append(newPermissions, ancillaryPermission.Id)
+			}
+		}
+	}
+	return newPermissions
+}
+
+// removeRolePermissions removes the permissions from the permissions of
+// a role, along with the ancillary permissions of the sysconsole ones
+// that no remaining permission needs
+func removeRolePermissions(permissions []string, permissionIDs []string) []string {
+	newPermissionSet := permissions
+	for _, permissionID := range permissionIDs {
+		newPermissionSet = removeFromStringSlice(newPermissionSet, permissionID)
+	}
+
+	var ancillaryPermissionsStillUsed []*model.Permission
+	for _, permissionID := range newPermissionSet {
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			ancillaryPermissionsStillUsed = append(ancillaryPermissionsStillUsed, ancillaryPermissions...)
+		}
+	}
+
+	for _, permissionID := range permissionIDs {
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			for _, permission := range ancillaryPermissions {
+				if !permissionsSliceIncludes(ancillaryPermissionsStillUsed, permission) {
+					newPermissionSet = removeFromStringSlice(newPermissionSet, permission.Id)
+				}
+			}
+		}
+	}
+	return newPermissionSet
+}
+
 func removeFromStringSlice(items []string, item string) []string {
 	newPermissions := []string{}
 	for _, x := range items {
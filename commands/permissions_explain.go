@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ExplainPermissionCmd = &cobra.Command{
+	Use:   "explain <permission>",
+	Short: "List the roles that grant a permission",
+	Long: `List all the roles that grant a permission, along with the scheme of the roles that belong to a team or channel scheme, answering questions like who can delete the posts of others.
+With --count-users, the number of users that hold each system role is shown too. Team and channel roles are assigned per membership, so their users are not counted.`,
+	Example: `  permissions explain delete_others_posts
+  permissions explain manage_system --count-users`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(explainPermissionCmdF),
+}
+
+func init() {
+	ExplainPermissionCmd.Flags().Bool("count-users", false, "Count the users that hold each of the system roles")
+
+	PermissionsCmd.AddCommand(ExplainPermissionCmd)
+}
+
+type permissionGrant struct {
+	Role        string `json:"role"`
+	DisplayName string `json:"display_name"`
+	Scheme      string `json:"scheme,omitempty"`
+	SchemeID    string `json:"scheme_id,omitempty"`
+	Users       *int   `json:"users,omitempty"`
+}
+
+func isKnownPermission(permissionID string) bool {
+	for _, permission := range model.AllPermissions {
+		if permission.Id == permissionID {
+			return true
+		}
+	}
+	return false
+}
+
+// getSchemesByRole maps the names of the roles of the schemes to their
+// scheme
+func getSchemesByRole(c client.Client) (map[string]*model.Scheme, error) {
+	allSchemes, err := getAllSchemes(c)
+	if err != nil {
+		return nil, err
+	}
+
+	schemes := map[string]*model.Scheme{}
+	for _, scheme := range allSchemes {
+		for _, role := range schemeRoleNames(scheme) {
+			schemes[role] = scheme
+		}
+	}
+	return schemes, nil
+}
+
+func getAllSchemes(c client.Client) ([]*model.Scheme, error) {
+	schemes := []*model.Scheme{}
+	for page := 0; ; page++ {
+		pageSchemes, _, err := c.GetSchemes("", page, APILimitMaximum)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the schemes")
+		}
+		schemes = append(schemes, pageSchemes...)
+		if len(pageSchemes) < APILimitMaximum {
+			return schemes, nil
+		}
+	}
+}
+
+// schemeRoleNames maps the kinds of the roles of a scheme, like
+// team_admin, to the names of its roles
+func schemeRoleNames(scheme *model.Scheme) map[string]string {
+	roles := map[string]string{}
+	for kind, role := range map[string]string{
+		"team_admin":      scheme.DefaultTeamAdminRole,
+		"team_user":       scheme.DefaultTeamUserRole,
+		"team_guest":      scheme.DefaultTeamGuestRole,
+		"channel_admin":   scheme.DefaultChannelAdminRole,
+		"channel_user":    scheme.DefaultChannelUserRole,
+		"channel_guest":   scheme.DefaultChannelGuestRole,
+		"playbook_admin":  scheme.DefaultPlaybookAdminRole,
+		"playbook_member": scheme.DefaultPlaybookMemberRole,
+		"run_admin":       scheme.DefaultRunAdminRole,
+		"run_member":      scheme.DefaultRunMemberRole,
+	} {
+		if role != "" {
+			roles[kind] = role
+		}
+	}
+	return roles
+}
+
+func countUsersWithRole(c client.Client, role string) (int, error) {
+	filter := &userListFilter{role: role}
+	count := 0
+	for page := 0; ; page++ {
+		users, err := filter.getPage(c, page, APILimitMaximum)
+		if err != nil {
+			return 0, err
+		}
+		count += len(users)
+		if len(users) < APILimitMaximum {
+			return count, nil
+		}
+	}
+}
+
+func explainPermissionCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	countUsers, _ := cmd.Flags().GetBool("count-users")
+	permission := args[0]
+
+	if !isKnownPermission(permission) {
+		printer.PrintWarning(fmt.Sprintf("%q is not a permission known by this version of mmctl", permission))
+	}
+
+	roles, _, err := c.GetAllRoles()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the roles")
+	}
+	schemes, err := getSchemesByRole(c)
+	if err != nil {
+		return err
+	}
+
+	grants := []*permissionGrant{}
+	for _, role := range roles {
+		if role.DeleteAt != 0 {
+			continue
+		}
+		granted := false
+		for _, rolePermission := range role.Permissions {
+			granted = granted || rolePermission == permission
+		}
+		if !granted {
+			continue
+		}
+
+		grant := &permissionGrant{Role: role.Name, DisplayName: role.DisplayName}
+		if scheme, ok := schemes[role.Name]; ok {
+			grant.Scheme = scheme.DisplayName
+			grant.SchemeID = scheme.Id
+		} else if countUsers && strings.HasPrefix(role.Name, "system_") {
+			count, err := countUsersWithRole(c, role.Name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to count the users of role %s", role.Name)
+			}
+			grant.Users = &count
+		}
+		grants = append(grants, grant)
+	}
+
+	if len(grants) == 0 {
+		printer.Print(fmt.Sprintf("No role grants the %s permission", permission))
+		return nil
+	}
+
+	// the roles of the schemes are listed after the built-in ones
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].Scheme != grants[j].Scheme {
+			return grants[i].Scheme < grants[j].Scheme
+		}
+		return grants[i].Role < grants[j].Role
+	})
+
+	for _, grant := range grants {
+		printer.PrintT("{{.Role}}{{if .Scheme}} (scheme {{.Scheme}}){{end}}{{if .Users}}: {{.Users}} users{{end}}", grant)
+	}
+
+	return nil
+}
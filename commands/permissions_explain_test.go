@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestExplainPermissionCmd() {
+	newCmd := func(countUsers bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("count-users", countUsers, "")
+		return cmd
+	}
+
+	roles := []*model.Role{
+		{Name: "system_admin", DisplayName: "System Admin", Permissions: []string{"delete_others_posts", "manage_system"}},
+		{Name: "team_admin", DisplayName: "Team Admin", Permissions: []string{"delete_others_posts"}},
+		{Name: "channel_user", DisplayName: "Channel User", Permissions: []string{"create_post"}},
+		{Name: "scheme-team-admin", DisplayName: "Scheme Team Admin", Permissions: []string{"delete_others_posts"}},
+		{Name: "deleted_role", Permissions: []string{"delete_others_posts"}, DeleteAt: 1},
+	}
+	schemes := []*model.Scheme{
+		{Id: "scheme-id", DisplayName: "Support", DefaultTeamAdminRole: "scheme-team-admin"},
+	}
+
+	s.Run("Should list the roles granting the permission with the count of users", func() {
+		printer.Clean()
+		b, _ := json.Marshal([]*model.User{{Id: "admin-1"}, {Id: "admin-2"}})
+
+		s.client.
+			EXPECT().
+			GetAllRoles().
+			Return(roles, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return(schemes, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?page=0&per_page=200&role=system_admin", "").
+			Return(&http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil).
+			Times(1)
+
+		err := explainPermissionCmdF(s.client, newCmd(true), []string{"delete_others_posts"})
+		s.Require().NoError(err)
+		users := 2
+		s.Require().Equal([]interface{}{
+			&permissionGrant{Role: "system_admin", DisplayName: "System Admin", Users: &users},
+			&permissionGrant{Role: "team_admin", DisplayName: "Team Admin"},
+			&permissionGrant{Role: "scheme-team-admin", DisplayName: "Scheme Team Admin", Scheme: "Support", SchemeID: "scheme-id"},
+		}, printer.GetLines())
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("Should report that no role grants an unknown permission", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAllRoles().
+			Return(roles, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return(schemes, &model.Response{}, nil).
+			Times(1)
+
+		err := explainPermissionCmdF(s.client, newCmd(false), []string{"make_coffee"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"No role grants the make_coffee permission"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the roles can't be fetched", func() {
+		s.client.
+			EXPECT().
+			GetAllRoles().
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := explainPermissionCmdF(s.client, newCmd(false), []string{"delete_others_posts"})
+		s.Require().EqualError(err, "failed to fetch the roles: mock error")
+	})
+}
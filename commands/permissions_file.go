@@ -0,0 +1,306 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var PermissionsExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export the permissions of the roles and schemes to a YAML file",
+	Long: `Export the permissions of the roles of the server, and of the roles of its team and channel permission schemes, to a YAML file that can be version-controlled and imported onto other servers with "permissions import".
+The roles of the schemes are listed by their kind, like team_admin, as their names are generated by each server. The playbook and run schemes are skipped, as they can't be created through the API.`,
+	Example: `  permissions export permissions.yaml`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(permissionsExportCmdF),
+}
+
+var PermissionsImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import the permissions of the roles and schemes from a YAML file (EE Only)",
+	Long: `Import the permissions of the roles and schemes of a YAML file created by "permissions export", replacing the permissions of the roles of the server.
+The schemes are matched by their display name, and the schemes that don't exist are created along with their roles, which include the roles created with "permissions role create". The roles outside of the schemes that don't exist on the server, like the built-in roles of a newer server version, can't be created and are reported as failed. The roles that aren't in the file are left unchanged (Only works in Enterprise Edition).`,
+	Example: `  permissions import permissions.yaml --dry-run
+  permissions import permissions.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(permissionsImportCmdF),
+}
+
+func init() {
+	PermissionsImportCmd.Flags().Bool("dry-run", false, "Show the changes without applying them")
+
+	PermissionsCmd.AddCommand(
+		PermissionsExportCmd,
+		PermissionsImportCmd,
+	)
+}
+
+// permissionsFile is the YAML file of the permissions of the roles that
+// don't belong to a scheme, and of the roles of the schemes
+type permissionsFile struct {
+	Roles   []*permissionsFileRole   `yaml:"roles"`
+	Schemes []*permissionsFileScheme `yaml:"schemes"`
+}
+
+type permissionsFileRole struct {
+	Name        string   `yaml:"name"`
+	Permissions []string `yaml:"permissions"`
+}
+
+// permissionsFileScheme maps the kinds of the roles of a scheme to
+// their permissions
+type permissionsFileScheme struct {
+	DisplayName string              `yaml:"display_name"`
+	Description string              `yaml:"description,omitempty"`
+	Scope       string              `yaml:"scope"`
+	Roles       map[string][]string `yaml:"roles"`
+}
+
+// permissionsRoleChange is a change of the permissions of a role made
+// by an import
+type permissionsRoleChange struct {
+	Role    string   `json:"role"`
+	Scheme  string   `json:"scheme,omitempty"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+func loadPermissionsFile(path string) (*permissionsFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the permissions file")
+	}
+	defer f.Close()
+
+	var file permissionsFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, errors.Wrapf(err, "invalid permissions file %s", path)
+	}
+	for i, role := range file.Roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("invalid permissions file %s: role %d has no name", path, i+1)
+		}
+	}
+	for i, scheme := range file.Schemes {
+		if scheme.DisplayName == "" {
+			return nil, fmt.Errorf("invalid permissions file %s: scheme %d has no display_name", path, i+1)
+		}
+		if scheme.Scope != model.SchemeScopeTeam && scheme.Scope != model.SchemeScopeChannel {
+			return nil, fmt.Errorf("invalid permissions file %s: scheme %q has an invalid scope %q", path, scheme.DisplayName, scheme.Scope)
+		}
+	}
+	return &file, nil
+}
+
+func sortedPermissions(permissions []string) []string {
+	sorted := append([]string{}, permissions...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// permissionsDiff returns the permissions to add and to remove to get
+// from the current permissions to the wanted ones
+func permissionsDiff(current, wanted []string) (added, removed []string) {
+	currentSet := map[string]bool{}
+	for _, permission := range current {
+		currentSet[permission] = true
+	}
+	wantedSet := map[string]bool{}
+	for _, permission := range wanted {
+		wantedSet[permission] = true
+		if !currentSet[permission] {
+			added = append(added, permission)
+		}
+	}
+	for _, permission := range current {
+		if !wantedSet[permission] {
+			removed = append(removed, permission)
+		}
+	}
+	return sortedPermissions(added), sortedPermissions(removed)
+}
+
+func permissionsExportCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	roles, _, err := c.GetAllRoles()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the roles")
+	}
+	schemes, err := getAllSchemes(c)
+	if err != nil {
+		return err
+	}
+
+	rolesByName := map[string]*model.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+
+	file := &permissionsFile{Roles: []*permissionsFileRole{}, Schemes: []*permissionsFileScheme{}}
+	schemeRoles := map[string]bool{}
+	skipped := 0
+	for _, scheme := range schemes {
+		if scheme.Scope != model.SchemeScopeTeam && scheme.Scope != model.SchemeScopeChannel {
+			for _, name := range schemeRoleNames(scheme) {
+				schemeRoles[name] = true
+			}
+			skipped++
+			continue
+		}
+		fileScheme := &permissionsFileScheme{
+			DisplayName: scheme.DisplayName,
+			Description: scheme.Description,
+			Scope:       scheme.Scope,
+			Roles:       map[string][]string{},
+		}
+		for kind, name := range schemeRoleNames(scheme) {
+			schemeRoles[name] = true
+			if role, ok := rolesByName[name]; ok {
+				fileScheme.Roles[kind] = sortedPermissions(role.Permissions)
+			}
+		}
+		file.Schemes = append(file.Schemes, fileScheme)
+	}
+	for _, role := range roles {
+		if schemeRoles[role.Name] || role.DeleteAt > 0 {
+			continue
+		}
+		file.Roles = append(file.Roles, &permissionsFileRole{Name: role.Name, Permissions: sortedPermissions(role.Permissions)})
+	}
+
+	sort.Slice(file.Roles, func(i, j int) bool { return file.Roles[i].Name < file.Roles[j].Name })
+	sort.Slice(file.Schemes, func(i, j int) bool { return file.Schemes[i].DisplayName < file.Schemes[j].DisplayName })
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the permissions")
+	}
+	if err = ioutil.WriteFile(args[0], data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the permissions file")
+	}
+
+	message := fmt.Sprintf("Permissions of %d roles and %d schemes exported to %s", len(file.Roles), len(file.Schemes), args[0])
+	if skipped > 0 {
+		message += fmt.Sprintf(", %d playbook and run schemes skipped", skipped)
+	}
+	printer.Print(message)
+
+	return nil
+}
+
+// importRolePermissions replaces the permissions of a role with the
+// ones of the file, and reports the change if there is one
+func importRolePermissions(c client.Client, roleName, scheme string, permissions []string, dryRun bool) error {
+	role, r, err := c.GetRoleByName(roleName)
+	if err != nil && r != nil && r.StatusCode == http.StatusNotFound {
+		// the API has no endpoint to create roles outside of a scheme
+		return fmt.Errorf("role %s doesn't exist on the server and can't be created, as the server only creates the roles of the schemes", roleName)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get role %s", roleName)
+	}
+
+	added, removed := permissionsDiff(role.Permissions, permissions)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	if !dryRun {
+		newPermissions := sortedPermissions(permissions)
+		if _, _, err = c.PatchRole(role.Id, &model.RolePatch{Permissions: &newPermissions}); err != nil {
+			return errors.Wrapf(err, "failed to patch role %s", roleName)
+		}
+	}
+
+	printer.PrintT(`{{.Role}}{{if .Scheme}} of scheme {{.Scheme}}{{end}}: {{len .Added}} permissions added, {{len .Removed}} removed{{range .Added}}
+  + {{.}}{{end}}{{range .Removed}}
+  - {{.}}{{end}}`, &permissionsRoleChange{Role: roleName, Scheme: scheme, Added: added, Removed: removed})
+
+	return nil
+}
+
+func permissionsImportCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	file, err := loadPermissionsFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, role := range file.Roles {
+		if err = importRolePermissions(c, role.Name, "", role.Permissions, dryRun); err != nil {
+			printer.PrintError(err.Error())
+			failed++
+		}
+	}
+
+	schemes, err := getAllSchemes(c)
+	if err != nil {
+		return err
+	}
+	schemesByName := map[string]*model.Scheme{}
+	for _, scheme := range schemes {
+		schemesByName[scheme.DisplayName] = scheme
+	}
+
+	for _, fileScheme := range file.Schemes {
+		scheme, ok := schemesByName[fileScheme.DisplayName]
+		if !ok {
+			if dryRun {
+				printer.Print(fmt.Sprintf("Scheme %s would be created", fileScheme.DisplayName))
+				continue
+			}
+			scheme, _, err = c.CreateScheme(&model.Scheme{
+				DisplayName: fileScheme.DisplayName,
+				Description: fileScheme.Description,
+				Scope:       fileScheme.Scope,
+			})
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("Failed to create scheme %s: %s", fileScheme.DisplayName, err))
+				failed += len(fileScheme.Roles)
+				continue
+			}
+			printer.Print(fmt.Sprintf("Scheme %s created", fileScheme.DisplayName))
+		}
+
+		roleNames := schemeRoleNames(scheme)
+		kinds := make([]string, 0, len(fileScheme.Roles))
+		for kind := range fileScheme.Roles {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			roleName, ok := roleNames[kind]
+			if !ok {
+				printer.PrintError(fmt.Sprintf("Scheme %s has no %s role", fileScheme.DisplayName, kind))
+				failed++
+				continue
+			}
+			if err = importRolePermissions(c, roleName, fileScheme.DisplayName, fileScheme.Roles[kind], dryRun); err != nil {
+				printer.PrintError(err.Error())
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to import the permissions of %d roles", failed)
+	}
+	return nil
+}
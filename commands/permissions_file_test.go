@@ -0,0 +1,277 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPermissionsExportCmd() {
+	s.Run("Should export the roles and the roles of the schemes", func() {
+		printer.Clean()
+		dir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "permissions.yaml")
+
+		scheme := &model.Scheme{
+			Id:                      model.NewId(),
+			DisplayName:             "Announcements",
+			Scope:                   model.SchemeScopeChannel,
+			DefaultChannelAdminRole: "scheme-channel-admin",
+			DefaultChannelUserRole:  "scheme-channel-user",
+		}
+		roles := []*model.Role{
+			{Name: "system_user", Permissions: []string{"list_public_teams", "create_team"}},
+			{Name: "scheme-channel-admin", Permissions: []string{"manage_public_channel_members"}},
+			{Name: "scheme-channel-user", Permissions: []string{"read_channel"}},
+			{Name: "deleted-role", DeleteAt: 1},
+		}
+
+		s.client.
+			EXPECT().
+			GetAllRoles().
+			Return(roles, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{scheme}, &model.Response{}, nil).
+			Times(1)
+
+		err = permissionsExportCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"Permissions of 1 roles and 1 schemes exported to " + path}, printer.GetLines())
+
+		data, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		s.Require().Equal(`roles:
+    - name: system_user
+      permissions:
+        - create_team
+        - list_public_teams
+schemes:
+    - display_name: Announcements
+      scope: channel
+      roles:
+        channel_admin:
+            - manage_public_channel_members
+        channel_user:
+            - read_channel
+`, string(data))
+	})
+
+	s.Run("Should skip the playbook and run schemes so that the file can be imported back", func() {
+		printer.Clean()
+		dir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "permissions.yaml")
+
+		teamScheme := &model.Scheme{
+			Id:                  model.NewId(),
+			DisplayName:         "Partners",
+			Scope:               model.SchemeScopeTeam,
+			DefaultTeamUserRole: "scheme-team-user",
+		}
+		playbookScheme := &model.Scheme{
+			Id:                        model.NewId(),
+			DisplayName:               "Incidents",
+			Scope:                     model.SchemeScopePlaybook,
+			DefaultPlaybookMemberRole: "scheme-playbook-member",
+		}
+		teamUser := &model.Role{Id: model.NewId(), Name: "scheme-team-user", Permissions: []string{"view_team"}}
+		roles := []*model.Role{
+			teamUser,
+			{Name: "scheme-playbook-member", Permissions: []string{"playbook_public_view"}},
+		}
+
+		s.client.
+			EXPECT().
+			GetAllRoles().
+			Return(roles, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{teamScheme, playbookScheme}, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetRoleByName(teamUser.Name).
+			Return(teamUser, &model.Response{}, nil).
+			Times(1)
+
+		err = permissionsExportCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"Permissions of 0 roles and 1 schemes exported to " + path + ", 1 playbook and run schemes skipped"}, printer.GetLines())
+
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "")
+		err = permissionsImportCmdF(s.client, cmd, []string{path})
+		s.Require().NoError(err)
+		s.Require().Empty(printer.GetLines())
+		s.Require().Empty(printer.GetErrorLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestPermissionsImportCmd() {
+	writeFile := func(content string) (string, func()) {
+		dir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(dir, "permissions.yaml")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path, func() { os.RemoveAll(dir) }
+	}
+
+	newCmd := func(dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	s.Run("Should patch the roles and create the missing schemes", func() {
+		printer.Clean()
+		path, cleanup := writeFile(`roles:
+  - name: system_user
+    permissions: [create_team, list_private_teams]
+schemes:
+  - display_name: Announcements
+    scope: channel
+    roles:
+      channel_user: [read_channel]
+`)
+		defer cleanup()
+
+		systemUser := &model.Role{Id: model.NewId(), Name: "system_user", Permissions: []string{"create_team", "list_public_teams"}}
+		scheme := &model.Scheme{Id: model.NewId(), DisplayName: "Announcements", Scope: model.SchemeScopeChannel, DefaultChannelUserRole: "scheme-channel-user"}
+		channelUser := &model.Role{Id: model.NewId(), Name: "scheme-channel-user", Permissions: []string{"create_post", "read_channel"}}
+		systemUserPermissions := []string{"create_team", "list_private_teams"}
+		channelUserPermissions := []string{"read_channel"}
+
+		s.client.
+			EXPECT().
+			GetRoleByName("system_user").
+			Return(systemUser, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchRole(systemUser.Id, &model.RolePatch{Permissions: &systemUserPermissions}).
+			Return(systemUser, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateScheme(&model.Scheme{DisplayName: "Announcements", Scope: model.SchemeScopeChannel}).
+			Return(scheme, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRoleByName("scheme-channel-user").
+			Return(channelUser, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchRole(channelUser.Id, &model.RolePatch{Permissions: &channelUserPermissions}).
+			Return(channelUser, &model.Response{}, nil).
+			Times(1)
+
+		err := permissionsImportCmdF(s.client, newCmd(false), []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&permissionsRoleChange{Role: "system_user", Added: []string{"list_private_teams"}, Removed: []string{"list_public_teams"}},
+			"Scheme Announcements created",
+			&permissionsRoleChange{Role: "scheme-channel-user", Scheme: "Announcements", Added: []string{}, Removed: []string{"create_post"}},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should only show the changes in dry run", func() {
+		printer.Clean()
+		path, cleanup := writeFile(`roles:
+  - name: system_user
+    permissions: [create_team]
+`)
+		defer cleanup()
+
+		s.client.
+			EXPECT().
+			GetRoleByName("system_user").
+			Return(&model.Role{Id: model.NewId(), Name: "system_user", Permissions: []string{"create_team", "list_public_teams"}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{}, &model.Response{}, nil).
+			Times(1)
+
+		err := permissionsImportCmdF(s.client, newCmd(true), []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&permissionsRoleChange{Role: "system_user", Added: []string{}, Removed: []string{"list_public_teams"}},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should report the roles that don't exist on the server and import the others", func() {
+		printer.Clean()
+		path, cleanup := writeFile(`roles:
+  - name: custom_role
+    permissions: [create_team]
+  - name: system_user
+    permissions: [create_team]
+`)
+		defer cleanup()
+
+		systemUser := &model.Role{Id: model.NewId(), Name: "system_user", Permissions: []string{"create_team", "list_public_teams"}}
+
+		s.client.
+			EXPECT().
+			GetRoleByName("custom_role").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("role not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRoleByName("system_user").
+			Return(systemUser, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{}, &model.Response{}, nil).
+			Times(1)
+
+		err := permissionsImportCmdF(s.client, newCmd(true), []string{path})
+		s.Require().EqualError(err, "unable to import the permissions of 1 roles")
+		s.Require().Equal([]interface{}{
+			&permissionsRoleChange{Role: "system_user", Added: []string{}, Removed: []string{"list_public_teams"}},
+		}, printer.GetLines())
+		s.Require().Equal([]interface{}{
+			"role custom_role doesn't exist on the server and can't be created, as the server only creates the roles of the schemes",
+		}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fail with an invalid scope", func() {
+		path, cleanup := writeFile(`schemes:
+  - display_name: Announcements
+    scope: system
+`)
+		defer cleanup()
+
+		err := permissionsImportCmdF(s.client, newCmd(false), []string{path})
+		s.Require().EqualError(err, `invalid permissions file `+path+`: scheme "Announcements" has an invalid scope "system"`)
+	})
+}
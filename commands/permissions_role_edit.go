@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var RoleCreateCmd = &cobra.Command{
+	Use:   "create <display_name>",
+	Short: "Create the roles of a new permission scheme (EE Only)",
+	Long: `Create a new team or channel permission scheme, along with its roles, and show the names of the roles.
+The server only creates roles as part of a permission scheme, so the roles are created in sets: the admin, user and guest roles of the team and channels of a team scheme, or of the channels of a channel scheme. They start with the default permissions, and can be edited with "role patch" or "role clone" (Only works in Enterprise Edition).`,
+	Example: `  permissions role create "Read only teams" --scope team --description "Members of the teams can't post"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(roleCreateCmdF),
+}
+
+var RoleCloneCmd = &cobra.Command{
+	Use:   "clone <source_role> <target_role>",
+	Short: "Copy the permissions of a role to another (EE Only)",
+	Long:  `Replace the permissions of the target role with the permissions of the source role (Only works in Enterprise Edition).`,
+	Example: `  # Give the users of the teams of a scheme the permissions of the default team users
+  permissions role clone team_user 3wdpcq6b6pbj8yg4xpmuanjjrc`,
+	Args: cobra.ExactArgs(2),
+	RunE: withClient(roleCloneCmdF),
+}
+
+var RolePatchCmd = &cobra.Command{
+	Use:   "patch <role_name>",
+	Short: "Add and remove permissions of a role (EE Only)",
+	Long:  `Add and remove permissions of a role in a single change. The permissions are removed before the new ones are added, along with the ancillary permissions of the sysconsole ones (Only works in Enterprise Edition).`,
+	Example: `  permissions role patch system_user --add list_private_teams --remove create_team
+  permissions role patch system_manager --add sysconsole_read_user_management_channels,sysconsole_write_user_management_channels`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(rolePatchCmdF),
+}
+
+func init() {
+	RoleCreateCmd.Flags().String("scope", model.SchemeScopeTeam, "Scope of the scheme, team or channel")
+	RoleCreateCmd.Flags().String("description", "", "Description of the scheme")
+
+	RolePatchCmd.Flags().StringSlice("add", []string{}, "Permissions to add to the role")
+	RolePatchCmd.Flags().StringSlice("remove", []string{}, "Permissions to remove from the role")
+
+	RoleCmd.AddCommand(
+		RoleCreateCmd,
+		RoleCloneCmd,
+		RolePatchCmd,
+	)
+}
+
+// createdSchemeRoles are the roles of a new scheme by their kind
+type createdSchemeRoles struct {
+	Scheme   string            `json:"scheme"`
+	SchemeID string            `json:"scheme_id"`
+	Roles    map[string]string `json:"roles"`
+}
+
+func roleCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+	description, _ := cmd.Flags().GetString("description")
+	if scope != model.SchemeScopeTeam && scope != model.SchemeScopeChannel {
+		return errors.New("the scope must be team or channel")
+	}
+
+	scheme, _, err := c.CreateScheme(&model.Scheme{
+		DisplayName: args[0],
+		Description: description,
+		Scope:       scope,
+	})
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT(`Scheme {{.Scheme}} ({{.SchemeID}}) created with the roles:{{range $kind, $role := .Roles}}
+  {{$kind}}: {{$role}}{{end}}`, &createdSchemeRoles{
+		Scheme:   scheme.DisplayName,
+		SchemeID: scheme.Id,
+		Roles:    schemeRoleNames(scheme),
+	})
+
+	return nil
+}
+
+func roleCloneCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	source, _, err := c.GetRoleByName(args[0])
+	if err != nil {
+		return err
+	}
+
+	target, _, err := c.GetRoleByName(args[1])
+	if err != nil {
+		return err
+	}
+
+	permissions := append([]string{}, source.Permissions...)
+	target, _, err = c.PatchRole(target.Id, &model.RolePatch{Permissions: &permissions})
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT(prettyRole(target), nil)
+
+	return nil
+}
+
+func rolePatchCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	add, _ := cmd.Flags().GetStringSlice("add")
+	remove, _ := cmd.Flags().GetStringSlice("remove")
+	if len(add) == 0 && len(remove) == 0 {
+		return errors.New("at least one permission to add or remove is required")
+	}
+
+	role, _, err := c.GetRoleByName(args[0])
+	if err != nil {
+		return err
+	}
+
+	newPermissions := addRolePermissions(removeRolePermissions(role.Permissions, remove), add)
+	role, _, err = c.PatchRole(role.Id, &model.RolePatch{Permissions: &newPermissions})
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT(prettyRole(role), nil)
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestRoleCreateCmd() {
+	newCmd := func(scope string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("scope", scope, "")
+		cmd.Flags().String("description", "Read only", "")
+		return cmd
+	}
+
+	s.Run("Should create the roles of a channel scheme", func() {
+		printer.Clean()
+		scheme := &model.Scheme{
+			Id:                      model.NewId(),
+			DisplayName:             "Announcements",
+			Scope:                   model.SchemeScopeChannel,
+			DefaultChannelAdminRole: "channel-admin-role",
+			DefaultChannelUserRole:  "channel-user-role",
+			DefaultChannelGuestRole: "channel-guest-role",
+		}
+
+		s.client.
+			EXPECT().
+			CreateScheme(&model.Scheme{DisplayName: "Announcements", Description: "Read only", Scope: model.SchemeScopeChannel}).
+			Return(scheme, &model.Response{}, nil).
+			Times(1)
+
+		err := roleCreateCmdF(s.client, newCmd(model.SchemeScopeChannel), []string{"Announcements"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&createdSchemeRoles{
+			Scheme:   "Announcements",
+			SchemeID: scheme.Id,
+			Roles: map[string]string{
+				"channel_admin": "channel-admin-role",
+				"channel_user":  "channel-user-role",
+				"channel_guest": "channel-guest-role",
+			},
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should fail with an invalid scope", func() {
+		err := roleCreateCmdF(s.client, newCmd("system"), []string{"Announcements"})
+		s.Require().EqualError(err, "the scope must be team or channel")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestRoleCloneCmd() {
+	s.Run("Should copy the permissions of the source role", func() {
+		printer.Clean()
+		source := &model.Role{Id: model.NewId(), Name: "team_user", Permissions: []string{"create_post", "read_channel"}}
+		target := &model.Role{Id: model.NewId(), Name: "custom-team-user", Permissions: []string{"read_channel"}}
+		expectedPermissions := []string{"create_post", "read_channel"}
+
+		s.client.
+			EXPECT().
+			GetRoleByName(source.Name).
+			Return(source, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRoleByName(target.Name).
+			Return(target, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchRole(target.Id, &model.RolePatch{Permissions: &expectedPermissions}).
+			Return(&model.Role{Id: target.Id, Name: target.Name, Permissions: expectedPermissions}, &model.Response{}, nil).
+			Times(1)
+
+		err := roleCloneCmdF(s.client, &cobra.Command{}, []string{source.Name, target.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should fail if the target role doesn't exist", func() {
+		source := &model.Role{Id: model.NewId(), Name: "team_user"}
+
+		s.client.
+			EXPECT().
+			GetRoleByName(source.Name).
+			Return(source, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRoleByName("missing").
+			Return(nil, &model.Response{}, errors.New("role_not_found")).
+			Times(1)
+
+		err := roleCloneCmdF(s.client, &cobra.Command{}, []string{source.Name, "missing"})
+		s.Require().EqualError(err, "role_not_found")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestRolePatchCmd() {
+	newCmd := func(add, remove []string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("add", add, "")
+		cmd.Flags().StringSlice("remove", remove, "")
+		return cmd
+	}
+
+	s.Run("Should add and remove permissions", func() {
+		printer.Clean()
+		role := &model.Role{Id: model.NewId(), Name: "system_user", Permissions: []string{"create_team", "list_public_teams"}}
+		expectedPermissions := []string{"list_public_teams", "list_private_teams"}
+
+		s.client.
+			EXPECT().
+			GetRoleByName(role.Name).
+			Return(role, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchRole(role.Id, &model.RolePatch{Permissions: &expectedPermissions}).
+			Return(&model.Role{Id: role.Id, Name: role.Name, Permissions: expectedPermissions}, &model.Response{}, nil).
+			Times(1)
+
+		err := rolePatchCmdF(s.client, newCmd([]string{"list_private_teams"}, []string{"create_team"}), []string{role.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should fail without permissions to add or remove", func() {
+		err := rolePatchCmdF(s.client, newCmd([]string{}, []string{}), []string{"system_user"})
+		s.Require().EqualError(err, "at least one permission to add or remove is required")
+	})
+}
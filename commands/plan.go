@@ -0,0 +1,298 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// dryRunAnnotation marks the commands that support the --dry-run flag,
+// which are the ones that support plans
+const dryRunAnnotation = "dry-run"
+
+// planAction is an action on the entity of the ID. The target is the
+// entity the action is performed in relation to, if any, like the
+// channel a user is removed from
+type planAction struct {
+	Action     string `json:"action"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	TargetID   string `json:"target_id,omitempty"`
+	TargetName string `json:"target_name,omitempty"`
+}
+
+// commandPlan describes every action a destructive command would
+// perform. The actions target entities by ID, so applying a plan acts
+// on exactly the entities that were reviewed
+type commandPlan struct {
+	Command  string       `json:"command"`
+	CreateAt int64        `json:"create_at"`
+	Actions  []planAction `json:"actions"`
+}
+
+func (p *commandPlan) String() string {
+	b, _ := json.MarshalIndent(p, "", "  ")
+	return string(b)
+}
+
+// planResolver resolves the arguments and flags of a command into the
+// actions the command would perform
+type planResolver func(c client.Client, cmd *cobra.Command, args []string) ([]planAction, error)
+
+// planApplier performs the actions of a plan file
+type planApplier func(c client.Client, cmd *cobra.Command, actions []planAction) error
+
+// addPlanFlags adds the --plan and --apply-plan flags to a command,
+// allowing it to be run without arguments when a plan is applied
+func addPlanFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("plan", false, "Print a JSON plan with the actions the command would perform, without performing them")
+	cmd.Flags().String("apply-plan", "", "Perform the actions of a plan file previously generated with --plan, instead of taking them from the arguments")
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[dryRunAnnotation] = "true"
+
+	args := cmd.Args
+	cmd.Args = func(cmd *cobra.Command, positionalArgs []string) error {
+		if planFile, _ := cmd.Flags().GetString("apply-plan"); planFile != "" {
+			return nil
+		}
+		if args == nil {
+			return nil
+		}
+		return args(cmd, positionalArgs)
+	}
+}
+
+// checkDryRun fails if the --dry-run flag is used with a command that
+// doesn't support it, as the command would otherwise perform its
+// actions. The commands with a dry run flag of their own shadow the
+// global one, so it is set if dry run is enabled through the environment
+func checkDryRun(cmd *cobra.Command) error {
+	if !viper.GetBool("dry-run") {
+		return nil
+	}
+	if flag := cmd.Flags().Lookup("dry-run"); flag != nil && flag != cmd.Root().PersistentFlags().Lookup("dry-run") {
+		return cmd.Flags().Set("dry-run", "true")
+	}
+	if cmd.Annotations[dryRunAnnotation] == "" {
+		return fmt.Errorf("the --dry-run flag is not supported by %q", cmd.CommandPath())
+	}
+	return nil
+}
+
+func printDryRun(actions []planAction) {
+	if len(actions) == 0 {
+		printer.Print("Dry run: nothing would be affected")
+		return
+	}
+	for _, action := range actions {
+		printer.PrintT("Dry run: {{.Action}} {{.Name}} ({{.ID}}){{if .TargetID}} of {{.TargetName}} ({{.TargetID}}){{end}}", action)
+	}
+}
+
+// withPlan wraps the run function of a destructive command, printing
+// the plan of the command instead of running it when --plan is set,
+// and running it on the targets of a plan file when --apply-plan is set.
+// With --dry-run, the targets are resolved and printed instead
+func withPlan(resolve planResolver, fn func(c client.Client, cmd *cobra.Command, args []string) error) func(c client.Client, cmd *cobra.Command, args []string) error {
+	apply := func(c client.Client, cmd *cobra.Command, actions []planAction) error {
+		ids := make([]string, 0, len(actions))
+		for _, action := range actions {
+			ids = append(ids, action.ID)
+		}
+		return fn(c, cmd, ids)
+	}
+	return withPlanActions(resolve, apply, fn)
+}
+
+// withPlanActions is like withPlan, for the commands whose actions
+// can't be performed by running them with the IDs of the plan as
+// arguments, as they act on entities of several kinds or depend on
+// flags. The actions of a plan file are performed with apply instead
+func withPlanActions(resolve planResolver, apply planApplier, fn func(c client.Client, cmd *cobra.Command, args []string) error) func(c client.Client, cmd *cobra.Command, args []string) error {
+	return func(c client.Client, cmd *cobra.Command, args []string) error {
+		planOnly, _ := cmd.Flags().GetBool("plan")
+		planFile, _ := cmd.Flags().GetString("apply-plan")
+		dryRun := viper.GetBool("dry-run")
+
+		switch {
+		case planOnly && planFile != "":
+			return errors.New("--plan and --apply-plan can't be used together")
+		case planFile != "":
+			if len(args) > 0 {
+				return errors.New("arguments can't be used with --apply-plan")
+			}
+
+			plan, err := readPlanFile(planFile, cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			if len(plan.Actions) == 0 {
+				printer.Print("The plan has no actions")
+				return nil
+			}
+
+			if dryRun {
+				printDryRun(plan.Actions)
+				return nil
+			}
+			return apply(c, cmd, plan.Actions)
+		case planOnly:
+			actions, err := resolve(c, cmd, args)
+			if err != nil {
+				return err
+			}
+
+			printer.SetSingle(true)
+			printer.PrintT("{{.}}", &commandPlan{
+				Command:  cmd.CommandPath(),
+				CreateAt: model.GetMillis(),
+				Actions:  actions,
+			})
+			return nil
+		case dryRun:
+			actions, err := resolve(c, cmd, args)
+			if err != nil {
+				return err
+			}
+			printDryRun(actions)
+			return nil
+		default:
+			return fn(c, cmd, args)
+		}
+	}
+}
+
+func readPlanFile(path, command string) (*commandPlan, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read plan file: %w", err)
+	}
+
+	var plan commandPlan
+	if err := json.Unmarshal(b, &plan); err != nil {
+		return nil, fmt.Errorf("cannot parse plan file: %w", err)
+	}
+
+	if plan.Command != command {
+		return nil, fmt.Errorf("the plan was generated for %q and can't be applied with %q", plan.Command, command)
+	}
+
+	for _, action := range plan.Actions {
+		if !model.IsValidId(action.ID) {
+			return nil, fmt.Errorf("invalid ID %q in plan action %q", action.ID, action.Action)
+		}
+		if action.TargetID != "" && !model.IsValidId(action.TargetID) {
+			return nil, fmt.Errorf("invalid target ID %q in plan action %q", action.TargetID, action.Action)
+		}
+		if action.TargetID == "" && planActionNeedsTarget(action.Action) {
+			return nil, fmt.Errorf("missing target ID in plan action %q", action.Action)
+		}
+	}
+
+	return &plan, nil
+}
+
+// planActionNeedsTarget tells whether the action is performed in
+// relation to a target, like the channel a user is removed from
+func planActionNeedsTarget(action string) bool {
+	if action == planActionRemoveChannelMember || action == planActionRevokeSession {
+		return true
+	}
+	for _, name := range teamUsersSyncPlanActions {
+		if action == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlanActions fails if any action of a plan is not one of the
+// actions the command performs
+func checkPlanActions(actions []planAction, allowed ...string) error {
+	for _, action := range actions {
+		found := false
+		for _, name := range allowed {
+			found = found || action.Action == name
+		}
+		if !found {
+			return fmt.Errorf("unsupported plan action %q", action.Action)
+		}
+	}
+	return nil
+}
+
+func channelPlanResolver(action string) planResolver {
+	return func(c client.Client, _ *cobra.Command, args []string) ([]planAction, error) {
+		var result *multierror.Error
+		actions := []planAction{}
+		for i, channel := range getChannelsFromChannelArgs(c, args) {
+			if channel == nil {
+				result = multierror.Append(result, fmt.Errorf("unable to find channel %q", args[i]))
+				continue
+			}
+			actions = append(actions, planAction{Action: action, ID: channel.Id, Name: channel.Name})
+		}
+		return actions, result.ErrorOrNil()
+	}
+}
+
+func teamPlanResolver(action string) planResolver {
+	return func(c client.Client, _ *cobra.Command, args []string) ([]planAction, error) {
+		var result *multierror.Error
+		actions := []planAction{}
+		for i, team := range getTeamsFromTeamArgs(c, args) {
+			if team == nil {
+				result = multierror.Append(result, fmt.Errorf("unable to find team %q", args[i]))
+				continue
+			}
+			actions = append(actions, planAction{Action: action, ID: team.Id, Name: team.Name})
+		}
+		return actions, result.ErrorOrNil()
+	}
+}
+
+func userPlanResolver(action string) planResolver {
+	return func(c client.Client, _ *cobra.Command, args []string) ([]planAction, error) {
+		users, err := getUsersFromArgs(c, args)
+		if err != nil {
+			return nil, err
+		}
+
+		actions := []planAction{}
+		for _, user := range users {
+			actions = append(actions, planAction{Action: action, ID: user.Id, Name: user.Username})
+		}
+		return actions, nil
+	}
+}
+
+func postPlanResolver(action string) planResolver {
+	return func(c client.Client, _ *cobra.Command, args []string) ([]planAction, error) {
+		var result *multierror.Error
+		actions := []planAction{}
+		for _, postID := range args {
+			post, _, err := c.GetPost(postID, "")
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("unable to find post %q", postID))
+				continue
+			}
+			actions = append(actions, planAction{Action: action, ID: post.Id, Name: postSummary(post)})
+		}
+		return actions, result.ErrorOrNil()
+	}
+}
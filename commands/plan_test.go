@@ -0,0 +1,212 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPlan() {
+	team1 := &model.Team{Id: model.NewId(), Name: "team1"}
+	team2 := &model.Team{Id: model.NewId(), Name: "team2"}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "delete", Args: cobra.MinimumNArgs(1)}
+		cmd.Flags().Bool("confirm", true, "")
+		addPlanFlags(cmd)
+		return cmd
+	}
+
+	writePlan := func(dir, content string) string {
+		path := filepath.Join(dir, "plan.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	s.Run("Should print the plan without running the command", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("plan", "true"))
+
+		s.client.
+			EXPECT().
+			GetTeam(team1.Name, "").
+			Return(team1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(team2.Id, "").
+			Return(team2, &model.Response{}, nil).
+			Times(1)
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{team1.Name, team2.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+
+		plan, ok := printer.GetLines()[0].(*commandPlan)
+		s.Require().True(ok)
+		s.Require().Equal("delete", plan.Command)
+		s.Require().Equal([]planAction{
+			{Action: "delete_team", ID: team1.Id, Name: team1.Name},
+			{Action: "delete_team", ID: team2.Id, Name: team2.Name},
+		}, plan.Actions)
+	})
+
+	s.Run("Should fail to plan when a target is not found", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("plan", "true"))
+
+		s.client.
+			EXPECT().
+			GetTeam("unknown", "").
+			Return(nil, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName("unknown", "").
+			Return(nil, &model.Response{}, nil).
+			Times(1)
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{"unknown"})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), `unable to find team "unknown"`)
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should apply the actions of a plan file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("apply-plan", writePlan(tmp, `{"command": "delete", "actions": [{"action": "delete_team", "id": "`+team1.Id+`", "name": "team1"}]}`)))
+		s.Require().NoError(cmd.Args(cmd, []string{}))
+
+		s.client.
+			EXPECT().
+			GetTeam(team1.Id, "").
+			Return(team1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PermanentDeleteTeam(team1.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{team1}, printer.GetLines())
+	})
+
+	s.Run("Should refuse plans generated for another command", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("apply-plan", writePlan(tmp, `{"command": "mmctl user delete", "actions": []}`)))
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{})
+		s.Require().EqualError(err, `the plan was generated for "mmctl user delete" and can't be applied with "delete"`)
+	})
+
+	s.Run("Should refuse plans with invalid IDs", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("apply-plan", writePlan(tmp, `{"command": "delete", "actions": [{"action": "delete_team", "id": "myteam"}]}`)))
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{})
+		s.Require().EqualError(err, `invalid ID "myteam" in plan action "delete_team"`)
+	})
+
+	s.Run("Should refuse plans with actions missing their target", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		userID := model.NewId()
+		path := writePlan(tmp, `{"command": "remove", "actions": [{"action": "remove_channel_member", "id": "`+userID+`", "name": "user"}]}`)
+
+		_, err := readPlanFile(path, "remove")
+		s.Require().EqualError(err, `missing target ID in plan action "remove_channel_member"`)
+	})
+
+	s.Run("Should refuse arguments together with a plan file", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().Error(cmd.Args(cmd, []string{}))
+		s.Require().NoError(cmd.Flags().Set("apply-plan", "plan.json"))
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, cmd, []string{team1.Name})
+		s.Require().EqualError(err, "arguments can't be used with --apply-plan")
+	})
+
+	s.Run("Should only print the targets with --dry-run", func() {
+		printer.Clean()
+		defer viper.Set("dry-run", false)
+		viper.Set("dry-run", true)
+
+		s.client.
+			EXPECT().
+			GetTeam(team1.Name, "").
+			Return(team1, &model.Response{}, nil).
+			Times(1)
+
+		err := withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)(s.client, newCmd(), []string{team1.Name})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{planAction{Action: "delete_team", ID: team1.Id, Name: team1.Name}}, printer.GetLines())
+	})
+
+	s.Run("Should print the posts that would be deleted with --dry-run", func() {
+		printer.Clean()
+		printer.SetFormat(printer.FormatPlain)
+		defer printer.SetFormat(printer.FormatJSON)
+		defer viper.Set("dry-run", false)
+		viper.Set("dry-run", true)
+		post := &model.Post{Id: model.NewId(), Message: "a message\nwith two lines"}
+
+		s.client.
+			EXPECT().
+			GetPost(post.Id, "").
+			Return(post, &model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd()
+		_ = cmd.Flags().Set("confirm", "false")
+		err := withPlan(postPlanResolver("delete_post"), postDeleteCmdF)(s.client, cmd, []string{post.Id})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"Dry run: delete_post a message with two lines (" + post.Id + ")"}, printer.GetLines())
+	})
+
+	s.Run("Should refuse --dry-run with the commands that don't support it", func() {
+		defer viper.Set("dry-run", false)
+		viper.Set("dry-run", true)
+
+		s.Require().NoError(checkDryRun(newCmd()))
+		s.Require().EqualError(checkDryRun(&cobra.Command{Use: "create"}), `the --dry-run flag is not supported by "create"`)
+
+		// the commands with their own dry run flag get it set
+		cmd := &cobra.Command{Use: "enforce"}
+		cmd.Flags().Bool("dry-run", false, "")
+		s.Require().NoError(checkDryRun(cmd))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		s.Require().True(dryRun)
+	})
+}
@@ -4,7 +4,17 @@
 package commands
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
 
 	"github.com/mattermost/mmctl/v6/client"
 	"github.com/mattermost/mmctl/v6/printer"
@@ -51,12 +61,15 @@ var PluginDeleteCmd = &cobra.Command{
 }
 
 var PluginEnableCmd = &cobra.Command{
-	Use:     "enable [plugins]",
-	Short:   "Enable plugins",
-	Long:    "Enable plugins for use on your Mattermost server.",
-	Example: `  plugin enable hovercardexample pluginexample`,
-	RunE:    withClient(pluginEnableCmdF),
-	Args:    cobra.MinimumNArgs(1),
+	Use:   "enable [plugins]",
+	Short: "Enable plugins",
+	Long:  "Enable plugins for use on your Mattermost server.",
+	Example: `  plugin enable hovercardexample pluginexample
+
+  # wait up to two minutes for the plugins to be running on every server of the cluster
+  plugin enable hovercardexample pluginexample --wait-healthy --wait-timeout 2m`,
+	RunE: withClient(pluginEnableCmdF),
+	Args: cobra.MinimumNArgs(1),
 }
 
 var PluginDisableCmd = &cobra.Command{
@@ -68,17 +81,40 @@ var PluginDisableCmd = &cobra.Command{
 	Args:    cobra.MinimumNArgs(1),
 }
 
+var PluginDeployCmd = &cobra.Command{
+	Use:   "deploy <bundle>",
+	Short: "Deploy a plugin",
+	Long: `Upload a plugin bundle, replacing the installed version if any, enable it and wait until it is running.
+If the plugin fails to start and --rollback-on-failure is set, the previously installed version is restored, either from the bundle provided with --previous-bundle, which is checked to be of the same plugin before the upload, or from the marketplace. If there was no previous version, the plugin is removed.`,
+	Example: `  plugin deploy myplugin-1.1.0.tar.gz --rollback-on-failure
+  plugin deploy myplugin-1.1.0.tar.gz --rollback-on-failure --previous-bundle myplugin-1.0.0.tar.gz`,
+	RunE: withClient(pluginDeployCmdF),
+	Args: cobra.ExactArgs(1),
+}
+
 var PluginListCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List plugins",
-	Long:    "List all enabled and disabled plugins installed on your Mattermost server.",
-	Example: `  plugin list`,
-	RunE:    withClient(pluginListCmdF),
+	Use:   "list",
+	Short: "List plugins",
+	Long: `List all enabled and disabled plugins installed on your Mattermost server.
+With --outdated, only the plugins whose installed version is older than the latest version of the marketplace are listed, together with both versions.`,
+	Example: `  plugin list
+
+  # list the plugins with a newer version in the marketplace
+  plugin list --outdated --json`,
+	RunE: withClient(pluginListCmdF),
 }
 
 func init() {
 	PluginAddCmd.Flags().BoolP("force", "f", false, "overwrite a previously installed plugin with the same ID, if any")
+	PluginAddCmd.Flags().String("expect-sha256", "", "fail without uploading if the SHA-256 digest of the plugin doesn't match. Only valid when adding a single plugin")
 	PluginInstallURLCmd.Flags().BoolP("force", "f", false, "overwrite a previously installed plugin with the same ID, if any")
+	PluginEnableCmd.Flags().Bool("wait-healthy", false, "wait until the enabled plugins are running, failing if they don't start")
+	PluginEnableCmd.Flags().Duration("wait-timeout", time.Minute, "maximum time to wait for each plugin to be running when --wait-healthy is set")
+	PluginDeployCmd.Flags().Bool("rollback-on-failure", false, "restore the previously installed version if the plugin fails to start")
+	PluginDeployCmd.Flags().String("previous-bundle", "", "bundle of the previously installed version to restore on rollback. If not set, the previous version is installed from the marketplace")
+	PluginDeployCmd.Flags().Duration("wait-timeout", time.Minute, "maximum time to wait for the plugin to be running")
+	PluginDeployCmd.Flags().String("expect-sha256", "", "fail without uploading if the SHA-256 digest of the bundle doesn't match")
+	PluginListCmd.Flags().Bool("outdated", false, "only list the plugins with a newer version in the marketplace")
 
 	PluginCmd.AddCommand(
 		PluginAddCmd,
@@ -86,6 +122,7 @@ func init() {
 		PluginDeleteCmd,
 		PluginEnableCmd,
 		PluginDisableCmd,
+		PluginDeployCmd,
 		PluginListCmd,
 	)
 	RootCmd.AddCommand(PluginCmd)
@@ -93,6 +130,10 @@ func init() {
 
 func pluginAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
+	expectedSHA256, _ := cmd.Flags().GetString("expect-sha256")
+	if expectedSHA256 != "" && len(args) > 1 {
+		return errors.New("--expect-sha256 can only be used when adding a single plugin")
+	}
 
 	for i, plugin := range args {
 		fileReader, err := os.Open(plugin)
@@ -100,16 +141,26 @@ func pluginAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		if force {
-			_, _, err = c.UploadPluginForced(fileReader)
-		} else {
-			_, _, err = c.UploadPlugin(fileReader)
+		digest, err := checkFileSHA256(plugin, expectedSHA256)
+		if err != nil {
+			fileReader.Close()
+			return err
 		}
 
+		err = withUploadProgress(filepath.Base(plugin), 0, -1, func() error {
+			if force {
+				_, _, err := c.UploadPluginForced(fileReader)
+				return err
+			}
+			_, _, err := c.UploadPlugin(fileReader)
+			return err
+		})
+
 		if err != nil {
 			printer.PrintError("Unable to add plugin: " + args[i] + ". Error: " + err.Error())
 		} else {
 			printer.Print("Added plugin: " + plugin)
+			printSHA256(plugin, digest)
 		}
 		fileReader.Close()
 	}
@@ -146,16 +197,85 @@ func pluginDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	return nil
 }
 
+// pluginHealthPollInterval is the time between plugin status checks
+// while waiting for a plugin to be running
+var pluginHealthPollInterval = 2 * time.Second
+
 func pluginEnableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	waitHealthy, _ := cmd.Flags().GetBool("wait-healthy")
+	waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+	var multiErr *multierror.Error
+
 	for _, plugin := range args {
 		if _, err := c.EnablePlugin(plugin); err != nil {
 			printer.PrintError("Unable to enable plugin: " + plugin + ". Error: " + err.Error())
-		} else {
-			printer.Print("Enabled plugin: " + plugin)
+			if waitHealthy {
+				multiErr = multierror.Append(multiErr, err)
+			}
+			continue
+		}
+		printer.Print("Enabled plugin: " + plugin)
+
+		if !waitHealthy {
+			continue
 		}
+
+		if err := waitForPluginHealthy(c, plugin, "", waitTimeout); err != nil {
+			printer.PrintError("Plugin " + plugin + " is not healthy. Error: " + err.Error())
+			multiErr = multierror.Append(multiErr, err)
+			continue
+		}
+		printer.Print("Plugin " + plugin + " is running")
 	}
 
-	return nil
+	return multiErr.ErrorOrNil()
+}
+
+// waitForPluginHealthy polls the plugin statuses until the plugin is
+// running on every server of the cluster, failing early if any of
+// them reports that the plugin crashed. If the version is set, the
+// servers still running another version are not counted as healthy
+func waitForPluginHealthy(c client.Client, pluginID, version string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		statuses, _, err := c.GetPluginStatuses()
+		if err != nil {
+			return fmt.Errorf("unable to get plugin statuses: %w", err)
+		}
+
+		found, running := false, true
+		for _, status := range statuses {
+			if status.PluginId != pluginID {
+				continue
+			}
+			found = true
+
+			switch status.State {
+			case model.PluginStateFailedToStart:
+				return fmt.Errorf("plugin %s failed to start on %s", pluginID, status.ClusterId)
+			case model.PluginStateFailedToStayRunning:
+				return fmt.Errorf("plugin %s failed to stay running on %s", pluginID, status.ClusterId)
+			case model.PluginStateRunning:
+				if version != "" && status.Version != version {
+					running = false
+				}
+			default:
+				running = false
+			}
+		}
+
+		if found && running {
+			return nil
+		}
+
+		if time.Now().Add(pluginHealthPollInterval).After(deadline) {
+			if version != "" {
+				return fmt.Errorf("timed out after %s waiting for version %s of plugin %s to be running", timeout, version, pluginID)
+			}
+			return fmt.Errorf("timed out after %s waiting for plugin %s to be running", timeout, pluginID)
+		}
+		time.Sleep(pluginHealthPollInterval)
+	}
 }
 
 func pluginDisableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
@@ -170,6 +290,174 @@ func pluginDisableCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	return nil
 }
 
+// readBundleManifest extracts the plugin.json manifest of a plugin
+// bundle without uploading it
+func readBundleManifest(path string) (*model.Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bundle %s: %w", path, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read bundle %s: %w", path, err)
+		}
+
+		// the manifest lives either at the root of the bundle or
+		// inside the plugin directory
+		name := strings.TrimPrefix(filepath.ToSlash(header.Name), "./")
+		if filepath.Base(name) != "plugin.json" || strings.Count(name, "/") > 1 {
+			continue
+		}
+
+		var manifest model.Manifest
+		if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("unable to parse the manifest of bundle %s: %w", path, err)
+		}
+		return &manifest, nil
+	}
+
+	return nil, fmt.Errorf("unable to find plugin.json in bundle %s", path)
+}
+
+func uploadPluginBundle(c client.Client, path string) (*model.Manifest, error) {
+	fileReader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fileReader.Close()
+
+	var manifest *model.Manifest
+	err = withUploadProgress(filepath.Base(path), 0, -1, func() error {
+		var uErr error
+		manifest, _, uErr = c.UploadPluginForced(fileReader)
+		return uErr
+	})
+	return manifest, err
+}
+
+func pluginDeployCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	rollback, _ := cmd.Flags().GetBool("rollback-on-failure")
+	previousBundle, _ := cmd.Flags().GetString("previous-bundle")
+	waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+	expectedSHA256, _ := cmd.Flags().GetString("expect-sha256")
+
+	digest, err := checkFileSHA256(args[0], expectedSHA256)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := readBundleManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	// the previous bundle is checked before the upload, as a bundle
+	// that can't be used would only be found once the rollback is due
+	if previousBundle != "" {
+		previousManifest, err := readBundleManifest(previousBundle)
+		if err != nil {
+			return fmt.Errorf("unable to use previous bundle %s: %w", previousBundle, err)
+		}
+		if previousManifest.Id != manifest.Id {
+			return fmt.Errorf("previous bundle %s is of plugin %s instead of %s", previousBundle, previousManifest.Id, manifest.Id)
+		}
+	}
+
+	pluginsResp, _, err := c.GetPlugins()
+	if err != nil {
+		return errors.Wrap(err, "Unable to get plugins")
+	}
+
+	var previous *model.PluginInfo
+	previousEnabled := false
+	for _, plugin := range pluginsResp.Active {
+		if plugin.Id == manifest.Id {
+			previous, previousEnabled = plugin, true
+		}
+	}
+	for _, plugin := range pluginsResp.Inactive {
+		if plugin.Id == manifest.Id {
+			previous = plugin
+		}
+	}
+
+	if _, err := uploadPluginBundle(c, args[0]); err != nil {
+		return fmt.Errorf("unable to upload plugin %s: %w", manifest.Id, err)
+	}
+	printer.Print("Uploaded plugin " + manifest.Id + " version " + manifest.Version)
+	printSHA256(args[0], digest)
+
+	deployErr := func() error {
+		if _, err := c.EnablePlugin(manifest.Id); err != nil {
+			return fmt.Errorf("unable to enable plugin %s: %w", manifest.Id, err)
+		}
+		return waitForPluginHealthy(c, manifest.Id, manifest.Version, waitTimeout)
+	}()
+	if deployErr == nil {
+		printer.Print("Plugin " + manifest.Id + " version " + manifest.Version + " is running")
+		return nil
+	}
+
+	if !rollback {
+		return deployErr
+	}
+
+	printer.PrintError("Deployment of plugin " + manifest.Id + " failed, rolling back. Error: " + deployErr.Error())
+	if err := rollbackPlugin(c, manifest.Id, previous, previousEnabled, previousBundle); err != nil {
+		return fmt.Errorf("%s. Rollback failed: %w", deployErr, err)
+	}
+
+	return deployErr
+}
+
+func rollbackPlugin(c client.Client, pluginID string, previous *model.PluginInfo, previousEnabled bool, previousBundle string) error {
+	if previous == nil {
+		if _, err := c.DisablePlugin(pluginID); err != nil {
+			printer.PrintError("Unable to disable plugin " + pluginID + ". Error: " + err.Error())
+		}
+		if _, err := c.RemovePlugin(pluginID); err != nil {
+			return fmt.Errorf("unable to remove plugin %s: %w", pluginID, err)
+		}
+		printer.Print("Removed plugin " + pluginID + " as no previous version was installed")
+		return nil
+	}
+
+	if previousBundle != "" {
+		if _, err := uploadPluginBundle(c, previousBundle); err != nil {
+			return fmt.Errorf("unable to upload previous bundle %s: %w", previousBundle, err)
+		}
+	} else {
+		req := &model.InstallMarketplacePluginRequest{Id: pluginID, Version: previous.Version}
+		if _, _, err := c.InstallMarketplacePlugin(req); err != nil {
+			return fmt.Errorf("unable to install version %s of plugin %s from the marketplace: %w", previous.Version, pluginID, err)
+		}
+	}
+
+	if previousEnabled {
+		if _, err := c.EnablePlugin(pluginID); err != nil {
+			return fmt.Errorf("unable to enable plugin %s: %w", pluginID, err)
+		}
+	} else if _, err := c.DisablePlugin(pluginID); err != nil {
+		return fmt.Errorf("unable to disable plugin %s: %w", pluginID, err)
+	}
+
+	printer.Print("Rolled back plugin " + pluginID + " to version " + previous.Version)
+	return nil
+}
+
 func pluginListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	pluginsResp, _, err := c.GetPlugins()
 	if err != nil {
@@ -178,6 +466,21 @@ func pluginListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 
 	format, _ := cmd.Flags().GetString("format")
 	json, _ := cmd.Flags().GetBool("json")
+	if outdated, _ := cmd.Flags().GetBool("outdated"); outdated {
+		plugins, err := getOutdatedPlugins(c, pluginsResp)
+		if err != nil {
+			return err
+		}
+
+		if len(plugins) == 0 && format != printer.FormatJSON && !json {
+			printer.Print("All plugins are up to date")
+		}
+		for _, plugin := range plugins {
+			printer.PrintT("{{.ID}}: {{.Name}}, Version: {{.Installed}}, Latest: {{.Latest}}", plugin)
+		}
+		return nil
+	}
+
 	if format == printer.FormatJSON || json {
 		printer.Print(pluginsResp)
 	} else {
@@ -38,7 +38,7 @@ func (s *MmctlE2ETestSuite) TestPluginAddCmd() {
 		err := pluginAddCmdF(c, &cobra.Command{}, []string{pluginPath})
 		s.Require().Nil(err)
 
-		s.Require().Equal(1, len(printer.GetLines()))
+		s.Require().Equal(2, len(printer.GetLines()))
 		s.Require().Contains(printer.GetLines()[0], "Added plugin: ")
 
 		printer.Clean()
@@ -77,7 +77,7 @@ func (s *MmctlE2ETestSuite) TestPluginAddCmd() {
 		err := pluginAddCmdF(c, &cobra.Command{}, []string{pluginPath})
 		s.Require().Nil(err)
 
-		s.Require().Equal(1, len(printer.GetLines()))
+		s.Require().Equal(2, len(printer.GetLines()))
 		s.Require().Contains(printer.GetLines()[0], "Added plugin: ")
 
 		printer.Clean()
@@ -87,7 +87,7 @@ func (s *MmctlE2ETestSuite) TestPluginAddCmd() {
 		err = pluginAddCmdF(c, cmd, []string{pluginPath})
 		s.Require().Nil(err)
 
-		s.Require().Equal(1, len(printer.GetLines()))
+		s.Require().Equal(2, len(printer.GetLines()))
 		s.Require().Equal(0, len(printer.GetErrorLines()))
 		s.Require().Contains(printer.GetLines()[0], "Added plugin: ")
 
@@ -127,7 +127,7 @@ func (s *MmctlE2ETestSuite) TestPluginAddCmd() {
 		err := pluginAddCmdF(c, &cobra.Command{}, []string{pluginPath})
 		s.Require().Nil(err)
 
-		s.Require().Equal(1, len(printer.GetLines()))
+		s.Require().Equal(2, len(printer.GetLines()))
 		s.Require().Contains(printer.GetLines()[0], "Added plugin: ")
 
 		res, appErr := s.th.App.GetPlugins()
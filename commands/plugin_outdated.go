@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+type outdatedPlugin struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Installed string `json:"installed_version"`
+	Latest    string `json:"latest_version"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// getMarketplaceVersions returns the latest version of each plugin
+// known to the marketplace of the server
+func getMarketplaceVersions(c client.Client) (map[string]string, error) {
+	versions := map[string]string{}
+	for page := 0; ; page++ {
+		plugins, _, err := c.GetMarketplacePlugins(&model.MarketplacePluginFilter{Page: page, PerPage: APILimitMaximum})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the marketplace plugins")
+		}
+		for _, plugin := range plugins {
+			if plugin.Manifest != nil {
+				versions[plugin.Manifest.Id] = plugin.Manifest.Version
+			}
+		}
+		if len(plugins) < APILimitMaximum {
+			return versions, nil
+		}
+	}
+}
+
+// getOutdatedPlugins compares the versions of the installed plugins
+// with the ones of the marketplace. The plugins that aren't in the
+// marketplace or whose versions can't be compared are skipped
+func getOutdatedPlugins(c client.Client, plugins *model.PluginsResponse) ([]*outdatedPlugin, error) {
+	latestVersions, err := getMarketplaceVersions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	outdated := []*outdatedPlugin{}
+	check := func(infos []*model.PluginInfo, enabled bool) {
+		for _, info := range infos {
+			latest, ok := latestVersions[info.Manifest.Id]
+			if !ok {
+				continue
+			}
+			installedVersion, err := semver.NewVersion(info.Manifest.Version)
+			if err != nil {
+				printer.PrintWarning("unable to parse the version of plugin " + info.Manifest.Id + ": " + err.Error())
+				continue
+			}
+			latestVersion, err := semver.NewVersion(latest)
+			if err != nil {
+				printer.PrintWarning("unable to parse the marketplace version of plugin " + info.Manifest.Id + ": " + err.Error())
+				continue
+			}
+			if installedVersion.LessThan(latestVersion) {
+				outdated = append(outdated, &outdatedPlugin{
+					ID:        info.Manifest.Id,
+					Name:      info.Manifest.Name,
+					Installed: info.Manifest.Version,
+					Latest:    latest,
+					Enabled:   enabled,
+				})
+			}
+		}
+	}
+	check(plugins.Active, true)
+	check(plugins.Inactive, false)
+
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].ID < outdated[j].ID })
+	return outdated, nil
+}
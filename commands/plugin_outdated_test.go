@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPluginListOutdatedCmd() {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("outdated", true, "")
+
+	plugins := &model.PluginsResponse{
+		Active: []*model.PluginInfo{
+			{Manifest: model.Manifest{Id: "jira", Name: "Jira", Version: "3.0.0"}},
+			{Manifest: model.Manifest{Id: "custom", Name: "Custom", Version: "0.1.0"}},
+		},
+		Inactive: []*model.PluginInfo{
+			{Manifest: model.Manifest{Id: "github", Name: "GitHub", Version: "2.0.1"}},
+			{Manifest: model.Manifest{Id: "zoom", Name: "Zoom", Version: "1.6.0"}},
+		},
+	}
+
+	marketplacePlugin := func(id, version string) *model.MarketplacePlugin {
+		return &model.MarketplacePlugin{BaseMarketplacePlugin: &model.BaseMarketplacePlugin{Manifest: &model.Manifest{Id: id, Version: version}}}
+	}
+
+	s.Run("Should list the plugins with a newer marketplace version", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(plugins, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetMarketplacePlugins(&model.MarketplacePluginFilter{Page: 0, PerPage: APILimitMaximum}).
+			Return([]*model.MarketplacePlugin{
+				marketplacePlugin("jira", "3.2.1"),
+				marketplacePlugin("github", "2.0.1"),
+				marketplacePlugin("zoom", "1.10.0"),
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginListCmdF(s.client, cmd, nil)
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&outdatedPlugin{ID: "jira", Name: "Jira", Installed: "3.0.0", Latest: "3.2.1", Enabled: true},
+			&outdatedPlugin{ID: "zoom", Name: "Zoom", Installed: "1.6.0", Latest: "1.10.0", Enabled: false},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the marketplace can't be fetched", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(plugins, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetMarketplacePlugins(&model.MarketplacePluginFilter{Page: 0, PerPage: APILimitMaximum}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := pluginListCmdF(s.client, cmd, nil)
+		s.Require().EqualError(err, "failed to fetch the marketplace plugins: mock error")
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var PluginSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Export and import the settings of plugins",
+}
+
+var PluginSettingsExportCmd = &cobra.Command{
+	Use:   "export [plugin-id] [file]",
+	Short: "Export the settings of a plugin",
+	Long: `Export the settings of a plugin from the server configuration to a JSON file, to import them to another server with "plugin settings import".
+Only the settings declared in the settings schema of the plugin manifest are exported. The secrets are masked by the server, so they are exported masked.`,
+	Example: `  plugin settings export com.mattermost.plugin-jira jira-settings.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    withClient(pluginSettingsExportCmdF),
+}
+
+var PluginSettingsImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import the settings of a plugin",
+	Long: `Import the settings of a plugin exported with "plugin settings export" to the server configuration. The plugin has to be installed on the server.
+Only the settings declared in the settings schema of the plugin manifest installed on the server are imported, and the masked secrets are left unchanged, so they have to be set on each server. The settings of the plugin that aren't in the file keep their value.`,
+	Example: `  plugin settings import jira-settings.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(pluginSettingsImportCmdF),
+}
+
+func init() {
+	PluginSettingsCmd.AddCommand(
+		PluginSettingsExportCmd,
+		PluginSettingsImportCmd,
+	)
+	PluginCmd.AddCommand(PluginSettingsCmd)
+}
+
+type pluginSettingsFile struct {
+	PluginID      string                 `json:"plugin_id"`
+	PluginVersion string                 `json:"plugin_version"`
+	Settings      map[string]interface{} `json:"settings"`
+}
+
+// getPluginSettingKeys returns the keys of the settings declared in
+// the manifest of an installed plugin. They are lowercased, as the
+// System Console saves them that way
+func getPluginSettingKeys(c client.Client, pluginID string) (*model.Manifest, map[string]bool, error) {
+	plugins, _, err := c.GetPlugins()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to fetch the plugins")
+	}
+
+	var manifest *model.Manifest
+	for _, info := range append(plugins.Active, plugins.Inactive...) {
+		if info.Manifest.Id == pluginID {
+			manifest = &info.Manifest
+			break
+		}
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("plugin %q is not installed", pluginID)
+	}
+	if manifest.SettingsSchema == nil || len(manifest.SettingsSchema.Settings) == 0 {
+		return nil, nil, fmt.Errorf("plugin %q has no settings", pluginID)
+	}
+
+	keys := map[string]bool{}
+	for _, setting := range manifest.SettingsSchema.Settings {
+		if setting.Key != "" {
+			keys[strings.ToLower(setting.Key)] = true
+		}
+	}
+	return manifest, keys, nil
+}
+
+func pluginSettingsExportCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	pluginID, path := args[0], args[1]
+
+	manifest, keys, err := getPluginSettingKeys(c, pluginID)
+	if err != nil {
+		return err
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+
+	settings := map[string]interface{}{}
+	for key, value := range config.PluginSettings.Plugins[pluginID] {
+		if keys[strings.ToLower(key)] {
+			settings[strings.ToLower(key)] = value
+		}
+	}
+
+	file := &pluginSettingsFile{PluginID: pluginID, PluginVersion: manifest.Version, Settings: settings}
+	b, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the settings")
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the settings file")
+	}
+
+	printer.PrintT("Exported {{.Settings | len}} settings of plugin {{.PluginID}}", file)
+	return nil
+}
+
+func pluginSettingsImportCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	b, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to read the settings file")
+	}
+	var file pluginSettingsFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return errors.Wrap(err, "failed to parse the settings file")
+	}
+	if file.PluginID == "" {
+		return errors.New("the settings file has no plugin id")
+	}
+
+	manifest, keys, err := getPluginSettingKeys(c, file.PluginID)
+	if err != nil {
+		return err
+	}
+	if file.PluginVersion != "" && file.PluginVersion != manifest.Version {
+		printer.PrintWarning(fmt.Sprintf("the settings were exported from version %s of plugin %s, and version %s is installed", file.PluginVersion, file.PluginID, manifest.Version))
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+
+	settingKeys := make([]string, 0, len(file.Settings))
+	for key := range file.Settings {
+		settingKeys = append(settingKeys, key)
+	}
+	sort.Strings(settingKeys)
+
+	pluginSettings := map[string]interface{}{}
+	for key, value := range config.PluginSettings.Plugins[file.PluginID] {
+		pluginSettings[key] = value
+	}
+
+	imported := []string{}
+	for _, key := range settingKeys {
+		value := file.Settings[key]
+		switch {
+		case !keys[strings.ToLower(key)]:
+			printer.PrintWarning(fmt.Sprintf("skipping setting %q, which isn't in the settings schema of the plugin", key))
+			continue
+		case value == model.FakeSetting:
+			printer.PrintWarning(fmt.Sprintf("skipping setting %q, which is a masked secret", key))
+			continue
+		}
+		pluginSettings[strings.ToLower(key)] = value
+		imported = append(imported, strings.ToLower(key))
+	}
+
+	if len(imported) == 0 {
+		return errors.New("there are no settings to import")
+	}
+
+	if config.PluginSettings.Plugins == nil {
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{}
+	}
+	config.PluginSettings.Plugins[file.PluginID] = pluginSettings
+	if _, _, err := c.PatchConfig(config); err != nil {
+		return errors.Wrap(err, "failed to update the config")
+	}
+
+	for _, key := range imported {
+		printer.PrintT("Imported setting {{.}}", key)
+	}
+	return nil
+}
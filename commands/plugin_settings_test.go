@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPluginSettingsCmds() {
+	pluginID := "com.mattermost.plugin-jira"
+
+	plugins := func(version string) *model.PluginsResponse {
+		return &model.PluginsResponse{Active: []*model.PluginInfo{{Manifest: model.Manifest{
+			Id:      pluginID,
+			Version: version,
+			SettingsSchema: &model.PluginSettingsSchema{Settings: []*model.PluginSetting{
+				{Key: "JiraURL"},
+				{Key: "Secret"},
+			}},
+		}}}}
+	}
+
+	s.Run("Should export the settings of the schema", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "jira.json")
+
+		config := &model.Config{}
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{
+			pluginID: {"jiraurl": "https://jira.example.com", "secret": model.FakeSetting, "obsolete": true},
+		}
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(plugins("3.0.0"), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginSettingsExportCmdF(s.client, &cobra.Command{}, []string{pluginID, path})
+		s.Require().NoError(err)
+
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		var file pluginSettingsFile
+		s.Require().NoError(json.Unmarshal(b, &file))
+		s.Require().Equal(pluginSettingsFile{
+			PluginID:      pluginID,
+			PluginVersion: "3.0.0",
+			Settings:      map[string]interface{}{"jiraurl": "https://jira.example.com", "secret": model.FakeSetting},
+		}, file)
+	})
+
+	s.Run("Should import the settings of the schema except the masked secrets", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "jira.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`{
+			"plugin_id": "com.mattermost.plugin-jira",
+			"plugin_version": "3.0.0",
+			"settings": {"JiraURL": "https://jira.example.com", "secret": "`+model.FakeSetting+`", "unknown": 1}
+		}`), 0600))
+
+		config := &model.Config{}
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{
+			pluginID: {"jiraurl": "https://staging.example.com", "secret": "staging-secret"},
+		}
+		expectedConfig := &model.Config{}
+		expectedConfig.PluginSettings.Plugins = map[string]map[string]interface{}{
+			pluginID: {"jiraurl": "https://jira.example.com", "secret": "staging-secret"},
+		}
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(plugins("3.0.0"), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(expectedConfig).
+			Return(expectedConfig, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginSettingsImportCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"jiraurl"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the plugin is not installed", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "other.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`{"plugin_id": "com.example.other", "settings": {"key": 1}}`), 0600))
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(plugins("3.0.0"), &model.Response{}, nil).
+			Times(1)
+
+		err := pluginSettingsImportCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().EqualError(err, `plugin "com.example.other" is not installed`)
+	})
+}
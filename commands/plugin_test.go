@@ -4,10 +4,16 @@
 package commands
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-server/v6/model"
@@ -35,7 +41,7 @@ func (s *MmctlUnitTestSuite) TestPluginAddCmd() {
 
 		err = pluginAddCmdF(s.client, &cobra.Command{}, []string{pluginName})
 		s.Require().NoError(err)
-		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Len(printer.GetLines(), 2)
 		s.Require().Equal(printer.GetLines()[0], "Added plugin: "+pluginName)
 	})
 
@@ -58,7 +64,7 @@ func (s *MmctlUnitTestSuite) TestPluginAddCmd() {
 
 		err = pluginAddCmdF(s.client, cmd, []string{pluginName})
 		s.Require().NoError(err)
-		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Len(printer.GetLines(), 2)
 		s.Require().Equal(printer.GetLines()[0], "Added plugin: "+pluginName)
 	})
 
@@ -90,6 +96,55 @@ func (s *MmctlUnitTestSuite) TestPluginAddCmd() {
 		s.Require().Equal(printer.GetErrorLines()[0], "Unable to add plugin: "+pluginName+". Error: "+mockError.Error())
 	})
 
+	s.Run("Add 1 plugin and print its digest", func() {
+		printer.Clean()
+		tmpFile, err := ioutil.TempFile("", "tmpPlugin")
+		s.Require().Nil(err)
+		defer os.Remove(tmpFile.Name())
+
+		pluginName := tmpFile.Name()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("expect-sha256", "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", "")
+
+		s.client.
+			EXPECT().
+			UploadPlugin(gomock.AssignableToTypeOf(tmpFile)).
+			Return(&model.Manifest{}, &model.Response{}, nil).
+			Times(1)
+
+		err = pluginAddCmdF(s.client, cmd, []string{pluginName})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(struct {
+			File   string `json:"file"`
+			SHA256 string `json:"sha256"`
+		}{pluginName, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}, printer.GetLines()[1])
+	})
+
+	s.Run("Fail to add 1 plugin with an unexpected digest", func() {
+		printer.Clean()
+		tmpFile, err := ioutil.TempFile("", "tmpPlugin")
+		s.Require().Nil(err)
+		defer os.Remove(tmpFile.Name())
+
+		pluginName := tmpFile.Name()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("expect-sha256", "0000", "")
+
+		err = pluginAddCmdF(s.client, cmd, []string{pluginName})
+		s.Require().EqualError(err, fmt.Sprintf("SHA-256 mismatch for %q: expected 0000, got e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", pluginName))
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Fail to add several plugins with an expected digest", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("expect-sha256", "0000", "")
+
+		err := pluginAddCmdF(s.client, cmd, []string{"plugin1", "plugin2"})
+		s.Require().EqualError(err, "--expect-sha256 can only be used when adding a single plugin")
+	})
+
 	s.Run("Add several plugins with some error", func() {
 		printer.Clean()
 		args := []string{"fail", "ok", "fail"}
@@ -117,7 +172,7 @@ func (s *MmctlUnitTestSuite) TestPluginAddCmd() {
 
 		err := pluginAddCmdF(s.client, &cobra.Command{}, args)
 		s.Require().NoError(err)
-		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Len(printer.GetLines(), 2)
 		s.Require().Equal(printer.GetLines()[0], "Added plugin: "+args[1])
 		s.Require().Len(printer.GetErrorLines(), 2)
 		s.Require().Equal(printer.GetErrorLines()[0], "Unable to add plugin: "+args[0]+". Error: "+mockError.Error())
@@ -365,6 +420,400 @@ func (s *MmctlUnitTestSuite) TestPluginEnableCmd() {
 		s.Require().Equal(printer.GetErrorLines()[0], "Unable to enable plugin: "+failPlugins[0]+". Error: "+mockErr.Error())
 		s.Require().Equal(printer.GetErrorLines()[1], "Unable to enable plugin: "+failPlugins[1]+". Error: "+mockErr.Error())
 	})
+
+	s.Run("Enable a plugin and wait for it to be running", func() {
+		printer.Clean()
+		pluginArg := "test-plugin"
+		defer func(interval time.Duration) { pluginHealthPollInterval = interval }(pluginHealthPollInterval)
+		pluginHealthPollInterval = time.Millisecond
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("wait-healthy", true, "")
+		cmd.Flags().Duration("wait-timeout", time.Minute, "")
+
+		s.client.
+			EXPECT().
+			EnablePlugin(pluginArg).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				GetPluginStatuses().
+				Return(model.PluginStatuses{
+					{PluginId: pluginArg, ClusterId: "node-1", State: model.PluginStateRunning},
+					{PluginId: pluginArg, ClusterId: "node-2", State: model.PluginStateNotRunning},
+				}, &model.Response{}, nil).
+				Times(1),
+			s.client.
+				EXPECT().
+				GetPluginStatuses().
+				Return(model.PluginStatuses{
+					{PluginId: pluginArg, ClusterId: "node-1", State: model.PluginStateRunning},
+					{PluginId: pluginArg, ClusterId: "node-2", State: model.PluginStateRunning},
+				}, &model.Response{}, nil).
+				Times(1),
+		)
+
+		err := pluginEnableCmdF(s.client, cmd, []string{pluginArg})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal("Enabled plugin: "+pluginArg, printer.GetLines()[0])
+		s.Require().Equal("Plugin "+pluginArg+" is running", printer.GetLines()[1])
+	})
+
+	s.Run("Enable a plugin that fails to start while waiting for it", func() {
+		printer.Clean()
+		pluginArg := "test-plugin"
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("wait-healthy", true, "")
+		cmd.Flags().Duration("wait-timeout", time.Minute, "")
+
+		s.client.
+			EXPECT().
+			EnablePlugin(pluginArg).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPluginStatuses().
+			Return(model.PluginStatuses{
+				{PluginId: pluginArg, ClusterId: "node-1", State: model.PluginStateFailedToStart},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginEnableCmdF(s.client, cmd, []string{pluginArg})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "plugin test-plugin failed to start on node-1")
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Len(printer.GetErrorLines(), 1)
+	})
+
+	s.Run("Time out waiting for a plugin to be running", func() {
+		printer.Clean()
+		pluginArg := "test-plugin"
+		defer func(interval time.Duration) { pluginHealthPollInterval = interval }(pluginHealthPollInterval)
+		pluginHealthPollInterval = time.Millisecond
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("wait-healthy", true, "")
+		cmd.Flags().Duration("wait-timeout", 0, "")
+
+		s.client.
+			EXPECT().
+			EnablePlugin(pluginArg).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPluginStatuses().
+			Return(model.PluginStatuses{}, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginEnableCmdF(s.client, cmd, []string{pluginArg})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "timed out after 0s waiting for plugin test-plugin to be running")
+		s.Require().Len(printer.GetErrorLines(), 1)
+	})
+}
+
+func writePluginBundle(s *MmctlUnitTestSuite, dir string, manifest *model.Manifest) string {
+	path := filepath.Join(dir, manifest.Id+"-"+manifest.Version+".tar.gz")
+	file, err := os.Create(path)
+	s.Require().NoError(err)
+	defer file.Close()
+
+	manifestJSON, err := json.Marshal(manifest)
+	s.Require().NoError(err)
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	s.Require().NoError(tarWriter.WriteHeader(&tar.Header{Name: manifest.Id + "/plugin.json", Mode: 0600, Size: int64(len(manifestJSON))}))
+	_, err = tarWriter.Write(manifestJSON)
+	s.Require().NoError(err)
+	s.Require().NoError(tarWriter.Close())
+	s.Require().NoError(gzipWriter.Close())
+
+	return path
+}
+
+func (s *MmctlUnitTestSuite) TestPluginDeployCmd() {
+	defer func(interval time.Duration) { pluginHealthPollInterval = interval }(pluginHealthPollInterval)
+	pluginHealthPollInterval = time.Millisecond
+
+	tmp, err := ioutil.TempDir("", "mmctl-")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmp)
+
+	manifest := &model.Manifest{Id: "com.example.plugin", Version: "1.1.0"}
+	bundle := writePluginBundle(s, tmp, manifest)
+	previous := &model.PluginInfo{Manifest: model.Manifest{Id: manifest.Id, Version: "1.0.0"}}
+
+	newCmd := func(rollback bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("rollback-on-failure", rollback, "")
+		cmd.Flags().String("previous-bundle", "", "")
+		cmd.Flags().Duration("wait-timeout", time.Minute, "")
+		return cmd
+	}
+
+	s.Run("Deploy a plugin that starts successfully", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{Active: []*model.PluginInfo{previous}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPluginStatuses().
+			Return(model.PluginStatuses{{PluginId: manifest.Id, Version: manifest.Version, State: model.PluginStateRunning}}, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginDeployCmdF(s.client, newCmd(true), []string{bundle})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal("Uploaded plugin com.example.plugin version 1.1.0", printer.GetLines()[0])
+		s.Require().Equal("Plugin com.example.plugin version 1.1.0 is running", printer.GetLines()[2])
+	})
+
+	s.Run("Wait for the servers still running the previous version", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{Active: []*model.PluginInfo{previous}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				GetPluginStatuses().
+				Return(model.PluginStatuses{
+					{PluginId: manifest.Id, ClusterId: "node-1", Version: manifest.Version, State: model.PluginStateRunning},
+					{PluginId: manifest.Id, ClusterId: "node-2", Version: previous.Version, State: model.PluginStateRunning},
+				}, &model.Response{}, nil).
+				Times(1),
+			s.client.
+				EXPECT().
+				GetPluginStatuses().
+				Return(model.PluginStatuses{
+					{PluginId: manifest.Id, ClusterId: "node-1", Version: manifest.Version, State: model.PluginStateRunning},
+					{PluginId: manifest.Id, ClusterId: "node-2", Version: manifest.Version, State: model.PluginStateRunning},
+				}, &model.Response{}, nil).
+				Times(1),
+		)
+
+		err := pluginDeployCmdF(s.client, newCmd(true), []string{bundle})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Fail to deploy a plugin if the previous version keeps running", func() {
+		printer.Clean()
+		cmd := newCmd(false)
+		// the timeout is shorter than the poll interval, so the statuses
+		// are only checked once
+		s.Require().NoError(cmd.Flags().Set("wait-timeout", "1ms"))
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{Active: []*model.PluginInfo{previous}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPluginStatuses().
+			Return(model.PluginStatuses{{PluginId: manifest.Id, Version: previous.Version, State: model.PluginStateRunning}}, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginDeployCmdF(s.client, cmd, []string{bundle})
+		s.Require().EqualError(err, "timed out after 1ms waiting for version 1.1.0 of plugin com.example.plugin to be running")
+	})
+
+	s.Run("Fail to deploy a plugin without rolling back", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{Active: []*model.PluginInfo{previous}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := pluginDeployCmdF(s.client, newCmd(false), []string{bundle})
+		s.Require().EqualError(err, "unable to enable plugin com.example.plugin: mock error")
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Roll back to the previous version from the marketplace", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{Active: []*model.PluginInfo{previous}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(2)
+
+		s.client.
+			EXPECT().
+			GetPluginStatuses().
+			Return(model.PluginStatuses{{PluginId: manifest.Id, ClusterId: "node-1", State: model.PluginStateFailedToStart}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			InstallMarketplacePlugin(&model.InstallMarketplacePluginRequest{Id: manifest.Id, Version: previous.Version}).
+			Return(&previous.Manifest, &model.Response{}, nil).
+			Times(1)
+
+		err := pluginDeployCmdF(s.client, newCmd(true), []string{bundle})
+		s.Require().EqualError(err, "plugin com.example.plugin failed to start on node-1")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal("Uploaded plugin com.example.plugin version 1.1.0", printer.GetLines()[0])
+		s.Require().Equal("Rolled back plugin com.example.plugin to version 1.0.0", printer.GetLines()[2])
+	})
+
+	s.Run("Remove the plugin on failure if there was no previous version", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPlugins().
+			Return(&model.PluginsResponse{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadPluginForced(gomock.AssignableToTypeOf(&os.File{})).
+			Return(manifest, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			EnablePlugin(manifest.Id).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DisablePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemovePlugin(manifest.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := pluginDeployCmdF(s.client, newCmd(true), []string{bundle})
+		s.Require().EqualError(err, "unable to enable plugin com.example.plugin: mock error")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Removed plugin com.example.plugin as no previous version was installed", printer.GetLines()[2])
+	})
+
+	s.Run("Fail with a bundle without manifest", func() {
+		printer.Clean()
+
+		tmpFile, err := ioutil.TempFile(tmp, "bundle")
+		s.Require().NoError(err)
+		tmpFile.Close()
+
+		err = pluginDeployCmdF(s.client, newCmd(true), []string{tmpFile.Name()})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "unable to read bundle")
+	})
+
+	s.Run("Fail before uploading if the previous bundle is missing", func() {
+		printer.Clean()
+		cmd := newCmd(true)
+		_ = cmd.Flags().Set("previous-bundle", filepath.Join(tmp, "missing.tar.gz"))
+
+		err := pluginDeployCmdF(s.client, cmd, []string{bundle})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "unable to use previous bundle")
+	})
+
+	s.Run("Fail before uploading if the previous bundle is of another plugin", func() {
+		printer.Clean()
+		other := writePluginBundle(s, tmp, &model.Manifest{Id: "com.example.other", Version: "1.0.0"})
+		cmd := newCmd(true)
+		_ = cmd.Flags().Set("previous-bundle", other)
+
+		err := pluginDeployCmdF(s.client, cmd, []string{bundle})
+		s.Require().EqualError(err, fmt.Sprintf("previous bundle %s is of plugin com.example.other instead of com.example.plugin", other))
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestPluginListCmd() {
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// policyFilePath is the location of the policy file an administrator
+// can deploy to restrict the commands of the local installation, e.g.
+// on shared jump hosts.
+//
+// The policy file looks like:
+//
+//	deny:
+//	  - command: user delete
+//	    flags: [confirm]
+//	    message: users are deleted through the offboarding process
+//	  - command: system restart
+//
+// A rule denies a command and its subcommands, and if flags are given,
+// only when all of them are set. The policy is a guardrail against
+// mistakes and not a security boundary, as it can be avoided by using a
+// different binary.
+var policyFilePath = "/etc/mmctl/policy.yaml"
+
+type policyRule struct {
+	Command string   `yaml:"command"`
+	Flags   []string `yaml:"flags"`
+	Message string   `yaml:"message"`
+}
+
+type policy struct {
+	Deny []policyRule `yaml:"deny"`
+}
+
+// loadPolicy reads the policy file, returning no policy if the file
+// doesn't exist. Any other error is returned so the commands are not
+// run with a policy that can't be enforced
+func loadPolicy(path string) (*policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the policy file")
+	}
+
+	var p policy
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return nil, errors.Wrapf(err, "invalid policy file %s", path)
+	}
+	for i, rule := range p.Deny {
+		if strings.TrimSpace(rule.Command) == "" {
+			return nil, fmt.Errorf("invalid policy file %s: rule %d has no command", path, i+1)
+		}
+	}
+
+	return &p, nil
+}
+
+func (r *policyRule) matches(cmd *cobra.Command) bool {
+	commandPath := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())
+	commandPath = strings.Join(strings.Fields(commandPath), " ")
+	ruleCommand := strings.Join(strings.Fields(r.Command), " ")
+	if commandPath != ruleCommand && !strings.HasPrefix(commandPath, ruleCommand+" ") {
+		return false
+	}
+
+	for _, flag := range r.Flags {
+		if !cmd.Flags().Changed(strings.TrimLeft(flag, "-")) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// check returns an error if any of the rules of the policy denies the
+// command
+func (p *policy) check(cmd *cobra.Command) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, rule := range p.Deny {
+		if !rule.matches(cmd) {
+			continue
+		}
+
+		denied := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+		for _, flag := range rule.Flags {
+			denied += " --" + strings.TrimLeft(flag, "-")
+		}
+		err := fmt.Errorf("%q is not allowed by the policy of this installation", denied)
+		if rule.Message != "" {
+			err = fmt.Errorf("%w: %s", err, rule.Message)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func checkPolicy(cmd *cobra.Command) error {
+	p, err := loadPolicy(policyFilePath)
+	if err != nil {
+		return err
+	}
+	return p.check(cmd)
+}
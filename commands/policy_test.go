@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	newCommandTree := func() (*cobra.Command, *cobra.Command, *cobra.Command) {
+		root := &cobra.Command{Use: "mmctl"}
+		user := &cobra.Command{Use: "user"}
+		deleteCmd := &cobra.Command{Use: "delete"}
+		deleteCmd.Flags().Bool("confirm", false, "")
+		system := &cobra.Command{Use: "system"}
+		restart := &cobra.Command{Use: "restart"}
+		root.AddCommand(user, system)
+		user.AddCommand(deleteCmd)
+		system.AddCommand(restart)
+		return deleteCmd, restart, user
+	}
+
+	writePolicy := func(t *testing.T, content string) string {
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(tmp) })
+
+		path := filepath.Join(tmp, "policy.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("Should not restrict anything without a policy file", func(t *testing.T) {
+		p, err := loadPolicy(filepath.Join(os.TempDir(), "non-existent-policy.yaml"))
+		require.NoError(t, err)
+		require.Nil(t, p)
+
+		deleteCmd, _, _ := newCommandTree()
+		require.NoError(t, p.check(deleteCmd))
+	})
+
+	t.Run("Should deny the commands and flags of the rules", func(t *testing.T) {
+		p, err := loadPolicy(writePolicy(t, `deny:
+  - command: user delete
+    flags: [--confirm]
+    message: users are deleted through the offboarding process
+  - command: system
+`))
+		require.NoError(t, err)
+
+		deleteCmd, restart, user := newCommandTree()
+		require.NoError(t, p.check(deleteCmd))
+		require.NoError(t, p.check(user))
+
+		require.NoError(t, deleteCmd.Flags().Set("confirm", "true"))
+		require.EqualError(t, p.check(deleteCmd), `"user delete --confirm" is not allowed by the policy of this installation: users are deleted through the offboarding process`)
+		require.EqualError(t, p.check(restart), `"system restart" is not allowed by the policy of this installation`)
+	})
+
+	t.Run("Should fail with an invalid policy file", func(t *testing.T) {
+		_, err := loadPolicy(writePolicy(t, "deny: [user delete"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid policy file")
+
+		path := writePolicy(t, "deny:\n  - flags: [confirm]\n")
+		_, err = loadPolicy(path)
+		require.EqualError(t, err, "invalid policy file "+path+": rule 1 has no command")
+	})
+}
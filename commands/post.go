@@ -6,8 +6,10 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/mattermost/mattermost-server/v6/model"
 
 	"github.com/mattermost/mmctl/v6/client"
@@ -33,15 +35,43 @@ var PostCreateCmd = &cobra.Command{
 var PostListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List posts for a channel",
+	Long: `List the last posts of a channel, oldest first.
+The posts can be filtered by time range, author and whether they are thread replies. When filtering, pages of posts are fetched until enough matching posts are found, or until the whole history is walked through with --all.`,
 	Example: `  post list myteam:mychannel
-  post list myteam:mychannel --number 20`,
+  post list myteam:mychannel --number 20
+
+  # extract all the posts of a user during a month, without replies
+  post list myteam:mychannel --all --since 2021-06-01T00:00:00+00:00 --until 2021-07-01T00:00:00+00:00 --author john.doe --exclude-replies`,
 	Args: cobra.ExactArgs(1),
 	RunE: withClient(postListCmdF),
 }
 
+var PostForwardCmd = &cobra.Command{
+	Use:   "forward [post] [channel]",
+	Short: "Forward a post to another channel",
+	Long: `Forward a post to another channel or direct message, as the webapp does. The forwarded post contains a permalink to the original one, which is rendered with the original author and message, and an optional comment.
+To forward the post to a direct message with a user, prefix the username with "@".`,
+	Example: `  post forward 1r7ab3w6ptbcipa9bs5quiqfbc myteam:mychannel
+  post forward 1r7ab3w6ptbcipa9bs5quiqfbc @john.doe --comment "can you have a look?"`,
+	Args: cobra.ExactArgs(2),
+	RunE: withClient(postForwardCmdF),
+}
+
+var PostDeleteCmd = &cobra.Command{
+	Use:   "delete [posts]",
+	Short: "Delete posts",
+	Long: `Delete posts by their ID.
+The replies of the posts that are the root of a thread are deleted too.`,
+	Example: `  post delete 1r7ab3w6ptbcipa9bs5quiqfbc --confirm
+
+  # check which posts would be deleted
+  post delete 1r7ab3w6ptbcipa9bs5quiqfbc 6e3y3ym8kbn53fpka9pzxqbouc --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(withPlan(postPlanResolver("delete_post"), postDeleteCmdF)),
+}
+
 const (
-	ISO8601Layout  = "2006-01-02T15:04:05-07:00"
-	PostTimeFormat = "2006-01-02 15:04:05-07:00"
+	ISO8601Layout = "2006-01-02T15:04:05-07:00"
 )
 
 func init() {
@@ -52,10 +82,21 @@ func init() {
 	PostListCmd.Flags().BoolP("show-ids", "i", false, "Show posts ids")
 	PostListCmd.Flags().BoolP("follow", "f", false, "Output appended data as new messages are posted to the channel")
 	PostListCmd.Flags().StringP("since", "s", "", "List messages posted after a certain time (ISO 8601)")
+	PostListCmd.Flags().String("until", "", "List messages posted before a certain time (ISO 8601)")
+	PostListCmd.Flags().StringSlice("author", []string{}, "List only the messages of these users")
+	PostListCmd.Flags().Bool("all", false, "List all the matching messages instead of the number given with --number")
+	PostListCmd.Flags().Bool("exclude-replies", false, "Exclude the replies of threads")
+
+	PostForwardCmd.Flags().StringP("comment", "m", "", "Comment to add to the forwarded post")
+
+	PostDeleteCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the posts")
+	addPlanFlags(PostDeleteCmd)
 
 	PostCmd.AddCommand(
 		PostCreateCmd,
 		PostListCmd,
+		PostForwardCmd,
+		PostDeleteCmd,
 	)
 
 	RootCmd.AddCommand(PostCmd)
@@ -101,6 +142,86 @@ func postCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func postForwardCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	comment, _ := cmd.Flags().GetString("comment")
+
+	post, _, err := c.GetPost(args[0], "")
+	if err != nil {
+		return fmt.Errorf("unable to find post %q: %w", args[0], err)
+	}
+
+	target, err := getForwardTargetChannel(c, args[1])
+	if err != nil {
+		return err
+	}
+
+	permalink, err := getPostPermalink(c, post, target)
+	if err != nil {
+		return err
+	}
+
+	message := permalink
+	if comment != "" {
+		message = comment + "\n" + permalink
+	}
+
+	newPost, _, err := c.CreatePost(&model.Post{
+		ChannelId: target.Id,
+		Message:   message,
+	})
+	if err != nil {
+		return fmt.Errorf("could not forward post: %w", err)
+	}
+
+	printer.PrintT("Post forwarded as {{.Id}}", newPost)
+	return nil
+}
+
+// getForwardTargetChannel resolves the channel a post is forwarded
+// to, which is a direct channel with the current user if the argument
+// is a username prefixed with "@"
+func getForwardTargetChannel(c client.Client, channelArg string) (*model.Channel, error) {
+	if !strings.HasPrefix(channelArg, "@") {
+		channel := getChannelFromChannelArg(c, channelArg)
+		if channel == nil {
+			return nil, errors.New("Unable to find channel '" + channelArg + "'")
+		}
+		return channel, nil
+	}
+
+	return getDMChannel(c, []string{strings.TrimPrefix(channelArg, "@")})
+}
+
+// getPostPermalink builds the permalink of a post. Posts of direct
+// channels don't belong to any team, so the team of the target
+// channel is used for them
+func getPostPermalink(c client.Client, post *model.Post, target *model.Channel) (string, error) {
+	channel, _, err := c.GetChannel(post.ChannelId, "")
+	if err != nil {
+		return "", fmt.Errorf("unable to get the channel of the post: %w", err)
+	}
+
+	teamID := channel.TeamId
+	if teamID == "" {
+		teamID = target.TeamId
+	}
+	if teamID == "" {
+		return "", errors.New("unable to forward a post from a direct channel to another direct channel")
+	}
+
+	team, _, err := c.GetTeam(teamID, "")
+	if err != nil {
+		return "", fmt.Errorf("unable to get the team of the post: %w", err)
+	}
+
+	clientConfig, _, err := c.GetOldClientConfig("")
+	if err != nil {
+		return "", fmt.Errorf("unable to get the site URL: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s/pl/%s", strings.TrimSuffix(clientConfig["SiteURL"], "/"), team.Name, post.Id), nil
+}
+
 func eventDataToPost(eventData map[string]interface{}) (*model.Post, error) {
 	post := &model.Post{}
 	var rawPost string
@@ -132,8 +253,7 @@ func printPost(c client.Client, post *model.Post, usernames map[string]string, s
 		}
 	}
 
-	postTime := model.GetTimeForMillis(post.CreateAt)
-	createdAt := postTime.Format(PostTimeFormat)
+	createdAt := printer.FormatTimestamp(post.CreateAt)
 
 	if showTimestamp {
 		printer.PrintT(fmt.Sprintf("\u001b[32m%s\u001b[0m \u001b[34;1m[%s]\u001b[0m {{.Message}}", createdAt, username), post)
@@ -160,6 +280,75 @@ func getPostList(client client.Client, channelID, since string, perPage int) (*m
 	return client.GetPostsSince(channelID, sinceTimeMillis, false)
 }
 
+// postListFilter holds the criteria of the posts to list when
+// filtering the history of a channel
+type postListFilter struct {
+	since          int64
+	until          int64
+	authors        map[string]bool
+	excludeReplies bool
+}
+
+func (f *postListFilter) matches(post *model.Post) bool {
+	if f.since > 0 && post.CreateAt < f.since {
+		return false
+	}
+	if f.until > 0 && post.CreateAt > f.until {
+		return false
+	}
+	if len(f.authors) > 0 && !f.authors[post.UserId] {
+		return false
+	}
+	if f.excludeReplies && post.RootId != "" {
+		return false
+	}
+	return true
+}
+
+func parsePostListTime(name, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse(ISO8601Layout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s time '%s'", name, value)
+	}
+	return model.GetMillisForTime(t), nil
+}
+
+// getFilteredPosts walks through the pages of posts of a channel,
+// newest first, until the given number of matching posts is found or
+// the posts are older than the start of the filter. A negative number
+// returns all the matching posts
+func getFilteredPosts(c client.Client, channelID string, filter *postListFilter, number int) ([]*model.Post, error) {
+	posts := []*model.Post{}
+	for page := 0; ; page++ {
+		postList, _, err := c.GetPostsForChannel(channelID, page, APILimitMaximum, "", false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch posts")
+		}
+
+		pagePosts := postList.ToSlice()
+		for _, post := range pagePosts {
+			if filter.since > 0 && post.CreateAt < filter.since {
+				return posts, nil
+			}
+			if !filter.matches(post) {
+				continue
+			}
+			posts = append(posts, post)
+			if number >= 0 && len(posts) == number {
+				return posts, nil
+			}
+		}
+
+		if len(pagePosts) < APILimitMaximum {
+			return posts, nil
+		}
+	}
+}
+
 func postListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	printer.SetSingle(true)
 
@@ -172,15 +361,46 @@ func postListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	showIds, _ := cmd.Flags().GetBool("show-ids")
 	follow, _ := cmd.Flags().GetBool("follow")
 	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	authors, _ := cmd.Flags().GetStringSlice("author")
+	all, _ := cmd.Flags().GetBool("all")
+	excludeReplies, _ := cmd.Flags().GetBool("exclude-replies")
 
-	postList, _, err := getPostList(c, channel.Id, since, number)
-	if err != nil {
-		return err
+	usernames := map[string]string{}
+	filter := &postListFilter{authors: map[string]bool{}, excludeReplies: excludeReplies}
+	for _, author := range authors {
+		user, err := getUserFromArg(c, author)
+		if err != nil {
+			return err
+		}
+		filter.authors[user.Id] = true
+		usernames[user.Id] = user.Username
 	}
 
-	posts := postList.ToSlice()
-	showTimestamp := len(since) > 0
-	usernames := map[string]string{}
+	var posts []*model.Post
+	if until == "" && len(authors) == 0 && !all && !excludeReplies {
+		postList, _, err := getPostList(c, channel.Id, since, number)
+		if err != nil {
+			return err
+		}
+		posts = postList.ToSlice()
+	} else {
+		var err error
+		if filter.since, err = parsePostListTime("since", since); err != nil {
+			return err
+		}
+		if filter.until, err = parsePostListTime("until", until); err != nil {
+			return err
+		}
+		if all {
+			number = -1
+		}
+		if posts, err = getFilteredPosts(c, channel.Id, filter, number); err != nil {
+			return err
+		}
+	}
+
+	showTimestamp := len(since) > 0 || len(until) > 0
 	for i := 1; i <= len(posts); i++ {
 		post := posts[len(posts)-i]
 		printPost(c, post, usernames, showIds, showTimestamp)
@@ -205,7 +425,7 @@ func postListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 				if err != nil {
 					fmt.Println("Error parsing incoming post: " + err.Error())
 				}
-				if post.ChannelId == channel.Id {
+				if post.ChannelId == channel.Id && filter.matches(post) {
 					printPost(c, post, usernames, showIds, showTimestamp)
 				}
 			}
@@ -213,3 +433,32 @@ func postListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// postSummary returns the beginning of the message of a post, to tell
+// which post it is in plans
+func postSummary(post *model.Post) string {
+	summary := strings.Join(strings.Fields(post.Message), " ")
+	if runes := []rune(summary); len(runes) > 50 {
+		summary = string(runes[:50]) + "..."
+	}
+	return summary
+}
+
+func postDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getConfirmation("Are you sure you want to delete the posts specified?", false); err != nil {
+			return err
+		}
+	}
+
+	var result *multierror.Error
+	for _, postID := range args {
+		if _, err := c.DeletePost(postID); err != nil {
+			result = multierror.Append(result, fmt.Errorf("unable to delete post %q: %w", postID, err))
+			continue
+		}
+		printer.PrintT("Deleted post {{.}}", postID)
+	}
+	return result.ErrorOrNil()
+}
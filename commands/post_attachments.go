@@ -0,0 +1,240 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const attachmentsManifestFileName = "manifest.csv"
+
+var PostAttachmentsCmd = &cobra.Command{
+	Use:   "attachments",
+	Short: "Management of the files attached to posts",
+}
+
+var PostAttachmentsDownloadCmd = &cobra.Command{
+	Use:   "download",
+	Short: "Download the files attached to the posts of a channel",
+	Long: `Download the files attached to the posts of a channel, oldest first, to a directory with their original names. A name already taken in the directory, or the name of the manifest, gets a number appended.
+A manifest.csv file is written to the directory with the post, the author, the time, the size and the SHA-256 digest of each file as it's downloaded, so the download can be checked later on, also if it's interrupted.`,
+	Example: `  post attachments download --channel myteam:mychannel --output-dir evidence
+  post attachments download --channel myteam:mychannel --since 2026-01-01T00:00:00+00:00 --output-dir backup-2026`,
+	Args: cobra.NoArgs,
+	RunE: withClient(postAttachmentsDownloadCmdF),
+}
+
+func init() {
+	PostAttachmentsDownloadCmd.Flags().String("channel", "", "Channel to download the attachments of")
+	_ = PostAttachmentsDownloadCmd.MarkFlagRequired("channel")
+	PostAttachmentsDownloadCmd.Flags().String("since", "", "Only download the files posted after a certain time (ISO 8601)")
+	PostAttachmentsDownloadCmd.Flags().String("until", "", "Only download the files posted before a certain time (ISO 8601)")
+	PostAttachmentsDownloadCmd.Flags().String("output-dir", "attachments", "Directory to download the files to")
+
+	PostAttachmentsCmd.AddCommand(PostAttachmentsDownloadCmd)
+	PostCmd.AddCommand(PostAttachmentsCmd)
+}
+
+type attachmentDownload struct {
+	FileID   string `json:"file_id"`
+	PostID   string `json:"post_id"`
+	Author   string `json:"author"`
+	PostedAt int64  `json:"posted_at"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+}
+
+// attachmentFileName returns a name for the file in the directory that
+// isn't taken yet, keeping the original one when possible. The names
+// are reduced to their base so that they can't point outside of it, and
+// the name of the manifest is always taken, compared regardless of the
+// case for the case-insensitive file systems
+func attachmentFileName(dir string, info *model.FileInfo) string {
+	name := filepath.Base(filepath.FromSlash(info.Name))
+	if name == "." || name == string(filepath.Separator) || name == ".." {
+		name = info.Id
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, candidate)); os.IsNotExist(err) && !strings.EqualFold(candidate, attachmentsManifestFileName) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
+
+// attachmentsManifest is the CSV manifest of the downloaded files,
+// where each file is written as soon as it's downloaded, so the
+// manifest lists the files of the directory even if the download is
+// interrupted
+type attachmentsManifest struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func createAttachmentsManifest(path string) (*attachmentsManifest, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the manifest")
+	}
+	manifest := &attachmentsManifest{f: f, w: csv.NewWriter(f)}
+	if err := manifest.writeRecord([]string{"file_id", "post_id", "author", "posted_at", "name", "path", "size", "mime_type", "sha256"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (m *attachmentsManifest) writeRecord(record []string) error {
+	if err := m.w.Write(record); err != nil {
+		return errors.Wrap(err, "failed to write the manifest")
+	}
+	m.w.Flush()
+	if err := m.w.Error(); err != nil {
+		return errors.Wrap(err, "failed to write the manifest")
+	}
+	return nil
+}
+
+func (m *attachmentsManifest) write(d *attachmentDownload) error {
+	return m.writeRecord([]string{d.FileID, d.PostID, d.Author, printer.FormatTimestamp(d.PostedAt), d.Name, d.Path, strconv.FormatInt(d.Size, 10), d.MimeType, d.SHA256})
+}
+
+func (m *attachmentsManifest) close() error {
+	if err := m.f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the manifest")
+	}
+	return nil
+}
+
+func downloadAttachment(c client.Client, dir string, info *model.FileInfo) (string, string, error) {
+	data, _, err := c.GetFile(info.Id)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to download the file")
+	}
+
+	name := attachmentFileName(dir, info)
+	if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return "", "", errors.Wrap(err, "failed to save the file")
+	}
+	return name, fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+func postAttachmentsDownloadCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channelArg, _ := cmd.Flags().GetString("channel")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	dir, _ := cmd.Flags().GetString("output-dir")
+
+	channel := getChannelFromChannelArg(c, channelArg)
+	if channel == nil {
+		return errors.New("Unable to find channel '" + channelArg + "'")
+	}
+
+	filter := &postListFilter{}
+	var err error
+	if filter.since, err = parsePostListTime("since", since); err != nil {
+		return err
+	}
+	if filter.until, err = parsePostListTime("until", until); err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join(dir, attachmentsManifestFileName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return fmt.Errorf("the directory %s already contains a manifest, use another one", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create the output directory")
+	}
+
+	posts, err := getFilteredPosts(c, channel.Id, filter, -1)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := createAttachmentsManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer manifest.close()
+
+	usernames := map[string]string{}
+	failed := 0
+	// the posts are fetched newest first
+	for i := len(posts) - 1; i >= 0; i-- {
+		post := posts[i]
+		if len(post.FileIds) == 0 {
+			continue
+		}
+
+		infos, _, err := c.GetFileInfosForPost(post.Id, "")
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to get the files of post %s: %s", post.Id, err))
+			failed += len(post.FileIds)
+			continue
+		}
+
+		if _, ok := usernames[post.UserId]; !ok {
+			usernames[post.UserId] = post.UserId
+			if user, _, err := c.GetUser(post.UserId, ""); err == nil {
+				usernames[post.UserId] = user.Username
+			}
+		}
+
+		for _, info := range infos {
+			name, digest, err := downloadAttachment(c, dir, info)
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("Unable to download file %s of post %s: %s", info.Id, post.Id, err))
+				failed++
+				continue
+			}
+
+			download := &attachmentDownload{
+				FileID:   info.Id,
+				PostID:   post.Id,
+				Author:   usernames[post.UserId],
+				PostedAt: post.CreateAt,
+				Name:     info.Name,
+				Path:     name,
+				Size:     info.Size,
+				MimeType: info.MimeType,
+				SHA256:   digest,
+			}
+			if err := manifest.write(download); err != nil {
+				return err
+			}
+			printer.PrintT("Downloaded {{.Name}} to {{.Path}}", download)
+		}
+	}
+
+	if err := manifest.close(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to download %d files", failed)
+	}
+	return nil
+}
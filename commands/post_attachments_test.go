@@ -0,0 +1,235 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPostAttachmentsDownloadCmd() {
+	newCmd := func(channel, since, dir string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("channel", channel, "")
+		cmd.Flags().String("since", since, "")
+		cmd.Flags().String("until", "", "")
+		cmd.Flags().String("output-dir", dir, "")
+		return cmd
+	}
+
+	channel := &model.Channel{Id: "channel-id", Name: "mychannel"}
+	user := &model.User{Id: "user-id", Username: "john.doe"}
+
+	s.Run("Should download the attachments of a channel with a manifest", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		dir := filepath.Join(tmp, "evidence")
+
+		postList := model.NewPostList()
+		for _, post := range []*model.Post{
+			{Id: "post-2", UserId: user.Id, CreateAt: 2000, FileIds: model.StringArray{"file-2"}},
+			{Id: "post-text", UserId: user.Id, CreateAt: 1500, Message: "no files"},
+			{Id: "post-1", UserId: user.Id, CreateAt: 1000, FileIds: model.StringArray{"file-1"}},
+		} {
+			postList.AddPost(post)
+			postList.AddOrder(post.Id)
+		}
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(2)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		// both files have the same name, and the second one can't
+		// escape the directory
+		s.client.
+			EXPECT().
+			GetFileInfosForPost("post-1", "").
+			Return([]*model.FileInfo{{Id: "file-1", Name: "report.pdf", Size: 5, MimeType: "application/pdf"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFileInfosForPost("post-2", "").
+			Return([]*model.FileInfo{{Id: "file-2", Name: "../report.pdf", Size: 6, MimeType: "application/pdf"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(user.Id, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile("file-1").
+			Return([]byte("first"), &model.Response{}, nil).
+			Times(1)
+
+		// the downloaded files are in the manifest before the next one
+		s.client.
+			EXPECT().
+			GetFile("file-2").
+			DoAndReturn(func(fileID string) ([]byte, *model.Response, error) {
+				manifest, err := ioutil.ReadFile(filepath.Join(dir, attachmentsManifestFileName))
+				s.Require().NoError(err)
+				s.Require().Contains(string(manifest), "file-1,post-1,john.doe,")
+				return []byte("second"), &model.Response{}, nil
+			}).
+			Times(1)
+
+		err := postAttachmentsDownloadCmdF(s.client, newCmd(channel.Id, "", dir), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal("report (1).pdf", printer.GetLines()[1].(*attachmentDownload).Path)
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, "report.pdf"))
+		s.Require().NoError(err)
+		s.Require().Equal("first", string(b))
+		b, err = ioutil.ReadFile(filepath.Join(dir, "report (1).pdf"))
+		s.Require().NoError(err)
+		s.Require().Equal("second", string(b))
+
+		manifest, err := ioutil.ReadFile(filepath.Join(dir, attachmentsManifestFileName))
+		s.Require().NoError(err)
+		s.Require().Contains(string(manifest), "file_id,post_id,author,posted_at,name,path,size,mime_type,sha256\n")
+		s.Require().Contains(string(manifest), fmt.Sprintf(",report (1).pdf,6,application/pdf,%x\n", sha256.Sum256([]byte("second"))))
+
+		err = postAttachmentsDownloadCmdF(s.client, newCmd(channel.Id, "", dir), []string{})
+		s.Require().EqualError(err, fmt.Sprintf("the directory %s already contains a manifest, use another one", dir))
+	})
+
+	s.Run("Should report the files that fail to download", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		postList := model.NewPostList()
+		post := &model.Post{Id: "post-1", UserId: user.Id, CreateAt: 1000, FileIds: model.StringArray{"file-1"}}
+		postList.AddPost(post)
+		postList.AddOrder(post.Id)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFileInfosForPost("post-1", "").
+			Return([]*model.FileInfo{{Id: "file-1", Name: "report.pdf"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(user.Id, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile("file-1").
+			Return(nil, &model.Response{}, fmt.Errorf("not found")).
+			Times(1)
+
+		err := postAttachmentsDownloadCmdF(s.client, newCmd(channel.Id, "", tmp), []string{})
+		s.Require().EqualError(err, "unable to download 1 files")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().FileExists(filepath.Join(tmp, attachmentsManifestFileName))
+	})
+
+	s.Run("Should not overwrite the manifest with an attachment of the same name", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+
+		postList := model.NewPostList()
+		post := &model.Post{Id: "post-1", UserId: user.Id, CreateAt: 1000, FileIds: model.StringArray{"file-1", "file-2"}}
+		postList.AddPost(post)
+		postList.AddOrder(post.Id)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFileInfosForPost("post-1", "").
+			Return([]*model.FileInfo{{Id: "file-1", Name: attachmentsManifestFileName}, {Id: "file-2", Name: "MANIFEST.csv"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(user.Id, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile("file-1").
+			Return([]byte("attached"), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile("file-2").
+			Return([]byte("ATTACHED"), &model.Response{}, nil).
+			Times(1)
+
+		err := postAttachmentsDownloadCmdF(s.client, newCmd(channel.Id, "", tmp), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal("manifest (1).csv", printer.GetLines()[0].(*attachmentDownload).Path)
+		s.Require().Equal("MANIFEST (1).csv", printer.GetLines()[1].(*attachmentDownload).Path)
+
+		b, err := ioutil.ReadFile(filepath.Join(tmp, "manifest (1).csv"))
+		s.Require().NoError(err)
+		s.Require().Equal("attached", string(b))
+		manifest, err := ioutil.ReadFile(filepath.Join(tmp, attachmentsManifestFileName))
+		s.Require().NoError(err)
+		s.Require().Contains(string(manifest), "file_id,post_id,author,posted_at,name,path,size,mime_type,sha256\n")
+	})
+
+	s.Run("Should fail with an invalid since time", func() {
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		err := postAttachmentsDownloadCmdF(s.client, newCmd(channel.Id, "yesterday", ""), []string{})
+		s.Require().EqualError(err, "invalid since time 'yesterday'")
+	})
+}
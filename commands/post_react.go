@@ -0,0 +1,148 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var PostReactCmd = &cobra.Command{
+	Use:   "react",
+	Short: "Management of the reactions of posts",
+}
+
+var PostReactAddCmd = &cobra.Command{
+	Use:   "add [posts]",
+	Short: "Add a reaction to posts",
+	Long: `Add a reaction with an emoji to posts, as the authenticated user. The posts can be given as arguments and, with --file, read from a file containing one post id per line.
+Posts that already have the reaction of the user are left as they are.`,
+	Example: `  post react add 1r7ab3w6ptbcipa9bs5quiqfbc --emoji white_check_mark
+  post react add --emoji white_check_mark --file processed-posts.txt`,
+	RunE: withClient(postReactAddCmdF),
+}
+
+var PostReactRemoveCmd = &cobra.Command{
+	Use:   "remove [posts]",
+	Short: "Remove a reaction from posts",
+	Long:  `Remove the reaction of the authenticated user with an emoji from posts. The posts can be given as arguments and, with --file, read from a file containing one post id per line.`,
+	Example: `  post react remove 1r7ab3w6ptbcipa9bs5quiqfbc --emoji white_check_mark
+  post react remove --emoji eyes --file posts.txt`,
+	RunE: withClient(postReactRemoveCmdF),
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{PostReactAddCmd, PostReactRemoveCmd} {
+		cmd.Flags().StringP("emoji", "e", "", "Name of the emoji of the reaction, with or without colons")
+		_ = cmd.MarkFlagRequired("emoji")
+		cmd.Flags().String("file", "", "File containing the ids of the posts, one per line")
+	}
+
+	PostReactCmd.AddCommand(
+		PostReactAddCmd,
+		PostReactRemoveCmd,
+	)
+
+	PostCmd.AddCommand(PostReactCmd)
+}
+
+// getReactionPostIDs returns the post ids of the arguments followed by
+// the ones of the file, skipping the empty lines and duplicates
+func getReactionPostIDs(args []string, path string) ([]string, error) {
+	ids := []string{}
+	seen := map[string]bool{}
+	add := func(id string) {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, arg := range args {
+		add(arg)
+	}
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open the posts file")
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			add(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to read the posts file")
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("at least one post must be given as an argument or with --file")
+	}
+	return ids, nil
+}
+
+type postReactionFunc func(c client.Client, reaction *model.Reaction) error
+
+func postReactCmdF(c client.Client, cmd *cobra.Command, args []string, action, done string, react postReactionFunc) error {
+	emoji, _ := cmd.Flags().GetString("emoji")
+	path, _ := cmd.Flags().GetString("file")
+
+	emoji = strings.Trim(emoji, ":")
+	if emoji == "" {
+		return errors.New("emoji cannot be empty")
+	}
+
+	postIDs, err := getReactionPostIDs(args, path)
+	if err != nil {
+		return err
+	}
+
+	me, _, err := c.GetMe("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the authenticated user")
+	}
+
+	failed := 0
+	for _, postID := range postIDs {
+		reaction := &model.Reaction{UserId: me.Id, PostId: postID, EmojiName: emoji}
+		if err := react(c, reaction); err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to %s reaction :%s: on post %s: %s", action, emoji, postID, err))
+			failed++
+			continue
+		}
+		printer.PrintT("Reaction :{{.EmojiName}}: "+done+" post {{.PostId}}", reaction)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to %s the reaction on %d posts", action, failed)
+	}
+	return nil
+}
+
+func postReactAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return postReactCmdF(c, cmd, args, "add", "added to", func(c client.Client, reaction *model.Reaction) error {
+		_, _, err := c.SaveReaction(reaction)
+		return err
+	})
+}
+
+func postReactRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return postReactCmdF(c, cmd, args, "remove", "removed from", func(c client.Client, reaction *model.Reaction) error {
+		_, err := c.DeleteReaction(reaction)
+		return err
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPostReactCmd() {
+	newCmd := func(emoji, file string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("emoji", emoji, "")
+		cmd.Flags().String("file", file, "")
+		return cmd
+	}
+	me := &model.User{Id: "user-id"}
+
+	s.Run("Should add the reaction to the posts of the arguments and the file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		file := filepath.Join(tmp, "posts.txt")
+		s.Require().NoError(ioutil.WriteFile(file, []byte("post-2\n\npost-1\npost-3\n"), 0600))
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(me, &model.Response{}, nil).
+			Times(1)
+
+		for _, postID := range []string{"post-1", "post-2", "post-3"} {
+			reaction := &model.Reaction{UserId: me.Id, PostId: postID, EmojiName: "white_check_mark"}
+			s.client.
+				EXPECT().
+				SaveReaction(reaction).
+				Return(reaction, &model.Response{}, nil).
+				Times(1)
+		}
+
+		err := postReactAddCmdF(s.client, newCmd(":white_check_mark:", file), []string{"post-1", "post-2"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal(&model.Reaction{UserId: me.Id, PostId: "post-3", EmojiName: "white_check_mark"}, printer.GetLines()[2])
+	})
+
+	s.Run("Should remove the reaction and report the failures", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(me, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteReaction(&model.Reaction{UserId: me.Id, PostId: "post-1", EmojiName: "eyes"}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteReaction(&model.Reaction{UserId: me.Id, PostId: "post-2", EmojiName: "eyes"}).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := postReactRemoveCmdF(s.client, newCmd("eyes", ""), []string{"post-1", "post-2"})
+		s.Require().EqualError(err, "unable to remove the reaction on 1 posts")
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("Unable to remove reaction :eyes: on post post-2: mock error", printer.GetErrorLines()[0])
+	})
+
+	s.Run("Should fail without posts", func() {
+		err := postReactAddCmdF(s.client, newCmd("eyes", ""), []string{})
+		s.Require().EqualError(err, "at least one post must be given as an argument or with --file")
+	})
+
+	s.Run("Should fail without emoji", func() {
+		err := postReactAddCmdF(s.client, newCmd("::", ""), []string{"post-1"})
+		s.Require().EqualError(err, "emoji cannot be empty")
+	})
+}
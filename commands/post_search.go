@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const postSearchDateLayout = "2006-01-02"
+
+var PostSearchCmd = &cobra.Command{
+	Use:   "search [team] [terms]",
+	Short: "Search posts",
+	Long: `Search the posts of a team with the search syntax of the server, as the webapp does. The terms can contain quoted phrases, "-" to exclude words, "*" as a wildcard suffix and hashtags, and the --from, --in, --after and --before flags are added to them as the "from:", "in:", "after:" and "before:" modifiers.
+The results are returned newest first, --limit at a time, so the following ones are fetched with --page. The dates are in UTC.`,
+	Example: `  post search myteam "release notes"
+  post search myteam deploy --from john.doe --in town-square --after 2026-01-01
+
+  # the second page of results
+  post search myteam incident --limit 50 --page 1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(postSearchCmdF),
+}
+
+func init() {
+	PostSearchCmd.Flags().StringSlice("from", []string{}, "Only return the posts of these users")
+	PostSearchCmd.Flags().StringSlice("in", []string{}, "Only return the posts of these channels, by name")
+	PostSearchCmd.Flags().String("after", "", "Only return the posts created after this day, in YYYY-MM-DD format")
+	PostSearchCmd.Flags().String("before", "", "Only return the posts created before this day, in YYYY-MM-DD format")
+	PostSearchCmd.Flags().Bool("or", false, "Return the posts that match any of the terms instead of all of them")
+	PostSearchCmd.Flags().Int("limit", 20, "Number of posts to return")
+	PostSearchCmd.Flags().Int("page", 0, "Page of results to return, starting at 0")
+	PostSearchCmd.Flags().Bool("include-archived", false, "Include the posts of archived channels")
+
+	PostCmd.AddCommand(PostSearchCmd)
+}
+
+type postSearchResult struct {
+	ID       string `json:"id"`
+	Channel  string `json:"channel"`
+	Author   string `json:"author"`
+	CreateAt int64  `json:"create_at"`
+	Message  string `json:"message"`
+}
+
+// buildPostSearchTerms adds the flags to the search terms as the
+// modifiers of the server search syntax
+func buildPostSearchTerms(c client.Client, cmd *cobra.Command, terms []string) (string, error) {
+	from, _ := cmd.Flags().GetStringSlice("from")
+	in, _ := cmd.Flags().GetStringSlice("in")
+
+	for _, userArg := range from {
+		user, err := getUserFromArg(c, userArg)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, "from:"+user.Username)
+	}
+	for _, channelName := range in {
+		terms = append(terms, "in:"+strings.TrimPrefix(channelName, "~"))
+	}
+	for _, modifier := range []string{"after", "before"} {
+		day, _ := cmd.Flags().GetString(modifier)
+		if day == "" {
+			continue
+		}
+		if _, err := time.Parse(postSearchDateLayout, day); err != nil {
+			return "", fmt.Errorf("invalid %s day '%s', expected YYYY-MM-DD", modifier, day)
+		}
+		terms = append(terms, modifier+":"+day)
+	}
+
+	return strings.Join(terms, " "), nil
+}
+
+func postSearchCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	isOrSearch, _ := cmd.Flags().GetBool("or")
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+	includeArchived, _ := cmd.Flags().GetBool("include-archived")
+
+	if limit < 1 {
+		return errors.New("the limit must be greater than 0")
+	}
+	if page < 0 {
+		return errors.New("the page cannot be negative")
+	}
+
+	team := getTeamFromTeamArg(c, args[0])
+	if team == nil {
+		return errors.New("Unable to find team '" + args[0] + "'")
+	}
+
+	terms, err := buildPostSearchTerms(c, cmd, args[1:])
+	if err != nil {
+		return err
+	}
+	if terms == "" {
+		return errors.New("either search terms or a --from, --in, --after or --before flag must be given")
+	}
+
+	timeZoneOffset := 0
+	postList, _, err := c.SearchPostsWithParams(team.Id, &model.SearchParameter{
+		Terms:                  &terms,
+		IsOrSearch:             &isOrSearch,
+		TimeZoneOffset:         &timeZoneOffset,
+		Page:                   &page,
+		PerPage:                &limit,
+		IncludeDeletedChannels: &includeArchived,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to search the posts")
+	}
+
+	channels := map[string]string{}
+	usernames := map[string]string{}
+	for _, postID := range postList.Order {
+		post, ok := postList.Posts[postID]
+		if !ok {
+			continue
+		}
+
+		if _, ok := channels[post.ChannelId]; !ok {
+			channels[post.ChannelId] = post.ChannelId
+			if channel, _, err := c.GetChannel(post.ChannelId, ""); err == nil {
+				channels[post.ChannelId] = channel.Name
+			}
+		}
+		if _, ok := usernames[post.UserId]; !ok {
+			usernames[post.UserId] = post.UserId
+			if user, _, err := c.GetUser(post.UserId, ""); err == nil {
+				usernames[post.UserId] = user.Username
+			}
+		}
+
+		printer.PrintT("{{.ID}} {{timestamp .CreateAt}} ~{{.Channel}} [{{.Author}}] {{.Message}}", &postSearchResult{
+			ID:       post.Id,
+			Channel:  channels[post.ChannelId],
+			Author:   usernames[post.UserId],
+			CreateAt: post.CreateAt,
+			Message:  post.Message,
+		})
+	}
+
+	if len(postList.Order) == limit {
+		printer.PrintWarning(fmt.Sprintf("there may be more results, use --page %d to fetch them", page+1))
+	}
+
+	return nil
+}
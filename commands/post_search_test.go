@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestPostSearchCmd() {
+	newCmd := func(from, in []string, after string, limit, page int) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("from", from, "")
+		cmd.Flags().StringSlice("in", in, "")
+		cmd.Flags().String("after", after, "")
+		cmd.Flags().String("before", "", "")
+		cmd.Flags().Bool("or", false, "")
+		cmd.Flags().Int("limit", limit, "")
+		cmd.Flags().Int("page", page, "")
+		cmd.Flags().Bool("include-archived", false, "")
+		return cmd
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	user := &model.User{Id: "user-id", Username: "john.doe", Email: "john@example.com"}
+	channel := &model.Channel{Id: "channel-id", Name: "town-square"}
+	first := &model.Post{Id: "first-id", ChannelId: channel.Id, UserId: user.Id, CreateAt: 2, Message: "deploy done"}
+	second := &model.Post{Id: "second-id", ChannelId: channel.Id, UserId: user.Id, CreateAt: 1, Message: "deploy started"}
+
+	expectTeam := func() {
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should search the posts with the modifiers of the flags", func() {
+		printer.Clean()
+		expectTeam()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		terms := "deploy from:john.doe in:town-square after:2026-01-01"
+		isOrSearch, timeZoneOffset, page, perPage, includeArchived := false, 0, 1, 2, false
+		s.client.
+			EXPECT().
+			SearchPostsWithParams(team.Id, &model.SearchParameter{
+				Terms:                  &terms,
+				IsOrSearch:             &isOrSearch,
+				TimeZoneOffset:         &timeZoneOffset,
+				Page:                   &page,
+				PerPage:                &perPage,
+				IncludeDeletedChannels: &includeArchived,
+			}).
+			Return(&model.PostList{
+				Order: []string{first.Id, second.Id},
+				Posts: map[string]*model.Post{first.Id: first, second.Id: second},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(user.Id, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		cmd := newCmd([]string{user.Email}, []string{"~town-square"}, "2026-01-01", 2, 1)
+		err := postSearchCmdF(s.client, cmd, []string{team.Name, "deploy"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&postSearchResult{ID: first.Id, Channel: channel.Name, Author: user.Username, CreateAt: first.CreateAt, Message: first.Message},
+			&postSearchResult{ID: second.Id, Channel: channel.Name, Author: user.Username, CreateAt: second.CreateAt, Message: second.Message},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the search fails", func() {
+		printer.Clean()
+		expectTeam()
+
+		s.client.
+			EXPECT().
+			SearchPostsWithParams(team.Id, gomock.Any()).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := postSearchCmdF(s.client, newCmd(nil, nil, "", 20, 0), []string{team.Name, "deploy"})
+		s.Require().EqualError(err, "failed to search the posts: mock error")
+	})
+
+	s.Run("Should fail with an invalid day or without terms", func() {
+		expectTeam()
+		err := postSearchCmdF(s.client, newCmd(nil, nil, "01/01/2026", 20, 0), []string{team.Name})
+		s.Require().EqualError(err, "invalid after day '01/01/2026', expected YYYY-MM-DD")
+
+		expectTeam()
+		err = postSearchCmdF(s.client, newCmd(nil, nil, "", 20, 0), []string{team.Name})
+		s.Require().EqualError(err, "either search terms or a --from, --in, --after or --before flag must be given")
+	})
+}
@@ -4,6 +4,8 @@
 package commands
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/mattermost/mattermost-server/v6/model"
@@ -255,4 +257,291 @@ func (s *MmctlUnitTestSuite) TestPostListCmdF() {
 		s.Len(printer.GetLines(), 1)
 		s.Len(printer.GetErrorLines(), 0)
 	})
+
+	s.Run("list posts for a channel filtered by time range, author and replies", func() {
+		printer.Clean()
+
+		mockChannel := model.Channel{Name: channelName, Id: channelID}
+		mockUser := &model.User{Id: userID, Username: "some-user", Email: "some-user@example.com"}
+		base := model.GetMillisForTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+		posts := []*model.Post{
+			{Id: "after-until", UserId: userID, CreateAt: base + 5000},
+			{Id: "reply", UserId: userID, CreateAt: base + 4000, RootId: "root"},
+			{Id: "other-author", UserId: "other-user", CreateAt: base + 3000},
+			{Id: "root", UserId: userID, CreateAt: base + 2000},
+			{Id: "first", UserId: userID, CreateAt: base + 1000},
+			{Id: "before-since", UserId: userID, CreateAt: base - 1000},
+		}
+		mockPostList := model.NewPostList()
+		for _, post := range posts {
+			mockPostList.AddPost(post)
+			mockPostList.AddOrder(post.Id)
+		}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("number", 20, "")
+		cmd.Flags().String("since", "2021-06-01T00:00:00+00:00", "")
+		cmd.Flags().String("until", "2021-06-01T00:00:04+00:00", "")
+		cmd.Flags().StringSlice("author", []string{mockUser.Email}, "")
+		cmd.Flags().Bool("exclude-replies", true, "")
+
+		s.client.
+			EXPECT().
+			GetChannel(channelName, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(mockUser.Email, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channelID, 0, APILimitMaximum, "", false).
+			Return(mockPostList, &model.Response{}, nil).
+			Times(1)
+
+		err := postListCmdF(s.client, cmd, []string{channelName})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(posts[4], printer.GetLines()[0])
+		s.Require().Equal(posts[3], printer.GetLines()[1])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("list all the posts for a channel", func() {
+		printer.Clean()
+
+		mockChannel := model.Channel{Name: channelName, Id: channelID}
+		mockUser := model.User{Id: userID, Username: "some-user"}
+		firstPage := model.NewPostList()
+		for i := 0; i < APILimitMaximum; i++ {
+			post := &model.Post{Id: fmt.Sprintf("post-%d", i), UserId: userID}
+			firstPage.AddPost(post)
+			firstPage.AddOrder(post.Id)
+		}
+		lastPost := &model.Post{Id: "last-post", UserId: userID}
+		secondPage := model.NewPostList()
+		secondPage.AddPost(lastPost)
+		secondPage.AddOrder(lastPost.Id)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("number", 20, "")
+		cmd.Flags().Bool("all", true, "")
+
+		s.client.
+			EXPECT().
+			GetChannel(channelName, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channelID, 0, APILimitMaximum, "", false).
+			Return(firstPage, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channelID, 1, APILimitMaximum, "", false).
+			Return(secondPage, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser(userID, "").
+			Return(&mockUser, &model.Response{}, nil).
+			Times(1)
+
+		err := postListCmdF(s.client, cmd, []string{channelName})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), APILimitMaximum+1)
+		s.Require().Equal(lastPost, printer.GetLines()[0])
+	})
+
+	s.Run("invalid time for until flag", func() {
+		mockChannel := model.Channel{Name: channelName}
+
+		s.client.
+			EXPECT().
+			GetChannel(channelName, "").
+			Return(&mockChannel, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("until", "invalid-date", "")
+
+		err := postListCmdF(s.client, cmd, []string{channelName})
+		s.Require().EqualError(err, "invalid until time 'invalid-date'")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestPostForwardCmdF() {
+	team := &model.Team{Id: model.NewId(), Name: "myteam"}
+	channel := &model.Channel{Id: model.NewId(), Name: "source", TeamId: team.Id}
+	dmChannel := &model.Channel{Id: model.NewId(), Type: model.ChannelTypeDirect}
+	target := &model.Channel{Id: model.NewId(), Name: "target", TeamId: team.Id}
+	post := &model.Post{Id: model.NewId(), ChannelId: channel.Id, Message: "original"}
+	clientConfig := map[string]string{"SiteURL": "https://example.com/"}
+
+	newCmd := func(comment string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("comment", comment, "")
+		return cmd
+	}
+
+	s.Run("forward a post to a channel with a comment", func() {
+		printer.Clean()
+		expectedPost := &model.Post{
+			ChannelId: target.Id,
+			Message:   "have a look\nhttps://example.com/myteam/pl/" + post.Id,
+		}
+
+		s.client.
+			EXPECT().
+			GetPost(post.Id, "").
+			Return(post, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(target.Id, "").
+			Return(target, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(clientConfig, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreatePost(expectedPost).
+			Return(&model.Post{Id: "new-post"}, &model.Response{}, nil).
+			Times(1)
+
+		err := postForwardCmdF(s.client, newCmd("have a look"), []string{post.Id, target.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&model.Post{Id: "new-post"}, printer.GetLines()[0])
+	})
+
+	s.Run("forward a post from a direct channel to a user", func() {
+		printer.Clean()
+		me := &model.User{Id: model.NewId(), Username: "me"}
+		user := &model.User{Id: model.NewId(), Username: "john"}
+		dmPost := &model.Post{Id: model.NewId(), ChannelId: dmChannel.Id}
+
+		s.client.
+			EXPECT().
+			GetPost(dmPost.Id, "").
+			Return(dmPost, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Username, "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(me, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateDirectChannel(me.Id, user.Id).
+			Return(&model.Channel{Id: "dm-id", Type: model.ChannelTypeDirect}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(dmChannel.Id, "").
+			Return(dmChannel, &model.Response{}, nil).
+			Times(1)
+
+		err := postForwardCmdF(s.client, newCmd(""), []string{dmPost.Id, "@john"})
+		s.Require().EqualError(err, "unable to forward a post from a direct channel to another direct channel")
+	})
+
+	s.Run("fail when the post does not exist", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetPost("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := postForwardCmdF(s.client, newCmd(""), []string{"unknown", target.Id})
+		s.Require().EqualError(err, `unable to find post "unknown": not found`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestPostDeleteCmdF() {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	s.Run("Should delete the posts", func() {
+		printer.Clean()
+		postID := model.NewId()
+
+		s.client.
+			EXPECT().
+			DeletePost(postID).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := postDeleteCmdF(s.client, newCmd(), []string{postID})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{postID}, printer.GetLines())
+	})
+
+	s.Run("Should report the posts that cannot be deleted", func() {
+		printer.Clean()
+		postID, missingID := model.NewId(), model.NewId()
+
+		s.client.
+			EXPECT().
+			DeletePost(missingID).
+			Return(&model.Response{StatusCode: http.StatusNotFound}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeletePost(postID).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := postDeleteCmdF(s.client, newCmd(), []string{missingID, postID})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), fmt.Sprintf("unable to delete post %q: mock error", missingID))
+		s.Require().Equal([]interface{}{postID}, printer.GetLines())
+	})
 }
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// transferProgress draws the progress of an upload or a download on a
+// terminal, with the bytes transferred, the rate and the time left
+type transferProgress struct {
+	out         io.Writer
+	name        string
+	offset      int64
+	done        int64
+	total       int64
+	transferred int64
+	started     time.Time
+	lastDrawn   time.Time
+}
+
+// newTransferProgress returns the progress of a transfer if the errors
+// are shown on a terminal and the output isn't JSON, or nil otherwise
+// so that the output of scripts isn't cluttered
+func newTransferProgress(name string) *transferProgress {
+	if viper.GetBool("quiet") || viper.GetBool("json") || viper.GetString("format") == printer.FormatJSON {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+	return &transferProgress{out: os.Stderr, name: name, total: -1, started: time.Now()}
+}
+
+// begin starts a transfer from the offset, with the total size of the
+// file if it's known or -1 otherwise
+func (p *transferProgress) begin(offset, total int64) {
+	p.offset, p.done = offset, offset
+	if total >= 0 {
+		p.total = total
+	}
+}
+
+func (p *transferProgress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	p.transferred += int64(len(b))
+	if time.Since(p.lastDrawn) > 200*time.Millisecond {
+		p.draw()
+	}
+	return len(b), nil
+}
+
+// rate returns the bytes transferred per second since the progress was
+// created, which only counts the ones of this run when resuming
+func (p *transferProgress) rate() float64 {
+	elapsed := time.Since(p.started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.transferred) / elapsed
+}
+
+func (p *transferProgress) draw() {
+	p.lastDrawn = time.Now()
+	rate := p.rate()
+	if p.total <= 0 {
+		fmt.Fprintf(p.out, "\r%s: %s, %s/s\x1b[K", p.name, formatByteSize(p.done), formatByteSize(int64(rate)))
+		return
+	}
+
+	const width = 30
+	filled := int(p.done * width / p.total)
+	if filled > width {
+		filled = width
+	}
+	eta := "-"
+	switch {
+	case p.done >= p.total:
+		eta = "0s"
+	case rate > 0:
+		eta = time.Duration(float64(p.total-p.done) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+	fmt.Fprintf(p.out, "\r%s: [%s%s] %3d%% %s of %s, %s/s, ETA %s\x1b[K", p.name, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.done*100/p.total, formatByteSize(p.done), formatByteSize(p.total), formatByteSize(int64(rate)), eta)
+}
+
+func (p *transferProgress) finish() {
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+func formatByteSize(n int64) string {
+	const unit = 1 << 10
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// uploadProgress is the progress of the upload in course, which the
+// requests made through withTransferProgress report to. The client
+// builds some request bodies in memory before sending them, so the
+// transfer can only be followed at the transport
+var uploadProgress *transferProgress
+
+// withUploadProgress runs the upload of a file with its progress shown,
+// starting from the offset of the file and with its total size if it's
+// known or -1 otherwise
+func withUploadProgress(name string, offset, total int64, upload func() error) error {
+	p := newTransferProgress(name)
+	if p == nil {
+		return upload()
+	}
+
+	p.begin(offset, total)
+	uploadProgress = p
+	defer func() {
+		uploadProgress = nil
+		p.finish()
+	}()
+	return upload()
+}
+
+// withTransferProgress returns a round tripper that reports the bytes
+// of the request bodies sent through the given one to the upload in
+// course
+func withTransferProgress(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &progressTransport{base: base}
+}
+
+type progressTransport struct {
+	base http.RoundTripper
+}
+
+type progressReadCloser struct {
+	io.ReadCloser
+	progress *transferProgress
+}
+
+func (r *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	_, _ = r.progress.Write(b[:n])
+	return n, err
+}
+
+func (pt *progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := uploadProgress
+	if p == nil || req.Body == nil || req.Body == http.NoBody {
+		return pt.base.RoundTrip(req)
+	}
+
+	// a retried request sends the whole body again
+	p.begin(p.offset, -1)
+	if p.total < 0 && req.ContentLength > 0 {
+		p.total = p.offset + req.ContentLength
+	}
+	req = req.Clone(req.Context())
+	req.Body = &progressReadCloser{ReadCloser: req.Body, progress: p}
+	return pt.base.RoundTrip(req)
+}
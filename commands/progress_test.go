@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferProgress(t *testing.T) {
+	t.Run("draws the bytes transferred, the rate and the time left", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		p := &transferProgress{out: out, name: "plugin.tar.gz", total: -1, started: time.Now().Add(-time.Second)}
+		p.begin(1024, 4096)
+		_, _ = p.Write(make([]byte, 1024))
+
+		require.Contains(t, out.String(), "plugin.tar.gz: [===============               ]  50% 2.0 KiB of 4.0 KiB")
+		require.Contains(t, out.String(), ", ETA 2s")
+	})
+
+	t.Run("draws the bytes transferred without a total", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		p := &transferProgress{out: out, name: "export.zip", total: -1, started: time.Now()}
+		_, _ = p.Write(make([]byte, 3*1024*1024))
+		require.True(t, strings.HasPrefix(out.String(), "\rexport.zip: 3.0 MiB, "))
+	})
+
+	t.Run("is disabled with the JSON output", func(t *testing.T) {
+		viper.Set("json", true)
+		defer viper.Set("json", false)
+		require.Nil(t, newTransferProgress("export.zip"))
+	})
+}
+
+func TestProgressTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: withTransferProgress(nil)}
+	body := strings.Repeat("x", 5000)
+
+	t.Run("reports the request bodies to the upload in course", func(t *testing.T) {
+		p := &transferProgress{out: ioutil.Discard, total: -1, started: time.Now()}
+		p.begin(0, -1)
+		uploadProgress = p
+		defer func() { uploadProgress = nil }()
+
+		resp, err := client.Post(server.URL, "application/octet-stream", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, int64(5000), p.done)
+		require.Equal(t, int64(5000), p.total)
+	})
+
+	t.Run("counts from the offset of a resumed upload", func(t *testing.T) {
+		p := &transferProgress{out: ioutil.Discard, total: -1, started: time.Now()}
+		p.begin(1000, 6000)
+		uploadProgress = p
+		defer func() { uploadProgress = nil }()
+
+		resp, err := client.Post(server.URL, "application/octet-stream", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, int64(6000), p.done)
+		require.Equal(t, int64(6000), p.total)
+	})
+
+	t.Run("leaves the requests alone without an upload in course", func(t *testing.T) {
+		resp, err := client.Post(server.URL, "application/octet-stream", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitSleep is used to wait between transfers and can be
+// overridden in the tests
+var rateLimitSleep = time.Sleep
+
+// parseRate parses a transfer rate in bytes per second, accepting
+// the K, M and G suffixes as curl's --limit-rate does. An empty rate
+// means no limit, and is returned as zero
+func parseRate(rate string) (int64, error) {
+	n, err := parseByteSize(rate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q, must be a positive number of bytes per second optionally followed by K, M or G", rate)
+	}
+	return n, nil
+}
+
+// parseByteSize parses a number of bytes accepting the K, M and G
+// suffixes. An empty size is returned as zero
+func parseByteSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	number := strings.ToUpper(size)
+	switch {
+	case strings.HasSuffix(number, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(number, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(number, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier != 1 {
+		number = number[:len(number)-1]
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	return n * multiplier, nil
+}
+
+// rateLimiter keeps track of the transferred bytes and waits as long
+// as needed to keep the average transfer rate under the limit
+type rateLimiter struct {
+	rate        int64
+	start       time.Time
+	transferred int64
+}
+
+func newRateLimiter(rate int64) *rateLimiter {
+	return &rateLimiter{rate: rate, start: time.Now()}
+}
+
+// chunk caps the size of a transfer so the rate is kept stable
+// instead of transferring big bursts and then waiting
+func (l *rateLimiter) chunk(size int) int {
+	if int64(size) > l.rate {
+		return int(l.rate)
+	}
+	return size
+}
+
+func (l *rateLimiter) wait(n int) {
+	l.transferred += int64(n)
+	expected := time.Duration(float64(l.transferred) / float64(l.rate) * float64(time.Second))
+	if elapsed := time.Since(l.start); expected > elapsed {
+		rateLimitSleep(expected - elapsed)
+	}
+}
+
+type rateLimitedReader struct {
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p[:r.limiter.chunk(len(p))])
+	r.limiter.wait(n)
+	return n, err
+}
+
+type rateLimitedWriter struct {
+	w       io.Writer
+	limiter *rateLimiter
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := w.w.Write(p[written : written+w.limiter.chunk(len(p)-written)])
+		written += n
+		w.limiter.wait(n)
+		if err != nil {
+			return written, err
+		}
+		// a writer that writes nothing without an error would make
+		// the loop spin forever
+		if n == 0 {
+			return written, io.ErrShortWrite
+		}
+	}
+	return written, nil
+}
+
+// limitReader returns a reader that reads at most rate bytes per
+// second, or the reader itself if the rate is zero
+func limitReader(r io.Reader, rate int64) io.Reader {
+	if rate <= 0 {
+		return r
+	}
+	return &rateLimitedReader{r: r, limiter: newRateLimiter(rate)}
+}
+
+// limitWriter returns a writer that writes at most rate bytes per
+// second, or the writer itself if the rate is zero
+func limitWriter(w io.Writer, rate int64) io.Writer {
+	if rate <= 0 {
+		return w
+	}
+	return &rateLimitedWriter{w: w, limiter: newRateLimiter(rate)}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	for rate, expected := range map[string]int64{
+		"":     0,
+		"100":  100,
+		"500K": 500 * 1024,
+		"2m":   2 * 1024 * 1024,
+		"1G":   1024 * 1024 * 1024,
+	} {
+		n, err := parseRate(rate)
+		require.NoError(t, err)
+		require.Equal(t, expected, n, rate)
+	}
+
+	for _, rate := range []string{"K", "-10", "0", "1T", "fast"} {
+		_, err := parseRate(rate)
+		require.Error(t, err, rate)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var slept time.Duration
+	rateLimitSleep = func(d time.Duration) { slept += d }
+	defer func() { rateLimitSleep = time.Sleep }()
+
+	data := bytes.Repeat([]byte("a"), 3000)
+
+	t.Run("should read at most the rate", func(t *testing.T) {
+		slept = 0
+
+		b, err := ioutil.ReadAll(limitReader(bytes.NewReader(data), 1000))
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+		require.Greater(t, int64(slept), int64(2*time.Second))
+	})
+
+	t.Run("should write at most the rate", func(t *testing.T) {
+		slept = 0
+		buf := &bytes.Buffer{}
+
+		n, err := limitWriter(buf, 1000).Write(data)
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+		require.Equal(t, data, buf.Bytes())
+		require.Greater(t, int64(slept), int64(2*time.Second))
+	})
+
+	t.Run("should not limit if the rate is zero", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.Equal(t, buf, limitWriter(buf, 0))
+	})
+
+	t.Run("should fail if the writer writes nothing", func(t *testing.T) {
+		n, err := limitWriter(zeroWriter{}, 1000).Write(data)
+		require.Equal(t, io.ErrShortWrite, err)
+		require.Zero(t, n)
+	})
+}
+
+// zeroWriter is a writer that never writes anything, nor fails
+type zeroWriter struct{}
+
+func (zeroWriter) Write(p []byte) (int, error) {
+	return 0, nil
+}
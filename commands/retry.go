@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// maxRetryDelay caps the exponential backoff between two attempts, the
+// Retry-After header of the server is honored as is
+const maxRetryDelay = time.Minute
+
+// retrySleep waits between two attempts, and is replaced in the tests
+var retrySleep = time.Sleep
+
+// retryTransport retries the requests rejected by the rate limiter of
+// the server or failing with a transient server error
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	delay   time.Duration
+}
+
+// withRetries returns a round tripper that retries the requests made
+// through the given one as configured with the --retries and
+// --retry-delay flags
+func withRetries(base http.RoundTripper) http.RoundTripper {
+	retries := viper.GetInt("retries")
+	if retries <= 0 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, retries: retries, delay: viper.GetDuration("retry-delay")}
+}
+
+// shouldRetry returns whether a request that got the given status can
+// be sent again. The requests rejected with 429 or 503 were not
+// processed, but the ones that got a 502 or 504 may have been, so only
+// the idempotent ones are retried
+func shouldRetry(req *http.Request, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of
+// the response, given either in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// backoff returns the delay before the given retry, doubling the
+// configured one on each attempt
+func (rt *retryTransport) backoff(attempt int) time.Duration {
+	delay := rt.delay
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.base.RoundTrip(req)
+		if err != nil || attempt >= rt.retries || !shouldRetry(req, resp.StatusCode) {
+			return resp, err
+		}
+
+		// the body of the request has already been read, so it can
+		// only be sent again if it can be rewound
+		var body io.ReadCloser
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			if body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+
+		delay, ok := retryAfter(resp)
+		if !ok {
+			delay = rt.backoff(attempt)
+		}
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if !viper.GetBool("suppress-warnings") {
+			printer.PrintWarning(fmt.Sprintf("%s %s failed with status %d, retrying in %s (%d/%d)", req.Method, req.URL.Path, resp.StatusCode, delay, attempt+1, rt.retries))
+		}
+
+		retrySleep(delay)
+
+		if body != nil {
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	originalSleep := retrySleep
+	defer func() { retrySleep = originalSleep }()
+	var delays []time.Duration
+	retrySleep = func(d time.Duration) { delays = append(delays, d) }
+
+	// newServer answers with the given statuses in order, and then
+	// with 200, recording the bodies of the requests it gets
+	newServer := func(header http.Header, statuses ...int) (*httptest.Server, *[]string) {
+		bodies := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) <= len(statuses) {
+				for key := range header {
+					w.Header().Set(key, header.Get(key))
+				}
+				w.WriteHeader(statuses[len(bodies)-1])
+			}
+		}))
+		return server, &bodies
+	}
+
+	rt := &retryTransport{base: http.DefaultTransport, retries: 3, delay: time.Second}
+	httpClient := &http.Client{Transport: rt}
+
+	t.Run("Should retry with an exponential backoff", func(t *testing.T) {
+		delays = nil
+		server, bodies := newServer(nil, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		resp, err := httpClient.Post(server.URL+"/api/v4/users", "application/json", strings.NewReader(`{"username":"john"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{`{"username":"john"}`, `{"username":"john"}`, `{"username":"john"}`}, *bodies)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+	})
+
+	t.Run("Should honor the Retry-After header", func(t *testing.T) {
+		delays = nil
+		server, _ := newServer(http.Header{"Retry-After": []string{"7"}}, http.StatusTooManyRequests)
+		defer server.Close()
+
+		resp, err := httpClient.Get(server.URL + "/api/v4/users")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []time.Duration{7 * time.Second}, delays)
+	})
+
+	t.Run("Should give up after the configured retries", func(t *testing.T) {
+		delays = nil
+		server, bodies := newServer(nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		defer server.Close()
+
+		resp, err := httpClient.Get(server.URL + "/api/v4/users")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.Len(t, *bodies, 4)
+		require.Len(t, delays, 3)
+	})
+
+	t.Run("Should not retry the requests that may have been processed", func(t *testing.T) {
+		delays = nil
+		server, bodies := newServer(nil, http.StatusGatewayTimeout)
+		defer server.Close()
+
+		resp, err := httpClient.Post(server.URL+"/api/v4/posts", "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		require.Len(t, *bodies, 1)
+
+		server, bodies = newServer(nil, http.StatusInternalServerError)
+		defer server.Close()
+
+		resp, err = httpClient.Get(server.URL + "/api/v4/users")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Len(t, *bodies, 1)
+		require.Empty(t, delays)
+	})
+
+	t.Run("Should cap the backoff", func(t *testing.T) {
+		require.Equal(t, 8*time.Second, rt.backoff(3))
+		require.Equal(t, maxRetryDelay, rt.backoff(10))
+	})
+}
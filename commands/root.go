@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -30,11 +31,18 @@ func Run(args []string) error {
 	_ = RootCmd.PersistentFlags().MarkHidden("config-path")
 	RootCmd.PersistentFlags().Bool("suppress-warnings", false, "disables printing warning messages")
 	_ = viper.BindPFlag("suppress-warnings", RootCmd.PersistentFlags().Lookup("suppress-warnings"))
-	RootCmd.PersistentFlags().String("format", "plain", "the format of the command output [plain, json]")
+	RootCmd.PersistentFlags().String("format", "plain", "the format of the command output [plain, json, csv], or a Go template to render each returned entity with, e.g. '{{.Username}} {{.Email}}'")
 	_ = viper.BindPFlag("format", RootCmd.PersistentFlags().Lookup("format"))
-	_ = RootCmd.PersistentFlags().MarkHidden("format")
 	RootCmd.PersistentFlags().Bool("json", false, "the output format will be in json format")
 	_ = viper.BindPFlag("json", RootCmd.PersistentFlags().Lookup("json"))
+	RootCmd.PersistentFlags().Bool("csv", false, "the output format will be in CSV format, with a header row")
+	_ = viper.BindPFlag("csv", RootCmd.PersistentFlags().Lookup("csv"))
+	RootCmd.PersistentFlags().StringSlice("columns", []string{}, "the fields to include as columns in the CSV output, by their JSON name")
+	_ = viper.BindPFlag("columns", RootCmd.PersistentFlags().Lookup("columns"))
+	RootCmd.PersistentFlags().Bool("id-only", false, "only print the IDs of the returned entities, one per line")
+	_ = viper.BindPFlag("id-only", RootCmd.PersistentFlags().Lookup("id-only"))
+	RootCmd.PersistentFlags().String("profile", "", "the name of the stored credentials to use for the command instead of the active ones")
+	_ = viper.BindPFlag("profile", RootCmd.PersistentFlags().Lookup("profile"))
 	RootCmd.PersistentFlags().Bool("strict", false, "will only run commands if the mmctl version matches the server one")
 	_ = viper.BindPFlag("strict", RootCmd.PersistentFlags().Lookup("strict"))
 	RootCmd.PersistentFlags().Bool("insecure-sha1-intermediate", false, "allows to use insecure TLS protocols, such as SHA-1")
@@ -51,8 +59,25 @@ func Run(args []string) error {
 	_ = viper.BindPFlag("disable-pager", RootCmd.PersistentFlags().Lookup("disable-pager"))
 	RootCmd.PersistentFlags().Bool("quiet", false, "prevent mmctl to generate output for the commands")
 	_ = viper.BindPFlag("quiet", RootCmd.PersistentFlags().Lookup("quiet"))
+	RootCmd.PersistentFlags().Bool("utc", false, "render timestamps in UTC instead of the local timezone")
+	_ = viper.BindPFlag("utc", RootCmd.PersistentFlags().Lookup("utc"))
+	RootCmd.PersistentFlags().Bool("no-table", false, "print the plain output line by line instead of as tables when it's printed to a terminal")
+	_ = viper.BindPFlag("no-table", RootCmd.PersistentFlags().Lookup("no-table"))
+	RootCmd.PersistentFlags().Bool("no-color", false, "disable the colors of the output")
+	_ = viper.BindPFlag("no-color", RootCmd.PersistentFlags().Lookup("no-color"))
+	RootCmd.PersistentFlags().String("timestamp-format", printer.DefaultTimestampFormat, "the Go time layout used to render timestamps in the plain output")
+	_ = viper.BindPFlag("timestamp-format", RootCmd.PersistentFlags().Lookup("timestamp-format"))
 
-	RootCmd.SetArgs(args)
+	RootCmd.PersistentFlags().String("trace-otlp", "", "export a trace of the API calls to an OTLP/HTTP collector URL or to a local JSON file")
+	_ = viper.BindPFlag("trace-otlp", RootCmd.PersistentFlags().Lookup("trace-otlp"))
+	RootCmd.PersistentFlags().Int("retries", 3, "the number of times to retry the API calls rejected by rate limiting or failing with a transient server error")
+	_ = viper.BindPFlag("retries", RootCmd.PersistentFlags().Lookup("retries"))
+	RootCmd.PersistentFlags().Duration("retry-delay", time.Second, "the delay before the first retry of an API call, doubled on each attempt unless the server sends a Retry-After header")
+	_ = viper.BindPFlag("retry-delay", RootCmd.PersistentFlags().Lookup("retry-delay"))
+	RootCmd.PersistentFlags().Int("stdin-batch-size", defaultStdinBatchSize, `the number of arguments read from the standard input with "-" passed to the command at a time, all of them with --plan or --dry-run. Commands with a --confirm flag require it when reading from the standard input`)
+	_ = viper.BindPFlag("stdin-batch-size", RootCmd.PersistentFlags().Lookup("stdin-batch-size"))
+	RootCmd.PersistentFlags().Bool("dry-run", false, "print the entities a destructive command would affect, without changing them")
+	_ = viper.BindPFlag("dry-run", RootCmd.PersistentFlags().Lookup("dry-run"))
 
 	defer func() {
 		if x := recover(); x != nil {
@@ -64,7 +89,24 @@ func Run(args []string) error {
 		}
 	}()
 
-	return RootCmd.Execute()
+	_, err := executeRootCmd(args)
+	return err
+}
+
+// executeRootCmd runs the command of the arguments. Cobra skips
+// PersistentPostRun when a command fails, so the output printed before
+// the error is flushed here, for the results of the command not to be
+// lost along with it
+func executeRootCmd(args []string) (*cobra.Command, error) {
+	RootCmd.SetArgs(args)
+	executed, err := RootCmd.ExecuteC()
+	if err != nil {
+		if len(printer.GetLines()) > 0 || len(printer.GetErrorLines()) > 0 {
+			_ = printer.Flush()
+		}
+		RootCmd.PrintErrln("Error:", err.Error())
+	}
+	return executed, err
 }
 
 var RootCmd = &cobra.Command{
@@ -72,7 +114,7 @@ var RootCmd = &cobra.Command{
 	Short:             "Remote client for the Open Source, self-hosted Slack-alternative",
 	Long:              `Mattermost offers workplace messaging across web, PC and phones with archiving, search and integration with your existing systems. Documentation available at https://docs.mattermost.com`,
 	DisableAutoGenTag: true,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		format := viper.GetString("format")
 		if viper.GetBool("disable-pager") {
 			printer.OverrideEnablePager(false)
@@ -80,16 +122,36 @@ var RootCmd = &cobra.Command{
 
 		printer.SetCommand(cmd)
 		isJSON := viper.GetBool("json")
-		if isJSON || format == printer.FormatJSON {
+		switch {
+		case viper.GetBool("id-only"):
+			printer.SetFormat(printer.FormatIDOnly)
+		case isJSON || format == printer.FormatJSON:
 			printer.SetFormat(printer.FormatJSON)
-		} else {
+		case viper.GetBool("csv") || format == printer.FormatCSV:
+			printer.SetFormat(printer.FormatCSV)
+			printer.SetColumns(viper.GetStringSlice("columns"))
+		case printer.IsTemplateFormat(format):
+			if err := printer.SetTemplate(format); err != nil {
+				return err
+			}
+		default:
 			printer.SetFormat(printer.FormatPlain)
+			printer.SetTable(!viper.GetBool("no-table"))
 		}
+		printer.SetNoColor(viper.GetBool("no-color"))
 		quiet := viper.GetBool("quiet")
 		printer.SetQuiet(quiet)
+		printer.SetUTC(viper.GetBool("utc"))
+		printer.SetTimestampFormat(viper.GetString("timestamp-format"))
+
+		if err := checkDryRun(cmd); err != nil {
+			return err
+		}
+		return checkPolicy(cmd)
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		_ = printer.Flush()
 	},
-	SilenceUsage: true,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
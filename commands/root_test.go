@@ -0,0 +1,41 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestExecuteRootCmd() {
+	s.Run("Should flush the output printed before the command failed", func() {
+		printer.Clean()
+
+		failingCmd := &cobra.Command{
+			Use: "failing",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				printer.Print("partial result")
+				printer.PrintError("partial error")
+				return errors.New("command failed")
+			},
+		}
+		RootCmd.AddCommand(failingCmd)
+		defer RootCmd.RemoveCommand(failingCmd)
+
+		errOut := &bytes.Buffer{}
+		RootCmd.SetErr(errOut)
+		defer RootCmd.SetErr(nil)
+
+		executed, err := executeRootCmd([]string{"failing"})
+		s.Require().EqualError(err, "command failed")
+		s.Require().Equal(failingCmd, executed)
+		s.Require().Equal("Error: command failed\n", errOut.String())
+		s.Require().Empty(printer.GetLines())
+		s.Require().Empty(printer.GetErrorLines())
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SamlCertificateCmd = &cobra.Command{
+	Use:   "certificate",
+	Short: "Management of the SAML certificates",
+	Long: `Management of the SAML certificates. The certificates are of three kinds:
+  idp      the public certificate of the identity provider
+  public   the public certificate of the service provider, used to encrypt the assertions
+  private  the private key of the service provider, used to decrypt the assertions`,
+}
+
+var SamlCertificateStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show which SAML certificates are uploaded",
+	Example: "  saml certificate status",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(samlCertificateStatusCmdF),
+}
+
+var SamlCertificateUploadCmd = &cobra.Command{
+	Use:   "upload [idp|public|private] [file]",
+	Short: "Upload a SAML certificate",
+	Long:  "Upload a PEM encoded SAML certificate, replacing the one of the same kind if there is one.",
+	Example: `  saml certificate upload idp idp.crt
+  saml certificate upload private sp.key`,
+	Args: cobra.ExactArgs(2),
+	RunE: withClient(samlCertificateUploadCmdF),
+}
+
+var SamlCertificateRemoveCmd = &cobra.Command{
+	Use:     "remove [idp|public|private]",
+	Short:   "Remove a SAML certificate",
+	Long:    "Remove a SAML certificate. Removing the identity provider certificate makes SAML logins fail until a new one is uploaded.",
+	Example: "  saml certificate remove public --confirm",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(samlCertificateRemoveCmdF),
+}
+
+var SamlIdpMetadataCmd = &cobra.Command{
+	Use:   "idp-metadata [url]",
+	Short: "Get the metadata of the SAML identity provider",
+	Long: `Get the metadata of the SAML identity provider from its metadata URL, showing its SSO URL, its issuer URL and its public certificate.
+With --apply, the certificate is uploaded as the identity provider certificate and the URLs are saved in the SAML settings of the server.`,
+	Example: "  saml idp-metadata https://idp.example.com/metadata --apply",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(samlIdpMetadataCmdF),
+}
+
+func init() {
+	SamlCertificateRemoveCmd.Flags().Bool("confirm", false, "Confirm you really want to remove the certificate")
+	SamlIdpMetadataCmd.Flags().Bool("apply", false, "Upload the certificate and save the URLs of the identity provider in the server config")
+
+	SamlCertificateCmd.AddCommand(
+		SamlCertificateStatusCmd,
+		SamlCertificateUploadCmd,
+		SamlCertificateRemoveCmd,
+	)
+	SamlCmd.AddCommand(
+		SamlCertificateCmd,
+		SamlIdpMetadataCmd,
+	)
+}
+
+// samlCertificateKind binds a kind of SAML certificate to the client
+// methods that manage it
+type samlCertificateKind struct {
+	name   string
+	upload func(data []byte, filename string) (*model.Response, error)
+	remove func() (*model.Response, error)
+}
+
+func getSamlCertificateKind(c client.Client, kind string) (*samlCertificateKind, error) {
+	switch kind {
+	case "idp":
+		return &samlCertificateKind{"identity provider certificate", c.UploadSamlIdpCertificate, c.DeleteSamlIdpCertificate}, nil
+	case "public":
+		return &samlCertificateKind{"service provider public certificate", c.UploadSamlPublicCertificate, c.DeleteSamlPublicCertificate}, nil
+	case "private":
+		return &samlCertificateKind{"service provider private key", c.UploadSamlPrivateCertificate, c.DeleteSamlPrivateCertificate}, nil
+	}
+	return nil, fmt.Errorf("invalid certificate kind %q, must be idp, public or private", kind)
+}
+
+// validatePemData checks that the data holds a PEM block, so a wrong
+// file isn't uploaded and breaks the SAML logins
+func validatePemData(data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errors.New("the file is not PEM encoded")
+	}
+	return nil
+}
+
+// idpCertificateToPem encodes the certificate of the identity provider
+// metadata as PEM, as metadata usually holds only its base64 data
+func idpCertificateToPem(certificate string) []byte {
+	certificate = strings.TrimSpace(certificate)
+	if strings.HasPrefix(certificate, "-----BEGIN") {
+		return []byte(certificate + "\n")
+	}
+	return []byte("-----BEGIN CERTIFICATE-----\n" + strings.Join(strings.Fields(certificate), "\n") + "\n-----END CERTIFICATE-----\n")
+}
+
+func samlCertificateStatusCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	status, _, err := c.GetSamlCertificateStatus()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the status of the SAML certificates")
+	}
+
+	printer.PrintT(`Identity provider certificate: {{.IdpCertificateFile}}
+Service provider public certificate: {{.PublicCertificateFile}}
+Service provider private key: {{.PrivateKeyFile}}`, status)
+
+	return nil
+}
+
+func samlCertificateUploadCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	kind, err := getSamlCertificateKind(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		return errors.Wrap(err, "failed to read the certificate")
+	}
+	if err := validatePemData(data); err != nil {
+		return fmt.Errorf("invalid certificate %s: %w", args[1], err)
+	}
+
+	if _, err := kind.upload(data, filepath.Base(args[1])); err != nil {
+		return errors.Wrapf(err, "failed to upload the %s", kind.name)
+	}
+
+	printer.Print(fmt.Sprintf("SAML %s uploaded", kind.name))
+	return nil
+}
+
+func samlCertificateRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	kind, err := getSamlCertificateKind(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to remove the SAML %s?", kind.name), false); err != nil {
+			return err
+		}
+	}
+
+	if _, err := kind.remove(); err != nil {
+		return errors.Wrapf(err, "failed to remove the %s", kind.name)
+	}
+
+	printer.Print(fmt.Sprintf("SAML %s removed", kind.name))
+	return nil
+}
+
+func samlIdpMetadataCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	metadataURL := args[0]
+	metadata, _, err := c.GetSamlMetadataFromIdp(metadataURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the metadata of the identity provider")
+	}
+
+	apply, _ := cmd.Flags().GetBool("apply")
+	if !apply {
+		printer.PrintT(`SSO URL: {{.IdpURL}}
+Issuer URL: {{.IdpDescriptorURL}}
+Certificate:
+{{.IdpPublicCertificate}}`, metadata)
+		return nil
+	}
+
+	certificate := idpCertificateToPem(metadata.IdpPublicCertificate)
+	if err := validatePemData(certificate); err != nil {
+		return fmt.Errorf("invalid certificate in the metadata of the identity provider: %w", err)
+	}
+	if _, err := c.UploadSamlIdpCertificate(certificate, "saml-idp.crt"); err != nil {
+		return errors.Wrap(err, "failed to upload the identity provider certificate")
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the server config")
+	}
+	config.SamlSettings.IdpMetadataURL = model.NewString(metadataURL)
+	config.SamlSettings.IdpURL = model.NewString(metadata.IdpURL)
+	config.SamlSettings.IdpDescriptorURL = model.NewString(metadata.IdpDescriptorURL)
+	if _, _, err := c.PatchConfig(config); err != nil {
+		return errors.Wrap(err, "unable to update the server config")
+	}
+
+	printer.PrintT("SAML identity provider {{.IdpDescriptorURL}} applied, SSO URL: {{.IdpURL}}", metadata)
+	return nil
+}
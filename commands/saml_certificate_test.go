@@ -0,0 +1,177 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSamlCertificateCmds() {
+	certificate := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+	writeFile := func(name, content string) (string, func()) {
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(tmp, name)
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path, func() { os.RemoveAll(tmp) }
+	}
+
+	s.Run("Should show the status of the certificates", func() {
+		printer.Clean()
+		status := &model.SamlCertificateStatus{IdpCertificateFile: true}
+
+		s.client.
+			EXPECT().
+			GetSamlCertificateStatus().
+			Return(status, &model.Response{}, nil).
+			Times(1)
+
+		err := samlCertificateStatusCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{status}, printer.GetLines())
+	})
+
+	s.Run("Should upload a certificate of the given kind", func() {
+		printer.Clean()
+		path, cleanup := writeFile("sp.crt", certificate)
+		defer cleanup()
+
+		s.client.
+			EXPECT().
+			UploadSamlPublicCertificate([]byte(certificate), "sp.crt").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := samlCertificateUploadCmdF(s.client, &cobra.Command{}, []string{"public", path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"SAML service provider public certificate uploaded"}, printer.GetLines())
+	})
+
+	s.Run("Should not upload a file that isn't PEM encoded", func() {
+		path, cleanup := writeFile("idp.crt", "not a certificate")
+		defer cleanup()
+
+		err := samlCertificateUploadCmdF(s.client, &cobra.Command{}, []string{"idp", path})
+		s.Require().EqualError(err, "invalid certificate "+path+": the file is not PEM encoded")
+	})
+
+	s.Run("Should fail with an invalid kind of certificate", func() {
+		err := samlCertificateUploadCmdF(s.client, &cobra.Command{}, []string{"server", "server.crt"})
+		s.Require().EqualError(err, `invalid certificate kind "server", must be idp, public or private`)
+	})
+
+	s.Run("Should remove a certificate", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		s.client.
+			EXPECT().
+			DeleteSamlPrivateCertificate().
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := samlCertificateRemoveCmdF(s.client, cmd, []string{"private"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"SAML service provider private key removed"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if removing a certificate fails", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		s.client.
+			EXPECT().
+			DeleteSamlIdpCertificate().
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := samlCertificateRemoveCmdF(s.client, cmd, []string{"idp"})
+		s.Require().EqualError(err, "failed to remove the identity provider certificate: mock error")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSamlIdpMetadataCmd() {
+	metadataURL := "https://idp.example.com/metadata"
+	metadata := &model.SamlMetadataResponse{
+		IdpDescriptorURL:     "https://idp.example.com",
+		IdpURL:               "https://idp.example.com/sso",
+		IdpPublicCertificate: "MIIB",
+	}
+
+	s.Run("Should show the metadata of the identity provider", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("apply", false, "")
+
+		s.client.
+			EXPECT().
+			GetSamlMetadataFromIdp(metadataURL).
+			Return(metadata, &model.Response{}, nil).
+			Times(1)
+
+		err := samlIdpMetadataCmdF(s.client, cmd, []string{metadataURL})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{metadata}, printer.GetLines())
+	})
+
+	s.Run("Should upload the certificate and save the URLs with --apply", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("apply", true, "")
+		config := &model.Config{}
+		config.SetDefaults()
+
+		s.client.
+			EXPECT().
+			GetSamlMetadataFromIdp(metadataURL).
+			Return(metadata, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			UploadSamlIdpCertificate([]byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"), "saml-idp.crt").
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := samlIdpMetadataCmdF(s.client, cmd, []string{metadataURL})
+		s.Require().NoError(err)
+		s.Require().Equal(metadataURL, *config.SamlSettings.IdpMetadataURL)
+		s.Require().Equal(metadata.IdpURL, *config.SamlSettings.IdpURL)
+		s.Require().Equal(metadata.IdpDescriptorURL, *config.SamlSettings.IdpDescriptorURL)
+		s.Require().Equal([]interface{}{metadata}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the metadata can't be fetched", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("apply", true, "")
+
+		s.client.
+			EXPECT().
+			GetSamlMetadataFromIdp(metadataURL).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := samlIdpMetadataCmdF(s.client, cmd, []string{metadataURL})
+		s.Require().EqualError(err, "failed to get the metadata of the identity provider: mock error")
+	})
+}
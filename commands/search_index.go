@@ -0,0 +1,257 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Management of the search engines",
+}
+
+var SearchIndexStatusCmd = &cobra.Command{
+	Use:   "index-status",
+	Short: "Show the status of the search indexes",
+	Long: `Show the status of the Elasticsearch and Bleve indexes that are enabled: the last indexing job, the time up to which the content was indexed, and the number of indexes, documents and size of the indexes of each entity.
+The Elasticsearch indexes are read from the Elasticsearch server of the config, with the password read from --elasticsearch-password-file when the server hides it. The Bleve indexes are read from the index directory, so they are only shown when running on the server in local mode.`,
+	Example: `  search index-status
+  search index-status --elasticsearch-password-file elasticsearch-password.txt`,
+	Args: cobra.NoArgs,
+	RunE: withClient(searchIndexStatusCmdF),
+}
+
+func init() {
+	SearchIndexStatusCmd.Flags().String("elasticsearch-password-file", "", "File containing the password of the Elasticsearch server, used when the server config hides it")
+
+	SearchCmd.AddCommand(
+		SearchIndexStatusCmd,
+	)
+	RootCmd.AddCommand(SearchCmd)
+}
+
+// searchIndexEntities are the entities that have search indexes, with
+// their index names
+var searchIndexEntities = []string{"posts", "files", "channels", "users"}
+
+// searchEngineStatus is the indexing status of a search engine
+type searchEngineStatus struct {
+	Engine        string               `json:"engine"`
+	Indexing      bool                 `json:"indexing"`
+	Searching     bool                 `json:"searching"`
+	Autocomplete  bool                 `json:"autocomplete"`
+	LastJob       *model.Job           `json:"last_job,omitempty"`
+	LastIndexedAt int64                `json:"last_indexed_at,omitempty"`
+	Entities      []*searchIndexEntity `json:"entities,omitempty"`
+}
+
+// searchIndexEntity is the size of the indexes of an entity. The
+// documents are unknown for Bleve indexes
+type searchIndexEntity struct {
+	Entity    string `json:"entity"`
+	Indexes   int    `json:"indexes"`
+	Documents *int64 `json:"documents,omitempty"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+const searchEngineStatusTemplate = `{{.Engine}}: indexing {{.Indexing}}, searching {{.Searching}}, autocomplete {{.Autocomplete}}
+{{if .LastJob}}  Last indexing job: {{.LastJob.Id}} {{.LastJob.Status}} ({{.LastJob.Progress}}%), created at {{timestamp .LastJob.CreateAt}}
+{{end}}  Last indexed at: {{if .LastIndexedAt}}{{timestamp .LastIndexedAt}}{{else}}never{{end}}
+{{range .Entities}}  {{.Entity}}: {{.Indexes}} indexes, {{if .Documents}}{{.Documents}} documents, {{end}}{{size .SizeBytes}}
+{{end}}`
+
+// indexingJobsPerPage is the number of indexing jobs fetched at once
+// when looking for the last successful one
+const indexingJobsPerPage = 50
+
+// getIndexingJobStatus returns the last indexing job of a type, and the
+// time up to which the last successful one indexed the content
+func getIndexingJobStatus(c client.Client, jobType string) (*model.Job, int64, error) {
+	var lastJob *model.Job
+	for page := 0; ; page++ {
+		jobs, _, err := c.GetJobsByType(jobType, page, indexingJobsPerPage)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to get the indexing jobs")
+		}
+		if lastJob == nil && len(jobs) > 0 {
+			lastJob = jobs[0]
+		}
+		for _, job := range jobs {
+			if job.Status != model.JobStatusSuccess {
+				continue
+			}
+			if endTime, err := strconv.ParseInt(job.Data["end_time"], 10, 64); err == nil {
+				return lastJob, endTime, nil
+			}
+			return lastJob, job.LastActivityAt, nil
+		}
+		if len(jobs) < indexingJobsPerPage {
+			return lastJob, 0, nil
+		}
+	}
+}
+
+// getElasticsearchIndexStats reads the number of documents and size of
+// the indexes of each entity from the Elasticsearch server
+func getElasticsearchIndexStats(settings model.ElasticsearchSettings, password string) ([]*searchIndexEntity, error) {
+	if password == "" {
+		password = *settings.Password
+	}
+	if password == model.FakeSetting {
+		return nil, errors.New("the server hides the Elasticsearch password, give it with --elasticsearch-password-file")
+	}
+
+	httpClient := &http.Client{
+		Timeout: time.Duration(*settings.RequestTimeoutSeconds) * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: *settings.SkipTLSVerification},
+		},
+	}
+
+	prefix := *settings.IndexPrefix
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(*settings.ConnectionURL, "/")+"/_cat/indices/"+prefix+"*?format=json&bytes=b", nil)
+	if err != nil {
+		return nil, err
+	}
+	if *settings.Username != "" {
+		req.SetBasicAuth(*settings.Username, password)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the Elasticsearch server returned status %d", resp.StatusCode)
+	}
+
+	var indexes []struct {
+		Index     string `json:"index"`
+		DocsCount string `json:"docs.count"`
+		StoreSize string `json:"store.size"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&indexes); err != nil {
+		return nil, errors.Wrap(err, "invalid response of the Elasticsearch server")
+	}
+
+	entities := make([]*searchIndexEntity, len(searchIndexEntities))
+	for i, name := range searchIndexEntities {
+		entities[i] = &searchIndexEntity{Entity: name, Documents: model.NewInt64(0)}
+	}
+	for _, index := range indexes {
+		name := strings.TrimPrefix(index.Index, prefix)
+		for _, entity := range entities {
+			if name != entity.Entity && !strings.HasPrefix(name, entity.Entity+"_") {
+				continue
+			}
+			docs, _ := strconv.ParseInt(index.DocsCount, 10, 64)
+			size, _ := strconv.ParseInt(index.StoreSize, 10, 64)
+			entity.Indexes++
+			*entity.Documents += docs
+			entity.SizeBytes += size
+			break
+		}
+	}
+	return entities, nil
+}
+
+// getBleveIndexStats reads the size of the index of each entity from
+// the index directory, which is only there on the server
+func getBleveIndexStats(indexDir string) ([]*searchIndexEntity, error) {
+	if _, err := os.Stat(indexDir); err != nil {
+		return nil, fmt.Errorf("the index directory %s can't be read, run the command on the server in local mode", indexDir)
+	}
+
+	entities := []*searchIndexEntity{}
+	for _, name := range searchIndexEntities {
+		entity := &searchIndexEntity{Entity: name}
+		dir := filepath.Join(indexDir, name+".bleve")
+		if _, err := os.Stat(dir); err == nil {
+			size, sizeErr := dirSize(dir)
+			if sizeErr != nil {
+				return nil, errors.Wrapf(sizeErr, "failed to read the %s index", name)
+			}
+			entity.Indexes = 1
+			entity.SizeBytes = size
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func searchIndexStatusCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the server config")
+	}
+
+	printer.SetTemplateFunc("size", formatByteSize)
+
+	es, bleve := config.ElasticsearchSettings, config.BleveSettings
+	if !*es.EnableIndexing && !*bleve.EnableIndexing {
+		return errors.New("neither Elasticsearch nor Bleve indexing is enabled")
+	}
+
+	if *es.EnableIndexing {
+		status := &searchEngineStatus{Engine: "elasticsearch", Indexing: true, Searching: *es.EnableSearching, Autocomplete: *es.EnableAutocomplete}
+		if status.LastJob, status.LastIndexedAt, err = getIndexingJobStatus(c, model.JobTypeElasticsearchPostIndexing); err != nil {
+			return err
+		}
+		var password string
+		if passwordFile, _ := cmd.Flags().GetString("elasticsearch-password-file"); passwordFile != "" {
+			if err := readSecretFromFile(passwordFile, &password); err != nil {
+				return errors.Wrap(err, "could not read the Elasticsearch password")
+			}
+		}
+		if status.Entities, err = getElasticsearchIndexStats(es, password); err != nil {
+			printer.PrintWarning(fmt.Sprintf("Unable to get the Elasticsearch indexes: %s", err))
+		}
+		printer.PrintT(searchEngineStatusTemplate, status)
+	}
+
+	if *bleve.EnableIndexing {
+		status := &searchEngineStatus{Engine: "bleve", Indexing: true, Searching: *bleve.EnableSearching, Autocomplete: *bleve.EnableAutocomplete}
+		if status.LastJob, status.LastIndexedAt, err = getIndexingJobStatus(c, model.JobTypeBlevePostIndexing); err != nil {
+			return err
+		}
+		if status.Entities, err = getBleveIndexStats(*bleve.IndexDir); err != nil {
+			printer.PrintWarning(fmt.Sprintf("Unable to get the Bleve indexes: %s", err))
+		}
+		printer.PrintT(searchEngineStatusTemplate, status)
+	}
+
+	return nil
+}
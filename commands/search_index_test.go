@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSearchIndexStatusCmd() {
+	newCmd := func(passwordFile string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("elasticsearch-password-file", passwordFile, "")
+		return cmd
+	}
+
+	newConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		return config
+	}
+
+	s.Run("Should show the Elasticsearch indexes of each entity", func() {
+		printer.Clean()
+		var username, password string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, _ = r.BasicAuth()
+			s.Require().Equal("/_cat/indices/mm_*", r.URL.Path)
+			_, _ = w.Write([]byte(`[
+				{"index": "mm_posts_2022_01_01", "docs.count": "10", "store.size": "1000"},
+				{"index": "mm_posts_2022_01_02", "docs.count": "5", "store.size": "500"},
+				{"index": "mm_channels", "docs.count": "3", "store.size": "300"},
+				{"index": "mm_users", "docs.count": "2", "store.size": "200"}
+			]`))
+		}))
+		defer server.Close()
+
+		config := newConfig()
+		config.ElasticsearchSettings.EnableIndexing = model.NewBool(true)
+		config.ElasticsearchSettings.EnableSearching = model.NewBool(true)
+		config.ElasticsearchSettings.ConnectionURL = model.NewString(server.URL)
+		config.ElasticsearchSettings.IndexPrefix = model.NewString("mm_")
+		config.ElasticsearchSettings.Password = model.NewString(model.FakeSetting)
+
+		running := &model.Job{Id: model.NewId(), Type: model.JobTypeElasticsearchPostIndexing, Status: model.JobStatusInProgress, Progress: 40}
+		succeeded := &model.Job{Id: model.NewId(), Type: model.JobTypeElasticsearchPostIndexing, Status: model.JobStatusSuccess, Data: model.StringMap{"end_time": "1640995200000"}}
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeElasticsearchPostIndexing, 0, indexingJobsPerPage).
+			Return([]*model.Job{running, succeeded}, &model.Response{}, nil).
+			Times(1)
+
+		passwordFile := filepath.Join(s.T().TempDir(), "elasticsearch-password.txt")
+		s.Require().NoError(ioutil.WriteFile(passwordFile, []byte("secret\n"), 0600))
+
+		err := searchIndexStatusCmdF(s.client, newCmd(passwordFile), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal("elastic", username)
+		s.Require().Equal("secret", password)
+		s.Require().Equal([]interface{}{&searchEngineStatus{
+			Engine:        "elasticsearch",
+			Indexing:      true,
+			Searching:     true,
+			LastJob:       running,
+			LastIndexedAt: 1640995200000,
+			Entities: []*searchIndexEntity{
+				{Entity: "posts", Indexes: 2, Documents: model.NewInt64(15), SizeBytes: 1500},
+				{Entity: "files", Documents: model.NewInt64(0)},
+				{Entity: "channels", Indexes: 1, Documents: model.NewInt64(3), SizeBytes: 300},
+				{Entity: "users", Indexes: 1, Documents: model.NewInt64(2), SizeBytes: 200},
+			},
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should show the size of the Bleve indexes", func() {
+		printer.Clean()
+		indexDir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		defer os.RemoveAll(indexDir)
+		s.Require().NoError(os.MkdirAll(filepath.Join(indexDir, "posts.bleve", "store"), 0700))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(indexDir, "posts.bleve", "index_meta.json"), []byte("{}"), 0600))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(indexDir, "posts.bleve", "store", "root.bolt"), make([]byte, 100), 0600))
+
+		config := newConfig()
+		config.BleveSettings.EnableIndexing = model.NewBool(true)
+		config.BleveSettings.IndexDir = model.NewString(indexDir)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeBlevePostIndexing, 0, indexingJobsPerPage).
+			Return([]*model.Job{}, &model.Response{}, nil).
+			Times(1)
+
+		err = searchIndexStatusCmdF(s.client, newCmd(""), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&searchEngineStatus{
+			Engine:   "bleve",
+			Indexing: true,
+			Entities: []*searchIndexEntity{
+				{Entity: "posts", Indexes: 1, SizeBytes: 102},
+				{Entity: "files"},
+				{Entity: "channels"},
+				{Entity: "users"},
+			},
+		}}, printer.GetLines())
+	})
+
+	s.Run("Should fail if no search engine indexes", func() {
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := searchIndexStatusCmdF(s.client, newCmd(""), []string{})
+		s.Require().EqualError(err, "neither Elasticsearch nor Bleve indexing is enabled")
+	})
+
+	s.Run("Should fail if the indexing jobs can't be fetched", func() {
+		config := newConfig()
+		config.BleveSettings.EnableIndexing = model.NewBool(true)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetJobsByType(model.JobTypeBlevePostIndexing, 0, indexingJobsPerPage).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := searchIndexStatusCmdF(s.client, newCmd(""), []string{})
+		s.Require().EqualError(err, "failed to get the indexing jobs: mock error")
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var SessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Management of user sessions",
+}
+
+var SessionPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Revoke idle sessions",
+	Long: `Revoke the sessions of all users that have been idle for longer than the given duration.
+With --plan, the sessions that would be revoked are printed as a plan that can be reviewed and then applied with --apply-plan, without --idle-for.`,
+	Example: `  session prune --idle-for 720h
+  session prune --idle-for 168h --exclude-users sysadmin,user@example.com
+
+  # review the sessions before revoking them
+  session prune --idle-for 720h --plan > plan.json
+  session prune --apply-plan plan.json --confirm`,
+	Args: cobra.NoArgs,
+	RunE: withClient(withPlanActions(sessionPrunePlanResolver, applySessionPrunePlan, sessionPruneCmdF)),
+}
+
+func init() {
+	SessionPruneCmd.Flags().Duration("idle-for", 0, "Revoke sessions with no activity for longer than this duration, e.g. 720h")
+	SessionPruneCmd.Flags().StringSlice("exclude-users", []string{}, "Users whose sessions should not be revoked")
+	SessionPruneCmd.Flags().Bool("confirm", false, "Confirm you really want to revoke the idle sessions")
+	addPlanFlags(SessionPruneCmd)
+
+	SessionCmd.AddCommand(
+		SessionPruneCmd,
+	)
+	RootCmd.AddCommand(SessionCmd)
+}
+
+const planActionRevokeSession = "revoke_session"
+
+type sessionPruneResult struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Revoked  int    `json:"revoked"`
+	Failed   int    `json:"failed"`
+}
+
+// getIdleSessions returns the sessions idle for longer than --idle-for
+// of the users that aren't excluded, as the actions to revoke them. The
+// users whose sessions can't be listed are reported and skipped
+func getIdleSessions(c client.Client, cmd *cobra.Command) ([]planAction, error) {
+	idleFor, _ := cmd.Flags().GetDuration("idle-for")
+	if idleFor <= 0 {
+		return nil, errors.New("--idle-for must be a positive duration")
+	}
+
+	excludeArgs, _ := cmd.Flags().GetStringSlice("exclude-users")
+	excluded := map[string]bool{}
+	if len(excludeArgs) > 0 {
+		excludedUsers, err := getUsersFromArgs(c, excludeArgs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve excluded users: %w", err)
+		}
+		for _, user := range excludedUsers {
+			excluded[user.Id] = true
+		}
+	}
+
+	cutoff := model.GetMillisForTime(time.Now().Add(-idleFor))
+	actions := []planAction{}
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsers(page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch users")
+		}
+
+		for _, user := range users {
+			if excluded[user.Id] {
+				continue
+			}
+
+			sessions, _, err := c.GetSessions(user.Id, "")
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("unable to get sessions for user %q: %s", user.Username, err))
+				continue
+			}
+			for _, session := range sessions {
+				if session.LastActivityAt >= cutoff {
+					continue
+				}
+				actions = append(actions, planAction{
+					Action:     planActionRevokeSession,
+					ID:         session.Id,
+					Name:       "idle since " + model.GetTimeForMillis(session.LastActivityAt).UTC().Format(time.RFC3339),
+					TargetID:   user.Id,
+					TargetName: user.Username,
+				})
+			}
+		}
+
+		if len(users) < APILimitMaximum {
+			break
+		}
+	}
+
+	return actions, nil
+}
+
+// revokeSessions revokes the sessions of the actions, which are grouped
+// by user, and prints the sessions revoked of each user
+func revokeSessions(c client.Client, actions []planAction) {
+	total := 0
+	var result *sessionPruneResult
+	printResult := func() {
+		if result != nil {
+			total += result.Revoked
+			printer.PrintT("{{.Username}}: {{.Revoked}} sessions revoked", result)
+		}
+	}
+	for _, action := range actions {
+		if result == nil || result.UserID != action.TargetID {
+			printResult()
+			result = &sessionPruneResult{UserID: action.TargetID, Username: action.TargetName}
+		}
+
+		if _, err := c.RevokeSession(action.TargetID, action.ID); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to revoke session %q for user %q: %s", action.ID, action.TargetName, err))
+			result.Failed++
+			continue
+		}
+		result.Revoked++
+	}
+	printResult()
+
+	printer.PrintT("Total: {{.Revoked}} sessions revoked", struct {
+		Revoked int `json:"revoked"`
+	}{total})
+}
+
+func sessionPrunePlanResolver(c client.Client, cmd *cobra.Command, _ []string) ([]planAction, error) {
+	return getIdleSessions(c, cmd)
+}
+
+func applySessionPrunePlan(c client.Client, cmd *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, planActionRevokeSession); err != nil {
+		return err
+	}
+
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to revoke the %d sessions of the plan?", len(actions)), false); err != nil {
+			return err
+		}
+	}
+
+	revokeSessions(c, actions)
+	return nil
+}
+
+func sessionPruneCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	idleFor, _ := cmd.Flags().GetDuration("idle-for")
+	if idleFor <= 0 {
+		return errors.New("--idle-for must be a positive duration")
+	}
+
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to revoke all sessions idle for longer than %s?", idleFor), false); err != nil {
+			return err
+		}
+	}
+
+	actions, err := getIdleSessions(c, cmd)
+	if err != nil {
+		return err
+	}
+	revokeSessions(c, actions)
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSessionPruneCmd() {
+	idleTime := model.GetMillisForTime(time.Now().Add(-48 * time.Hour))
+	activeTime := model.GetMillis()
+
+	user1 := &model.User{Id: model.NewId(), Username: "user1"}
+	user2 := &model.User{Id: model.NewId(), Username: "user2", Email: "user2@example.com"}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("idle-for", 24*time.Hour, "")
+		cmd.Flags().StringSlice("exclude-users", []string{}, "")
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	s.Run("Should fail with a non positive duration", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("idle-for", "0s"))
+
+		err := sessionPruneCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "--idle-for must be a positive duration")
+	})
+
+	s.Run("Should revoke the idle sessions of all users", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{user1, user2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSessions(user1.Id, "").
+			Return([]*model.Session{
+				{Id: "session-1", LastActivityAt: idleTime},
+				{Id: "session-2", LastActivityAt: activeTime},
+				{Id: "session-3", LastActivityAt: idleTime},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSessions(user2.Id, "").
+			Return([]*model.Session{
+				{Id: "session-4", LastActivityAt: activeTime},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RevokeSession(user1.Id, "session-1").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RevokeSession(user1.Id, "session-3").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := sessionPruneCmdF(s.client, newCmd(), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&sessionPruneResult{UserID: user1.Id, Username: user1.Username, Revoked: 2}, printer.GetLines()[0])
+	})
+
+	s.Run("Should skip the excluded users", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("exclude-users", user2.Email))
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user2.Email, "").
+			Return(user2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{user1, user2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSessions(user1.Id, "").
+			Return([]*model.Session{
+				{Id: "session-1", LastActivityAt: idleTime},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RevokeSession(user1.Id, "session-1").
+			Return(nil, errors.New("mock error")).
+			Times(1)
+
+		err := sessionPruneCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Contains(printer.GetErrorLines()[0], `unable to revoke session "session-1" for user "user1"`)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&sessionPruneResult{UserID: user1.Id, Username: user1.Username, Failed: 1}, printer.GetLines()[0])
+	})
+
+	s.Run("Should plan the revocation of the idle sessions and apply the plan", func() {
+		printer.Clean()
+		cmd := newCmd()
+		cmd.Use = "prune"
+		addPlanFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("plan", "true"))
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{user1}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetSessions(user1.Id, "").
+			Return([]*model.Session{
+				{Id: "session-1", LastActivityAt: idleTime},
+				{Id: "session-2", LastActivityAt: activeTime},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := withPlanActions(sessionPrunePlanResolver, applySessionPrunePlan, sessionPruneCmdF)(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		plan := printer.GetLines()[0].(*commandPlan)
+		s.Require().Equal([]planAction{{
+			Action:     planActionRevokeSession,
+			ID:         "session-1",
+			Name:       "idle since " + model.GetTimeForMillis(idleTime).UTC().Format(time.RFC3339),
+			TargetID:   user1.Id,
+			TargetName: user1.Username,
+		}}, plan.Actions)
+
+		printer.Clean()
+		s.client.
+			EXPECT().
+			RevokeSession(user1.Id, "session-1").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err = applySessionPrunePlan(s.client, cmd, plan.Actions)
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&sessionPruneResult{UserID: user1.Id, Username: user1.Username, Revoked: 1}, printer.GetLines()[0])
+	})
+
+	s.Run("Should refuse the plans with actions of other commands", func() {
+		err := applySessionPrunePlan(s.client, newCmd(), []planAction{{Action: "delete_user", ID: user1.Id}})
+		s.Require().EqualError(err, `unsupported plan action "delete_user"`)
+	})
+
+	s.Run("Should fail if the users cannot be listed", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := sessionPruneCmdF(s.client, newCmd(), []string{})
+		s.Require().EqualError(err, "Failed to fetch users: mock error")
+	})
+}
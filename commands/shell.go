@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var ShellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run mmctl commands in an interactive shell",
+	Long: `Start an interactive shell that runs mmctl commands, written without the leading "mmctl", reusing a single authenticated client, so the commands don't pay the startup and login overhead of a new process each.
+The shell keeps the history of the commands, browsable with the up and down arrows, and completes the commands and flags with the tab key. Use "exit", "quit" or Ctrl-D to leave it.
+If the input isn't a terminal, the commands are read from it one per line, skipping the empty lines and the ones starting with "#", and the shell fails if any of them fails.
+The client of the shell is kept for the whole session, so the credentials set with "auth set" only apply after restarting it.
+The global flags given to the shell, like --json or --local, apply to all the commands of the session, and setting them in a command only overrides them for that command. The commands that set --local, --profile, --trace-otlp or the --insecure flags to other values than the shell create a client of their own for that command. The standard input can't be used as an argument inside the shell.`,
+	Example: `  shell
+
+  # print the output of all the commands as JSON
+  shell --json
+
+  # run a batch of commands with a single login
+  shell < commands.txt`,
+	Args: cobra.NoArgs,
+	RunE: withClient(shellCmdF),
+}
+
+func init() {
+	RootCmd.AddCommand(ShellCmd)
+}
+
+// shellClient is the client shared by the commands run from the
+// shell, which withClient uses instead of creating a new one
+var shellClient client.Client
+
+// shellGlobalFlags holds the values of the global flags given to the
+// shell, which the flags are restored to after each command
+var shellGlobalFlags = map[string]string{}
+
+// shellClientFlags are the global flags that change how the client is
+// created, so a command of the shell that sets them to other values than
+// the shell doesn't use the client of the shell
+var shellClientFlags = []string{"local", "profile", "trace-otlp", "insecure-sha1-intermediate", "insecure-tls-version"}
+
+const shellPrompt = "mmctl> "
+
+func shellCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	shellClient = c
+	shellGlobalFlags = getShellGlobalFlags()
+	defer func() {
+		shellClient = nil
+		shellGlobalFlags = map[string]string{}
+	}()
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return runShellScript(os.Stdin)
+	}
+	return runInteractiveShell()
+}
+
+func runInteractiveShell() error {
+	fd := int(os.Stdin.Fd())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, matches := completeShellLine(line, pos)
+		if len(matches) > 1 {
+			fmt.Fprintf(t, "%s\n", strings.Join(matches, "  "))
+		}
+		return newLine, newPos, newLine != line
+	}
+
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return errors.Wrap(err, "failed to set up the terminal")
+		}
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+		if err == io.EOF {
+			fmt.Println("")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		exit, _ := runShellLine(line)
+		if exit {
+			return nil
+		}
+	}
+}
+
+func runShellScript(r io.Reader) error {
+	failed := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		exit, err := runShellLine(scanner.Text())
+		if err != nil {
+			failed++
+		}
+		if exit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read the commands")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d commands failed", failed)
+	}
+	return nil
+}
+
+// runShellLine runs the command of a line of the shell, returning
+// whether the shell should exit
+func runShellLine(line string) (bool, error) {
+	args, err := splitShellLine(line)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return false, err
+	}
+	if len(args) > 0 && args[0] == "mmctl" {
+		args = args[1:]
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true, nil
+	case "shell":
+		err := errors.New("the shell is already running")
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return false, err
+	}
+
+	printer.Clean()
+	printer.SetSingle(false)
+	printer.SetNoNewline(false)
+
+	executed, err := executeRootCmd(args)
+	if executed != nil {
+		resetShellFlags(executed)
+	}
+	return false, err
+}
+
+// shellClientFlagsChanged returns whether the command being run set
+// any of the client flags to another value than the one of the shell
+func shellClientFlagsChanged() bool {
+	for _, name := range shellClientFlags {
+		f := RootCmd.PersistentFlags().Lookup(name)
+		if f == nil {
+			continue
+		}
+		value, ok := shellGlobalFlags[name]
+		if !ok {
+			value = f.DefValue
+		}
+		if f.Value.String() != value {
+			return true
+		}
+	}
+	return false
+}
+
+// getShellGlobalFlags returns the global flags set when starting the
+// shell
+func getShellGlobalFlags() map[string]string {
+	flags := map[string]string{}
+	RootCmd.PersistentFlags().Visit(func(f *pflag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+	return flags
+}
+
+// resetShellFlags restores the flags set by a command to their default
+// values, or to the ones of the global flags of the shell, as the
+// commands are reused by the following lines
+func resetShellFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if value, ok := shellGlobalFlags[f.Name]; ok && RootCmd.PersistentFlags().Lookup(f.Name) == f {
+			_ = f.Value.Set(value)
+			f.Changed = true
+			return
+		}
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			defaults := []string{}
+			if value := strings.Trim(f.DefValue, "[]"); value != "" {
+				defaults = strings.Split(value, ",")
+			}
+			_ = slice.Replace(defaults)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// splitShellLine splits a line into arguments as a shell would,
+// supporting single and double quotes and escaping with backslashes
+func splitShellLine(line string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// completeShellLine completes the word under the cursor with the
+// subcommands or the flags of the command of the line, returning the
+// new line and cursor position along with all the candidates
+func completeShellLine(line string, pos int) (string, int, []string) {
+	prefix := line[:pos]
+	fields := strings.Fields(prefix)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(prefix, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	cmd := RootCmd
+	for _, field := range fields {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, sub := range cmd.Commands() {
+			if sub.Name() == field || sub.HasAlias(field) {
+				cmd = sub
+				break
+			}
+		}
+	}
+
+	candidates := map[string]bool{}
+	if strings.HasPrefix(word, "-") {
+		addFlag := func(f *pflag.Flag) {
+			if !f.Hidden {
+				candidates["--"+f.Name] = true
+			}
+		}
+		cmd.Flags().VisitAll(addFlag)
+		cmd.InheritedFlags().VisitAll(addFlag)
+	} else {
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				candidates[sub.Name()] = true
+			}
+		}
+	}
+
+	matches := []string{}
+	for candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) == 0 {
+		return line, pos, matches
+	}
+
+	completion := matches[0]
+	if len(matches) == 1 {
+		if !strings.HasPrefix(line[pos:], " ") {
+			completion += " "
+		}
+	} else {
+		for _, match := range matches[1:] {
+			for !strings.HasPrefix(match, completion) {
+				completion = completion[:len(completion)-1]
+			}
+		}
+	}
+
+	start := pos - len(word)
+	return line[:start] + completion + line[pos:], start + len(completion), matches
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+)
+
+func (s *MmctlUnitTestSuite) TestRunShellScript() {
+	s.Run("Should run all the commands with the shared client", func() {
+		shellClient = s.client
+		defer func() { shellClient = nil }()
+
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{ServerVersion: "6.3.0"}, nil).
+			Times(2)
+
+		script := "system version\n\n# a comment\nmmctl system version\nexit\nsystem version\n"
+		err := runShellScript(strings.NewReader(script))
+		s.Require().NoError(err)
+	})
+
+	s.Run("Should fail if any of the commands fails", func() {
+		shellClient = s.client
+		defer func() { shellClient = nil }()
+
+		err := runShellScript(strings.NewReader("shell\nsystem version 'unterminated\n"))
+		s.Require().EqualError(err, "2 commands failed")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSplitShellLine() {
+	args, err := splitShellLine(`post create myteam:town-square --message "hello world" --reply-to 'it'"'"'s' a\ b`)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"post", "create", "myteam:town-square", "--message", "hello world", "--reply-to", "it's", "a b"}, args)
+
+	args, err = splitShellLine(`  user list  ""  `)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"user", "list", ""}, args)
+
+	_, err = splitShellLine(`user search "john`)
+	s.Require().EqualError(err, "unterminated quote or escape")
+}
+
+func (s *MmctlUnitTestSuite) TestCompleteShellLine() {
+	s.Run("Should complete a unique subcommand", func() {
+		line, pos, matches := completeShellLine("webhook rot", 11)
+		s.Require().Equal("webhook rotate-token ", line)
+		s.Require().Equal(len(line), pos)
+		s.Require().Equal([]string{"rotate-token"}, matches)
+	})
+
+	s.Run("Should complete the common prefix of the flags", func() {
+		line, _, matches := completeShellLine("channel archive --exp", 21)
+		s.Require().Equal("channel archive --export-", line)
+		s.Require().Equal([]string{"--export-dir", "--export-first"}, matches)
+	})
+
+	s.Run("Should keep the rest of the line", func() {
+		line, pos, _ := completeShellLine("team lis myteam", 8)
+		s.Require().Equal("team list myteam", line)
+		s.Require().Equal(9, pos)
+	})
+
+	s.Run("Should not change the line without matches", func() {
+		line, pos, matches := completeShellLine("nonexistent", 11)
+		s.Require().Equal("nonexistent", line)
+		s.Require().Equal(11, pos)
+		s.Require().Empty(matches)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestResetShellFlags() {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("dry-run", false, "")
+	cmd.Flags().String("team", "default", "")
+	cmd.Flags().StringSlice("expand", nil, "")
+	s.Require().NoError(cmd.Flags().Parse([]string{"--dry-run", "--team", "myteam", "--expand", "creator,team"}))
+
+	resetShellFlags(cmd)
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	team, _ := cmd.Flags().GetString("team")
+	expand, _ := cmd.Flags().GetStringSlice("expand")
+	s.Require().False(dryRun)
+	s.Require().Equal("default", team)
+	s.Require().Empty(expand)
+	s.Require().False(cmd.Flags().Changed("team"))
+
+	s.Require().NoError(cmd.Flags().Parse([]string{"--expand", "team"}))
+	expand, _ = cmd.Flags().GetStringSlice("expand")
+	s.Require().Equal([]string{"team"}, expand)
+}
+
+func (s *MmctlUnitTestSuite) TestResetShellGlobalFlags() {
+	// the global flags are defined when running mmctl
+	if RootCmd.PersistentFlags().Lookup("json") == nil {
+		RootCmd.PersistentFlags().Bool("json", false, "")
+	}
+	jsonFlag := RootCmd.PersistentFlags().Lookup("json")
+	defer func() {
+		_ = jsonFlag.Value.Set(jsonFlag.DefValue)
+		jsonFlag.Changed = false
+		shellGlobalFlags = map[string]string{}
+	}()
+
+	s.Require().NoError(RootCmd.PersistentFlags().Set("json", "true"))
+	shellGlobalFlags = getShellGlobalFlags()
+	s.Require().Equal(map[string]string{"json": "true"}, shellGlobalFlags)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().AddFlag(jsonFlag)
+	cmd.Flags().Bool("dry-run", false, "")
+	s.Require().NoError(cmd.Flags().Parse([]string{"--json=false", "--dry-run"}))
+
+	resetShellFlags(cmd)
+
+	json, _ := cmd.Flags().GetBool("json")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	s.Require().True(json)
+	s.Require().True(cmd.Flags().Changed("json"))
+	s.Require().False(dryRun)
+}
+
+func (s *MmctlUnitTestSuite) TestShellClientFlagsChanged() {
+	// the global flags are defined when running mmctl
+	if RootCmd.PersistentFlags().Lookup("profile") == nil {
+		RootCmd.PersistentFlags().String("profile", "", "")
+	}
+	profileFlag := RootCmd.PersistentFlags().Lookup("profile")
+	defer func() {
+		_ = profileFlag.Value.Set(profileFlag.DefValue)
+		shellGlobalFlags = map[string]string{}
+	}()
+
+	s.Require().False(shellClientFlagsChanged())
+
+	s.Require().NoError(profileFlag.Value.Set("staging"))
+	s.Require().True(shellClientFlagsChanged())
+
+	shellGlobalFlags = map[string]string{"profile": "staging"}
+	s.Require().False(shellClientFlagsChanged())
+}
+
+func (s *MmctlUnitTestSuite) TestWithClientInShell() {
+	shellClient = s.client
+	defer func() { shellClient = nil }()
+
+	s.Run("Should use the client of the shell", func() {
+		var used interface{}
+		err := withClient(func(c client.Client, cmd *cobra.Command, args []string) error {
+			used = c
+			return nil
+		})(&cobra.Command{}, []string{"user-id"})
+		s.Require().NoError(err)
+		s.Require().Equal(s.client, used)
+	})
+
+	s.Run("Should reject the standard input as an argument", func() {
+		err := withClient(func(c client.Client, cmd *cobra.Command, args []string) error {
+			s.Fail("the command shouldn't run")
+			return nil
+		})(&cobra.Command{}, []string{"-"})
+		s.Require().EqualError(err, "the standard input can't be used in the arguments of the commands of the shell")
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// stdinArg is the argument replaced with the entities read from the
+// standard input, e.g. channel list myteam --id-only | channel archive -
+const stdinArg = "-"
+
+const defaultStdinBatchSize = 200
+
+// stdinArgsReader is where the arguments given with "-" are read from,
+// and is replaced in the tests
+var stdinArgsReader io.Reader = os.Stdin
+
+// withStdinArgs calls fn with the arguments of the command, replacing
+// "-" with the entities read from the standard input. They are read one
+// per line or separated by spaces, and fn is called for each batch of
+// them so that long inputs don't need to be read before starting. When
+// a plan or a dry run is requested, fn is called once with all of them,
+// so that a single plan covers the whole input
+func withStdinArgs(cmd *cobra.Command, args []string, fn func(args []string) error) error {
+	position := -1
+	for i, arg := range args {
+		if arg != stdinArg {
+			continue
+		}
+		if position != -1 {
+			return errors.New("the standard input can only be used once in the arguments")
+		}
+		position = i
+	}
+	if position == -1 {
+		return fn(args)
+	}
+
+	planOnly, _ := cmd.Flags().GetBool("plan")
+	dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+	preview := planOnly || dryRunFlag || viper.GetBool("dry-run")
+
+	// the confirmation prompt would read from the same standard input
+	// that holds the arguments
+	if confirm, err := cmd.Flags().GetBool("confirm"); err == nil && !confirm && !preview {
+		return errors.New("the --confirm flag is required when the arguments are read from the standard input")
+	}
+
+	batchSize := viper.GetInt("stdin-batch-size")
+	if batchSize < 1 {
+		batchSize = defaultStdinBatchSize
+	}
+
+	read := 0
+	batch := []string{}
+	runBatch := func() error {
+		batchArgs := append(append(append([]string{}, args[:position]...), batch...), args[position+1:]...)
+		batch = []string{}
+		if cmd.Args != nil {
+			if err := cmd.Args(cmd, batchArgs); err != nil {
+				return err
+			}
+		}
+		return fn(batchArgs)
+	}
+
+	scanner := bufio.NewScanner(stdinArgsReader)
+	for scanner.Scan() {
+		for _, field := range strings.Fields(scanner.Text()) {
+			batch = append(batch, field)
+			read++
+			if !preview && len(batch) == batchSize {
+				if err := runBatch(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read the arguments from the standard input")
+	}
+
+	if read == 0 {
+		return errors.New("no arguments were read from the standard input")
+	}
+	if len(batch) > 0 {
+		return runBatch()
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestWithStdinArgs() {
+	withStdin := func(input string, f func()) {
+		original := stdinArgsReader
+		defer func() { stdinArgsReader = original }()
+		stdinArgsReader = strings.NewReader(input)
+		f()
+	}
+
+	s.Run("Should call the command with the arguments as they are without -", func() {
+		calls := [][]string{}
+		err := withStdinArgs(&cobra.Command{}, []string{"myteam", "mychannel"}, func(args []string) error {
+			calls = append(calls, args)
+			return nil
+		})
+		s.Require().NoError(err)
+		s.Require().Equal([][]string{{"myteam", "mychannel"}}, calls)
+	})
+
+	s.Run("Should replace - with the arguments read in batches", func() {
+		defer viper.Set("stdin-batch-size", defaultStdinBatchSize)
+		viper.Set("stdin-batch-size", 2)
+
+		withStdin("user1\n\nuser2 user3\nuser4\nuser5\n", func() {
+			calls := [][]string{}
+			err := withStdinArgs(&cobra.Command{}, []string{"myteam:mychannel", "-"}, func(args []string) error {
+				calls = append(calls, args)
+				return nil
+			})
+			s.Require().NoError(err)
+			s.Require().Equal([][]string{
+				{"myteam:mychannel", "user1", "user2"},
+				{"myteam:mychannel", "user3", "user4"},
+				{"myteam:mychannel", "user5"},
+			}, calls)
+		})
+	})
+
+	s.Run("Should validate the arguments of each batch", func() {
+		withStdin("channel1\nchannel2\n", func() {
+			cmd := &cobra.Command{Args: cobra.ExactArgs(1)}
+			err := withStdinArgs(cmd, []string{"-"}, func(args []string) error {
+				s.Fail("the command should not run")
+				return nil
+			})
+			s.Require().EqualError(err, "accepts 1 arg(s), received 2")
+		})
+	})
+
+	s.Run("Should fail without arguments in the standard input", func() {
+		withStdin("\n  \n", func() {
+			err := withStdinArgs(&cobra.Command{}, []string{"-"}, func(args []string) error { return nil })
+			s.Require().EqualError(err, "no arguments were read from the standard input")
+		})
+
+		err := withStdinArgs(&cobra.Command{}, []string{"-", "-"}, func(args []string) error { return nil })
+		s.Require().EqualError(err, "the standard input can only be used once in the arguments")
+	})
+
+	s.Run("Should call the command once with all the arguments when a plan is requested", func() {
+		defer viper.Set("stdin-batch-size", defaultStdinBatchSize)
+		viper.Set("stdin-batch-size", 2)
+
+		withStdin("user1 user2 user3\n", func() {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("plan", true, "")
+			cmd.Flags().Bool("confirm", false, "")
+			calls := [][]string{}
+			err := withStdinArgs(cmd, []string{"-"}, func(args []string) error {
+				calls = append(calls, args)
+				return nil
+			})
+			s.Require().NoError(err)
+			s.Require().Equal([][]string{{"user1", "user2", "user3"}}, calls)
+		})
+	})
+
+	s.Run("Should require --confirm when the arguments are read from the standard input", func() {
+		withStdin("user1\n", func() {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("confirm", false, "")
+			err := withStdinArgs(cmd, []string{"-"}, func(args []string) error {
+				s.Fail("the command should not run")
+				return nil
+			})
+			s.Require().EqualError(err, "the --confirm flag is required when the arguments are read from the standard input")
+		})
+	})
+
+	s.Run("Should archive the channels read from the standard input", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: "channel-id", Name: "mychannel"}
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteChannel(channel.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		withStdin(channel.Id+"\n", func() {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("export-first", false, "")
+			cmd.Flags().String("export-dir", "", "")
+			err := withStdinArgs(cmd, []string{"-"}, func(args []string) error {
+				return archiveChannelsCmdF(s.client, cmd, args)
+			})
+			s.Require().NoError(err)
+			s.Require().Empty(printer.GetErrorLines())
+		})
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SystemErrorBudgetCmd = &cobra.Command{
+	Use:   "error-budget",
+	Short: "Error budget of the server",
+}
+
+var SystemErrorBudgetReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report the availability and error rate of the server over a window",
+	Long: `Samples the server during the window, and reports its availability, its error rate and how much of the error budget of the SLO they consume.
+On each sample the server is pinged, and the availability is the percentage of successful pings. The error rate is the percentage of the HTTP requests served during the window that failed, computed from the mattermost_http_requests_total and mattermost_http_errors_total counters of the metrics endpoint of the server, which needs the performance monitoring of an Enterprise license to be enabled. If the metrics can't be read, only the availability is reported.
+The consumed budget is the worst of the unavailability and the error rate, relative to the errors allowed by the SLO, so a value over 100% means the SLO is not met in the window.`,
+	Example: `  system error-budget report
+  system error-budget report --window 1h --interval 1m --slo 99.5
+  system error-budget report --metrics-url http://10.0.0.5:8067/metrics`,
+	Args: cobra.NoArgs,
+	RunE: withClient(systemErrorBudgetReportCmdF),
+}
+
+func init() {
+	SystemErrorBudgetReportCmd.Flags().Duration("window", 5*time.Minute, "Duration of the window to sample the server during")
+	SystemErrorBudgetReportCmd.Flags().Duration("interval", 30*time.Second, "Time between the samples")
+	SystemErrorBudgetReportCmd.Flags().Float64("slo", 99.9, "Target percentage of successful requests")
+	SystemErrorBudgetReportCmd.Flags().String("metrics-url", "", "URL of the metrics endpoint, defaults to the listen address of the metrics settings of the server")
+
+	SystemErrorBudgetCmd.AddCommand(SystemErrorBudgetReportCmd)
+	SystemCmd.AddCommand(SystemErrorBudgetCmd)
+}
+
+const (
+	errorBudgetRequestsMetric = "mattermost_http_requests_total"
+	errorBudgetErrorsMetric   = "mattermost_http_errors_total"
+)
+
+// errorBudgetSleep is used to wait between the samples and can be
+// overridden in the tests
+var errorBudgetSleep = time.Sleep
+
+type errorBudgetSample struct {
+	up         bool
+	hasMetrics bool
+	requests   float64
+	errors     float64
+}
+
+type errorBudgetReport struct {
+	Window         string   `json:"window"`
+	Samples        int      `json:"samples"`
+	FailedPings    int      `json:"failed_pings"`
+	Availability   float64  `json:"availability"`
+	Requests       *float64 `json:"requests,omitempty"`
+	Errors         *float64 `json:"errors,omitempty"`
+	ErrorRate      *float64 `json:"error_rate,omitempty"`
+	SLO            float64  `json:"slo"`
+	BudgetConsumed float64  `json:"budget_consumed"`
+}
+
+// String renders the report for the plain output
+func (r *errorBudgetReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Window: %s (%d samples)\n", r.Window, r.Samples)
+	fmt.Fprintf(&sb, "Availability: %.3f%% (%d failed pings)\n", r.Availability, r.FailedPings)
+	if r.ErrorRate != nil {
+		fmt.Fprintf(&sb, "Error rate: %.3f%% (%.0f errors in %.0f requests)\n", *r.ErrorRate, *r.Errors, *r.Requests)
+	} else {
+		sb.WriteString("Error rate: unknown, the metrics couldn't be read\n")
+	}
+	fmt.Fprintf(&sb, "Error budget for a %g%% SLO: %.1f%% consumed", r.SLO, r.BudgetConsumed)
+	return sb.String()
+}
+
+// parseMetricsCounter sums the samples of a metric in the Prometheus
+// text format across all their labels
+func parseMetricsCounter(r io.Reader, name string) (float64, bool, error) {
+	var total float64
+	found := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || !strings.HasPrefix(line, name) {
+			continue
+		}
+
+		rest := line[len(name):]
+		switch {
+		case strings.HasPrefix(rest, "{"):
+			end := strings.LastIndex(rest, "}")
+			if end == -1 {
+				continue
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, " "):
+		default:
+			// another metric sharing the prefix of the name
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid value for metric %s: %w", name, err)
+		}
+		total += value
+		found = true
+	}
+	return total, found, scanner.Err()
+}
+
+// getMetricsURL builds the URL of the metrics endpoint from the
+// metrics settings of the server and the host of the credentials
+func getMetricsURL(c client.Client) (string, error) {
+	if viper.GetBool("local") {
+		return "", errors.New("the metrics URL can't be guessed in local mode, use --metrics-url")
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the config")
+	}
+	if !*config.MetricsSettings.Enable {
+		return "", errors.New("the metrics of the server are disabled, they can be enabled with the MetricsSettings.Enable setting")
+	}
+
+	credentials, err := GetCurrentCredentials()
+	if err != nil {
+		return "", err
+	}
+	instanceURL, err := url.Parse(credentials.InstanceURL)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid instance URL")
+	}
+
+	host, port, err := net.SplitHostPort(*config.MetricsSettings.ListenAddress)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid metrics listen address")
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = instanceURL.Hostname()
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/metrics", nil
+}
+
+func scrapeErrorBudgetMetrics(metricsURL string) (requests, errs float64, err error) {
+	resp, err := http.Get(metricsURL) //nolint:gosec
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, err
+	}
+	requests, found, err := parseMetricsCounter(bytes.NewReader(body), errorBudgetRequestsMetric)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return 0, 0, fmt.Errorf("metric %s not found", errorBudgetRequestsMetric)
+	}
+	// the errors counter isn't present until the first error happens
+	errs, _, err = parseMetricsCounter(bytes.NewReader(body), errorBudgetErrorsMetric)
+	if err != nil {
+		return 0, 0, err
+	}
+	return requests, errs, nil
+}
+
+// counterDelta returns the increase of a counter between two samples,
+// taking a restart of the server into account
+func counterDelta(first, last float64) float64 {
+	if last < first {
+		return last
+	}
+	return last - first
+}
+
+func roundPercentage(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+func buildErrorBudgetReport(samples []errorBudgetSample, window time.Duration, slo float64) *errorBudgetReport {
+	report := &errorBudgetReport{Window: window.String(), Samples: len(samples), SLO: slo}
+
+	var first, last *errorBudgetSample
+	for i := range samples {
+		if !samples[i].up {
+			report.FailedPings++
+		}
+		if samples[i].hasMetrics {
+			if first == nil {
+				first = &samples[i]
+			}
+			last = &samples[i]
+		}
+	}
+
+	unavailability := 0.0
+	if len(samples) > 0 {
+		unavailability = float64(report.FailedPings) / float64(len(samples)) * 100
+	}
+	report.Availability = roundPercentage(100 - unavailability)
+
+	worst := unavailability
+	if first != nil && last != first {
+		requests := counterDelta(first.requests, last.requests)
+		errs := counterDelta(first.errors, last.errors)
+		errorRate := 0.0
+		if requests > 0 {
+			errorRate = errs / requests * 100
+		}
+		report.Requests = &requests
+		report.Errors = &errs
+		roundedErrorRate := roundPercentage(errorRate)
+		report.ErrorRate = &roundedErrorRate
+		worst = math.Max(worst, errorRate)
+	}
+
+	if allowed := 100 - slo; allowed > 0 {
+		report.BudgetConsumed = math.Round(worst/allowed*1000) / 10
+	}
+	return report
+}
+
+func systemErrorBudgetReportCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	window, _ := cmd.Flags().GetDuration("window")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	slo, _ := cmd.Flags().GetFloat64("slo")
+	metricsURL, _ := cmd.Flags().GetString("metrics-url")
+
+	if interval <= 0 || window < interval {
+		return errors.New("the interval must be greater than 0 and not longer than the window")
+	}
+	if slo <= 0 || slo >= 100 {
+		return errors.New("the SLO must be a percentage between 0 and 100")
+	}
+
+	var metricsWarnings []string
+	if metricsURL == "" {
+		var err error
+		if metricsURL, err = getMetricsURL(c); err != nil {
+			metricsWarnings = append(metricsWarnings, err.Error())
+		}
+	}
+
+	count := int(window/interval) + 1
+	samples := make([]errorBudgetSample, 0, count)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			errorBudgetSleep(interval)
+		}
+
+		sample := errorBudgetSample{}
+		_, _, err := c.GetPing()
+		sample.up = err == nil
+		if metricsURL != "" {
+			requests, errs, err := scrapeErrorBudgetMetrics(metricsURL)
+			if err != nil {
+				metricsWarnings = append(metricsWarnings, fmt.Sprintf("unable to read the metrics of sample %d: %s", i+1, err))
+			} else {
+				sample.hasMetrics = true
+				sample.requests = requests
+				sample.errors = errs
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	report := buildErrorBudgetReport(samples, window, slo)
+	for _, warning := range metricsWarnings {
+		printer.PrintWarning(warning)
+	}
+	printer.PrintT("{{.String}}", report)
+
+	return nil
+}
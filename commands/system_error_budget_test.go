@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemErrorBudgetReportCmd() {
+	newCmd := func(metricsURL string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("window", 2*time.Minute, "")
+		cmd.Flags().Duration("interval", time.Minute, "")
+		cmd.Flags().Float64("slo", 99, "")
+		cmd.Flags().String("metrics-url", metricsURL, "")
+		return cmd
+	}
+
+	errorBudgetSleep = func(time.Duration) {}
+	defer func() { errorBudgetSleep = time.Sleep }()
+
+	s.Run("Should compute the availability and error rate of the window", func() {
+		printer.Clean()
+
+		scrapes := 0
+		counters := [][2]int{{1000, 10}, {1500, 12}, {2000, 14}}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counter := counters[scrapes]
+			scrapes++
+			fmt.Fprintf(w, "# TYPE mattermost_http_requests_total counter\n")
+			fmt.Fprintf(w, "mattermost_http_requests_total %d\n", counter[0])
+			fmt.Fprintf(w, "mattermost_http_requests_total_other 5\n")
+			fmt.Fprintf(w, "mattermost_http_errors_total{code=\"500\"} %d\n", counter[1]/2)
+			fmt.Fprintf(w, "mattermost_http_errors_total{code=\"503\"} %d\n", counter[1]/2)
+		}))
+		defer server.Close()
+
+		firstPings := s.client.EXPECT().GetPing()
+		firstPings.Return("OK", &model.Response{}, nil).Times(2)
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("", &model.Response{}, errors.New("mock error")).
+			Times(1).
+			After(firstPings)
+
+		err := systemErrorBudgetReportCmdF(s.client, newCmd(server.URL), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		report := printer.GetLines()[0].(*errorBudgetReport)
+		s.Require().Equal(3, report.Samples)
+		s.Require().Equal(1, report.FailedPings)
+		s.Require().Equal(66.667, report.Availability)
+		s.Require().Equal(1000.0, *report.Requests)
+		s.Require().Equal(4.0, *report.Errors)
+		s.Require().Equal(0.4, *report.ErrorRate)
+		s.Require().Equal(3333.3, report.BudgetConsumed)
+	})
+
+	s.Run("Should report only the availability if the metrics can't be read", func() {
+		printer.Clean()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		s.client.
+			EXPECT().
+			GetPing().
+			Return("OK", &model.Response{}, nil).
+			Times(3)
+
+		err := systemErrorBudgetReportCmdF(s.client, newCmd(server.URL), []string{})
+		s.Require().NoError(err)
+		report := printer.GetLines()[0].(*errorBudgetReport)
+		s.Require().Equal(100.0, report.Availability)
+		s.Require().Nil(report.ErrorRate)
+		s.Require().Equal(0.0, report.BudgetConsumed)
+		s.Require().True(strings.HasPrefix(report.String(), "Window: 2m0s (3 samples)"))
+	})
+
+	s.Run("Should fail with an interval longer than the window", func() {
+		cmd := newCmd("")
+		_ = cmd.Flags().Set("interval", "1h")
+
+		err := systemErrorBudgetReportCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the interval must be greater than 0 and not longer than the window")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestParseMetricsCounter() {
+	metrics := `# HELP mattermost_http_errors_total The total number of http API errors.
+mattermost_http_errors_total{code="400"} 3
+mattermost_http_errors_total{code="500",path="/api/v4/users"} 2.5e+00 1633024800000
+mattermost_http_errors_totally 100
+`
+
+	total, found, err := parseMetricsCounter(strings.NewReader(metrics), "mattermost_http_errors_total")
+	s.Require().NoError(err)
+	s.Require().True(found)
+	s.Require().Equal(5.5, total)
+
+	_, found, err = parseMetricsCounter(strings.NewReader(metrics), "mattermost_http_requests_total")
+	s.Require().NoError(err)
+	s.Require().False(found)
+}
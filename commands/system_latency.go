@@ -0,0 +1,175 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SystemLatencyReportCmd = &cobra.Command{
+	Use:   "latency-report",
+	Short: "Report the latency of representative API endpoints",
+	Long: `Sends the requests to get the current user, the posts of a channel and a search in the team of the channel a number of times, and reports the p50 and p95 latencies of each of them. Run it before and after an infrastructure change from the same machine to compare the results.
+If --login-id and --password-file are given, the login endpoint is measured as well. Each login creates a session that is revoked right after, and the logout that revokes it is reported on its own row. As the login replaces the session of the command, it is measured last.`,
+	Example: `  system latency-report --channel myteam:town-square
+  system latency-report --channel myteam:town-square --iterations 50 --terms "release notes"
+  system latency-report --channel myteam:town-square --login-id loadtest-user --password-file password.txt`,
+	Args: cobra.NoArgs,
+	RunE: withClient(systemLatencyReportCmdF),
+}
+
+func init() {
+	SystemLatencyReportCmd.Flags().IntP("iterations", "n", 10, "Number of requests to send to each endpoint")
+	SystemLatencyReportCmd.Flags().String("channel", "", "Channel to get the posts of and to search in the team of")
+	SystemLatencyReportCmd.Flags().String("terms", "test", "Terms of the search")
+	SystemLatencyReportCmd.Flags().String("login-id", "", "Username or email of the user to log in as to measure the login endpoint")
+	SystemLatencyReportCmd.Flags().String("password-file", "", "File containing the password of the user given with --login-id")
+	_ = SystemLatencyReportCmd.MarkFlagRequired("channel")
+
+	SystemCmd.AddCommand(SystemLatencyReportCmd)
+}
+
+type latencyReport struct {
+	Endpoint string  `json:"endpoint"`
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	P50Ms    float64 `json:"p50_ms"`
+	P95Ms    float64 `json:"p95_ms"`
+}
+
+// latencyPercentile returns the nearest rank percentile of a sorted
+// list of latencies
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// setLatencyPercentiles sets the percentiles of the report from the
+// latencies of the successful requests
+func setLatencyPercentiles(report *latencyReport, latencies []time.Duration) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.P50Ms = durationToMs(latencyPercentile(latencies, 50))
+	report.P95Ms = durationToMs(latencyPercentile(latencies, 95))
+}
+
+// measureEndpoint sends the request the given number of times and
+// builds the report with the latencies of the successful ones
+func measureEndpoint(endpoint string, iterations int, request func() error) latencyReport {
+	report := latencyReport{Endpoint: endpoint, Requests: iterations}
+	latencies := []time.Duration{}
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		if err := request(); err != nil {
+			report.Errors++
+			continue
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+
+	setLatencyPercentiles(&report, latencies)
+	return report
+}
+
+// measureLogin logs in the given number of times, and reports the
+// latencies of the logins and of the logouts that revoke their sessions
+// apart, a logout being sent after each successful login
+func measureLogin(c client.Client, loginID, password string, iterations int) []latencyReport {
+	login := latencyReport{Endpoint: "login", Requests: iterations}
+	logout := latencyReport{Endpoint: "logout"}
+	loginLatencies, logoutLatencies := []time.Duration{}, []time.Duration{}
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		if _, _, err := c.Login(loginID, password); err != nil {
+			login.Errors++
+			continue
+		}
+		loginLatencies = append(loginLatencies, time.Since(start))
+
+		logout.Requests++
+		start = time.Now()
+		if _, err := c.Logout(); err != nil {
+			logout.Errors++
+			continue
+		}
+		logoutLatencies = append(logoutLatencies, time.Since(start))
+	}
+
+	setLatencyPercentiles(&login, loginLatencies)
+	setLatencyPercentiles(&logout, logoutLatencies)
+	return []latencyReport{login, logout}
+}
+
+func systemLatencyReportCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	channelArg, _ := cmd.Flags().GetString("channel")
+	terms, _ := cmd.Flags().GetString("terms")
+	loginID, _ := cmd.Flags().GetString("login-id")
+	passwordFile, _ := cmd.Flags().GetString("password-file")
+
+	if iterations < 1 {
+		return errors.New("the number of iterations must be greater than 0")
+	}
+	if (loginID == "") != (passwordFile == "") {
+		return errors.New("--login-id and --password-file must be used together")
+	}
+	var password string
+	if err := readSecretFromFile(passwordFile, &password); err != nil {
+		return fmt.Errorf("could not read the password: %w", err)
+	}
+
+	channel := getChannelFromChannelArg(c, channelArg)
+	if channel == nil {
+		return fmt.Errorf("unable to find channel %q", channelArg)
+	}
+
+	reports := []latencyReport{
+		measureEndpoint("get user", iterations, func() error {
+			_, _, err := c.GetMe("")
+			return err
+		}),
+		measureEndpoint("channel posts", iterations, func() error {
+			_, _, err := c.GetPostsForChannel(channel.Id, 0, 60, "", false)
+			return err
+		}),
+		measureEndpoint("search", iterations, func() error {
+			_, _, err := c.SearchPosts(channel.TeamId, terms, false)
+			return err
+		}),
+	}
+	if loginID != "" {
+		reports = append(reports, measureLogin(c, loginID, password, iterations)...)
+	}
+
+	failed := 0
+	for _, report := range reports {
+		failed += report.Errors
+		printer.PrintT("{{.Endpoint}}: p50 {{.P50Ms}}ms, p95 {{.P95Ms}}ms ({{.Requests}} requests, {{.Errors}} errors)", report)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d requests failed", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 1; i <= 20; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	require.Equal(t, 10*time.Millisecond, latencyPercentile(latencies, 50))
+	require.Equal(t, 19*time.Millisecond, latencyPercentile(latencies, 95))
+	require.Equal(t, time.Millisecond, latencyPercentile(latencies[:1], 95))
+	require.Equal(t, time.Duration(0), latencyPercentile(nil, 50))
+}
+
+func (s *MmctlUnitTestSuite) TestSystemLatencyReportCmd() {
+	channel := &model.Channel{Id: "channel-id", Name: "town-square", TeamId: "team-id"}
+
+	newCmd := func(loginID, passwordFile string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("iterations", 3, "")
+		cmd.Flags().String("channel", channel.Id, "")
+		cmd.Flags().String("terms", "test", "")
+		cmd.Flags().String("login-id", loginID, "")
+		cmd.Flags().String("password-file", passwordFile, "")
+		return cmd
+	}
+
+	expectEndpoints := func(searchErr error) {
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(&model.User{}, &model.Response{}, nil).
+			Times(3)
+
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, 60, "", false).
+			Return(model.NewPostList(), &model.Response{}, nil).
+			Times(3)
+
+		s.client.
+			EXPECT().
+			SearchPosts(channel.TeamId, "test", false).
+			Return(model.NewPostList(), &model.Response{}, searchErr).
+			Times(3)
+	}
+
+	s.Run("Should report the latencies of the endpoints", func() {
+		printer.Clean()
+		expectEndpoints(nil)
+
+		err := systemLatencyReportCmdF(s.client, newCmd("", ""), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 3)
+		for i, endpoint := range []string{"get user", "channel posts", "search"} {
+			report := printer.GetLines()[i].(latencyReport)
+			s.Require().Equal(endpoint, report.Endpoint)
+			s.Require().Equal(3, report.Requests)
+			s.Require().Zero(report.Errors)
+		}
+	})
+
+	s.Run("Should measure the login endpoint last", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		passwordFile := filepath.Join(tmp, "password.txt")
+		s.Require().NoError(ioutil.WriteFile(passwordFile, []byte("secret\n"), 0600))
+		expectEndpoints(nil)
+
+		s.client.
+			EXPECT().
+			Login("loadtest-user", "secret").
+			Return(&model.User{}, &model.Response{}, nil).
+			Times(3)
+
+		s.client.
+			EXPECT().
+			Logout().
+			Return(&model.Response{}, nil).
+			Times(3)
+
+		err := systemLatencyReportCmdF(s.client, newCmd("loadtest-user", passwordFile), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 5)
+		s.Require().Equal("login", printer.GetLines()[3].(latencyReport).Endpoint)
+		s.Require().Equal("logout", printer.GetLines()[4].(latencyReport).Endpoint)
+	})
+
+	s.Run("Should only log out after the successful logins", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		passwordFile := filepath.Join(tmp, "password.txt")
+		s.Require().NoError(ioutil.WriteFile(passwordFile, []byte("secret\n"), 0600))
+		expectEndpoints(nil)
+
+		gomock.InOrder(
+			s.client.EXPECT().Login("loadtest-user", "secret").Return(&model.User{}, &model.Response{}, nil),
+			s.client.EXPECT().Logout().Return(nil, errors.New("mock error")),
+			s.client.EXPECT().Login("loadtest-user", "secret").Return(nil, &model.Response{}, errors.New("mock error")),
+			s.client.EXPECT().Login("loadtest-user", "secret").Return(&model.User{}, &model.Response{}, nil),
+			s.client.EXPECT().Logout().Return(&model.Response{}, nil),
+		)
+
+		err := systemLatencyReportCmdF(s.client, newCmd("loadtest-user", passwordFile), []string{})
+		s.Require().EqualError(err, "2 requests failed")
+		s.Require().Len(printer.GetLines(), 5)
+		login := printer.GetLines()[3].(latencyReport)
+		logout := printer.GetLines()[4].(latencyReport)
+		s.Require().Equal(3, login.Requests)
+		s.Require().Equal(1, login.Errors)
+		s.Require().Equal(2, logout.Requests)
+		s.Require().Equal(1, logout.Errors)
+	})
+
+	s.Run("Should count the failed requests", func() {
+		printer.Clean()
+		expectEndpoints(errors.New("mock error"))
+
+		err := systemLatencyReportCmdF(s.client, newCmd("", ""), []string{})
+		s.Require().EqualError(err, "3 requests failed")
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal(latencyReport{Endpoint: "search", Requests: 3, Errors: 3}, printer.GetLines()[2])
+	})
+
+	s.Run("Should fail if the login id is given without a password", func() {
+		printer.Clean()
+
+		err := systemLatencyReportCmdF(s.client, newCmd("loadtest-user", ""), []string{})
+		s.Require().EqualError(err, "--login-id and --password-file must be used together")
+	})
+}
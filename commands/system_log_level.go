@@ -0,0 +1,157 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SystemLogLevelCmd = &cobra.Command{
+	Use:   "log-level",
+	Short: "Management of the log level of the server",
+}
+
+var SystemLogLevelSetCmd = &cobra.Command{
+	Use:   "set [level]",
+	Short: "Set the log level of the server",
+	Long: `Set the level of the console and file logs of the server, which is one of DEBUG, INFO, WARN or ERROR.
+With --temporary, the previous levels are restored after the given duration, so debug logging isn't forgotten in production. The command waits for the duration, and restores the levels earlier if it's interrupted with Ctrl-C, so it must be kept running: if it's killed, the levels are not restored.`,
+	Example: `  system log-level set INFO
+
+  # debug the server for 15 minutes
+  system log-level set DEBUG --temporary 15m
+
+  # only change the level of the file logs
+  system log-level set DEBUG --target file --temporary 1h`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(systemLogLevelSetCmdF),
+}
+
+func init() {
+	SystemLogLevelSetCmd.Flags().Duration("temporary", 0, "Restore the previous levels after this duration")
+	SystemLogLevelSetCmd.Flags().String("target", "all", "Logs to set the level of: console, file or all")
+
+	SystemLogLevelCmd.AddCommand(SystemLogLevelSetCmd)
+	SystemCmd.AddCommand(SystemLogLevelCmd)
+}
+
+var validLogLevels = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+// logLevelWait waits for the duration or until mmctl is interrupted,
+// returning whether it was interrupted. It can be replaced by the tests
+var logLevelWait = func(d time.Duration) bool {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	select {
+	case <-time.After(d):
+		return false
+	case <-interrupt:
+		return true
+	}
+}
+
+type serverLogLevels struct {
+	ConsoleLevel string `json:"console_level"`
+	FileLevel    string `json:"file_level"`
+}
+
+func getLogLevels(c client.Client) (*serverLogLevels, error) {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the config")
+	}
+	levels := &serverLogLevels{}
+	if config.LogSettings.ConsoleLevel != nil {
+		levels.ConsoleLevel = *config.LogSettings.ConsoleLevel
+	}
+	if config.LogSettings.FileLevel != nil {
+		levels.FileLevel = *config.LogSettings.FileLevel
+	}
+	return levels, nil
+}
+
+func patchLogLevels(c client.Client, levels *serverLogLevels) error {
+	patch := &model.Config{}
+	if levels.ConsoleLevel != "" {
+		patch.LogSettings.ConsoleLevel = model.NewString(levels.ConsoleLevel)
+	}
+	if levels.FileLevel != "" {
+		patch.LogSettings.FileLevel = model.NewString(levels.FileLevel)
+	}
+	if _, _, err := c.PatchConfig(patch); err != nil {
+		return errors.Wrap(err, "failed to update the log level")
+	}
+	return nil
+}
+
+func systemLogLevelSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	temporary, _ := cmd.Flags().GetDuration("temporary")
+	target, _ := cmd.Flags().GetString("target")
+
+	level := strings.ToUpper(args[0])
+	valid := false
+	for _, l := range validLogLevels {
+		valid = valid || l == level
+	}
+	if !valid {
+		return fmt.Errorf("invalid log level %q, the levels are %s", args[0], strings.Join(validLogLevels, ", "))
+	}
+	if temporary < 0 {
+		return errors.New("the temporary duration must be positive")
+	}
+
+	previous, err := getLogLevels(c)
+	if err != nil {
+		return err
+	}
+
+	levels := *previous
+	switch target {
+	case "console":
+		levels.ConsoleLevel = level
+	case "file":
+		levels.FileLevel = level
+	case "all":
+		levels.ConsoleLevel, levels.FileLevel = level, level
+	default:
+		return fmt.Errorf("invalid target %q, the targets are console, file and all", target)
+	}
+
+	if err := patchLogLevels(c, &levels); err != nil {
+		return err
+	}
+
+	if temporary == 0 {
+		printer.PrintT("Log level set to console {{.ConsoleLevel}} and file {{.FileLevel}}", &levels)
+		return nil
+	}
+
+	printer.PrintWarning(fmt.Sprintf("Log level set to %s, it will be restored in %s. Keep mmctl running until then", level, temporary))
+	interrupted := logLevelWait(temporary)
+
+	if err := patchLogLevels(c, previous); err != nil {
+		return errors.Wrap(err, "failed to restore the log level, it must be restored manually")
+	}
+	if interrupted {
+		printer.PrintT("Interrupted, log level restored to console {{.ConsoleLevel}} and file {{.FileLevel}}", previous)
+	} else {
+		printer.PrintT("Log level restored to console {{.ConsoleLevel}} and file {{.FileLevel}}", previous)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemLogLevelSetCmd() {
+	newCmd := func(temporary time.Duration, target string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("temporary", temporary, "")
+		cmd.Flags().String("target", target, "")
+		return cmd
+	}
+
+	config := &model.Config{}
+	config.LogSettings.ConsoleLevel = model.NewString("INFO")
+	config.LogSettings.FileLevel = model.NewString("ERROR")
+
+	patch := func(console, file string) *model.Config {
+		patch := &model.Config{}
+		patch.LogSettings.ConsoleLevel = model.NewString(console)
+		patch.LogSettings.FileLevel = model.NewString(file)
+		return patch
+	}
+
+	wait := logLevelWait
+
+	s.Run("Should set the level and restore it after the duration", func() {
+		printer.Clean()
+		var waited time.Duration
+		logLevelWait = func(d time.Duration) bool {
+			waited = d
+			return false
+		}
+		defer func() { logLevelWait = wait }()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		gomock.InOrder(
+			s.client.
+				EXPECT().
+				PatchConfig(patch("INFO", "DEBUG")).
+				Return(config, &model.Response{}, nil).
+				Times(1),
+			s.client.
+				EXPECT().
+				PatchConfig(patch("INFO", "ERROR")).
+				Return(config, &model.Response{}, nil).
+				Times(1),
+		)
+
+		err := systemLogLevelSetCmdF(s.client, newCmd(15*time.Minute, "file"), []string{"debug"})
+		s.Require().NoError(err)
+		s.Require().Equal(15*time.Minute, waited)
+		s.Require().Equal([]interface{}{&serverLogLevels{ConsoleLevel: "INFO", FileLevel: "ERROR"}}, printer.GetLines())
+	})
+
+	s.Run("Should set the level permanently without a duration", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(patch("WARN", "WARN")).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := systemLogLevelSetCmdF(s.client, newCmd(0, "all"), []string{"WARN"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&serverLogLevels{ConsoleLevel: "WARN", FileLevel: "WARN"}}, printer.GetLines())
+	})
+
+	s.Run("Should report when the level can't be restored", func() {
+		logLevelWait = func(time.Duration) bool { return true }
+		defer func() { logLevelWait = wait }()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(patch("DEBUG", "ERROR")).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(patch("INFO", "ERROR")).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := systemLogLevelSetCmdF(s.client, newCmd(time.Hour, "console"), []string{"DEBUG"})
+		s.Require().EqualError(err, "failed to restore the log level, it must be restored manually: failed to update the log level: mock error")
+	})
+
+	s.Run("Should fail with an invalid level", func() {
+		err := systemLogLevelSetCmdF(s.client, newCmd(0, "all"), []string{"verbose"})
+		s.Require().EqualError(err, `invalid log level "verbose", the levels are DEBUG, INFO, WARN, ERROR`)
+	})
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const (
+	maintenanceModeFileName       = "maintenance_mode.json"
+	defaultMaintenanceModeMessage = "Scheduled maintenance in progress, some features are temporarily unavailable."
+)
+
+var SystemMaintenanceModeCmd = &cobra.Command{
+	Use:   "maintenance-mode",
+	Short: "Enable or disable the maintenance mode of the server",
+	Long: `Put the server in maintenance mode: an announcement banner that can't be dismissed is shown to all the users, posting is restricted to channel admins in the channels given with --read-only, and the integrations given with --disable-integrations are turned off.
+The settings changed, and whether the members and guests of each channel could post, are saved to a state file, next to the mmctl config file by default, so that --disable restores them as they were before. Restricting posting in channels requires an Enterprise license, and the channels where it fails are reported and left out.`,
+	Example: `  system maintenance-mode --message "Upgrade in progress until 22:00 UTC" --read-only myteam:town-square --disable-integrations incoming-webhooks,slash-commands
+
+  # restore the settings and the channels as they were
+  system maintenance-mode --disable`,
+	Args: cobra.NoArgs,
+	RunE: withClient(systemMaintenanceModeCmdF),
+}
+
+func init() {
+	SystemMaintenanceModeCmd.Flags().Bool("disable", false, "Disable the maintenance mode, restoring the settings saved when it was enabled")
+	SystemMaintenanceModeCmd.Flags().String("message", defaultMaintenanceModeMessage, "Text of the announcement banner")
+	SystemMaintenanceModeCmd.Flags().String("banner-color", model.AnnouncementSettingsDefaultBannerColor, "Background color of the announcement banner")
+	SystemMaintenanceModeCmd.Flags().String("banner-text-color", model.AnnouncementSettingsDefaultBannerTextColor, "Text color of the announcement banner")
+	SystemMaintenanceModeCmd.Flags().StringSlice("read-only", []string{}, "Channels where posting is restricted to channel admins")
+	SystemMaintenanceModeCmd.Flags().StringSlice("disable-integrations", []string{}, "Integrations to turn off: incoming-webhooks, outgoing-webhooks or slash-commands")
+	SystemMaintenanceModeCmd.Flags().String("state-file", "", "Path of the file where the settings to restore are saved. Defaults to a file next to the mmctl config file")
+
+	SystemCmd.AddCommand(SystemMaintenanceModeCmd)
+}
+
+// maintenanceIntegrations maps the integrations that can be turned off
+// to their setting
+var maintenanceIntegrations = map[string]func(*model.ServiceSettings) **bool{
+	"incoming-webhooks": func(s *model.ServiceSettings) **bool { return &s.EnableIncomingWebhooks },
+	"outgoing-webhooks": func(s *model.ServiceSettings) **bool { return &s.EnableOutgoingWebhooks },
+	"slash-commands":    func(s *model.ServiceSettings) **bool { return &s.EnableCommands },
+}
+
+// maintenanceState holds what the maintenance mode changed, with the
+// previous values of the settings as a config patch
+type maintenanceState struct {
+	SiteURL          string                `json:"site_url"`
+	EnabledAt        int64                 `json:"enabled_at"`
+	Settings         *model.Config         `json:"settings"`
+	ReadOnlyChannels []*maintenanceChannel `json:"read_only_channels"`
+}
+
+// maintenanceChannel holds whether the members and the guests of a
+// channel could post before it was restricted. A nil value is a role
+// the channel doesn't moderate, which is left unchanged
+type maintenanceChannel struct {
+	ID      string `json:"id"`
+	Members *bool  `json:"members"`
+	Guests  *bool  `json:"guests"`
+}
+
+// maintenanceSiteURL tells which server the state was saved for
+func maintenanceSiteURL(config *model.Config) string {
+	if config.ServiceSettings.SiteURL == nil {
+		return ""
+	}
+	return *config.ServiceSettings.SiteURL
+}
+
+func maintenanceStateFilePath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("state-file"); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(resolveConfigFilePath()), maintenanceModeFileName)
+}
+
+func readMaintenanceState(path string) (*maintenanceState, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the maintenance mode state")
+	}
+	var state maintenanceState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the maintenance mode state")
+	}
+	return &state, nil
+}
+
+func saveMaintenanceState(path string, state *maintenanceState) error {
+	b, _ := json.MarshalIndent(state, "", "  ")
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "failed to save the maintenance mode state")
+	}
+	return nil
+}
+
+// getChannelPostingModeration returns whether the members and the
+// guests of a channel can post
+func getChannelPostingModeration(c client.Client, channelID string) (*maintenanceChannel, error) {
+	moderations, _, err := c.GetChannelModerations(channelID, "")
+	if err != nil {
+		return nil, err
+	}
+	channel := &maintenanceChannel{ID: channelID}
+	for _, moderation := range moderations {
+		if moderation.Name != model.ChannelModeratedPermissionsMap[model.PermissionCreatePost.Id] || moderation.Roles == nil {
+			continue
+		}
+		if moderation.Roles.Members != nil {
+			channel.Members = model.NewBool(moderation.Roles.Members.Value)
+		}
+		if moderation.Roles.Guests != nil {
+			channel.Guests = model.NewBool(moderation.Roles.Guests.Value)
+		}
+	}
+	return channel, nil
+}
+
+// setChannelPostingModeration sets whether the members and the guests
+// of a channel can post, as channel slowmode does
+func setChannelPostingModeration(c client.Client, channelID string, members, guests *bool) error {
+	_, _, err := c.PatchChannelModerations(channelID, []*model.ChannelModerationPatch{{
+		Name:  model.NewString(model.ChannelModeratedPermissionsMap[model.PermissionCreatePost.Id]),
+		Roles: &model.ChannelModeratedRolesPatch{Members: members, Guests: guests},
+	}})
+	return err
+}
+
+func enableMaintenanceMode(c client.Client, cmd *cobra.Command, path string) error {
+	message, _ := cmd.Flags().GetString("message")
+	bannerColor, _ := cmd.Flags().GetString("banner-color")
+	bannerTextColor, _ := cmd.Flags().GetString("banner-text-color")
+	readOnly, _ := cmd.Flags().GetStringSlice("read-only")
+	integrations, _ := cmd.Flags().GetStringSlice("disable-integrations")
+
+	for _, integration := range integrations {
+		if _, ok := maintenanceIntegrations[integration]; !ok {
+			return fmt.Errorf("invalid integration %q, the integrations are incoming-webhooks, outgoing-webhooks and slash-commands", integration)
+		}
+	}
+
+	state, err := readMaintenanceState(path)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		return fmt.Errorf("the maintenance mode is already enabled since %s, disable it first", printer.FormatTimestamp(state.EnabledAt))
+	}
+
+	channels := []*model.Channel{}
+	for i, channel := range getChannelsFromChannelArgs(c, readOnly) {
+		if channel == nil {
+			return errors.New("Unable to find channel '" + readOnly[i] + "'")
+		}
+		channels = append(channels, channel)
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+
+	previous := &model.Config{}
+	previous.AnnouncementSettings = model.AnnouncementSettings{
+		EnableBanner:         config.AnnouncementSettings.EnableBanner,
+		BannerText:           config.AnnouncementSettings.BannerText,
+		BannerColor:          config.AnnouncementSettings.BannerColor,
+		BannerTextColor:      config.AnnouncementSettings.BannerTextColor,
+		AllowBannerDismissal: config.AnnouncementSettings.AllowBannerDismissal,
+	}
+	patch := &model.Config{}
+	patch.AnnouncementSettings = model.AnnouncementSettings{
+		EnableBanner:         model.NewBool(true),
+		BannerText:           model.NewString(message),
+		BannerColor:          model.NewString(bannerColor),
+		BannerTextColor:      model.NewString(bannerTextColor),
+		AllowBannerDismissal: model.NewBool(false),
+	}
+	for _, integration := range integrations {
+		setting := maintenanceIntegrations[integration]
+		*setting(&previous.ServiceSettings) = *setting(&config.ServiceSettings)
+		*setting(&patch.ServiceSettings) = model.NewBool(false)
+	}
+
+	// the state is saved before changing anything, so that whatever was
+	// changed can be restored if one of the changes fails
+	state = &maintenanceState{SiteURL: maintenanceSiteURL(config), EnabledAt: model.GetMillis(), Settings: previous}
+	if err := saveMaintenanceState(path, state); err != nil {
+		return err
+	}
+
+	if _, _, err := c.PatchConfig(patch); err != nil {
+		return errors.Wrap(err, "failed to update the config, run the command with --disable to restore it")
+	}
+	printer.PrintT("Announcement banner enabled: {{.}}", message)
+	for _, integration := range integrations {
+		printer.PrintT("Integration disabled: {{.}}", integration)
+	}
+
+	for _, channel := range channels {
+		previousModeration, moderationErr := getChannelPostingModeration(c, channel.Id)
+		if moderationErr != nil {
+			printer.PrintError("Unable to get the moderation of channel '" + channel.Name + "'. Error: " + moderationErr.Error())
+			continue
+		}
+		if err := setChannelPostingModeration(c, channel.Id, model.NewBool(false), model.NewBool(false)); err != nil {
+			printer.PrintError("Unable to restrict posting in channel '" + channel.Name + "'. Error: " + err.Error())
+			continue
+		}
+		state.ReadOnlyChannels = append(state.ReadOnlyChannels, previousModeration)
+		printer.PrintT("Posting restricted to channel admins in channel {{.Name}}", channel)
+	}
+
+	return saveMaintenanceState(path, state)
+}
+
+func disableMaintenanceMode(c client.Client, path string) error {
+	state, err := readMaintenanceState(path)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return errors.New("the maintenance mode is not enabled, as there is no state file at " + path)
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+	if siteURL := maintenanceSiteURL(config); siteURL != state.SiteURL {
+		return fmt.Errorf("the maintenance mode was enabled on %q, not on %q", state.SiteURL, siteURL)
+	}
+
+	if _, _, err := c.PatchConfig(state.Settings); err != nil {
+		return errors.Wrap(err, "failed to restore the config")
+	}
+	printer.Print("Settings restored")
+
+	failed := []*maintenanceChannel{}
+	failedIDs := []string{}
+	for _, channel := range state.ReadOnlyChannels {
+		if err := setChannelPostingModeration(c, channel.ID, channel.Members, channel.Guests); err != nil {
+			printer.PrintError("Unable to restore posting in channel '" + channel.ID + "'. Error: " + err.Error())
+			failed = append(failed, channel)
+			failedIDs = append(failedIDs, channel.ID)
+			continue
+		}
+		printer.PrintT("Posting restored as it was in channel {{.ID}}", channel)
+	}
+
+	if len(failed) > 0 {
+		// only the channels left are kept, so running the command again
+		// retries them
+		state.ReadOnlyChannels = failed
+		state.Settings = &model.Config{}
+		if err := saveMaintenanceState(path, state); err != nil {
+			return err
+		}
+		return fmt.Errorf("unable to restore posting in channels %s", strings.Join(failedIDs, ", "))
+	}
+
+	if err := os.Remove(path); err != nil {
+		return errors.Wrap(err, "failed to remove the maintenance mode state")
+	}
+	return nil
+}
+
+func systemMaintenanceModeCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	disable, _ := cmd.Flags().GetBool("disable")
+	path := maintenanceStateFilePath(cmd)
+
+	if disable {
+		return disableMaintenanceMode(c, path)
+	}
+	return enableMaintenanceMode(c, cmd, path)
+}
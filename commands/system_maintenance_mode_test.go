@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemMaintenanceModeCmd() {
+	newCmd := func(disable bool, stateFile string, readOnly, integrations []string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("disable", disable, "")
+		cmd.Flags().String("message", "Upgrade in progress", "")
+		cmd.Flags().String("banner-color", "#ff0000", "")
+		cmd.Flags().String("banner-text-color", "#ffffff", "")
+		cmd.Flags().StringSlice("read-only", readOnly, "")
+		cmd.Flags().StringSlice("disable-integrations", integrations, "")
+		cmd.Flags().String("state-file", stateFile, "")
+		return cmd
+	}
+
+	liveConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.ServiceSettings.SiteURL = model.NewString("https://mattermost.example.com")
+		config.AnnouncementSettings.EnableBanner = model.NewBool(true)
+		config.AnnouncementSettings.BannerText = model.NewString("Welcome")
+		return config
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	channel := &model.Channel{Id: "channel-id", Name: "town-square", TeamId: team.Id}
+
+	s.Run("Should enable the maintenance mode and restore the previous settings", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		stateFile := filepath.Join(tmp, "maintenance.json")
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channel.Name, team.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(liveConfig(), &model.Response{}, nil).
+			Times(2)
+
+		patch := &model.Config{}
+		patch.AnnouncementSettings = model.AnnouncementSettings{
+			EnableBanner:         model.NewBool(true),
+			BannerText:           model.NewString("Upgrade in progress"),
+			BannerColor:          model.NewString("#ff0000"),
+			BannerTextColor:      model.NewString("#ffffff"),
+			AllowBannerDismissal: model.NewBool(false),
+		}
+		patch.ServiceSettings.EnableIncomingWebhooks = model.NewBool(false)
+		s.client.
+			EXPECT().
+			PatchConfig(patch).
+			Return(&model.Config{}, &model.Response{}, nil).
+			Times(1)
+
+		// the members could post, the guests couldn't
+		s.client.
+			EXPECT().
+			GetChannelModerations(channel.Id, "").
+			Return([]*model.ChannelModeration{
+				{Name: "create_reactions", Roles: &model.ChannelModeratedRoles{Members: &model.ChannelModeratedRole{Value: false, Enabled: true}}},
+				{Name: "create_post", Roles: &model.ChannelModeratedRoles{
+					Members: &model.ChannelModeratedRole{Value: true, Enabled: true},
+					Guests:  &model.ChannelModeratedRole{Value: false, Enabled: true},
+				}},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channel.Id, []*model.ChannelModerationPatch{{
+				Name:  model.NewString("create_post"),
+				Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(false), Guests: model.NewBool(false)},
+			}}).
+			Return(nil, &model.Response{}, nil).
+			Times(1)
+
+		err := systemMaintenanceModeCmdF(s.client, newCmd(false, stateFile, []string{"myteam:town-square"}, []string{"incoming-webhooks"}), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().FileExists(stateFile)
+
+		err = systemMaintenanceModeCmdF(s.client, newCmd(false, stateFile, nil, nil), []string{})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "the maintenance mode is already enabled since")
+
+		printer.Clean()
+		previous := &model.Config{}
+		previous.AnnouncementSettings = model.AnnouncementSettings{
+			EnableBanner:         model.NewBool(true),
+			BannerText:           model.NewString("Welcome"),
+			BannerColor:          model.NewString(model.AnnouncementSettingsDefaultBannerColor),
+			BannerTextColor:      model.NewString(model.AnnouncementSettingsDefaultBannerTextColor),
+			AllowBannerDismissal: model.NewBool(true),
+		}
+		previous.ServiceSettings.EnableIncomingWebhooks = model.NewBool(true)
+		s.client.
+			EXPECT().
+			PatchConfig(previous).
+			Return(&model.Config{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchChannelModerations(channel.Id, []*model.ChannelModerationPatch{{
+				Name:  model.NewString("create_post"),
+				Roles: &model.ChannelModeratedRolesPatch{Members: model.NewBool(true), Guests: model.NewBool(false)},
+			}}).
+			Return(nil, &model.Response{}, nil).
+			Times(1)
+
+		err = systemMaintenanceModeCmdF(s.client, newCmd(true, stateFile, nil, nil), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			"Settings restored",
+			&maintenanceChannel{ID: channel.Id, Members: model.NewBool(true), Guests: model.NewBool(false)},
+		}, printer.GetLines())
+		s.Require().NoFileExists(stateFile)
+	})
+
+	s.Run("Should fail to disable the maintenance mode if it isn't enabled", func() {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		stateFile := filepath.Join(tmp, "maintenance.json")
+
+		err := systemMaintenanceModeCmdF(s.client, newCmd(true, stateFile, nil, nil), []string{})
+		s.Require().EqualError(err, "the maintenance mode is not enabled, as there is no state file at "+stateFile)
+	})
+
+	s.Run("Should fail with an unknown integration", func() {
+		err := systemMaintenanceModeCmdF(s.client, newCmd(false, "", nil, []string{"plugins"}), []string{})
+		s.Require().EqualError(err, `invalid integration "plugins", the integrations are incoming-webhooks, outgoing-webhooks and slash-commands`)
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	noticeCategoryAdmin = "admin"
+	noticeCategoryUser  = "user"
+)
+
+var SystemNoticesCmd = &cobra.Command{
+	Use:   "notices",
+	Short: "Management of product notices",
+}
+
+var SystemNoticesListCmd = &cobra.Command{
+	Use:   "list [team]",
+	Short: "List the pending notices",
+	Long:  "List the product and admin notices that the current user has not dismissed yet, as they would be shown in the given team.",
+	Example: `  system notices list myteam
+  system notices list myteam --client desktop --locale es`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(systemNoticesListCmdF),
+}
+
+var SystemNoticesDismissCmd = &cobra.Command{
+	Use:   "dismiss [team] [notice-ids]",
+	Short: "Dismiss notices",
+	Long: `Mark notices as viewed so they are not shown again.
+Notices are dismissed for the current user only, as the server keeps track of the viewed notices per user and only lets each user mark their own notices as viewed, so they can't be dismissed for all the admins at once. To stop showing the admin notices to every admin, use "system notices suppress admin" instead.`,
+	Example: `  system notices dismiss myteam notice-id
+  system notices dismiss myteam --all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(systemNoticesDismissCmdF),
+}
+
+var SystemNoticesSuppressCmd = &cobra.Command{
+	Use:   "suppress [categories]",
+	Short: "Suppress notice categories",
+	Long: `Stop showing a category of notices to every user of the server. The available categories are "admin", for the notices shown to system and team admins, and "user", for the notices shown to the rest of users.
+The categories can be shown again with the "system notices unsuppress" command.`,
+	Example: `  system notices suppress admin
+  system notices suppress admin user`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(systemNoticesSuppressCmdF),
+}
+
+var SystemNoticesUnsuppressCmd = &cobra.Command{
+	Use:     "unsuppress [categories]",
+	Short:   "Unsuppress notice categories",
+	Long:    `Show again a category of notices previously suppressed. The available categories are "admin" and "user".`,
+	Example: `  system notices unsuppress admin`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(systemNoticesUnsuppressCmdF),
+}
+
+func init() {
+	SystemNoticesListCmd.Flags().String("client", string(model.NoticeClientTypeWeb), "Client type the notices are shown in: web, desktop, mobile-android or mobile-ios")
+	SystemNoticesListCmd.Flags().String("locale", "en", "Locale of the notice messages")
+
+	SystemNoticesDismissCmd.Flags().Bool("all", false, "Dismiss all the pending notices")
+	SystemNoticesDismissCmd.Flags().String("client", string(model.NoticeClientTypeWeb), "Client type used to find the pending notices when using --all")
+
+	SystemNoticesCmd.AddCommand(
+		SystemNoticesListCmd,
+		SystemNoticesDismissCmd,
+		SystemNoticesSuppressCmd,
+		SystemNoticesUnsuppressCmd,
+	)
+	SystemCmd.AddCommand(SystemNoticesCmd)
+}
+
+func getPendingNotices(c client.Client, teamArg, clientType, locale string) (model.NoticeMessages, error) {
+	team := getTeamFromTeamArg(c, teamArg)
+	if team == nil {
+		return nil, fmt.Errorf("unable to find team %q", teamArg)
+	}
+
+	noticeClient, err := model.NoticeClientTypeFromString(clientType)
+	if err != nil {
+		return nil, err
+	}
+
+	notices, _, err := c.GetNotices(0, team.Id, noticeClient, "", locale, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the notices: %w", err)
+	}
+
+	return notices, nil
+}
+
+func systemNoticesListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	clientType, _ := cmd.Flags().GetString("client")
+	locale, _ := cmd.Flags().GetString("locale")
+
+	notices, err := getPendingNotices(c, args[0], clientType, locale)
+	if err != nil {
+		return err
+	}
+
+	if len(notices) == 0 {
+		printer.Print("There are no pending notices")
+		return nil
+	}
+
+	for _, notice := range notices {
+		printer.PrintT("{{.ID}}: {{.Title}}{{if .SysAdminOnly}} (system admins only){{else if .TeamAdminOnly}} (team admins only){{end}}", notice)
+	}
+
+	return nil
+}
+
+func systemNoticesDismissCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	ids := args[1:]
+	if all == (len(ids) > 0) {
+		return errors.New("either notice ids or --all must be provided")
+	}
+
+	if all {
+		clientType, _ := cmd.Flags().GetString("client")
+		notices, err := getPendingNotices(c, args[0], clientType, "en")
+		if err != nil {
+			return err
+		}
+		for _, notice := range notices {
+			ids = append(ids, notice.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		printer.Print("There are no pending notices")
+		return nil
+	}
+
+	if _, err := c.MarkNoticesViewed(ids); err != nil {
+		return fmt.Errorf("unable to dismiss the notices: %w", err)
+	}
+
+	for _, id := range ids {
+		printer.PrintT("Notice {{.ID}} dismissed", struct {
+			ID string `json:"id"`
+		}{id})
+	}
+
+	return nil
+}
+
+func systemNoticesSuppressCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return setNoticeCategoriesEnabled(c, args, false)
+}
+
+func systemNoticesUnsuppressCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	return setNoticeCategoriesEnabled(c, args, true)
+}
+
+func setNoticeCategoriesEnabled(c client.Client, categories []string, enabled bool) error {
+	for _, category := range categories {
+		if category != noticeCategoryAdmin && category != noticeCategoryUser {
+			return fmt.Errorf("invalid notice category %q, must be one of: admin, user", category)
+		}
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the server config")
+	}
+
+	for _, category := range categories {
+		if category == noticeCategoryAdmin {
+			config.AnnouncementSettings.AdminNoticesEnabled = model.NewBool(enabled)
+		} else {
+			config.AnnouncementSettings.UserNoticesEnabled = model.NewBool(enabled)
+		}
+	}
+
+	newConfig, _, err := c.PatchConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "unable to update the server config")
+	}
+
+	printer.PrintT("Admin notices enabled: {{.AdminNoticesEnabled}}, user notices enabled: {{.UserNoticesEnabled}}", newConfig.AnnouncementSettings)
+	return nil
+}
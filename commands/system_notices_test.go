@@ -0,0 +1,205 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemNoticesListCmd() {
+	team := &model.Team{Id: model.NewId(), Name: "team"}
+	notices := model.NoticeMessages{
+		{ID: "notice-1", NoticeMessageInternal: model.NoticeMessageInternal{Title: "First"}, SysAdminOnly: true},
+		{ID: "notice-2", NoticeMessageInternal: model.NoticeMessageInternal{Title: "Second"}},
+	}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("client", "web", "")
+		cmd.Flags().String("locale", "en", "")
+		return cmd
+	}
+
+	s.Run("Should list the pending notices", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetNotices(int64(0), team.Id, model.NoticeClientTypeWeb, "", "en", "").
+			Return(notices, &model.Response{}, nil).
+			Times(1)
+
+		err := systemNoticesListCmdF(s.client, newCmd(), []string{team.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{notices[0], notices[1]}, printer.GetLines())
+	})
+
+	s.Run("Should fail with an invalid client type", func() {
+		printer.Clean()
+		cmd := newCmd()
+		s.Require().NoError(cmd.Flags().Set("client", "fax"))
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		err := systemNoticesListCmdF(s.client, cmd, []string{team.Id})
+		s.Require().Error(err)
+	})
+
+	s.Run("Should fail if the team does not exist", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := systemNoticesListCmdF(s.client, newCmd(), []string{"unknown"})
+		s.Require().EqualError(err, `unable to find team "unknown"`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSystemNoticesDismissCmd() {
+	team := &model.Team{Id: model.NewId(), Name: "team"}
+
+	newCmd := func(all bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", all, "")
+		cmd.Flags().String("client", "web", "")
+		return cmd
+	}
+
+	s.Run("Should require either notice ids or --all", func() {
+		printer.Clean()
+
+		err := systemNoticesDismissCmdF(s.client, newCmd(false), []string{team.Id})
+		s.Require().EqualError(err, "either notice ids or --all must be provided")
+
+		err = systemNoticesDismissCmdF(s.client, newCmd(true), []string{team.Id, "notice-1"})
+		s.Require().EqualError(err, "either notice ids or --all must be provided")
+	})
+
+	s.Run("Should dismiss the given notices", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			MarkNoticesViewed([]string{"notice-1", "notice-2"}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := systemNoticesDismissCmdF(s.client, newCmd(false), []string{team.Id, "notice-1", "notice-2"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+	})
+
+	s.Run("Should dismiss all the pending notices", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetNotices(int64(0), team.Id, model.NoticeClientTypeWeb, "", "en", "").
+			Return(model.NoticeMessages{{ID: "notice-1"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			MarkNoticesViewed([]string{"notice-1"}).
+			Return(nil, errors.New("mock error")).
+			Times(1)
+
+		err := systemNoticesDismissCmdF(s.client, newCmd(true), []string{team.Id})
+		s.Require().EqualError(err, "unable to dismiss the notices: mock error")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSystemNoticesSuppressCmd() {
+	s.Run("Should suppress the admin notices", func() {
+		printer.Clean()
+		config := &model.Config{}
+		config.SetDefaults()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := systemNoticesSuppressCmdF(s.client, &cobra.Command{}, []string{"admin"})
+		s.Require().NoError(err)
+		s.Require().False(*config.AnnouncementSettings.AdminNoticesEnabled)
+		s.Require().True(*config.AnnouncementSettings.UserNoticesEnabled)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Should unsuppress the user notices", func() {
+		printer.Clean()
+		config := &model.Config{}
+		config.SetDefaults()
+		config.AnnouncementSettings.UserNoticesEnabled = model.NewBool(false)
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := systemNoticesUnsuppressCmdF(s.client, &cobra.Command{}, []string{"user"})
+		s.Require().NoError(err)
+		s.Require().True(*config.AnnouncementSettings.UserNoticesEnabled)
+	})
+
+	s.Run("Should fail with an invalid category", func() {
+		printer.Clean()
+
+		err := systemNoticesSuppressCmdF(s.client, &cobra.Command{}, []string{"banner"})
+		s.Require().EqualError(err, `invalid notice category "banner", must be one of: admin, user`)
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var SystemS3TestCmd = &cobra.Command{
+	Use:   "s3-test",
+	Short: "Test the S3 file storage connection",
+	Long: `Tests the connection to the S3 file storage using the current server configuration and reports the latency of each step.
+With --write-read, a test object is also uploaded to the given channel, read back and compared, which exercises the same storage operations attachments use. The object is never posted, so no user is notified.
+The API has no way of deleting a file, so the delete step can't be checked and the test object stays in the bucket: its id is printed so it can be removed from the bucket directly.`,
+	Example: `  system s3-test
+  system s3-test --write-read --channel myteam:storage-checks`,
+	Args: cobra.NoArgs,
+	RunE: withClient(systemS3TestCmdF),
+}
+
+func init() {
+	SystemS3TestCmd.Flags().Bool("write-read", false, "Perform a write and read round-trip with a test object")
+	SystemS3TestCmd.Flags().String("channel", "", "Channel to upload the test object to, required with --write-read")
+
+	SystemCmd.AddCommand(SystemS3TestCmd)
+}
+
+const s3TestContent = "mmctl s3-test object"
+
+type s3TestStep struct {
+	Step      string `json:"step"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+func printS3TestStep(step string, start time.Time) {
+	printer.PrintT("{{.Step}}: ok ({{.LatencyMs}}ms)", s3TestStep{
+		Step:      step,
+		LatencyMs: time.Since(start).Milliseconds(),
+	})
+}
+
+func systemS3TestCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	roundTrip, _ := cmd.Flags().GetBool("write-read")
+	channelArg, _ := cmd.Flags().GetString("channel")
+	if roundTrip && channelArg == "" {
+		return errors.New("--channel is required with --write-read")
+	}
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the server config")
+	}
+	if config.FileSettings.DriverName == nil || *config.FileSettings.DriverName != model.ImageDriverS3 {
+		return errors.New("the server is not configured to use S3 file storage")
+	}
+
+	start := time.Now()
+	if _, err := c.TestS3Connection(config); err != nil {
+		return errors.Wrap(err, "connection test failed")
+	}
+	printS3TestStep("connect", start)
+
+	if !roundTrip {
+		return nil
+	}
+
+	channel := getChannelFromChannelArg(c, channelArg)
+	if channel == nil {
+		return fmt.Errorf("unable to find channel %q", channelArg)
+	}
+
+	content := []byte(s3TestContent)
+	filename := fmt.Sprintf("mmctl-s3-test-%d.txt", model.GetMillis())
+
+	start = time.Now()
+	uploaded, _, err := c.UploadFile(content, channel.Id, filename)
+	if err != nil {
+		return errors.Wrap(err, "write failed")
+	}
+	if len(uploaded.FileInfos) != 1 {
+		return errors.New("write failed: the server did not return the uploaded file")
+	}
+	fileID := uploaded.FileInfos[0].Id
+	printS3TestStep("write", start)
+	defer printer.PrintWarning(fmt.Sprintf("the test file %q can't be deleted through the API and must be removed from the bucket directly", fileID))
+
+	start = time.Now()
+	data, _, err := c.GetFile(fileID)
+	if err == nil && !bytes.Equal(data, content) {
+		err = errors.New("the content does not match what was written")
+	}
+	if err != nil {
+		return errors.Wrapf(err, "read of file %q failed", fileID)
+	}
+	printS3TestStep("read", start)
+
+	return nil
+}
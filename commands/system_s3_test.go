@@ -0,0 +1,176 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemS3TestCmd() {
+	s3Config := &model.Config{}
+	s3Config.SetDefaults()
+	s3Config.FileSettings.DriverName = model.NewString(model.ImageDriverS3)
+
+	newCmd := func(roundTrip bool, channel string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("write-read", roundTrip, "")
+		cmd.Flags().String("channel", channel, "")
+		return cmd
+	}
+
+	s.Run("Should require a channel for the round-trip", func() {
+		printer.Clean()
+
+		err := systemS3TestCmdF(s.client, newCmd(true, ""), []string{})
+		s.Require().EqualError(err, "--channel is required with --write-read")
+	})
+
+	s.Run("Should fail if the server does not use S3", func() {
+		printer.Clean()
+		config := &model.Config{}
+		config.SetDefaults()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := systemS3TestCmdF(s.client, newCmd(false, ""), []string{})
+		s.Require().EqualError(err, "the server is not configured to use S3 file storage")
+	})
+
+	s.Run("Should only test the connection by default", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(s3Config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			TestS3Connection(s3Config).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := systemS3TestCmdF(s.client, newCmd(false, ""), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("connect", printer.GetLines()[0].(s3TestStep).Step)
+	})
+
+	s.Run("Should fail if the connection test fails", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(s3Config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			TestS3Connection(s3Config).
+			Return(&model.Response{}, errors.New("access denied")).
+			Times(1)
+
+		err := systemS3TestCmdF(s.client, newCmd(true, "channel-id"), []string{})
+		s.Require().EqualError(err, "connection test failed: access denied")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should write and read a test object without posting it", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: "channel-id"}
+		fileInfo := &model.FileInfo{Id: "file-id"}
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(s3Config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			TestS3Connection(s3Config).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadFile([]byte(s3TestContent), channel.Id, gomock.Any()).
+			Return(&model.FileUploadResponse{FileInfos: []*model.FileInfo{fileInfo}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile(fileInfo.Id).
+			Return([]byte(s3TestContent), &model.Response{}, nil).
+			Times(1)
+
+		err := systemS3TestCmdF(s.client, newCmd(true, channel.Id), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 3)
+		for i, step := range []string{"connect", "write", "read"} {
+			s.Require().Equal(step, printer.GetLines()[i].(s3TestStep).Step)
+		}
+	})
+
+	s.Run("Should fail if the read content does not match", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: "channel-id"}
+		fileInfo := &model.FileInfo{Id: "file-id"}
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(s3Config, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			TestS3Connection(s3Config).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UploadFile(gomock.Any(), channel.Id, gomock.Any()).
+			Return(&model.FileUploadResponse{FileInfos: []*model.FileInfo{fileInfo}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetFile(fileInfo.Id).
+			Return([]byte("corrupted"), &model.Response{}, nil).
+			Times(1)
+
+		err := systemS3TestCmdF(s.client, newCmd(true, channel.Id), []string{})
+		s.Require().EqualError(err, `read of file "file-id" failed: the content does not match what was written`)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+const versionHistoryFileName = "version_history.json"
+
+var SystemVersionHistoryCmd = &cobra.Command{
+	Use:   "version-history [server names]",
+	Short: "Show the version changes of the servers",
+	Long: `Show when the version of the servers changed, as seen by mmctl. Each time mmctl connects to a server using stored credentials it records its version in a file next to the credentials file, so the history only covers the versions seen while mmctl was used.
+Without arguments the history of the current server is shown.`,
+	Example: `  system version-history
+  system version-history production staging
+  system version-history --all`,
+	RunE: systemVersionHistoryCmdF,
+}
+
+func init() {
+	SystemVersionHistoryCmd.Flags().Bool("all", false, "Show the history of all the servers")
+
+	SystemCmd.AddCommand(SystemVersionHistoryCmd)
+}
+
+// versionChange is a version of a server, with when it was seen for the
+// first and the last time
+type versionChange struct {
+	Server          string `json:"server"`
+	InstanceURL     string `json:"instance_url"`
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version,omitempty"`
+	FirstSeenAt     int64  `json:"first_seen_at"`
+	LastSeenAt      int64  `json:"last_seen_at"`
+}
+
+// versionHistory holds the version changes of each server by the name
+// of its credentials
+type versionHistory map[string][]*versionChange
+
+func versionHistoryFilePath() string {
+	return filepath.Join(filepath.Dir(resolveConfigFilePath()), versionHistoryFileName)
+}
+
+// errInvalidVersionHistory is returned when the version history file
+// can't be parsed
+var errInvalidVersionHistory = errors.New("cannot parse the version history")
+
+func readVersionHistory() (versionHistory, error) {
+	history := versionHistory{}
+	b, err := ioutil.ReadFile(versionHistoryFilePath())
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot read the version history")
+	}
+	if err := json.Unmarshal(b, &history); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidVersionHistory, err)
+	}
+	return history, nil
+}
+
+// saveVersionHistory writes the history to a temporary file of its own
+// first, and then renames it, so that concurrent invocations never leave
+// the history half written
+func saveVersionHistory(history versionHistory) error {
+	b, _ := json.MarshalIndent(history, "", "    ")
+	path := versionHistoryFilePath()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), versionHistoryFileName+".*.tmp")
+	if err != nil {
+		return errors.WithMessage(err, "cannot save the version history")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return errors.WithMessage(err, "cannot save the version history")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WithMessage(err, "cannot save the version history")
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// recordServerVersion adds the version to the history of the server if
+// it changed, or updates when it was last seen otherwise
+func recordServerVersion(credentials *Credentials, serverVersion string) error {
+	if credentials.Name == "" || serverVersion == "" {
+		return nil
+	}
+
+	// an unparsable history is started again, as it would otherwise
+	// fail to be updated on every command
+	history, err := readVersionHistory()
+	if errors.Is(err, errInvalidVersionHistory) {
+		history = versionHistory{}
+	} else if err != nil {
+		return err
+	}
+
+	now := model.GetMillis()
+	changes := history[credentials.Name]
+	if n := len(changes); n > 0 && changes[n-1].Version == serverVersion && changes[n-1].InstanceURL == credentials.InstanceURL {
+		changes[n-1].LastSeenAt = now
+	} else {
+		change := &versionChange{
+			Server:      credentials.Name,
+			InstanceURL: credentials.InstanceURL,
+			Version:     serverVersion,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		}
+		if n > 0 {
+			change.PreviousVersion = changes[n-1].Version
+		}
+		history[credentials.Name] = append(changes, change)
+	}
+	return saveVersionHistory(history)
+}
+
+// renameVersionHistory moves the history of the server to the new name
+// of its credentials
+func renameVersionHistory(name, newName string) error {
+	history, err := readVersionHistory()
+	if err != nil {
+		return err
+	}
+	changes, ok := history[name]
+	if !ok {
+		return nil
+	}
+
+	for _, change := range changes {
+		change.Server = newName
+	}
+	delete(history, name)
+	history[newName] = changes
+	return saveVersionHistory(history)
+}
+
+// deleteVersionHistory removes the history of the servers of deleted
+// credentials
+func deleteVersionHistory(names ...string) error {
+	history, err := readVersionHistory()
+	if err != nil {
+		return err
+	}
+
+	deleted := false
+	for _, name := range names {
+		if _, ok := history[name]; ok {
+			delete(history, name)
+			deleted = true
+		}
+	}
+	if !deleted {
+		return nil
+	}
+	return saveVersionHistory(history)
+}
+
+func systemVersionHistoryCmdF(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all && len(args) > 0 {
+		return errors.New("server names cannot be used with the --all flag")
+	}
+
+	history, err := readVersionHistory()
+	if err != nil {
+		return err
+	}
+
+	servers := args
+	switch {
+	case all:
+		for server := range history {
+			servers = append(servers, server)
+		}
+		sort.Strings(servers)
+	case len(servers) == 0:
+		server := viper.GetString("profile")
+		if server == "" {
+			credentials, err := GetCurrentCredentials()
+			if err != nil {
+				return err
+			}
+			server = credentials.Name
+		}
+		servers = []string{server}
+	}
+
+	for _, server := range servers {
+		changes, ok := history[server]
+		if !ok {
+			printer.PrintError("No version history for server " + server)
+			continue
+		}
+		for _, change := range changes {
+			printer.PrintT("{{timestamp .FirstSeenAt}} {{.Server}} {{if .PreviousVersion}}{{.PreviousVersion}} -> {{end}}{{.Version}} (last seen at {{timestamp .LastSeenAt}})", change)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSystemVersionHistoryCmd() {
+	production := &Credentials{Name: "production", InstanceURL: "https://mattermost.example.com", Active: true}
+	staging := &Credentials{Name: "staging", InstanceURL: "https://staging.example.com"}
+
+	newCmd := func(all bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", all, "")
+		return cmd
+	}
+
+	withConfigDir := func(f func()) {
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		originalConfig := viper.GetString("config")
+		defer viper.Set("config", originalConfig)
+		viper.Set("config", filepath.Join(tmp, "config"))
+		f()
+	}
+
+	s.Run("Should only record the version changes", func() {
+		withConfigDir(func() {
+			s.Require().NoError(recordServerVersion(production, "6.2.0"))
+			s.Require().NoError(recordServerVersion(production, "6.2.0"))
+			s.Require().NoError(recordServerVersion(production, "6.3.0"))
+			s.Require().NoError(recordServerVersion(staging, "6.4.0"))
+			// the local mode has no credentials to record the version for
+			s.Require().NoError(recordServerVersion(&Credentials{}, "6.4.0"))
+
+			history, err := readVersionHistory()
+			s.Require().NoError(err)
+			s.Require().Len(history, 2)
+			s.Require().Len(history["production"], 2)
+			s.Require().Equal("6.2.0", history["production"][0].Version)
+			s.Require().Empty(history["production"][0].PreviousVersion)
+			s.Require().GreaterOrEqual(history["production"][0].LastSeenAt, history["production"][0].FirstSeenAt)
+			s.Require().Equal("6.3.0", history["production"][1].Version)
+			s.Require().Equal("6.2.0", history["production"][1].PreviousVersion)
+			s.Require().Equal("https://mattermost.example.com", history["production"][1].InstanceURL)
+		})
+	})
+
+	s.Run("Should start an unparsable history again", func() {
+		withConfigDir(func() {
+			s.Require().NoError(ioutil.WriteFile(versionHistoryFilePath(), []byte(`{"production": [`), 0600))
+
+			_, err := readVersionHistory()
+			s.Require().ErrorIs(err, errInvalidVersionHistory)
+
+			s.Require().NoError(recordServerVersion(production, "6.3.0"))
+			history, err := readVersionHistory()
+			s.Require().NoError(err)
+			s.Require().Len(history["production"], 1)
+			s.Require().Equal("6.3.0", history["production"][0].Version)
+
+			// the temporary file of the save is renamed to the history
+			files, err := filepath.Glob(filepath.Join(filepath.Dir(versionHistoryFilePath()), "*.tmp"))
+			s.Require().NoError(err)
+			s.Require().Empty(files)
+		})
+	})
+
+	s.Run("Should show the history of the given servers", func() {
+		withConfigDir(func() {
+			printer.Clean()
+			s.Require().NoError(recordServerVersion(production, "6.2.0"))
+			s.Require().NoError(recordServerVersion(production, "6.3.0"))
+			s.Require().NoError(recordServerVersion(staging, "6.4.0"))
+
+			err := systemVersionHistoryCmdF(newCmd(false), []string{"production", "unknown"})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 2)
+			s.Require().Equal("6.3.0", printer.GetLines()[1].(*versionChange).Version)
+			s.Require().Equal([]interface{}{"No version history for server unknown"}, printer.GetErrorLines())
+
+			printer.Clean()
+			err = systemVersionHistoryCmdF(newCmd(true), []string{})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 3)
+			s.Require().Equal("staging", printer.GetLines()[2].(*versionChange).Server)
+		})
+	})
+
+	s.Run("Should show the history of the current server", func() {
+		withConfigDir(func() {
+			printer.Clean()
+			productionCopy, stagingCopy := *production, *staging
+			s.Require().NoError(SaveCredentialsList(&CredentialsList{production.Name: &productionCopy, staging.Name: &stagingCopy}))
+			s.Require().NoError(recordServerVersion(production, "6.2.0"))
+			s.Require().NoError(recordServerVersion(staging, "6.4.0"))
+
+			err := systemVersionHistoryCmdF(newCmd(false), []string{})
+			s.Require().NoError(err)
+			s.Require().Len(printer.GetLines(), 1)
+			s.Require().Equal("production", printer.GetLines()[0].(*versionChange).Server)
+
+			err = systemVersionHistoryCmdF(newCmd(true), []string{"staging"})
+			s.Require().EqualError(err, "server names cannot be used with the --all flag")
+		})
+	})
+}
@@ -36,9 +36,13 @@ var DeleteTeamsCmd = &cobra.Command{
 	Short: "Delete teams",
 	Long: `Permanently delete some teams.
 Permanently deletes a team along with all related information including posts from the database.`,
-	Example: "  team delete myteam",
-	Args:    cobra.MinimumNArgs(1),
-	RunE:    withClient(deleteTeamsCmdF),
+	Example: `  team delete myteam
+
+  # review the plan before applying it
+  team delete myteam otherteam --plan > plan.json
+  team delete --apply-plan plan.json --confirm`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(withPlan(teamPlanResolver("delete_team"), deleteTeamsCmdF)),
 }
 
 var ArchiveTeamsCmd = &cobra.Command{
@@ -48,7 +52,7 @@ var ArchiveTeamsCmd = &cobra.Command{
 Archives a team along with all related information including posts from the database.`,
 	Example: "  team archive myteam",
 	Args:    cobra.MinimumNArgs(1),
-	RunE:    withClient(archiveTeamsCmdF),
+	RunE:    withClient(withPlan(teamPlanResolver("archive_team"), archiveTeamsCmdF)),
 }
 
 var RestoreTeamsCmd = &cobra.Command{
@@ -106,6 +110,8 @@ func init() {
 
 	DeleteTeamsCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the team and a DB backup has been performed.")
 	ArchiveTeamsCmd.Flags().Bool("confirm", false, "Confirm you really want to archive the team and a DB backup has been performed.")
+	addPlanFlags(DeleteTeamsCmd)
+	addPlanFlags(ArchiveTeamsCmd)
 
 	ModifyTeamsCmd.Flags().Bool("private", false, "Modify team to be private.")
 	ModifyTeamsCmd.Flags().Bool("public", false, "Modify team to be public.")
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var TeamAnalyticsCmd = &cobra.Command{
+	Use:   "analytics [team]",
+	Short: "Show team analytics",
+	Long: `Show the engagement analytics of a team, or of the whole site if no team is provided.
+The available metrics are "counts" (totals of posts, channels, users, etc.), "posts-per-day" and "active-users-per-day". The daily metrics cover the last 30 days.
+With --trend, the daily metrics are summarized as their totals in the last 7 days and in the previous 7 days, along with the percentage of change between them, for week-over-week reports.`,
+	Example: `  # show all the metrics for the site
+  team analytics
+
+  # compare the activity of a team with the previous week
+  team analytics myteam --metrics posts-per-day,active-users-per-day --trend
+
+  # show the posts per day of a team as CSV
+  team analytics myteam --metrics posts-per-day --csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: withClient(teamAnalyticsCmdF),
+}
+
+// teamAnalyticsMetrics maps the metric names accepted by the analytics
+// command to the names of the analytics served by the API
+var teamAnalyticsMetrics = map[string]string{
+	"counts":               "standard",
+	"posts-per-day":        "post_counts_day",
+	"active-users-per-day": "user_counts_with_posts_day",
+}
+
+func init() {
+	TeamAnalyticsCmd.Flags().StringSlice("metrics", []string{"counts", "posts-per-day", "active-users-per-day"}, "Metrics to show")
+	TeamAnalyticsCmd.Flags().Bool("trend", false, "Summarize the daily metrics as the week-over-week change")
+
+	TeamCmd.AddCommand(TeamAnalyticsCmd)
+}
+
+type teamAnalyticsRow struct {
+	Metric string  `json:"metric"`
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+}
+
+// teamAnalyticsTrendDays is the length of the periods compared by the
+// trend of the daily metrics
+const teamAnalyticsTrendDays = 7
+
+// getTeamAnalyticsTrendRows sums the values of a daily metric in the
+// last days and in the same number of days before them. The change is
+// not reported when there are no values in the previous period
+func getTeamAnalyticsTrendRows(metric string, analytics model.AnalyticsRows, now time.Time) []*teamAnalyticsRow {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var current, previous float64
+	for _, row := range analytics {
+		day, err := time.Parse("2006-01-02", row.Name)
+		if err != nil {
+			continue
+		}
+		switch daysAgo := int(today.Sub(day).Hours() / 24); {
+		case daysAgo >= 0 && daysAgo < teamAnalyticsTrendDays:
+			current += row.Value
+		case daysAgo >= teamAnalyticsTrendDays && daysAgo < 2*teamAnalyticsTrendDays:
+			previous += row.Value
+		}
+	}
+
+	rows := []*teamAnalyticsRow{
+		{Metric: metric, Name: fmt.Sprintf("last_%d_days", teamAnalyticsTrendDays), Value: current},
+		{Metric: metric, Name: fmt.Sprintf("previous_%d_days", teamAnalyticsTrendDays), Value: previous},
+	}
+	if previous != 0 {
+		rows = append(rows, &teamAnalyticsRow{Metric: metric, Name: "change_percent", Value: math.Round((current-previous)/previous*1000) / 10})
+	}
+	return rows
+}
+
+func teamAnalyticsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	metrics, _ := cmd.Flags().GetStringSlice("metrics")
+	trend, _ := cmd.Flags().GetBool("trend")
+	for _, metric := range metrics {
+		if _, ok := teamAnalyticsMetrics[metric]; !ok {
+			return fmt.Errorf("invalid metric %q", metric)
+		}
+	}
+
+	teamID := ""
+	if len(args) == 1 {
+		team := getTeamFromTeamArg(c, args[0])
+		if team == nil {
+			return fmt.Errorf("unable to find team %q", args[0])
+		}
+		teamID = team.Id
+	}
+
+	rows := []*teamAnalyticsRow{}
+	for _, metric := range metrics {
+		analytics, _, err := c.GetAnalyticsOld(teamAnalyticsMetrics[metric], teamID)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get the %s analytics", metric)
+		}
+
+		if trend && metric != "counts" {
+			rows = append(rows, getTeamAnalyticsTrendRows(metric, analytics, time.Now())...)
+			continue
+		}
+
+		metricRows := make([]*teamAnalyticsRow, 0, len(analytics))
+		for _, row := range analytics {
+			metricRows = append(metricRows, &teamAnalyticsRow{Metric: metric, Name: row.Name, Value: row.Value})
+		}
+		// the daily series are returned newest first, so they are
+		// sorted to be printed chronologically
+		if metric != "counts" {
+			sort.Slice(metricRows, func(i, j int) bool { return metricRows[i].Name < metricRows[j].Name })
+		}
+		rows = append(rows, metricRows...)
+	}
+
+	for _, row := range rows {
+		printer.PrintT("{{.Metric}} {{.Name}}: {{.Value}}", row)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestTeamAnalyticsCmd() {
+	newCmd := func(metrics ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("metrics", metrics, "")
+		return cmd
+	}
+
+	s.Run("Should fail with an invalid metric", func() {
+		printer.Clean()
+
+		err := teamAnalyticsCmdF(s.client, newCmd("invalid"), []string{})
+		s.Require().EqualError(err, `invalid metric "invalid"`)
+	})
+
+	s.Run("Should fail if the team doesn't exist", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetTeam("myteam", "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName("myteam", "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := teamAnalyticsCmdF(s.client, newCmd("counts"), []string{"myteam"})
+		s.Require().EqualError(err, `unable to find team "myteam"`)
+	})
+
+	s.Run("Should print the site metrics", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAnalyticsOld("standard", "").
+			Return(model.AnalyticsRows{
+				{Name: "post_count", Value: 120},
+				{Name: "team_count", Value: 2},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAnalyticsOld("post_counts_day", "").
+			Return(model.AnalyticsRows{
+				{Name: "2021-03-05", Value: 10},
+				{Name: "2021-03-04", Value: 5},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := teamAnalyticsCmdF(s.client, newCmd("counts", "posts-per-day"), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&teamAnalyticsRow{Metric: "counts", Name: "post_count", Value: 120},
+			&teamAnalyticsRow{Metric: "counts", Name: "team_count", Value: 2},
+			&teamAnalyticsRow{Metric: "posts-per-day", Name: "2021-03-04", Value: 5},
+			&teamAnalyticsRow{Metric: "posts-per-day", Name: "2021-03-05", Value: 10},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the analytics can't be retrieved", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAnalyticsOld("standard", "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := teamAnalyticsCmdF(s.client, newCmd("counts"), []string{})
+		s.Require().EqualError(err, "unable to get the counts analytics: mock error")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestGetTeamAnalyticsTrendRows() {
+	now := time.Date(2021, 3, 14, 18, 0, 0, 0, time.UTC)
+
+	s.Run("Should compare the last week with the previous one", func() {
+		rows := getTeamAnalyticsTrendRows("posts-per-day", model.AnalyticsRows{
+			{Name: "2021-03-14", Value: 10},
+			{Name: "2021-03-08", Value: 20},
+			{Name: "2021-03-07", Value: 5},
+			{Name: "2021-03-01", Value: 15},
+			{Name: "2021-02-28", Value: 100},
+		}, now)
+		s.Require().Equal([]*teamAnalyticsRow{
+			{Metric: "posts-per-day", Name: "last_7_days", Value: 30},
+			{Metric: "posts-per-day", Name: "previous_7_days", Value: 20},
+			{Metric: "posts-per-day", Name: "change_percent", Value: 50},
+		}, rows)
+	})
+
+	s.Run("Should not report the change without a previous value", func() {
+		rows := getTeamAnalyticsTrendRows("active-users-per-day", model.AnalyticsRows{
+			{Name: "2021-03-10", Value: 4},
+		}, now)
+		s.Require().Equal([]*teamAnalyticsRow{
+			{Metric: "active-users-per-day", Name: "last_7_days", Value: 4},
+			{Metric: "active-users-per-day", Name: "previous_7_days", Value: 0},
+		}, rows)
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var TeamIconCmd = &cobra.Command{
+	Use:   "icon",
+	Short: "Management of team icons",
+}
+
+var TeamIconSetCmd = &cobra.Command{
+	Use:   "set [team] [image-file]",
+	Short: "Set the icon of teams",
+	Long: `Set the icon of a team from a local image file.
+With --bulk, the only argument is a directory of image files named after the teams they belong to, e.g. "myteam.png", and the icon of each of those teams is set.`,
+	Example: `  team icon set myteam ./icon.png
+  team icon set --bulk ./team-icons`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: withClient(teamIconSetCmdF),
+}
+
+var TeamIconRemoveCmd = &cobra.Command{
+	Use:   "remove [teams]",
+	Short: "Remove the icon of teams",
+	Long:  "Remove the icon of the given teams, or of all the teams with --bulk, so the team initials are shown instead.",
+	Example: `  team icon remove myteam otherteam
+  team icon remove --bulk --confirm`,
+	RunE: withClient(teamIconRemoveCmdF),
+}
+
+func init() {
+	TeamIconSetCmd.Flags().Bool("bulk", false, "Set the icons of all the teams with an image file in the given directory")
+
+	TeamIconRemoveCmd.Flags().Bool("bulk", false, "Remove the icons of all the teams instead of the teams provided as arguments")
+	TeamIconRemoveCmd.Flags().Bool("confirm", false, "Confirm you really want to remove the icons of all the teams")
+
+	TeamIconCmd.AddCommand(
+		TeamIconSetCmd,
+		TeamIconRemoveCmd,
+	)
+	TeamCmd.AddCommand(TeamIconCmd)
+}
+
+type teamIconResult struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+}
+
+// getTeamIconFiles maps the team names to the image files of the
+// directory, named after the team they belong to
+func getTeamIconFiles(dir string) (map[string]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read icons directory: %w", err)
+	}
+
+	files := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		teamName := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if other, ok := files[teamName]; ok {
+			return nil, fmt.Errorf("files %q and %q both belong to team %q", filepath.Base(other), entry.Name(), teamName)
+		}
+		files[teamName] = filepath.Join(dir, entry.Name())
+	}
+
+	return files, nil
+}
+
+func setTeamIcon(c client.Client, team *model.Team, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read icon file: %w", err)
+	}
+
+	if _, err := c.SetTeamIcon(team.Id, data); err != nil {
+		return err
+	}
+
+	printer.PrintT("Icon of team '{{.Name}}' set from {{.File}}", teamIconResult{ID: team.Id, Name: team.Name, File: path})
+	return nil
+}
+
+func teamIconSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	bulk, _ := cmd.Flags().GetBool("bulk")
+	if !bulk {
+		if len(args) != 2 {
+			return errors.New("expected a team and an image file, or a directory along with the --bulk flag")
+		}
+
+		team := getTeamFromTeamArg(c, args[0])
+		if team == nil {
+			return fmt.Errorf("unable to find team %q", args[0])
+		}
+		if err := setTeamIcon(c, team, args[1]); err != nil {
+			return fmt.Errorf("unable to set the icon of team %q: %w", team.Name, err)
+		}
+		return nil
+	}
+
+	if len(args) != 1 {
+		return errors.New("expected a directory of image files along with the --bulk flag")
+	}
+
+	files, err := getTeamIconFiles(args[0])
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no image files found in %q", args[0])
+	}
+
+	teamNames := make([]string, 0, len(files))
+	for teamName := range files {
+		teamNames = append(teamNames, teamName)
+	}
+	sort.Strings(teamNames)
+
+	failed := 0
+	for _, teamName := range teamNames {
+		path := files[teamName]
+		team := getTeamFromTeamArg(c, teamName)
+		if team == nil {
+			printer.PrintError(fmt.Sprintf("unable to find team %q for file %q", teamName, path))
+			failed++
+			continue
+		}
+		if err := setTeamIcon(c, team, path); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to set the icon of team %q: %s", team.Name, err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to set %d of %d team icons", failed, len(files))
+	}
+
+	return nil
+}
+
+func getAllTeams(c client.Client) ([]*model.Team, error) {
+	teams := []*model.Team{}
+	for page := 0; ; page++ {
+		teamsPage, _, err := c.GetAllTeams("", page, APILimitMaximum)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, teamsPage...)
+
+		if len(teamsPage) < APILimitMaximum {
+			break
+		}
+	}
+
+	return teams, nil
+}
+
+func teamIconRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	bulk, _ := cmd.Flags().GetBool("bulk")
+
+	var teams []*model.Team
+	if bulk {
+		if len(args) > 0 {
+			return errors.New("teams cannot be provided as arguments along with the --bulk flag")
+		}
+
+		confirmFlag, _ := cmd.Flags().GetBool("confirm")
+		if !confirmFlag {
+			if err := getConfirmation("Are you sure you want to remove the icons of all the teams?", false); err != nil {
+				return err
+			}
+		}
+
+		allTeams, err := getAllTeams(c)
+		if err != nil {
+			return errors.Wrap(err, "Failed to fetch teams")
+		}
+		for _, team := range allTeams {
+			if team.LastTeamIconUpdate > 0 {
+				teams = append(teams, team)
+			}
+		}
+	} else {
+		if len(args) == 0 {
+			return errors.New("expected at least one team or the --bulk flag. See help text for details")
+		}
+		for i, team := range getTeamsFromTeamArgs(c, args) {
+			if team == nil {
+				printer.PrintError("Unable to find team '" + args[i] + "'")
+				continue
+			}
+			teams = append(teams, team)
+		}
+	}
+
+	for _, team := range teams {
+		if _, err := c.RemoveTeamIcon(team.Id); err != nil {
+			printer.PrintError("Unable to remove the icon of team '" + team.Name + "' error: " + err.Error())
+			continue
+		}
+		printer.PrintT("Icon of team '{{.Name}}' removed", teamIconResult{ID: team.Id, Name: team.Name})
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestTeamIconSetCmd() {
+	team1 := &model.Team{Id: "team-1", Name: "team1"}
+	team2 := &model.Team{Id: "team-2", Name: "team2"}
+
+	newCmd := func(bulk bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", bulk, "")
+		return cmd
+	}
+
+	s.Run("Should set the icon of a team", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "icon.png")
+		s.Require().NoError(ioutil.WriteFile(path, []byte("image"), 0600))
+
+		s.client.
+			EXPECT().
+			GetTeam(team1.Name, "").
+			Return(team1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetTeamIcon(team1.Id, []byte("image")).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := teamIconSetCmdF(s.client, newCmd(false), []string{team1.Name, path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(teamIconResult{ID: team1.Id, Name: team1.Name, File: path}, printer.GetLines()[0])
+	})
+
+	s.Run("Should require an image file without --bulk", func() {
+		printer.Clean()
+
+		err := teamIconSetCmdF(s.client, newCmd(false), []string{team1.Name})
+		s.Require().EqualError(err, "expected a team and an image file, or a directory along with the --bulk flag")
+	})
+
+	s.Run("Should set the icons of the teams of a directory", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, "team1.png"), []byte("image1"), 0600))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, "team2.jpg"), []byte("image2"), 0600))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, "unknown.png"), []byte("image3"), 0600))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, ".DS_Store"), []byte{}, 0600))
+
+		s.client.
+			EXPECT().
+			GetTeam(team1.Name, "").
+			Return(team1, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(team2.Name, "").
+			Return(team2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam("unknown", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName("unknown", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetTeamIcon(team1.Id, []byte("image1")).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetTeamIcon(team2.Id, []byte("image2")).
+			Return(&model.Response{}, errors.New("invalid image")).
+			Times(1)
+
+		err := teamIconSetCmdF(s.client, newCmd(true), []string{tmp})
+		s.Require().EqualError(err, "unable to set 2 of 3 team icons")
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(teamIconResult{ID: team1.Id, Name: team1.Name, File: filepath.Join(tmp, "team1.png")}, printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 2)
+		s.Require().Equal(`unable to set the icon of team "team2": invalid image`, printer.GetErrorLines()[0])
+		s.Require().Contains(printer.GetErrorLines()[1], `unable to find team "unknown"`)
+	})
+
+	s.Run("Should fail if two files belong to the same team", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, "team1.jpg"), []byte("image1"), 0600))
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(tmp, "team1.png"), []byte("image2"), 0600))
+
+		err := teamIconSetCmdF(s.client, newCmd(true), []string{tmp})
+		s.Require().EqualError(err, `files "team1.jpg" and "team1.png" both belong to team "team1"`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestTeamIconRemoveCmd() {
+	team1 := &model.Team{Id: "team-1", Name: "team1", LastTeamIconUpdate: 1}
+	team2 := &model.Team{Id: "team-2", Name: "team2"}
+
+	s.Run("Should remove the icons of the given teams", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", false, "")
+
+		s.client.
+			EXPECT().
+			GetTeam(team2.Name, "").
+			Return(team2, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveTeamIcon(team2.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := teamIconRemoveCmdF(s.client, cmd, []string{team2.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(teamIconResult{ID: team2.Id, Name: team2.Name}, printer.GetLines()[0])
+	})
+
+	s.Run("Should remove the icons of all the teams that have one", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", true, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{team1, team2}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveTeamIcon(team1.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := teamIconRemoveCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(teamIconResult{ID: team1.Id, Name: team1.Name}, printer.GetLines()[0])
+	})
+
+	s.Run("Should not accept teams along with --bulk", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", true, "")
+
+		err := teamIconRemoveCmdF(s.client, cmd, []string{team1.Name})
+		s.Require().EqualError(err, "teams cannot be provided as arguments along with the --bulk flag")
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var TeamPrivacyCmd = &cobra.Command{
+	Use:   "privacy",
+	Short: "Management of the privacy of teams",
+}
+
+var TeamPrivacyAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report the teams open to self-service",
+	Long: `Report the teams that allow open sign-up, have open invites or accept users of any email domain, sorted by member count, biggest first.
+A team allows open sign-up when it is public, has open invites when any user of the server can join it, and accepts any email domain when it has no allowed domains. Archived and group synced teams are not reported, as nobody can join them on their own.`,
+	Example: `  team privacy audit
+
+  # include the teams that aren't open to self-service
+  team privacy audit --all`,
+	Args: cobra.NoArgs,
+	RunE: withClient(teamPrivacyAuditCmdF),
+}
+
+func init() {
+	TeamPrivacyAuditCmd.Flags().Bool("all", false, "Report all the teams, including the ones that aren't open to self-service")
+
+	TeamPrivacyCmd.AddCommand(TeamPrivacyAuditCmd)
+	TeamCmd.AddCommand(TeamPrivacyCmd)
+}
+
+type teamPrivacyAuditRow struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Members    int64  `json:"members"`
+	OpenSignUp bool   `json:"open_sign_up"`
+	OpenInvite bool   `json:"open_invite"`
+	AnyDomain  bool   `json:"any_domain"`
+}
+
+func newTeamPrivacyAuditRow(team *model.Team) *teamPrivacyAuditRow {
+	return &teamPrivacyAuditRow{
+		ID:         team.Id,
+		Name:       team.Name,
+		OpenSignUp: team.Type == model.TeamOpen,
+		OpenInvite: team.AllowOpenInvite,
+		AnyDomain:  strings.TrimSpace(team.AllowedDomains) == "",
+	}
+}
+
+func (r *teamPrivacyAuditRow) isOpen() bool {
+	return r.OpenSignUp || r.OpenInvite || r.AnyDomain
+}
+
+// Exposure describes the ways users can join the team on their own
+func (r *teamPrivacyAuditRow) Exposure() string {
+	exposure := []string{}
+	if r.OpenSignUp {
+		exposure = append(exposure, "open sign-up")
+	}
+	if r.OpenInvite {
+		exposure = append(exposure, "open invite")
+	}
+	if r.AnyDomain {
+		exposure = append(exposure, "any email domain")
+	}
+	if len(exposure) == 0 {
+		return "restricted"
+	}
+	return strings.Join(exposure, ", ")
+}
+
+func teamPrivacyAuditCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	teams, err := getAllTeams(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch teams")
+	}
+
+	rows := []*teamPrivacyAuditRow{}
+	for _, team := range teams {
+		if team.DeleteAt > 0 || team.IsGroupConstrained() {
+			continue
+		}
+		row := newTeamPrivacyAuditRow(team)
+		if !row.isOpen() && !all {
+			continue
+		}
+
+		stats, _, statsErr := c.GetTeamStats(team.Id, "")
+		if statsErr != nil {
+			return errors.Wrapf(statsErr, "failed to get the stats of team %s", team.Name)
+		}
+		row.Members = stats.ActiveMemberCount
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Members != rows[j].Members {
+			return rows[i].Members > rows[j].Members
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	for _, row := range rows {
+		printer.PrintT("{{.Name}} ({{.Members}} members): {{.Exposure}}", row)
+	}
+
+	return nil
+}
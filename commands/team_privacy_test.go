@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestTeamPrivacyAuditCmd() {
+	newCmd := func(all bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", all, "")
+		return cmd
+	}
+
+	open := &model.Team{Id: "open-id", Name: "open", Type: model.TeamOpen, AllowOpenInvite: true}
+	anyDomain := &model.Team{Id: "any-domain-id", Name: "any-domain", Type: model.TeamInvite}
+	restricted := &model.Team{Id: "restricted-id", Name: "restricted", Type: model.TeamInvite, AllowedDomains: "example.com"}
+	archived := &model.Team{Id: "archived-id", Name: "archived", Type: model.TeamOpen, DeleteAt: 1}
+	synced := &model.Team{Id: "synced-id", Name: "synced", Type: model.TeamOpen, GroupConstrained: model.NewBool(true)}
+	teams := []*model.Team{restricted, open, archived, anyDomain, synced}
+
+	s.Run("Should report the open teams sorted by member count", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return(teams, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamStats(open.Id, "").
+			Return(&model.TeamStats{TeamId: open.Id, ActiveMemberCount: 12}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamStats(anyDomain.Id, "").
+			Return(&model.TeamStats{TeamId: anyDomain.Id, ActiveMemberCount: 40}, &model.Response{}, nil).
+			Times(1)
+
+		err := teamPrivacyAuditCmdF(s.client, newCmd(false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&teamPrivacyAuditRow{ID: anyDomain.Id, Name: anyDomain.Name, Members: 40, AnyDomain: true},
+			&teamPrivacyAuditRow{ID: open.Id, Name: open.Name, Members: 12, OpenSignUp: true, OpenInvite: true, AnyDomain: true},
+		}, printer.GetLines())
+		s.Require().Equal("open sign-up, open invite, any email domain", printer.GetLines()[1].(*teamPrivacyAuditRow).Exposure())
+	})
+
+	s.Run("Should include the restricted teams with all", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{restricted}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamStats(restricted.Id, "").
+			Return(&model.TeamStats{TeamId: restricted.Id, ActiveMemberCount: 3}, &model.Response{}, nil).
+			Times(1)
+
+		err := teamPrivacyAuditCmdF(s.client, newCmd(true), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("restricted", printer.GetLines()[0].(*teamPrivacyAuditRow).Exposure())
+	})
+
+	s.Run("Should fail if the stats of a team can't be fetched", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{open}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamStats(open.Id, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := teamPrivacyAuditCmdF(s.client, newCmd(false), []string{})
+		s.Require().EqualError(err, "failed to get the stats of team open: mock error")
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var TeamSchemeReportCmd = &cobra.Command{
+	Use:   "scheme-report [teams]",
+	Short: "Report the permission schemes of teams",
+	Long: `Report the permission scheme that applies to each team, and the channels of the team that override it with their own scheme, as a tree.
+The teams without a scheme use the system scheme, and the channels without a scheme use the scheme of their team. Without arguments all the teams are reported.`,
+	Example: `  team scheme-report
+  team scheme-report myteam --overrides-only`,
+	RunE: withClient(teamSchemeReportCmdF),
+}
+
+func init() {
+	TeamSchemeReportCmd.Flags().Bool("overrides-only", false, "Only report the teams with a scheme of their own or with channels that override it")
+
+	TeamCmd.AddCommand(TeamSchemeReportCmd)
+}
+
+type channelSchemeOverride struct {
+	Channel   string `json:"channel"`
+	ChannelID string `json:"channel_id"`
+	Scheme    string `json:"scheme"`
+	SchemeID  string `json:"scheme_id"`
+}
+
+type teamSchemeReport struct {
+	Team             string                   `json:"team"`
+	TeamID           string                   `json:"team_id"`
+	Scheme           string                   `json:"scheme,omitempty"`
+	SchemeID         string                   `json:"scheme_id,omitempty"`
+	ChannelOverrides []*channelSchemeOverride `json:"channel_overrides"`
+}
+
+func getSchemesByID(c client.Client) (map[string]*model.Scheme, error) {
+	schemes := map[string]*model.Scheme{}
+	for page := 0; ; page++ {
+		pageSchemes, _, err := c.GetSchemes("", page, APILimitMaximum)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the schemes")
+		}
+		for _, scheme := range pageSchemes {
+			schemes[scheme.Id] = scheme
+		}
+		if len(pageSchemes) < APILimitMaximum {
+			return schemes, nil
+		}
+	}
+}
+
+// schemeName returns the display name of the scheme, which may have
+// been deleted since it was assigned
+func schemeName(schemes map[string]*model.Scheme, schemeID string) string {
+	if scheme, ok := schemes[schemeID]; ok {
+		return scheme.DisplayName
+	}
+	return "unknown scheme"
+}
+
+func getTeamSchemeReport(c client.Client, team *model.Team, schemes map[string]*model.Scheme) (*teamSchemeReport, error) {
+	report := &teamSchemeReport{Team: team.Name, TeamID: team.Id, ChannelOverrides: []*channelSchemeOverride{}}
+	if team.SchemeId != nil && *team.SchemeId != "" {
+		report.SchemeID = *team.SchemeId
+		report.Scheme = schemeName(schemes, report.SchemeID)
+	}
+
+	channels, err := getAllPublicChannelsForTeam(c, team.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the public channels")
+	}
+	privateChannels, err := getPrivateChannels(c, team.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the private channels")
+	}
+
+	for _, channel := range append(channels, privateChannels...) {
+		if channel.SchemeId == nil || *channel.SchemeId == "" {
+			continue
+		}
+		report.ChannelOverrides = append(report.ChannelOverrides, &channelSchemeOverride{
+			Channel:   channel.Name,
+			ChannelID: channel.Id,
+			Scheme:    schemeName(schemes, *channel.SchemeId),
+			SchemeID:  *channel.SchemeId,
+		})
+	}
+	return report, nil
+}
+
+func teamSchemeReportCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	overridesOnly, _ := cmd.Flags().GetBool("overrides-only")
+
+	schemes, err := getSchemesByID(c)
+	if err != nil {
+		return err
+	}
+	teams, err := getTeamsOrAllTeams(c, args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, team := range teams {
+		report, err := getTeamSchemeReport(c, team, schemes)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("unable to report the schemes of team %q: %s", team.Name, err))
+			failed++
+			continue
+		}
+		if overridesOnly && report.SchemeID == "" && len(report.ChannelOverrides) == 0 {
+			continue
+		}
+		printer.PrintT(`{{.Team}}: {{if .SchemeID}}{{.Scheme}} ({{.SchemeID}}){{else}}system scheme{{end}}`+
+			`{{range .ChannelOverrides}}`+"\n"+`  └─ {{.Channel}}: {{.Scheme}} ({{.SchemeID}}){{end}}`, report)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to report the schemes of %d teams", failed)
+	}
+	return nil
+}
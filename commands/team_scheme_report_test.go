@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/web"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestTeamSchemeReportCmd() {
+	newCmd := func(overridesOnly bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("overrides-only", overridesOnly, "")
+		return cmd
+	}
+
+	teamScheme := &model.Scheme{Id: "team-scheme-id", DisplayName: "Restricted teams", Scope: model.SchemeScopeTeam}
+	channelScheme := &model.Scheme{Id: "channel-scheme-id", DisplayName: "Read only", Scope: model.SchemeScopeChannel}
+
+	custom := &model.Team{Id: "custom-id", Name: "custom", SchemeId: model.NewString(teamScheme.Id)}
+	system := &model.Team{Id: "system-id", Name: "system"}
+
+	announcements := &model.Channel{Id: "announcements-id", Name: "announcements", SchemeId: model.NewString(channelScheme.Id)}
+	private := &model.Channel{Id: "private-id", Name: "private", Type: model.ChannelTypePrivate, SchemeId: model.NewString("deleted-scheme-id")}
+	general := &model.Channel{Id: "general-id", Name: "general"}
+
+	expectChannels := func(team *model.Team, public, private []*model.Channel) {
+		s.client.
+			EXPECT().
+			GetPublicChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return(public, &model.Response{}, nil).
+			Times(1)
+
+		if len(public) > 0 {
+			s.client.
+				EXPECT().
+				GetPublicChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+				Return([]*model.Channel{}, &model.Response{}, nil).
+				Times(1)
+		}
+
+		s.client.
+			EXPECT().
+			GetPrivateChannelsForTeam(team.Id, 0, web.PerPageMaximum, "").
+			Return(private, &model.Response{}, nil).
+			Times(1)
+
+		if len(private) > 0 {
+			s.client.
+				EXPECT().
+				GetPrivateChannelsForTeam(team.Id, 1, web.PerPageMaximum, "").
+				Return([]*model.Channel{}, &model.Response{}, nil).
+				Times(1)
+		}
+	}
+
+	expectTeams := func() {
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{teamScheme, channelScheme}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{custom, system}, &model.Response{}, nil).
+			Times(1)
+
+		expectChannels(custom, []*model.Channel{announcements, general}, []*model.Channel{private})
+		expectChannels(system, []*model.Channel{general}, nil)
+	}
+
+	s.Run("Should report the schemes of all the teams", func() {
+		printer.Clean()
+		expectTeams()
+
+		err := teamSchemeReportCmdF(s.client, newCmd(false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&teamSchemeReport{Team: custom.Name, TeamID: custom.Id, Scheme: teamScheme.DisplayName, SchemeID: teamScheme.Id, ChannelOverrides: []*channelSchemeOverride{
+				{Channel: announcements.Name, ChannelID: announcements.Id, Scheme: channelScheme.DisplayName, SchemeID: channelScheme.Id},
+				{Channel: private.Name, ChannelID: private.Id, Scheme: "unknown scheme", SchemeID: "deleted-scheme-id"},
+			}},
+			&teamSchemeReport{Team: system.Name, TeamID: system.Id, ChannelOverrides: []*channelSchemeOverride{}},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should only report the teams with overrides", func() {
+		printer.Clean()
+		printer.SetFormat(printer.FormatPlain)
+		defer printer.SetFormat(printer.FormatJSON)
+		expectTeams()
+
+		err := teamSchemeReportCmdF(s.client, newCmd(true), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"custom: Restricted teams (team-scheme-id)\n" +
+			"  └─ announcements: Read only (channel-scheme-id)\n" +
+			"  └─ private: unknown scheme (deleted-scheme-id)"}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the schemes cannot be fetched", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := teamSchemeReportCmdF(s.client, newCmd(false), []string{})
+		s.Require().EqualError(err, "failed to fetch the schemes: mock error")
+	})
+}
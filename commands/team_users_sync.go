@@ -0,0 +1,276 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var TeamUsersSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make the members of teams match a manifest",
+	Long: `Make the members of the teams of a YAML manifest match the members listed for them, so the team memberships can be managed from version control. The manifest maps the teams, by name or ID, to their members, by username, email or ID:
+
+  teams:
+    engineering:
+      - john.doe
+      - jane@example.com
+
+The plan of the additions and removals is printed first. The users missing from the teams are added, and with --remove the members of the teams that are not in the manifest are removed, except for the bots and the deactivated users. The teams that aren't in the manifest are left unchanged.
+With --plan, the additions and removals are printed as a JSON plan that can be reviewed and then applied with --apply-plan, without the manifest.`,
+	Example: `  team users sync --file teams.yaml --dry-run
+  team users sync --file teams.yaml --remove --confirm
+
+  # review the changes before applying them
+  team users sync --file teams.yaml --remove --plan > plan.json
+  team users sync --apply-plan plan.json --confirm`,
+	Args: cobra.NoArgs,
+	RunE: withClient(withPlanActions(teamUsersSyncPlanResolver, applyTeamUsersSyncPlan, teamUsersSyncCmdF)),
+}
+
+func init() {
+	TeamUsersSyncCmd.Flags().String("file", "", "YAML manifest of the members of the teams")
+	TeamUsersSyncCmd.Flags().Bool("remove", false, "Remove the members of the teams that are not in the manifest")
+	TeamUsersSyncCmd.Flags().Bool("dry-run", false, "Print the plan without changing the members of the teams")
+	TeamUsersSyncCmd.Flags().Bool("confirm", false, "Confirm you really want to remove the members that are not in the manifest")
+	addPlanFlags(TeamUsersSyncCmd)
+
+	TeamUsersCmd.AddCommand(TeamUsersSyncCmd)
+}
+
+// teamMembersManifest maps the teams to their members
+type teamMembersManifest struct {
+	Teams map[string][]string `yaml:"teams"`
+}
+
+const (
+	teamUsersSyncAdd    = "add"
+	teamUsersSyncRemove = "remove"
+)
+
+type teamUsersSyncAction struct {
+	Action   string `json:"action"`
+	Team     string `json:"team"`
+	TeamID   string `json:"team_id"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id"`
+}
+
+func loadTeamMembersManifest(path string) (*teamMembersManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the manifest")
+	}
+	defer f.Close()
+
+	var manifest teamMembersManifest
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, errors.Wrapf(err, "invalid manifest %s", path)
+	}
+	if len(manifest.Teams) == 0 {
+		return nil, fmt.Errorf("invalid manifest %s: no teams", path)
+	}
+	return &manifest, nil
+}
+
+// planTeamUsersSync returns the members to add to the team and, if
+// remove is set, the members to remove from it to match the manifest
+func planTeamUsersSync(c client.Client, team *model.Team, memberArgs []string, remove bool) ([]*teamUsersSyncAction, error) {
+	wanted, err := getUsersFromArgs(c, memberArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]*model.User{}
+	for page := 0; ; page++ {
+		users, _, usersErr := c.GetUsersInTeam(team.Id, page, APILimitMaximum, "")
+		if usersErr != nil {
+			return nil, errors.Wrap(usersErr, "failed to fetch the members of the team")
+		}
+		for _, user := range users {
+			current[user.Id] = user
+		}
+		if len(users) < APILimitMaximum {
+			break
+		}
+	}
+
+	actions := []*teamUsersSyncAction{}
+	wantedIDs := map[string]bool{}
+	for _, user := range wanted {
+		if wantedIDs[user.Id] {
+			continue
+		}
+		wantedIDs[user.Id] = true
+		if _, ok := current[user.Id]; !ok {
+			actions = append(actions, &teamUsersSyncAction{Action: teamUsersSyncAdd, Team: team.Name, TeamID: team.Id, Username: user.Username, UserID: user.Id})
+		}
+	}
+	if remove {
+		removals := []*teamUsersSyncAction{}
+		for _, user := range current {
+			if wantedIDs[user.Id] || user.IsBot || user.DeleteAt > 0 {
+				continue
+			}
+			removals = append(removals, &teamUsersSyncAction{Action: teamUsersSyncRemove, Team: team.Name, TeamID: team.Id, Username: user.Username, UserID: user.Id})
+		}
+		sort.Slice(removals, func(i, j int) bool { return removals[i].Username < removals[j].Username })
+		actions = append(actions, removals...)
+	}
+	return actions, nil
+}
+
+// planTeamUsersSyncManifest returns the additions and removals needed
+// for the members of the teams of the manifest of the --file flag to
+// match it
+func planTeamUsersSyncManifest(c client.Client, cmd *cobra.Command) ([]*teamUsersSyncAction, error) {
+	path, _ := cmd.Flags().GetString("file")
+	remove, _ := cmd.Flags().GetBool("remove")
+	if path == "" {
+		return nil, errors.New("the --file flag is required")
+	}
+
+	manifest, err := loadTeamMembersManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	teamArgs := make([]string, 0, len(manifest.Teams))
+	for teamArg := range manifest.Teams {
+		teamArgs = append(teamArgs, teamArg)
+	}
+	sort.Strings(teamArgs)
+
+	plan := []*teamUsersSyncAction{}
+	for _, teamArg := range teamArgs {
+		team := getTeamFromTeamArg(c, teamArg)
+		if team == nil {
+			return nil, fmt.Errorf("unable to find team %q", teamArg)
+		}
+		actions, planErr := planTeamUsersSync(c, team, manifest.Teams[teamArg], remove)
+		if planErr != nil {
+			return nil, errors.Wrapf(planErr, "unable to plan the members of team %q", teamArg)
+		}
+		plan = append(plan, actions...)
+	}
+	return plan, nil
+}
+
+// teamUsersSyncPlanActions maps the actions of the sync to the actions
+// of their plans
+var teamUsersSyncPlanActions = map[string]string{
+	teamUsersSyncAdd:    "add_team_member",
+	teamUsersSyncRemove: "remove_team_member",
+}
+
+func teamUsersSyncPlanResolver(c client.Client, cmd *cobra.Command, _ []string) ([]planAction, error) {
+	plan, err := planTeamUsersSyncManifest(c, cmd)
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, action := range plan {
+		actions = append(actions, planAction{
+			Action:     teamUsersSyncPlanActions[action.Action],
+			ID:         action.UserID,
+			Name:       action.Username,
+			TargetID:   action.TeamID,
+			TargetName: action.Team,
+		})
+	}
+	return actions, nil
+}
+
+func applyTeamUsersSyncPlan(c client.Client, cmd *cobra.Command, actions []planAction) error {
+	plan := []*teamUsersSyncAction{}
+	for _, action := range actions {
+		syncAction := &teamUsersSyncAction{Team: action.TargetName, TeamID: action.TargetID, Username: action.Name, UserID: action.ID}
+		for name, planName := range teamUsersSyncPlanActions {
+			if action.Action == planName {
+				syncAction.Action = name
+			}
+		}
+		if syncAction.Action == "" || syncAction.TeamID == "" {
+			return fmt.Errorf("unsupported plan action %q", action.Action)
+		}
+		plan = append(plan, syncAction)
+	}
+	return syncTeamUsers(c, cmd, plan)
+}
+
+func teamUsersSyncCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	// the whole plan is made before changing anything, so a mistake in
+	// the manifest doesn't leave the teams half synced
+	plan, err := planTeamUsersSyncManifest(c, cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(plan) == 0 {
+		printer.Print("The members of the teams match the manifest")
+		return nil
+	}
+	return syncTeamUsers(c, cmd, plan)
+}
+
+// syncTeamUsers prints the plan and performs its additions and
+// removals, asking to confirm the removals
+func syncTeamUsers(c client.Client, cmd *cobra.Command, plan []*teamUsersSyncAction) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	removals := 0
+	for _, action := range plan {
+		if action.Action == teamUsersSyncRemove {
+			removals++
+		}
+		printer.PrintT(`{{if eq .Action "add"}}+{{else}}-{{end}} {{.Team}}: {{.Username}}`, action)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if removals > 0 && !confirmFlag {
+		// the plan is printed before asking to confirm the removals in it
+		if err := printer.Flush(); err != nil {
+			return err
+		}
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to remove %d members from their teams?", removals), false); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, action := range plan {
+		if action.Action == teamUsersSyncAdd {
+			if _, _, addErr := c.AddTeamMember(action.TeamID, action.UserID); addErr != nil {
+				printer.PrintError(fmt.Sprintf("unable to add user %q to team %q: %s", action.Username, action.Team, addErr))
+				failed++
+			}
+			continue
+		}
+		if _, removeErr := c.RemoveTeamMember(action.TeamID, action.UserID); removeErr != nil {
+			printer.PrintError(fmt.Sprintf("unable to remove user %q from team %q: %s", action.Username, action.Team, removeErr))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to sync %d of %d members", failed, len(plan))
+	}
+	printer.Print(fmt.Sprintf("Added %d and removed %d members", len(plan)-removals, removals))
+	return nil
+}
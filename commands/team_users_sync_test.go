@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestTeamUsersSyncCmd() {
+	team := &model.Team{Id: model.NewId(), Name: "engineering"}
+	john := &model.User{Id: model.NewId(), Username: "john.doe", Email: "john@example.com"}
+	jane := &model.User{Id: model.NewId(), Username: "jane.doe", Email: "jane@example.com"}
+	former := &model.User{Id: model.NewId(), Username: "former"}
+	bot := &model.User{Id: model.NewId(), Username: "bot", IsBot: true}
+
+	writeManifest := func() (string, func()) {
+		dir, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(dir, "teams.yaml")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`teams:
+  `+team.Id+`:
+    - john@example.com
+    - jane@example.com
+`), 0600))
+		return path, func() { os.RemoveAll(dir) }
+	}
+
+	newCmd := func(path string, remove, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("remove", remove, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	expectPlan := func() {
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(john.Email, "").
+			Return(john, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(jane.Email, "").
+			Return(jane, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{john, former, bot}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Should add and remove members to match the manifest", func() {
+		printer.Clean()
+		path, cleanup := writeManifest()
+		defer cleanup()
+
+		expectPlan()
+		s.client.
+			EXPECT().
+			AddTeamMember(team.Id, jane.Id).
+			Return(&model.TeamMember{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RemoveTeamMember(team.Id, former.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := teamUsersSyncCmdF(s.client, newCmd(path, true, false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&teamUsersSyncAction{Action: teamUsersSyncAdd, Team: team.Name, TeamID: team.Id, Username: jane.Username, UserID: jane.Id},
+			&teamUsersSyncAction{Action: teamUsersSyncRemove, Team: team.Name, TeamID: team.Id, Username: former.Username, UserID: former.Id},
+			"Added 1 and removed 1 members",
+		}, printer.GetLines())
+	})
+
+	s.Run("Should only print the plan in dry run", func() {
+		printer.Clean()
+		path, cleanup := writeManifest()
+		defer cleanup()
+
+		expectPlan()
+
+		err := teamUsersSyncCmdF(s.client, newCmd(path, false, true), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&teamUsersSyncAction{Action: teamUsersSyncAdd, Team: team.Name, TeamID: team.Id, Username: jane.Username, UserID: jane.Id},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail without changes if a team can't be found", func() {
+		printer.Clean()
+		path, cleanup := writeManifest()
+		defer cleanup()
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Id, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := teamUsersSyncCmdF(s.client, newCmd(path, true, false), []string{})
+		s.Require().EqualError(err, `unable to find team "`+team.Id+`"`)
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should plan the changes and apply the plan without the manifest", func() {
+		printer.Clean()
+		path, cleanup := writeManifest()
+		defer cleanup()
+
+		expectPlan()
+		actions, err := teamUsersSyncPlanResolver(s.client, newCmd(path, true, false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]planAction{
+			{Action: "add_team_member", ID: jane.Id, Name: jane.Username, TargetID: team.Id, TargetName: team.Name},
+			{Action: "remove_team_member", ID: former.Id, Name: former.Username, TargetID: team.Id, TargetName: team.Name},
+		}, actions)
+
+		s.client.
+			EXPECT().
+			AddTeamMember(team.Id, jane.Id).
+			Return(&model.TeamMember{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RemoveTeamMember(team.Id, former.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err = applyTeamUsersSyncPlan(s.client, newCmd("", false, false), actions)
+		s.Require().NoError(err)
+		s.Require().Equal("Added 1 and removed 1 members", printer.GetLines()[2])
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// otlpSpanKind values as defined by the OTLP protocol
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeError = 2
+)
+
+// apiTracer records the API calls of the clients created while it is
+// set, and is set by withClient when the --trace-otlp flag is used
+var apiTracer *tracer
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func intAttribute(key string, value int) otlpAttribute {
+	// int64 values are encoded as strings in the OTLP JSON encoding
+	s := strconv.Itoa(value)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+// tracer collects a span for each API call made during a command,
+// all of them children of a span for the command itself
+type tracer struct {
+	mu         sync.Mutex
+	name       string
+	traceID    string
+	rootSpanID string
+	start      time.Time
+	spans      []otlpSpan
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func newTracer(name string) *tracer {
+	return &tracer{
+		name:       name,
+		traceID:    newID(16),
+		rootSpanID: newID(8),
+		start:      time.Now(),
+	}
+}
+
+// traceparent returns the W3C trace context header for a span, so the
+// server side traces of the request can be correlated with it
+func (t *tracer) traceparent(spanID string) string {
+	return fmt.Sprintf("00-%s-%s-01", t.traceID, spanID)
+}
+
+func (t *tracer) addSpan(span otlpSpan) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, span)
+}
+
+// wrap returns a round tripper that records the requests made through
+// the given one
+func (t *tracer) wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &traceTransport{tracer: t, base: base}
+}
+
+type traceTransport struct {
+	tracer *tracer
+	base   http.RoundTripper
+}
+
+// tracedBody ends the span of a request when its response body is
+// closed, so the span covers the download of the body too
+type tracedBody struct {
+	io.ReadCloser
+	once sync.Once
+	end  func()
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.end)
+	return err
+}
+
+func (tt *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	spanID := newID(8)
+	req = req.Clone(req.Context())
+	req.Header.Set("traceparent", tt.tracer.traceparent(spanID))
+
+	start := time.Now()
+	resp, err := tt.base.RoundTrip(req)
+
+	span := otlpSpan{
+		TraceID:           tt.tracer.traceID,
+		SpanID:            spanID,
+		ParentSpanID:      tt.tracer.rootSpanID,
+		Name:              req.Method + " " + req.URL.Path,
+		Kind:              otlpSpanKindClient,
+		StartTimeUnixNano: unixNano(start),
+		Attributes: []otlpAttribute{
+			stringAttribute("http.method", req.Method),
+			stringAttribute("http.target", req.URL.Path),
+			stringAttribute("net.peer.name", req.URL.Host),
+		},
+	}
+
+	if err != nil {
+		span.EndTimeUnixNano = unixNano(time.Now())
+		span.Status = otlpStatus{Code: otlpStatusCodeError, Message: err.Error()}
+		tt.tracer.addSpan(span)
+		return resp, err
+	}
+
+	span.Attributes = append(span.Attributes, intAttribute("http.status_code", resp.StatusCode))
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		span.Attributes = append(span.Attributes, stringAttribute("mattermost.request_id", requestID))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.Status = otlpStatus{Code: otlpStatusCodeError}
+	}
+	resp.Body = &tracedBody{ReadCloser: resp.Body, end: func() {
+		span.EndTimeUnixNano = unixNano(time.Now())
+		tt.tracer.addSpan(span)
+	}}
+
+	return resp, nil
+}
+
+// payload builds the OTLP JSON export request of the recorded spans,
+// closing the command span with the given error
+func (t *tracer) payload(cmdErr error) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	root := otlpSpan{
+		TraceID:           t.traceID,
+		SpanID:            t.rootSpanID,
+		Name:              t.name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: unixNano(t.start),
+		EndTimeUnixNano:   unixNano(time.Now()),
+	}
+	if cmdErr != nil {
+		root.Status = otlpStatus{Code: otlpStatusCodeError, Message: cmdErr.Error()}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpAttribute{
+						stringAttribute("service.name", "mmctl"),
+						stringAttribute("service.version", Version),
+					},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "mmctl"},
+						"spans": append([]otlpSpan{root}, t.spans...),
+					},
+				},
+			},
+		},
+	})
+}
+
+// export sends the recorded spans to an OTLP/HTTP endpoint if the
+// destination is an URL, or writes them to a local file otherwise
+func (t *tracer) export(destination string, cmdErr error) error {
+	payload, err := t.payload(cmdErr)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(destination, "http://") && !strings.HasPrefix(destination, "https://") {
+		return ioutil.WriteFile(destination, payload, 0600)
+	}
+
+	if !strings.HasSuffix(destination, "/v1/traces") {
+		destination = strings.TrimSuffix(destination, "/") + "/v1/traces"
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Post(destination, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("the collector responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
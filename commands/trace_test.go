@@ -0,0 +1,133 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type otlpExport struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []otlpSpan `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func TestTraceTransport(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("X-Request-Id", "request-id")
+		if r.URL.Path == "/api/v4/users/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tr := newTracer("mmctl user list")
+	httpClient := &http.Client{Transport: tr.wrap(nil)}
+
+	resp, err := httpClient.Get(server.URL + "/api/v4/users/me")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Len(t, tr.spans, 1)
+	require.Equal(t, tr.traceparent(tr.spans[0].SpanID), traceparent)
+
+	resp, err = httpClient.Get(server.URL + "/api/v4/users/missing")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	tmp, err := ioutil.TempDir("", "mmctl-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "trace.json")
+	require.NoError(t, tr.export(path, errors.New("command failed")))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var export otlpExport
+	require.NoError(t, json.Unmarshal(b, &export))
+	spans := export.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 3)
+
+	root := spans[0]
+	require.Equal(t, "mmctl user list", root.Name)
+	require.Equal(t, otlpSpanKindInternal, root.Kind)
+	require.Equal(t, otlpStatus{Code: otlpStatusCodeError, Message: "command failed"}, root.Status)
+
+	for _, span := range spans[1:] {
+		require.Equal(t, root.TraceID, span.TraceID)
+		require.Equal(t, root.SpanID, span.ParentSpanID)
+		require.Equal(t, otlpSpanKindClient, span.Kind)
+		require.Contains(t, span.Attributes, stringAttribute("mattermost.request_id", "request-id"))
+	}
+	require.Equal(t, "GET /api/v4/users/me", spans[1].Name)
+	require.Contains(t, spans[1].Attributes, intAttribute("http.status_code", http.StatusOK))
+	require.Equal(t, otlpStatus{}, spans[1].Status)
+	require.Contains(t, spans[2].Attributes, intAttribute("http.status_code", http.StatusNotFound))
+	require.Equal(t, otlpStatus{Code: otlpStatusCodeError}, spans[2].Status)
+}
+
+func TestTraceTransportBody(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	tr := newTracer("mmctl export download")
+	httpClient := &http.Client{Transport: tr.wrap(&http.Transport{})}
+
+	resp, err := httpClient.Get(server.URL + "/api/v4/exports/export.zip")
+	require.NoError(t, err)
+	// the span is still open while the body is downloaded
+	require.Empty(t, tr.spans)
+
+	close(release)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "body", string(b))
+	require.Empty(t, tr.spans)
+
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, resp.Body.Close())
+	require.Len(t, tr.spans, 1)
+	require.NotEmpty(t, tr.spans[0].EndTimeUnixNano)
+}
+
+func TestTraceExportOTLP(t *testing.T) {
+	var path string
+	var export otlpExport
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&export)
+	}))
+	defer collector.Close()
+
+	tr := newTracer("mmctl version")
+	require.NoError(t, tr.export(collector.URL, nil))
+	require.Equal(t, "/v1/traces", path)
+	require.Len(t, export.ResourceSpans[0].ScopeSpans[0].Spans, 1)
+	require.Equal(t, "mmctl version", export.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	require.EqualError(t, tr.export(failing.URL+"/v1/traces", nil), "the collector responded with status 503")
+}
@@ -39,7 +39,7 @@ var UserDeactivateCmd = &cobra.Command{
 	Long:  "Deactivate users. Deactivated users are immediately logged out of all sessions and are unable to log back in.",
 	Example: `  user deactivate user@example.com
   user deactivate username`,
-	RunE: withClient(userDeactivateCmdF),
+	RunE: withClient(withPlan(userPlanResolver("deactivate_user"), userDeactivateCmdF)),
 	Args: cobra.MinimumNArgs(1),
 }
 
@@ -89,6 +89,22 @@ var UpdateUserEmailCmd = &cobra.Command{
 	RunE:    withClient(updateUserEmailCmdF),
 }
 
+var UserEmailVerifyCmd = &cobra.Command{
+	Use:   "verify [users]",
+	Short: "Manage email verification of users",
+	Long: `Report the email verification coverage of users, resending the verification email to unverified users or marking them as verified.
+Users can be specified as arguments, or all active users can be selected with the --bulk flag.`,
+	Example: `  # report which users have not verified their email
+  user email verify --bulk
+
+  # resend the verification email to all unverified users of a team
+  user email verify --bulk --team myteam --resend
+
+  # mark some users as verified without requiring them to complete the email verification
+  user email verify user1 user2@example.com --mark-verified`,
+	RunE: withClient(userEmailVerifyCmdF),
+}
+
 var UpdateUsernameCmd = &cobra.Command{
 	Use:     "username [user] [new username]",
 	Short:   "Change username of the user",
@@ -135,9 +151,13 @@ var DeleteUsersCmd = &cobra.Command{
 	Short: "Delete users",
 	Long: `Permanently delete some users.
 Permanently deletes one or multiple users along with all related information including posts from the database.`,
-	Example: "  user delete user@example.com",
-	Args:    cobra.MinimumNArgs(1),
-	RunE:    withClient(deleteUsersCmdF),
+	Example: `  user delete user@example.com
+
+  # review the plan before applying it
+  user delete user@example.com username --plan > plan.json
+  user delete --apply-plan plan.json --confirm`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(withPlan(userPlanResolver("delete_user"), deleteUsersCmdF)),
 }
 
 var DeleteAllUsersCmd = &cobra.Command{
@@ -159,12 +179,17 @@ var SearchUserCmd = &cobra.Command{
 }
 
 var ListUsersCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List users",
-	Long:    "List all users",
-	Example: "  user list",
-	RunE:    withClient(listUsersCmdF),
-	Args:    cobra.NoArgs,
+	Use:   "list",
+	Short: "List users",
+	Long: `List all users, optionally filtered by team membership, state, role and authentication service.
+The filters that the server can't combine in a single request are applied by mmctl on each fetched page of users instead. In that case, pages of users are fetched until enough matching users are found, or until all the users are walked through with --all. Use --verbose to see which filters were applied by the server and which by mmctl.
+The deactivated users are listed along with the active ones, and --include-deleted shows their deletion time, which helps to tell them apart. With --deleted-only, only the deactivated users are listed along with their deletion time.`,
+	Example: `  user list
+  user list --team myteam --inactive
+  user list --deleted-only --all
+  user list --not-in-team myteam --role system_admin --verbose`,
+	RunE: withClient(listUsersCmdF),
+	Args: cobra.NoArgs,
 }
 
 var VerifyUserEmailWithoutTokenCmd = &cobra.Command{
@@ -270,12 +295,30 @@ func init() {
 	UserCreateCmd.Flags().Bool("disable-welcome-email", false, "Optional. If supplied, the new user will not receive a welcome email. Defaults to false")
 
 	DeleteUsersCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the user and a DB backup has been performed")
+	addPlanFlags(DeleteUsersCmd)
+	addPlanFlags(UserDeactivateCmd)
+	addConcurrencyFlag(UserActivateCmd, "users")
+	addConcurrencyFlag(UserDeactivateCmd, "users")
 	DeleteAllUsersCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the user and a DB backup has been performed")
 
+	UserEmailVerifyCmd.Flags().Bool("bulk", false, "Select all active users instead of the users provided as arguments")
+	UserEmailVerifyCmd.Flags().String("team", "", "If supplied along with --bulk, only users belonging to this team will be selected")
+	UserEmailVerifyCmd.Flags().Bool("resend", false, "Resend the verification email to the selected users that have not verified their email")
+	UserEmailVerifyCmd.Flags().Bool("mark-verified", false, "Mark the email of the selected users as verified")
+
 	ListUsersCmd.Flags().Int("page", 0, "Page number to fetch for the list of users")
 	ListUsersCmd.Flags().Int("per-page", 200, "Number of users to be fetched")
 	ListUsersCmd.Flags().Bool("all", false, "Fetch all users. --page flag will be ignore if provided")
+	ListUsersCmd.Flags().Int("concurrency", 4, fmt.Sprintf("Number of pages fetched at a time with --all when the number of users is known, up to %d. Use 1 to fetch them one after another", maxConcurrency))
 	ListUsersCmd.Flags().String("team", "", "If supplied, only users belonging to this team will be listed")
+	ListUsersCmd.Flags().String("not-in-team", "", "If supplied, only users not belonging to this team will be listed")
+	ListUsersCmd.Flags().Bool("without-team", false, "If supplied, only users not belonging to any team will be listed")
+	ListUsersCmd.Flags().Bool("inactive", false, "If supplied, only deactivated users will be listed")
+	ListUsersCmd.Flags().Bool("include-deleted", false, "If supplied, the deletion time of the deactivated users will be shown")
+	ListUsersCmd.Flags().Bool("deleted-only", false, "If supplied, only deactivated users will be listed, along with their deletion time")
+	ListUsersCmd.Flags().String("role", "", "If supplied, only users with this system role will be listed")
+	ListUsersCmd.Flags().String("auth-service", "", "If supplied, only users of this authentication service will be listed (ldap, saml, gitlab...)")
+	ListUsersCmd.Flags().Bool("verbose", false, "Report which filters were applied by the server and which by mmctl")
 
 	UserConvertCmd.Flags().Bool("bot", false, "If supplied, convert users to bots")
 	UserConvertCmd.Flags().Bool("user", false, "If supplied, convert a bot to a user")
@@ -334,6 +377,10 @@ Global Flags:
 {{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
 `)
 
+	UpdateUserEmailCmd.AddCommand(
+		UserEmailVerifyCmd,
+	)
+
 	UserCmd.AddCommand(
 		UserActivateCmd,
 		UserDeactivateCmd,
@@ -359,21 +406,25 @@ Global Flags:
 }
 
 func userActivateCmdF(c client.Client, command *cobra.Command, args []string) error {
-	changeUsersActiveStatus(c, args, true)
+	concurrency, err := getConcurrency(command)
+	if err != nil {
+		return err
+	}
+	changeUsersActiveStatus(c, args, true, concurrency)
 
 	return nil
 }
 
-func changeUsersActiveStatus(c client.Client, userArgs []string, active bool) {
+func changeUsersActiveStatus(c client.Client, userArgs []string, active bool, concurrency int) {
 	users, err := getUsersFromArgs(c, userArgs)
 	if err != nil {
 		printer.PrintError(err.Error())
 	}
-	for _, user := range users {
-		if err := changeUserActiveStatus(c, user, active); err != nil {
+	forEachConcurrently(len(users), concurrency, func(i int) {
+		if err := changeUserActiveStatus(c, users[i], active); err != nil {
 			printer.PrintError(err.Error())
 		}
-	}
+	})
 }
 
 func changeUserActiveStatus(c client.Client, user *model.User, activate bool) error {
@@ -388,7 +439,11 @@ func changeUserActiveStatus(c client.Client, user *model.User, activate bool) er
 }
 
 func userDeactivateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
-	changeUsersActiveStatus(c, args, false)
+	concurrency, err := getConcurrency(cmd)
+	if err != nil {
+		return err
+	}
+	changeUsersActiveStatus(c, args, false, concurrency)
 
 	return nil
 }
@@ -723,49 +778,103 @@ func listUsersCmdF(c client.Client, command *cobra.Command, args []string) error
 	if err != nil {
 		return err
 	}
+	notInTeamArg, _ := command.Flags().GetString("not-in-team")
+	withoutTeam, _ := command.Flags().GetBool("without-team")
+	inactive, _ := command.Flags().GetBool("inactive")
+	includeDeleted, _ := command.Flags().GetBool("include-deleted")
+	deletedOnly, _ := command.Flags().GetBool("deleted-only")
+	role, _ := command.Flags().GetString("role")
+	authService, _ := command.Flags().GetString("auth-service")
+	verbose, _ := command.Flags().GetBool("verbose")
+	concurrency, err := getConcurrency(command)
+	if err != nil {
+		return err
+	}
+
+	if withoutTeam && teamName != "" {
+		return errors.New("the --without-team and --team flags cannot be used together")
+	}
+	if includeDeleted && deletedOnly {
+		return errors.New("the --include-deleted and --deleted-only flags cannot be used together")
+	}
+	if deletedOnly {
+		inactive = true
+	}
 
 	if showAll {
 		page = 0
 	}
 
-	var team *model.Team
+	filter := &userListFilter{withoutTeam: withoutTeam, inactive: inactive, role: role, authService: authService}
 	if teamName != "" {
 		var err error
-		team, _, err = c.GetTeamByName(teamName, "")
+		filter.team, _, err = c.GetTeamByName(teamName, "")
 		if err != nil {
 			return errors.Wrap(err, fmt.Sprintf("Failed to get team %s", teamName))
 		}
 	}
+	if notInTeamArg != "" {
+		if filter.notInTeam = getTeamFromTeamArg(c, notInTeamArg); filter.notInTeam == nil {
+			return fmt.Errorf("unable to find team %q", notInTeamArg)
+		}
+	}
+	if err := filter.prepare(c); err != nil {
+		return err
+	}
 
+	// when filtering client side, the pages are walked through until
+	// enough users match, as a page may not contain any
+	clientSide := len(filter.clientSide()) > 0
+	fetched, matched := 0, 0
 	tpl := `{{.Id}}: {{.Username}} ({{.Email}})`
+	if includeDeleted || deletedOnly {
+		tpl += `{{if .DeleteAt}} deleted at {{timestamp .DeleteAt}}{{end}}`
+	}
+
+	// listing all the users, the pages are fetched concurrently if
+	// their number is known, and printed in order
+	var prefetched [][]*model.User
+	if showAll && concurrency > 1 {
+		if total, ok := filter.total(c); ok {
+			if prefetched, err = filter.prefetchPages(c, perPage, total, concurrency); err != nil {
+				return err
+			}
+		}
+	}
+
 	for {
 		var users []*model.User
-		var err error
-		if team != nil {
-			users, _, err = c.GetUsersInTeam(team.Id, page, perPage, "")
-			if err != nil {
-				return errors.Wrap(err, fmt.Sprintf("Failed to fetch users for team %s", teamName))
-			}
-		} else {
-			users, _, err = c.GetUsers(page, perPage, "")
-			if err != nil {
-				return errors.Wrap(err, "Failed to fetch users")
-			}
+		if page < len(prefetched) {
+			users = prefetched[page]
+		} else if users, err = filter.getPage(c, page, perPage); err != nil {
+			return err
 		}
 		if len(users) == 0 {
 			break
 		}
 
+		fetched += len(users)
 		for _, user := range users {
+			if !filter.matches(user) {
+				continue
+			}
+			matched++
 			printer.PrintT(tpl, user)
+			if clientSide && !showAll && matched == perPage {
+				break
+			}
 		}
 
-		if !showAll {
+		if !showAll && (!clientSide || matched == perPage || len(users) < perPage) {
 			break
 		}
 		page++
 	}
 
+	if verbose {
+		printUserListFilterReport(filter, fetched, matched)
+	}
+
 	return nil
 }
 
@@ -785,6 +894,65 @@ func verifyUserEmailWithoutTokenCmdF(c client.Client, cmd *cobra.Command, userAr
 	return nil
 }
 
+type emailVerificationCoverage struct {
+	Total    int     `json:"total"`
+	Verified int     `json:"verified"`
+	Failed   int     `json:"failed"`
+	Coverage float64 `json:"coverage"`
+}
+
+func userEmailVerifyCmdF(c client.Client, cmd *cobra.Command, userArgs []string) error {
+	resend, _ := cmd.Flags().GetBool("resend")
+	markVerified, _ := cmd.Flags().GetBool("mark-verified")
+	if resend && markVerified {
+		return errors.New("the --resend and --mark-verified flags cannot be used together")
+	}
+
+	users, unresolved, err := getBulkUserTargets(c, cmd, userArgs)
+	if err != nil {
+		return err
+	}
+
+	coverage := emailVerificationCoverage{Total: len(users), Failed: unresolved}
+	for _, user := range users {
+		if user.EmailVerified {
+			coverage.Verified++
+			continue
+		}
+
+		switch {
+		case markVerified:
+			newUser, _, err := c.VerifyUserEmailWithoutToken(user.Id)
+			if err != nil {
+				printer.PrintError(fmt.Sprintf("unable to verify user %s email: %s", user.Id, err))
+				coverage.Failed++
+				continue
+			}
+			coverage.Verified++
+			printer.PrintT("User {{.Username}} verified", newUser)
+		case resend:
+			if _, err := c.SendVerificationEmail(user.Email); err != nil {
+				printer.PrintError(fmt.Sprintf("unable to send verification email to user %s: %s", user.Id, err))
+				coverage.Failed++
+				continue
+			}
+			printer.PrintT("Verification email sent to {{.Username}} ({{.Email}})", user)
+		default:
+			printer.PrintT("User {{.Username}} ({{.Email}}) has not verified their email", user)
+		}
+	}
+
+	if coverage.Total > 0 {
+		coverage.Coverage = float64(coverage.Verified) * 100 / float64(coverage.Total)
+	}
+	printer.PrintT(`{{.Verified}} of {{.Total}} users have a verified email ({{printf "%.1f" .Coverage}}%), {{.Failed}} failed`, coverage)
+
+	if coverage.Failed > 0 {
+		return fmt.Errorf("unable to verify or send the verification email to %d users", coverage.Failed)
+	}
+	return nil
+}
+
 func userConvertCmdF(c client.Client, cmd *cobra.Command, userArgs []string) error {
 	toBot, _ := cmd.Flags().GetBool("bot")
 	toUser, _ := cmd.Flags().GetBool("user")
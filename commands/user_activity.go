@@ -0,0 +1,211 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var UserActivityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Reports on the activity of users",
+}
+
+var UserActivityTimelineCmd = &cobra.Command{
+	Use:   "timeline [user]",
+	Short: "Show the activity timeline of a user",
+	Long: `Show the activity of a user during a time range, day by day and channel by channel: the posts they created, the files they attached to them and the reactions they added. Only counts are shown, never the content of the messages.
+The activity of a user is personal data, so only system admins can get it, and it should only be requested and shared according to the privacy policy of the organization. The direct and group messages are left out unless --include-direct is given. The days are in UTC, and the reactions are the ones added to the posts of the time range.`,
+	Example: `  user activity timeline john.doe --since 2026-01-01T00:00:00+00:00
+  user activity timeline john.doe --since 2026-01-01T00:00:00+00:00 --until 2026-02-01T00:00:00+00:00 --include-direct --confirm`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(userActivityTimelineCmdF),
+}
+
+func init() {
+	UserActivityTimelineCmd.Flags().String("since", "", "Start of the time range, in ISO 8601 format")
+	_ = UserActivityTimelineCmd.MarkFlagRequired("since")
+	UserActivityTimelineCmd.Flags().String("until", "", "End of the time range, in ISO 8601 format. Defaults to now")
+	UserActivityTimelineCmd.Flags().Bool("include-direct", false, "Include the direct and group messages of the user")
+	UserActivityTimelineCmd.Flags().Bool("confirm", false, "Confirm you are allowed to get the activity of the user")
+
+	UserActivityCmd.AddCommand(UserActivityTimelineCmd)
+	UserCmd.AddCommand(UserActivityCmd)
+}
+
+const userActivityDayLayout = "2006-01-02"
+
+type userActivityDay struct {
+	Day       string `json:"day"`
+	Channel   string `json:"channel"`
+	ChannelID string `json:"channel_id"`
+	Posts     int    `json:"posts"`
+	Files     int    `json:"files"`
+	Reactions int    `json:"reactions"`
+}
+
+// checkSystemAdmin fails unless the current user is a system admin.
+// Local mode has no user and is allowed
+func checkSystemAdmin(c client.Client) error {
+	if viper.GetBool("local") {
+		return nil
+	}
+	me, _, err := c.GetMe("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the current user")
+	}
+	if !me.IsSystemAdmin() {
+		return errors.New("only system admins can get the activity of users")
+	}
+	return nil
+}
+
+// userActivityChannelName names a channel as team:channel, or after
+// the other user for direct messages
+func userActivityChannelName(c client.Client, channel *model.Channel, userID string, teams map[string]string) string {
+	switch channel.Type {
+	case model.ChannelTypeDirect:
+		if other, _, err := c.GetUser(channel.GetOtherUserIdForDM(userID), ""); err == nil {
+			return "@" + other.Username
+		}
+		return channel.Name
+	case model.ChannelTypeGroup:
+		return channel.DisplayName
+	}
+
+	if _, ok := teams[channel.TeamId]; !ok {
+		teams[channel.TeamId] = channel.TeamId
+		if team, _, err := c.GetTeam(channel.TeamId, ""); err == nil {
+			teams[channel.TeamId] = team.Name
+		}
+	}
+	return teams[channel.TeamId] + ":" + channel.Name
+}
+
+// getUserChannelActivity counts the posts, files and reactions of the
+// user in a channel for each day of the range of the filter
+func getUserChannelActivity(c client.Client, channel *model.Channel, userID string, filter *postListFilter) (map[string]*userActivityDay, error) {
+	posts, err := getFilteredPosts(c, channel.Id, filter, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	days := map[string]*userActivityDay{}
+	dayOf := func(millis int64) *userActivityDay {
+		day := model.GetTimeForMillis(millis).UTC().Format(userActivityDayLayout)
+		if _, ok := days[day]; !ok {
+			days[day] = &userActivityDay{Day: day, ChannelID: channel.Id}
+		}
+		return days[day]
+	}
+
+	for _, post := range posts {
+		if post.UserId == userID && !post.IsSystemMessage() {
+			activity := dayOf(post.CreateAt)
+			activity.Posts++
+			activity.Files += len(post.FileIds)
+		}
+		if post.Metadata == nil {
+			continue
+		}
+		for _, reaction := range post.Metadata.Reactions {
+			if reaction.UserId != userID || reaction.CreateAt < filter.since || (filter.until > 0 && reaction.CreateAt > filter.until) {
+				continue
+			}
+			dayOf(reaction.CreateAt).Reactions++
+		}
+	}
+	return days, nil
+}
+
+func userActivityTimelineCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	includeDirect, _ := cmd.Flags().GetBool("include-direct")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	filter := &postListFilter{}
+	var err error
+	if filter.since, err = parsePostListTime("since", since); err != nil {
+		return err
+	}
+	if filter.since == 0 {
+		return errors.New("the --since flag is required")
+	}
+	if filter.until, err = parsePostListTime("until", until); err != nil {
+		return err
+	}
+
+	if err = checkSystemAdmin(c); err != nil {
+		return err
+	}
+
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	printer.PrintWarning("The activity of a user is personal data, only request and share it according to the privacy policy of your organization")
+	if !confirmFlag {
+		if err = getConfirmation(fmt.Sprintf("Are you sure you want to get the activity of %s?", user.Username), false); err != nil {
+			return err
+		}
+	}
+
+	channels, _, err := c.GetChannelsForUserWithLastDeleteAt(user.Id, 0)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the channels of user %s", user.Username)
+	}
+
+	timeline := []*userActivityDay{}
+	teams := map[string]string{}
+	failed := 0
+	for _, channel := range channels {
+		if channel.IsGroupOrDirect() && !includeDirect {
+			continue
+		}
+		days, err := getUserChannelActivity(c, channel, user.Id, filter)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to get the activity in channel %q: %s", channel.Name, err))
+			failed++
+			continue
+		}
+		if len(days) == 0 {
+			continue
+		}
+		name := userActivityChannelName(c, channel, user.Id, teams)
+		for _, day := range days {
+			day.Channel = name
+			timeline = append(timeline, day)
+		}
+	}
+
+	sort.Slice(timeline, func(i, j int) bool {
+		if timeline[i].Day != timeline[j].Day {
+			return timeline[i].Day < timeline[j].Day
+		}
+		return timeline[i].Channel < timeline[j].Channel
+	})
+
+	for _, day := range timeline {
+		printer.PrintT("{{.Day}} {{.Channel}}: {{.Posts}} posts, {{.Files}} files, {{.Reactions}} reactions", day)
+	}
+	if len(timeline) == 0 {
+		printer.Print(fmt.Sprintf("No activity of %s since %s", user.Username, model.GetTimeForMillis(filter.since).UTC().Format(userActivityDayLayout)))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to get the activity in %d channels", failed)
+	}
+	return nil
+}
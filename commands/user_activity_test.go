@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserActivityTimelineCmd() {
+	newCmd := func(includeDirect bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("since", "2026-01-01T00:00:00+00:00", "")
+		cmd.Flags().String("until", "", "")
+		cmd.Flags().Bool("include-direct", includeDirect, "")
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	millis := func(value string) int64 {
+		t, err := time.Parse(time.RFC3339, value)
+		s.Require().NoError(err)
+		return model.GetMillisForTime(t)
+	}
+
+	admin := &model.User{Id: model.NewId(), Username: "admin", Roles: "system_admin system_user"}
+	user := &model.User{Id: model.NewId(), Username: "john.doe", Email: "john.doe@example.com"}
+	other := &model.User{Id: model.NewId(), Username: "jane.doe"}
+	team := &model.Team{Id: model.NewId(), Name: "team"}
+	channel := &model.Channel{Id: model.NewId(), TeamId: team.Id, Name: "town-square", Type: model.ChannelTypeOpen}
+	direct := &model.Channel{Id: model.NewId(), Name: model.GetDMNameFromIds(user.Id, other.Id), Type: model.ChannelTypeDirect}
+
+	s.Run("Should count the posts, files and reactions of each day", func() {
+		printer.Clean()
+		postList := model.NewPostList()
+		for _, post := range []*model.Post{
+			{Id: model.NewId(), UserId: user.Id, ChannelId: channel.Id, CreateAt: millis("2026-01-03T09:00:00Z"), FileIds: model.StringArray{model.NewId(), model.NewId()}},
+			{Id: model.NewId(), UserId: other.Id, ChannelId: channel.Id, CreateAt: millis("2026-01-02T18:00:00Z"), Metadata: &model.PostMetadata{
+				Reactions: []*model.Reaction{{UserId: user.Id, EmojiName: "smile", CreateAt: millis("2026-01-03T08:00:00Z")}},
+			}},
+			{Id: model.NewId(), UserId: user.Id, ChannelId: channel.Id, CreateAt: millis("2026-01-02T10:00:00Z")},
+			{Id: model.NewId(), UserId: user.Id, ChannelId: channel.Id, CreateAt: millis("2026-01-02T09:00:00Z"), Type: model.PostTypeJoinChannel},
+			{Id: model.NewId(), UserId: user.Id, ChannelId: channel.Id, CreateAt: millis("2025-12-31T10:00:00Z")},
+		} {
+			postList.AddPost(post)
+			postList.AddOrder(post.Id)
+		}
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(admin, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelsForUserWithLastDeleteAt(user.Id, 0).
+			Return([]*model.Channel{channel, direct}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPostsForChannel(channel.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		err := userActivityTimelineCmdF(s.client, newCmd(false), []string{user.Email})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&userActivityDay{Day: "2026-01-02", Channel: "team:town-square", ChannelID: channel.Id, Posts: 1},
+			&userActivityDay{Day: "2026-01-03", Channel: "team:town-square", ChannelID: channel.Id, Posts: 1, Files: 2, Reactions: 1},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should include the direct messages if asked", func() {
+		printer.Clean()
+		postList := model.NewPostList()
+		post := &model.Post{Id: model.NewId(), UserId: user.Id, ChannelId: direct.Id, CreateAt: millis("2026-01-05T12:00:00Z")}
+		postList.AddPost(post)
+		postList.AddOrder(post.Id)
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(admin, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelsForUserWithLastDeleteAt(user.Id, 0).
+			Return([]*model.Channel{direct}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetPostsForChannel(direct.Id, 0, APILimitMaximum, "", false).
+			Return(postList, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUser(other.Id, "").
+			Return(other, &model.Response{}, nil).
+			Times(1)
+
+		err := userActivityTimelineCmdF(s.client, newCmd(true), []string{user.Email})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&userActivityDay{Day: "2026-01-05", Channel: "@jane.doe", ChannelID: direct.Id, Posts: 1},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail if the current user is not a system admin", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetMe("").
+			Return(other, &model.Response{}, nil).
+			Times(1)
+
+		err := userActivityTimelineCmdF(s.client, newCmd(false), []string{user.Email})
+		s.Require().EqualError(err, "only system admins can get the activity of users")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/spf13/cobra"
+)
+
+var UserAvatarInitialsCmd = &cobra.Command{
+	Use:   "avatar-initials",
+	Short: "Management of the default avatars of users",
+}
+
+var UserAvatarInitialsRegenerateCmd = &cobra.Command{
+	Use:   "regenerate [users]",
+	Short: "Regenerate default avatars",
+	Long: `Regenerate the default avatar, generated from the initials of the user, of users without a custom profile picture. This is useful after changing the names of users, as the server doesn't update the generated avatars.
+Users with a custom profile picture are skipped. Users can be specified as arguments, or all active users can be selected with the --bulk flag.`,
+	Example: `  user avatar-initials regenerate user1 user2@example.com
+  user avatar-initials regenerate --bulk --team myteam`,
+	RunE: withClient(userAvatarInitialsRegenerateCmdF),
+}
+
+func init() {
+	UserAvatarInitialsRegenerateCmd.Flags().Bool("bulk", false, "Select all active users instead of the users provided as arguments")
+	UserAvatarInitialsRegenerateCmd.Flags().String("team", "", "If supplied along with --bulk, only users belonging to this team will be selected")
+
+	UserAvatarInitialsCmd.AddCommand(UserAvatarInitialsRegenerateCmd)
+	UserCmd.AddCommand(UserAvatarInitialsCmd)
+}
+
+func userAvatarInitialsRegenerateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	users, unresolved, err := getBulkUserTargets(c, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	summary := struct {
+		Regenerated int `json:"regenerated"`
+		Skipped     int `json:"skipped"`
+		Failed      int `json:"failed"`
+	}{Failed: unresolved}
+	for _, user := range users {
+		// a positive last picture update means that the user uploaded
+		// a custom picture, default avatars have it zero or negative
+		if user.LastPictureUpdate > 0 {
+			summary.Skipped++
+			continue
+		}
+
+		if _, err := c.SetDefaultProfileImage(user.Id); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to regenerate the avatar of user %s: %s", user.Username, err))
+			summary.Failed++
+			continue
+		}
+
+		summary.Regenerated++
+		printer.PrintT("Avatar of {{.Username}} regenerated", user)
+	}
+
+	printer.PrintT("{{.Regenerated}} avatars regenerated, {{.Skipped}} users with a custom picture skipped, {{.Failed}} failed", summary)
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("unable to regenerate the avatar of %d users", summary.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserAvatarInitialsRegenerateCmd() {
+	newCmd := func(bulk bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", bulk, "")
+		cmd.Flags().String("team", "", "")
+		return cmd
+	}
+
+	s.Run("Should regenerate the avatars of the users without a custom picture and report the failures", func() {
+		printer.Clean()
+		user1 := &model.User{Id: model.NewId(), Username: "user1"}
+		user2 := &model.User{Id: model.NewId(), Username: "user2", LastPictureUpdate: model.GetMillis()}
+		user3 := &model.User{Id: model.NewId(), Username: "user3", LastPictureUpdate: -model.GetMillis()}
+		user4 := &model.User{Id: model.NewId(), Username: "user4"}
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{user1, user2, user3, user4}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetDefaultProfileImage(user1.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetDefaultProfileImage(user3.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SetDefaultProfileImage(user4.Id).
+			Return(nil, errors.New("mock error")).
+			Times(1)
+
+		err := userAvatarInitialsRegenerateCmdF(s.client, newCmd(true), []string{})
+		s.Require().EqualError(err, "unable to regenerate the avatar of 1 users")
+		s.Require().Equal([]interface{}{"unable to regenerate the avatar of user user4: mock error"}, printer.GetErrorLines())
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal(user1, printer.GetLines()[0])
+		s.Require().Equal(user3, printer.GetLines()[1])
+	})
+
+	s.Run("Should fail without users nor --bulk", func() {
+		printer.Clean()
+
+		err := userAvatarInitialsRegenerateCmdF(s.client, newCmd(false), []string{})
+		s.Require().EqualError(err, "expected at least one user or the --bulk flag. See help text for details")
+	})
+}
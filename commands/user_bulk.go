@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// getBulkUserTargets returns the users given as arguments, or all the
+// active users when the --bulk flag is set, optionally only the ones of
+// the --team team and created after the --created-after time for the
+// commands that have those flags. The users given as arguments that
+// can't be found are printed as errors and returned as unresolved, for
+// the commands to count them as failures
+func getBulkUserTargets(c client.Client, cmd *cobra.Command, userArgs []string) ([]*model.User, int, error) {
+	bulk, _ := cmd.Flags().GetBool("bulk")
+	teamArg, _ := cmd.Flags().GetString("team")
+	createdAfterArg, _ := cmd.Flags().GetString("created-after")
+
+	if !bulk {
+		if teamArg != "" {
+			return nil, 0, errors.New("the --team flag can only be used along with --bulk")
+		}
+		if createdAfterArg != "" {
+			return nil, 0, errors.New("the --created-after flag can only be used along with --bulk")
+		}
+		if len(userArgs) == 0 {
+			return nil, 0, errors.New("expected at least one user or the --bulk flag. See help text for details")
+		}
+		users, err := getUsersFromArgs(c, userArgs)
+		var lookupErrs *multierror.Error
+		if errors.As(err, &lookupErrs) {
+			for _, lookupErr := range lookupErrs.Errors {
+				printer.PrintError(lookupErr.Error())
+			}
+			return users, len(lookupErrs.Errors), nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return users, 0, nil
+	}
+
+	if len(userArgs) > 0 {
+		return nil, 0, errors.New("users cannot be provided as arguments along with the --bulk flag")
+	}
+
+	createdAfter, err := parsePostListTime("created-after", createdAfterArg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var team *model.Team
+	if teamArg != "" {
+		team = getTeamFromTeamArg(c, teamArg)
+		if team == nil {
+			return nil, 0, fmt.Errorf("unable to find team %q", teamArg)
+		}
+	}
+
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		var usersPage []*model.User
+		var err error
+		if team != nil {
+			usersPage, _, err = c.GetUsersInTeam(team.Id, page, APILimitMaximum, "")
+		} else {
+			usersPage, _, err = c.GetUsers(page, APILimitMaximum, "")
+		}
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "Failed to fetch users")
+		}
+
+		for _, user := range usersPage {
+			if user.IsBot || user.DeleteAt != 0 || (createdAfter > 0 && user.CreateAt <= createdAfter) {
+				continue
+			}
+			users = append(users, user)
+		}
+
+		if len(usersPage) < APILimitMaximum {
+			break
+		}
+	}
+
+	return users, 0, nil
+}
+
+// userUpdateReport counts the users of a bulk update by their result,
+// for the commands that report the changes of each user and a summary
+// at the end
+type userUpdateReport struct {
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+// add counts a user with the number of changes made to it
+func (r *userUpdateReport) add(changes int) {
+	if changes == 0 {
+		r.Unchanged++
+		return
+	}
+	r.Updated++
+}
+
+// fail counts a user that couldn't be updated, printing the reason
+func (r *userUpdateReport) fail(message string) {
+	printer.PrintError(message)
+	r.Failed++
+}
+
+// finish prints the summary of the update, failing if any user couldn't
+// be updated
+func (r *userUpdateReport) finish(dryRun bool) error {
+	if dryRun {
+		printer.PrintT("{{.Updated}} users would be updated, {{.Unchanged}} unchanged, {{.Failed}} failed", r)
+	} else {
+		printer.PrintT("{{.Updated}} users updated, {{.Unchanged}} unchanged, {{.Failed}} failed", r)
+	}
+
+	if r.Failed > 0 {
+		return fmt.Errorf("unable to update %d users", r.Failed)
+	}
+	return nil
+}
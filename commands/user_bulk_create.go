@@ -0,0 +1,328 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var UserBulkCreateCmd = &cobra.Command{
+	Use:   "bulk-create [file]",
+	Short: "Create users in bulk from a CSV or JSON file",
+	Long: `Create the users of a CSV or JSON file, adding them to their teams and channels. Files with the ".json" extension are read as an array of users, and any other file as CSV with a header row.
+The supported fields are email, username, password, nickname, first_name, last_name, auth_service, auth_data, teams and channels. The email and username are required. Users with no auth_service, or the "email" one, require a password, and users of other auth services, like ldap or saml, require the auth_data that identifies them in the service.
+In CSV files the teams and channels are separated by semicolons, and in JSON files they are arrays. The channels are given as team:channel, and the user must be a member of their team.
+The creation stops at the first failed user unless --continue-on-error is set, in which case the failed rows are reported and the remaining users are created.`,
+	Example: `  user bulk-create users.csv
+  user bulk-create users.json --continue-on-error`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(userBulkCreateCmdF),
+}
+
+func init() {
+	UserBulkCreateCmd.Flags().Bool("continue-on-error", false, "Keep creating the remaining users when a user fails")
+
+	UserCmd.AddCommand(UserBulkCreateCmd)
+}
+
+var bulkUserAuthServices = []string{
+	model.UserAuthServiceEmail,
+	model.UserAuthServiceLdap,
+	model.UserAuthServiceSaml,
+	model.UserAuthServiceGitlab,
+	model.ServiceGoogle,
+	model.ServiceOffice365,
+	model.ServiceOpenid,
+}
+
+type bulkUser struct {
+	Email       string   `json:"email"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	Nickname    string   `json:"nickname"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	AuthService string   `json:"auth_service"`
+	AuthData    string   `json:"auth_data"`
+	Teams       []string `json:"teams"`
+	Channels    []string `json:"channels"`
+}
+
+type bulkCreatedUser struct {
+	Row      int      `json:"row"`
+	ID       string   `json:"id"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Teams    []string `json:"teams"`
+	Channels []string `json:"channels"`
+}
+
+func (u *bulkUser) validate() error {
+	if !model.IsValidEmail(u.Email) {
+		return fmt.Errorf("invalid email %q", u.Email)
+	}
+	if !model.IsValidUsername(u.Username) {
+		return fmt.Errorf("invalid username %q", u.Username)
+	}
+
+	switch u.AuthService {
+	case "", model.UserAuthServiceEmail:
+		if u.Password == "" {
+			return errors.New("the password is required for email authentication")
+		}
+		if u.AuthData != "" {
+			return errors.New("the auth_data can't be set for email authentication")
+		}
+	default:
+		found := false
+		for _, service := range bulkUserAuthServices {
+			found = found || service == u.AuthService
+		}
+		if !found {
+			return fmt.Errorf("unknown auth_service %q, the supported ones are %s", u.AuthService, strings.Join(bulkUserAuthServices, ", "))
+		}
+		if u.AuthData == "" {
+			return fmt.Errorf("the auth_data is required for %s authentication", u.AuthService)
+		}
+		if u.Password != "" {
+			return fmt.Errorf("the password can't be set for %s authentication", u.AuthService)
+		}
+	}
+
+	return nil
+}
+
+func (u *bulkUser) toModel() *model.User {
+	user := &model.User{
+		Email:     u.Email,
+		Username:  u.Username,
+		Password:  u.Password,
+		Nickname:  u.Nickname,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+	}
+	if u.AuthService != "" && u.AuthService != model.UserAuthServiceEmail {
+		user.AuthService = u.AuthService
+		user.AuthData = model.NewString(u.AuthData)
+	}
+	return user
+}
+
+func splitBulkUserList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func readBulkUsersCSV(r io.Reader) ([]*bulkUser, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the CSV header")
+	}
+
+	setters := map[string]func(u *bulkUser, value string){
+		"email":        func(u *bulkUser, value string) { u.Email = value },
+		"username":     func(u *bulkUser, value string) { u.Username = value },
+		"password":     func(u *bulkUser, value string) { u.Password = value },
+		"nickname":     func(u *bulkUser, value string) { u.Nickname = value },
+		"first_name":   func(u *bulkUser, value string) { u.FirstName = value },
+		"last_name":    func(u *bulkUser, value string) { u.LastName = value },
+		"auth_service": func(u *bulkUser, value string) { u.AuthService = value },
+		"auth_data":    func(u *bulkUser, value string) { u.AuthData = value },
+		"teams":        func(u *bulkUser, value string) { u.Teams = splitBulkUserList(value) },
+		"channels":     func(u *bulkUser, value string) { u.Channels = splitBulkUserList(value) },
+	}
+	columns := make([]func(u *bulkUser, value string), len(header))
+	found := map[string]bool{}
+	for i, column := range header {
+		name := strings.ToLower(strings.TrimSpace(column))
+		if setters[name] == nil {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		columns[i] = setters[name]
+		found[name] = true
+	}
+	if !found["email"] || !found["username"] {
+		return nil, errors.New("the CSV file must have the email and username columns")
+	}
+
+	users := []*bulkUser{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return users, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the CSV file")
+		}
+
+		user := &bulkUser{}
+		for i, value := range record {
+			columns[i](user, strings.TrimSpace(value))
+		}
+		users = append(users, user)
+	}
+}
+
+func readBulkUsers(path string) ([]*bulkUser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the users file")
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(path)) != ".json" {
+		return readBulkUsersCSV(f)
+	}
+
+	var users []*bulkUser
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&users); err != nil {
+		return nil, errors.Wrap(err, "failed to read the JSON file")
+	}
+	return users, nil
+}
+
+// bulkUserCreator creates the users, caching the teams and channels
+// shared by many of them
+type bulkUserCreator struct {
+	c        client.Client
+	teams    map[string]*model.Team
+	channels map[string]*model.Channel
+}
+
+func (b *bulkUserCreator) getTeam(teamArg string) (*model.Team, error) {
+	if team, ok := b.teams[teamArg]; ok {
+		return team, nil
+	}
+	team := getTeamFromTeamArg(b.c, teamArg)
+	if team == nil {
+		return nil, fmt.Errorf("unable to find team %q", teamArg)
+	}
+	b.teams[teamArg] = team
+	return team, nil
+}
+
+func (b *bulkUserCreator) getChannel(channelArg string) (*model.Channel, error) {
+	if channel, ok := b.channels[channelArg]; ok {
+		return channel, nil
+	}
+	channel := getChannelFromChannelArg(b.c, channelArg)
+	if channel == nil {
+		return nil, fmt.Errorf("unable to find channel %q", channelArg)
+	}
+	b.channels[channelArg] = channel
+	return channel, nil
+}
+
+func (b *bulkUserCreator) create(row int, u *bulkUser) (*bulkCreatedUser, error) {
+	if err := u.validate(); err != nil {
+		return nil, err
+	}
+
+	// the teams and channels are resolved before creating the user, so
+	// it isn't created without its memberships because of a typo
+	teams := make([]*model.Team, 0, len(u.Teams))
+	for _, teamArg := range u.Teams {
+		team, err := b.getTeam(teamArg)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	channels := make([]*model.Channel, 0, len(u.Channels))
+	for _, channelArg := range u.Channels {
+		channel, err := b.getChannel(channelArg)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	user, _, err := b.c.CreateUser(u.toModel())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create user")
+	}
+
+	for i, team := range teams {
+		if _, _, err := b.c.AddTeamMember(team.Id, user.Id); err != nil {
+			return nil, errors.Wrapf(err, "user created but unable to add it to team %q", u.Teams[i])
+		}
+	}
+	for i, channel := range channels {
+		if _, _, err := b.c.AddChannelMember(channel.Id, user.Id); err != nil {
+			return nil, errors.Wrapf(err, "user created but unable to add it to channel %q", u.Channels[i])
+		}
+	}
+
+	return &bulkCreatedUser{
+		Row:      row,
+		ID:       user.Id,
+		Username: user.Username,
+		Email:    user.Email,
+		Teams:    u.Teams,
+		Channels: u.Channels,
+	}, nil
+}
+
+func userBulkCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+
+	users, err := readBulkUsers(args[0])
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return errors.New("the file has no users")
+	}
+
+	// the rows are numbered as in the file, after the header of CSV files
+	firstRow := 2
+	if strings.ToLower(filepath.Ext(args[0])) == ".json" {
+		firstRow = 1
+	}
+
+	creator := &bulkUserCreator{c: c, teams: map[string]*model.Team{}, channels: map[string]*model.Channel{}}
+	created, failed := 0, 0
+	for i, u := range users {
+		row := firstRow + i
+		result, err := creator.create(row, u)
+		if err != nil {
+			if !continueOnError {
+				return fmt.Errorf("row %d: %s: %w", row, u.Username, err)
+			}
+			printer.PrintError(fmt.Sprintf("row %d: %s: %s", row, u.Username, err))
+			failed++
+			continue
+		}
+		created++
+		printer.PrintT("Created user {{.Username}}", result)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("created %d users, unable to create %d users", created, failed)
+	}
+
+	return nil
+}
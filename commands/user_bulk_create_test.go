@@ -0,0 +1,133 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserBulkCreateCmd() {
+	newCmd := func(continueOnError bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("continue-on-error", continueOnError, "")
+		return cmd
+	}
+
+	writeFile := func(dir, name, content string) string {
+		path := filepath.Join(dir, name)
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	team := &model.Team{Id: "team-id", Name: "myteam"}
+	channel := &model.Channel{Id: "channel-id", Name: "town-square", TeamId: team.Id}
+
+	s.Run("Should create the users of a CSV file reporting the failed rows", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeFile(tmp, "users.csv", `email,username,password,nickname,auth_service,auth_data,teams,channels
+john@example.com,john,Password1,Johnny,,,team-id,team-id:town-square
+invalid,jane,Password1,,,,,
+ldap@example.com,ldapuser,,,ldap,ldap-id,team-id,
+`)
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(2)
+
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channel.Name, team.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateUser(&model.User{Email: "john@example.com", Username: "john", Password: "Password1", Nickname: "Johnny"}).
+			Return(&model.User{Id: "john-id", Email: "john@example.com", Username: "john"}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateUser(&model.User{Email: "ldap@example.com", Username: "ldapuser", AuthService: "ldap", AuthData: model.NewString("ldap-id")}).
+			Return(&model.User{Id: "ldap-user-id", Email: "ldap@example.com", Username: "ldapuser"}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddTeamMember(team.Id, "john-id").
+			Return(&model.TeamMember{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddChannelMember(channel.Id, "john-id").
+			Return(&model.ChannelMember{}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddTeamMember(team.Id, "ldap-user-id").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := userBulkCreateCmdF(s.client, newCmd(true), []string{path})
+		s.Require().EqualError(err, "created 1 users, unable to create 2 users")
+		s.Require().Equal([]interface{}{
+			&bulkCreatedUser{Row: 2, ID: "john-id", Username: "john", Email: "john@example.com", Teams: []string{"team-id"}, Channels: []string{"team-id:town-square"}},
+		}, printer.GetLines())
+		s.Require().Equal([]interface{}{
+			`row 3: jane: invalid email "invalid"`,
+			`row 4: ldapuser: user created but unable to add it to team "team-id": mock error`,
+		}, printer.GetErrorLines())
+	})
+
+	s.Run("Should stop at the first failed user of a JSON file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeFile(tmp, "users.json", `[
+  {"email": "john@example.com", "username": "john", "password": "Password1", "teams": ["unknown"]},
+  {"email": "jane@example.com", "username": "jane", "password": "Password1"}
+]`)
+
+		s.client.
+			EXPECT().
+			GetTeam("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName("unknown", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := userBulkCreateCmdF(s.client, newCmd(false), []string{path})
+		s.Require().EqualError(err, `row 1: john: unable to find team "unknown"`)
+		s.Require().Empty(printer.GetLines())
+	})
+
+	s.Run("Should fail with an unknown CSV column", func() {
+		_, err := readBulkUsersCSV(strings.NewReader("email,username,roles\n"))
+		s.Require().EqualError(err, `unknown column "roles"`)
+	})
+
+	s.Run("Should require the auth data for other auth services", func() {
+		user := &bulkUser{Email: "john@example.com", Username: "john", AuthService: "saml"}
+		s.Require().EqualError(user.validate(), "the auth_data is required for saml authentication")
+	})
+}
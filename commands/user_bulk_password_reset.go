@@ -0,0 +1,302 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var BulkPasswordResetCmd = &cobra.Command{
+	Use:   "bulk-password-reset [users]",
+	Short: "Send password reset emails to a set of users",
+	Long: `Send a password reset email to each user of a set, and with --force-logout revoke all their sessions before, so they have to log in again. Revoking the sessions doesn't change the passwords: the users can still log in with their current password until they reset it.
+The users can be given as arguments and, with --file, read from a file containing one user per line. Without them, all the active users are selected. The --domain and --role flags narrow the selection down to the users with an email in the domain or with the role. Bots, deactivated users and users that log in with an authentication service other than email are skipped, as they have no password to reset.
+With --plan, the selected users are printed as a plan that can be reviewed and then applied with --apply-plan, along with --force-logout if their sessions have to be revoked.`,
+	Example: `  # check which users would be selected
+  user bulk-password-reset --domain example.com --dry-run
+
+  user bulk-password-reset --domain example.com --force-logout --confirm
+  user bulk-password-reset --file compromised-users.txt --force-logout --concurrency 4 --confirm
+
+  # review the users before resetting their password
+  user bulk-password-reset --domain example.com --plan > plan.json
+  user bulk-password-reset --apply-plan plan.json --force-logout --confirm`,
+	RunE: withClient(withPlanActions(bulkPasswordResetPlanResolver, applyBulkPasswordResetPlan, bulkPasswordResetCmdF)),
+}
+
+func init() {
+	BulkPasswordResetCmd.Flags().String("file", "", "File containing the users, one per line")
+	BulkPasswordResetCmd.Flags().String("domain", "", "Only select the users with an email in this domain")
+	BulkPasswordResetCmd.Flags().String("role", "", "Only select the users with this role")
+	BulkPasswordResetCmd.Flags().Bool("force-logout", false, "Revoke all the sessions of the users")
+	BulkPasswordResetCmd.Flags().Bool("dry-run", false, "List the users that would be selected without sending the emails")
+	BulkPasswordResetCmd.Flags().Bool("confirm", false, "Confirm you really want to reset the password of the users")
+	addConcurrencyFlag(BulkPasswordResetCmd, "users")
+	addPlanFlags(BulkPasswordResetCmd)
+
+	UserCmd.AddCommand(BulkPasswordResetCmd)
+}
+
+const planActionResetPassword = "reset_password"
+
+const (
+	passwordResetSent        = "reset email sent"
+	passwordResetWouldBeSent = "reset email would be sent"
+)
+
+type passwordResetResult struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Result   string `json:"result"`
+}
+
+// readUsersFile returns the users of a file with one per line,
+// skipping the empty lines. They are read from the standard input if
+// the path is "-"
+func readUsersFile(path string) ([]string, error) {
+	r := stdinArgsReader
+	if path != stdinArg {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open the users file")
+		}
+		defer f.Close()
+		r = f
+	}
+
+	users := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			users = append(users, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the users file")
+	}
+	return users, nil
+}
+
+// canResetPassword reports whether the password of the user can be
+// reset by email, which is not the case for the users without one
+func canResetPassword(user *model.User) bool {
+	return !user.IsBot && user.DeleteAt == 0 && (user.AuthService == "" || user.AuthService == model.UserAuthServiceEmail)
+}
+
+func hasEmailDomain(user *model.User, domain string) bool {
+	return strings.HasSuffix(strings.ToLower(user.Email), "@"+strings.ToLower(strings.TrimPrefix(domain, "@")))
+}
+
+func hasRole(user *model.User, role string) bool {
+	for _, r := range strings.Fields(user.Roles) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// getPasswordResetUsers returns the users given as arguments or in the
+// file, or all the users with the role if none is given, keeping the
+// ones with an email in the domain and a password to reset
+func getPasswordResetUsers(c client.Client, userArgs []string, domain, role string) ([]*model.User, error) {
+	var candidates []*model.User
+	if len(userArgs) > 0 {
+		users, err := getUsersFromArgs(c, userArgs)
+		if err != nil {
+			return nil, err
+		}
+		candidates = users
+	} else {
+		filter := &userListFilter{role: role}
+		for page := 0; ; page++ {
+			pageUsers, err := filter.getPage(c, page, APILimitMaximum)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, pageUsers...)
+			if len(pageUsers) < APILimitMaximum {
+				break
+			}
+		}
+	}
+
+	users := []*model.User{}
+	for _, user := range candidates {
+		if !canResetPassword(user) {
+			continue
+		}
+		if domain != "" && !hasEmailDomain(user, domain) {
+			continue
+		}
+		if role != "" && !hasRole(user, role) {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func resetUserPassword(c client.Client, user *model.User, forceLogout bool) error {
+	if forceLogout {
+		if _, err := c.RevokeAllSessions(user.Id); err != nil {
+			return fmt.Errorf("unable to revoke the sessions of user %q: %w", user.Username, err)
+		}
+	}
+	if _, err := c.SendPasswordResetEmail(user.Email); err != nil {
+		return fmt.Errorf("unable to send the reset password email to user %q: %w", user.Username, err)
+	}
+	return nil
+}
+
+// getBulkPasswordResetUsers returns the users of the arguments and of
+// the --file, selected with the --domain and --role
+func getBulkPasswordResetUsers(c client.Client, cmd *cobra.Command, args []string) ([]*model.User, error) {
+	path, _ := cmd.Flags().GetString("file")
+	domain, _ := cmd.Flags().GetString("domain")
+	role, _ := cmd.Flags().GetString("role")
+
+	userArgs := args
+	if path != "" {
+		fileUsers, err := readUsersFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(fileUsers) == 0 {
+			return nil, errors.New("no users were read from the users file")
+		}
+		userArgs = append(append([]string{}, args...), fileUsers...)
+	}
+
+	return getPasswordResetUsers(c, userArgs, domain, role)
+}
+
+// resetUsersPasswords sends the password reset emails to the users,
+// revoking their sessions before with --force-logout
+func resetUsersPasswords(c client.Client, cmd *cobra.Command, users []*model.User) error {
+	forceLogout, _ := cmd.Flags().GetBool("force-logout")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	concurrency, err := getConcurrency(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun && !confirmFlag {
+		question := fmt.Sprintf("Are you sure you want to send a password reset email to %d users?", len(users))
+		if forceLogout {
+			question = fmt.Sprintf("Are you sure you want to revoke the sessions of %d users and send them a password reset email?", len(users))
+		}
+		if err := getConfirmation(question, false); err != nil {
+			return err
+		}
+	}
+
+	done := passwordResetSent
+	if forceLogout {
+		done += ", sessions revoked"
+	}
+
+	errs := make([]error, len(users))
+	forEachConcurrently(len(users), concurrency, func(i int) {
+		user := users[i]
+		result := &passwordResetResult{ID: user.Id, Username: user.Username, Email: user.Email, Result: passwordResetWouldBeSent}
+		if dryRun {
+			printer.PrintT("{{.Username}} ({{.Email}}): {{.Result}}", result)
+			return
+		}
+
+		if errs[i] = resetUserPassword(c, user, forceLogout); errs[i] != nil {
+			printer.PrintError(errs[i].Error())
+			return
+		}
+		result.Result = done
+		printer.PrintT("{{.Username}} ({{.Email}}): {{.Result}}", result)
+	})
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to reset the password of %d users", failed)
+	}
+
+	return nil
+}
+
+func bulkPasswordResetPlanResolver(c client.Client, cmd *cobra.Command, args []string) ([]planAction, error) {
+	users, err := getBulkPasswordResetUsers(c, cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, user := range users {
+		actions = append(actions, planAction{Action: planActionResetPassword, ID: user.Id, Name: user.Username})
+	}
+	return actions, nil
+}
+
+// applyBulkPasswordResetPlan resets the password of the users of a
+// plan, skipping the ones that can't have their password reset anymore
+func applyBulkPasswordResetPlan(c client.Client, cmd *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, planActionResetPassword); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(actions))
+	for _, action := range actions {
+		ids = append(ids, action.ID)
+	}
+	planUsers, _, err := c.GetUsersByIds(ids)
+	if err != nil {
+		return errors.Wrap(err, "unable to get the users of the plan")
+	}
+
+	users := []*model.User{}
+	for _, user := range planUsers {
+		if !canResetPassword(user) {
+			printer.PrintError(fmt.Sprintf("user %q can't have their password reset anymore and is skipped", user.Username))
+			continue
+		}
+		users = append(users, user)
+	}
+	skipped := len(actions) - len(users)
+
+	if len(users) > 0 {
+		if err := resetUsersPasswords(c, cmd, users); err != nil {
+			return err
+		}
+	}
+	if skipped > 0 {
+		return fmt.Errorf("unable to reset the password of %d users", skipped)
+	}
+	return nil
+}
+
+func bulkPasswordResetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	users, err := getBulkPasswordResetUsers(c, cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		printer.Print("There are no users to reset the password of")
+		return nil
+	}
+	return resetUsersPasswords(c, cmd, users)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestBulkPasswordResetCmd() {
+	newCmd := func(file, domain string, forceLogout, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", file, "")
+		cmd.Flags().String("domain", domain, "")
+		cmd.Flags().String("role", "", "")
+		cmd.Flags().Bool("force-logout", forceLogout, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		cmd.Flags().Bool("confirm", true, "")
+		cmd.Flags().Int("concurrency", 1, "")
+		return cmd
+	}
+
+	alice := &model.User{Id: "alice-id", Username: "alice", Email: "alice@example.com"}
+	bob := &model.User{Id: "bob-id", Username: "bob", Email: "bob@Example.com"}
+	carol := &model.User{Id: "carol-id", Username: "carol", Email: "carol@other.com"}
+	sso := &model.User{Id: "sso-id", Username: "sso", Email: "sso@example.com", AuthService: model.UserAuthServiceGitlab}
+	bot := &model.User{Id: "bot-id", Username: "bot", Email: "bot@example.com", IsBot: true}
+	deactivated := &model.User{Id: "deactivated-id", Username: "deactivated", Email: "deactivated@example.com", DeleteAt: 1}
+
+	s.Run("Should revoke the sessions and send the emails to the users of the domain", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{alice, bob, carol, sso, bot, deactivated}, &model.Response{}, nil).
+			Times(1)
+
+		for _, user := range []*model.User{alice, bob} {
+			s.client.
+				EXPECT().
+				RevokeAllSessions(user.Id).
+				Return(&model.Response{}, nil).
+				Times(1)
+
+			s.client.
+				EXPECT().
+				SendPasswordResetEmail(user.Email).
+				Return(&model.Response{}, nil).
+				Times(1)
+		}
+
+		err := bulkPasswordResetCmdF(s.client, newCmd("", "@example.com", true, false), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&passwordResetResult{ID: alice.Id, Username: alice.Username, Email: alice.Email, Result: "reset email sent, sessions revoked"},
+			&passwordResetResult{ID: bob.Id, Username: bob.Username, Email: bob.Email, Result: "reset email sent, sessions revoked"},
+		}, printer.GetLines())
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("Should only list the users of the file with --dry-run", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "users.txt")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(alice.Email+"\n\n"), 0600))
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(alice.Email, "").
+			Return(alice, &model.Response{}, nil).
+			Times(1)
+
+		err := bulkPasswordResetCmdF(s.client, newCmd(path, "", true, true), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&passwordResetResult{ID: alice.Id, Username: alice.Username, Email: alice.Email, Result: "reset email would be sent"},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should report the users whose password couldn't be reset", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(alice.Email, "").
+			Return(alice, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(carol.Email, "").
+			Return(carol, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SendPasswordResetEmail(alice.Email).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SendPasswordResetEmail(carol.Email).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := bulkPasswordResetCmdF(s.client, newCmd("", "", false, false), []string{alice.Email, carol.Email})
+		s.Require().EqualError(err, "unable to reset the password of 1 users")
+		s.Require().Equal([]interface{}{
+			&passwordResetResult{ID: carol.Id, Username: carol.Username, Email: carol.Email, Result: "reset email sent"},
+		}, printer.GetLines())
+		s.Require().Equal([]interface{}{`unable to send the reset password email to user "alice": mock error`}, printer.GetErrorLines())
+	})
+
+	s.Run("Should plan the password resets and apply the plan", func() {
+		printer.Clean()
+		cmd := newCmd("", "@example.com", true, false)
+		cmd.Use = "bulk-password-reset"
+		addPlanFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("plan", "true"))
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{alice, carol, bot}, &model.Response{}, nil).
+			Times(1)
+
+		err := withPlanActions(bulkPasswordResetPlanResolver, applyBulkPasswordResetPlan, bulkPasswordResetCmdF)(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		plan := printer.GetLines()[0].(*commandPlan)
+		s.Require().Equal([]planAction{{Action: planActionResetPassword, ID: alice.Id, Name: alice.Username}}, plan.Actions)
+
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{alice.Id}).
+			Return([]*model.User{alice}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RevokeAllSessions(alice.Id).
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			SendPasswordResetEmail(alice.Email).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err = applyBulkPasswordResetPlan(s.client, cmd, plan.Actions)
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&passwordResetResult{ID: alice.Id, Username: alice.Username, Email: alice.Email, Result: "reset email sent, sessions revoked"},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should skip the users of a plan that can't have their password reset anymore", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUsersByIds([]string{deactivated.Id}).
+			Return([]*model.User{deactivated}, &model.Response{}, nil).
+			Times(1)
+
+		err := applyBulkPasswordResetPlan(s.client, newCmd("", "", false, false), []planAction{{Action: planActionResetPassword, ID: deactivated.Id, Name: deactivated.Username}})
+		s.Require().EqualError(err, "unable to reset the password of 1 users")
+		s.Require().Equal([]interface{}{`user "deactivated" can't have their password reset anymore and is skipped`}, printer.GetErrorLines())
+	})
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var DeleteInactiveUsersCmd = &cobra.Command{
+	Use:   "delete-inactive",
+	Short: "Permanently delete the users deactivated before a time",
+	Long: `Permanently delete the users that were deactivated before the given time, along with all their related information including posts from the database. Bots are not deleted.
+With --report, a CSV file with the id, username, email, deactivation time and result of each selected user is written as the users are deleted, so the removed accounts are recorded even if the command is interrupted.
+With --plan, the users that would be deleted are printed as a plan that can be reviewed and then applied with --apply-plan, without --before. The users of the plan that were reactivated meanwhile are not deleted.`,
+	Example: `  # check which users would be deleted
+  user delete-inactive --before 2026-01-01T00:00:00+00:00 --dry-run
+
+  user delete-inactive --before 2026-01-01T00:00:00+00:00 --report deleted-users.csv --confirm
+
+  # review the users before deleting them
+  user delete-inactive --before 2026-01-01T00:00:00+00:00 --plan > plan.json
+  user delete-inactive --apply-plan plan.json --report deleted-users.csv --confirm`,
+	Args: cobra.NoArgs,
+	RunE: withClient(withPlanActions(deleteInactiveUsersPlanResolver, applyDeleteInactiveUsersPlan, deleteInactiveUsersCmdF)),
+}
+
+func init() {
+	DeleteInactiveUsersCmd.Flags().String("before", "", "Delete the users deactivated before this time, in ISO 8601 format")
+	DeleteInactiveUsersCmd.Flags().Bool("dry-run", false, "List the users that would be deleted without deleting them")
+	DeleteInactiveUsersCmd.Flags().String("report", "", "Path of the CSV file to write the report of the selected users to")
+	DeleteInactiveUsersCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the users and a DB backup has been performed")
+	addPlanFlags(DeleteInactiveUsersCmd)
+
+	UserCmd.AddCommand(DeleteInactiveUsersCmd)
+}
+
+const (
+	inactiveUserDeleted        = "deleted"
+	inactiveUserWouldBeDeleted = "would be deleted"
+)
+
+type inactiveUserResult struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	DeactivatedAt int64  `json:"deactivated_at"`
+	Result        string `json:"result"`
+}
+
+// getUsersDeactivatedBefore fetches the users, excluding the bots,
+// that were deactivated before the given time
+func getUsersDeactivatedBefore(c client.Client, before int64) ([]*model.User, error) {
+	filter := &userListFilter{inactive: true}
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		pageUsers, err := filter.getPage(c, page, APILimitMaximum)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range pageUsers {
+			if user.DeleteAt > 0 && user.DeleteAt < before && !user.IsBot {
+				users = append(users, user)
+			}
+		}
+		if len(pageUsers) < APILimitMaximum {
+			return users, nil
+		}
+	}
+}
+
+// inactiveUsersReport is the CSV report of the selected users, where
+// each user is written as soon as it's handled, so the report records
+// the deleted users even if the command is interrupted
+type inactiveUsersReport struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func createInactiveUsersReport(path string) (*inactiveUsersReport, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the report")
+	}
+	report := &inactiveUsersReport{f: f, w: csv.NewWriter(f)}
+	if err := report.writeRecord([]string{"id", "username", "email", "deactivated_at", "result"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *inactiveUsersReport) writeRecord(record []string) error {
+	if err := r.w.Write(record); err != nil {
+		return errors.Wrap(err, "failed to write the report")
+	}
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		return errors.Wrap(err, "failed to write the report")
+	}
+	return nil
+}
+
+func (r *inactiveUsersReport) write(result *inactiveUserResult) error {
+	return r.writeRecord([]string{result.ID, result.Username, result.Email, printer.FormatTimestamp(result.DeactivatedAt), result.Result})
+}
+
+func (r *inactiveUsersReport) close() error {
+	if err := r.f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the report")
+	}
+	return nil
+}
+
+func getInactiveUsersToDelete(c client.Client, cmd *cobra.Command) ([]*model.User, error) {
+	beforeArg, _ := cmd.Flags().GetString("before")
+	before, err := parsePostListTime("before", beforeArg)
+	if err != nil {
+		return nil, err
+	}
+	if before == 0 {
+		return nil, errors.New("the --before flag is required")
+	}
+	return getUsersDeactivatedBefore(c, before)
+}
+
+func deleteInactiveUsersPlanResolver(c client.Client, cmd *cobra.Command, _ []string) ([]planAction, error) {
+	users, err := getInactiveUsersToDelete(c, cmd)
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, user := range users {
+		actions = append(actions, planAction{Action: "delete_user", ID: user.Id, Name: user.Username})
+	}
+	return actions, nil
+}
+
+// applyDeleteInactiveUsersPlan deletes the users of a plan, skipping the
+// ones that were reactivated since the plan was made
+func applyDeleteInactiveUsersPlan(c client.Client, cmd *cobra.Command, actions []planAction) error {
+	if err := checkPlanActions(actions, "delete_user"); err != nil {
+		return err
+	}
+
+	users := []*model.User{}
+	failed := 0
+	for _, action := range actions {
+		user, _, err := c.GetUser(action.ID, "")
+		switch {
+		case err != nil:
+			printer.PrintError(fmt.Sprintf("unable to get user %q: %s", action.Name, err))
+			failed++
+		case user.DeleteAt == 0:
+			printer.PrintError(fmt.Sprintf("user %q is active and won't be deleted", user.Username))
+			failed++
+		default:
+			users = append(users, user)
+		}
+	}
+
+	if err := deleteInactiveUsers(c, cmd, users, "from the plan"); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to delete %d users", failed)
+	}
+	return nil
+}
+
+func deleteInactiveUsersCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	beforeArg, _ := cmd.Flags().GetString("before")
+	users, err := getInactiveUsersToDelete(c, cmd)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		printer.Print("There are no users deactivated before " + beforeArg)
+		return nil
+	}
+	return deleteInactiveUsers(c, cmd, users, "deactivated before "+beforeArg)
+}
+
+// deleteInactiveUsers permanently deletes the users, writing the report
+// of the --report flag. The selection describes how the users were
+// selected for the confirmation prompt
+func deleteInactiveUsers(c client.Client, cmd *cobra.Command, users []*model.User, selection string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	reportPath, _ := cmd.Flags().GetString("report")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	if !dryRun && !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to delete the %d users %s? All their data will be permanently deleted?", len(users), selection), true); err != nil {
+			return err
+		}
+	}
+
+	var report *inactiveUsersReport
+	if reportPath != "" {
+		var err error
+		if report, err = createInactiveUsersReport(reportPath); err != nil {
+			return err
+		}
+		defer report.close()
+	}
+
+	failed := 0
+	for _, user := range users {
+		result := &inactiveUserResult{ID: user.Id, Username: user.Username, Email: user.Email, DeactivatedAt: user.DeleteAt, Result: inactiveUserWouldBeDeleted}
+		if dryRun {
+			printer.PrintT("{{.Username}} ({{.ID}}) deactivated at {{timestamp .DeactivatedAt}} would be deleted", result)
+		} else if res, err := c.PermanentDeleteUser(user.Id); err != nil {
+			result.Result = "failed: " + err.Error()
+			printer.PrintError("Unable to delete user '" + user.Username + "' error: " + err.Error())
+			failed++
+		} else {
+			// res.StatusCode is checked for 202 to identify issues with file deletion.
+			if res.StatusCode == http.StatusAccepted {
+				printer.PrintError("There were issues with deleting profile image of the user. Please delete it manually. Id: " + user.Id)
+			}
+			result.Result = inactiveUserDeleted
+			printer.PrintT("Deleted user '{{.Username}}'", result)
+		}
+
+		if report != nil {
+			if err := report.write(result); err != nil {
+				return err
+			}
+		}
+	}
+
+	if report != nil {
+		if err := report.close(); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to delete %d users", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestDeleteInactiveUsersCmd() {
+	newCmd := func(dryRun bool, report string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("before", "2026-01-01T00:00:00+00:00", "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		cmd.Flags().String("report", report, "")
+		cmd.Flags().Bool("confirm", true, "")
+		return cmd
+	}
+
+	usersResponse := func(users ...*model.User) *http.Response {
+		b, err := json.Marshal(users)
+		s.Require().NoError(err)
+		return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}
+	}
+
+	// 2025-06-01 and 2026-03-01
+	old := &model.User{Id: "old-id", Username: "old", Email: "old@example.com", DeleteAt: 1748736000000}
+	another := &model.User{Id: "another-id", Username: "another", Email: "another@example.com", DeleteAt: 1748736000000}
+	recent := &model.User{Id: "recent-id", Username: "recent", DeleteAt: 1772323200000}
+	bot := &model.User{Id: "bot-id", Username: "bot", DeleteAt: 1748736000000, IsBot: true}
+
+	s.Run("Should delete the users deactivated before the time and write the report", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		report := filepath.Join(tmp, "report.csv")
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?inactive=true&page=0&per_page=200", "").
+			Return(usersResponse(old, another, recent, bot), nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PermanentDeleteUser(old.Id).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		// the deleted users are in the report before the next deletion
+		s.client.
+			EXPECT().
+			PermanentDeleteUser(another.Id).
+			DoAndReturn(func(userID string) (*model.Response, error) {
+				b, err := ioutil.ReadFile(report)
+				s.Require().NoError(err)
+				s.Require().Contains(string(b), "old-id,old,old@example.com,")
+				return nil, errors.New("mock error")
+			}).
+			Times(1)
+
+		err := deleteInactiveUsersCmdF(s.client, newCmd(false, report), []string{})
+		s.Require().EqualError(err, "unable to delete 1 users")
+		s.Require().Equal([]interface{}{
+			&inactiveUserResult{ID: old.Id, Username: old.Username, Email: old.Email, DeactivatedAt: old.DeleteAt, Result: inactiveUserDeleted},
+		}, printer.GetLines())
+		s.Require().Equal("Unable to delete user 'another' error: mock error", printer.GetErrorLines()[0])
+
+		b, err := ioutil.ReadFile(report)
+		s.Require().NoError(err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		s.Require().Len(lines, 3)
+		s.Require().Equal("id,username,email,deactivated_at,result", lines[0])
+		s.Require().True(strings.HasPrefix(lines[1], "old-id,old,old@example.com,"))
+		s.Require().True(strings.HasSuffix(lines[1], ",deleted"))
+		s.Require().True(strings.HasSuffix(lines[2], ",failed: mock error"))
+	})
+
+	s.Run("Should list the users without deleting them with dry run", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?inactive=true&page=0&per_page=200", "").
+			Return(usersResponse(old, recent), nil).
+			Times(1)
+
+		err := deleteInactiveUsersCmdF(s.client, newCmd(true, ""), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&inactiveUserResult{ID: old.Id, Username: old.Username, Email: old.Email, DeactivatedAt: old.DeleteAt, Result: inactiveUserWouldBeDeleted},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should fail with an invalid time", func() {
+		cmd := newCmd(true, "")
+		_ = cmd.Flags().Set("before", "last year")
+
+		err := deleteInactiveUsersCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "invalid before time 'last year'")
+	})
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var UserSetFieldCmd = &cobra.Command{
+	Use:   "set-field [user] [field] [value]",
+	Short: "Update a profile field of users",
+	Long: `Update a profile field of a user, or with --bulk, update any number of profile fields of many users from a CSV file.
+The supported fields are nickname, position, first_name, last_name, locale and timezone. Setting a timezone disables the automatic timezone of the user.
+The CSV file must have a header row with an email or username column to identify the users, and a column per field to update. Empty cells leave the field of the user untouched. All the rows are validated before updating any user, and only the fields whose value changes are reported and updated.`,
+	Example: `  user set-field john.doe position "Engineering Manager"
+  user set-field john.doe timezone Europe/Madrid
+
+  # sync the HR data of the users, checking the changes first
+  user set-field --bulk hr-export.csv --dry-run
+  user set-field --bulk hr-export.csv`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if bulk, _ := cmd.Flags().GetBool("bulk"); bulk {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(3)(cmd, args)
+	},
+	RunE: withClient(userSetFieldCmdF),
+}
+
+func init() {
+	UserSetFieldCmd.Flags().Bool("bulk", false, "Read the users and fields to update from the CSV file given as argument")
+	UserSetFieldCmd.Flags().Bool("dry-run", false, "Report the changes without updating the users")
+
+	UserCmd.AddCommand(UserSetFieldCmd)
+}
+
+type userField struct {
+	maxLength int
+	get       func(user *model.User) string
+	set       func(patch *model.UserPatch, user *model.User, value string)
+	validate  func(value string) error
+}
+
+var userFields = map[string]*userField{
+	"nickname": {
+		maxLength: model.UserNicknameMaxRunes,
+		get:       func(user *model.User) string { return user.Nickname },
+		set:       func(patch *model.UserPatch, _ *model.User, value string) { patch.Nickname = model.NewString(value) },
+	},
+	"position": {
+		maxLength: model.UserPositionMaxRunes,
+		get:       func(user *model.User) string { return user.Position },
+		set:       func(patch *model.UserPatch, _ *model.User, value string) { patch.Position = model.NewString(value) },
+	},
+	"first_name": {
+		maxLength: model.UserFirstNameMaxRunes,
+		get:       func(user *model.User) string { return user.FirstName },
+		set:       func(patch *model.UserPatch, _ *model.User, value string) { patch.FirstName = model.NewString(value) },
+	},
+	"last_name": {
+		maxLength: model.UserLastNameMaxRunes,
+		get:       func(user *model.User) string { return user.LastName },
+		set:       func(patch *model.UserPatch, _ *model.User, value string) { patch.LastName = model.NewString(value) },
+	},
+	"locale": {
+		maxLength: model.UserLocaleMaxLength,
+		get:       func(user *model.User) string { return user.Locale },
+		set:       func(patch *model.UserPatch, _ *model.User, value string) { patch.Locale = model.NewString(value) },
+	},
+	"timezone": {
+		maxLength: model.UserTimezoneMaxRunes,
+		get: func(user *model.User) string {
+			if user.Timezone["useAutomaticTimezone"] == "true" {
+				return ""
+			}
+			return user.Timezone["manualTimezone"]
+		},
+		set: func(patch *model.UserPatch, user *model.User, value string) {
+			patch.Timezone = model.StringMap{
+				"useAutomaticTimezone": "false",
+				"manualTimezone":       value,
+				"automaticTimezone":    user.Timezone["automaticTimezone"],
+			}
+		},
+		validate: func(value string) error {
+			if _, err := time.LoadLocation(value); err != nil {
+				return fmt.Errorf("unknown timezone %q", value)
+			}
+			return nil
+		},
+	},
+}
+
+func getUserFieldNames() []string {
+	names := make([]string, 0, len(userFields))
+	for name := range userFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateUserField(name, value string) error {
+	field, ok := userFields[name]
+	if !ok {
+		return fmt.Errorf("unknown field %q, the supported fields are %s", name, strings.Join(getUserFieldNames(), ", "))
+	}
+	if utf8.RuneCountInString(value) > field.maxLength {
+		return fmt.Errorf("the value of field %q is longer than %d characters", name, field.maxLength)
+	}
+	if field.validate != nil {
+		return field.validate(value)
+	}
+	return nil
+}
+
+// userFieldUpdate holds the fields to update of the user identified
+// by the key
+type userFieldUpdate struct {
+	key    string
+	fields map[string]string
+}
+
+type userFieldChange struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Field    string `json:"field"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+// readUserFieldUpdates reads and validates the rows of the CSV file,
+// returning all the validation errors at once
+func readUserFieldUpdates(r io.Reader) ([]*userFieldUpdate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the CSV header")
+	}
+
+	keyColumn := -1
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case header[i] == "email" || header[i] == "username":
+			if keyColumn != -1 {
+				return nil, errors.New("the CSV file must have only one of the email and username columns")
+			}
+			keyColumn = i
+		case userFields[header[i]] == nil:
+			return nil, fmt.Errorf("unknown column %q, the supported fields are %s", column, strings.Join(getUserFieldNames(), ", "))
+		}
+	}
+	if keyColumn == -1 {
+		return nil, errors.New("the CSV file must have an email or username column")
+	}
+
+	updates := []*userFieldUpdate{}
+	var validationErrors []string
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the CSV file")
+		}
+
+		update := &userFieldUpdate{key: strings.TrimSpace(record[keyColumn]), fields: map[string]string{}}
+		if update.key == "" {
+			validationErrors = append(validationErrors, fmt.Sprintf("row %d: the %s is empty", row, header[keyColumn]))
+			continue
+		}
+		for i, value := range record {
+			if i == keyColumn || value == "" {
+				continue
+			}
+			if err := validateUserField(header[i], value); err != nil {
+				validationErrors = append(validationErrors, fmt.Sprintf("row %d: %s", row, err))
+				continue
+			}
+			update.fields[header[i]] = value
+		}
+		updates = append(updates, update)
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("the CSV file is not valid:\n%s", strings.Join(validationErrors, "\n"))
+	}
+
+	return updates, nil
+}
+
+// applyUserFieldUpdate patches the fields of the user whose value
+// changes, returning the changes
+func applyUserFieldUpdate(c client.Client, update *userFieldUpdate, dryRun bool) ([]*userFieldChange, error) {
+	user, err := getUserFromArg(c, update.key)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &model.UserPatch{}
+	changes := []*userFieldChange{}
+	for _, name := range getUserFieldNames() {
+		value, ok := update.fields[name]
+		if !ok {
+			continue
+		}
+		field := userFields[name]
+		if old := field.get(user); old != value {
+			field.set(patch, user, value)
+			changes = append(changes, &userFieldChange{ID: user.Id, Username: user.Username, Field: name, Old: old, New: value})
+		}
+	}
+
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+
+	if _, _, err := c.PatchUser(user.Id, patch); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func userSetFieldCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	bulk, _ := cmd.Flags().GetBool("bulk")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	var updates []*userFieldUpdate
+	if bulk {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to open the CSV file")
+		}
+		defer f.Close()
+
+		if updates, err = readUserFieldUpdates(f); err != nil {
+			return err
+		}
+	} else {
+		if err := validateUserField(args[1], args[2]); err != nil {
+			return err
+		}
+		updates = []*userFieldUpdate{{key: args[0], fields: map[string]string{args[1]: args[2]}}}
+	}
+
+	report := &userUpdateReport{}
+	for _, update := range updates {
+		changes, err := applyUserFieldUpdate(c, update, dryRun)
+		if err != nil {
+			report.fail(fmt.Sprintf("unable to update user %q: %s", update.key, err))
+			continue
+		}
+		report.add(len(changes))
+		for _, change := range changes {
+			printer.PrintT(`{{.Username}}: {{.Field}} {{printf "%q" .Old}} -> {{printf "%q" .New}}`, change)
+		}
+	}
+
+	return report.finish(dryRun)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserSetFieldCmd() {
+	newCmd := func(bulk, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", bulk, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	writeCSV := func(dir, content string) string {
+		path := filepath.Join(dir, "users.csv")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	s.Run("Should update a single field of a user", func() {
+		printer.Clean()
+		user := &model.User{Id: "user-id", Username: "john", Email: "john@example.com", Position: "Engineer"}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchUser(user.Id, &model.UserPatch{Position: model.NewString("Engineering Manager")}).
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		err := userSetFieldCmdF(s.client, newCmd(false, false), []string{user.Email, "position", "Engineering Manager"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&userFieldChange{ID: user.Id, Username: user.Username, Field: "position", Old: "Engineer", New: "Engineering Manager"}, printer.GetLines()[0])
+	})
+
+	s.Run("Should update the changed fields of the users of a CSV file", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeCSV(tmp, `email,first_name,last_name,timezone
+john@example.com,John,Smith,Europe/Madrid
+jane@example.com,Jane,,
+`)
+		john := &model.User{Id: "john-id", Username: "john", FirstName: "John", LastName: "Doe", Timezone: model.StringMap{"useAutomaticTimezone": "true", "automaticTimezone": "Europe/Berlin"}}
+		jane := &model.User{Id: "jane-id", Username: "jane", FirstName: "Jane", LastName: "Doe"}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("john@example.com", "").
+			Return(john, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("jane@example.com", "").
+			Return(jane, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchUser(john.Id, &model.UserPatch{
+				LastName: model.NewString("Smith"),
+				Timezone: model.StringMap{"useAutomaticTimezone": "false", "manualTimezone": "Europe/Madrid", "automaticTimezone": "Europe/Berlin"},
+			}).
+			Return(john, &model.Response{}, nil).
+			Times(1)
+
+		err := userSetFieldCmdF(s.client, newCmd(true, false), []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 3)
+		s.Require().Equal(&userFieldChange{ID: john.Id, Username: john.Username, Field: "last_name", Old: "Doe", New: "Smith"}, printer.GetLines()[0])
+		s.Require().Equal(&userFieldChange{ID: john.Id, Username: john.Username, Field: "timezone", Old: "", New: "Europe/Madrid"}, printer.GetLines()[1])
+	})
+
+	s.Run("Should report the changes without updating the users with dry run", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeCSV(tmp, "email,nickname\njohn@example.com,johnny\n")
+		john := &model.User{Id: "john-id", Username: "john"}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("john@example.com", "").
+			Return(john, &model.Response{}, nil).
+			Times(1)
+
+		err := userSetFieldCmdF(s.client, newCmd(true, true), []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&userFieldChange{ID: john.Id, Username: john.Username, Field: "nickname", Old: "", New: "johnny"}, printer.GetLines()[0])
+	})
+
+	s.Run("Should validate all the rows before updating any user", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeCSV(tmp, "username,locale,timezone\njohn,en,Mars/Olympus\n,es,\njane,"+strings.Repeat("x", 6)+",\n")
+
+		err := userSetFieldCmdF(s.client, newCmd(true, false), []string{path})
+		s.Require().EqualError(err, `the CSV file is not valid:
+row 2: unknown timezone "Mars/Olympus"
+row 3: the username is empty
+row 4: the value of field "locale" is longer than 5 characters`)
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Should fail with an unknown field", func() {
+		printer.Clean()
+
+		err := userSetFieldCmdF(s.client, newCmd(false, false), []string{"john", "email", "john@example.com"})
+		s.Require().EqualError(err, `unknown field "email", the supported fields are first_name, last_name, locale, nickname, position, timezone`)
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// userListFilter holds the criteria of the users to list. The server
+// applies the criteria it supports in a single request, and the rest
+// are applied by mmctl on each fetched page of users
+type userListFilter struct {
+	team        *model.Team
+	notInTeam   *model.Team
+	withoutTeam bool
+	inactive    bool
+	role        string
+	authService string
+
+	// notInTeamMembers holds the members of the not-in-team team
+	// when that criterion can't be applied by the server
+	notInTeamMembers map[string]bool
+}
+
+// notInTeamServerSide reports whether the server can apply the
+// not-in-team criterion, as the API ignores all the others when it
+// is present
+func (f *userListFilter) notInTeamServerSide() bool {
+	return f.team == nil && !f.withoutTeam && !f.inactive && f.role == ""
+}
+
+func (f *userListFilter) serverSide() []string {
+	filters := []string{}
+	if f.team != nil {
+		filters = append(filters, "team")
+	}
+	if f.notInTeam != nil && f.notInTeamServerSide() {
+		filters = append(filters, "not-in-team")
+	}
+	if f.withoutTeam {
+		filters = append(filters, "without-team")
+	}
+	if f.inactive {
+		filters = append(filters, "inactive")
+	}
+	if f.role != "" {
+		filters = append(filters, "role")
+	}
+	return filters
+}
+
+func (f *userListFilter) clientSide() []string {
+	filters := []string{}
+	if f.notInTeam != nil && !f.notInTeamServerSide() {
+		filters = append(filters, "not-in-team")
+	}
+	if f.authService != "" {
+		filters = append(filters, "auth-service")
+	}
+	return filters
+}
+
+func (f *userListFilter) matches(user *model.User) bool {
+	if f.notInTeamMembers != nil && f.notInTeamMembers[user.Id] {
+		return false
+	}
+	if f.authService != "" && !strings.EqualFold(user.AuthService, f.authService) {
+		return false
+	}
+	return true
+}
+
+// prepare fetches the data that the client side criteria need
+func (f *userListFilter) prepare(c client.Client) error {
+	if f.notInTeam == nil || f.notInTeamServerSide() {
+		return nil
+	}
+
+	f.notInTeamMembers = map[string]bool{}
+	for page := 0; ; page++ {
+		users, _, err := c.GetUsersInTeam(f.notInTeam.Id, page, APILimitMaximum, "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch users for team %s", f.notInTeam.Name)
+		}
+		for _, user := range users {
+			f.notInTeamMembers[user.Id] = true
+		}
+		if len(users) < APILimitMaximum {
+			return nil
+		}
+	}
+}
+
+// getPage fetches a page of users applying the server side criteria
+func (f *userListFilter) getPage(c client.Client, page, perPage int) ([]*model.User, error) {
+	query := url.Values{}
+	if f.team != nil {
+		query.Set("in_team", f.team.Id)
+	}
+	if f.notInTeam != nil && f.notInTeamServerSide() {
+		query.Set("not_in_team", f.notInTeam.Id)
+	}
+	if f.withoutTeam {
+		query.Set("without_team", "true")
+	}
+	if f.inactive {
+		query.Set("inactive", "true")
+	}
+	if f.role != "" {
+		query.Set("role", f.role)
+	}
+
+	switch {
+	case len(query) == 0:
+		users, _, err := c.GetUsers(page, perPage, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch users")
+		}
+		return users, nil
+	case len(query) == 1 && f.team != nil:
+		users, _, err := c.GetUsersInTeam(f.team.Id, page, perPage, "")
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Failed to fetch users for team %s", f.team.Name))
+		}
+		return users, nil
+	}
+
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	r, err := c.DoAPIGet("/users?"+query.Encode(), "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch users")
+	}
+	defer r.Body.Close()
+
+	var users []*model.User
+	if err := json.NewDecoder(r.Body).Decode(&users); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the users")
+	}
+	return users, nil
+}
+
+// total returns an estimate of the number of users that the server
+// side criteria match, and whether it is known, which is only the case
+// for the unfiltered and the team listings
+func (f *userListFilter) total(c client.Client) (int, bool) {
+	switch {
+	case len(f.serverSide()) == 0:
+		stats, _, err := c.GetTotalUsersStats("")
+		if err != nil {
+			return 0, false
+		}
+		return int(stats.TotalUsersCount), true
+	case len(f.serverSide()) == 1 && f.team != nil:
+		stats, _, err := c.GetTeamStats(f.team.Id, "")
+		if err != nil {
+			return 0, false
+		}
+		return int(stats.TotalMemberCount), true
+	}
+	return 0, false
+}
+
+// prefetchPages fetches the pages that the total number of users spans
+// concurrently, with at most the given number of requests at a time.
+// The total is an estimate, as it doesn't include the deactivated users
+// for instance, so the pages after the fetched ones are still walked
+// through one after another
+func (f *userListFilter) prefetchPages(c client.Client, perPage, total, concurrency int) ([][]*model.User, error) {
+	n := (total + perPage - 1) / perPage
+	pages := make([][]*model.User, n)
+	errs := make([]error, n)
+
+	forEachConcurrently(n, concurrency, func(page int) {
+		pages[page], errs[page] = f.getPage(c, page, perPage)
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// printUserListFilterReport prints where each criterion of the filter
+// was applied, and how many of the fetched users matched the client
+// side ones
+func printUserListFilterReport(f *userListFilter, fetched, matched int) {
+	if serverSide := f.serverSide(); len(serverSide) > 0 {
+		printer.PrintError("filters applied by the server: " + strings.Join(serverSide, ", "))
+	}
+	if clientSide := f.clientSide(); len(clientSide) > 0 {
+		printer.PrintError(fmt.Sprintf("filters applied by mmctl on each page: %s (%d of %d fetched users matched)", strings.Join(clientSide, ", "), matched, fetched))
+	}
+}
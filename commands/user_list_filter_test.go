@@ -0,0 +1,296 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestListUserCmdFFilters() {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", 2, "")
+		cmd.Flags().Bool("all", false, "")
+		cmd.Flags().String("team", "", "")
+		cmd.Flags().String("not-in-team", "", "")
+		cmd.Flags().Bool("without-team", false, "")
+		cmd.Flags().Bool("inactive", false, "")
+		cmd.Flags().Bool("include-deleted", false, "")
+		cmd.Flags().Bool("deleted-only", false, "")
+		cmd.Flags().String("role", "", "")
+		cmd.Flags().String("auth-service", "", "")
+		cmd.Flags().Bool("verbose", true, "")
+		cmd.Flags().Int("concurrency", 1, "")
+		return cmd
+	}
+
+	usersResponse := func(users ...*model.User) *http.Response {
+		b, err := json.Marshal(users)
+		s.Require().NoError(err)
+		return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}
+	}
+
+	team := &model.Team{Id: "team-id", Name: "team"}
+	john := &model.User{Id: "john-id", Username: "john", AuthService: "ldap"}
+	jane := &model.User{Id: "jane-id", Username: "jane"}
+	mary := &model.User{Id: "mary-id", Username: "mary", AuthService: "ldap"}
+
+	s.Run("Should apply the supported filters server side", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("team", team.Name)
+		_ = cmd.Flags().Set("inactive", "true")
+
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?in_team=team-id&inactive=true&page=0&per_page=2", "").
+			Return(usersResponse(john), nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(john, printer.GetLines()[0])
+		s.Require().Equal([]interface{}{"filters applied by the server: team, inactive"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fall back to client side filtering when the server can't combine the filters", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("not-in-team", team.Name)
+		_ = cmd.Flags().Set("role", model.SystemAdminRoleId)
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Name, "").
+			Return(nil, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{jane}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?page=0&per_page=2&role=system_admin", "").
+			Return(usersResponse(john, jane), nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?page=1&per_page=2&role=system_admin", "").
+			Return(usersResponse(mary), nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{john, mary}, printer.GetLines())
+		s.Require().Equal([]interface{}{
+			"filters applied by the server: role",
+			"filters applied by mmctl on each page: not-in-team (2 of 3 fetched users matched)",
+		}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fetch pages until enough users match", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("per-page", "1")
+		_ = cmd.Flags().Set("auth-service", "ldap")
+		_ = cmd.Flags().Set("verbose", "false")
+
+		s.client.
+			EXPECT().
+			GetUsers(0, 1, "").
+			Return([]*model.User{jane}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(1, 1, "").
+			Return([]*model.User{john}, &model.Response{}, nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{john}, printer.GetLines())
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Should fail when combining team and without team", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("team", team.Name)
+		_ = cmd.Flags().Set("without-team", "true")
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the --without-team and --team flags cannot be used together")
+	})
+
+	s.Run("Should only list the deactivated users with deleted only", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("deleted-only", "true")
+		deleted := &model.User{Id: "deleted-id", Username: "deleted", DeleteAt: 1748736000000}
+
+		s.client.
+			EXPECT().
+			DoAPIGet("/users?inactive=true&page=0&per_page=2", "").
+			Return(usersResponse(deleted), nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{deleted}, printer.GetLines())
+		s.Require().Equal([]interface{}{"filters applied by the server: inactive"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should fail when combining include deleted and deleted only", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("include-deleted", "true")
+		_ = cmd.Flags().Set("deleted-only", "true")
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the --include-deleted and --deleted-only flags cannot be used together")
+	})
+
+	s.Run("Should fetch the pages concurrently when the number of users is known", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("all", "true")
+		_ = cmd.Flags().Set("concurrency", "4")
+		deleted := &model.User{Id: "deleted-id", Username: "deleted", DeleteAt: 1748736000000}
+
+		s.client.
+			EXPECT().
+			GetTotalUsersStats("").
+			Return(&model.UsersStats{TotalUsersCount: 3}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(0, 2, "").
+			Return([]*model.User{john, jane}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(1, 2, "").
+			Return([]*model.User{mary, deleted}, &model.Response{}, nil).
+			Times(1)
+
+		// the total doesn't include the deactivated users, so the
+		// following pages are still fetched
+		s.client.
+			EXPECT().
+			GetUsers(2, 2, "").
+			Return([]*model.User{}, &model.Response{}, nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{john, jane, mary, deleted}, printer.GetLines())
+	})
+
+	s.Run("Should fetch the pages one after another when the number of users is unknown", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("all", "true")
+		_ = cmd.Flags().Set("concurrency", "4")
+		_ = cmd.Flags().Set("team", team.Name)
+		_ = cmd.Flags().Set("verbose", "false")
+
+		s.client.
+			EXPECT().
+			GetTeamByName(team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamStats(team.Id, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 0, 2, "").
+			Return([]*model.User{john}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(team.Id, 1, 2, "").
+			Return([]*model.User{}, &model.Response{}, nil).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{john}, printer.GetLines())
+	})
+
+	s.Run("Should fail when a prefetched page can't be fetched", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("all", "true")
+		_ = cmd.Flags().Set("concurrency", "2")
+
+		s.client.
+			EXPECT().
+			GetTotalUsersStats("").
+			Return(&model.UsersStats{TotalUsersCount: 4}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(0, 2, "").
+			Return([]*model.User{john, jane}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(1, 2, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "Failed to fetch users: mock error")
+		s.Require().Empty(printer.GetLines())
+	})
+
+	s.Run("Should fail with a concurrency over the maximum", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("all", "true")
+		_ = cmd.Flags().Set("concurrency", "100")
+
+		err := listUsersCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "the concurrency cannot be greater than 32")
+		s.Require().Empty(printer.GetLines())
+	})
+}
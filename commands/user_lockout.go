@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const loginAuditAction = "/api/v4/users/login"
+
+var UserLockoutCmd = &cobra.Command{
+	Use:   "lockout",
+	Short: "Diagnose and clear login lockouts",
+}
+
+var UserLockoutListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users with failed login attempts",
+	Long: `List the login IDs with failed login attempts since their last successful login, based on the server audits.
+A user is reported as locked out when the number of failed attempts reaches the "ServiceSettings.MaximumLoginAttempts" setting. As the server doesn't expose the failed attempts counter, the result is an estimation based on the inspected audits.`,
+	Example: `  user lockout list
+  user lockout list --since 72h --locked-only`,
+	Args: cobra.NoArgs,
+	RunE: withClient(userLockoutListCmdF),
+}
+
+var UserLockoutUnlockCmd = &cobra.Command{
+	Use:   "unlock [users]",
+	Short: "Unlock users",
+	Long: `Unlock users that are locked out after too many failed login attempts.
+The server only resets the failed attempts counter when the password of the user changes, so either a new password has to be set or a password reset email has to be sent to the user.`,
+	Example: `  user lockout unlock user@example.com --send-reset-email
+  user lockout unlock john.doe --password NewPassword1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(userLockoutUnlockCmdF),
+}
+
+func init() {
+	UserLockoutListCmd.Flags().Duration("since", 24*time.Hour, "Only inspect the audits of this period")
+	UserLockoutListCmd.Flags().Int("max-audits", 5000, "Maximum number of audits to inspect")
+	UserLockoutListCmd.Flags().Bool("locked-only", false, "Only list the users that are locked out")
+
+	UserLockoutUnlockCmd.Flags().String("password", "", "Set this new password to unlock the users")
+	UserLockoutUnlockCmd.Flags().Bool("send-reset-email", false, "Send a password reset email to the users, which unlocks them once they reset their password")
+
+	UserLockoutCmd.AddCommand(
+		UserLockoutListCmd,
+		UserLockoutUnlockCmd,
+	)
+	UserCmd.AddCommand(UserLockoutCmd)
+}
+
+type loginFailures struct {
+	LoginID       string `json:"login_id"`
+	UserID        string `json:"user_id"`
+	Username      string `json:"username"`
+	Failures      int    `json:"failures"`
+	LastFailureAt int64  `json:"last_failure_at"`
+	Locked        bool   `json:"locked"`
+}
+
+func userLockoutListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	maxAudits, _ := cmd.Flags().GetInt("max-audits")
+	lockedOnly, _ := cmd.Flags().GetBool("locked-only")
+
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get the server config")
+	}
+	maxAttempts := *config.ServiceSettings.MaximumLoginAttempts
+
+	sinceMillis := model.GetMillisForTime(time.Now().Add(-since))
+	lastLogins := map[string]int64{}
+	failures := map[string][]int64{}
+	inspected := 0
+	perPage := APILimitMaximum
+	for page := 0; inspected < maxAudits; page++ {
+		audits, _, err := c.GetAudits(page, perPage, "")
+		if err != nil {
+			return errors.Wrap(err, "unable to get the audits")
+		}
+
+		done := false
+		for _, audit := range audits {
+			if audit.CreateAt < sinceMillis || inspected >= maxAudits {
+				done = true
+				break
+			}
+			inspected++
+
+			if audit.Action != loginAuditAction {
+				continue
+			}
+
+			// the audits are sorted from newest to oldest, so the
+			// first success found is the last login of the user
+			if strings.HasPrefix(audit.ExtraInfo, "success") {
+				if _, ok := lastLogins[audit.UserId]; !ok {
+					lastLogins[audit.UserId] = audit.CreateAt
+				}
+				continue
+			}
+
+			if strings.HasPrefix(audit.ExtraInfo, "failure - login_id=") {
+				loginID := strings.Fields(strings.TrimPrefix(audit.ExtraInfo, "failure - login_id="))
+				if len(loginID) == 0 {
+					continue
+				}
+				failures[loginID[0]] = append(failures[loginID[0]], audit.CreateAt)
+			}
+		}
+
+		if done || len(audits) < perPage {
+			break
+		}
+	}
+
+	loginIDs := make([]string, 0, len(failures))
+	for loginID := range failures {
+		loginIDs = append(loginIDs, loginID)
+	}
+	sort.Strings(loginIDs)
+
+	for _, loginID := range loginIDs {
+		result := &loginFailures{LoginID: loginID}
+		if user, err := getUserFromArg(c, loginID); err == nil {
+			result.UserID = user.Id
+			result.Username = user.Username
+		}
+
+		for _, failureAt := range failures[loginID] {
+			if result.UserID != "" && failureAt < lastLogins[result.UserID] {
+				continue
+			}
+			result.Failures++
+			if failureAt > result.LastFailureAt {
+				result.LastFailureAt = failureAt
+			}
+		}
+		result.Locked = result.UserID != "" && result.Failures >= maxAttempts
+
+		if result.Failures == 0 || (lockedOnly && !result.Locked) {
+			continue
+		}
+
+		printer.PrintT("{{.LoginID}}{{if .Username}} ({{.Username}}){{else}} (unknown user){{end}}: {{.Failures}} failed attempts, last at {{timestamp .LastFailureAt}}{{if .Locked}}, locked out{{end}}", result)
+	}
+
+	return nil
+}
+
+func userLockoutUnlockCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	password, _ := cmd.Flags().GetString("password")
+	sendResetEmail, _ := cmd.Flags().GetBool("send-reset-email")
+	if (password == "") == !sendResetEmail {
+		return errors.New("either --password or --send-reset-email must be provided")
+	}
+
+	users, err := getUsersFromArgs(c, args)
+	if err != nil {
+		printer.PrintError(err.Error())
+	}
+
+	for _, user := range users {
+		if sendResetEmail {
+			if _, err := c.SendPasswordResetEmail(user.Email); err != nil {
+				printer.PrintError(fmt.Sprintf("unable to send the password reset email to user %s: %s", user.Username, err))
+				continue
+			}
+			printer.PrintT("Password reset email sent to {{.Username}}, they will be unlocked once they reset their password", user)
+			continue
+		}
+
+		if _, err := c.UpdateUserPassword(user.Id, "", password); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to set the password of user %s: %s", user.Username, err))
+			continue
+		}
+		printer.PrintT("User {{.Username}} unlocked", user)
+	}
+
+	return nil
+}
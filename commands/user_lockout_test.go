@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserLockoutListCmd() {
+	now := model.GetMillis()
+	mockConfig := &model.Config{}
+	mockConfig.SetDefaults()
+	*mockConfig.ServiceSettings.MaximumLoginAttempts = 3
+
+	mockUser := &model.User{Id: model.NewId(), Username: "john", Email: "john@example.com"}
+
+	newCmd := func(lockedOnly bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("since", 24*time.Hour, "")
+		cmd.Flags().Int("max-audits", 5000, "")
+		cmd.Flags().Bool("locked-only", lockedOnly, "")
+		return cmd
+	}
+
+	mockAudits := model.Audits{
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=john@example.com", CreateAt: now - 1000},
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=john@example.com", CreateAt: now - 2000},
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=ghost", CreateAt: now - 2500},
+		{Action: "/api/v4/users/me", ExtraInfo: "", UserId: mockUser.Id, CreateAt: now - 2600},
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=john@example.com", CreateAt: now - 3000},
+		{Action: loginAuditAction, ExtraInfo: "success session_user=" + mockUser.Id, UserId: mockUser.Id, CreateAt: now - 4000},
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=john@example.com", CreateAt: now - 5000},
+		{Action: loginAuditAction, ExtraInfo: "failure - login_id=john@example.com", CreateAt: now - 48*time.Hour.Milliseconds()},
+	}
+
+	expectLookups := func() {
+		s.client.
+			EXPECT().
+			GetUserByEmail("john@example.com", "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("List the failed login attempts since the last login", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(mockConfig, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAudits(0, APILimitMaximum, "").
+			Return(mockAudits, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		expectLookups()
+
+		err := userLockoutListCmdF(s.client, newCmd(false), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&loginFailures{LoginID: "ghost", Failures: 1, LastFailureAt: now - 2500},
+			&loginFailures{LoginID: "john@example.com", UserID: mockUser.Id, Username: mockUser.Username, Failures: 3, LastFailureAt: now - 1000, Locked: true},
+		}, printer.GetLines())
+	})
+
+	s.Run("List only the locked out users", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(mockConfig, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAudits(0, APILimitMaximum, "").
+			Return(mockAudits[:3], &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("ghost", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		expectLookups()
+
+		err := userLockoutListCmdF(s.client, newCmd(true), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("Fail if the audits can't be retrieved", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(mockConfig, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAudits(0, APILimitMaximum, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := userLockoutListCmdF(s.client, newCmd(false), []string{})
+		s.Require().EqualError(err, "unable to get the audits: mock error")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestUserLockoutUnlockCmd() {
+	mockUser := &model.User{Id: model.NewId(), Username: "john", Email: "john@example.com"}
+
+	newCmd := func(password string, sendResetEmail bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("password", password, "")
+		cmd.Flags().Bool("send-reset-email", sendResetEmail, "")
+		return cmd
+	}
+
+	s.Run("Fail without an unlock method", func() {
+		printer.Clean()
+
+		err := userLockoutUnlockCmdF(s.client, newCmd("", false), []string{mockUser.Email})
+		s.Require().EqualError(err, "either --password or --send-reset-email must be provided")
+
+		err = userLockoutUnlockCmdF(s.client, newCmd("password", true), []string{mockUser.Email})
+		s.Require().EqualError(err, "either --password or --send-reset-email must be provided")
+	})
+
+	s.Run("Unlock a user setting a new password", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(mockUser.Email, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateUserPassword(mockUser.Id, "", "NewPassword1").
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := userLockoutUnlockCmdF(s.client, newCmd("NewPassword1", false), []string{mockUser.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{mockUser}, printer.GetLines())
+	})
+
+	s.Run("Unlock a user sending a password reset email", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(mockUser.Email, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SendPasswordResetEmail(mockUser.Email).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := userLockoutUnlockCmdF(s.client, newCmd("", true), []string{mockUser.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Equal([]interface{}{"unable to send the password reset email to user john: mock error"}, printer.GetErrorLines())
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var UserGroupMembershipsCmd = &cobra.Command{
+	Use:   "group-memberships [user]",
+	Short: "Show the memberships of a user",
+	Long: `Show all the teams, channels and groups a user belongs to, along with the roles of the user in each team and channel.
+Direct and group messages are not included. Use the --json flag to get the memberships as a tree, e.g. for access reviews.`,
+	Example: `  user group-memberships john.doe@example.com
+  user group-memberships john.doe --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(userGroupMembershipsCmdF),
+}
+
+func init() {
+	UserCmd.AddCommand(UserGroupMembershipsCmd)
+}
+
+type channelMembership struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Roles       string `json:"roles"`
+}
+
+type teamMembership struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	DisplayName string               `json:"display_name"`
+	Roles       string               `json:"roles"`
+	Channels    []*channelMembership `json:"channels"`
+}
+
+type groupMembership struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Source      string `json:"source"`
+}
+
+type userMemberships struct {
+	ID       string             `json:"id"`
+	Username string             `json:"username"`
+	Roles    string             `json:"roles"`
+	Teams    []*teamMembership  `json:"teams"`
+	Groups   []*groupMembership `json:"groups"`
+}
+
+const userMembershipsTemplate = `{{.Username}} ({{.Roles}})
+{{- range .Teams}}
+  team {{.Name}} ({{.Roles}})
+  {{- range .Channels}}
+    channel {{.Name}} ({{.Roles}})
+  {{- end}}
+{{- end}}
+{{- range .Groups}}
+  group {{.DisplayName}} ({{.Source}})
+{{- end}}`
+
+func getTeamMembership(c client.Client, user *model.User, team *model.Team, member *model.TeamMember) (*teamMembership, error) {
+	membership := &teamMembership{
+		ID:          team.Id,
+		Name:        team.Name,
+		DisplayName: team.DisplayName,
+		Roles:       member.Roles,
+		Channels:    []*channelMembership{},
+	}
+
+	channels, _, err := c.GetChannelsForTeamForUser(team.Id, user.Id, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the channels of team %q: %w", team.Name, err)
+	}
+
+	channelMembers, _, err := c.GetChannelMembersForUser(user.Id, team.Id, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the channel memberships of team %q: %w", team.Name, err)
+	}
+	roles := map[string]string{}
+	for _, channelMember := range channelMembers {
+		roles[channelMember.ChannelId] = channelMember.Roles
+	}
+
+	for _, channel := range channels {
+		// direct and group messages are returned along with the
+		// channels of every team
+		if channel.TeamId != team.Id {
+			continue
+		}
+		membership.Channels = append(membership.Channels, &channelMembership{
+			ID:          channel.Id,
+			Name:        channel.Name,
+			DisplayName: channel.DisplayName,
+			Type:        string(channel.Type),
+			Roles:       roles[channel.Id],
+		})
+	}
+	sort.Slice(membership.Channels, func(i, j int) bool {
+		return membership.Channels[i].Name < membership.Channels[j].Name
+	})
+
+	return membership, nil
+}
+
+func userGroupMembershipsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	teams, _, err := c.GetTeamsForUser(user.Id, "")
+	if err != nil {
+		return errors.Wrap(err, "unable to get the teams of the user")
+	}
+
+	teamMembers, _, err := c.GetTeamMembersForUser(user.Id, "")
+	if err != nil {
+		return errors.Wrap(err, "unable to get the team memberships of the user")
+	}
+	members := map[string]*model.TeamMember{}
+	for _, member := range teamMembers {
+		members[member.TeamId] = member
+	}
+
+	memberships := &userMemberships{
+		ID:       user.Id,
+		Username: user.Username,
+		Roles:    user.Roles,
+		Teams:    []*teamMembership{},
+		Groups:   []*groupMembership{},
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+	for _, team := range teams {
+		member, ok := members[team.Id]
+		if !ok || member.DeleteAt > 0 {
+			continue
+		}
+
+		membership, err := getTeamMembership(c, user, team, member)
+		if err != nil {
+			return err
+		}
+		memberships.Teams = append(memberships.Teams, membership)
+	}
+
+	groups, _, err := c.GetGroupsByUserId(user.Id)
+	if err != nil {
+		return errors.Wrap(err, "unable to get the groups of the user")
+	}
+	for _, group := range groups {
+		membership := &groupMembership{
+			ID:          group.Id,
+			DisplayName: group.DisplayName,
+			Source:      string(group.Source),
+		}
+		if group.Name != nil {
+			membership.Name = *group.Name
+		}
+		memberships.Groups = append(memberships.Groups, membership)
+	}
+
+	printer.PrintT(userMembershipsTemplate, memberships)
+
+	return nil
+}
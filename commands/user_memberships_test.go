@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserGroupMembershipsCmd() {
+	user := &model.User{Id: "user-id", Username: "john", Email: "john@example.com", Roles: "system_user"}
+	team1 := &model.Team{Id: "team-1", Name: "team1"}
+	team2 := &model.Team{Id: "team-2", Name: "team2"}
+
+	s.Run("Should show the teams, channels and groups of the user", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamsForUser(user.Id, "").
+			Return([]*model.Team{team2, team1}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamMembersForUser(user.Id, "").
+			Return([]*model.TeamMember{
+				{TeamId: team1.Id, UserId: user.Id, Roles: "team_user team_admin"},
+				{TeamId: team2.Id, UserId: user.Id, Roles: "team_user", DeleteAt: 1},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelsForTeamForUser(team1.Id, user.Id, false, "").
+			Return([]*model.Channel{
+				{Id: "channel-2", Name: "town-square", TeamId: team1.Id, Type: model.ChannelTypeOpen},
+				{Id: "channel-1", Name: "private", TeamId: team1.Id, Type: model.ChannelTypePrivate},
+				{Id: "dm", Name: "user-id__other-id", Type: model.ChannelTypeDirect},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetChannelMembersForUser(user.Id, team1.Id, "").
+			Return(model.ChannelMembers{
+				{ChannelId: "channel-1", Roles: "channel_user channel_admin"},
+				{ChannelId: "channel-2", Roles: "channel_user"},
+				{ChannelId: "dm", Roles: "channel_user"},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetGroupsByUserId(user.Id).
+			Return([]*model.Group{
+				{Id: "group-id", Name: model.NewString("developers"), DisplayName: "Developers", Source: model.GroupSourceLdap},
+			}, &model.Response{}, nil).
+			Times(1)
+
+		err := userGroupMembershipsCmdF(s.client, &cobra.Command{}, []string{user.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&userMemberships{
+			ID:       user.Id,
+			Username: user.Username,
+			Roles:    user.Roles,
+			Teams: []*teamMembership{
+				{
+					ID:    team1.Id,
+					Name:  team1.Name,
+					Roles: "team_user team_admin",
+					Channels: []*channelMembership{
+						{ID: "channel-1", Name: "private", Type: string(model.ChannelTypePrivate), Roles: "channel_user channel_admin"},
+						{ID: "channel-2", Name: "town-square", Type: string(model.ChannelTypeOpen), Roles: "channel_user"},
+					},
+				},
+			},
+			Groups: []*groupMembership{
+				{ID: "group-id", Name: "developers", DisplayName: "Developers", Source: string(model.GroupSourceLdap)},
+			},
+		}, printer.GetLines()[0])
+	})
+
+	s.Run("Should fail if the teams of the user cannot be fetched", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamsForUser(user.Id, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := userGroupMembershipsCmdF(s.client, &cobra.Command{}, []string{user.Email})
+		s.Require().EqualError(err, "unable to get the teams of the user: mock error")
+	})
+}
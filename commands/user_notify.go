@@ -0,0 +1,256 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var UserNotifySettingsCmd = &cobra.Command{
+	Use:   "notify-settings",
+	Short: "Management of the notification settings of users",
+}
+
+var UserNotifySettingsSetCmd = &cobra.Command{
+	Use:   "set [users]",
+	Short: "Set the notification settings of users",
+	Long: `Set the notification settings of users from a template. Users can be specified as arguments, or all active users can be selected with the --bulk flag, optionally only the ones created after a given time.
+The template is a JSON object with the settings to set, any setting not present in the template is left untouched:
+  email           whether to send email notifications: true or false
+  email_interval  how often to send the email notifications: immediately, fifteen or hour
+  desktop         the desktop notifications: all, mention or none
+  push            the mobile push notifications: all, mention or none
+  push_status     the status from which push notifications are sent: online, away or offline
+  mention_keys    the comma separated keywords that trigger mentions
+Only the settings whose value changes are reported and updated.`,
+	Example: `  # template.json: {"email": "true", "email_interval": "hour", "desktop": "mention", "mention_keys": "oncall,incident"}
+  user notify-settings set user1 user2@example.com --template template.json
+
+  # enforce the baseline on the users that joined this year, checking the changes first
+  user notify-settings set --bulk --created-after 2026-01-01T00:00:00+00:00 --template template.json --dry-run`,
+	RunE: withClient(userNotifySettingsSetCmdF),
+}
+
+func init() {
+	UserNotifySettingsSetCmd.Flags().String("template", "", "Path to the JSON file with the notification settings to set")
+	_ = UserNotifySettingsSetCmd.MarkFlagRequired("template")
+	UserNotifySettingsSetCmd.Flags().Bool("bulk", false, "Select all active users instead of the users provided as arguments")
+	UserNotifySettingsSetCmd.Flags().String("team", "", "If supplied along with --bulk, only users belonging to this team will be selected")
+	UserNotifySettingsSetCmd.Flags().String("created-after", "", "If supplied along with --bulk, only users created after this time will be selected, in ISO 8601 format")
+	UserNotifySettingsSetCmd.Flags().Bool("dry-run", false, "Report the changes without updating the users")
+
+	UserNotifySettingsCmd.AddCommand(UserNotifySettingsSetCmd)
+	UserCmd.AddCommand(UserNotifySettingsCmd)
+}
+
+// userNotifyProps maps the notification settings stored in the notify
+// props of the users to their valid values. A nil slice accepts any
+// value
+var userNotifyProps = map[string][]string{
+	model.EmailNotifyProp:       {"true", "false"},
+	model.DesktopNotifyProp:     {model.UserNotifyAll, model.UserNotifyMention, model.UserNotifyNone},
+	model.PushNotifyProp:        {model.UserNotifyAll, model.UserNotifyMention, model.UserNotifyNone},
+	model.PushStatusNotifyProp:  {model.StatusOnline, model.StatusAway, model.StatusOffline},
+	model.MentionKeysNotifyProp: nil,
+}
+
+// userEmailIntervals maps the email intervals accepted in the template
+// to the values stored in the preferences of the users
+var userEmailIntervals = map[string]string{
+	model.PreferenceEmailIntervalImmediately: model.PreferenceEmailIntervalNoBatchingSeconds,
+	model.PreferenceEmailIntervalFifteen:     model.PreferenceEmailIntervalFifteenAsSeconds,
+	model.PreferenceEmailIntervalHour:        model.PreferenceEmailIntervalHourAsSeconds,
+}
+
+type userNotifySettingChange struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Setting  string `json:"setting"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+// readUserNotifyTemplate reads and validates the template, returning
+// all the validation errors at once
+func readUserNotifyTemplate(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the template")
+	}
+
+	var template map[string]string
+	if err := json.Unmarshal(b, &template); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the template, it must be a JSON object of strings")
+	}
+	if len(template) == 0 {
+		return nil, errors.New("the template doesn't contain any setting")
+	}
+
+	var validationErrors []string
+	for setting, value := range template {
+		if setting == model.PreferenceNameEmailInterval {
+			if _, ok := userEmailIntervals[value]; !ok {
+				validationErrors = append(validationErrors, fmt.Sprintf("invalid value %q for setting %q", value, setting))
+			}
+			continue
+		}
+
+		valid, ok := userNotifyProps[setting]
+		if !ok {
+			validationErrors = append(validationErrors, fmt.Sprintf("unknown setting %q", setting))
+			continue
+		}
+		if setting == model.MentionKeysNotifyProp {
+			keys := []string{}
+			for _, key := range strings.Split(value, ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					keys = append(keys, key)
+				}
+			}
+			template[setting] = strings.Join(keys, ",")
+			continue
+		}
+		if !utils.StringInSlice(value, valid) {
+			validationErrors = append(validationErrors, fmt.Sprintf("invalid value %q for setting %q, it must be one of %s", value, setting, strings.Join(valid, ", ")))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		sort.Strings(validationErrors)
+		return nil, fmt.Errorf("the template is not valid:\n%s", strings.Join(validationErrors, "\n"))
+	}
+
+	return template, nil
+}
+
+// getEmailInterval returns the email interval of the user as named in
+// the templates, or the raw value if it doesn't match any of them
+func getEmailInterval(c client.Client, user *model.User) (string, error) {
+	preferences, _, err := c.GetPreferencesByCategory(user.Id, model.PreferenceCategoryNotifications)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the notification preferences")
+	}
+
+	for _, preference := range preferences {
+		if preference.Name != model.PreferenceNameEmailInterval {
+			continue
+		}
+		for name, seconds := range userEmailIntervals {
+			if preference.Value == seconds {
+				return name, nil
+			}
+		}
+		return preference.Value, nil
+	}
+	return "", nil
+}
+
+// applyUserNotifyTemplate updates the settings of the user whose value
+// changes, returning the changes
+func applyUserNotifyTemplate(c client.Client, user *model.User, template map[string]string, dryRun bool) ([]*userNotifySettingChange, error) {
+	settings := make([]string, 0, len(template))
+	for setting := range template {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+
+	notifyProps := model.StringMap{}
+	for key, value := range user.NotifyProps {
+		notifyProps[key] = value
+	}
+
+	changes := []*userNotifySettingChange{}
+	propsChanged := false
+	var emailInterval *model.Preference
+	for _, setting := range settings {
+		value := template[setting]
+
+		var old string
+		if setting == model.PreferenceNameEmailInterval {
+			var err error
+			if old, err = getEmailInterval(c, user); err != nil {
+				return nil, err
+			}
+			if old != value {
+				emailInterval = &model.Preference{
+					UserId:   user.Id,
+					Category: model.PreferenceCategoryNotifications,
+					Name:     model.PreferenceNameEmailInterval,
+					Value:    userEmailIntervals[value],
+				}
+			}
+		} else {
+			old = notifyProps[setting]
+			if old != value {
+				notifyProps[setting] = value
+				propsChanged = true
+			}
+		}
+
+		if old != value {
+			changes = append(changes, &userNotifySettingChange{ID: user.Id, Username: user.Username, Setting: setting, Old: old, New: value})
+		}
+	}
+
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+
+	// the patch replaces all the notify props, so the whole map with
+	// the template applied is sent
+	if propsChanged {
+		if _, _, err := c.PatchUser(user.Id, &model.UserPatch{NotifyProps: notifyProps}); err != nil {
+			return nil, err
+		}
+	}
+	if emailInterval != nil {
+		if _, err := c.UpdatePreferences(user.Id, model.Preferences{*emailInterval}); err != nil {
+			return nil, errors.Wrap(err, "failed to update the email interval")
+		}
+	}
+
+	return changes, nil
+}
+
+func userNotifySettingsSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	templatePath, _ := cmd.Flags().GetString("template")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	template, err := readUserNotifyTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+
+	users, unresolved, err := getBulkUserTargets(c, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	report := &userUpdateReport{Failed: unresolved}
+	for _, user := range users {
+		changes, err := applyUserNotifyTemplate(c, user, template, dryRun)
+		if err != nil {
+			report.fail(fmt.Sprintf("unable to update the notification settings of user %q: %s", user.Username, err))
+			continue
+		}
+		report.add(len(changes))
+		for _, change := range changes {
+			printer.PrintT(`{{.Username}}: {{.Setting}} {{printf "%q" .Old}} -> {{printf "%q" .New}}`, change)
+		}
+	}
+
+	return report.finish(dryRun)
+}
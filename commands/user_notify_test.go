@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestUserNotifySettingsSetCmd() {
+	newCmd := func(template string, bulk, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("template", template, "")
+		cmd.Flags().Bool("bulk", bulk, "")
+		cmd.Flags().String("team", "", "")
+		cmd.Flags().String("created-after", "", "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	writeTemplate := func(dir, content string) string {
+		path := filepath.Join(dir, "template.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	s.Run("Should update the changed settings of the users", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeTemplate(tmp, `{"desktop": "mention", "mention_keys": "oncall, incident", "email_interval": "hour"}`)
+		user := &model.User{Id: "user-id", Username: "john", Email: "john@example.com", NotifyProps: model.StringMap{"desktop": "all", "push": "all"}}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetPreferencesByCategory(user.Id, model.PreferenceCategoryNotifications).
+			Return(model.Preferences{{UserId: user.Id, Category: model.PreferenceCategoryNotifications, Name: model.PreferenceNameEmailInterval, Value: "30"}}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchUser(user.Id, &model.UserPatch{NotifyProps: model.StringMap{"desktop": "mention", "push": "all", "mention_keys": "oncall,incident"}}).
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdatePreferences(user.Id, model.Preferences{{UserId: user.Id, Category: model.PreferenceCategoryNotifications, Name: model.PreferenceNameEmailInterval, Value: "3600"}}).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := userNotifySettingsSetCmdF(s.client, newCmd(path, false, false), []string{user.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 4)
+		s.Require().Equal(&userNotifySettingChange{ID: user.Id, Username: user.Username, Setting: "desktop", Old: "all", New: "mention"}, printer.GetLines()[0])
+		s.Require().Equal(&userNotifySettingChange{ID: user.Id, Username: user.Username, Setting: "email_interval", Old: "immediately", New: "hour"}, printer.GetLines()[1])
+		s.Require().Equal(&userNotifySettingChange{ID: user.Id, Username: user.Username, Setting: "mention_keys", Old: "", New: "oncall,incident"}, printer.GetLines()[2])
+	})
+
+	s.Run("Should report the changes of the bulk users without updating them with dry run", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeTemplate(tmp, `{"push_status": "offline"}`)
+		john := &model.User{Id: "john-id", Username: "john", NotifyProps: model.StringMap{"push_status": "away"}}
+		jane := &model.User{Id: "jane-id", Username: "jane", NotifyProps: model.StringMap{"push_status": "offline"}}
+		bot := &model.User{Id: "bot-id", Username: "bot", IsBot: true}
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{john, jane, bot}, &model.Response{}, nil).
+			Times(1)
+
+		err := userNotifySettingsSetCmdF(s.client, newCmd(path, true, true), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&userNotifySettingChange{ID: john.Id, Username: john.Username, Setting: "push_status", Old: "away", New: "offline"}, printer.GetLines()[0])
+	})
+
+	s.Run("Should count the users that can't be found as failed", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeTemplate(tmp, `{"desktop": "mention"}`)
+
+		s.client.
+			EXPECT().
+			GetUserByEmail("unknown", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername("unknown", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUser("unknown", "").
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("not found")).
+			Times(1)
+
+		err := userNotifySettingsSetCmdF(s.client, newCmd(path, false, false), []string{"unknown"})
+		s.Require().EqualError(err, "unable to update 1 users")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal(&userUpdateReport{Failed: 1}, printer.GetLines()[0])
+	})
+
+	s.Run("Should validate the whole template", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeTemplate(tmp, `{"desktop": "sometimes", "email_interval": "weekly", "sound": "bing"}`)
+
+		err := userNotifySettingsSetCmdF(s.client, newCmd(path, true, false), []string{})
+		s.Require().EqualError(err, `the template is not valid:
+invalid value "sometimes" for setting "desktop", it must be one of all, mention, none
+invalid value "weekly" for setting "email_interval"
+unknown setting "sound"`)
+	})
+
+	s.Run("Should fail to use created after without bulk", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		path := writeTemplate(tmp, `{"desktop": "mention"}`)
+		cmd := newCmd(path, false, false)
+		_ = cmd.Flags().Set("created-after", "2026-01-01T00:00:00+00:00")
+
+		err := userNotifySettingsSetCmdF(s.client, cmd, []string{"john"})
+		s.Require().EqualError(err, "the --created-after flag can only be used along with --bulk")
+	})
+}
@@ -257,6 +257,57 @@ func (s *MmctlUnitTestSuite) TestDeactivateUserCmd() {
 		s.Require().Equal(fmt.Errorf("unable to change activation status of user: %v", mockUser.Id).Error(), printer.GetErrorLines()[0])
 	})
 
+	s.Run("Deactivate users concurrently", func() {
+		printer.Clean()
+		users := []*model.User{
+			{Id: "user1", Email: "user1@example.com"},
+			{Id: "user2", Email: "user2@example.com"},
+			{Id: "user3", Email: "user3@example.com"},
+		}
+		for _, user := range users {
+			s.client.
+				EXPECT().
+				GetUserByEmail(user.Email, "").
+				Return(user, &model.Response{}, nil).
+				Times(1)
+		}
+
+		s.client.
+			EXPECT().
+			UpdateUserActive("user1", false).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateUserActive("user2", false).
+			Return(&model.Response{StatusCode: http.StatusBadRequest}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			UpdateUserActive("user3", false).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		addConcurrencyFlag(cmd, "users")
+		_ = cmd.Flags().Set("concurrency", "2")
+
+		err := userDeactivateCmdF(s.client, cmd, []string{"user1@example.com", "user2@example.com", "user3@example.com"})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{"unable to change activation status of user: user2"}, printer.GetErrorLines())
+	})
+
+	s.Run("Fail with a concurrency over the maximum", func() {
+		cmd := &cobra.Command{}
+		addConcurrencyFlag(cmd, "users")
+		_ = cmd.Flags().Set("concurrency", "100")
+
+		err := userDeactivateCmdF(s.client, cmd, []string{"user1@example.com"})
+		s.Require().EqualError(err, "the concurrency cannot be greater than 32")
+	})
+
 	s.Run("Deactivate SSO user", func() {
 		printer.Clean()
 		emailArg := "example@example.com"
@@ -2245,6 +2296,143 @@ func (s *MmctlUnitTestSuite) TestVerifyUserEmailWithoutTokenCmd() {
 	})
 }
 
+func (s *MmctlUnitTestSuite) TestUserEmailVerifyCmd() {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("bulk", false, "")
+		cmd.Flags().String("team", "", "")
+		cmd.Flags().Bool("resend", false, "")
+		cmd.Flags().Bool("mark-verified", false, "")
+		return cmd
+	}
+
+	verifiedUser := &model.User{Id: "user1", Username: "user1", Email: "user1@example.com", EmailVerified: true}
+	unverifiedUser := &model.User{Id: "user2", Username: "user2", Email: "user2@example.com"}
+	botUser := &model.User{Id: "bot", Username: "bot", IsBot: true}
+	deletedUser := &model.User{Id: "deleted", Username: "deleted", DeleteAt: 1}
+
+	s.Run("Should fail without users nor --bulk", func() {
+		printer.Clean()
+
+		err := userEmailVerifyCmdF(s.client, newCmd(), []string{})
+		s.Require().EqualError(err, "expected at least one user or the --bulk flag. See help text for details")
+	})
+
+	s.Run("Should fail with both --resend and --mark-verified", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("resend", "true")
+		_ = cmd.Flags().Set("mark-verified", "true")
+
+		err := userEmailVerifyCmdF(s.client, cmd, []string{"user1"})
+		s.Require().EqualError(err, "the --resend and --mark-verified flags cannot be used together")
+	})
+
+	s.Run("Should report the verification coverage of all users", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("bulk", "true")
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{verifiedUser, unverifiedUser, botUser, deletedUser}, &model.Response{}, nil).
+			Times(1)
+
+		err := userEmailVerifyCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(unverifiedUser, printer.GetLines()[0])
+		s.Require().Equal(emailVerificationCoverage{Total: 2, Verified: 1, Coverage: 50}, printer.GetLines()[1])
+	})
+
+	s.Run("Should resend the verification email to the unverified users of a team", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("bulk", "true")
+		_ = cmd.Flags().Set("team", "team-id")
+		_ = cmd.Flags().Set("resend", "true")
+		mockTeam := &model.Team{Id: "team-id"}
+
+		s.client.
+			EXPECT().
+			GetTeam(mockTeam.Id, "").
+			Return(mockTeam, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsersInTeam(mockTeam.Id, 0, APILimitMaximum, "").
+			Return([]*model.User{verifiedUser, unverifiedUser}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SendVerificationEmail(unverifiedUser.Email).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := userEmailVerifyCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(unverifiedUser, printer.GetLines()[0])
+		s.Require().Equal(emailVerificationCoverage{Total: 2, Verified: 1, Coverage: 50}, printer.GetLines()[1])
+	})
+
+	s.Run("Should mark the given users as verified", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("mark-verified", "true")
+		updatedUser := &model.User{Id: unverifiedUser.Id, Username: unverifiedUser.Username, Email: unverifiedUser.Email, EmailVerified: true}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(unverifiedUser.Email, "").
+			Return(unverifiedUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			VerifyUserEmailWithoutToken(unverifiedUser.Id).
+			Return(updatedUser, &model.Response{}, nil).
+			Times(1)
+
+		err := userEmailVerifyCmdF(s.client, cmd, []string{unverifiedUser.Email})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(updatedUser, printer.GetLines()[0])
+		s.Require().Equal(emailVerificationCoverage{Total: 1, Verified: 1, Coverage: 100}, printer.GetLines()[1])
+	})
+
+	s.Run("Should report users whose verification email could not be sent", func() {
+		printer.Clean()
+		cmd := newCmd()
+		_ = cmd.Flags().Set("resend", "true")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(unverifiedUser.Email, "").
+			Return(unverifiedUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			SendVerificationEmail(unverifiedUser.Email).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := userEmailVerifyCmdF(s.client, cmd, []string{unverifiedUser.Email})
+		s.Require().EqualError(err, "unable to verify or send the verification email to 1 users")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Contains(printer.GetErrorLines()[0], "unable to send verification email to user user2")
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(emailVerificationCoverage{Total: 1, Verified: 0, Failed: 1, Coverage: 0}, printer.GetLines()[0])
+	})
+}
+
 func (s *MmctlUnitTestSuite) TestUserConvertCmd() {
 	s.Run("convert user to a bot", func() {
 		printer.Clean()
@@ -0,0 +1,258 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/ldap"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var UpcomingDeactivationsCmd = &cobra.Command{
+	Use:   "upcoming-deactivations",
+	Short: "Preview the users an LDAP sync would deactivate",
+	Long: `List the active users authenticated with AD/LDAP that the next synchronization would deactivate, as they are no longer found in the directory with the user filter of the server.
+The directory is queried from the machine running mmctl with the AD/LDAP settings of the server. The bind password isn't returned by the API, so it has to be given with --bind-password-file unless the command is run in local mode.`,
+	Example: `  user upcoming-deactivations --bind-password-file bind-password.txt`,
+	Args:    cobra.NoArgs,
+	RunE:    withClient(upcomingDeactivationsCmdF),
+}
+
+func init() {
+	UpcomingDeactivationsCmd.Flags().String("bind-password-file", "", "File containing the password of the AD/LDAP bind user")
+
+	UserCmd.AddCommand(UpcomingDeactivationsCmd)
+}
+
+// ldapDirectory gives the ID attribute values of the users matching
+// the user filter of the AD/LDAP settings, and tests the filters
+// against a user
+type ldapDirectory interface {
+	userIDs() (map[string]bool, error)
+	testUser(login string) (*ldapUserTest, error)
+	close()
+}
+
+// newLDAPDirectory connects to the AD/LDAP server of the settings, and
+// is replaced in the tests
+var newLDAPDirectory = func(settings *model.LdapSettings, bindPassword string) (ldapDirectory, error) {
+	return dialLDAPDirectory(settings, bindPassword)
+}
+
+type ldapConnDirectory struct {
+	conn     *ldap.Conn
+	settings *model.LdapSettings
+}
+
+func dialLDAPDirectory(settings *model.LdapSettings, bindPassword string) (*ldapConnDirectory, error) {
+	server := *settings.LdapServer
+	addr := net.JoinHostPort(server, strconv.Itoa(*settings.LdapPort))
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: *settings.SkipCertificateVerification,
+		ServerName:         server,
+	}
+
+	var conn *ldap.Conn
+	var err error
+	switch *settings.ConnectionSecurity {
+	case model.ConnSecurityTLS:
+		conn, err = ldap.DialTLS("tcp", addr, tlsConfig)
+	case model.ConnSecurityStarttls:
+		if conn, err = ldap.Dial("tcp", addr); err == nil {
+			if err = conn.StartTLS(tlsConfig); err != nil {
+				conn.Close()
+			}
+		}
+	default:
+		conn, err = ldap.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the AD/LDAP server %s", addr)
+	}
+
+	if timeout := *settings.QueryTimeout; timeout > 0 {
+		conn.SetTimeout(time.Duration(timeout) * time.Second)
+	}
+	if err := conn.Bind(*settings.BindUsername, bindPassword); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to bind to the AD/LDAP server")
+	}
+	return &ldapConnDirectory{conn: conn, settings: settings}, nil
+}
+
+// ldapUserFilter returns the filter of the users the synchronization
+// keeps, the ones with an ID that match the user filter
+func ldapUserFilter(settings *model.LdapSettings) string {
+	filter := "(" + *settings.IdAttribute + "=*)"
+	userFilter := ldapFilter(*settings.UserFilter)
+	if userFilter == "" {
+		return filter
+	}
+	return "(&" + filter + userFilter + ")"
+}
+
+// ldapFilter wraps a filter of the settings in parentheses, as they
+// can be given without them
+func ldapFilter(filter string) string {
+	filter = strings.TrimSpace(filter)
+	if filter != "" && !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	return filter
+}
+
+func (d *ldapConnDirectory) userIDs() (map[string]bool, error) {
+	idAttribute := *d.settings.IdAttribute
+	request := ldap.NewSearchRequest(
+		*d.settings.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		ldapUserFilter(d.settings),
+		[]string{idAttribute},
+		nil,
+	)
+
+	pageSize := *d.settings.MaxPageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	result, err := d.conn.SearchWithPaging(request, uint32(pageSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search the AD/LDAP users")
+	}
+
+	ids := map[string]bool{}
+	for _, entry := range result.Entries {
+		if id := entry.GetAttributeValue(idAttribute); id != "" {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+func (d *ldapConnDirectory) close() {
+	d.conn.Close()
+}
+
+type upcomingDeactivation struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	AuthData string `json:"auth_data"`
+}
+
+func userAuthData(user *model.User) string {
+	if user.AuthData == nil {
+		return ""
+	}
+	return *user.AuthData
+}
+
+// getActiveLDAPUsers returns the active users authenticated with
+// AD/LDAP
+func getActiveLDAPUsers(c client.Client) ([]*model.User, error) {
+	filter := &userListFilter{}
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		pageUsers, err := filter.getPage(c, page, APILimitMaximum)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range pageUsers {
+			if user.AuthService == model.UserAuthServiceLdap && user.DeleteAt == 0 {
+				users = append(users, user)
+			}
+		}
+		if len(pageUsers) < APILimitMaximum {
+			return users, nil
+		}
+	}
+}
+
+// getLDAPBindPassword returns the bind password of the settings, or
+// the one of --bind-password-file, as the server hides it
+func getLDAPBindPassword(cmd *cobra.Command, settings *model.LdapSettings) (string, error) {
+	bindPassword := *settings.BindPassword
+	if passwordFile, _ := cmd.Flags().GetString("bind-password-file"); passwordFile != "" {
+		if err := readSecretFromFile(passwordFile, &bindPassword); err != nil {
+			return "", errors.Wrap(err, "could not read the bind password")
+		}
+	} else if bindPassword == model.FakeSetting {
+		return "", errors.New("the bind password isn't returned by the server, give it with --bind-password-file")
+	}
+	return bindPassword, nil
+}
+
+func upcomingDeactivationsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	config, _, err := c.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the config")
+	}
+	settings := &config.LdapSettings
+	if !*settings.Enable || !*settings.EnableSync {
+		return errors.New("the AD/LDAP synchronization is not enabled on the server")
+	}
+	if *settings.IdAttribute == "" {
+		return errors.New("the AD/LDAP ID attribute is not set on the server")
+	}
+
+	bindPassword, err := getLDAPBindPassword(cmd, settings)
+	if err != nil {
+		return err
+	}
+
+	users, err := getActiveLDAPUsers(c)
+	if err != nil {
+		return err
+	}
+
+	directory, err := newLDAPDirectory(settings, bindPassword)
+	if err != nil {
+		return err
+	}
+	defer directory.close()
+
+	ids, err := directory.userIDs()
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, user := range users {
+		if ids[userAuthData(user)] {
+			continue
+		}
+		found++
+		printer.PrintT("{{.Username}} ({{.Email}}) is no longer found in the directory as {{.AuthData}}", &upcomingDeactivation{
+			ID:       user.Id,
+			Username: user.Username,
+			Email:    user.Email,
+			AuthData: userAuthData(user),
+		})
+	}
+
+	switch {
+	case found == 0:
+		printer.Print(fmt.Sprintf("None of the %d AD/LDAP users would be deactivated", len(users)))
+	case found == len(users) && len(ids) > 0:
+		// the values of binary attributes are stored in another form by
+		// the server, so they never match
+		printer.PrintWarning(fmt.Sprintf("none of the AD/LDAP users were found in the directory although it has %d users, check that the values of the ID attribute %s are stored as is by the server", len(ids), *settings.IdAttribute))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+// staticLDAPDirectory is a directory with a fixed set of user IDs, the
+// users it has matching all the filters
+type staticLDAPDirectory map[string]bool
+
+func (d staticLDAPDirectory) userIDs() (map[string]bool, error) { return d, nil }
+func (d staticLDAPDirectory) close()                            {}
+func (d staticLDAPDirectory) testUser(login string) (*ldapUserTest, error) {
+	return &ldapUserTest{Login: login, Found: d[login], UserFilter: d[login]}, nil
+}
+
+func (s *MmctlUnitTestSuite) TestUpcomingDeactivationsCmd() {
+	ldapConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.LdapSettings.Enable = model.NewBool(true)
+		config.LdapSettings.EnableSync = model.NewBool(true)
+		config.LdapSettings.IdAttribute = model.NewString("uid")
+		config.LdapSettings.BindPassword = model.NewString(model.FakeSetting)
+		return config
+	}
+
+	newCmd := func(passwordFile string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("bind-password-file", passwordFile, "")
+		return cmd
+	}
+
+	s.Run("Should list the LDAP users missing from the directory", func() {
+		printer.Clean()
+		tmp, _ := ioutil.TempDir("", "mmctl-")
+		defer os.RemoveAll(tmp)
+		passwordFile := filepath.Join(tmp, "password")
+		s.Require().NoError(ioutil.WriteFile(passwordFile, []byte("bind-secret\n"), 0600))
+
+		originalNewDirectory := newLDAPDirectory
+		defer func() { newLDAPDirectory = originalNewDirectory }()
+		var bindPassword string
+		newLDAPDirectory = func(_ *model.LdapSettings, password string) (ldapDirectory, error) {
+			bindPassword = password
+			return staticLDAPDirectory{"kept-guid": true}, nil
+		}
+
+		kept := &model.User{Id: "kept-id", Username: "kept", AuthService: model.UserAuthServiceLdap, AuthData: model.NewString("kept-guid")}
+		gone := &model.User{Id: "gone-id", Username: "gone", Email: "gone@example.com", AuthService: model.UserAuthServiceLdap, AuthData: model.NewString("gone-guid")}
+		deactivated := &model.User{Id: "deactivated-id", AuthService: model.UserAuthServiceLdap, AuthData: model.NewString("old-guid"), DeleteAt: 1}
+		emailUser := &model.User{Id: "email-id", Username: "local"}
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(ldapConfig(), &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUsers(0, APILimitMaximum, "").
+			Return([]*model.User{kept, gone, deactivated, emailUser}, &model.Response{}, nil).
+			Times(1)
+
+		err := upcomingDeactivationsCmdF(s.client, newCmd(passwordFile), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal("bind-secret", bindPassword)
+		s.Require().Equal([]interface{}{&upcomingDeactivation{ID: gone.Id, Username: gone.Username, Email: gone.Email, AuthData: "gone-guid"}}, printer.GetLines())
+	})
+
+	s.Run("Should fail without the bind password", func() {
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(ldapConfig(), &model.Response{}, nil).
+			Times(1)
+
+		err := upcomingDeactivationsCmdF(s.client, newCmd(""), []string{})
+		s.Require().EqualError(err, "the bind password isn't returned by the server, give it with --bind-password-file")
+	})
+
+	s.Run("Should fail if the synchronization is disabled", func() {
+		config := ldapConfig()
+		config.LdapSettings.EnableSync = model.NewBool(false)
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{}, nil).
+			Times(1)
+
+		err := upcomingDeactivationsCmdF(s.client, newCmd(""), []string{})
+		s.Require().EqualError(err, "the AD/LDAP synchronization is not enabled on the server")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestLDAPUserFilter() {
+	settings := &model.LdapSettings{IdAttribute: model.NewString("uid"), UserFilter: model.NewString("")}
+	s.Require().Equal("(uid=*)", ldapUserFilter(settings))
+
+	settings.UserFilter = model.NewString("memberOf=cn=mattermost,ou=groups,dc=example,dc=com")
+	s.Require().Equal("(&(uid=*)(memberOf=cn=mattermost,ou=groups,dc=example,dc=com))", ldapUserFilter(settings))
+}
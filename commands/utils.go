@@ -5,11 +5,16 @@ package commands
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mmctl/v6/printer"
 
 	"github.com/pkg/errors"
 )
@@ -89,3 +94,47 @@ func addToZip(zipWriter *zip.Writer, basedir, path string) error {
 
 	return nil
 }
+
+// fileSHA256 returns the hex encoded SHA-256 digest of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("cannot read file %q: %w", path, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkFileSHA256 computes the SHA-256 digest of a file and, if an
+// expected digest is provided, fails when they don't match
+func checkFileSHA256(path, expected string) (string, error) {
+	digest, err := fileSHA256(path)
+	if err != nil {
+		return "", err
+	}
+
+	return digest, verifySHA256(path, digest, expected)
+}
+
+// verifySHA256 fails if an expected digest is provided and it doesn't
+// match the computed one
+func verifySHA256(name, digest, expected string) error {
+	if expected != "" && !strings.EqualFold(digest, expected) {
+		return fmt.Errorf("SHA-256 mismatch for %q: expected %s, got %s", name, strings.ToLower(expected), digest)
+	}
+
+	return nil
+}
+
+func printSHA256(path, digest string) {
+	printer.PrintT("SHA-256 of {{.File}}: {{.SHA256}}", struct {
+		File   string `json:"file"`
+		SHA256 string `json:"sha256"`
+	}{path, digest})
+}
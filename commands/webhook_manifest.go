@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var CreateWebhooksCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create webhooks from a manifest",
+	Long: `Create the incoming and outgoing webhooks declared in a YAML manifest. The tokens of the outgoing webhooks are only shown once, when they are created.
+The webhooks that already exist are skipped and reported as such, so the manifest can be applied again after a partial failure. An incoming webhook exists if its channel has one with the same display name, and an outgoing webhook if its team has one with the same channel and display name.
+The manifest has the following format:
+
+  incoming:
+    - channel: myteam:alerts
+      user: bot-user
+      display_name: Alerts
+      description: Alerts from the monitoring
+      icon_url: https://example.com/alerts.png
+      lock_to_channel: true
+  outgoing:
+    - team: myteam
+      channel: builds
+      user: bot-user
+      display_name: Builds
+      trigger_words: [build, deploy]
+      trigger_when: start
+      callback_urls: [https://ci.example.com/hooks/mattermost]
+      content_type: application/json`,
+	Example: "  webhook create --from-file webhooks.yaml",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(createWebhooksCmdF),
+}
+
+func init() {
+	CreateWebhooksCmd.Flags().String("from-file", "", "Path of the YAML manifest of the webhooks (required)")
+	_ = CreateWebhooksCmd.MarkFlagRequired("from-file")
+
+	WebhookCmd.AddCommand(CreateWebhooksCmd)
+}
+
+// webhookManifest is the YAML manifest that webhooks can be created
+// from
+type webhookManifest struct {
+	Incoming []*incomingWebhookManifest `yaml:"incoming"`
+	Outgoing []*outgoingWebhookManifest `yaml:"outgoing"`
+}
+
+type incomingWebhookManifest struct {
+	Channel       string `yaml:"channel"`
+	User          string `yaml:"user"`
+	DisplayName   string `yaml:"display_name"`
+	Description   string `yaml:"description"`
+	IconURL       string `yaml:"icon_url"`
+	LockToChannel bool   `yaml:"lock_to_channel"`
+}
+
+type outgoingWebhookManifest struct {
+	Team         string   `yaml:"team"`
+	Channel      string   `yaml:"channel"`
+	User         string   `yaml:"user"`
+	DisplayName  string   `yaml:"display_name"`
+	Description  string   `yaml:"description"`
+	IconURL      string   `yaml:"icon_url"`
+	TriggerWords []string `yaml:"trigger_words"`
+	TriggerWhen  string   `yaml:"trigger_when"`
+	CallbackURLs []string `yaml:"callback_urls"`
+	ContentType  string   `yaml:"content_type"`
+}
+
+// createdWebhook is the output of a created or existing webhook, with
+// the URL of incoming webhooks and the token of the created outgoing
+// ones
+type createdWebhook struct {
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	URL         string `json:"url,omitempty"`
+	Token       string `json:"token,omitempty"`
+}
+
+func loadWebhookManifest(path string) (*webhookManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the webhook manifest")
+	}
+	defer f.Close()
+
+	var m webhookManifest
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil {
+		return nil, errors.Wrapf(err, "invalid webhook manifest %s", path)
+	}
+
+	for i, hook := range m.Incoming {
+		if hook.Channel == "" || hook.User == "" || hook.DisplayName == "" {
+			return nil, fmt.Errorf("invalid webhook manifest %s: incoming webhook %d must have a channel, a user and a display name", path, i+1)
+		}
+	}
+	for i, hook := range m.Outgoing {
+		if hook.Team == "" || hook.User == "" || hook.DisplayName == "" {
+			return nil, fmt.Errorf("invalid webhook manifest %s: outgoing webhook %d must have a team, a user and a display name", path, i+1)
+		}
+		if len(hook.CallbackURLs) == 0 {
+			return nil, fmt.Errorf("invalid webhook manifest %s: outgoing webhook %d has no callback urls", path, i+1)
+		}
+		if len(hook.TriggerWords) == 0 && hook.Channel == "" {
+			return nil, fmt.Errorf("invalid webhook manifest %s: outgoing webhook %d must have trigger words or a channel", path, i+1)
+		}
+		if _, err := parseTriggerWhen(hook.TriggerWhen); err != nil {
+			return nil, fmt.Errorf("invalid webhook manifest %s: outgoing webhook %d: %w", path, i+1, err)
+		}
+	}
+	if len(m.Incoming) == 0 && len(m.Outgoing) == 0 {
+		return nil, fmt.Errorf("invalid webhook manifest %s: no webhooks to create", path)
+	}
+	return &m, nil
+}
+
+// parseTriggerWhen converts the trigger when of an outgoing webhook,
+// which defaults to exact
+func parseTriggerWhen(triggerWhen string) (int, error) {
+	switch triggerWhen {
+	case "", "exact":
+		return 0, nil
+	case "start":
+		return 1, nil
+	}
+	return 0, fmt.Errorf("invalid trigger when %q, must be exact or start", triggerWhen)
+}
+
+// findIncomingWebhook returns the incoming webhook of the channel with
+// the display name, or nil if there is none
+func findIncomingWebhook(c client.Client, channel *model.Channel, displayName string) (*model.IncomingWebhook, error) {
+	for page := 0; ; page++ {
+		hooks, _, err := c.GetIncomingWebhooksForTeam(channel.TeamId, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get the incoming webhooks")
+		}
+		for _, hook := range hooks {
+			if hook.ChannelId == channel.Id && hook.DisplayName == displayName {
+				return hook, nil
+			}
+		}
+		if len(hooks) < APILimitMaximum {
+			return nil, nil
+		}
+	}
+}
+
+// findOutgoingWebhook returns the outgoing webhook of the team with the
+// channel and display name, or nil if there is none
+func findOutgoingWebhook(c client.Client, teamID, channelID, displayName string) (*model.OutgoingWebhook, error) {
+	for page := 0; ; page++ {
+		hooks, _, err := c.GetOutgoingWebhooksForTeam(teamID, page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get the outgoing webhooks")
+		}
+		for _, hook := range hooks {
+			if hook.ChannelId == channelID && hook.DisplayName == displayName {
+				return hook, nil
+			}
+		}
+		if len(hooks) < APILimitMaximum {
+			return nil, nil
+		}
+	}
+}
+
+func (hook *incomingWebhookManifest) create(c client.Client, siteURL string) (*createdWebhook, error) {
+	channel := getChannelFromChannelArg(c, hook.Channel)
+	if channel == nil {
+		return nil, fmt.Errorf("unable to find channel %q", hook.Channel)
+	}
+	user := getUserFromUserArg(c, hook.User)
+	if user == nil {
+		return nil, fmt.Errorf("unable to find user %q", hook.User)
+	}
+
+	existing, err := findIncomingWebhook(c, channel, hook.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &createdWebhook{
+			Type:        "incoming",
+			Status:      "exists",
+			ID:          existing.Id,
+			DisplayName: existing.DisplayName,
+			URL:         siteURL + "/hooks/" + existing.Id,
+		}, nil
+	}
+
+	created, _, err := c.CreateIncomingWebhook(&model.IncomingWebhook{
+		ChannelId:     channel.Id,
+		DisplayName:   hook.DisplayName,
+		Description:   hook.Description,
+		IconURL:       hook.IconURL,
+		ChannelLocked: hook.LockToChannel,
+		Username:      user.Username,
+		UserId:        user.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdWebhook{
+		Type:        "incoming",
+		Status:      "created",
+		ID:          created.Id,
+		DisplayName: created.DisplayName,
+		URL:         siteURL + "/hooks/" + created.Id,
+	}, nil
+}
+
+func (hook *outgoingWebhookManifest) create(c client.Client) (*createdWebhook, error) {
+	team := getTeamFromTeamArg(c, hook.Team)
+	if team == nil {
+		return nil, fmt.Errorf("unable to find team %q", hook.Team)
+	}
+	user := getUserFromUserArg(c, hook.User)
+	if user == nil {
+		return nil, fmt.Errorf("unable to find user %q", hook.User)
+	}
+	triggerWhen, _ := parseTriggerWhen(hook.TriggerWhen)
+
+	outgoingWebhook := &model.OutgoingWebhook{
+		CreatorId:    user.Id,
+		Username:     user.Username,
+		TeamId:       team.Id,
+		TriggerWords: hook.TriggerWords,
+		TriggerWhen:  triggerWhen,
+		CallbackURLs: hook.CallbackURLs,
+		DisplayName:  hook.DisplayName,
+		Description:  hook.Description,
+		ContentType:  hook.ContentType,
+		IconURL:      hook.IconURL,
+	}
+	if hook.Channel != "" {
+		channelArg := hook.Channel
+		if !strings.Contains(channelArg, ":") && !model.IsValidId(channelArg) {
+			channelArg = team.Id + ":" + channelArg
+		}
+		channel := getChannelFromChannelArg(c, channelArg)
+		if channel == nil {
+			return nil, fmt.Errorf("unable to find channel %q", hook.Channel)
+		}
+		outgoingWebhook.ChannelId = channel.Id
+	}
+
+	existing, err := findOutgoingWebhook(c, team.Id, outgoingWebhook.ChannelId, hook.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &createdWebhook{
+			Type:        "outgoing",
+			Status:      "exists",
+			ID:          existing.Id,
+			DisplayName: existing.DisplayName,
+		}, nil
+	}
+
+	created, _, err := c.CreateOutgoingWebhook(outgoingWebhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdWebhook{
+		Type:        "outgoing",
+		Status:      "created",
+		ID:          created.Id,
+		DisplayName: created.DisplayName,
+		Token:       created.Token,
+	}, nil
+}
+
+func createWebhooksCmdF(c client.Client, command *cobra.Command, args []string) error {
+	path, _ := command.Flags().GetString("from-file")
+	manifest, err := loadWebhookManifest(path)
+	if err != nil {
+		return err
+	}
+
+	var siteURL string
+	if len(manifest.Incoming) > 0 {
+		clientConfig, _, err := c.GetOldClientConfig("")
+		if err != nil {
+			return errors.Wrap(err, "unable to get the site URL")
+		}
+		siteURL = strings.TrimSuffix(clientConfig["SiteURL"], "/")
+	}
+
+	failed, tokens := 0, 0
+	for i, hook := range manifest.Incoming {
+		result, err := hook.create(c, siteURL)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to create incoming webhook %d: %s", i+1, err))
+			failed++
+			continue
+		}
+		printer.PrintT("Incoming webhook {{.DisplayName}} {{.Status}}, ID: {{.ID}}, URL: {{.URL}}", result)
+	}
+	for i, hook := range manifest.Outgoing {
+		result, err := hook.create(c)
+		if err != nil {
+			printer.PrintError(fmt.Sprintf("Unable to create outgoing webhook %d: %s", i+1, err))
+			failed++
+			continue
+		}
+		if result.Status == "created" {
+			tokens++
+			printer.PrintT("Outgoing webhook {{.DisplayName}} created, ID: {{.ID}}, token: {{.Token}}", result)
+		} else {
+			printer.PrintT("Outgoing webhook {{.DisplayName}} exists, ID: {{.ID}}", result)
+		}
+	}
+
+	if tokens > 0 {
+		printer.PrintWarning("The tokens of the outgoing webhooks won't be shown again")
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to create %d webhooks", failed)
+	}
+	return nil
+}
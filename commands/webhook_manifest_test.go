@@ -0,0 +1,285 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestCreateWebhooksCmd() {
+	writeManifest := func(content string) (string, func()) {
+		tmp, err := ioutil.TempDir("", "mmctl-")
+		s.Require().NoError(err)
+		path := filepath.Join(tmp, "webhooks.yaml")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(content), 0600))
+		return path, func() { os.RemoveAll(tmp) }
+	}
+
+	newCmd := func(path string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("from-file", path, "")
+		return cmd
+	}
+
+	user := &model.User{Id: model.NewId(), Username: "bot", Email: "bot@example.com"}
+	team := &model.Team{Id: model.NewId(), Name: "myteam"}
+	channel := &model.Channel{Id: model.NewId(), Name: "builds", TeamId: team.Id}
+
+	s.Run("Should create the webhooks of the manifest and show their URLs and tokens", func() {
+		printer.Clean()
+		path, cleanup := writeManifest(`
+incoming:
+  - channel: ` + channel.Id + `
+    user: bot@example.com
+    display_name: Alerts
+    lock_to_channel: true
+outgoing:
+  - team: ` + team.Id + `
+    channel: builds
+    user: bot@example.com
+    display_name: Builds
+    trigger_words: [build]
+    trigger_when: start
+    callback_urls: [https://ci.example.com/hook]
+`)
+		defer cleanup()
+
+		incoming := &model.IncomingWebhook{Id: model.NewId(), DisplayName: "Alerts"}
+		outgoing := &model.OutgoingWebhook{Id: model.NewId(), DisplayName: "Builds", Token: model.NewId()}
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(map[string]string{"SiteURL": "https://chat.example.com/"}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetIncomingWebhooksForTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.IncomingWebhook{{Id: model.NewId(), ChannelId: model.NewId(), DisplayName: "Alerts"}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateIncomingWebhook(&model.IncomingWebhook{ChannelId: channel.Id, DisplayName: "Alerts", ChannelLocked: true, Username: user.Username, UserId: user.Id}).
+			Return(incoming, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channel.Name, team.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetOutgoingWebhooksForTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.OutgoingWebhook{{Id: model.NewId(), DisplayName: "Builds"}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateOutgoingWebhook(&model.OutgoingWebhook{
+				CreatorId:    user.Id,
+				Username:     user.Username,
+				TeamId:       team.Id,
+				ChannelId:    channel.Id,
+				TriggerWords: []string{"build"},
+				TriggerWhen:  1,
+				CallbackURLs: []string{"https://ci.example.com/hook"},
+				DisplayName:  "Builds",
+			}).
+			Return(outgoing, &model.Response{}, nil).
+			Times(1)
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&createdWebhook{Type: "incoming", Status: "created", ID: incoming.Id, DisplayName: "Alerts", URL: "https://chat.example.com/hooks/" + incoming.Id},
+			&createdWebhook{Type: "outgoing", Status: "created", ID: outgoing.Id, DisplayName: "Builds", Token: outgoing.Token},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should go on with the rest of the webhooks if one fails", func() {
+		printer.Clean()
+		path, cleanup := writeManifest(`
+outgoing:
+  - team: ` + team.Id + `
+    user: bot@example.com
+    display_name: Builds
+    trigger_words: [build]
+    callback_urls: [https://ci.example.com/hook]
+  - team: ` + team.Id + `
+    user: bot@example.com
+    display_name: Tests
+    trigger_words: [test]
+    callback_urls: [https://ci.example.com/hook]
+`)
+		defer cleanup()
+
+		outgoing := &model.OutgoingWebhook{Id: model.NewId(), DisplayName: "Tests", Token: model.NewId()}
+
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetOutgoingWebhooksForTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.OutgoingWebhook{}, &model.Response{}, nil).
+			Times(2)
+		newOutgoing := func(displayName, triggerWord string) *model.OutgoingWebhook {
+			return &model.OutgoingWebhook{
+				CreatorId:    user.Id,
+				Username:     user.Username,
+				TeamId:       team.Id,
+				TriggerWords: []string{triggerWord},
+				CallbackURLs: []string{"https://ci.example.com/hook"},
+				DisplayName:  displayName,
+			}
+		}
+		s.client.
+			EXPECT().
+			CreateOutgoingWebhook(newOutgoing("Builds", "build")).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateOutgoingWebhook(newOutgoing("Tests", "test")).
+			Return(outgoing, &model.Response{}, nil).
+			Times(1)
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().EqualError(err, "unable to create 1 webhooks")
+		s.Require().Equal([]interface{}{
+			&createdWebhook{Type: "outgoing", Status: "created", ID: outgoing.Id, DisplayName: "Tests", Token: outgoing.Token},
+		}, printer.GetLines())
+		s.Require().Equal([]interface{}{"Unable to create outgoing webhook 1: mock error"}, printer.GetErrorLines())
+	})
+
+	s.Run("Should skip the webhooks that already exist", func() {
+		printer.Clean()
+		path, cleanup := writeManifest(`
+incoming:
+  - channel: ` + channel.Id + `
+    user: bot@example.com
+    display_name: Alerts
+outgoing:
+  - team: ` + team.Id + `
+    user: bot@example.com
+    display_name: Builds
+    trigger_words: [build]
+    callback_urls: [https://ci.example.com/hook]
+`)
+		defer cleanup()
+
+		incoming := &model.IncomingWebhook{Id: model.NewId(), ChannelId: channel.Id, DisplayName: "Alerts"}
+		outgoing := &model.OutgoingWebhook{Id: model.NewId(), TeamId: team.Id, DisplayName: "Builds", Token: model.NewId()}
+
+		s.client.
+			EXPECT().
+			GetOldClientConfig("").
+			Return(map[string]string{"SiteURL": "https://chat.example.com"}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannel(channel.Id, "").
+			Return(channel, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Email, "").
+			Return(user, &model.Response{}, nil).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetIncomingWebhooksForTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.IncomingWebhook{incoming}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(team.Id, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetOutgoingWebhooksForTeam(team.Id, 0, APILimitMaximum, "").
+			Return([]*model.OutgoingWebhook{outgoing}, &model.Response{}, nil).
+			Times(1)
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{
+			&createdWebhook{Type: "incoming", Status: "exists", ID: incoming.Id, DisplayName: "Alerts", URL: "https://chat.example.com/hooks/" + incoming.Id},
+			&createdWebhook{Type: "outgoing", Status: "exists", ID: outgoing.Id, DisplayName: "Builds"},
+		}, printer.GetLines())
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("Should fail without creating anything if the manifest is invalid", func() {
+		path, cleanup := writeManifest(`
+outgoing:
+  - team: myteam
+    user: bot
+    display_name: Builds
+    trigger_words: [build]
+    trigger_when: always
+    callback_urls: [https://ci.example.com/hook]
+`)
+		defer cleanup()
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().EqualError(err, `invalid webhook manifest `+path+`: outgoing webhook 1: invalid trigger when "always", must be exact or start`)
+	})
+
+	s.Run("Should fail if an incoming webhook has no display name", func() {
+		path, cleanup := writeManifest(`
+incoming:
+  - channel: myteam:alerts
+    user: bot
+`)
+		defer cleanup()
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().EqualError(err, `invalid webhook manifest `+path+`: incoming webhook 1 must have a channel, a user and a display name`)
+	})
+
+	s.Run("Should fail with unknown fields in the manifest", func() {
+		path, cleanup := writeManifest(`
+incoming:
+  - channel: myteam:alerts
+    user: bot
+    token: secret
+`)
+		defer cleanup()
+
+		err := createWebhooksCmdF(s.client, newCmd(path), []string{})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "field token not found")
+	})
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/client"
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+var RotateTokenWebhookCmd = &cobra.Command{
+	Use:   "rotate-token [integrations]",
+	Short: "Regenerate the tokens of outgoing webhooks and slash commands",
+	Long: `Regenerate the tokens of the outgoing webhooks and custom slash commands provided as arguments, or of all of them with the --all flag, and print the new token of each integration.
+Integrations can be specified by outgoing webhook ID, slash command ID or slash command team:trigger. The new tokens are only printed once, so store them before updating the external services that validate them.`,
+	Example: `  webhook rotate-token w16zb5tu3n1zkqo18goqry1je myteam:deploy
+
+  # rotate the tokens of every integration, saving the mapping
+  webhook rotate-token --all --json > tokens.json
+
+  # check which integrations would be rotated
+  webhook rotate-token --all --dry-run
+
+  # review the integrations before rotating their tokens
+  webhook rotate-token --all --plan > plan.json
+  webhook rotate-token --apply-plan plan.json --json > tokens.json`,
+	RunE: withClient(withPlanActions(rotateTokenPlanResolver, applyRotateTokenPlan, rotateTokenWebhookCmdF)),
+}
+
+func init() {
+	RotateTokenWebhookCmd.Flags().Bool("all", false, "Rotate the tokens of all the outgoing webhooks and custom slash commands")
+	RotateTokenWebhookCmd.Flags().Bool("dry-run", false, "List the integrations whose tokens would be rotated without rotating them")
+	addPlanFlags(RotateTokenWebhookCmd)
+
+	WebhookCmd.AddCommand(RotateTokenWebhookCmd)
+}
+
+const (
+	rotatedTokenTypeOutgoingWebhook = "outgoing_webhook"
+	rotatedTokenTypeSlashCommand    = "slash_command"
+)
+
+type rotatedToken struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token,omitempty"`
+}
+
+func newRotatedTokenFromWebhook(hook *model.OutgoingWebhook) *rotatedToken {
+	return &rotatedToken{Type: rotatedTokenTypeOutgoingWebhook, ID: hook.Id, Name: hook.DisplayName}
+}
+
+func newRotatedTokenFromCommand(command *model.Command) *rotatedToken {
+	return &rotatedToken{Type: rotatedTokenTypeSlashCommand, ID: command.Id, Name: "/" + command.Trigger}
+}
+
+// getRotateTokenTargetFromArg resolves an argument into an outgoing
+// webhook or a slash command
+func getRotateTokenTargetFromArg(c client.Client, arg string) *rotatedToken {
+	if strings.Contains(arg, ":") {
+		if command := getCommandFromTeamTrigger(c, arg); command != nil {
+			return newRotatedTokenFromCommand(command)
+		}
+		return nil
+	}
+
+	if hook, _, err := c.GetOutgoingWebhook(arg); err == nil && hook != nil {
+		return newRotatedTokenFromWebhook(hook)
+	}
+	if command, _, err := c.GetCommandById(arg); err == nil && command != nil {
+		return newRotatedTokenFromCommand(command)
+	}
+	return nil
+}
+
+// getAllRotateTokenTargets fetches every outgoing webhook and custom
+// slash command of the server
+func getAllRotateTokenTargets(c client.Client) ([]*rotatedToken, error) {
+	targets := []*rotatedToken{}
+	for page := 0; ; page++ {
+		hooks, _, err := c.GetOutgoingWebhooks(page, APILimitMaximum, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the outgoing webhooks")
+		}
+		for _, hook := range hooks {
+			targets = append(targets, newRotatedTokenFromWebhook(hook))
+		}
+		if len(hooks) < APILimitMaximum {
+			break
+		}
+	}
+
+	teams, err := getAllTeams(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch teams")
+	}
+	for _, team := range teams {
+		commands, _, commandsErr := c.ListCommands(team.Id, true)
+		if commandsErr != nil {
+			return nil, errors.Wrapf(commandsErr, "failed to fetch the slash commands of team %s", team.Name)
+		}
+		for _, command := range commands {
+			targets = append(targets, newRotatedTokenFromCommand(command))
+		}
+	}
+
+	return targets, nil
+}
+
+func rotateToken(c client.Client, target *rotatedToken) error {
+	if target.Type == rotatedTokenTypeSlashCommand {
+		token, _, err := c.RegenCommandToken(target.ID)
+		if err != nil {
+			return err
+		}
+		target.Token = token
+		return nil
+	}
+
+	hook, _, err := c.RegenOutgoingHookToken(target.ID)
+	if err != nil {
+		return err
+	}
+	target.Token = hook.Token
+	return nil
+}
+
+// getRotateTokenTargets returns the integrations of the arguments, or
+// all of them with the --all flag, along with the number of arguments
+// that couldn't be found
+func getRotateTokenTargets(c client.Client, cmd *cobra.Command, args []string) ([]*rotatedToken, int, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		return nil, 0, errors.New("either some integrations or the --all flag must be provided")
+	}
+
+	if all {
+		targets, err := getAllRotateTokenTargets(c)
+		return targets, 0, err
+	}
+
+	targets := []*rotatedToken{}
+	notFound := 0
+	for _, arg := range args {
+		target := getRotateTokenTargetFromArg(c, arg)
+		if target == nil {
+			printer.PrintError(fmt.Sprintf("unable to find an outgoing webhook or slash command %q", arg))
+			notFound++
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets, notFound, nil
+}
+
+// rotateTokenPlanActions maps the types of the integrations to the
+// actions of their plans
+var rotateTokenPlanActions = map[string]string{
+	rotatedTokenTypeOutgoingWebhook: "rotate_outgoing_webhook_token",
+	rotatedTokenTypeSlashCommand:    "rotate_slash_command_token",
+}
+
+func rotateTokenPlanResolver(c client.Client, cmd *cobra.Command, args []string) ([]planAction, error) {
+	targets, notFound, err := getRotateTokenTargets(c, cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	actions := []planAction{}
+	for _, target := range targets {
+		actions = append(actions, planAction{Action: rotateTokenPlanActions[target.Type], ID: target.ID, Name: target.Name})
+	}
+	if notFound > 0 {
+		return actions, fmt.Errorf("unable to find %d integrations", notFound)
+	}
+	return actions, nil
+}
+
+func applyRotateTokenPlan(c client.Client, _ *cobra.Command, actions []planAction) error {
+	targets := []*rotatedToken{}
+	for _, action := range actions {
+		target := &rotatedToken{ID: action.ID, Name: action.Name}
+		for tokenType, name := range rotateTokenPlanActions {
+			if action.Action == name {
+				target.Type = tokenType
+			}
+		}
+		if target.Type == "" {
+			return fmt.Errorf("unsupported plan action %q", action.Action)
+		}
+		targets = append(targets, target)
+	}
+	return rotateTokens(c, targets, false, 0)
+}
+
+// rotateTokens rotates the tokens of the integrations, failing if any
+// of them, or of the ones that couldn't be found, isn't rotated
+func rotateTokens(c client.Client, targets []*rotatedToken, dryRun bool, notFound int) error {
+	failed := notFound
+	for _, target := range targets {
+		if dryRun {
+			printer.PrintT("{{.Type}}\t{{.Name}} ({{.ID}})", target)
+			continue
+		}
+
+		if err := rotateToken(c, target); err != nil {
+			printer.PrintError(fmt.Sprintf("unable to rotate the token of %s %q: %s", target.Type, target.ID, err))
+			failed++
+			continue
+		}
+		printer.PrintT("{{.Type}}\t{{.Name}} ({{.ID}}): {{.Token}}", target)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to rotate %d tokens", failed)
+	}
+
+	return nil
+}
+
+func rotateTokenWebhookCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	targets, notFound, err := getRotateTokenTargets(c, cmd, args)
+	if err != nil {
+		return err
+	}
+	return rotateTokens(c, targets, dryRun, notFound)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/v6/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestRotateTokenWebhookCmd() {
+	newCmd := func(all, dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", all, "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	team := &model.Team{Id: "team-id", Name: "team"}
+	hook := &model.OutgoingWebhook{Id: "hook-id", DisplayName: "build"}
+	command := &model.Command{Id: "command-id", Trigger: "deploy", TeamId: team.Id}
+
+	s.Run("Should rotate the tokens of all the integrations", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhooks(0, APILimitMaximum, "").
+			Return([]*model.OutgoingWebhook{hook}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetAllTeams("", 0, APILimitMaximum).
+			Return([]*model.Team{team}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			ListCommands(team.Id, true).
+			Return([]*model.Command{command}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RegenOutgoingHookToken(hook.Id).
+			Return(&model.OutgoingWebhook{Id: hook.Id, Token: "new-hook-token"}, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RegenCommandToken(command.Id).
+			Return("new-command-token", &model.Response{}, nil).
+			Times(1)
+
+		err := rotateTokenWebhookCmdF(s.client, newCmd(true, false), []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Equal([]interface{}{
+			&rotatedToken{Type: rotatedTokenTypeOutgoingWebhook, ID: hook.Id, Name: hook.DisplayName, Token: "new-hook-token"},
+			&rotatedToken{Type: rotatedTokenTypeSlashCommand, ID: command.Id, Name: "/deploy", Token: "new-command-token"},
+		}, printer.GetLines())
+	})
+
+	s.Run("Should list the integrations of the arguments without rotating them with dry run", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhook(command.Id).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetCommandById(command.Id).
+			Return(command, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhook("unknown").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetCommandById("unknown").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := rotateTokenWebhookCmdF(s.client, newCmd(false, true), []string{command.Id, "unknown"})
+		s.Require().EqualError(err, "unable to rotate 1 tokens")
+		s.Require().Equal([]interface{}{
+			&rotatedToken{Type: rotatedTokenTypeSlashCommand, ID: command.Id, Name: "/deploy"},
+		}, printer.GetLines())
+		s.Require().Equal(`unable to find an outgoing webhook or slash command "unknown"`, printer.GetErrorLines()[0])
+	})
+
+	s.Run("Should report the tokens that fail to rotate", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhook(hook.Id).
+			Return(hook, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RegenOutgoingHookToken(hook.Id).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := rotateTokenWebhookCmdF(s.client, newCmd(false, false), []string{hook.Id})
+		s.Require().EqualError(err, "unable to rotate 1 tokens")
+		s.Require().Equal(`unable to rotate the token of outgoing_webhook "hook-id": mock error`, printer.GetErrorLines()[0])
+	})
+
+	s.Run("Should fail without integrations nor the all flag", func() {
+		err := rotateTokenWebhookCmdF(s.client, newCmd(false, false), []string{})
+		s.Require().EqualError(err, "either some integrations or the --all flag must be provided")
+	})
+}
@@ -5,7 +5,12 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +21,23 @@ var WebsocketCmd = &cobra.Command{
 	RunE:  websocketCmdF,
 }
 
+var WebsocketTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Stream the websocket events as JSON lines",
+	Long: `Connect to the websocket API of the server and write its events as they happen to the standard output, one JSON document per line, until mmctl is interrupted with Ctrl-C.
+The events the credentials have access to are streamed, like the posted, user_updated or config_changed ones. Use --event-type to only stream some of them.`,
+	Example: `  websocket tail
+
+  # follow the new posts
+  websocket tail --event-type posted,post_edited | jq .data.post`,
+	Args: cobra.NoArgs,
+	RunE: websocketTailCmdF,
+}
+
 func init() {
+	WebsocketTailCmd.Flags().StringSlice("event-type", nil, "Only stream the events of these types")
+
+	WebsocketCmd.AddCommand(WebsocketTailCmd)
 	RootCmd.AddCommand(WebsocketCmd)
 }
 
@@ -37,3 +58,74 @@ func websocketCmdF(cmd *cobra.Command, args []string) error {
 		fmt.Println(event.ToJSON())
 	}
 }
+
+func websocketTailCmdF(cmd *cobra.Command, args []string) error {
+	eventTypes, _ := cmd.Flags().GetStringSlice("event-type")
+
+	c, err := InitWebSocketClient()
+	if err != nil {
+		return err
+	}
+	if appErr := c.Connect(); appErr != nil {
+		return errors.New(appErr.Error())
+	}
+	defer c.Close()
+
+	c.Listen()
+	go func() {
+		// the responses and ping timeouts have to be read for the
+		// client to keep receiving events
+		for {
+			select {
+			case _, ok := <-c.ResponseChannel:
+				if !ok {
+					return
+				}
+			case <-c.PingTimeoutChannel:
+			}
+		}
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	if err := tailWebsocketEvents(os.Stdout, c.EventChannel, interrupt, eventTypes); err != nil {
+		if c.ListenError != nil {
+			return errors.Wrap(c.ListenError, err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// tailWebsocketEvents writes the events of the given types, or all of
+// them if no types are given, as JSON lines until it is interrupted or
+// the events channel is closed
+func tailWebsocketEvents(w io.Writer, events <-chan *model.WebSocketEvent, interrupt <-chan os.Signal, eventTypes []string) error {
+	types := map[string]bool{}
+	for _, eventType := range eventTypes {
+		types[eventType] = true
+	}
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return errors.New("the websocket connection was closed")
+			}
+			if len(types) > 0 && !types[event.EventType()] {
+				continue
+			}
+			b, err := event.ToJSON()
+			if err != nil {
+				return errors.Wrap(err, "failed to encode the event")
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+func (s *MmctlUnitTestSuite) TestTailWebsocketEvents() {
+	newEvents := func(eventTypes ...string) chan *model.WebSocketEvent {
+		events := make(chan *model.WebSocketEvent, len(eventTypes))
+		for _, eventType := range eventTypes {
+			events <- model.NewWebSocketEvent(eventType, "", "channel-id", "", nil)
+		}
+		return events
+	}
+
+	decodeLines := func(b *bytes.Buffer) []string {
+		eventTypes := []string{}
+		for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+			var event map[string]interface{}
+			s.Require().NoError(json.Unmarshal([]byte(line), &event))
+			eventTypes = append(eventTypes, event["event"].(string))
+		}
+		return eventTypes
+	}
+
+	s.Run("Should write the events of the given types as JSON lines", func() {
+		events := newEvents(model.WebsocketEventPosted, model.WebsocketEventTyping, model.WebsocketEventUserUpdated)
+		close(events)
+
+		var b bytes.Buffer
+		err := tailWebsocketEvents(&b, events, nil, []string{model.WebsocketEventPosted, model.WebsocketEventUserUpdated})
+		s.Require().EqualError(err, "the websocket connection was closed")
+		s.Require().Equal([]string{model.WebsocketEventPosted, model.WebsocketEventUserUpdated}, decodeLines(&b))
+	})
+
+	s.Run("Should write all the events without types", func() {
+		events := newEvents(model.WebsocketEventPosted, model.WebsocketEventConfigChanged)
+		close(events)
+
+		var b bytes.Buffer
+		err := tailWebsocketEvents(&b, events, nil, nil)
+		s.Require().Error(err)
+		s.Require().Equal([]string{model.WebsocketEventPosted, model.WebsocketEventConfigChanged}, decodeLines(&b))
+	})
+
+	s.Run("Should stop when interrupted", func() {
+		interrupt := make(chan os.Signal, 1)
+		interrupt <- os.Interrupt
+
+		var b bytes.Buffer
+		err := tailWebsocketEvents(&b, newEvents(), interrupt, nil)
+		s.Require().NoError(err)
+		s.Require().Empty(b.String())
+	})
+}
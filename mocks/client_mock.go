@@ -54,6 +54,21 @@ func (mr *MockClientMockRecorder) AddChannelMember(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChannelMember", reflect.TypeOf((*MockClient)(nil).AddChannelMember), arg0, arg1)
 }
 
+// AddChannelsToRetentionPolicy mocks base method
+func (m *MockClient) AddChannelsToRetentionPolicy(arg0 string, arg1 []string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddChannelsToRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddChannelsToRetentionPolicy indicates an expected call of AddChannelsToRetentionPolicy
+func (mr *MockClientMockRecorder) AddChannelsToRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChannelsToRetentionPolicy", reflect.TypeOf((*MockClient)(nil).AddChannelsToRetentionPolicy), arg0, arg1)
+}
+
 // AddTeamMember mocks base method
 func (m *MockClient) AddTeamMember(arg0, arg1 string) (*model.TeamMember, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +85,21 @@ func (mr *MockClientMockRecorder) AddTeamMember(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamMember", reflect.TypeOf((*MockClient)(nil).AddTeamMember), arg0, arg1)
 }
 
+// AddTeamsToRetentionPolicy mocks base method
+func (m *MockClient) AddTeamsToRetentionPolicy(arg0 string, arg1 []string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTeamsToRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTeamsToRetentionPolicy indicates an expected call of AddTeamsToRetentionPolicy
+func (mr *MockClientMockRecorder) AddTeamsToRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamsToRetentionPolicy", reflect.TypeOf((*MockClient)(nil).AddTeamsToRetentionPolicy), arg0, arg1)
+}
+
 // AssignBot mocks base method
 func (m *MockClient) AssignBot(arg0, arg1 string) (*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -212,6 +242,54 @@ func (mr *MockClientMockRecorder) CreateCommand(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommand", reflect.TypeOf((*MockClient)(nil).CreateCommand), arg0)
 }
 
+// CreateDataRetentionPolicy mocks base method
+func (m *MockClient) CreateDataRetentionPolicy(arg0 *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDataRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*model.RetentionPolicyWithTeamAndChannelCounts)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateDataRetentionPolicy indicates an expected call of CreateDataRetentionPolicy
+func (mr *MockClientMockRecorder) CreateDataRetentionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataRetentionPolicy", reflect.TypeOf((*MockClient)(nil).CreateDataRetentionPolicy), arg0)
+}
+
+// CreateDirectChannel mocks base method
+func (m *MockClient) CreateDirectChannel(arg0, arg1 string) (*model.Channel, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDirectChannel", arg0, arg1)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateDirectChannel indicates an expected call of CreateDirectChannel
+func (mr *MockClientMockRecorder) CreateDirectChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDirectChannel", reflect.TypeOf((*MockClient)(nil).CreateDirectChannel), arg0, arg1)
+}
+
+// CreateGroupChannel mocks base method
+func (m *MockClient) CreateGroupChannel(arg0 []string) (*model.Channel, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupChannel", arg0)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateGroupChannel indicates an expected call of CreateGroupChannel
+func (mr *MockClientMockRecorder) CreateGroupChannel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupChannel", reflect.TypeOf((*MockClient)(nil).CreateGroupChannel), arg0)
+}
+
 // CreateIncomingWebhook mocks base method
 func (m *MockClient) CreateIncomingWebhook(arg0 *model.IncomingWebhook) (*model.IncomingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -276,6 +354,38 @@ func (mr *MockClientMockRecorder) CreatePost(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockClient)(nil).CreatePost), arg0)
 }
 
+// CreateScheme mocks base method
+func (m *MockClient) CreateScheme(arg0 *model.Scheme) (*model.Scheme, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheme", arg0)
+	ret0, _ := ret[0].(*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateScheme indicates an expected call of CreateScheme
+func (mr *MockClientMockRecorder) CreateScheme(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheme", reflect.TypeOf((*MockClient)(nil).CreateScheme), arg0)
+}
+
+// CreateSidebarCategoryForTeamForUser mocks base method
+func (m *MockClient) CreateSidebarCategoryForTeamForUser(arg0, arg1 string, arg2 *model.SidebarCategoryWithChannels) (*model.SidebarCategoryWithChannels, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSidebarCategoryForTeamForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.SidebarCategoryWithChannels)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateSidebarCategoryForTeamForUser indicates an expected call of CreateSidebarCategoryForTeamForUser
+func (mr *MockClientMockRecorder) CreateSidebarCategoryForTeamForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSidebarCategoryForTeamForUser", reflect.TypeOf((*MockClient)(nil).CreateSidebarCategoryForTeamForUser), arg0, arg1, arg2)
+}
+
 // CreateTeam mocks base method
 func (m *MockClient) CreateTeam(arg0 *model.Team) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -370,6 +480,21 @@ func (mr *MockClientMockRecorder) DeleteCommand(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommand", reflect.TypeOf((*MockClient)(nil).DeleteCommand), arg0)
 }
 
+// DeleteDataRetentionPolicy mocks base method
+func (m *MockClient) DeleteDataRetentionPolicy(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDataRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDataRetentionPolicy indicates an expected call of DeleteDataRetentionPolicy
+func (mr *MockClientMockRecorder) DeleteDataRetentionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataRetentionPolicy", reflect.TypeOf((*MockClient)(nil).DeleteDataRetentionPolicy), arg0)
+}
+
 // DeleteExport mocks base method
 func (m *MockClient) DeleteExport(arg0 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -415,6 +540,81 @@ func (mr *MockClientMockRecorder) DeleteOutgoingWebhook(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutgoingWebhook", reflect.TypeOf((*MockClient)(nil).DeleteOutgoingWebhook), arg0)
 }
 
+// DeletePost mocks base method
+func (m *MockClient) DeletePost(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePost", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePost indicates an expected call of DeletePost
+func (mr *MockClientMockRecorder) DeletePost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockClient)(nil).DeletePost), arg0)
+}
+
+// DeleteReaction mocks base method
+func (m *MockClient) DeleteReaction(arg0 *model.Reaction) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReaction", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReaction indicates an expected call of DeleteReaction
+func (mr *MockClientMockRecorder) DeleteReaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReaction", reflect.TypeOf((*MockClient)(nil).DeleteReaction), arg0)
+}
+
+// DeleteSamlIdpCertificate mocks base method
+func (m *MockClient) DeleteSamlIdpCertificate() (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSamlIdpCertificate")
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSamlIdpCertificate indicates an expected call of DeleteSamlIdpCertificate
+func (mr *MockClientMockRecorder) DeleteSamlIdpCertificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSamlIdpCertificate", reflect.TypeOf((*MockClient)(nil).DeleteSamlIdpCertificate))
+}
+
+// DeleteSamlPrivateCertificate mocks base method
+func (m *MockClient) DeleteSamlPrivateCertificate() (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSamlPrivateCertificate")
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSamlPrivateCertificate indicates an expected call of DeleteSamlPrivateCertificate
+func (mr *MockClientMockRecorder) DeleteSamlPrivateCertificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSamlPrivateCertificate", reflect.TypeOf((*MockClient)(nil).DeleteSamlPrivateCertificate))
+}
+
+// DeleteSamlPublicCertificate mocks base method
+func (m *MockClient) DeleteSamlPublicCertificate() (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSamlPublicCertificate")
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSamlPublicCertificate indicates an expected call of DeleteSamlPublicCertificate
+func (mr *MockClientMockRecorder) DeleteSamlPublicCertificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSamlPublicCertificate", reflect.TypeOf((*MockClient)(nil).DeleteSamlPublicCertificate))
+}
+
 // DemoteUserToGuest mocks base method
 func (m *MockClient) DemoteUserToGuest(arg0 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -461,6 +661,21 @@ func (mr *MockClientMockRecorder) DisablePlugin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisablePlugin", reflect.TypeOf((*MockClient)(nil).DisablePlugin), arg0)
 }
 
+// DoAPIGet mocks base method
+func (m *MockClient) DoAPIGet(arg0, arg1 string) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoAPIGet", arg0, arg1)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoAPIGet indicates an expected call of DoAPIGet
+func (mr *MockClientMockRecorder) DoAPIGet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoAPIGet", reflect.TypeOf((*MockClient)(nil).DoAPIGet), arg0, arg1)
+}
+
 // DoAPIPost mocks base method
 func (m *MockClient) DoAPIPost(arg0, arg1 string) (*http.Response, error) {
 	m.ctrl.T.Helper()
@@ -476,6 +691,21 @@ func (mr *MockClientMockRecorder) DoAPIPost(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoAPIPost", reflect.TypeOf((*MockClient)(nil).DoAPIPost), arg0, arg1)
 }
 
+// DoAPIRequestWithHeaders mocks base method
+func (m *MockClient) DoAPIRequestWithHeaders(arg0, arg1, arg2 string, arg3 map[string]string) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoAPIRequestWithHeaders", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoAPIRequestWithHeaders indicates an expected call of DoAPIRequestWithHeaders
+func (mr *MockClientMockRecorder) DoAPIRequestWithHeaders(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoAPIRequestWithHeaders", reflect.TypeOf((*MockClient)(nil).DoAPIRequestWithHeaders), arg0, arg1, arg2, arg3)
+}
+
 // DownloadExport mocks base method
 func (m *MockClient) DownloadExport(arg0 string, arg1 io.Writer, arg2 int64) (int64, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -492,6 +722,22 @@ func (mr *MockClientMockRecorder) DownloadExport(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadExport", reflect.TypeOf((*MockClient)(nil).DownloadExport), arg0, arg1, arg2)
 }
 
+// DownloadJob mocks base method
+func (m *MockClient) DownloadJob(arg0 string) ([]byte, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadJob", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DownloadJob indicates an expected call of DownloadJob
+func (mr *MockClientMockRecorder) DownloadJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadJob", reflect.TypeOf((*MockClient)(nil).DownloadJob), arg0)
+}
+
 // EnableBot mocks base method
 func (m *MockClient) EnableBot(arg0 string) (*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -523,6 +769,38 @@ func (mr *MockClientMockRecorder) EnablePlugin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnablePlugin", reflect.TypeOf((*MockClient)(nil).EnablePlugin), arg0)
 }
 
+// ExecuteCommand mocks base method
+func (m *MockClient) ExecuteCommand(arg0, arg1 string) (*model.CommandResponse, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteCommand", arg0, arg1)
+	ret0, _ := ret[0].(*model.CommandResponse)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExecuteCommand indicates an expected call of ExecuteCommand
+func (mr *MockClientMockRecorder) ExecuteCommand(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteCommand", reflect.TypeOf((*MockClient)(nil).ExecuteCommand), arg0, arg1)
+}
+
+// GetAllRoles mocks base method
+func (m *MockClient) GetAllRoles() ([]*model.Role, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllRoles")
+	ret0, _ := ret[0].([]*model.Role)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllRoles indicates an expected call of GetAllRoles
+func (mr *MockClientMockRecorder) GetAllRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRoles", reflect.TypeOf((*MockClient)(nil).GetAllRoles))
+}
+
 // GetAllTeams mocks base method
 func (m *MockClient) GetAllTeams(arg0 string, arg1, arg2 int) ([]*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -539,6 +817,38 @@ func (mr *MockClientMockRecorder) GetAllTeams(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockClient)(nil).GetAllTeams), arg0, arg1, arg2)
 }
 
+// GetAnalyticsOld mocks base method
+func (m *MockClient) GetAnalyticsOld(arg0, arg1 string) (model.AnalyticsRows, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalyticsOld", arg0, arg1)
+	ret0, _ := ret[0].(model.AnalyticsRows)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAnalyticsOld indicates an expected call of GetAnalyticsOld
+func (mr *MockClientMockRecorder) GetAnalyticsOld(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalyticsOld", reflect.TypeOf((*MockClient)(nil).GetAnalyticsOld), arg0, arg1)
+}
+
+// GetAudits mocks base method
+func (m *MockClient) GetAudits(arg0, arg1 int, arg2 string) (model.Audits, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudits", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Audits)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAudits indicates an expected call of GetAudits
+func (mr *MockClientMockRecorder) GetAudits(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudits", reflect.TypeOf((*MockClient)(nil).GetAudits), arg0, arg1, arg2)
+}
+
 // GetBots mocks base method
 func (m *MockClient) GetBots(arg0, arg1 int, arg2 string) ([]*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -635,84 +945,276 @@ func (mr *MockClientMockRecorder) GetChannelByNameIncludeDeleted(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelByNameIncludeDeleted", reflect.TypeOf((*MockClient)(nil).GetChannelByNameIncludeDeleted), arg0, arg1, arg2)
 }
 
-// GetChannelMembers mocks base method
-func (m *MockClient) GetChannelMembers(arg0 string, arg1, arg2 int, arg3 string) (model.ChannelMembers, *model.Response, error) {
+// GetChannelMembers mocks base method
+func (m *MockClient) GetChannelMembers(arg0 string, arg1, arg2 int, arg3 string) (model.ChannelMembers, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelMembers", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(model.ChannelMembers)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelMembers indicates an expected call of GetChannelMembers
+func (mr *MockClientMockRecorder) GetChannelMembers(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelMembers", reflect.TypeOf((*MockClient)(nil).GetChannelMembers), arg0, arg1, arg2, arg3)
+}
+
+// GetChannelMembersForUser mocks base method
+func (m *MockClient) GetChannelMembersForUser(arg0, arg1, arg2 string) (model.ChannelMembers, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelMembersForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.ChannelMembers)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelMembersForUser indicates an expected call of GetChannelMembersForUser
+func (mr *MockClientMockRecorder) GetChannelMembersForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelMembersForUser", reflect.TypeOf((*MockClient)(nil).GetChannelMembersForUser), arg0, arg1, arg2)
+}
+
+// GetChannelModerations mocks base method
+func (m *MockClient) GetChannelModerations(arg0 string, arg1 string) ([]*model.ChannelModeration, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelModerations", arg0, arg1)
+	ret0, _ := ret[0].([]*model.ChannelModeration)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelModerations indicates an expected call of GetChannelModerations
+func (mr *MockClientMockRecorder) GetChannelModerations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelModerations", reflect.TypeOf((*MockClient)(nil).GetChannelModerations), arg0, arg1)
+}
+
+// GetChannelStats mocks base method
+func (m *MockClient) GetChannelStats(channelID, etag string) (*model.ChannelStats, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelStats", channelID, etag)
+	ret0, _ := ret[0].(*model.ChannelStats)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelStats indicates an expected call of GetChannelStats
+func (mr *MockClientMockRecorder) GetChannelStats(channelID, etag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelStats", reflect.TypeOf((*MockClient)(nil).GetChannelStats), channelID, etag)
+}
+
+// GetChannelsForRetentionPolicy mocks base method
+func (m *MockClient) GetChannelsForRetentionPolicy(arg0 string, arg1, arg2 int) (*model.ChannelsWithCount, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelsForRetentionPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.ChannelsWithCount)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelsForRetentionPolicy indicates an expected call of GetChannelsForRetentionPolicy
+func (mr *MockClientMockRecorder) GetChannelsForRetentionPolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelsForRetentionPolicy", reflect.TypeOf((*MockClient)(nil).GetChannelsForRetentionPolicy), arg0, arg1, arg2)
+}
+
+// GetChannelsForTeamForUser mocks base method
+func (m *MockClient) GetChannelsForTeamForUser(arg0, arg1 string, arg2 bool, arg3 string) ([]*model.Channel, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelsForTeamForUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelsForTeamForUser indicates an expected call of GetChannelsForTeamForUser
+func (mr *MockClientMockRecorder) GetChannelsForTeamForUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelsForTeamForUser", reflect.TypeOf((*MockClient)(nil).GetChannelsForTeamForUser), arg0, arg1, arg2, arg3)
+}
+
+// GetChannelsForUserWithLastDeleteAt mocks base method
+func (m *MockClient) GetChannelsForUserWithLastDeleteAt(arg0 string, arg1 int) ([]*model.Channel, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelsForUserWithLastDeleteAt", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelsForUserWithLastDeleteAt indicates an expected call of GetChannelsForUserWithLastDeleteAt
+func (mr *MockClientMockRecorder) GetChannelsForUserWithLastDeleteAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelsForUserWithLastDeleteAt", reflect.TypeOf((*MockClient)(nil).GetChannelsForUserWithLastDeleteAt), arg0, arg1)
+}
+
+// GetCommandById mocks base method
+func (m *MockClient) GetCommandById(arg0 string) (*model.Command, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommandById", arg0)
+	ret0, _ := ret[0].(*model.Command)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommandById indicates an expected call of GetCommandById
+func (mr *MockClientMockRecorder) GetCommandById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommandById", reflect.TypeOf((*MockClient)(nil).GetCommandById), arg0)
+}
+
+// GetConfig mocks base method
+func (m *MockClient) GetConfig() (*model.Config, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfig")
+	ret0, _ := ret[0].(*model.Config)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetConfig indicates an expected call of GetConfig
+func (mr *MockClientMockRecorder) GetConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockClient)(nil).GetConfig))
+}
+
+// GetDataRetentionPolicies mocks base method
+func (m *MockClient) GetDataRetentionPolicies(arg0, arg1 int) (*model.RetentionPolicyWithTeamAndChannelCountsList, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataRetentionPolicies", arg0, arg1)
+	ret0, _ := ret[0].(*model.RetentionPolicyWithTeamAndChannelCountsList)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDataRetentionPolicies indicates an expected call of GetDataRetentionPolicies
+func (mr *MockClientMockRecorder) GetDataRetentionPolicies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataRetentionPolicies", reflect.TypeOf((*MockClient)(nil).GetDataRetentionPolicies), arg0, arg1)
+}
+
+// GetDataRetentionPolicy mocks base method
+func (m *MockClient) GetDataRetentionPolicy() (*model.GlobalRetentionPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataRetentionPolicy")
+	ret0, _ := ret[0].(*model.GlobalRetentionPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDataRetentionPolicy indicates an expected call of GetDataRetentionPolicy
+func (mr *MockClientMockRecorder) GetDataRetentionPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataRetentionPolicy", reflect.TypeOf((*MockClient)(nil).GetDataRetentionPolicy))
+}
+
+// GetDeletedChannelsForTeam mocks base method
+func (m *MockClient) GetDeletedChannelsForTeam(arg0 string, arg1, arg2 int, arg3 string) ([]*model.Channel, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedChannelsForTeam", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeletedChannelsForTeam indicates an expected call of GetDeletedChannelsForTeam
+func (mr *MockClientMockRecorder) GetDeletedChannelsForTeam(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedChannelsForTeam", reflect.TypeOf((*MockClient)(nil).GetDeletedChannelsForTeam), arg0, arg1, arg2, arg3)
+}
+
+// GetEnvironmentConfig mocks base method
+func (m *MockClient) GetEnvironmentConfig() (map[string]interface{}, *model.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChannelMembers", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(model.ChannelMembers)
+	ret := m.ctrl.Call(m, "GetEnvironmentConfig")
+	ret0, _ := ret[0].(map[string]interface{})
 	ret1, _ := ret[1].(*model.Response)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetChannelMembers indicates an expected call of GetChannelMembers
-func (mr *MockClientMockRecorder) GetChannelMembers(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetEnvironmentConfig indicates an expected call of GetEnvironmentConfig
+func (mr *MockClientMockRecorder) GetEnvironmentConfig() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelMembers", reflect.TypeOf((*MockClient)(nil).GetChannelMembers), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvironmentConfig", reflect.TypeOf((*MockClient)(nil).GetEnvironmentConfig))
 }
 
-// GetChannelsForTeamForUser mocks base method
-func (m *MockClient) GetChannelsForTeamForUser(arg0, arg1 string, arg2 bool, arg3 string) ([]*model.Channel, *model.Response, error) {
+// GetFile mocks base method
+func (m *MockClient) GetFile(arg0 string) ([]byte, *model.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChannelsForTeamForUser", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].([]*model.Channel)
+	ret := m.ctrl.Call(m, "GetFile", arg0)
+	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(*model.Response)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetChannelsForTeamForUser indicates an expected call of GetChannelsForTeamForUser
-func (mr *MockClientMockRecorder) GetChannelsForTeamForUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetFile indicates an expected call of GetFile
+func (mr *MockClientMockRecorder) GetFile(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelsForTeamForUser", reflect.TypeOf((*MockClient)(nil).GetChannelsForTeamForUser), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockClient)(nil).GetFile), arg0)
 }
 
-// GetCommandById mocks base method
-func (m *MockClient) GetCommandById(arg0 string) (*model.Command, *model.Response, error) {
+// GetFileInfosForPost mocks base method
+func (m *MockClient) GetFileInfosForPost(arg0 string, arg1 string) ([]*model.FileInfo, *model.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommandById", arg0)
-	ret0, _ := ret[0].(*model.Command)
+	ret := m.ctrl.Call(m, "GetFileInfosForPost", arg0, arg1)
+	ret0, _ := ret[0].([]*model.FileInfo)
 	ret1, _ := ret[1].(*model.Response)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetCommandById indicates an expected call of GetCommandById
-func (mr *MockClientMockRecorder) GetCommandById(arg0 interface{}) *gomock.Call {
+// GetFileInfosForPost indicates an expected call of GetFileInfosForPost
+func (mr *MockClientMockRecorder) GetFileInfosForPost(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommandById", reflect.TypeOf((*MockClient)(nil).GetCommandById), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfosForPost", reflect.TypeOf((*MockClient)(nil).GetFileInfosForPost), arg0, arg1)
 }
 
-// GetConfig mocks base method
-func (m *MockClient) GetConfig() (*model.Config, *model.Response, error) {
+// GetGroup mocks base method
+func (m *MockClient) GetGroup(arg0, arg1 string) (*model.Group, *model.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConfig")
-	ret0, _ := ret[0].(*model.Config)
+	ret := m.ctrl.Call(m, "GetGroup", arg0, arg1)
+	ret0, _ := ret[0].(*model.Group)
 	ret1, _ := ret[1].(*model.Response)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetConfig indicates an expected call of GetConfig
-func (mr *MockClientMockRecorder) GetConfig() *gomock.Call {
+// GetGroup indicates an expected call of GetGroup
+func (mr *MockClientMockRecorder) GetGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockClient)(nil).GetConfig))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockClient)(nil).GetGroup), arg0, arg1)
 }
 
-// GetDeletedChannelsForTeam mocks base method
-func (m *MockClient) GetDeletedChannelsForTeam(arg0 string, arg1, arg2 int, arg3 string) ([]*model.Channel, *model.Response, error) {
+// GetGroups mocks base method
+func (m *MockClient) GetGroups(arg0 model.GroupSearchOpts) ([]*model.Group, *model.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDeletedChannelsForTeam", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].([]*model.Channel)
+	ret := m.ctrl.Call(m, "GetGroups", arg0)
+	ret0, _ := ret[0].([]*model.Group)
 	ret1, _ := ret[1].(*model.Response)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetDeletedChannelsForTeam indicates an expected call of GetDeletedChannelsForTeam
-func (mr *MockClientMockRecorder) GetDeletedChannelsForTeam(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetGroups indicates an expected call of GetGroups
+func (mr *MockClientMockRecorder) GetGroups(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedChannelsForTeam", reflect.TypeOf((*MockClient)(nil).GetDeletedChannelsForTeam), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockClient)(nil).GetGroups), arg0)
 }
 
 // GetGroupsByChannel mocks base method
@@ -749,6 +1251,22 @@ func (mr *MockClientMockRecorder) GetGroupsByTeam(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByTeam", reflect.TypeOf((*MockClient)(nil).GetGroupsByTeam), arg0, arg1)
 }
 
+// GetGroupsByUserId mocks base method
+func (m *MockClient) GetGroupsByUserId(arg0 string) ([]*model.Group, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupsByUserId", arg0)
+	ret0, _ := ret[0].([]*model.Group)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetGroupsByUserId indicates an expected call of GetGroupsByUserId
+func (mr *MockClientMockRecorder) GetGroupsByUserId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByUserId", reflect.TypeOf((*MockClient)(nil).GetGroupsByUserId), arg0)
+}
+
 // GetIncomingWebhook mocks base method
 func (m *MockClient) GetIncomingWebhook(arg0, arg1 string) (*model.IncomingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -893,6 +1411,70 @@ func (mr *MockClientMockRecorder) GetMarketplacePlugins(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarketplacePlugins", reflect.TypeOf((*MockClient)(nil).GetMarketplacePlugins), arg0)
 }
 
+// GetMe mocks base method
+func (m *MockClient) GetMe(arg0 string) (*model.User, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMe", arg0)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMe indicates an expected call of GetMe
+func (mr *MockClientMockRecorder) GetMe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMe", reflect.TypeOf((*MockClient)(nil).GetMe), arg0)
+}
+
+// GetNotices mocks base method
+func (m *MockClient) GetNotices(arg0 int64, arg1 string, arg2 model.NoticeClientType, arg3, arg4, arg5 string) (model.NoticeMessages, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotices", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(model.NoticeMessages)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNotices indicates an expected call of GetNotices
+func (mr *MockClientMockRecorder) GetNotices(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotices", reflect.TypeOf((*MockClient)(nil).GetNotices), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetOldClientConfig mocks base method
+func (m *MockClient) GetOldClientConfig(arg0 string) (map[string]string, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldClientConfig", arg0)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOldClientConfig indicates an expected call of GetOldClientConfig
+func (mr *MockClientMockRecorder) GetOldClientConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldClientConfig", reflect.TypeOf((*MockClient)(nil).GetOldClientConfig), arg0)
+}
+
+// GetOldClientLicense mocks base method
+func (m *MockClient) GetOldClientLicense(etag string) (map[string]string, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldClientLicense", etag)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOldClientLicense indicates an expected call of GetOldClientLicense
+func (mr *MockClientMockRecorder) GetOldClientLicense(etag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldClientLicense", reflect.TypeOf((*MockClient)(nil).GetOldClientLicense), etag)
+}
+
 // GetOutgoingWebhook mocks base method
 func (m *MockClient) GetOutgoingWebhook(arg0 string) (*model.OutgoingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -989,6 +1571,22 @@ func (mr *MockClientMockRecorder) GetPingWithFullServerStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPingWithFullServerStatus", reflect.TypeOf((*MockClient)(nil).GetPingWithFullServerStatus))
 }
 
+// GetPluginStatuses mocks base method
+func (m *MockClient) GetPluginStatuses() (model.PluginStatuses, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginStatuses")
+	ret0, _ := ret[0].(model.PluginStatuses)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPluginStatuses indicates an expected call of GetPluginStatuses
+func (mr *MockClientMockRecorder) GetPluginStatuses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginStatuses", reflect.TypeOf((*MockClient)(nil).GetPluginStatuses))
+}
+
 // GetPlugins mocks base method
 func (m *MockClient) GetPlugins() (*model.PluginsResponse, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1053,6 +1651,22 @@ func (mr *MockClientMockRecorder) GetPostsSince(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsSince", reflect.TypeOf((*MockClient)(nil).GetPostsSince), arg0, arg1, arg2)
 }
 
+// GetPreferencesByCategory mocks base method
+func (m *MockClient) GetPreferencesByCategory(arg0, arg1 string) (model.Preferences, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferencesByCategory", arg0, arg1)
+	ret0, _ := ret[0].(model.Preferences)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPreferencesByCategory indicates an expected call of GetPreferencesByCategory
+func (mr *MockClientMockRecorder) GetPreferencesByCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferencesByCategory", reflect.TypeOf((*MockClient)(nil).GetPreferencesByCategory), arg0, arg1)
+}
+
 // GetPrivateChannelsForTeam mocks base method
 func (m *MockClient) GetPrivateChannelsForTeam(arg0 string, arg1, arg2 int, arg3 string) ([]*model.Channel, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1101,6 +1715,54 @@ func (mr *MockClientMockRecorder) GetRoleByName(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockClient)(nil).GetRoleByName), arg0)
 }
 
+// GetSamlCertificateStatus mocks base method
+func (m *MockClient) GetSamlCertificateStatus() (*model.SamlCertificateStatus, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSamlCertificateStatus")
+	ret0, _ := ret[0].(*model.SamlCertificateStatus)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSamlCertificateStatus indicates an expected call of GetSamlCertificateStatus
+func (mr *MockClientMockRecorder) GetSamlCertificateStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSamlCertificateStatus", reflect.TypeOf((*MockClient)(nil).GetSamlCertificateStatus))
+}
+
+// GetSamlMetadataFromIdp mocks base method
+func (m *MockClient) GetSamlMetadataFromIdp(arg0 string) (*model.SamlMetadataResponse, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSamlMetadataFromIdp", arg0)
+	ret0, _ := ret[0].(*model.SamlMetadataResponse)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSamlMetadataFromIdp indicates an expected call of GetSamlMetadataFromIdp
+func (mr *MockClientMockRecorder) GetSamlMetadataFromIdp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSamlMetadataFromIdp", reflect.TypeOf((*MockClient)(nil).GetSamlMetadataFromIdp), arg0)
+}
+
+// GetSchemes mocks base method
+func (m *MockClient) GetSchemes(arg0 string, arg1, arg2 int) ([]*model.Scheme, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSchemes indicates an expected call of GetSchemes
+func (mr *MockClientMockRecorder) GetSchemes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemes", reflect.TypeOf((*MockClient)(nil).GetSchemes), arg0, arg1, arg2)
+}
+
 // GetServerBusy mocks base method
 func (m *MockClient) GetServerBusy() (*model.ServerBusyState, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1117,6 +1779,38 @@ func (mr *MockClientMockRecorder) GetServerBusy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerBusy", reflect.TypeOf((*MockClient)(nil).GetServerBusy))
 }
 
+// GetSessions mocks base method
+func (m *MockClient) GetSessions(arg0, arg1 string) ([]*model.Session, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Session)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSessions indicates an expected call of GetSessions
+func (mr *MockClientMockRecorder) GetSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockClient)(nil).GetSessions), arg0, arg1)
+}
+
+// GetSidebarCategoriesForTeamForUser mocks base method
+func (m *MockClient) GetSidebarCategoriesForTeamForUser(arg0, arg1, arg2 string) (*model.OrderedSidebarCategories, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidebarCategoriesForTeamForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.OrderedSidebarCategories)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSidebarCategoriesForTeamForUser indicates an expected call of GetSidebarCategoriesForTeamForUser
+func (mr *MockClientMockRecorder) GetSidebarCategoriesForTeamForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidebarCategoriesForTeamForUser", reflect.TypeOf((*MockClient)(nil).GetSidebarCategoriesForTeamForUser), arg0, arg1, arg2)
+}
+
 // GetTeam mocks base method
 func (m *MockClient) GetTeam(arg0, arg1 string) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1149,6 +1843,86 @@ func (mr *MockClientMockRecorder) GetTeamByName(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamByName", reflect.TypeOf((*MockClient)(nil).GetTeamByName), arg0, arg1)
 }
 
+// GetTeamMembersForUser mocks base method
+func (m *MockClient) GetTeamMembersForUser(arg0, arg1 string) ([]*model.TeamMember, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMembersForUser", arg0, arg1)
+	ret0, _ := ret[0].([]*model.TeamMember)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamMembersForUser indicates an expected call of GetTeamMembersForUser
+func (mr *MockClientMockRecorder) GetTeamMembersForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMembersForUser", reflect.TypeOf((*MockClient)(nil).GetTeamMembersForUser), arg0, arg1)
+}
+
+// GetTeamStats mocks base method
+func (m *MockClient) GetTeamStats(arg0, arg1 string) (*model.TeamStats, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamStats", arg0, arg1)
+	ret0, _ := ret[0].(*model.TeamStats)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamStats indicates an expected call of GetTeamStats
+func (mr *MockClientMockRecorder) GetTeamStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamStats", reflect.TypeOf((*MockClient)(nil).GetTeamStats), arg0, arg1)
+}
+
+// GetTeamsForRetentionPolicy mocks base method
+func (m *MockClient) GetTeamsForRetentionPolicy(arg0 string, arg1, arg2 int) (*model.TeamsWithCount, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsForRetentionPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TeamsWithCount)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamsForRetentionPolicy indicates an expected call of GetTeamsForRetentionPolicy
+func (mr *MockClientMockRecorder) GetTeamsForRetentionPolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForRetentionPolicy", reflect.TypeOf((*MockClient)(nil).GetTeamsForRetentionPolicy), arg0, arg1, arg2)
+}
+
+// GetTeamsForUser mocks base method
+func (m *MockClient) GetTeamsForUser(arg0, arg1 string) ([]*model.Team, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Team)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamsForUser indicates an expected call of GetTeamsForUser
+func (mr *MockClientMockRecorder) GetTeamsForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForUser", reflect.TypeOf((*MockClient)(nil).GetTeamsForUser), arg0, arg1)
+}
+
+// GetTotalUsersStats mocks base method
+func (m *MockClient) GetTotalUsersStats(arg0 string) (*model.UsersStats, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalUsersStats", arg0)
+	ret0, _ := ret[0].(*model.UsersStats)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTotalUsersStats indicates an expected call of GetTotalUsersStats
+func (mr *MockClientMockRecorder) GetTotalUsersStats(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalUsersStats", reflect.TypeOf((*MockClient)(nil).GetTotalUsersStats), arg0)
+}
+
 // GetUpload mocks base method
 func (m *MockClient) GetUpload(arg0 string) (*model.UploadSession, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1277,6 +2051,22 @@ func (mr *MockClientMockRecorder) GetUsersByIds(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIds", reflect.TypeOf((*MockClient)(nil).GetUsersByIds), arg0)
 }
 
+// GetUsersInGroup mocks base method
+func (m *MockClient) GetUsersInGroup(arg0 string, arg1, arg2 int, arg3 string) ([]*model.User, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersInGroup", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsersInGroup indicates an expected call of GetUsersInGroup
+func (mr *MockClientMockRecorder) GetUsersInGroup(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersInGroup", reflect.TypeOf((*MockClient)(nil).GetUsersInGroup), arg0, arg1, arg2, arg3)
+}
+
 // GetUsersInTeam mocks base method
 func (m *MockClient) GetUsersInTeam(arg0 string, arg1, arg2 int, arg3 string) ([]*model.User, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1293,6 +2083,22 @@ func (mr *MockClientMockRecorder) GetUsersInTeam(arg0, arg1, arg2, arg3 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersInTeam", reflect.TypeOf((*MockClient)(nil).GetUsersInTeam), arg0, arg1, arg2, arg3)
 }
 
+// GetUsersStatusesByIds mocks base method
+func (m *MockClient) GetUsersStatusesByIds(arg0 []string) ([]*model.Status, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersStatusesByIds", arg0)
+	ret0, _ := ret[0].([]*model.Status)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsersStatusesByIds indicates an expected call of GetUsersStatusesByIds
+func (mr *MockClientMockRecorder) GetUsersStatusesByIds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersStatusesByIds", reflect.TypeOf((*MockClient)(nil).GetUsersStatusesByIds), arg0)
+}
+
 // InstallMarketplacePlugin mocks base method
 func (m *MockClient) InstallMarketplacePlugin(arg0 *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1340,6 +2146,38 @@ func (mr *MockClientMockRecorder) InviteUsersToTeam(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteUsersToTeam", reflect.TypeOf((*MockClient)(nil).InviteUsersToTeam), arg0, arg1)
 }
 
+// LinkGroupSyncable mocks base method
+func (m *MockClient) LinkGroupSyncable(arg0, arg1 string, arg2 model.GroupSyncableType, arg3 *model.GroupSyncablePatch) (*model.GroupSyncable, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkGroupSyncable", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.GroupSyncable)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LinkGroupSyncable indicates an expected call of LinkGroupSyncable
+func (mr *MockClientMockRecorder) LinkGroupSyncable(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkGroupSyncable", reflect.TypeOf((*MockClient)(nil).LinkGroupSyncable), arg0, arg1, arg2, arg3)
+}
+
+// LinkLdapGroup mocks base method
+func (m *MockClient) LinkLdapGroup(arg0 string) (*model.Group, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkLdapGroup", arg0)
+	ret0, _ := ret[0].(*model.Group)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LinkLdapGroup indicates an expected call of LinkLdapGroup
+func (mr *MockClientMockRecorder) LinkLdapGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkLdapGroup", reflect.TypeOf((*MockClient)(nil).LinkLdapGroup), arg0)
+}
+
 // ListCommands mocks base method
 func (m *MockClient) ListCommands(arg0 string, arg1 bool) ([]*model.Command, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1382,10 +2220,56 @@ func (m *MockClient) ListImports() ([]string, *model.Response, error) {
 	return ret0, ret1, ret2
 }
 
-// ListImports indicates an expected call of ListImports
-func (mr *MockClientMockRecorder) ListImports() *gomock.Call {
+// ListImports indicates an expected call of ListImports
+func (mr *MockClientMockRecorder) ListImports() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockClient)(nil).ListImports))
+}
+
+// Login mocks base method
+func (m *MockClient) Login(arg0, arg1 string) (*model.User, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", arg0, arg1)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Login indicates an expected call of Login
+func (mr *MockClientMockRecorder) Login(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClient)(nil).Login), arg0, arg1)
+}
+
+// Logout mocks base method
+func (m *MockClient) Logout() (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout")
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout
+func (mr *MockClientMockRecorder) Logout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockClient)(nil).Logout))
+}
+
+// MarkNoticesViewed mocks base method
+func (m *MockClient) MarkNoticesViewed(arg0 []string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNoticesViewed", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNoticesViewed indicates an expected call of MarkNoticesViewed
+func (mr *MockClientMockRecorder) MarkNoticesViewed(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockClient)(nil).ListImports))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNoticesViewed", reflect.TypeOf((*MockClient)(nil).MarkNoticesViewed), arg0)
 }
 
 // MigrateAuthToLdap mocks base method
@@ -1511,6 +2395,22 @@ func (mr *MockClientMockRecorder) PatchChannel(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchChannel", reflect.TypeOf((*MockClient)(nil).PatchChannel), arg0, arg1)
 }
 
+// PatchChannelModerations mocks base method
+func (m *MockClient) PatchChannelModerations(arg0 string, arg1 []*model.ChannelModerationPatch) ([]*model.ChannelModeration, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchChannelModerations", arg0, arg1)
+	ret0, _ := ret[0].([]*model.ChannelModeration)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchChannelModerations indicates an expected call of PatchChannelModerations
+func (mr *MockClientMockRecorder) PatchChannelModerations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchChannelModerations", reflect.TypeOf((*MockClient)(nil).PatchChannelModerations), arg0, arg1)
+}
+
 // PatchConfig mocks base method
 func (m *MockClient) PatchConfig(arg0 *model.Config) (*model.Config, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1527,6 +2427,22 @@ func (mr *MockClientMockRecorder) PatchConfig(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchConfig", reflect.TypeOf((*MockClient)(nil).PatchConfig), arg0)
 }
 
+// PatchDataRetentionPolicy mocks base method
+func (m *MockClient) PatchDataRetentionPolicy(arg0 *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchDataRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*model.RetentionPolicyWithTeamAndChannelCounts)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchDataRetentionPolicy indicates an expected call of PatchDataRetentionPolicy
+func (mr *MockClientMockRecorder) PatchDataRetentionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchDataRetentionPolicy", reflect.TypeOf((*MockClient)(nil).PatchDataRetentionPolicy), arg0)
+}
+
 // PatchRole mocks base method
 func (m *MockClient) PatchRole(arg0 string, arg1 *model.RolePatch) (*model.Role, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1559,6 +2475,22 @@ func (mr *MockClientMockRecorder) PatchTeam(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTeam", reflect.TypeOf((*MockClient)(nil).PatchTeam), arg0, arg1)
 }
 
+// PatchUser mocks base method
+func (m *MockClient) PatchUser(arg0 string, arg1 *model.UserPatch) (*model.User, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", arg0, arg1)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchUser indicates an expected call of PatchUser
+func (mr *MockClientMockRecorder) PatchUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockClient)(nil).PatchUser), arg0, arg1)
+}
+
 // PermanentDeleteAllUsers mocks base method
 func (m *MockClient) PermanentDeleteAllUsers() (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1619,6 +2551,21 @@ func (mr *MockClientMockRecorder) PermanentDeleteUser(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PermanentDeleteUser", reflect.TypeOf((*MockClient)(nil).PermanentDeleteUser), arg0)
 }
 
+// PinPost mocks base method
+func (m *MockClient) PinPost(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinPost", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinPost indicates an expected call of PinPost
+func (mr *MockClientMockRecorder) PinPost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinPost", reflect.TypeOf((*MockClient)(nil).PinPost), arg0)
+}
+
 // PromoteGuestToUser mocks base method
 func (m *MockClient) PromoteGuestToUser(arg0 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1634,6 +2581,22 @@ func (mr *MockClientMockRecorder) PromoteGuestToUser(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteGuestToUser", reflect.TypeOf((*MockClient)(nil).PromoteGuestToUser), arg0)
 }
 
+// RegenCommandToken mocks base method
+func (m *MockClient) RegenCommandToken(commandID string) (string, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenCommandToken", commandID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegenCommandToken indicates an expected call of RegenCommandToken
+func (mr *MockClientMockRecorder) RegenCommandToken(commandID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenCommandToken", reflect.TypeOf((*MockClient)(nil).RegenCommandToken), commandID)
+}
+
 // RegenOutgoingHookToken mocks base method
 func (m *MockClient) RegenOutgoingHookToken(arg0 string) (*model.OutgoingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1665,6 +2628,21 @@ func (mr *MockClientMockRecorder) ReloadConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadConfig", reflect.TypeOf((*MockClient)(nil).ReloadConfig))
 }
 
+// RemoveChannelsFromRetentionPolicy mocks base method
+func (m *MockClient) RemoveChannelsFromRetentionPolicy(arg0 string, arg1 []string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChannelsFromRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveChannelsFromRetentionPolicy indicates an expected call of RemoveChannelsFromRetentionPolicy
+func (mr *MockClientMockRecorder) RemoveChannelsFromRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChannelsFromRetentionPolicy", reflect.TypeOf((*MockClient)(nil).RemoveChannelsFromRetentionPolicy), arg0, arg1)
+}
+
 // RemoveLicenseFile mocks base method
 func (m *MockClient) RemoveLicenseFile() (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1695,6 +2673,21 @@ func (mr *MockClientMockRecorder) RemovePlugin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePlugin", reflect.TypeOf((*MockClient)(nil).RemovePlugin), arg0)
 }
 
+// RemoveTeamIcon mocks base method
+func (m *MockClient) RemoveTeamIcon(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTeamIcon", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTeamIcon indicates an expected call of RemoveTeamIcon
+func (mr *MockClientMockRecorder) RemoveTeamIcon(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeamIcon", reflect.TypeOf((*MockClient)(nil).RemoveTeamIcon), arg0)
+}
+
 // RemoveTeamMember mocks base method
 func (m *MockClient) RemoveTeamMember(arg0, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1710,6 +2703,21 @@ func (mr *MockClientMockRecorder) RemoveTeamMember(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeamMember", reflect.TypeOf((*MockClient)(nil).RemoveTeamMember), arg0, arg1)
 }
 
+// RemoveTeamsFromRetentionPolicy mocks base method
+func (m *MockClient) RemoveTeamsFromRetentionPolicy(arg0 string, arg1 []string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTeamsFromRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTeamsFromRetentionPolicy indicates an expected call of RemoveTeamsFromRetentionPolicy
+func (mr *MockClientMockRecorder) RemoveTeamsFromRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeamsFromRetentionPolicy", reflect.TypeOf((*MockClient)(nil).RemoveTeamsFromRetentionPolicy), arg0, arg1)
+}
+
 // RemoveUserFromChannel mocks base method
 func (m *MockClient) RemoveUserFromChannel(arg0, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1773,6 +2781,36 @@ func (mr *MockClientMockRecorder) RestoreTeam(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTeam", reflect.TypeOf((*MockClient)(nil).RestoreTeam), arg0)
 }
 
+// RevokeAllSessions mocks base method
+func (m *MockClient) RevokeAllSessions(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions
+func (mr *MockClientMockRecorder) RevokeAllSessions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockClient)(nil).RevokeAllSessions), arg0)
+}
+
+// RevokeSession mocks base method
+func (m *MockClient) RevokeSession(arg0, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSession indicates an expected call of RevokeSession
+func (mr *MockClientMockRecorder) RevokeSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockClient)(nil).RevokeSession), arg0, arg1)
+}
+
 // RevokeUserAccessToken mocks base method
 func (m *MockClient) RevokeUserAccessToken(arg0 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1788,6 +2826,54 @@ func (mr *MockClientMockRecorder) RevokeUserAccessToken(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccessToken", reflect.TypeOf((*MockClient)(nil).RevokeUserAccessToken), arg0)
 }
 
+// SaveReaction mocks base method
+func (m *MockClient) SaveReaction(arg0 *model.Reaction) (*model.Reaction, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReaction", arg0)
+	ret0, _ := ret[0].(*model.Reaction)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SaveReaction indicates an expected call of SaveReaction
+func (mr *MockClientMockRecorder) SaveReaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReaction", reflect.TypeOf((*MockClient)(nil).SaveReaction), arg0)
+}
+
+// SearchPosts mocks base method
+func (m *MockClient) SearchPosts(arg0, arg1 string, arg2 bool) (*model.PostList, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.PostList)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchPosts indicates an expected call of SearchPosts
+func (mr *MockClientMockRecorder) SearchPosts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockClient)(nil).SearchPosts), arg0, arg1, arg2)
+}
+
+// SearchPostsWithParams mocks base method
+func (m *MockClient) SearchPostsWithParams(arg0 string, arg1 *model.SearchParameter) (*model.PostList, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPostsWithParams", arg0, arg1)
+	ret0, _ := ret[0].(*model.PostList)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchPostsWithParams indicates an expected call of SearchPostsWithParams
+func (mr *MockClientMockRecorder) SearchPostsWithParams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPostsWithParams", reflect.TypeOf((*MockClient)(nil).SearchPostsWithParams), arg0, arg1)
+}
+
 // SearchTeams mocks base method
 func (m *MockClient) SearchTeams(arg0 *model.TeamSearch) ([]*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1819,6 +2905,36 @@ func (mr *MockClientMockRecorder) SendPasswordResetEmail(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordResetEmail", reflect.TypeOf((*MockClient)(nil).SendPasswordResetEmail), arg0)
 }
 
+// SendVerificationEmail mocks base method
+func (m *MockClient) SendVerificationEmail(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerificationEmail", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendVerificationEmail indicates an expected call of SendVerificationEmail
+func (mr *MockClientMockRecorder) SendVerificationEmail(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerificationEmail", reflect.TypeOf((*MockClient)(nil).SendVerificationEmail), arg0)
+}
+
+// SetDefaultProfileImage mocks base method
+func (m *MockClient) SetDefaultProfileImage(arg0 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultProfileImage", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDefaultProfileImage indicates an expected call of SetDefaultProfileImage
+func (mr *MockClientMockRecorder) SetDefaultProfileImage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultProfileImage", reflect.TypeOf((*MockClient)(nil).SetDefaultProfileImage), arg0)
+}
+
 // SetServerBusy mocks base method
 func (m *MockClient) SetServerBusy(arg0 int) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1834,6 +2950,21 @@ func (mr *MockClientMockRecorder) SetServerBusy(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServerBusy", reflect.TypeOf((*MockClient)(nil).SetServerBusy), arg0)
 }
 
+// SetTeamIcon mocks base method
+func (m *MockClient) SetTeamIcon(arg0 string, arg1 []byte) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTeamIcon", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTeamIcon indicates an expected call of SetTeamIcon
+func (mr *MockClientMockRecorder) SetTeamIcon(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTeamIcon", reflect.TypeOf((*MockClient)(nil).SetTeamIcon), arg0, arg1)
+}
+
 // SoftDeleteTeam mocks base method
 func (m *MockClient) SoftDeleteTeam(arg0 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1864,6 +2995,82 @@ func (mr *MockClientMockRecorder) SyncLdap(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLdap", reflect.TypeOf((*MockClient)(nil).SyncLdap), arg0)
 }
 
+// TestLdap mocks base method
+func (m *MockClient) TestLdap() (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestLdap")
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestLdap indicates an expected call of TestLdap
+func (mr *MockClientMockRecorder) TestLdap() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestLdap", reflect.TypeOf((*MockClient)(nil).TestLdap))
+}
+
+// TestS3Connection mocks base method
+func (m *MockClient) TestS3Connection(arg0 *model.Config) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestS3Connection", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestS3Connection indicates an expected call of TestS3Connection
+func (mr *MockClientMockRecorder) TestS3Connection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestS3Connection", reflect.TypeOf((*MockClient)(nil).TestS3Connection), arg0)
+}
+
+// UnlinkGroupSyncable mocks base method
+func (m *MockClient) UnlinkGroupSyncable(arg0, arg1 string, arg2 model.GroupSyncableType) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkGroupSyncable", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlinkGroupSyncable indicates an expected call of UnlinkGroupSyncable
+func (mr *MockClientMockRecorder) UnlinkGroupSyncable(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkGroupSyncable", reflect.TypeOf((*MockClient)(nil).UnlinkGroupSyncable), arg0, arg1, arg2)
+}
+
+// UnlinkLdapGroup mocks base method
+func (m *MockClient) UnlinkLdapGroup(arg0 string) (*model.Group, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkLdapGroup", arg0)
+	ret0, _ := ret[0].(*model.Group)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UnlinkLdapGroup indicates an expected call of UnlinkLdapGroup
+func (mr *MockClientMockRecorder) UnlinkLdapGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkLdapGroup", reflect.TypeOf((*MockClient)(nil).UnlinkLdapGroup), arg0)
+}
+
+// UpdateChannelMemberSchemeRoles mocks base method
+func (m *MockClient) UpdateChannelMemberSchemeRoles(arg0, arg1 string, arg2 *model.SchemeRoles) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannelMemberSchemeRoles", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChannelMemberSchemeRoles indicates an expected call of UpdateChannelMemberSchemeRoles
+func (mr *MockClientMockRecorder) UpdateChannelMemberSchemeRoles(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelMemberSchemeRoles", reflect.TypeOf((*MockClient)(nil).UpdateChannelMemberSchemeRoles), arg0, arg1, arg2)
+}
+
 // UpdateChannelPrivacy mocks base method
 func (m *MockClient) UpdateChannelPrivacy(arg0 string, arg1 model.ChannelType) (*model.Channel, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1944,6 +3151,37 @@ func (mr *MockClientMockRecorder) UpdateOutgoingWebhook(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOutgoingWebhook", reflect.TypeOf((*MockClient)(nil).UpdateOutgoingWebhook), arg0)
 }
 
+// UpdatePreferences mocks base method
+func (m *MockClient) UpdatePreferences(arg0 string, arg1 model.Preferences) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences
+func (mr *MockClientMockRecorder) UpdatePreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockClient)(nil).UpdatePreferences), arg0, arg1)
+}
+
+// UpdateSidebarCategoryForTeamForUser mocks base method
+func (m *MockClient) UpdateSidebarCategoryForTeamForUser(arg0, arg1, arg2 string, arg3 *model.SidebarCategoryWithChannels) (*model.SidebarCategoryWithChannels, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSidebarCategoryForTeamForUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.SidebarCategoryWithChannels)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateSidebarCategoryForTeamForUser indicates an expected call of UpdateSidebarCategoryForTeamForUser
+func (mr *MockClientMockRecorder) UpdateSidebarCategoryForTeamForUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSidebarCategoryForTeamForUser", reflect.TypeOf((*MockClient)(nil).UpdateSidebarCategoryForTeamForUser), arg0, arg1, arg2, arg3)
+}
+
 // UpdateTeam mocks base method
 func (m *MockClient) UpdateTeam(arg0 *model.Team) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -2083,6 +3321,22 @@ func (mr *MockClientMockRecorder) UploadData(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadData", reflect.TypeOf((*MockClient)(nil).UploadData), arg0, arg1)
 }
 
+// UploadFile mocks base method
+func (m *MockClient) UploadFile(arg0 []byte, arg1, arg2 string) (*model.FileUploadResponse, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.FileUploadResponse)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UploadFile indicates an expected call of UploadFile
+func (mr *MockClientMockRecorder) UploadFile(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockClient)(nil).UploadFile), arg0, arg1, arg2)
+}
+
 // UploadLicenseFile mocks base method
 func (m *MockClient) UploadLicenseFile(arg0 []byte) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -2130,6 +3384,51 @@ func (mr *MockClientMockRecorder) UploadPluginForced(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPluginForced", reflect.TypeOf((*MockClient)(nil).UploadPluginForced), arg0)
 }
 
+// UploadSamlIdpCertificate mocks base method
+func (m *MockClient) UploadSamlIdpCertificate(arg0 []byte, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSamlIdpCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSamlIdpCertificate indicates an expected call of UploadSamlIdpCertificate
+func (mr *MockClientMockRecorder) UploadSamlIdpCertificate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSamlIdpCertificate", reflect.TypeOf((*MockClient)(nil).UploadSamlIdpCertificate), arg0, arg1)
+}
+
+// UploadSamlPrivateCertificate mocks base method
+func (m *MockClient) UploadSamlPrivateCertificate(arg0 []byte, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSamlPrivateCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSamlPrivateCertificate indicates an expected call of UploadSamlPrivateCertificate
+func (mr *MockClientMockRecorder) UploadSamlPrivateCertificate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSamlPrivateCertificate", reflect.TypeOf((*MockClient)(nil).UploadSamlPrivateCertificate), arg0, arg1)
+}
+
+// UploadSamlPublicCertificate mocks base method
+func (m *MockClient) UploadSamlPublicCertificate(arg0 []byte, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSamlPublicCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSamlPublicCertificate indicates an expected call of UploadSamlPublicCertificate
+func (mr *MockClientMockRecorder) UploadSamlPublicCertificate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSamlPublicCertificate", reflect.TypeOf((*MockClient)(nil).UploadSamlPublicCertificate), arg0, arg1)
+}
+
 // VerifyUserEmailWithoutToken mocks base method
 func (m *MockClient) VerifyUserEmailWithoutToken(arg0 string) (*model.User, *model.Response, error) {
 	m.ctrl.T.Helper()
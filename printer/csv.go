@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// csvValueColumn is the column of the elements that are not objects,
+// like the messages printed with Print
+const csvValueColumn = "value"
+
+// csvRecord is an element of the output as a JSON object, keeping the
+// order of its keys
+type csvRecord struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// decodeCSVRecords decodes the JSON of an element into records, one per
+// item if the element is an array
+func decodeCSVRecords(raw json.RawMessage) ([]*csvRecord, error) {
+	trimmed := bytes.TrimSpace(raw)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		records := []*csvRecord{}
+		for _, item := range items {
+			itemRecords, err := decodeCSVRecords(item)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, itemRecords...)
+		}
+		return records, nil
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		record := &csvRecord{values: map[string]json.RawMessage{}}
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := token.(string)
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+			if _, ok := record.values[key]; !ok {
+				record.keys = append(record.keys, key)
+			}
+			record.values[key] = value
+		}
+		return []*csvRecord{record}, nil
+	default:
+		return []*csvRecord{{keys: []string{csvValueColumn}, values: map[string]json.RawMessage{csvValueColumn: trimmed}}}, nil
+	}
+}
+
+// csvCell renders a JSON value as a cell. Strings are unquoted, null
+// values are empty, and objects and arrays are kept as JSON
+func csvCell(value json.RawMessage) string {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return ""
+	}
+	if trimmed[0] == '"' {
+		var s string
+		if err := json.Unmarshal(trimmed, &s); err == nil {
+			return s
+		}
+	}
+	return string(trimmed)
+}
+
+// linesToCSV renders the lines as CSV with a header row. The columns are
+// the given ones, or all the keys of the elements in order of
+// appearance
+func linesToCSV(lines []interface{}, columns []string) ([]byte, error) {
+	records := []*csvRecord{}
+	for _, line := range lines {
+		raw, err := json.Marshal(line)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode the output as CSV: %w", err)
+		}
+		lineRecords, err := decodeCSVRecords(raw)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode the output as CSV: %w", err)
+		}
+		records = append(records, lineRecords...)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := []string{}
+	for _, column := range columns {
+		if column = strings.TrimSpace(column); column != "" {
+			header = append(header, column)
+		}
+	}
+	if len(header) == 0 {
+		seen := map[string]bool{}
+		for _, record := range records {
+			for _, key := range record.keys {
+				if !seen[key] {
+					seen[key] = true
+					header = append(header, key)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		row := make([]string, len(header))
+		for i, column := range header {
+			row[i] = csvCell(record.values[column])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -10,18 +10,31 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 const (
-	FormatPlain = "plain"
-	FormatJSON  = "json"
+	FormatPlain  = "plain"
+	FormatJSON   = "json"
+	FormatIDOnly = "id-only"
+	FormatCSV    = "csv"
+
+	// FormatTemplate renders each element with the template set with
+	// SetTemplate
+	FormatTemplate = "template"
 )
 
+// DefaultTimestampFormat is the layout used to render timestamps in
+// the plain output when no other format is configured
+const DefaultTimestampFormat = "2006-01-02 15:04:05 -0700 MST"
+
 type Printer struct { //nolint
 	writer  io.Writer
 	eWriter io.Writer
@@ -30,8 +43,13 @@ type Printer struct { //nolint
 	Single        bool
 	NoNewline     bool
 	templateFuncs template.FuncMap
+	template      *template.Template
 	pager         bool
 	Quiet         bool
+	Table         bool
+	TimeFormat    string
+	UTC           bool
+	Columns       []string
 	Lines         []interface{}
 	ErrorLines    []interface{}
 
@@ -46,15 +64,45 @@ type printOpts struct {
 	usePager  bool
 	shortStat bool
 	noNewline bool
+	table     bool
 }
 
 var printer Printer
 
+// linesMu guards the accumulated lines, so that the commands running
+// their requests concurrently can print from any goroutine
+var linesMu sync.Mutex
+
+func addLine(v interface{}) {
+	linesMu.Lock()
+	defer linesMu.Unlock()
+	printer.Lines = append(printer.Lines, v)
+}
+
+// addPlainLine stores a line of the plain output, keeping the element
+// and its template if the line can be rendered as a row of a table
+func addPlainLine(text string, tpl *template.Template, v interface{}) {
+	if printer.Table && tableColumns(tpl) != nil {
+		addLine(tableLine{text: text, tpl: tpl, v: v})
+		return
+	}
+	addLine(text)
+}
+
+func addErrorLine(v interface{}) {
+	linesMu.Lock()
+	defer linesMu.Unlock()
+	printer.ErrorLines = append(printer.ErrorLines, v)
+}
+
 func init() {
 	printer.writer = os.Stdout
 	printer.eWriter = os.Stderr
 	printer.pager = true
-	printer.templateFuncs = make(template.FuncMap)
+	printer.templateFuncs = template.FuncMap{
+		"timestamp": FormatTimestamp,
+	}
+	printer.TimeFormat = DefaultTimestampFormat
 }
 
 // SetFormat sets the format for the final output of the printer
@@ -85,6 +133,45 @@ func SetNoNewline(no bool) {
 	printer.NoNewline = no
 }
 
+// SetTimestampFormat sets the layout used to render timestamps. An
+// empty layout restores the default one
+func SetTimestampFormat(layout string) {
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	printer.TimeFormat = layout
+}
+
+// SetColumns sets the columns of the CSV output. If no columns are
+// set, all the fields of the elements are included
+func SetColumns(columns []string) {
+	printer.Columns = columns
+}
+
+// SetUTC makes timestamps to be rendered in UTC instead of the local
+// timezone
+func SetUTC(utc bool) {
+	printer.UTC = utc
+}
+
+// FormatTimestamp renders a timestamp in milliseconds using the
+// configured layout and timezone. It is available in the templates as
+// the "timestamp" function, e.g. {{timestamp .CreateAt}}
+func FormatTimestamp(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+
+	t := time.Unix(0, millis*int64(time.Millisecond))
+	if printer.UTC {
+		t = t.UTC()
+	} else {
+		t = t.Local()
+	}
+
+	return t.Format(printer.TimeFormat)
+}
+
 func SetTemplateFunc(name string, f interface{}) {
 	printer.templateFuncs[name] = f
 }
@@ -112,9 +199,13 @@ func PrintT(templateString string, v interface{}) {
 			PrintError("Can't print the message using the provided template: " + templateString)
 			return
 		}
-		printer.Lines = append(printer.Lines, sb.String())
-	case FormatJSON:
-		printer.Lines = append(printer.Lines, v)
+		addPlainLine(sb.String(), tpl, v)
+	case FormatJSON, FormatCSV:
+		addLine(v)
+	case FormatIDOnly:
+		printID(v)
+	case FormatTemplate:
+		printTemplate(v)
 	}
 }
 
@@ -129,10 +220,60 @@ func PrintPreparedT(tpl *template.Template, v interface{}) {
 			PrintError("Can't print the message using the provided template: " + err.Error())
 			return
 		}
-		printer.Lines = append(printer.Lines, sb.String())
-	case FormatJSON:
-		printer.Lines = append(printer.Lines, v)
+		addPlainLine(sb.String(), tpl, v)
+	case FormatJSON, FormatCSV:
+		addLine(v)
+	case FormatIDOnly:
+		printID(v)
+	case FormatTemplate:
+		printTemplate(v)
+	}
+}
+
+// printID stores the ID of the element, or the IDs of its items if it
+// is a slice. Elements without an ID are not printed, so the output
+// only contains IDs
+func printID(v interface{}) {
+	ids := entityIDs(reflect.ValueOf(v))
+	if len(ids) == 0 {
+		return
+	}
+	addLine(strings.Join(ids, "\n"))
+}
+
+func entityIDs(rv reflect.Value) []string {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		ids := []string{}
+		for i := 0; i < rv.Len(); i++ {
+			ids = append(ids, entityIDs(rv.Index(i))...)
+		}
+		return ids
+	case reflect.Struct:
+		for _, name := range []string{"Id", "ID"} {
+			if field := rv.FieldByName(name); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+				return []string{field.String()}
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		if id := rv.MapIndex(reflect.ValueOf("id")); id.IsValid() {
+			if s, ok := id.Interface().(string); ok && s != "" {
+				return []string{s}
+			}
+		}
 	}
+
+	return nil
 }
 
 // Print an element. If the format requires a template, the element
@@ -162,13 +303,15 @@ func Flush() error {
 		}
 	}
 
+	isTTY := checkInteractiveTerminal() == nil
+	opts.table = printer.Table
+
 	b, err := printer.linesToBytes(opts)
 	if err != nil {
 		return err
 	}
 	lines := lineCount(b)
 
-	isTTY := checkInteractiveTerminal() == nil
 	var enablePager bool
 	termHeight, err := termHeight(os.Stdout)
 	if err == nil {
@@ -247,7 +390,7 @@ func GetErrorLines() []interface{} {
 
 // PrintError prints to the stderr.
 func PrintError(msg string) {
-	printer.ErrorLines = append(printer.ErrorLines, msg)
+	addErrorLine(msg)
 }
 
 // PrintWarning prints warning message to the error output, unlike Print and PrintError
@@ -270,7 +413,11 @@ func (p Printer) linesToBytes(opts printOpts) (b []byte, err error) {
 	}
 
 	switch opts.format {
-	case FormatPlain:
+	case FormatPlain, FormatIDOnly, FormatTemplate:
+		if opts.format == FormatPlain && opts.table && !opts.noNewline {
+			b = linesToTable(p.Lines, newline)
+			return
+		}
 		var buf bytes.Buffer
 		for i := range p.Lines {
 			fmt.Fprintf(&buf, "%s%s", p.Lines[i], newline)
@@ -286,6 +433,8 @@ func (p Printer) linesToBytes(opts printOpts) (b []byte, err error) {
 			b, err = json.MarshalIndent(p.Lines, "", "  ")
 		}
 		b = append(b, '\n')
+	case FormatCSV:
+		b, err = linesToCSV(p.Lines, p.Columns)
 	}
 	return
 }
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"testing"
 	"text/template"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -159,3 +161,258 @@ func TestFlushPlain(t *testing.T) {
 		assert.Empty(t, GetLines(), 0)
 	})
 }
+
+func TestFlushIDOnly(t *testing.T) {
+	printer.Format = FormatIDOnly
+	printer.NoNewline = false
+	defer func() { printer.Format = FormatPlain }()
+
+	type entity struct {
+		Id   string
+		Name string
+	}
+
+	t.Run("should print only the IDs of the elements", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+
+		PrintT("{{.Name}}", &entity{Id: "id-1", Name: "first"})
+		PrintT("{{.ID}}", struct{ ID string }{ID: "id-2"})
+		PrintT("{{.id}}", map[string]interface{}{"id": "id-3"})
+		PrintT("{{.}}", []*entity{{Id: "id-4"}, {Id: "id-5"}})
+		assert.Len(t, GetLines(), 4)
+
+		_ = Flush()
+		assert.Equal(t, "id-1\nid-2\nid-3\nid-4\nid-5\n", string(*mw))
+	})
+
+	t.Run("should skip elements without an ID", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+
+		Print("test string")
+		PrintT("{{.Name}}", &entity{Name: "no id"})
+		PrintT("{{.}}", (*entity)(nil))
+		assert.Empty(t, GetLines())
+
+		_ = Flush()
+		assert.Equal(t, "", string(*mw))
+	})
+}
+
+func TestFlushCSV(t *testing.T) {
+	printer.Format = FormatCSV
+	printer.NoNewline = false
+	defer func() {
+		printer.Format = FormatPlain
+		SetColumns(nil)
+	}()
+
+	type entity struct {
+		Id       string            `json:"id"`
+		Name     string            `json:"name"`
+		CreateAt int64             `json:"create_at"`
+		Props    map[string]string `json:"props,omitempty"`
+		Parent   *entity           `json:"parent"`
+	}
+
+	t.Run("should print all the fields with a header row", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+
+		PrintT("{{.Name}}", &entity{Id: "id-1", Name: "first, with comma", CreateAt: 1614853230000})
+		PrintT("{{.}}", []*entity{{Id: "id-2", Props: map[string]string{"a": "b"}}})
+		assert.Len(t, GetLines(), 2)
+
+		_ = Flush()
+		assert.Equal(t, `id,name,create_at,parent,props
+id-1,"first, with comma",1614853230000,,
+id-2,,0,,"{""a"":""b""}"
+`, string(*mw))
+	})
+
+	t.Run("should only print the selected columns", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+		SetColumns([]string{"name", "id", "unknown"})
+
+		PrintT("{{.Name}}", &entity{Id: "id-1", Name: "first"})
+		Print("a message")
+
+		_ = Flush()
+		assert.Equal(t, "name,id,unknown\nfirst,id-1,\n,,\n", string(*mw))
+	})
+
+	t.Run("should print the elements that aren't objects as values", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+		SetColumns(nil)
+
+		Print("a message")
+
+		_ = Flush()
+		assert.Equal(t, "value\na message\n", string(*mw))
+	})
+
+	t.Run("should print nothing without elements", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+
+		_ = Flush()
+		assert.Equal(t, "", string(*mw))
+	})
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	defer func() {
+		SetUTC(false)
+		SetTimestampFormat("")
+	}()
+
+	millis := time.Date(2021, time.March, 4, 10, 20, 30, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+	t.Run("should render an empty string for unset timestamps", func(t *testing.T) {
+		assert.Equal(t, "", FormatTimestamp(0))
+	})
+
+	t.Run("should render the timestamp in UTC with the default format", func(t *testing.T) {
+		SetUTC(true)
+		SetTimestampFormat("")
+		assert.Equal(t, "2021-03-04 10:20:30 +0000 UTC", FormatTimestamp(millis))
+	})
+
+	t.Run("should render the timestamp with a custom format", func(t *testing.T) {
+		SetUTC(true)
+		SetTimestampFormat(time.RFC3339)
+		assert.Equal(t, "2021-03-04T10:20:30Z", FormatTimestamp(millis))
+	})
+
+	t.Run("should be available in the templates", func(t *testing.T) {
+		printer.Format = FormatPlain
+		SetUTC(true)
+		SetTimestampFormat(time.RFC3339)
+
+		Clean()
+		PrintT("created at {{timestamp .CreateAt}}", struct{ CreateAt int64 }{millis})
+		assert.Len(t, GetLines(), 1)
+		assert.Equal(t, "created at 2021-03-04T10:20:30Z", printer.Lines[0])
+		Clean()
+	})
+}
+
+func TestFlushTemplate(t *testing.T) {
+	printer.NoNewline = false
+	defer func() {
+		printer.Format = FormatPlain
+	}()
+
+	type entity struct {
+		Id       string `json:"id"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+
+	t.Run("should render each element with the template", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+		assert.NoError(t, SetTemplate("{{.Username}} {{.Email}}"))
+
+		PrintT("{{.Id}}", &entity{Id: "id-1", Username: "john", Email: "john@example.com"})
+		PrintT("{{.}}", []*entity{{Username: "jane", Email: "jane@example.com"}, {Username: "joe"}})
+		Print("a message")
+
+		_ = Flush()
+		assert.Equal(t, "john john@example.com\njane jane@example.com\njoe \na message\n", string(*mw))
+	})
+
+	t.Run("should render values as JSON with the json function", func(t *testing.T) {
+		mw := &mockWriter{}
+		printer.writer = mw
+		Clean()
+		assert.NoError(t, SetTemplate("{{json .}}"))
+
+		PrintT("{{.Id}}", &entity{Id: "id-1"})
+
+		_ = Flush()
+		assert.Equal(t, `{"id":"id-1","username":"","email":""}`+"\n", string(*mw))
+	})
+
+	t.Run("should report the elements that can't be rendered", func(t *testing.T) {
+		Clean()
+		assert.NoError(t, SetTemplate("{{.Name}}"))
+
+		PrintT("{{.Id}}", &entity{Id: "id-1"})
+		assert.Empty(t, GetLines())
+		assert.Len(t, GetErrorLines(), 1)
+		Clean()
+	})
+
+	t.Run("should fail with an invalid template", func(t *testing.T) {
+		err := SetTemplate("{{.Name")
+		assert.Error(t, err)
+	})
+}
+
+func TestLinesToTable(t *testing.T) {
+	printer.Format = FormatPlain
+	printer.Table = true
+	defer func() { printer.Table = false }()
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	type user struct {
+		Username string
+		Email    string
+		DeleteAt int64
+		State    string
+	}
+
+	t.Run("should render the lines sharing a template as a table", func(t *testing.T) {
+		Clean()
+		tpl := `{{.Username}} ({{.Email}}): {{.State}}`
+		PrintT(tpl, user{Username: "john.doe", Email: "john@example.com", State: "active"})
+		PrintT(tpl, user{Username: "al", Email: "al@example.com", State: "deleted"})
+		Print("2 users found")
+
+		b := linesToTable(GetLines(), "\n")
+		assert.Equal(t, "USERNAME  EMAIL             STATE\n"+
+			"john.doe  john@example.com  active\n"+
+			"al        al@example.com    deleted\n"+
+			"2 users found\n", string(b))
+	})
+
+	t.Run("should name the columns after the last field of the actions", func(t *testing.T) {
+		Clean()
+		PrintT(`{{.Username}} {{printf "%d" .DeleteAt}}`, user{Username: "john.doe", DeleteAt: 12})
+
+		b := linesToTable(GetLines(), "\n")
+		assert.Equal(t, "USERNAME  DELETE AT\njohn.doe  12\n", string(b))
+	})
+
+	t.Run("should print the sentences and blocks as they are", func(t *testing.T) {
+		Clean()
+		PrintT(`User {{.Username}} has the email {{.Email}}`, user{Username: "john.doe", Email: "john@example.com"})
+		PrintT("Username: {{.Username}}\nEmail: {{.Email}}", user{Username: "al", Email: "al@example.com"})
+		PrintT(`{{.Username}}{{if .DeleteAt}} (deleted){{end}}`, user{Username: "al"})
+
+		b := linesToTable(GetLines(), "\n")
+		assert.Equal(t, "User john.doe has the email john@example.com\nUsername: al\nEmail: al@example.com\nal\n", string(b))
+	})
+
+	t.Run("should color the statuses", func(t *testing.T) {
+		color.NoColor = false
+		defer func() { color.NoColor = true }()
+
+		assert.Equal(t, color.GreenString("enabled"), colorizeStatus("enabled"))
+		assert.Equal(t, color.RedString("Disabled"), colorizeStatus("Disabled"))
+		assert.Equal(t, "myteam", colorizeStatus("myteam"))
+	})
+}
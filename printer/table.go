@@ -0,0 +1,226 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// tableColumnSeparator is the space between the columns of a table
+const tableColumnSeparator = "  "
+
+// tableLine is a line of the plain output that can be rendered as a
+// row of a table, as it keeps the element and the template it was
+// rendered with. It's printed as the rendered text otherwise
+type tableLine struct {
+	text string
+	tpl  *template.Template
+	v    interface{}
+}
+
+func (l tableLine) String() string {
+	return l.text
+}
+
+var (
+	positiveStatuses = map[string]bool{"enabled": true, "active": true, "true": true, "success": true, "online": true, "healthy": true}
+	negativeStatuses = map[string]bool{"disabled": true, "deleted": true, "inactive": true, "false": true, "error": true, "failed": true, "offline": true, "unhealthy": true}
+	pendingStatuses  = map[string]bool{"pending": true, "in_progress": true, "warning": true, "away": true, "dnd": true}
+)
+
+// SetTable makes the lines of the plain output that share a template
+// made only of fields to be rendered as an aligned table with headers.
+// Tables are only rendered when the output is a terminal, so piped
+// output keeps one line per element
+func SetTable(table bool) {
+	printer.Table = table && checkInteractiveTerminal() == nil
+}
+
+// SetNoColor disables the colors of the output
+func SetNoColor(noColor bool) {
+	if noColor {
+		color.NoColor = true
+	}
+}
+
+// tableColumns returns the actions of a template that would be the
+// columns of a table. Templates with control structures, with words
+// or line breaks between the actions, or with less than two actions,
+// are meant to be read as sentences or blocks and have no columns
+func tableColumns(tpl *template.Template) []*parse.ActionNode {
+	if tpl.Tree == nil || tpl.Tree.Root == nil {
+		return nil
+	}
+
+	columns := []*parse.ActionNode{}
+	for _, node := range tpl.Tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			if len(n.Pipe.Decl) > 0 {
+				return nil
+			}
+			columns = append(columns, n)
+		case *parse.TextNode:
+			for _, r := range string(n.Text) {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\n' {
+					return nil
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	if len(columns) < 2 {
+		return nil
+	}
+	return columns
+}
+
+// tableHeader names a column after the last field of its action, e.g.
+// CREATE AT for {{timestamp .CreateAt}}
+func tableHeader(action *parse.ActionNode) string {
+	name := "value"
+	for _, cmd := range action.Pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				name = a.Ident[len(a.Ident)-1]
+			case *parse.ChainNode:
+				if len(a.Field) > 0 {
+					name = a.Field[len(a.Field)-1]
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+	}
+	return strings.ToUpper(strings.ReplaceAll(sb.String(), "_", " "))
+}
+
+// colorizeStatus colors the cells that hold a well known status
+func colorizeStatus(cell string) string {
+	status := strings.ToLower(strings.TrimSpace(cell))
+	switch {
+	case positiveStatuses[status]:
+		return color.GreenString(cell)
+	case negativeStatuses[status]:
+		return color.RedString(cell)
+	case pendingStatuses[status]:
+		return color.YellowString(cell)
+	}
+	return cell
+}
+
+// renderTable renders lines sharing a template as a table, returning
+// false if any cell can't be rendered
+func renderTable(buf *bytes.Buffer, lines []tableLine, columns []*parse.ActionNode, newline string) bool {
+	base, err := lines[0].tpl.Clone()
+	if err != nil {
+		return false
+	}
+	cellTpls := make([]*template.Template, len(columns))
+	for i, column := range columns {
+		tpl, err := base.New("").Parse(column.String())
+		if err != nil {
+			return false
+		}
+		cellTpls[i] = tpl
+	}
+
+	rows := [][]string{make([]string, len(columns))}
+	for i, column := range columns {
+		rows[0][i] = tableHeader(column)
+	}
+	for _, line := range lines {
+		row := make([]string, len(columns))
+		for i, tpl := range cellTpls {
+			sb := &strings.Builder{}
+			if err := tpl.Execute(sb, line.v); err != nil {
+				return false
+			}
+			if strings.Contains(sb.String(), "\n") {
+				return false
+			}
+			row[i] = sb.String()
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	for r, row := range rows {
+		for i, cell := range row {
+			padding := ""
+			if i < len(row)-1 {
+				padding = strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + tableColumnSeparator
+			}
+			if r == 0 {
+				cell = color.New(color.Bold).Sprint(cell)
+			} else {
+				cell = colorizeStatus(cell)
+			}
+			buf.WriteString(cell + padding)
+		}
+		buf.WriteString(newline)
+	}
+	return true
+}
+
+// linesToTable renders the plain output, with each run of consecutive
+// lines sharing a template with columns as a table. The rest of the
+// lines are printed as they are
+func linesToTable(lines []interface{}, newline string) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(lines); {
+		line, ok := lines[i].(tableLine)
+		var columns []*parse.ActionNode
+		if ok {
+			columns = tableColumns(line.tpl)
+		}
+		if columns == nil {
+			fmt.Fprintf(&buf, "%s%s", lines[i], newline)
+			i++
+			continue
+		}
+
+		run := []tableLine{line}
+		key := line.tpl.Tree.Root.String()
+		for i++; i < len(lines); i++ {
+			next, ok := lines[i].(tableLine)
+			if !ok || next.tpl.Tree.Root.String() != key {
+				break
+			}
+			run = append(run, next)
+		}
+
+		if !renderTable(&buf, run, columns, newline) {
+			for _, l := range run {
+				buf.WriteString(l.text + newline)
+			}
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// IsTemplateFormat returns whether the format is a Go template to
+// render each element with, instead of one of the output formats
+func IsTemplateFormat(format string) bool {
+	return strings.Contains(format, "{{")
+}
+
+// SetTemplate sets the Go template used to render each element of the
+// output, like {{.Username}} {{.Email}}. Besides the template
+// functions of the plain output, the "json" function renders a value
+// as JSON
+func SetTemplate(text string) error {
+	funcs := template.FuncMap{"json": templateJSON}
+	for name, f := range printer.templateFuncs {
+		funcs[name] = f
+	}
+
+	tpl, err := template.New("format").Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+	printer.Format = FormatTemplate
+	printer.template = tpl
+	return nil
+}
+
+func templateJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// printTemplate stores the element rendered with the format template,
+// or each of its items if it is a slice. Strings, like the messages
+// printed with Print, are stored as they are
+func printTemplate(v interface{}) {
+	if s, ok := v.(string); ok {
+		addLine(s)
+		return
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			printTemplate(rv.Index(i).Interface())
+		}
+		return
+	}
+
+	sb := &strings.Builder{}
+	if err := printer.template.Execute(sb, v); err != nil {
+		PrintError("Can't print the element using the format template: " + err.Error())
+		return
+	}
+	addLine(sb.String())
+}